@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 	"vk_film/pkg/logger"
 
 	"github.com/ilyakaznacheev/cleanenv"
@@ -9,10 +10,60 @@ import (
 
 type (
 	Config struct {
-		Port       string     `yaml:"port"`
-		Postgres   PG         `yaml:"postgres"`
-		Redis      Redis      `yaml:"redis"`
-		LoggerInfo LoggerInfo `yaml:"logger"`
+		Port            string          `yaml:"port"`
+		Postgres        PG              `yaml:"postgres"`
+		Redis           Redis           `yaml:"redis"`
+		LoggerInfo      LoggerInfo      `yaml:"logger"`
+		LoginProtection LoginProtection `yaml:"login_protection"`
+		Auth            Auth            `yaml:"auth"`
+		Registration    Registration    `yaml:"registration"`
+		PasswordReset   PasswordReset   `yaml:"password_reset"`
+		Notifier        Notifier        `yaml:"notifier"`
+		Bootstrap       Bootstrap       `yaml:"bootstrap"`
+		SecurityEvents  SecurityEvents  `yaml:"security_events"`
+	}
+
+	SecurityEvents struct {
+		Retention       time.Duration `yaml:"retention" env-default:"2160h"`
+		CleanupInterval time.Duration `yaml:"cleanup_interval" env-default:"1h"`
+	}
+
+	Bootstrap struct {
+		AdminLogin    string `yaml:"admin_login" env:"ADMIN_LOGIN" env-default:"admin"`
+		AdminPassword string `yaml:"admin_password" env:"ADMIN_PASSWORD"`
+		AdminEmail    string `yaml:"admin_email" env:"ADMIN_EMAIL"`
+	}
+
+	Registration struct {
+		Enabled            bool   `yaml:"enabled"`
+		DefaultRole        string `yaml:"default_role" env-default:"user"`
+		LoginMinLength     int    `yaml:"login_min_length" env-default:"3"`
+		LoginMaxLength     int    `yaml:"login_max_length" env-default:"32"`
+		LoginPattern       string `yaml:"login_pattern" env-default:"^[a-zA-Z0-9_.-]+$"`
+		PasswordMinLength  int    `yaml:"password_min_length" env-default:"8"`
+		PasswordMinClasses int    `yaml:"password_min_classes" env-default:"2"`
+	}
+
+	PasswordReset struct {
+		TokenTTL        time.Duration `yaml:"token_ttl" env-default:"1h"`
+		ResetURL        string        `yaml:"reset_url"`
+		MaxUserRequests uint64        `yaml:"max_user_requests" env-default:"3"`
+		MaxIPRequests   uint64        `yaml:"max_ip_requests" env-default:"10"`
+		RequestsWindow  time.Duration `yaml:"requests_window" env-default:"1h"`
+	}
+
+	Notifier struct {
+		Type string `yaml:"type"`
+		File string `yaml:"file" env-default:"notifications.log"`
+		SMTP SMTP   `yaml:"smtp"`
+	}
+
+	SMTP struct {
+		Host     string `yaml:"host"`
+		Port     int    `yaml:"port" env-default:"587"`
+		Username string `yaml:"username"`
+		Password string `yaml:"password"`
+		From     string `yaml:"from"`
 	}
 
 	LoggerInfo struct {
@@ -30,6 +81,93 @@ type (
 	Redis struct {
 		URL string `yaml:"url"`
 	}
+
+	LoginProtection struct {
+		MaxLoginAttempts uint64        `yaml:"max_login_attempts" env-default:"5"`
+		MaxIPAttempts    uint64        `yaml:"max_ip_attempts" env-default:"20"`
+		BaseLockTime     time.Duration `yaml:"base_lock_time" env-default:"1m"`
+		MaxLockTime      time.Duration `yaml:"max_lock_time" env-default:"1h"`
+		AttemptsWindow   time.Duration `yaml:"attempts_window" env-default:"24h"`
+	}
+
+	Auth struct {
+		Mode      string    `yaml:"mode" env-default:"session"`
+		JWT       JWT       `yaml:"jwt"`
+		OIDC      OIDC      `yaml:"oidc"`
+		TwoFactor TwoFactor `yaml:"two_factor"`
+		Cookie    Cookie    `yaml:"cookie"`
+		Session   Session   `yaml:"session"`
+		Passwords Passwords `yaml:"password_hashing"`
+	}
+
+	Passwords struct {
+		Memory      uint32 `yaml:"memory" env-default:"65536"`
+		Iterations  uint32 `yaml:"iterations" env-default:"3"`
+		Parallelism uint8  `yaml:"parallelism" env-default:"4"`
+		SaltLength  uint32 `yaml:"salt_length" env-default:"16"`
+		KeyLength   uint32 `yaml:"key_length" env-default:"32"`
+	}
+
+	Session struct {
+		Backend             string        `yaml:"backend" env-default:"redis"`
+		EvictionInterval    time.Duration `yaml:"eviction_interval" env-default:"1m"`
+		IdleTimeout         time.Duration `yaml:"idle_timeout" env-default:"48h"`
+		MaxLifetime         time.Duration `yaml:"max_lifetime" env-default:"168h"`
+		RememberIdleTimeout time.Duration `yaml:"remember_idle_timeout" env-default:"720h"`
+		RememberMaxLifetime time.Duration `yaml:"remember_max_lifetime" env-default:"2160h"`
+		AdminIdleTimeout    time.Duration `yaml:"admin_idle_timeout" env-default:"1h"`
+		AdminMaxLifetime    time.Duration `yaml:"admin_max_lifetime" env-default:"12h"`
+		UserCacheTTL        time.Duration `yaml:"user_cache_ttl" env-default:"5s"`
+	}
+
+	Cookie struct {
+		Name     string `yaml:"name" env-default:"session_id"`
+		Domain   string `yaml:"domain"`
+		Secure   bool   `yaml:"secure"`
+		SameSite string `yaml:"same_site" env-default:"lax"`
+	}
+
+	TwoFactor struct {
+		Issuer           string        `yaml:"issuer" env-default:"VK Film"`
+		RequiredForAdmin bool          `yaml:"required_for_admin"`
+		ChallengeTTL     time.Duration `yaml:"challenge_ttl" env-default:"5m"`
+		MaxAttempts      uint64        `yaml:"max_attempts" env-default:"5"`
+	}
+
+	OIDC struct {
+		Enabled      bool          `yaml:"enabled"`
+		Issuer       string        `yaml:"issuer"`
+		ClientID     string        `yaml:"client_id"`
+		ClientSecret string        `yaml:"client_secret"`
+		RedirectURL  string        `yaml:"redirect_url"`
+		Scopes       []string      `yaml:"scopes"`
+		Timeout      time.Duration `yaml:"timeout" env-default:"10s"`
+		LoginClaim   string        `yaml:"login_claim" env-default:"preferred_username"`
+		RoleClaim    string        `yaml:"role_claim" env-default:"groups"`
+		Roles        []OIDCRole    `yaml:"roles"`
+		DefaultRole  string        `yaml:"default_role"`
+		PostLoginURL string        `yaml:"post_login_url"`
+	}
+
+	OIDCRole struct {
+		Value string `yaml:"value"`
+		Role  string `yaml:"role"`
+	}
+
+	JWT struct {
+		AccessTTL  time.Duration `yaml:"access_ttl" env-default:"15m"`
+		RefreshTTL time.Duration `yaml:"refresh_ttl" env-default:"720h"`
+		SigningKey string        `yaml:"signing_key"`
+		Keys       []JWTKey      `yaml:"keys"`
+	}
+
+	JWTKey struct {
+		ID         string `yaml:"kid"`
+		Algorithm  string `yaml:"algorithm"`
+		Secret     string `yaml:"secret"`
+		PrivateKey string `yaml:"private_key"`
+		PublicKey  string `yaml:"public_key"`
+	}
 )
 
 func NewConfig(path string) (*Config, error) {
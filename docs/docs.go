@@ -25,7 +25,7 @@ const docTemplate = `{
                         "sessionCookie": []
                     }
                 ],
-                "description": "Добавляет актёра включая его имя, пол и дату рождения.",
+                "description": "Добавляет актёра включая его имя, пол, дату рождения, а также необязательные дату смерти, место и страну рождения, биографию и альтернативные имена. Дата смерти должна быть позже даты рождения.",
                 "consumes": [
                     "application/json"
                 ],
@@ -88,7 +88,7 @@ const docTemplate = `{
                         "sessionCookie": []
                     }
                 ],
-                "description": "Формирует список всех актёров в системы.",
+                "description": "Формирует список актёров системы с возможностью поиска по фрагменту имени, фильтрации, сортировки и постраничного вывода. По умолчанию список отсортирован по имени по возрастанию. Фильмы актёров отбираются с учётом ограничения возрастного рейтинга пользователя.",
                 "produces": [
                     "application/json"
                 ],
@@ -96,6 +96,98 @@ const docTemplate = `{
                     "actor"
                 ],
                 "summary": "Получение списка актёров.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Фрагмент имени или альтернативного имени актёра",
+                        "name": "search_string",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "male",
+                            "female"
+                        ],
+                        "type": "string",
+                        "description": "Пол актёра",
+                        "name": "sex",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Актёры, родившиеся не раньше указанной даты",
+                        "name": "birthday_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Актёры, родившиеся не позже указанной даты",
+                        "name": "birthday_to",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Минимальный возраст актёра в полных годах",
+                        "name": "age_from",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Максимальный возраст актёра в полных годах",
+                        "name": "age_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "name",
+                            "birthday",
+                            "films_count"
+                        ],
+                        "type": "string",
+                        "default": "name",
+                        "description": "Параметр сортировки. Возможна сортировка по имени 'name', дате рождения 'birthday' и числу фильмов 'films_count'.",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "DESC",
+                            "ASC"
+                        ],
+                        "type": "string",
+                        "default": "ASC",
+                        "description": "Порядок сортировки. Возможна сортировка по возрастанию 'asc' или по убыванию 'desc'.",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Максимальное количество актёров в ответе. Если не указано, возвращаются все актёры.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Количество пропускаемых актёров",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Включать ли в ответ список фильмов каждого актёра",
+                        "name": "with_films",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "Список актёров успешно сформирован",
@@ -106,6 +198,12 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "400": {
+                        "description": "В параметрах запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
                     "401": {
                         "description": "Пользователь не авторизован",
                         "schema": {
@@ -128,7 +226,7 @@ const docTemplate = `{
                         "sessionCookie": []
                     }
                 ],
-                "description": "Обновляет данные об актёре. Все переданные поля будут обновлены. Отсутствующие поля будут оставлены без изменений.",
+                "description": "Обновляет данные об актёре. Все переданные поля будут обновлены. Отсутствующие поля будут оставлены без изменений. Дата смерти должна быть позже даты рождения.",
                 "consumes": [
                     "application/json"
                 ],
@@ -263,7 +361,7 @@ const docTemplate = `{
                         "sessionCookie": []
                     }
                 ],
-                "description": "Добавляет фильм включая его название, описание, рейтинг, дату публикации и список игравших в нём актёров.",
+                "description": "Добавляет фильм включая его название, описание, рейтинг, дату публикации и список игравших в нём актёров, а также необязательные продолжительность, страны производства, язык оригинала и возрастной рейтинг.",
                 "consumes": [
                     "application/json"
                 ],
@@ -310,6 +408,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/operate.ModelError"
                         }
                     },
+                    "409": {
+                        "description": "Актёр фильма не найден",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
                     "500": {
                         "description": "Ошибка сервера",
                         "schema": {
@@ -326,7 +430,7 @@ const docTemplate = `{
                         "sessionCookie": []
                     }
                 ],
-                "description": "Позволяет получить список фильмом отсортированный по определённому полю. А также можно делать поиска списка фильма по имени актёра или названии фильма. Если параметры \"search_by\" и \"search_string\" не указаны, поиск не производится.",
+                "description": "Позволяет получить список фильмом отсортированный по определённому полю. А также можно делать поиска списка фильма по имени актёра или названии фильма. Если параметры \"search_by\" и \"search_string\" не указаны, поиск не производится. Фильмы можно отфильтровать по продолжительности, стране производства, языку оригинала и возрастному рейтингу. Если для пользователя задано ограничение возрастного рейтинга, фильмы с более строгим или неизвестным рейтингом не возвращаются.",
                 "produces": [
                     "application/json"
                 ],
@@ -373,6 +477,56 @@ const docTemplate = `{
                         "description": "Фргамнет, по которому осуществляется поиск. Обязателен при указании параметра 'search_by'",
                         "name": "search_string",
                         "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Минимальная продолжительность фильма в минутах",
+                        "name": "runtime_from",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Максимальная продолжительность фильма в минутах",
+                        "name": "runtime_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Страна производства фильма",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код языка оригинала фильма по ISO 639-1",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "0+",
+                                "6+",
+                                "12+",
+                                "16+",
+                                "18+",
+                                "G",
+                                "PG",
+                                "PG-13",
+                                "R",
+                                "NC-17"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Возрастной рейтинг фильма. Можно указать несколько значений.",
+                        "name": "certification",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -473,6 +627,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/operate.ModelError"
                         }
                     },
+                    "409": {
+                        "description": "Актёр фильма не найден",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
                     "500": {
                         "description": "Ошибка сервера",
                         "schema": {
@@ -543,7 +703,7 @@ const docTemplate = `{
         },
         "/login": {
             "post": {
-                "description": "Авторизация пользователя в системе.",
+                "description": "Авторизация пользователя в системе. После нескольких неудачных попыток логин и адрес клиента временно блокируются, время блокировки растёт с каждой следующей неудачной попыткой. Если у пользователя подключена двухфакторная аутентификация, сессия выдаётся только после ввода кода в /login/2fa. С remember_me сессия живёт дольше, а cookie сохраняется после закрытия браузера; на сессии администраторов remember_me не действует.",
                 "consumes": [
                     "application/json"
                 ],
@@ -567,7 +727,10 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Пользователь успешно авторизован",
+                        "description": "Пользователь успешно авторизован, токены возвращаются только в режиме jwt",
+                        "schema": {
+                            "$ref": "#/definitions/response.Credentials"
+                        },
                         "headers": {
                             "Set-Cookie": {
                                 "type": "string",
@@ -575,12 +738,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "202": {
+                        "description": "Пароль верный, для входа нужен код второго фактора",
+                        "schema": {
+                            "$ref": "#/definitions/response.PendingLogin"
+                        }
+                    },
                     "400": {
                         "description": "В теле запроса ошибка",
                         "schema": {
                             "$ref": "#/definitions/operate.ModelError"
                         }
                     },
+                    "403": {
+                        "description": "Учётная запись ожидает одобрения или отклонена",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
                     "409": {
                         "description": "Неверный логин или пароль",
                         "schema": {
@@ -593,6 +768,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/operate.ModelError"
                         }
                     },
+                    "429": {
+                        "description": "Слишком много неудачных попыток входа, логин или адрес клиента временно заблокирован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Через сколько секунд можно повторить попытку"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Ошибка сервера",
                         "schema": {
@@ -602,27 +789,75 @@ const docTemplate = `{
                 }
             }
         },
-        "/logout": {
+        "/login/2fa": {
             "post": {
-                "security": [
-                    {
-                        "sessionCookie": []
-                    }
+                "description": "Завершает вход, ожидающий второй фактор, кодом из приложения-аутентификатора или одноразовым кодом восстановления. Если вход подключает второй фактор, принимается только код из приложения, а в ответе один раз возвращаются коды восстановления. После нескольких неверных кодов вход нужно начинать заново.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Позволяет выйти пользователю из системы.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "user"
                 ],
-                "summary": "Выход из системы.",
+                "summary": "Ввод кода второго фактора.",
+                "parameters": [
+                    {
+                        "description": "Токен ожидающего входа, код и необходимость запомнить вход",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.VerifyLogin"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "Пользователь успешно вышел из системы"
+                        "description": "Пользователь успешно авторизован, токены возвращаются только в режиме jwt",
+                        "schema": {
+                            "$ref": "#/definitions/response.Credentials"
+                        },
+                        "headers": {
+                            "Set-Cookie": {
+                                "type": "string",
+                                "description": "Устанавливает сессию текущего пользователя"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "В теле запроса ошибка или второй фактор ещё не подключён",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
                     },
                     "401": {
-                        "description": "Пользователь не авторизован",
+                        "description": "Ожидающий вход не найден или истёк",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "409": {
+                        "description": "Неверный код",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много неверных кодов",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Через сколько секунд можно повторить попытку"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
                         "schema": {
                             "$ref": "#/definitions/operate.ModelError"
                         }
@@ -630,14 +865,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user": {
+        "/login/2fa/enroll": {
             "post": {
-                "security": [
-                    {
-                        "sessionCookie": []
-                    }
-                ],
-                "description": "Добавляет пользователя включая его логин, пароль и роль. По умолчанию роль 'user'.",
+                "description": "Создаёт секрет для входа, который требует подключить второй фактор. Вход завершается первым кодом из приложения в /login/2fa.",
                 "consumes": [
                     "application/json"
                 ],
@@ -647,23 +877,23 @@ const docTemplate = `{
                 "tags": [
                     "user"
                 ],
-                "summary": "Добавление пользователя.",
+                "summary": "Подключение второго фактора при входе.",
                 "parameters": [
                     {
-                        "description": "Информация о добавляемом пользователе",
+                        "description": "Токен ожидающего входа",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.CreateUser"
+                            "$ref": "#/definitions/request.EnrollLogin"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Пользователь успешно добавлен в базу",
+                    "200": {
+                        "description": "Секрет и ссылка для приложения-аутентификатора",
                         "schema": {
-                            "$ref": "#/definitions/response.User"
+                            "$ref": "#/definitions/response.Enrollment"
                         }
                     },
                     "400": {
@@ -673,19 +903,13 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Пользователь не авторизован",
-                        "schema": {
-                            "$ref": "#/definitions/operate.ModelError"
-                        }
-                    },
-                    "403": {
-                        "description": "У пользователя нет прав на создание пользователя",
+                        "description": "Ожидающий вход не найден или истёк",
                         "schema": {
                             "$ref": "#/definitions/operate.ModelError"
                         }
                     },
                     "409": {
-                        "description": "Пользователь с таким же логином уже существует",
+                        "description": "Второй фактор уже подключён",
                         "schema": {
                             "$ref": "#/definitions/operate.ModelError"
                         }
@@ -699,39 +923,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/list": {
-            "get": {
+        "/logout": {
+            "post": {
                 "security": [
                     {
                         "sessionCookie": []
                     }
                 ],
-                "description": "Возвращает список пользователей системы.",
+                "description": "Позволяет выйти пользователю из системы.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "user"
                 ],
-                "summary": "Получение списка пользователей.",
+                "summary": "Выход из системы.",
                 "responses": {
                     "200": {
-                        "description": "Список пользователей успешно сформирован",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/response.User"
-                            }
-                        }
+                        "description": "Пользователь успешно вышел из системы"
                     },
-                    "401": {
-                        "description": "Пользователь не авторизован",
+                    "400": {
+                        "description": "Запрос авторизован не сессией",
                         "schema": {
                             "$ref": "#/definitions/operate.ModelError"
                         }
                     },
-                    "500": {
-                        "description": "Ошибка сервера",
+                    "401": {
+                        "description": "Пользователь не авторизован",
                         "schema": {
                             "$ref": "#/definitions/operate.ModelError"
                         }
@@ -739,54 +957,74 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/{user_id}": {
-            "delete": {
-                "security": [
-                    {
-                        "sessionCookie": []
-                    }
-                ],
-                "description": "Удаляет пользователя по его id.",
+        "/oidc/callback": {
+            "get": {
+                "description": "Принимает код авторизации от провайдера, проверяет id токен и выдаёт сессию. Если у пользователя подключена двухфакторная аутентификация, сессия выдаётся только после ввода кода в /login/2fa. При первом входе пользователь создаётся, его роль определяется заявкой провайдера и обновляется при каждом входе.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "user"
                 ],
-                "summary": "Удаление пользователя.",
+                "summary": "Завершение входа через внешний провайдер.",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Уникальный идентификатор пользователя",
-                        "name": "user_id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Код авторизации",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Состояние авторизации",
+                        "name": "state",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Пользователь успешно удалён"
+                        "description": "Пользователь успешно авторизован, токены возвращаются только в режиме jwt",
+                        "schema": {
+                            "$ref": "#/definitions/response.Credentials"
+                        },
+                        "headers": {
+                            "Set-Cookie": {
+                                "type": "string",
+                                "description": "Устанавливает сессию текущего пользователя"
+                            }
+                        }
+                    },
+                    "202": {
+                        "description": "Личность подтверждена провайдером, для входа нужен код второго фактора",
+                        "schema": {
+                            "$ref": "#/definitions/response.PendingLogin"
+                        }
+                    },
+                    "303": {
+                        "description": "Пользователь успешно авторизован и перенаправлен на адрес из настроек"
                     },
                     "400": {
-                        "description": "В теле запроса ошибка",
+                        "description": "Состояние авторизации не совпадает или отсутствует код",
                         "schema": {
                             "$ref": "#/definitions/operate.ModelError"
                         }
                     },
                     "401": {
-                        "description": "Пользователь не авторизован",
+                        "description": "Провайдер отклонил авторизацию или id токен недействителен",
                         "schema": {
                             "$ref": "#/definitions/operate.ModelError"
                         }
                     },
                     "403": {
-                        "description": "У пользователя нет прав на удаление пользователя",
+                        "description": "Пользователю провайдера не соответствует ни одна роль",
                         "schema": {
                             "$ref": "#/definitions/operate.ModelError"
                         }
                     },
-                    "404": {
-                        "description": "Пользователь с указанным id не найден",
+                    "409": {
+                        "description": "Логин пользователя провайдера уже занят",
                         "schema": {
                             "$ref": "#/definitions/operate.ModelError"
                         }
@@ -800,51 +1038,323 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/{user_id}/role": {
-            "put": {
-                "security": [
-                    {
-                        "sessionCookie": []
-                    }
-                ],
-                "description": "Обновляет пользовательскую роль.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+        "/oidc/login": {
+            "get": {
+                "description": "Начинает вход через OpenID Connect провайдер с PKCE и перенаправляет пользователя на страницу авторизации провайдера. Параметры авторизации сохраняются в cookie до возврата пользователя.",
                 "tags": [
                     "user"
                 ],
-                "summary": "Обновление роли пользователя.",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Уникальный идентификатор пользователя",
-                        "name": "user_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Информация о добавляемом пользователе",
+                "summary": "Вход через внешний провайдер.",
+                "responses": {
+                    "302": {
+                        "description": "Перенаправление на страницу авторизации провайдера",
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Адрес страницы авторизации провайдера"
+                            },
+                            "Set-Cookie": {
+                                "type": "string",
+                                "description": "Сохраняет параметры авторизации"
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "Провайдер недоступен",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/password/reset": {
+            "post": {
+                "description": "Отправляет одноразовый код сброса пароля на почту пользователя с указанной почтой или, если почта не указана, с указанным логином. Ответ не зависит от того, существует ли пользователь, поэтому письмо может и не прийти. Письмо отправляется после ответа. Число запросов для одного пользователя и с одного адреса ограничено, письма сверх ограничения для пользователя не отправляются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Запрос сброса пароля.",
+                "parameters": [
+                    {
+                        "description": "Логин или почта пользователя",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.UpdateRole"
+                            "$ref": "#/definitions/request.RequestPasswordReset"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Запрос принят"
+                    },
+                    "400": {
+                        "description": "В теле запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много запросов сброса с адреса клиента",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/password/reset/confirm": {
+            "post": {
+                "description": "Устанавливает новый пароль по одноразовому коду из письма. Новый пароль должен соответствовать политике паролей. Код можно использовать только один раз, все сессии пользователя завершаются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Подтверждение сброса пароля.",
+                "parameters": [
+                    {
+                        "description": "Код из письма и новый пароль",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.ConfirmPasswordReset"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Роль пользователя успешно обновлена",
+                        "description": "Пароль успешно изменён"
+                    },
+                    "400": {
+                        "description": "В теле запроса ошибка, код недействителен или пароль не соответствует политике",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/refresh": {
+            "post": {
+                "description": "Выдаёт новую пару access и refresh токенов в режиме авторизации jwt. Переданный refresh токен становится недействительным, его повторное использование завершает сессию.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Обновление access токена.",
+                "parameters": [
+                    {
+                        "description": "Refresh токен",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.Refresh"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Токены успешно обновлены",
+                        "schema": {
+                            "$ref": "#/definitions/response.Credentials"
+                        },
+                        "headers": {
+                            "Set-Cookie": {
+                                "type": "string",
+                                "description": "Устанавливает access токен текущего пользователя"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "В теле запроса ошибка или сервер работает не в режиме jwt",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Refresh токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Создаёт учётную запись с ролью по умолчанию, которая ожидает одобрения администратором. До одобрения вход невозможен. Логин и пароль должны соответствовать политике регистрации.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Регистрация пользователя.",
+                "parameters": [
+                    {
+                        "description": "Логин, пароль и необязательная почта нового пользователя",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.Register"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Учётная запись создана и ожидает одобрения",
                         "schema": {
                             "$ref": "#/definitions/response.User"
                         }
                     },
                     "400": {
-                        "description": "В теле запроса ошибка",
+                        "description": "В теле запроса ошибка или логин и пароль не соответствуют политике",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "403": {
+                        "description": "Регистрация отключена",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "409": {
+                        "description": "Пользователь с таким же логином или почтой уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "418": {
+                        "description": "Пользователь уже авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/registrations": {
+            "get": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Возвращает зарегистрированных пользователей, ожидающих одобрения, в порядке регистрации.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Очередь регистраций.",
+                "responses": {
+                    "200": {
+                        "description": "Очередь успешно получена",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.User"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "403": {
+                        "description": "У пользователя нет прав на управление пользователями",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/registrations/{user_id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Активирует ожидающего одобрения пользователя, после чего он может войти в систему.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Одобрение регистрации.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Уникальный идентификатор пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Регистрация одобрена",
+                        "schema": {
+                            "$ref": "#/definitions/response.User"
+                        }
+                    },
+                    "400": {
+                        "description": "В параметрах запроса ошибка",
                         "schema": {
                             "$ref": "#/definitions/operate.ModelError"
                         }
@@ -856,7 +1366,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "У пользователя нет прав на создание пользователя",
+                        "description": "У пользователя нет прав на управление пользователями",
                         "schema": {
                             "$ref": "#/definitions/operate.ModelError"
                         }
@@ -867,6 +1377,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/operate.ModelError"
                         }
                     },
+                    "409": {
+                        "description": "Пользователь не ожидает одобрения",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
                     "500": {
                         "description": "Ошибка сервера",
                         "schema": {
@@ -875,48 +1391,3033 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "operate.ModelError": {
+        },
+        "/registrations/{user_id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Отклоняет регистрацию пользователя. Логин остаётся занятым, пока пользователь не будет удалён.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Отклонение регистрации.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Уникальный идентификатор пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Регистрация отклонена",
+                        "schema": {
+                            "$ref": "#/definitions/response.User"
+                        }
+                    },
+                    "400": {
+                        "description": "В параметрах запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "403": {
+                        "description": "У пользователя нет прав на управление пользователями",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь с указанным id не найден",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "409": {
+                        "description": "Пользователь не ожидает одобрения",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles": {
+            "get": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Возвращает все роли вместе с их описанием и правами.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "role"
+                ],
+                "summary": "Получение списка ролей.",
+                "responses": {
+                    "200": {
+                        "description": "Роли успешно получены",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.Role"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "403": {
+                        "description": "У пользователя нет прав на управление ролями",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Добавляет роль как именованный набор прав. Название роли состоит из строчных латинских букв, цифр, '-' и '_' и начинается с буквы. Роль может содержать только права, которые есть у текущего пользователя.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "role"
+                ],
+                "summary": "Добавление роли.",
+                "parameters": [
+                    {
+                        "description": "Название, описание и права роли",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateRole"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Роль успешно добавлена",
+                        "schema": {
+                            "$ref": "#/definitions/response.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "В теле запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "403": {
+                        "description": "У пользователя нет прав на управление ролями или на права роли",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "409": {
+                        "description": "Роль с таким же названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/{role}": {
+            "put": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Заменяет описание и права роли. Изменения применяются ко всем пользователям с этой ролью. Встроенную роль 'admin' изменить нельзя, как и роль с правами, которых нет у текущего пользователя.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "role"
+                ],
+                "summary": "Обновление роли.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название роли",
+                        "name": "role",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Описание и права роли",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateRolePermissions"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Роль успешно обновлена",
+                        "schema": {
+                            "$ref": "#/definitions/response.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "В теле запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "403": {
+                        "description": "У пользователя нет прав на управление ролями или на права роли",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "404": {
+                        "description": "Роль с указанным названием не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "409": {
+                        "description": "Встроенную роль нельзя изменить",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Удаляет роль, которая не назначена ни одному пользователю. Встроенные роли 'admin' и 'user' удалить нельзя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "role"
+                ],
+                "summary": "Удаление роли.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название роли",
+                        "name": "role",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Роль успешно удалена"
+                    },
+                    "400": {
+                        "description": "В параметрах запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "403": {
+                        "description": "У пользователя нет прав на управление ролями",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "404": {
+                        "description": "Роль с указанным названием не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "409": {
+                        "description": "Роль встроенная или назначена пользователям",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/routes": {
+            "get": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Возвращает все маршруты API с требованиями к доступу: нужна ли авторизация, какие права должны быть у пользователя и какие роли допускаются.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "role"
+                ],
+                "summary": "Получение таблицы маршрутов.",
+                "responses": {
+                    "200": {
+                        "description": "Таблица маршрутов успешно получена",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.Route"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "403": {
+                        "description": "У пользователя нет прав на управление ролями",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/actors/ratings": {
+            "get": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Возвращает средний рейтинг фильмов для каждого актёра, отсортированный по убыванию.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Средний рейтинг фильмов актёров.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Учитывать фильмы, выпущенные не раньше указанной даты",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Учитывать фильмы, выпущенные не позже указанной даты",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Размер выборки",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Формат ответа",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Статистика успешно сформирована",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.ActorRating"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "В параметрах запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/actors/top": {
+            "get": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Возвращает актёров с наибольшим количеством фильмов.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Самые снимаемые актёры.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Учитывать фильмы, выпущенные не раньше указанной даты",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Учитывать фильмы, выпущенные не позже указанной даты",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Размер выборки",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Формат ответа",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Статистика успешно сформирована",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.ActorFilmsCount"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "В параметрах запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/actors/without-films": {
+            "get": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Возвращает список актёров, не снявшихся ни в одном фильме из указанного диапазона дат.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Актёры без фильмов.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Учитывать фильмы, выпущенные не раньше указанной даты",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Учитывать фильмы, выпущенные не позже указанной даты",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Формат ответа",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Статистика успешно сформирована",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.StatsActor"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "В параметрах запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/films/ratings": {
+            "get": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Возвращает гистограмму рейтингов: количество фильмов для каждого значения рейтинга от 0 до 10.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Распределение рейтингов фильмов.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Учитывать фильмы, выпущенные не раньше указанной даты",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Учитывать фильмы, выпущенные не позже указанной даты",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Формат ответа",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Статистика успешно сформирована",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.RatingCount"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "В параметрах запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/films/without-actors": {
+            "get": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Возвращает список фильмов, для которых не указан ни один актёр. Учитывается ограничение возрастного рейтинга пользователя.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Фильмы без актёров.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Учитывать фильмы, выпущенные не раньше указанной даты",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Учитывать фильмы, выпущенные не позже указанной даты",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Формат ответа",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Статистика успешно сформирована",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.StatsFilm"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "В параметрах запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/films/years": {
+            "get": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Возвращает количество фильмов, выпущенных в каждом году.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Количество фильмов по годам.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Учитывать фильмы, выпущенные не раньше указанной даты",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Учитывать фильмы, выпущенные не позже указанной даты",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Формат ответа",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Статистика успешно сформирована",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.FilmsPerYear"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "В параметрах запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "post": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Добавляет пользователя включая его логин, пароль и роль. По умолчанию роль 'user'. Нельзя выдать роль с правами, которых нет у текущего пользователя. Пароль должен соответствовать политике паролей.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Добавление пользователя.",
+                "parameters": [
+                    {
+                        "description": "Информация о добавляемом пользователе",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Пользователь успешно добавлен в базу",
+                        "schema": {
+                            "$ref": "#/definitions/response.User"
+                        }
+                    },
+                    "400": {
+                        "description": "В теле запроса ошибка или пароль не соответствует политике",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "403": {
+                        "description": "У пользователя нет прав на создание пользователя или выдачу роли",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "409": {
+                        "description": "Пользователь с таким же логином или почтой уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/list": {
+            "get": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Возвращает список пользователей системы вместе со статусом учётных записей и возрастными ограничениями. Доступно только пользователям с правом 'user:manage'.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Получение списка пользователей.",
+                "responses": {
+                    "200": {
+                        "description": "Список пользователей успешно сформирован",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.User"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "403": {
+                        "description": "У пользователя нет прав на просмотр пользователей",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me": {
+            "get": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Возвращает информацию об авторизованном пользователе.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Получение текущего пользователя.",
+                "responses": {
+                    "200": {
+                        "description": "Информация о пользователе успешно получена",
+                        "schema": {
+                            "$ref": "#/definitions/response.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/2fa": {
+            "post": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Создаёт секрет двухфакторной аутентификации текущего пользователя. Ссылку из ответа можно показать в виде QR-кода. Второй фактор начинает действовать после подтверждения кодом из приложения, повторный запрос до подтверждения заменяет секрет.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Подключение второго фактора.",
+                "responses": {
+                    "200": {
+                        "description": "Секрет и ссылка для приложения-аутентификатора",
+                        "schema": {
+                            "$ref": "#/definitions/response.Enrollment"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "409": {
+                        "description": "Второй фактор уже подключён",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Отключает двухфакторную аутентификацию текущего пользователя. Требуется код из приложения или код восстановления.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Отключение второго фактора.",
+                "parameters": [
+                    {
+                        "description": "Код из приложения или код восстановления",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.TwoFactorCode"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Второй фактор отключён"
+                    },
+                    "400": {
+                        "description": "В теле запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "403": {
+                        "description": "Второй фактор обязателен для роли пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "404": {
+                        "description": "Второй фактор не подключён",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "409": {
+                        "description": "Неверный код",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много неверных кодов",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Через сколько секунд можно повторить попытку"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/2fa/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Включает двухфакторную аутентификацию текущего пользователя кодом из приложения. Одноразовые коды восстановления возвращаются только в этом ответе.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Подтверждение второго фактора.",
+                "parameters": [
+                    {
+                        "description": "Код из приложения",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.TwoFactorCode"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Второй фактор подключён",
+                        "schema": {
+                            "$ref": "#/definitions/response.RecoveryCodes"
+                        }
+                    },
+                    "400": {
+                        "description": "В теле запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "404": {
+                        "description": "Подключение второго фактора не начато",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "409": {
+                        "description": "Неверный код или второй фактор уже подключён",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много неверных кодов",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Через сколько секунд можно повторить попытку"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/email": {
+            "put": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Меняет адрес почты авторизованного пользователя, на который отправляются письма для сброса пароля. Требуется указать текущий пароль. Пустой адрес удаляет почту.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Смена почты текущего пользователя.",
+                "parameters": [
+                    {
+                        "description": "Текущий пароль и новый адрес почты",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateEmail"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Почта успешно изменена"
+                    },
+                    "400": {
+                        "description": "В теле запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "409": {
+                        "description": "Неверный текущий пароль или почта уже используется другим пользователем",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/password": {
+            "put": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Меняет пароль авторизованного пользователя. Требуется указать текущий пароль, новый пароль должен соответствовать политике паролей. Все остальные сессии пользователя завершаются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Смена пароля текущего пользователя.",
+                "parameters": [
+                    {
+                        "description": "Текущий и новый пароли",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.ChangePassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пароль успешно изменён"
+                    },
+                    "400": {
+                        "description": "В теле запроса ошибка или пароль не соответствует политике",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "409": {
+                        "description": "Неверный текущий пароль",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/security-events": {
+            "get": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    },
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Возвращает события безопасности текущего пользователя от новых к старым: успешные и неудачные входы, выходы, смены и сбросы пароля, блокировки входа. Для каждого события указаны адрес клиента и его User-Agent. События старше срока хранения удаляются.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Получение журнала событий безопасности.",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "login_succeeded",
+                                "login_failed",
+                                "logout",
+                                "password_changed",
+                                "password_reset",
+                                "lockout"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Тип события. Можно указать несколько значений.",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Начало периода в формате RFC 3339 включительно",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Конец периода в формате RFC 3339 не включительно",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 500,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Количество событий",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Количество пропускаемых событий",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "События успешно получены",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.SecurityEvent"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "В параметрах запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    },
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Возвращает активные сессии текущего пользователя со временем создания и последней активности, адресом клиента и его User-Agent. Сессия, которой авторизован запрос, отмечена полем current.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Получение списка активных сессий.",
+                "responses": {
+                    "200": {
+                        "description": "Сессии успешно получены",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.Session"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/sessions/{session_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    },
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Завершает сессию текущего пользователя по её идентификатору из списка сессий.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Завершение сессии.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор сессии",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сессия успешно завершена"
+                    },
+                    "400": {
+                        "description": "В параметрах запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "404": {
+                        "description": "Сессия с указанным id не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    },
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Возвращает персональные токены текущего пользователя вместе со временем их последнего использования. Секреты токенов не возвращаются.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Получение списка персональных токенов.",
+                "responses": {
+                    "200": {
+                        "description": "Токены успешно получены",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.Token"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    },
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Создаёт именованный персональный токен текущего пользователя для доступа к API через заголовок ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + `. Секрет токена возвращается только один раз. Токен только для чтения разрешает лишь GET-запросы.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Создание персонального токена.",
+                "parameters": [
+                    {
+                        "description": "Название, область и срок действия токена",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateToken"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Токен успешно создан",
+                        "schema": {
+                            "$ref": "#/definitions/response.CreatedToken"
+                        }
+                    },
+                    "400": {
+                        "description": "В теле запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "409": {
+                        "description": "Токен с таким же названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/tokens/{token_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    },
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Удаляет персональный токен текущего пользователя, после чего токен перестаёт приниматься.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Отзыв персонального токена.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Уникальный идентификатор токена",
+                        "name": "token_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Токен успешно отозван"
+                    },
+                    "400": {
+                        "description": "В параметрах запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "404": {
+                        "description": "Токен с указанным id не найден",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/security-events": {
+            "get": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Возвращает события безопасности пользователей от новых к старым с фильтрами по пользователю, типу события, адресу клиента и периоду.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Получение журнала событий безопасности всех пользователей.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Уникальный идентификатор пользователя",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "login_succeeded",
+                                "login_failed",
+                                "logout",
+                                "password_changed",
+                                "password_reset",
+                                "lockout"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Тип события. Можно указать несколько значений.",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Адрес клиента",
+                        "name": "ip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Начало периода в формате RFC 3339 включительно",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Конец периода в формате RFC 3339 не включительно",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 500,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Количество событий",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Количество пропускаемых событий",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "События успешно получены",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.SecurityEvent"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "В параметрах запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "403": {
+                        "description": "У пользователя нет прав на просмотр событий",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{user_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Удаляет пользователя по его id. Удаление собственной учётной записи нужно подтвердить параметром confirm=true. Последнего активного администратора удалить нельзя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Удаление пользователя.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Уникальный идентификатор пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Подтверждение удаления собственной учётной записи",
+                        "name": "confirm",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пользователь успешно удалён"
+                    },
+                    "400": {
+                        "description": "В теле запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "403": {
+                        "description": "У пользователя нет прав на удаление пользователя или у удаляемого пользователя больше прав",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь с указанным id не найден",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "409": {
+                        "description": "Удаление не подтверждено или пользователь последний активный администратор",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{user_id}/certification": {
+            "put": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Задаёт максимальный возрастной рейтинг фильмов, доступных пользователю. Фильмы с более строгим или неизвестным рейтингом скрываются из списков фильмов, фильмографий актёров и статистики фильмов без актёров. Значение null или отсутствие поля снимает ограничение. Изменять ограничение может только администратор.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Обновление ограничения возрастного рейтинга пользователя.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Уникальный идентификатор пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Максимальный возрастной рейтинг",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateMaxCertification"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ограничение пользователя успешно обновлено",
+                        "schema": {
+                            "$ref": "#/definitions/response.User"
+                        }
+                    },
+                    "400": {
+                        "description": "В теле запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "403": {
+                        "description": "У пользователя нет прав на изменение ограничения или у изменяемого пользователя больше прав",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь с указанным id не найден",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{user_id}/lock": {
+            "delete": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Сбрасывает счётчик неудачных попыток входа и снимает временную блокировку логина пользователя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Снятие блокировки входа пользователя.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Уникальный идентификатор пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Блокировка успешно снята"
+                    },
+                    "400": {
+                        "description": "В параметрах запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "403": {
+                        "description": "У пользователя нет прав на снятие блокировки или у пользователя больше прав",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь с указанным id не найден",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{user_id}/password": {
+            "put": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Устанавливает пользователю новый пароль, соответствующий политике паролей. Все сессии пользователя завершаются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Сброс пароля пользователя.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Уникальный идентификатор пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый пароль",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.ResetPassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пароль успешно сброшен"
+                    },
+                    "400": {
+                        "description": "В теле запроса ошибка или пароль не соответствует политике",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "403": {
+                        "description": "У пользователя нет прав на сброс пароля или у изменяемого пользователя больше прав",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь с указанным id не найден",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{user_id}/role": {
+            "put": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Обновляет пользовательскую роль. Нельзя выдать роль с правами, которых нет у текущего пользователя. Смену собственной роли нужно подтвердить параметром confirm=true. Последний активный администратор не может лишиться роли 'admin'.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Обновление роли пользователя.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Уникальный идентификатор пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Подтверждение смены собственной роли",
+                        "name": "confirm",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Информация о добавляемом пользователе",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateRole"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Роль пользователя успешно обновлена",
+                        "schema": {
+                            "$ref": "#/definitions/response.User"
+                        }
+                    },
+                    "400": {
+                        "description": "В теле запроса ошибка или роль не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "403": {
+                        "description": "У пользователя нет прав на изменение роли, выдачу этой роли или у изменяемого пользователя больше прав",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь с указанным id не найден",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "409": {
+                        "description": "Смена своей роли не подтверждена или пользователь последний активный администратор",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{user_id}/sessions": {
+            "delete": {
+                "security": [
+                    {
+                        "sessionCookie": []
+                    }
+                ],
+                "description": "Завершает все сессии пользователя по его id. Персональные токены пользователя не отзываются.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Завершение всех сессий пользователя.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Уникальный идентификатор пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сессии успешно завершены"
+                    },
+                    "400": {
+                        "description": "В параметрах запроса ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "403": {
+                        "description": "У пользователя нет прав на завершение сессий или у пользователя больше прав",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь с указанным id не найден",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/operate.ModelError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "operate.ModelError": {
+            "type": "object",
+            "properties": {
+                "error_message": {
+                    "type": "string"
+                }
+            }
+        },
+        "request.ChangePassword": {
+            "type": "object",
+            "properties": {
+                "current_password": {
+                    "type": "string",
+                    "example": "password"
+                },
+                "new_password": {
+                    "type": "string",
+                    "example": "new password"
+                }
+            }
+        },
+        "request.ConfirmPasswordReset": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "new password"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "vkp_3f7a0c9d..."
+                }
+            }
+        },
+        "request.CreateActor": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Timothée Chalamet",
+                        "Тимоти Шаламэ"
+                    ]
+                },
+                "biography": {
+                    "type": "string",
+                    "example": "Американский актёр"
+                },
+                "birth_place": {
+                    "type": "string",
+                    "example": "Нью-Йорк"
+                },
+                "birthday": {
+                    "type": "string",
+                    "format": "date",
+                    "example": "12.02.2002"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "США"
+                },
+                "death_date": {
+                    "type": "string",
+                    "format": "date",
+                    "example": "12.02.2092"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Тимоти Шаламе"
+                },
+                "sex": {
+                    "type": "string",
+                    "enum": [
+                        "male",
+                        "female"
+                    ],
+                    "example": "male"
+                }
+            }
+        },
+        "request.CreateFilm": {
+            "type": "object",
+            "properties": {
+                "actors": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "age_certification": {
+                    "type": "string",
+                    "enum": [
+                        "0+",
+                        "6+",
+                        "12+",
+                        "16+",
+                        "18+",
+                        "G",
+                        "PG",
+                        "PG-13",
+                        "R",
+                        "NC-17"
+                    ],
+                    "example": "PG-13"
+                },
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "США",
+                        "Канада"
+                    ]
+                },
+                "data_publish": {
+                    "type": "string",
+                    "format": "date",
+                    "example": "12.02.2023"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Futuristic film"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Dune"
+                },
+                "original_language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "rating": {
+                    "type": "integer",
+                    "format": "uint8",
+                    "example": 9
+                },
+                "runtime": {
+                    "type": "integer",
+                    "format": "uint16",
+                    "example": 155
+                }
+            }
+        },
+        "request.CreateRole": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Редактор каталога"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "editor"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "film:write",
+                            "film:delete",
+                            "actor:write",
+                            "actor:delete",
+                            "user:manage",
+                            "user:moderate",
+                            "role:manage"
+                        ]
+                    },
+                    "example": [
+                        "film:write",
+                        "actor:write"
+                    ]
+                }
+            }
+        },
+        "request.CreateToken": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2030-01-02T15:04:05Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "ci"
+                },
+                "read_only": {
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                }
+            }
+        },
+        "request.CreateUser": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "login": {
+                    "type": "string",
+                    "example": "login"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password"
+                },
+                "role": {
+                    "type": "string",
+                    "default": "user",
+                    "example": "editor"
+                }
+            }
+        },
+        "request.EnrollLogin": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "vkc_5b1e9a7f..."
+                }
+            }
+        },
+        "request.Login": {
+            "type": "object",
+            "properties": {
+                "login": {
+                    "type": "string",
+                    "example": "login"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password"
+                },
+                "remember_me": {
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                }
+            }
+        },
+        "request.Refresh": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string",
+                    "example": "vkr_3f7a0c9d..."
+                }
+            }
+        },
+        "request.Register": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "login": {
+                    "type": "string",
+                    "example": "login"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "Str0ng-password"
+                }
+            }
+        },
+        "request.RequestPasswordReset": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "login": {
+                    "type": "string",
+                    "example": "login"
+                }
+            }
+        },
+        "request.ResetPassword": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "password"
+                }
+            }
+        },
+        "request.TwoFactorCode": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "request.UpdateActor": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Timothée Chalamet",
+                        "Тимоти Шаламэ"
+                    ]
+                },
+                "biography": {
+                    "type": "string",
+                    "example": "Американский актёр"
+                },
+                "birth_place": {
+                    "type": "string",
+                    "example": "Нью-Йорк"
+                },
+                "birthday": {
+                    "type": "string",
+                    "format": "date",
+                    "example": "12.02.2002"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "США"
+                },
+                "death_date": {
+                    "type": "string",
+                    "format": "date",
+                    "example": "12.02.2092"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Тимоти Шаламе"
+                },
+                "sex": {
+                    "type": "string",
+                    "enum": [
+                        "male",
+                        "female"
+                    ],
+                    "example": "male"
+                }
+            }
+        },
+        "request.UpdateEmail": {
+            "type": "object",
+            "properties": {
+                "current_password": {
+                    "type": "string",
+                    "example": "password"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                }
+            }
+        },
+        "request.UpdateFilm": {
+            "type": "object",
+            "properties": {
+                "actors": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "age_certification": {
+                    "type": "string",
+                    "enum": [
+                        "0+",
+                        "6+",
+                        "12+",
+                        "16+",
+                        "18+",
+                        "G",
+                        "PG",
+                        "PG-13",
+                        "R",
+                        "NC-17"
+                    ],
+                    "example": "PG-13"
+                },
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "США",
+                        "Канада"
+                    ]
+                },
+                "data_publish": {
+                    "type": "string",
+                    "format": "date",
+                    "example": "12.02.2023"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Futuristic film"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Dune"
+                },
+                "original_language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "rating": {
+                    "type": "integer",
+                    "format": "uint8",
+                    "example": 9
+                },
+                "runtime": {
+                    "type": "integer",
+                    "format": "uint16",
+                    "example": 155
+                }
+            }
+        },
+        "request.UpdateMaxCertification": {
+            "type": "object",
+            "properties": {
+                "max_certification": {
+                    "type": "string",
+                    "enum": [
+                        "0+",
+                        "6+",
+                        "12+",
+                        "16+",
+                        "18+",
+                        "G",
+                        "PG",
+                        "PG-13",
+                        "R",
+                        "NC-17"
+                    ],
+                    "example": "12+"
+                }
+            }
+        },
+        "request.UpdateRole": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "example": "editor"
+                }
+            }
+        },
+        "request.UpdateRolePermissions": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Редактор каталога"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "film:write",
+                            "film:delete",
+                            "actor:write",
+                            "actor:delete",
+                            "user:manage",
+                            "user:moderate",
+                            "role:manage"
+                        ]
+                    },
+                    "example": [
+                        "film:write",
+                        "actor:write"
+                    ]
+                }
+            }
+        },
+        "request.VerifyLogin": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "remember_me": {
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "token": {
+                    "type": "string",
+                    "example": "vkc_5b1e9a7f..."
+                }
+            }
+        },
+        "response.Actor": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Timothée Chalamet",
+                        "Тимоти Шаламэ"
+                    ]
+                },
+                "biography": {
+                    "type": "string",
+                    "example": "Американский актёр"
+                },
+                "birth_place": {
+                    "type": "string",
+                    "example": "Нью-Йорк"
+                },
+                "birthday": {
+                    "type": "string",
+                    "format": "date",
+                    "example": "12.02.2002"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "США"
+                },
+                "death_date": {
+                    "type": "string",
+                    "format": "date",
+                    "example": "12.02.2092"
+                },
+                "id": {
+                    "type": "integer",
+                    "format": "uint64",
+                    "example": 5
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Тимоти Шаламе"
+                },
+                "sex": {
+                    "type": "string",
+                    "enum": [
+                        "male",
+                        "female"
+                    ],
+                    "example": "male"
+                }
+            }
+        },
+        "response.ActorFilms": {
+            "type": "object",
+            "properties": {
+                "data_publish": {
+                    "type": "string",
+                    "format": "date",
+                    "example": "12.02.2023"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Futuristic film"
+                },
+                "id": {
+                    "type": "integer",
+                    "format": "uint64",
+                    "example": 5
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Dune"
+                },
+                "rating": {
+                    "type": "integer",
+                    "format": "uint8",
+                    "example": 9
+                }
+            }
+        },
+        "response.ActorFilmsCount": {
+            "type": "object",
+            "properties": {
+                "films_count": {
+                    "type": "integer",
+                    "format": "uint64",
+                    "example": 12
+                },
+                "id": {
+                    "type": "integer",
+                    "format": "uint64",
+                    "example": 5
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Тимоти Шаламе"
+                }
+            }
+        },
+        "response.ActorRating": {
+            "type": "object",
+            "properties": {
+                "average_rating": {
+                    "type": "number",
+                    "format": "double",
+                    "example": 7.5
+                },
+                "films_count": {
+                    "type": "integer",
+                    "format": "uint64",
+                    "example": 12
+                },
+                "id": {
+                    "type": "integer",
+                    "format": "uint64",
+                    "example": 5
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Тимоти Шаламе"
+                }
+            }
+        },
+        "response.ActorWithFilms": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Timothée Chalamet",
+                        "Тимоти Шаламэ"
+                    ]
+                },
+                "biography": {
+                    "type": "string",
+                    "example": "Американский актёр"
+                },
+                "birth_place": {
+                    "type": "string",
+                    "example": "Нью-Йорк"
+                },
+                "birthday": {
+                    "type": "string",
+                    "format": "date",
+                    "example": "12.02.2002"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "США"
+                },
+                "death_date": {
+                    "type": "string",
+                    "format": "date",
+                    "example": "12.02.2092"
+                },
+                "films": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.ActorFilms"
+                    }
+                },
+                "id": {
+                    "type": "integer",
+                    "format": "uint64",
+                    "example": 5
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Тимоти Шаламе"
+                },
+                "sex": {
+                    "type": "string",
+                    "enum": [
+                        "male",
+                        "female"
+                    ],
+                    "example": "male"
+                }
+            }
+        },
+        "response.CreatedToken": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-01-02T15:04:05Z"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2030-01-02T15:04:05Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "format": "uint64",
+                    "example": 5
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-02T15:04:05Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "ci"
+                },
+                "read_only": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "token": {
+                    "type": "string",
+                    "example": "vkf_3f7a0c9d..."
+                }
+            }
+        },
+        "response.Credentials": {
             "type": "object",
             "properties": {
-                "error_message": {
-                    "type": "string"
+                "access_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIs..."
+                },
+                "expires_in": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 900
+                },
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "3f7a0-c9d12"
+                    ]
+                },
+                "refresh_token": {
+                    "type": "string",
+                    "example": "vkr_3f7a0c9d..."
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
                 }
             }
         },
-        "request.CreateActor": {
+        "response.Enrollment": {
             "type": "object",
             "properties": {
-                "birthday": {
-                    "type": "string",
-                    "format": "date",
-                    "example": "12.02.2002"
-                },
-                "name": {
+                "secret": {
                     "type": "string",
-                    "example": "Тимоти Шаламе"
+                    "example": "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
                 },
-                "sex": {
+                "uri": {
                     "type": "string",
-                    "enum": [
-                        "male",
-                        "female"
-                    ],
-                    "example": "male"
+                    "example": "otpauth://totp/VK%20Film:admin?algorithm=SHA1\u0026digits=6\u0026issuer=VK+Film\u0026period=30\u0026secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
                 }
             }
         },
-        "request.CreateFilm": {
+        "response.Film": {
             "type": "object",
             "properties": {
                 "actors": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/response.FilmActors"
                     }
                 },
+                "age_certification": {
+                    "type": "string",
+                    "enum": [
+                        "0+",
+                        "6+",
+                        "12+",
+                        "16+",
+                        "18+",
+                        "G",
+                        "PG",
+                        "PG-13",
+                        "R",
+                        "NC-17"
+                    ],
+                    "example": "PG-13"
+                },
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "США",
+                        "Канада"
+                    ]
+                },
                 "data_publish": {
                     "type": "string",
                     "format": "date",
@@ -926,60 +4427,71 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Futuristic film"
                 },
+                "id": {
+                    "type": "integer",
+                    "format": "uint64",
+                    "example": 5
+                },
                 "name": {
                     "type": "string",
                     "example": "Dune"
                 },
+                "original_language": {
+                    "type": "string",
+                    "example": "en"
+                },
                 "rating": {
                     "type": "integer",
                     "format": "uint8",
                     "example": 9
+                },
+                "runtime": {
+                    "type": "integer",
+                    "format": "uint16",
+                    "example": 155
                 }
             }
         },
-        "request.CreateUser": {
+        "response.FilmActors": {
             "type": "object",
             "properties": {
-                "login": {
-                    "type": "string",
-                    "example": "login"
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Timothée Chalamet",
+                        "Тимоти Шаламэ"
+                    ]
                 },
-                "password": {
+                "biography": {
                     "type": "string",
-                    "example": "password"
+                    "example": "Американский актёр"
                 },
-                "role": {
-                    "type": "string",
-                    "default": "user",
-                    "enum": [
-                        "user",
-                        "admin"
-                    ],
-                    "example": "user"
-                }
-            }
-        },
-        "request.Login": {
-            "type": "object",
-            "properties": {
-                "login": {
+                "birth_place": {
                     "type": "string",
-                    "example": "login"
+                    "example": "Нью-Йорк"
                 },
-                "password": {
-                    "type": "string",
-                    "example": "password"
-                }
-            }
-        },
-        "request.UpdateActor": {
-            "type": "object",
-            "properties": {
                 "birthday": {
                     "type": "string",
                     "format": "date",
                     "example": "12.02.2002"
                 },
+                "country": {
+                    "type": "string",
+                    "example": "США"
+                },
+                "death_date": {
+                    "type": "string",
+                    "format": "date",
+                    "example": "12.02.2092"
+                },
+                "id": {
+                    "type": "integer",
+                    "format": "uint64",
+                    "example": 5
+                },
                 "name": {
                     "type": "string",
                     "example": "Тимоти Шаламе"
@@ -994,27 +4506,46 @@ const docTemplate = `{
                 }
             }
         },
-        "request.UpdateFilm": {
+        "response.FilmsPerYear": {
             "type": "object",
             "properties": {
-                "actors": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "count": {
+                    "type": "integer",
+                    "format": "uint64",
+                    "example": 12
                 },
-                "data_publish": {
-                    "type": "string",
-                    "format": "date",
-                    "example": "12.02.2023"
+                "year": {
+                    "type": "integer",
+                    "format": "uint64",
+                    "example": 2021
+                }
+            }
+        },
+        "response.PendingLogin": {
+            "type": "object",
+            "properties": {
+                "enroll_required": {
+                    "type": "boolean",
+                    "example": false
                 },
-                "description": {
-                    "type": "string",
-                    "example": "Futuristic film"
+                "expires_in": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 300
                 },
-                "name": {
+                "token": {
                     "type": "string",
-                    "example": "Dune"
+                    "example": "vkc_5b1e9a7f..."
+                }
+            }
+        },
+        "response.RatingCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "format": "uint64",
+                    "example": 12
                 },
                 "rating": {
                     "type": "integer",
@@ -1023,75 +4554,155 @@ const docTemplate = `{
                 }
             }
         },
-        "request.UpdateRole": {
+        "response.RecoveryCodes": {
             "type": "object",
             "properties": {
-                "role": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "3f7a0-c9d12",
+                        "8e21b-04fa9"
+                    ]
+                }
+            }
+        },
+        "response.Role": {
+            "type": "object",
+            "properties": {
+                "description": {
                     "type": "string",
-                    "enum": [
-                        "user",
+                    "example": "Редактор каталога"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "editor"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "film:write",
+                        "actor:write"
+                    ]
+                }
+            }
+        },
+        "response.Route": {
+            "type": "object",
+            "properties": {
+                "auth_required": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "method": {
+                    "type": "string",
+                    "example": "DELETE"
+                },
+                "path": {
+                    "type": "string",
+                    "example": "/api/v1/film/{film_id}"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "film:delete"
+                    ]
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
                         "admin"
-                    ],
-                    "example": "user"
+                    ]
                 }
             }
         },
-        "response.Actor": {
+        "response.SecurityEvent": {
             "type": "object",
             "properties": {
-                "birthday": {
+                "created_at": {
                     "type": "string",
-                    "format": "date",
-                    "example": "12.02.2002"
+                    "format": "date-time",
+                    "example": "2024-01-02T15:04:05Z"
                 },
                 "id": {
                     "type": "integer",
                     "format": "uint64",
-                    "example": 5
+                    "example": 42
                 },
-                "name": {
+                "ip": {
                     "type": "string",
-                    "example": "Тимоти Шаламе"
+                    "example": "192.168.0.1"
                 },
-                "sex": {
+                "login": {
+                    "type": "string",
+                    "example": "login"
+                },
+                "type": {
                     "type": "string",
                     "enum": [
-                        "male",
-                        "female"
+                        "login_succeeded",
+                        "login_failed",
+                        "logout",
+                        "password_changed",
+                        "password_reset",
+                        "lockout"
                     ],
-                    "example": "male"
+                    "example": "login_failed"
+                },
+                "user_agent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "format": "uint64",
+                    "example": 1
                 }
             }
         },
-        "response.ActorFilms": {
+        "response.Session": {
             "type": "object",
             "properties": {
-                "data_publish": {
+                "created_at": {
                     "type": "string",
-                    "format": "date",
-                    "example": "12.02.2023"
+                    "format": "date-time",
+                    "example": "2024-01-02T15:04:05Z"
                 },
-                "description": {
-                    "type": "string",
-                    "example": "Futuristic film"
+                "current": {
+                    "type": "boolean",
+                    "example": true
                 },
                 "id": {
-                    "type": "integer",
-                    "format": "uint64",
-                    "example": 5
+                    "type": "string",
+                    "example": "3f7a0c9d1b2e4f56"
                 },
-                "name": {
+                "ip": {
                     "type": "string",
-                    "example": "Dune"
+                    "example": "192.168.0.1"
                 },
-                "rating": {
-                    "type": "integer",
-                    "format": "uint8",
-                    "example": 9
+                "last_seen_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-02T15:04:05Z"
+                },
+                "user_agent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0"
                 }
             }
         },
-        "response.ActorWithFilms": {
+        "response.StatsActor": {
             "type": "object",
             "properties": {
                 "birthday": {
@@ -1099,12 +4710,6 @@ const docTemplate = `{
                     "format": "date",
                     "example": "12.02.2002"
                 },
-                "films": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/response.ActorFilms"
-                    }
-                },
                 "id": {
                     "type": "integer",
                     "format": "uint64",
@@ -1124,24 +4729,14 @@ const docTemplate = `{
                 }
             }
         },
-        "response.Film": {
+        "response.StatsFilm": {
             "type": "object",
             "properties": {
-                "actors": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/response.FilmActors"
-                    }
-                },
                 "data_publish": {
                     "type": "string",
                     "format": "date",
                     "example": "12.02.2023"
                 },
-                "description": {
-                    "type": "string",
-                    "example": "Futuristic film"
-                },
                 "id": {
                     "type": "integer",
                     "format": "uint64",
@@ -1158,36 +4753,46 @@ const docTemplate = `{
                 }
             }
         },
-        "response.FilmActors": {
+        "response.Token": {
             "type": "object",
             "properties": {
-                "birthday": {
+                "created_at": {
                     "type": "string",
-                    "format": "date",
-                    "example": "12.02.2002"
+                    "format": "date-time",
+                    "example": "2024-01-02T15:04:05Z"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2030-01-02T15:04:05Z"
                 },
                 "id": {
                     "type": "integer",
                     "format": "uint64",
                     "example": 5
                 },
-                "name": {
+                "last_used_at": {
                     "type": "string",
-                    "example": "Тимоти Шаламе"
+                    "format": "date-time",
+                    "example": "2024-03-02T15:04:05Z"
                 },
-                "sex": {
+                "name": {
                     "type": "string",
-                    "enum": [
-                        "male",
-                        "female"
-                    ],
-                    "example": "male"
+                    "example": "ci"
+                },
+                "read_only": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
         "response.User": {
             "type": "object",
             "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
                 "id": {
                     "type": "integer",
                     "format": "uint64",
@@ -1197,20 +4802,57 @@ const docTemplate = `{
                     "type": "string",
                     "example": "login"
                 },
+                "max_certification": {
+                    "type": "string",
+                    "enum": [
+                        "0+",
+                        "6+",
+                        "12+",
+                        "16+",
+                        "18+",
+                        "G",
+                        "PG",
+                        "PG-13",
+                        "R",
+                        "NC-17"
+                    ],
+                    "example": "12+"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "film:write",
+                        "actor:write"
+                    ]
+                },
                 "role": {
+                    "type": "string",
+                    "example": "editor"
+                },
+                "status": {
                     "type": "string",
                     "enum": [
-                        "user",
-                        "admin"
+                        "active",
+                        "pending",
+                        "rejected"
                     ],
-                    "example": "user"
+                    "example": "active"
                 }
             }
         }
     },
     "securityDefinitions": {
+        "bearerToken": {
+            "description": "Персональный токен пользователя в формате ` + "`" + `Bearer \u003ctoken\u003e` + "`" + `",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        },
         "sessionCookie": {
-            "description": "Уникальный идентификационный номер сессии",
+            "description": "Уникальный идентификационный номер сессии. Запросы, кроме GET, HEAD и OPTIONS, должны передавать в заголовке X-CSRF-Token значение cookie csrf_token",
             "type": "apiKey",
             "name": "session_id",
             "in": "cookie"
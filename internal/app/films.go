@@ -11,10 +11,16 @@ import (
 	"vk_film/config"
 	v1 "vk_film/internal/delivery/http/v1"
 	"vk_film/internal/delivery/http/v1/handlers"
+	"vk_film/internal/delivery/middleware"
 	"vk_film/internal/repository/actor"
+	"vk_film/internal/repository/event"
 	"vk_film/internal/repository/film"
+	"vk_film/internal/repository/role"
 	"vk_film/internal/repository/session"
+	"vk_film/internal/repository/stats"
+	"vk_film/internal/repository/token"
 	"vk_film/internal/repository/user"
+	"vk_film/internal/usecase/accounts"
 	"vk_film/internal/usecase/auth"
 	"vk_film/pkg/logger"
 	"vk_film/pkg/server"
@@ -30,6 +36,10 @@ func checkDatabaseConnections(db *sqlx.DB, rds *redis.Client, l logger.Interface
 
 	l.Info("[App] Init - success check connection to postgresql")
 
+	if rds == nil {
+		return true
+	}
+
 	if err := rds.Ping(context.Background()).Err(); err != nil {
 		l.Info("[App] Init - can't check connection to redis with error: %s", err)
 		return false
@@ -58,11 +68,16 @@ func Run(cfg *config.Config) {
 	defer pg.Close()
 
 	// Redis
-	opt, err := redis.ParseURL(cfg.Redis.URL)
-	if err != nil {
-		l.Fatal("[App] Init  - redis - redis.New: %s", err)
+	// Если сессии не хранятся в Redis, он используется только для защиты от перебора паролей и может быть не указан
+	var rds *redis.Client
+	sessionsInRedis := cfg.Auth.Mode != auth.JWTMode && cfg.Auth.Session.Backend == session.RedisBackend
+	if sessionsInRedis || cfg.Redis.URL != "" {
+		opt, err := redis.ParseURL(cfg.Redis.URL)
+		if err != nil {
+			l.Fatal("[App] Init  - redis - redis.New: %s", err)
+		}
+		rds = redis.NewClient(opt)
 	}
-	rds := redis.NewClient(opt)
 
 	if !checkDatabaseConnections(pg, rds, l) {
 		return
@@ -72,18 +87,79 @@ func Run(cfg *config.Config) {
 	actorRepository := actor.NewPostgresActor(pg)
 	userRepository := user.NewPostgresUser(pg)
 	filmRepository := film.NewPostgresFilm(pg)
-	sessionRepository := session.NewRedisSession(rds)
+	tokenRepository := token.NewPostgresToken(pg)
+	statsRepository := stats.NewPostgresStats(pg)
+	roleRepository := role.NewPostgresRole(pg)
+	eventRepository := event.NewPostgresEvent(pg)
+
+	if err := prepareEventRetention(cfg.SecurityEvents, eventRepository, l); err != nil {
+		l.Fatal("[App] Init - prepare security events retention error: %s", err)
+	}
 
 	// Use-cases
-	sessionManager := auth.NewSessionManager(userRepository, sessionRepository)
+	passwordHasher, err := preparePasswordHasher(cfg.Auth.Passwords)
+	if err != nil {
+		l.Fatal("[App] Init - prepare password hashing error: %s", err)
+	}
+
+	if err := bootstrapAdmin(cfg.Bootstrap, userRepository, passwordHasher, os.Stderr, l); err != nil {
+		l.Fatal("[App] Init - bootstrap admin error: %s", err)
+	}
+
+	sessionManager, err := prepareSessionManager(cfg, pg, rds, userRepository, tokenRepository, passwordHasher,
+		eventRepository, l)
+	if err != nil {
+		l.Fatal("[App] Init - prepare session manager error: %s", err)
+	}
+
+	accountsUsecase := accounts.NewAccountsUsecase(userRepository)
+
+	registrationUsecase, err := prepareRegistration(cfg.Registration, userRepository, passwordHasher)
+	if err != nil {
+		l.Fatal("[App] Init - prepare registration error: %s", err)
+	}
+
+	recoveryUsecase, err := prepareRecovery(cfg, pg, rds, userRepository, sessionManager, l)
+	if err != nil {
+		l.Fatal("[App] Init - prepare password reset error: %s", err)
+	}
+
+	passwordPolicy, err := preparePasswordPolicy(cfg.Registration)
+	if err != nil {
+		l.Fatal("[App] Init - prepare password policy error: %s", err)
+	}
+
+	cookie, err := prepareSessionCookie(cfg.Auth.Cookie)
+	if err != nil {
+		l.Fatal("[App] Init - prepare session cookie error: %s", err)
+	}
 
 	// Handlers
 	actorHandlers := handlers.NewActorHandlers(actorRepository)
-	userHandlers := handlers.NewUserHandlers(userRepository, sessionManager)
+	userHandlers := handlers.NewUserHandlers(userRepository, roleRepository, sessionManager, accountsUsecase,
+		passwordHasher, passwordPolicy, cookie)
 	filmHandlers := handlers.NewFilmHandlers(filmRepository)
+	statsHandlers := handlers.NewStatsHandlers(statsRepository)
+	roleHandlers := handlers.NewRoleHandlers(roleRepository, sessionManager)
+	routeHandlers := handlers.NewRouteHandlers()
+	registrationHandlers := handlers.NewRegistrationHandlers(registrationUsecase)
+	recoveryHandlers := handlers.NewRecoveryHandlers(recoveryUsecase)
+	ssoHandlers, err := prepareSSO(cfg.Auth.OIDC, pg, userRepository, sessionManager, cookie)
+	if err != nil {
+		l.Fatal("[App] Init - prepare oidc error: %s", err)
+	}
 
 	// routes
-	router, err := v1.NewRouter("/api", l, prepareRoutes(actorHandlers, userHandlers, filmHandlers, sessionManager))
+	routes := prepareRoutes(actorHandlers, userHandlers, filmHandlers, statsHandlers, roleHandlers, routeHandlers,
+		ssoHandlers, registrationHandlers, recoveryHandlers, sessionManager, cookie)
+
+	routeTable, err := routes.Describe("/api")
+	if err != nil {
+		l.Fatal("[App] Init - describe routes error: %s", err)
+	}
+	routeHandlers.SetRoutes(routeTable)
+
+	router, err := v1.NewRouter("/api", l, middleware.CheckSession(sessionManager, cookie, cfg.Auth.Mode == auth.JWTMode), routes)
 	if err != nil {
 		l.Fatal("[App] Init - init handler error: %s", err)
 	}
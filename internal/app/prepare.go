@@ -1,23 +1,53 @@
 package app
 
 import (
+	"encoding/base64"
+	"expvar"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
 	httpSwagger "github.com/swaggo/http-swagger"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"regexp"
+	"time"
 	"vk_film/config"
 	v1 "vk_film/internal/delivery/http/v1"
 	"vk_film/internal/delivery/http/v1/handlers"
 	"vk_film/internal/delivery/middleware"
+	"vk_film/internal/pkg/jwt"
+	"vk_film/internal/pkg/notify"
+	"vk_film/internal/pkg/oidc"
 	"vk_film/internal/pkg/prepare"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/attempts"
+	"vk_film/internal/repository/event"
+	"vk_film/internal/repository/identity"
+	"vk_film/internal/repository/refresh"
+	"vk_film/internal/repository/reset"
+	"vk_film/internal/repository/session"
+	"vk_film/internal/repository/token"
+	"vk_film/internal/repository/twofactor"
+	"vk_film/internal/repository/user"
 	"vk_film/internal/usecase/auth"
+	"vk_film/internal/usecase/bootstrap"
+	"vk_film/internal/usecase/recovery"
+	"vk_film/internal/usecase/registration"
+	"vk_film/internal/usecase/sso"
 	"vk_film/pkg/logger"
 	"vk_film/pkg/mux"
+	"vk_film/pkg/slices"
 
 	_ "vk_film/docs"
 )
 
+// userCacheMetric
+// Имя метрики кеша пользователей сессий в expvar
+const userCacheMetric = "session_user_cache"
+
 func prepareLogger(cfg config.LoggerInfo) (*logger.Logger, *os.File) {
 	var logOut io.Writer
 	var logFile *os.File
@@ -49,13 +79,458 @@ func prepareLogger(cfg config.LoggerInfo) (*logger.Logger, *os.File) {
 	return l, logFile
 }
 
+// prepareJWTKey
+// Создаёт ключ подписи токенов из конфигурации
+func prepareJWTKey(cfg config.JWTKey) (*jwt.Key, error) {
+	switch cfg.Algorithm {
+	case jwt.HS256:
+		// Секрет не хранится в репозитории и должен быть задан при развёртывании
+		if cfg.Secret == "" {
+			return nil, errors.Errorf("secret of key %s is not configured", cfg.ID)
+		}
+		secret, err := base64.StdEncoding.DecodeString(cfg.Secret)
+		if err != nil {
+			return nil, errors.Wrapf(err, "try decode secret of key %s", cfg.ID)
+		}
+		return jwt.NewHS256Key(cfg.ID, secret)
+	case jwt.EdDSA:
+		if cfg.PrivateKey == "" {
+			public, err := base64.StdEncoding.DecodeString(cfg.PublicKey)
+			if err != nil {
+				return nil, errors.Wrapf(err, "try decode public key %s", cfg.ID)
+			}
+			return jwt.NewEd25519PublicKey(cfg.ID, public)
+		}
+
+		private, err := base64.StdEncoding.DecodeString(cfg.PrivateKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "try decode private key %s", cfg.ID)
+		}
+		return jwt.NewEd25519Key(cfg.ID, private)
+	}
+
+	return nil, errors.Wrapf(jwt.ErrorUnknownAlgorithm, "algorithm %s of key %s", cfg.Algorithm, cfg.ID)
+}
+
+func prepareJWTKeys(cfg config.JWT) (*jwt.KeySet, error) {
+	keys := make([]*jwt.Key, 0, len(cfg.Keys))
+	for _, keyCfg := range cfg.Keys {
+		key, err := prepareJWTKey(keyCfg)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return jwt.NewKeySet(cfg.SigningKey, keys...)
+}
+
+func prepareSessionManager(cfg *config.Config, pg *sqlx.DB, rds *redis.Client, users user.Repository,
+	tokens token.Repository, passwords auth.PasswordHasher, events event.Repository,
+	l logger.Interface) (auth.Manager, error) {
+	protection := auth.LoginProtection{
+		MaxLoginAttempts: cfg.LoginProtection.MaxLoginAttempts,
+		MaxIPAttempts:    cfg.LoginProtection.MaxIPAttempts,
+		BaseLockTime:     cfg.LoginProtection.BaseLockTime,
+		MaxLockTime:      cfg.LoginProtection.MaxLockTime,
+		AttemptsWindow:   cfg.LoginProtection.AttemptsWindow,
+	}
+
+	passwordPolicy, err := preparePasswordPolicy(cfg.Registration)
+	if err != nil {
+		return nil, errors.Wrap(err, "try prepare password policy")
+	}
+
+	twoFactorPolicy := auth.TwoFactorPolicy{
+		Issuer:           cfg.Auth.TwoFactor.Issuer,
+		RequiredForAdmin: cfg.Auth.TwoFactor.RequiredForAdmin,
+		ChallengeTTL:     cfg.Auth.TwoFactor.ChallengeTTL,
+		MaxAttempts:      cfg.Auth.TwoFactor.MaxAttempts,
+	}
+	twoFactorRepository := twofactor.NewPostgresTwoFactor(pg)
+
+	// Без Redis защита от перебора паролей отключается
+	var attemptsRepository attempts.Repository
+	if rds != nil {
+		attemptsRepository = attempts.NewRedisAttempts(rds)
+	} else {
+		l.Warn("[App] Init - redis is not configured, login protection is disabled")
+	}
+
+	switch cfg.Auth.Mode {
+	case auth.SessionMode:
+		sessions, err := prepareSessionRepository(cfg.Auth.Session, pg, rds, l)
+		if err != nil {
+			return nil, err
+		}
+		sessionPolicy, err := prepareSessionPolicy(cfg.Auth.Session)
+		if err != nil {
+			return nil, errors.Wrap(err, "try prepare session policy")
+		}
+
+		cache := auth.NewUserCache(cfg.Auth.Session.UserCacheTTL)
+		expvar.Publish(userCacheMetric, expvar.Func(func() any { return cache.Stats() }))
+
+		return auth.NewSessionManager(users, sessions, attemptsRepository, tokens,
+			twoFactorRepository, protection, twoFactorPolicy, sessionPolicy, cache, passwords, passwordPolicy,
+			events, l), nil
+	case auth.JWTMode:
+		keys, err := prepareJWTKeys(cfg.Auth.JWT)
+		if err != nil {
+			return nil, errors.Wrap(err, "try prepare jwt keys")
+		}
+
+		return auth.NewJWTManager(users, attemptsRepository, tokens, twoFactorRepository,
+			refresh.NewPostgresRefresh(pg), keys,
+			auth.JWTPolicy{
+				AccessTTL:  cfg.Auth.JWT.AccessTTL,
+				RefreshTTL: cfg.Auth.JWT.RefreshTTL,
+			}, protection, twoFactorPolicy, passwords, passwordPolicy, events, l), nil
+	}
+
+	return nil, errors.Errorf("unknown auth mode %s", cfg.Auth.Mode)
+}
+
+// preparePasswordHasher
+// Создаёт хешер паролей с параметрами argon2id из конфигурации
+func preparePasswordHasher(cfg config.Passwords) (*auth.Argon2Hasher, error) {
+	if cfg.Iterations < 1 || cfg.Parallelism < 1 {
+		return nil, errors.New("iterations and parallelism must be positive")
+	}
+
+	// argon2 требует не меньше 8 KiB памяти на каждый поток
+	if cfg.Memory < 8*uint32(cfg.Parallelism) {
+		return nil, errors.Errorf("memory must be at least %d KiB", 8*uint32(cfg.Parallelism))
+	}
+
+	if cfg.SaltLength < 8 || cfg.KeyLength < 16 {
+		return nil, errors.New("salt_length must be at least 8 and key_length at least 16 bytes")
+	}
+
+	return auth.NewArgon2Hasher(auth.Argon2Params{
+		Memory:      cfg.Memory,
+		Iterations:  cfg.Iterations,
+		Parallelism: cfg.Parallelism,
+		SaltLength:  cfg.SaltLength,
+		KeyLength:   cfg.KeyLength,
+	}), nil
+}
+
+// bootstrapAdmin
+// Создаёт первого администратора, если в системе нет ни одного. Сгенерированный пароль
+// выводится в out один раз, не попадает в лог и больше нигде не хранится
+func bootstrapAdmin(cfg config.Bootstrap, users user.Repository, passwords auth.PasswordHasher, out io.Writer,
+	l logger.Interface) error {
+	result, err := bootstrap.NewAdminBootstrap(users, passwords).EnsureAdmin(bootstrap.Admin{
+		Login:    cfg.AdminLogin,
+		Password: cfg.AdminPassword,
+		Email:    cfg.AdminEmail,
+	})
+	if err != nil || result == nil {
+		return err
+	}
+
+	if result.Generated {
+		// Пароль выводится мимо логгера, чтобы не оседать в системе сбора логов
+		_, err = fmt.Fprintf(out, "Created admin %s with generated password: %s\n"+
+			"Change it after the first login, it will not be shown again.\n", result.User.Login, result.Password)
+		if err != nil {
+			return errors.Wrap(err, "can't print generated admin password")
+		}
+		l.Warn("[App] Init - created admin %s with generated password, change it after the first login",
+			result.User.Login)
+	} else {
+		l.Info("[App] Init - created admin %s with configured password", result.User.Login)
+	}
+
+	return nil
+}
+
+// prepareSessionRepository
+// Создаёт хранилище сессий выбранного в конфигурации типа
+func prepareSessionRepository(cfg config.Session, pg *sqlx.DB, rds *redis.Client,
+	l logger.Interface) (session.Repository, error) {
+	switch cfg.Backend {
+	case session.RedisBackend:
+		if rds == nil {
+			return nil, errors.New("redis is required for redis session backend")
+		}
+		return session.NewRedisSession(rds), nil
+	case session.PostgresBackend:
+		sessions := session.NewPostgresSession(pg)
+		if cfg.EvictionInterval > 0 {
+			go cleanupSessions(sessions, cfg.EvictionInterval, l)
+		}
+		return sessions, nil
+	case session.MemoryBackend:
+		l.Warn("[App] Init - sessions are stored in memory and will be lost on restart, use it for development only")
+		return session.NewMemorySession(cfg.EvictionInterval), nil
+	}
+
+	return nil, errors.Errorf("unknown session backend %s", cfg.Backend)
+}
+
+// cleanupSessions
+// Периодически удаляет из Postgres истёкшие сессии, работает до завершения процесса
+func cleanupSessions(sessions *session.PostgresSession, interval time.Duration, l logger.Interface) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		n, err := sessions.Cleanup()
+		if err != nil {
+			l.Error("[App] Cleanup - delete expired sessions error: %s", err)
+			continue
+		}
+		l.Debug("[App] Cleanup - deleted %d expired sessions", n)
+	}
+}
+
+// prepareEventRetention
+// Запускает удаление событий безопасности старше срока хранения. Нулевой срок хранит события бессрочно
+func prepareEventRetention(cfg config.SecurityEvents, events *event.PostgresEvent, l logger.Interface) error {
+	if cfg.Retention < 0 {
+		return errors.New("retention must not be negative")
+	}
+
+	if cfg.Retention == 0 {
+		l.Info("[App] Init - security events are kept forever")
+		return nil
+	}
+
+	if cfg.CleanupInterval <= 0 {
+		return errors.New("cleanup_interval must be positive")
+	}
+
+	go cleanupEvents(events, cfg.Retention, cfg.CleanupInterval, l)
+	return nil
+}
+
+// cleanupEvents
+// Периодически удаляет события безопасности старше срока хранения, работает до завершения процесса
+func cleanupEvents(events *event.PostgresEvent, retention, interval time.Duration, l logger.Interface) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		n, err := events.DeleteEventsBefore(time.Now().Add(-retention))
+		if err != nil {
+			l.Error("[App] Cleanup - delete old security events error: %s", err)
+			continue
+		}
+		l.Debug("[App] Cleanup - deleted %d old security events", n)
+	}
+}
+
+// prepareSessionPolicy
+// Собирает политику времени жизни сессий. Нулевые значения remember и admin отключают отдельную политику
+func prepareSessionPolicy(cfg config.Session) (auth.SessionPolicy, error) {
+	policy := auth.SessionPolicy{
+		Default:  auth.SessionLifetime{IdleTimeout: cfg.IdleTimeout, MaxLifetime: cfg.MaxLifetime},
+		Remember: auth.SessionLifetime{IdleTimeout: cfg.RememberIdleTimeout, MaxLifetime: cfg.RememberMaxLifetime},
+		Admin:    auth.SessionLifetime{IdleTimeout: cfg.AdminIdleTimeout, MaxLifetime: cfg.AdminMaxLifetime},
+	}
+
+	if policy.Default.IdleTimeout <= 0 || policy.Default.MaxLifetime < policy.Default.IdleTimeout {
+		return auth.SessionPolicy{}, errors.New("idle_timeout must be positive and not greater than max_lifetime")
+	}
+
+	for name, lifetime := range map[string]auth.SessionLifetime{"remember": policy.Remember, "admin": policy.Admin} {
+		if lifetime.IdleTimeout < 0 || lifetime.MaxLifetime < lifetime.IdleTimeout {
+			return auth.SessionPolicy{}, errors.Errorf(
+				"%s_idle_timeout must not be negative or greater than %s_max_lifetime", name, name)
+		}
+	}
+
+	return policy, nil
+}
+
+// prepareSessionCookie
+// Создаёт атрибуты cookie сессии из конфигурации
+func prepareSessionCookie(cfg config.Cookie) (*middleware.SessionCookie, error) {
+	sameSite, err := middleware.ParseSameSite(cfg.SameSite)
+	if err != nil {
+		return nil, err
+	}
+
+	// Браузеры отбрасывают cookie с SameSite=None без атрибута Secure
+	if sameSite == http.SameSiteNoneMode && !cfg.Secure {
+		return nil, errors.New("same_site none requires secure cookie")
+	}
+
+	return &middleware.SessionCookie{
+		Name:     cfg.Name,
+		Domain:   cfg.Domain,
+		Secure:   cfg.Secure,
+		SameSite: sameSite,
+	}, nil
+}
+
+// prepareRegistration
+// Создаёт сценарий самостоятельной регистрации с политикой из конфигурации
+func prepareRegistration(cfg config.Registration, users user.Repository,
+	passwords auth.PasswordHasher) (*registration.RegistrationUsecase, error) {
+	policy := registration.DefaultPolicy
+	policy.Enabled = cfg.Enabled
+	policy.DefaultRole = types.Roles(cfg.DefaultRole)
+	policy.LoginMinLength = cfg.LoginMinLength
+	policy.LoginMaxLength = cfg.LoginMaxLength
+
+	policy.LoginPattern = nil
+	if cfg.LoginPattern != "" {
+		pattern, err := regexp.Compile(cfg.LoginPattern)
+		if err != nil {
+			return nil, errors.Wrap(err, "try compile login_pattern")
+		}
+		policy.LoginPattern = pattern
+	}
+
+	if policy.LoginMinLength < 1 || policy.LoginMaxLength < policy.LoginMinLength {
+		return nil, errors.New("login_min_length must be positive and not greater than login_max_length")
+	}
+
+	passwordPolicy, err := preparePasswordPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+	policy.Password = passwordPolicy
+
+	return registration.NewRegistrationUsecase(users, passwords, policy), nil
+}
+
+// preparePasswordPolicy
+// Создаёт политику новых паролей. Она задаётся в разделе регистрации, но применяется
+// и при смене и сбросе пароля
+func preparePasswordPolicy(cfg config.Registration) (auth.PasswordPolicy, error) {
+	policy := auth.DefaultPasswordPolicy
+	policy.MinLength = cfg.PasswordMinLength
+	policy.MinClasses = cfg.PasswordMinClasses
+
+	if policy.MinLength > policy.MaxLength {
+		return policy, errors.Errorf("password_min_length must not be greater than %d", policy.MaxLength)
+	}
+
+	return policy, nil
+}
+
+// prepareNotifier
+// Создаёт способ доставки писем пользователям. Файл и лог предназначены только для локальной разработки,
+// поэтому способ доставки нужно указать явно.
+func prepareNotifier(cfg config.Notifier, l logger.Interface) (notify.Notifier, error) {
+	switch cfg.Type {
+	case "log":
+		l.Warn("[App] Init - notifications are written to the log without bodies, use it for development only")
+		return notify.NewLogNotifier(l), nil
+	case "file":
+		l.Warn("[App] Init - notifications are written to %s, use it for development only", cfg.File)
+		return notify.NewFileNotifier(cfg.File), nil
+	case "smtp":
+		if cfg.SMTP.Host == "" || cfg.SMTP.From == "" {
+			return nil, errors.New("host and from are required for smtp notifier")
+		}
+		return notify.NewSMTPNotifier(notify.SMTPConfig{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+		}), nil
+	case "":
+		return nil, errors.New("notifier type is required")
+	default:
+		return nil, errors.Errorf("unknown notifier type %s", cfg.Type)
+	}
+}
+
+// prepareRecovery
+// Создаёт сценарий сброса пароля по одноразовым токенам
+func prepareRecovery(cfg *config.Config, pg *sqlx.DB, rds *redis.Client, users user.Repository,
+	sessionManager auth.Manager, l logger.Interface) (*recovery.RecoveryUsecase, error) {
+	if cfg.PasswordReset.TokenTTL <= 0 {
+		return nil, errors.New("password_reset token_ttl must be positive")
+	}
+
+	if cfg.PasswordReset.RequestsWindow <= 0 {
+		return nil, errors.New("password_reset requests_window must be positive")
+	}
+
+	notifier, err := prepareNotifier(cfg.Notifier, l)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordPolicy, err := preparePasswordPolicy(cfg.Registration)
+	if err != nil {
+		return nil, err
+	}
+
+	// Без Redis число запросов сброса не ограничивается
+	var attemptsRepository attempts.Repository
+	if rds != nil {
+		attemptsRepository = attempts.NewRedisAttempts(rds)
+	} else {
+		l.Warn("[App] Init - redis is not configured, password reset requests are not limited")
+	}
+
+	return recovery.NewRecoveryUsecase(users, reset.NewPostgresReset(pg), sessionManager, attemptsRepository, notifier,
+		recovery.Policy{
+			TokenTTL:        cfg.PasswordReset.TokenTTL,
+			ResetURL:        cfg.PasswordReset.ResetURL,
+			Password:        passwordPolicy,
+			MaxUserRequests: cfg.PasswordReset.MaxUserRequests,
+			MaxIPRequests:   cfg.PasswordReset.MaxIPRequests,
+			RequestsWindow:  cfg.PasswordReset.RequestsWindow,
+		}, l), nil
+}
+
+// prepareSSO
+// Создаёт обработчики входа через OpenID Connect провайдер. Если вход выключен, возвращает nil.
+func prepareSSO(cfg config.OIDC, pg *sqlx.DB, users user.Repository, sessionManager auth.Manager,
+	cookie *middleware.SessionCookie) (*handlers.SSOHandlers, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	if cfg.Issuer == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+		return nil, errors.New("issuer, client_id and redirect_url are required for oidc")
+	}
+
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:       cfg.Issuer,
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  cfg.RedirectURL,
+		Scopes:       cfg.Scopes,
+	}, &http.Client{Timeout: cfg.Timeout})
+
+	usecase := sso.NewOIDCUsecase(provider, users, identity.NewPostgresIdentity(pg), sessionManager, sso.Policy{
+		LoginClaim: cfg.LoginClaim,
+		RoleClaim:  cfg.RoleClaim,
+		Roles: slices.Map(cfg.Roles, func(r config.OIDCRole) sso.RoleMapping {
+			return sso.RoleMapping{Value: r.Value, Role: types.Roles(r.Role)}
+		}),
+		DefaultRole: types.Roles(cfg.DefaultRole),
+	})
+
+	return handlers.NewSSOHandlers(usecase, cookie, cfg.PostLoginURL), nil
+}
+
 func Swagger(w http.ResponseWriter, r *http.Request, _ mux.Params) {
 	httpSwagger.Handler()(w, r)
 }
 
+// Metrics
+// Отдаёт опубликованные через expvar метрики сервера
+func Metrics(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	expvar.Handler().ServeHTTP(w, r)
+}
+
 func prepareRoutes(actorHandlers *handlers.ActorHandlers, userHandlers *handlers.UserHandlers,
-	filmHandlers *handlers.FilmHandlers, sessionManager auth.Manager) v1.Routes {
-	return v1.Routes{
+	filmHandlers *handlers.FilmHandlers, statsHandlers *handlers.StatsHandlers, roleHandlers *handlers.RoleHandlers,
+	routeHandlers *handlers.RouteHandlers, ssoHandlers *handlers.SSOHandlers,
+	registrationHandlers *handlers.RegistrationHandlers, recoveryHandlers *handlers.RecoveryHandlers,
+	sessionManager auth.Manager, cookie *middleware.SessionCookie) v1.Routes {
+	routes := v1.Routes{
 		//"Index"
 		v1.Route{
 			Method:      http.MethodGet,
@@ -63,101 +538,433 @@ func prepareRoutes(actorHandlers *handlers.ActorHandlers, userHandlers *handlers
 			HandlerFunc: Swagger,
 		},
 
+		// "Metrics"
+		v1.Route{
+			Method:      http.MethodGet,
+			Pattern:     "/metrics",
+			HandlerFunc: Metrics,
+			Roles:       []types.Roles{types.ADMIN},
+		},
+
+		// "CreateActor"
 		v1.Route{
 			Method:      http.MethodPost,
 			Pattern:     "/actor",
-			HandlerFunc: middleware.CheckSession(sessionManager)(actorHandlers.CreateActor),
+			HandlerFunc: actorHandlers.CreateActor,
+			Permissions: []types.Permission{types.ActorWrite},
 		},
 
 		// "DeleteActor"
 		v1.Route{
 			Method:      http.MethodDelete,
 			Pattern:     "/actor/{" + handlers.ActorIdField + "}",
-			HandlerFunc: middleware.CheckSession(sessionManager)(actorHandlers.DeleteActor),
+			HandlerFunc: actorHandlers.DeleteActor,
+			Permissions: []types.Permission{types.ActorDelete},
 		},
 
 		// "GetActors"
 		v1.Route{
 			Method:      http.MethodGet,
 			Pattern:     "/actor/list",
-			HandlerFunc: middleware.CheckSession(sessionManager)(actorHandlers.GetActors),
+			HandlerFunc: actorHandlers.GetActors,
+			Auth:        true,
 		},
 
 		// "UpdateActor"
 		v1.Route{
 			Method:      http.MethodPut,
 			Pattern:     "/actor/{" + handlers.ActorIdField + "}",
-			HandlerFunc: middleware.CheckSession(sessionManager)(actorHandlers.UpdateActor),
+			HandlerFunc: actorHandlers.UpdateActor,
+			Permissions: []types.Permission{types.ActorWrite},
 		},
 
 		// "CreateFilm"
 		v1.Route{
 			Method:      http.MethodPost,
 			Pattern:     "/film",
-			HandlerFunc: middleware.CheckSession(sessionManager)(filmHandlers.CreateFilm),
+			HandlerFunc: filmHandlers.CreateFilm,
+			Permissions: []types.Permission{types.FilmWrite},
 		},
 
 		// "DeleteFilm"
 		v1.Route{
 			Method:      http.MethodDelete,
 			Pattern:     "/film/{" + handlers.FilmIdField + "}",
-			HandlerFunc: middleware.CheckSession(sessionManager)(filmHandlers.DeleteFilm),
+			HandlerFunc: filmHandlers.DeleteFilm,
+			Permissions: []types.Permission{types.FilmDelete},
 		},
 
 		// "GetFilms",
 		v1.Route{
 			Method:      http.MethodGet,
 			Pattern:     "/film/list",
-			HandlerFunc: middleware.CheckSession(sessionManager)(filmHandlers.GetFilms),
+			HandlerFunc: filmHandlers.GetFilms,
+			Auth:        true,
 		},
 
 		// "UpdateFilm"
 		v1.Route{
 			Method:      http.MethodPut,
 			Pattern:     "/film/{" + handlers.FilmIdField + "}",
-			HandlerFunc: middleware.CheckSession(sessionManager)(filmHandlers.UpdateFilm),
+			HandlerFunc: filmHandlers.UpdateFilm,
+			Permissions: []types.Permission{types.FilmWrite},
 		},
 
 		// "CreateUser"
 		v1.Route{
 			Method:      http.MethodPost,
 			Pattern:     "/user",
-			HandlerFunc: middleware.CheckSession(sessionManager)(userHandlers.CreateUser),
+			HandlerFunc: userHandlers.CreateUser,
+			Permissions: []types.Permission{types.UserManage},
 		},
 
 		// "DeleteUser"
 		v1.Route{
 			Method:      http.MethodDelete,
 			Pattern:     "/user/{" + handlers.UserIdField + "}",
-			HandlerFunc: middleware.CheckSession(sessionManager)(userHandlers.DeleteUser),
+			HandlerFunc: userHandlers.DeleteUser,
+			Permissions: []types.Permission{types.UserManage},
 		},
 
 		// "Login"
 		v1.Route{
 			Method:      http.MethodPost,
 			Pattern:     "/login",
-			HandlerFunc: middleware.CheckNoSession(sessionManager)(userHandlers.Login),
+			HandlerFunc: middleware.CheckNoSession(sessionManager, cookie)(userHandlers.Login),
+		},
+
+		// "Register"
+		v1.Route{
+			Method:      http.MethodPost,
+			Pattern:     "/register",
+			HandlerFunc: middleware.CheckNoSession(sessionManager, cookie)(registrationHandlers.Register),
+		},
+
+		// "GetRegistrations"
+		v1.Route{
+			Method:      http.MethodGet,
+			Pattern:     "/registrations",
+			HandlerFunc: registrationHandlers.GetRegistrations,
+			Permissions: []types.Permission{types.UserManage},
+		},
+
+		// "ApproveRegistration"
+		v1.Route{
+			Method:      http.MethodPost,
+			Pattern:     "/registrations/{" + handlers.UserIdField + "}/approve",
+			HandlerFunc: registrationHandlers.ApproveRegistration,
+			Permissions: []types.Permission{types.UserManage},
+		},
+
+		// "RejectRegistration"
+		v1.Route{
+			Method:      http.MethodPost,
+			Pattern:     "/registrations/{" + handlers.UserIdField + "}/reject",
+			HandlerFunc: registrationHandlers.RejectRegistration,
+			Permissions: []types.Permission{types.UserManage},
+		},
+
+		// "RequestPasswordReset"
+		v1.Route{
+			Method:      http.MethodPost,
+			Pattern:     "/password/reset",
+			HandlerFunc: recoveryHandlers.RequestPasswordReset,
+		},
+
+		// "ConfirmPasswordReset"
+		v1.Route{
+			Method:      http.MethodPost,
+			Pattern:     "/password/reset/confirm",
+			HandlerFunc: recoveryHandlers.ConfirmPasswordReset,
+		},
+
+		// "VerifyLogin"
+		v1.Route{
+			Method:      http.MethodPost,
+			Pattern:     "/login/2fa",
+			HandlerFunc: middleware.CheckNoSession(sessionManager, cookie)(userHandlers.VerifyLogin),
+		},
+
+		// "EnrollLogin"
+		v1.Route{
+			Method:      http.MethodPost,
+			Pattern:     "/login/2fa/enroll",
+			HandlerFunc: middleware.CheckNoSession(sessionManager, cookie)(userHandlers.EnrollLogin),
+		},
+
+		// "Refresh"
+		v1.Route{
+			Method:      http.MethodPost,
+			Pattern:     "/refresh",
+			HandlerFunc: userHandlers.Refresh,
 		},
 
 		// "Logout"
 		v1.Route{
 			Method:      http.MethodPost,
 			Pattern:     "/logout",
-			HandlerFunc: middleware.CheckSession(sessionManager)(userHandlers.Logout),
+			HandlerFunc: userHandlers.Logout,
+			Auth:        true,
 		},
 
 		// "UpdateUserRole"
 		v1.Route{
 			Method:      http.MethodPut,
 			Pattern:     "/user/{" + handlers.UserIdField + "}/role",
-			HandlerFunc: middleware.CheckSession(sessionManager)(userHandlers.UpdateUserRole),
+			HandlerFunc: userHandlers.UpdateUserRole,
+			Permissions: []types.Permission{types.UserManage},
+		},
+
+		// "UpdateUserMaxCertification"
+		v1.Route{
+			Method:      http.MethodPut,
+			Pattern:     "/user/{" + handlers.UserIdField + "}/certification",
+			HandlerFunc: userHandlers.UpdateUserMaxCertification,
+			Permissions: []types.Permission{types.UserManage},
+		},
+
+		// "GetMe"
+		v1.Route{
+			Method:      http.MethodGet,
+			Pattern:     "/user/me",
+			HandlerFunc: userHandlers.GetMe,
+			Auth:        true,
+		},
+
+		// "ChangePassword"
+		v1.Route{
+			Method:      http.MethodPut,
+			Pattern:     "/user/me/password",
+			HandlerFunc: userHandlers.ChangePassword,
+			Auth:        true,
+		},
+
+		// "UpdateEmail"
+		v1.Route{
+			Method:      http.MethodPut,
+			Pattern:     "/user/me/email",
+			HandlerFunc: userHandlers.UpdateEmail,
+			Auth:        true,
+		},
+
+		// "EnrollTwoFactor"
+		v1.Route{
+			Method:      http.MethodPost,
+			Pattern:     "/user/me/2fa",
+			HandlerFunc: userHandlers.EnrollTwoFactor,
+			Auth:        true,
+		},
+
+		// "ConfirmTwoFactor"
+		v1.Route{
+			Method:      http.MethodPost,
+			Pattern:     "/user/me/2fa/confirm",
+			HandlerFunc: userHandlers.ConfirmTwoFactor,
+			Auth:        true,
+		},
+
+		// "DisableTwoFactor"
+		v1.Route{
+			Method:      http.MethodDelete,
+			Pattern:     "/user/me/2fa",
+			HandlerFunc: userHandlers.DisableTwoFactor,
+			Auth:        true,
+		},
+
+		// "CreateToken"
+		v1.Route{
+			Method:      http.MethodPost,
+			Pattern:     "/user/me/tokens",
+			HandlerFunc: userHandlers.CreateToken,
+			Auth:        true,
+		},
+
+		// "GetTokens"
+		v1.Route{
+			Method:      http.MethodGet,
+			Pattern:     "/user/me/tokens",
+			HandlerFunc: userHandlers.GetTokens,
+			Auth:        true,
+		},
+
+		// "RevokeToken"
+		v1.Route{
+			Method:      http.MethodDelete,
+			Pattern:     "/user/me/tokens/{" + handlers.TokenIdField + "}",
+			HandlerFunc: userHandlers.RevokeToken,
+			Auth:        true,
+		},
+
+		// "GetSessions"
+		v1.Route{
+			Method:      http.MethodGet,
+			Pattern:     "/user/me/sessions",
+			HandlerFunc: userHandlers.GetSessions,
+			Auth:        true,
+		},
+
+		// "RevokeSession"
+		v1.Route{
+			Method:      http.MethodDelete,
+			Pattern:     "/user/me/sessions/{" + handlers.SessionIdField + "}",
+			HandlerFunc: userHandlers.RevokeSession,
+			Auth:        true,
+		},
+
+		// "ResetUserPassword"
+		v1.Route{
+			Method:      http.MethodPut,
+			Pattern:     "/user/{" + handlers.UserIdField + "}/password",
+			HandlerFunc: userHandlers.ResetUserPassword,
+			Permissions: []types.Permission{types.UserManage},
+		},
+
+		// "UnlockUser"
+		v1.Route{
+			Method:      http.MethodDelete,
+			Pattern:     "/user/{" + handlers.UserIdField + "}/lock",
+			HandlerFunc: userHandlers.UnlockUser,
+			Permissions: []types.Permission{types.UserModerate},
+		},
+
+		// "RevokeUserSessions"
+		v1.Route{
+			Method:      http.MethodDelete,
+			Pattern:     "/user/{" + handlers.UserIdField + "}/sessions",
+			HandlerFunc: userHandlers.RevokeUserSessions,
+			Permissions: []types.Permission{types.UserModerate},
+		},
+
+		// "GetSecurityEvents"
+		v1.Route{
+			Method:      http.MethodGet,
+			Pattern:     "/user/me/security-events",
+			HandlerFunc: userHandlers.GetSecurityEvents,
+			Auth:        true,
+		},
+
+		// "GetUsersSecurityEvents"
+		v1.Route{
+			Method:      http.MethodGet,
+			Pattern:     "/user/security-events",
+			HandlerFunc: userHandlers.GetUsersSecurityEvents,
+			Permissions: []types.Permission{types.UserManage},
 		},
 
 		// "GetUsers"
 		v1.Route{
 			Method:      http.MethodGet,
 			Pattern:     "/user/list",
-			HandlerFunc: middleware.CheckSession(sessionManager)(userHandlers.GetUsers),
+			HandlerFunc: userHandlers.GetUsers,
+			Permissions: []types.Permission{types.UserManage},
+		},
+
+		// "GetFilmsPerYear"
+		v1.Route{
+			Method:      http.MethodGet,
+			Pattern:     "/stats/films/years",
+			HandlerFunc: statsHandlers.GetFilmsPerYear,
+			Auth:        true,
+		},
+
+		// "GetRatingDistribution"
+		v1.Route{
+			Method:      http.MethodGet,
+			Pattern:     "/stats/films/ratings",
+			HandlerFunc: statsHandlers.GetRatingDistribution,
+			Auth:        true,
+		},
+
+		// "GetFilmsWithoutActors"
+		v1.Route{
+			Method:      http.MethodGet,
+			Pattern:     "/stats/films/without-actors",
+			HandlerFunc: statsHandlers.GetFilmsWithoutActors,
+			Auth:        true,
+		},
+
+		// "GetTopActors"
+		v1.Route{
+			Method:      http.MethodGet,
+			Pattern:     "/stats/actors/top",
+			HandlerFunc: statsHandlers.GetTopActors,
+			Auth:        true,
+		},
+
+		// "GetActorsAverageRating"
+		v1.Route{
+			Method:      http.MethodGet,
+			Pattern:     "/stats/actors/ratings",
+			HandlerFunc: statsHandlers.GetActorsAverageRating,
+			Auth:        true,
+		},
+
+		// "GetActorsWithoutFilms"
+		v1.Route{
+			Method:      http.MethodGet,
+			Pattern:     "/stats/actors/without-films",
+			HandlerFunc: statsHandlers.GetActorsWithoutFilms,
+			Auth:        true,
+		},
+
+		// "GetRoles"
+		v1.Route{
+			Method:      http.MethodGet,
+			Pattern:     "/roles",
+			HandlerFunc: roleHandlers.GetRoles,
+			Permissions: []types.Permission{types.RoleManage},
+		},
+
+		// "CreateRole"
+		v1.Route{
+			Method:      http.MethodPost,
+			Pattern:     "/roles",
+			HandlerFunc: roleHandlers.CreateRole,
+			Permissions: []types.Permission{types.RoleManage},
+		},
+
+		// "UpdateRole"
+		v1.Route{
+			Method:      http.MethodPut,
+			Pattern:     "/roles/{" + handlers.RoleField + "}",
+			HandlerFunc: roleHandlers.UpdateRole,
+			Permissions: []types.Permission{types.RoleManage},
+		},
+
+		// "DeleteRole"
+		v1.Route{
+			Method:      http.MethodDelete,
+			Pattern:     "/roles/{" + handlers.RoleField + "}",
+			HandlerFunc: roleHandlers.DeleteRole,
+			Permissions: []types.Permission{types.RoleManage},
+		},
+
+		// "GetRoutes"
+		v1.Route{
+			Method:      http.MethodGet,
+			Pattern:     "/routes",
+			HandlerFunc: routeHandlers.GetRoutes,
+			Permissions: []types.Permission{types.RoleManage},
 		},
 	}
+
+	if ssoHandlers != nil {
+		routes = append(routes,
+			// "OIDCLogin"
+			v1.Route{
+				Method:      http.MethodGet,
+				Pattern:     "/oidc/login",
+				HandlerFunc: ssoHandlers.OIDCLogin,
+			},
+
+			// "OIDCCallback"
+			v1.Route{
+				Method:      http.MethodGet,
+				Pattern:     "/oidc/callback",
+				HandlerFunc: ssoHandlers.OIDCCallback,
+			},
+		)
+	}
+
+	return routes
 }
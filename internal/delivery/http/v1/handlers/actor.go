@@ -3,16 +3,30 @@ package handlers
 import (
 	"github.com/pkg/errors"
 	"net/http"
+	"net/url"
+	stdTime "time"
 	"vk_film/internal/delivery/http/v1/model/request"
 	"vk_film/internal/delivery/http/v1/model/response"
 	"vk_film/internal/delivery/middleware"
+	"vk_film/internal/pkg/time"
 	"vk_film/internal/pkg/types"
 	"vk_film/internal/repository/actor"
 	"vk_film/pkg/mux"
 	"vk_film/pkg/operate"
 )
 
-const ActorIdField = "actor_id"
+const (
+	SexKey          = "sex"
+	BirthdayFromKey = "birthday_from"
+	BirthdayToKey   = "birthday_to"
+	AgeFromKey      = "age_from"
+	AgeToKey        = "age_to"
+	WithFilmsKey    = "with_films"
+
+	MaxActorsLimit = 1000
+
+	ActorIdField = "actor_id"
+)
 
 type ActorHandlers struct {
 	repository actor.Repository
@@ -22,10 +36,117 @@ func NewActorHandlers(repository actor.Repository) *ActorHandlers {
 	return &ActorHandlers{repository: repository}
 }
 
+// now используется для вычисления границ дат рождения по возрасту.
+var now = stdTime.Now
+
+// ageToBirthdayRange
+// Переводит диапазон возраста в полных годах в диапазон дат рождения.
+// Nil граница возраста означает отсутствие соответствующей границы даты.
+func ageToBirthdayRange(ageFrom, ageTo *uint64) (from *time.FormattedTime, to *time.FormattedTime) {
+	year, month, day := now().Date()
+	today := stdTime.Date(year, month, day, 0, 0, 0, 0, stdTime.UTC)
+
+	if ageFrom != nil {
+		to = &time.FormattedTime{Time: today.AddDate(-int(*ageFrom), 0, 0)}
+	}
+
+	if ageTo != nil {
+		from = &time.FormattedTime{Time: today.AddDate(-int(*ageTo)-1, 0, 1)}
+	}
+
+	return from, to
+}
+
+// isDeathDateAfterBirthday
+// Проверяет, что дата смерти позже даты рождения. Если одна из дат не указана, проверка считается пройденной.
+func isDeathDateAfterBirthday(birthday, deathDate *time.FormattedTime) bool {
+	return birthday == nil || deathDate == nil || deathDate.After(birthday.Time)
+}
+
+func parseActorsParams(values url.Values) (actor.Params, error) {
+	params := actor.Params{
+		OrderField: types.ActorNameField,
+		Order:      types.ASC,
+		WithFilms:  true,
+	}
+
+	var err error
+
+	params.SearchString = values.Get(SearchStringKey)
+
+	if values.Has(SexKey) {
+		sex := types.Sexes(values.Get(SexKey))
+		if sex != types.MALE && sex != types.FEMALE {
+			return params, errors.Wrapf(ErrorIncorrectQueryParam, "with field %s and value %s", SexKey, sex)
+		}
+		params.Sex = &sex
+	}
+
+	if params.BirthdayFrom, err = parseDateParam(values, BirthdayFromKey); err != nil {
+		return params, err
+	}
+
+	if params.BirthdayTo, err = parseDateParam(values, BirthdayToKey); err != nil {
+		return params, err
+	}
+
+	ageFrom, err := parseOptionalUintParam(values, AgeFromKey)
+	if err != nil {
+		return params, err
+	}
+
+	ageTo, err := parseOptionalUintParam(values, AgeToKey)
+	if err != nil {
+		return params, err
+	}
+
+	// Ограничения по возрасту сужают диапазон дат рождения
+	ageBirthdayFrom, ageBirthdayTo := ageToBirthdayRange(ageFrom, ageTo)
+	if ageBirthdayFrom != nil && (params.BirthdayFrom == nil || ageBirthdayFrom.After(params.BirthdayFrom.Time)) {
+		params.BirthdayFrom = ageBirthdayFrom
+	}
+
+	if ageBirthdayTo != nil && (params.BirthdayTo == nil || ageBirthdayTo.Before(params.BirthdayTo.Time)) {
+		params.BirthdayTo = ageBirthdayTo
+	}
+
+	if values.Has(OrderFieldKey) {
+		field := types.ActorOrderField(values.Get(OrderFieldKey))
+		if field != types.ActorNameField && field != types.BirthdayField && field != types.FilmsCountField {
+			return params, errors.Wrapf(ErrorIncorrectQueryParam, "with field %s and value %s", OrderFieldKey, field)
+		}
+		params.OrderField = field
+	}
+
+	if values.Has(OrderKey) {
+		order := types.Order(values.Get(OrderKey))
+		if order != types.DESC && order != types.ASC {
+			return params, errors.Wrapf(ErrorIncorrectQueryParam, "with field %s and value %s", OrderKey, order)
+		}
+		params.Order = order
+	}
+
+	if values.Has(LimitKey) {
+		if params.Limit, err = parseLimitParam(values, 0, MaxActorsLimit); err != nil {
+			return params, err
+		}
+	}
+
+	if params.Offset, err = parseUintParam(values, OffsetKey, 0); err != nil {
+		return params, err
+	}
+
+	if params.WithFilms, err = parseBoolParam(values, WithFilmsKey, true); err != nil {
+		return params, err
+	}
+
+	return params, nil
+}
+
 // CreateActor
 //
 //	@Summary		Добавление актёра.
-//	@Description	Добавляет актёра включая его имя, пол и дату рождения.
+//	@Description	Добавляет актёра включая его имя, пол, дату рождения, а также необязательные дату смерти, место и страну рождения, биографию и альтернативные имена. Дата смерти должна быть позже даты рождения.
 //	@Tags			actor
 //	@Accept			json
 //	@Param			request	body	request.CreateActor	true	"Информация о добавляемом актёре"
@@ -40,12 +161,6 @@ func NewActorHandlers(repository actor.Repository) *ActorHandlers {
 func (ah *ActorHandlers) CreateActor(w http.ResponseWriter, r *http.Request, _ mux.Params) {
 	l := middleware.GetLogger(r)
 
-	// Проверка доступа
-	if usr := middleware.GetUser(r); usr == nil || usr.Role != types.ADMIN {
-		operate.SendError(w, ErrorUserNotPermitted, http.StatusForbidden, l)
-		return
-	}
-
 	// Получение значения тела запроса
 	var createActor request.CreateActor
 	if code, err := parseRequestBody(r.Body, &createActor, request.ValidateCreateActor, l); err != nil {
@@ -53,23 +168,32 @@ func (ah *ActorHandlers) CreateActor(w http.ResponseWriter, r *http.Request, _ m
 		return
 	}
 
+	if !isDeathDateAfterBirthday(&createActor.Birthday, createActor.DeathDate) {
+		operate.SendError(w, ErrorDeathDateBeforeBirthday, http.StatusBadRequest, l)
+		return
+	}
+
 	createdActor, err := ah.repository.CreateActor(&actor.Actor{
-		Name:     createActor.Name,
-		Sex:      types.Sexes(createActor.Sex),
-		Birthday: createActor.Birthday,
+		Name:       createActor.Name,
+		Sex:        types.Sexes(createActor.Sex),
+		Birthday:   createActor.Birthday,
+		DeathDate:  createActor.DeathDate,
+		BirthPlace: createActor.BirthPlace,
+		Country:    createActor.Country,
+		Biography:  createActor.Biography,
+		Aliases:    createActor.Aliases,
 	})
 	if err != nil {
+		if errors.Is(err, actor.ErrorDeathDateBeforeBirthday) {
+			operate.SendError(w, ErrorDeathDateBeforeBirthday, http.StatusBadRequest, l)
+			return
+		}
 		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
 		l.Error(errors.Wrapf(err, "can't create actor"))
 		return
 	}
 
-	operate.SendStatus(w, http.StatusCreated, &response.Actor{
-		ID:       createdActor.ID,
-		Name:     createdActor.Name,
-		Sex:      string(createdActor.Sex),
-		Birthday: createdActor.Birthday,
-	}, l)
+	operate.SendStatus(w, http.StatusCreated, response.FromRepositoryActor(createdActor), l)
 }
 
 // DeleteActor
@@ -90,12 +214,6 @@ func (ah *ActorHandlers) CreateActor(w http.ResponseWriter, r *http.Request, _ m
 func (ah *ActorHandlers) DeleteActor(w http.ResponseWriter, r *http.Request, params mux.Params) {
 	l := middleware.GetLogger(r)
 
-	// Проверка доступа
-	if usr := middleware.GetUser(r); usr == nil || usr.Role != types.ADMIN {
-		operate.SendError(w, ErrorUserNotPermitted, http.StatusForbidden, l)
-		return
-	}
-
 	// Получение уникального идентификатора
 	id, err := params.GetUint64(ActorIdField)
 	if err != nil {
@@ -119,10 +237,22 @@ func (ah *ActorHandlers) DeleteActor(w http.ResponseWriter, r *http.Request, par
 // GetActors
 //
 //	@Summary		Получение списка актёров.
-//	@Description	Формирует список всех актёров в системы.
+//	@Description	Формирует список актёров системы с возможностью поиска по фрагменту имени, фильтрации, сортировки и постраничного вывода. По умолчанию список отсортирован по имени по возрастанию. Фильмы актёров отбираются с учётом ограничения возрастного рейтинга пользователя.
 //	@Tags			actor
+//	@Param			search_string	query	string	false	"Фрагмент имени или альтернативного имени актёра"
+//	@Param			sex				query	string	false	"Пол актёра"																										Enums(male, female)
+//	@Param			birthday_from	query	string	false	"Актёры, родившиеся не раньше указанной даты"																		format(date)
+//	@Param			birthday_to		query	string	false	"Актёры, родившиеся не позже указанной даты"																		format(date)
+//	@Param			age_from		query	int		false	"Минимальный возраст актёра в полных годах"																			minimum(0)
+//	@Param			age_to			query	int		false	"Максимальный возраст актёра в полных годах"																		minimum(0)
+//	@Param			sort_by			query	string	false	"Параметр сортировки. Возможна сортировка по имени 'name', дате рождения 'birthday' и числу фильмов 'films_count'."	Enums(name, birthday, films_count)	default(name)
+//	@Param			sort_order		query	string	false	"Порядок сортировки. Возможна сортировка по возрастанию 'asc' или по убыванию 'desc'."								Enums(DESC, ASC)					default(ASC)
+//	@Param			limit			query	int		false	"Максимальное количество актёров в ответе. Если не указано, возвращаются все актёры."								minimum(1)							maximum(1000)
+//	@Param			offset			query	int		false	"Количество пропускаемых актёров"																					minimum(0)							default(0)
+//	@Param			with_films		query	bool	false	"Включать ли в ответ список фильмов каждого актёра"																	default(true)
 //	@Produce		json
 //	@Success		200	{array}		response.ActorWithFilms	"Список актёров успешно сформирован"
+//	@Failure		400	{object}	operate.ModelError		"В параметрах запроса ошибка"
 //	@Failure		401	{object}	operate.ModelError		"Пользователь не авторизован"
 //	@Failure		500	{object}	operate.ModelError		"Ошибка сервера"
 //	@Router			/actor/list [get]
@@ -130,7 +260,18 @@ func (ah *ActorHandlers) DeleteActor(w http.ResponseWriter, r *http.Request, par
 func (ah *ActorHandlers) GetActors(w http.ResponseWriter, r *http.Request, _ mux.Params) {
 	l := middleware.GetLogger(r)
 
-	actors, err := ah.repository.GetActors()
+	// Формируем параметры получения
+	getParams, err := parseActorsParams(r.URL.Query())
+	if err != nil {
+		operate.SendError(w, ErrorIncorrectQueryParam, http.StatusBadRequest, l)
+		l.Warn(err)
+		return
+	}
+
+	// Фильмы скрываются согласно ограничению возрастного рейтинга пользователя
+	getParams.MaxCertification = getMaxCertification(r)
+
+	actors, err := ah.repository.GetActors(getParams)
 	if err != nil {
 		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
 		l.Error(errors.Wrapf(err, "can't get actors"))
@@ -143,7 +284,7 @@ func (ah *ActorHandlers) GetActors(w http.ResponseWriter, r *http.Request, _ mux
 // UpdateActor
 //
 //	@Summary		Обновление данных об актёре.
-//	@Description	Обновляет данные об актёре. Все переданные поля будут обновлены. Отсутствующие поля будут оставлены без изменений.
+//	@Description	Обновляет данные об актёре. Все переданные поля будут обновлены. Отсутствующие поля будут оставлены без изменений. Дата смерти должна быть позже даты рождения.
 //	@Tags			actor
 //	@Accept			json
 //	@Param			actor_id	path	uint64				true	"Уникальный идентификатор актёра"
@@ -160,12 +301,6 @@ func (ah *ActorHandlers) GetActors(w http.ResponseWriter, r *http.Request, _ mux
 func (ah *ActorHandlers) UpdateActor(w http.ResponseWriter, r *http.Request, params mux.Params) {
 	l := middleware.GetLogger(r)
 
-	// Проверка доступа
-	if usr := middleware.GetUser(r); usr == nil || usr.Role != types.ADMIN {
-		operate.SendError(w, ErrorUserNotPermitted, http.StatusForbidden, l)
-		return
-	}
-
 	// Получение уникального идентификатора
 	id, err := params.GetUint64(ActorIdField)
 	if err != nil {
@@ -180,11 +315,24 @@ func (ah *ActorHandlers) UpdateActor(w http.ResponseWriter, r *http.Request, par
 		return
 	}
 
+	// Если дата рождения не передана, проверка выполняется базой данных
+	if !isDeathDateAfterBirthday(updateActor.Birthday, updateActor.DeathDate) {
+		operate.SendError(w, ErrorDeathDateBeforeBirthday, http.StatusBadRequest, l)
+		return
+	}
+
 	updatedActor, err := ah.repository.UpdateActor(&actor.UpdateActor{
-		ID:       types.Id(id),
-		Name:     updateActor.Name,
-		Sex:      (*types.Sexes)(updateActor.Sex),
-		Birthday: updateActor.Birthday,
+		ID:         types.Id(id),
+		Name:       updateActor.Name,
+		Sex:        (*types.Sexes)(updateActor.Sex),
+		Birthday:   updateActor.Birthday,
+		DeathDate:  updateActor.DeathDate,
+		BirthPlace: updateActor.BirthPlace,
+		Country:    updateActor.Country,
+		Biography:  updateActor.Biography,
+		Aliases:    updateActor.Aliases,
+
+		MaxCertification: getMaxCertification(r),
 	})
 
 	if err != nil {
@@ -192,6 +340,10 @@ func (ah *ActorHandlers) UpdateActor(w http.ResponseWriter, r *http.Request, par
 			operate.SendError(w, ErrorActorNotFound, http.StatusNotFound, l)
 			return
 		}
+		if errors.Is(err, actor.ErrorDeathDateBeforeBirthday) {
+			operate.SendError(w, ErrorDeathDateBeforeBirthday, http.StatusBadRequest, l)
+			return
+		}
 		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
 		l.Error(errors.Wrapf(err, "can't update actor"))
 		return
@@ -10,6 +10,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	stdTime "time"
 	"vk_film/internal/delivery/http/v1/model/request"
 	"vk_film/internal/delivery/http/v1/model/response"
 	"vk_film/internal/delivery/middleware"
@@ -18,6 +19,7 @@ import (
 	"vk_film/internal/repository/actor"
 	mra "vk_film/internal/repository/actor/mocks"
 	"vk_film/internal/repository/film"
+	"vk_film/internal/repository/user"
 	"vk_film/pkg/mux"
 )
 
@@ -45,9 +47,15 @@ func (ahs *ActorHandlersSuite) TestGetActorsHandler(t provider.T) {
 	actors := []actor.ActorWithFilms{actr, actr, actr}
 	expectedActors := response.FromRepositoryActorsWithFilms(actors)
 
+	defaultParams := actor.Params{
+		OrderField: types.ActorNameField,
+		Order:      types.ASC,
+		WithFilms:  true,
+	}
+
 	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		ahs.mockActor.EXPECT().GetActors().Return(actors, nil).Times(1)
+		ahs.mockActor.EXPECT().GetActors(defaultParams).Return(actors, nil).Times(1)
 
 		t.NewStep("Init http")
 		req, err := initRequest(nil, nil)
@@ -65,9 +73,118 @@ func (ahs *ActorHandlersSuite) TestGetActorsHandler(t provider.T) {
 		t.Require().EqualValues(expectedActors, actrs)
 	})
 
+	t.WithNewStep("Correct all params set execute", func(t provider.StepCtx) {
+		t.NewStep("Init test data")
+		sex := types.FEMALE
+		birthdayFrom := time.MustParse("01.01.1990")
+		birthdayTo := time.MustParse("31.12.2000")
+
+		t.NewStep("Init mock")
+		ahs.mockActor.EXPECT().GetActors(actor.Params{
+			SearchString: "name",
+			Sex:          &sex,
+			BirthdayFrom: &birthdayFrom,
+			BirthdayTo:   &birthdayTo,
+			OrderField:   types.FilmsCountField,
+			Order:        types.DESC,
+			Limit:        10,
+			Offset:       20,
+			WithFilms:    false,
+		}).Return(actors, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		vals := req.URL.Query()
+		vals.Set(SearchStringKey, "name")
+		vals.Set(SexKey, string(sex))
+		vals.Set(BirthdayFromKey, birthdayFrom.String())
+		vals.Set(BirthdayToKey, birthdayTo.String())
+		vals.Set(OrderFieldKey, string(types.FilmsCountField))
+		vals.Set(OrderKey, string(types.DESC))
+		vals.Set(LimitKey, "10")
+		vals.Set(OffsetKey, "20")
+		vals.Set(WithFilmsKey, "false")
+		req.URL.RawQuery = vals.Encode()
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		ahs.handlers.GetActors(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
+	t.WithNewStep("Correct age range execute", func(t provider.StepCtx) {
+		t.NewStep("Init test data")
+		oldNow := now
+		now = func() stdTime.Time { return stdTime.Date(2024, 3, 10, 15, 0, 0, 0, stdTime.UTC) }
+		defer func() { now = oldNow }()
+
+		birthdayFrom := time.MustParse("11.03.1993")
+		birthdayTo := time.MustParse("10.03.2004")
+
+		t.NewStep("Init mock")
+		ahs.mockActor.EXPECT().GetActors(actor.Params{
+			BirthdayFrom: &birthdayFrom,
+			BirthdayTo:   &birthdayTo,
+			OrderField:   types.ActorNameField,
+			Order:        types.ASC,
+			WithFilms:    true,
+		}).Return(actors, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		vals := req.URL.Query()
+		vals.Set(AgeFromKey, "20")
+		vals.Set(AgeToKey, "30")
+		vals.Set(BirthdayFromKey, "01.01.1980")
+		req.URL.RawQuery = vals.Encode()
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		ahs.handlers.GetActors(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
+	for _, param := range [][2]string{
+		{SexKey, "other"},
+		{BirthdayFromKey, "1990-01-01"},
+		{BirthdayToKey, "1990-01-01"},
+		{AgeFromKey, "-1"},
+		{AgeToKey, "age"},
+		{OrderFieldKey, "rating"},
+		{OrderKey, "up"},
+		{LimitKey, "0"},
+		{OffsetKey, "-5"},
+		{WithFilmsKey, "maybe"},
+	} {
+		t.WithNewStep("Incorrect "+param[0]+" param in execution", func(t provider.StepCtx) {
+			t.NewStep("Init http")
+			req, err := initRequest(nil, nil)
+			t.Require().NoError(err)
+
+			vals := req.URL.Query()
+			vals.Set(param[0], param[1])
+			req.URL.RawQuery = vals.Encode()
+
+			recorder := httptest.NewRecorder()
+
+			t.NewStep("Check result")
+			ahs.handlers.GetActors(recorder, req, mux.Params{})
+
+			t.Require().Equal(http.StatusBadRequest, recorder.Code)
+		})
+	}
+
 	t.WithNewStep("Actor repository error in execution", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		ahs.mockActor.EXPECT().GetActors().Return(actors, testError).Times(1)
+		ahs.mockActor.EXPECT().GetActors(defaultParams).Return(actors, testError).Times(1)
 
 		t.NewStep("Init http")
 		req, err := initRequest(nil, nil)
@@ -160,6 +277,32 @@ func (ahs *ActorHandlersSuite) TestUpdateActorHandler(t provider.T) {
 		t.Require().EqualValues(*expectedActor, responseActor)
 	})
 
+	t.WithNewStep("Correct user max certification execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		maxCertification := types.AGE12
+		ahs.mockActor.EXPECT().UpdateActor(&actor.UpdateActor{
+			ID:       actr.ID,
+			Name:     updateActor.Name,
+			Sex:      (*types.Sexes)(updateActor.Sex),
+			Birthday: updateActor.Birthday,
+
+			MaxCertification: &maxCertification,
+		}).Return(actr, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(string(body)), map[types.ContextField]any{
+			middleware.UserField: &user.User{Role: types.ADMIN, Permissions: types.Permissions, MaxCertification: &maxCertification},
+		})
+		t.Require().NoError(err)
+		req.SetPathValue(ActorIdField, fmt.Sprintf("%d", actr.ID))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		ahs.handlers.UpdateActor(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
 	t.WithNewStep("Actor repository error in execution", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		ahs.mockActor.EXPECT().UpdateActor(&actor.UpdateActor{
@@ -202,9 +345,17 @@ func (ahs *ActorHandlersSuite) TestUpdateActorHandler(t provider.T) {
 		t.Require().Equal(http.StatusNotFound, recorder.Code)
 	})
 
-	t.WithNewStep("Body error in execution", func(t provider.StepCtx) {
+	t.WithNewStep("Death date before birthday in execution", func(t provider.StepCtx) {
+		t.NewStep("Init test data")
+		deathDate := time.MustParse("12.02.2102")
+		incorrectBody, err := json.Marshal(&request.UpdateActor{
+			Birthday:  &data,
+			DeathDate: &deathDate,
+		})
+		t.Require().NoError(err)
+
 		t.NewStep("Init http")
-		req, err := initRequest(errReader(1), map[types.ContextField]any{middleware.UserField: adminUser})
+		req, err := initRequest(strings.NewReader(string(incorrectBody)), map[types.ContextField]any{middleware.UserField: adminUser})
 		t.Require().NoError(err)
 		req.SetPathValue(ActorIdField, fmt.Sprintf("%d", actr.ID))
 		recorder := httptest.NewRecorder()
@@ -212,14 +363,27 @@ func (ahs *ActorHandlersSuite) TestUpdateActorHandler(t provider.T) {
 		t.NewStep("Check result")
 		ahs.handlers.UpdateActor(recorder, req, *mux.NewParams(req))
 
-		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
 	})
 
-	t.WithNewStep("No actor id in execution", func(t provider.StepCtx) {
-		t.NewStep("Init http")
-		req, err := initRequest(errReader(1), map[types.ContextField]any{middleware.UserField: adminUser})
+	t.WithNewStep("Actor repository death date error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init test data")
+		deathDate := time.MustParse("12.02.2102")
+		deathDateBody, err := json.Marshal(&request.UpdateActor{
+			DeathDate: &deathDate,
+		})
 		t.Require().NoError(err)
 
+		t.NewStep("Init mock")
+		ahs.mockActor.EXPECT().UpdateActor(&actor.UpdateActor{
+			ID:        actr.ID,
+			DeathDate: &deathDate,
+		}).Return(nil, actor.ErrorDeathDateBeforeBirthday).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(string(deathDateBody)), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(ActorIdField, fmt.Sprintf("%d", actr.ID))
 		recorder := httptest.NewRecorder()
 
 		t.NewStep("Check result")
@@ -228,9 +392,22 @@ func (ahs *ActorHandlersSuite) TestUpdateActorHandler(t provider.T) {
 		t.Require().Equal(http.StatusBadRequest, recorder.Code)
 	})
 
-	t.WithNewStep("No user permissions in execution", func(t provider.StepCtx) {
+	t.WithNewStep("Body error in execution", func(t provider.StepCtx) {
 		t.NewStep("Init http")
-		req, err := initRequest(errReader(1), map[types.ContextField]any{middleware.UserField: userUser})
+		req, err := initRequest(errReader(1), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(ActorIdField, fmt.Sprintf("%d", actr.ID))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		ahs.handlers.UpdateActor(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.WithNewStep("No actor id in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(errReader(1), map[types.ContextField]any{middleware.UserField: adminUser})
 		t.Require().NoError(err)
 
 		recorder := httptest.NewRecorder()
@@ -238,7 +415,7 @@ func (ahs *ActorHandlersSuite) TestUpdateActorHandler(t provider.T) {
 		t.NewStep("Check result")
 		ahs.handlers.UpdateActor(recorder, req, *mux.NewParams(req))
 
-		t.Require().Equal(http.StatusForbidden, recorder.Code)
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
 	})
 }
 
@@ -307,18 +484,6 @@ func (ahs *ActorHandlersSuite) TestDeleteUserHandler(t provider.T) {
 
 		t.Require().Equal(http.StatusBadRequest, recorder.Code)
 	})
-
-	t.WithNewStep("No user permissions in in execution", func(t provider.StepCtx) {
-		t.NewStep("Init http")
-		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: userUser})
-		t.Require().NoError(err)
-		recorder := httptest.NewRecorder()
-
-		t.NewStep("Check result")
-		ahs.handlers.DeleteActor(recorder, req, *mux.NewParams(req))
-
-		t.Require().Equal(http.StatusForbidden, recorder.Code)
-	})
 }
 
 func (ahs *ActorHandlersSuite) TestCreateActorHandler(t provider.T) {
@@ -355,9 +520,85 @@ func (ahs *ActorHandlersSuite) TestCreateActorHandler(t provider.T) {
 		t.Require().EqualValues(*expectedActor, responseActor)
 	})
 
-	t.WithNewStep("Actor repository error in execution", func(t provider.StepCtx) {
+	t.WithNewStep("Correct profile execute", func(t provider.StepCtx) {
+		t.NewStep("Init test data")
+		birthday := time.MustParse("12.02.1950")
+		deathDate := time.MustParse("12.02.2020")
+		birthPlace := "Moscow"
+		country := "Russia"
+		biography := "Famous actor"
+		aliases := []string{"alias", "other alias"}
+
+		profileActor := &actor.Actor{
+			Name:       "actor",
+			Sex:        "female",
+			Birthday:   birthday,
+			DeathDate:  &deathDate,
+			BirthPlace: &birthPlace,
+			Country:    &country,
+			Biography:  &biography,
+			Aliases:    aliases,
+		}
+		profileBody, err := json.Marshal(&request.CreateActor{
+			Name:       "actor",
+			Sex:        "female",
+			Birthday:   birthday,
+			DeathDate:  &deathDate,
+			BirthPlace: &birthPlace,
+			Country:    &country,
+			Biography:  &biography,
+			Aliases:    aliases,
+		})
+		t.Require().NoError(err)
+
+		savedActor := *profileActor
+		savedActor.ID = 1
+
 		t.NewStep("Init mock")
-		ahs.mockActor.EXPECT().CreateActor(createdActor).Return(actr, testError).Times(1)
+		ahs.mockActor.EXPECT().CreateActor(profileActor).Return(&savedActor, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(string(profileBody)), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		ahs.handlers.CreateActor(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusCreated, recorder.Code)
+		var responseActor response.Actor
+		dec := json.NewDecoder(recorder.Body)
+		t.Require().NoError(dec.Decode(&responseActor))
+		t.Require().EqualValues(*response.FromRepositoryActor(&savedActor), responseActor)
+	})
+
+	t.WithNewStep("Death date before birthday in execution", func(t provider.StepCtx) {
+		t.NewStep("Init test data")
+		deathDate := time.MustParse("12.02.1940")
+		incorrectBody, err := json.Marshal(&request.CreateActor{
+			Name:      "actor",
+			Sex:       "female",
+			Birthday:  time.MustParse("12.02.1950"),
+			DeathDate: &deathDate,
+		})
+		t.Require().NoError(err)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(string(incorrectBody)), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		ahs.handlers.CreateActor(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("Actor repository death date error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ahs.mockActor.EXPECT().CreateActor(createdActor).Return(nil, actor.ErrorDeathDateBeforeBirthday).Times(1)
 
 		t.NewStep("Init http")
 		req, err := initRequest(strings.NewReader(string(body)), map[types.ContextField]any{middleware.UserField: adminUser})
@@ -368,13 +609,17 @@ func (ahs *ActorHandlersSuite) TestCreateActorHandler(t provider.T) {
 		t.NewStep("Check result")
 		ahs.handlers.CreateActor(recorder, req, *mux.NewParams(req))
 
-		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
 	})
 
-	t.WithNewStep("Body error in execution", func(t provider.StepCtx) {
+	t.WithNewStep("Actor repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ahs.mockActor.EXPECT().CreateActor(createdActor).Return(actr, testError).Times(1)
+
 		t.NewStep("Init http")
-		req, err := initRequest(errReader(1), map[types.ContextField]any{middleware.UserField: adminUser})
+		req, err := initRequest(strings.NewReader(string(body)), map[types.ContextField]any{middleware.UserField: adminUser})
 		t.Require().NoError(err)
+
 		recorder := httptest.NewRecorder()
 
 		t.NewStep("Check result")
@@ -383,17 +628,16 @@ func (ahs *ActorHandlersSuite) TestCreateActorHandler(t provider.T) {
 		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
 	})
 
-	t.WithNewStep("No user permissions in execution", func(t provider.StepCtx) {
+	t.WithNewStep("Body error in execution", func(t provider.StepCtx) {
 		t.NewStep("Init http")
-		req, err := initRequest(errReader(1), map[types.ContextField]any{middleware.UserField: userUser})
+		req, err := initRequest(errReader(1), map[types.ContextField]any{middleware.UserField: adminUser})
 		t.Require().NoError(err)
-
 		recorder := httptest.NewRecorder()
 
 		t.NewStep("Check result")
 		ahs.handlers.CreateActor(recorder, req, *mux.NewParams(req))
 
-		t.Require().Equal(http.StatusForbidden, recorder.Code)
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
 	})
 }
 
@@ -4,14 +4,48 @@ import "github.com/pkg/errors"
 
 var (
 	ErrorIncorrectLoginOrPassword = errors.New("incorrect login or password")
+	ErrorIncorrectPassword        = errors.New("incorrect current password")
+	ErrorUserNotAuthorized        = errors.New("user is not authorized")
+	ErrorTooManyLoginAttempts     = errors.New("too many login attempts, try again later")
+	ErrorInvalidRefreshToken      = errors.New("refresh token is invalid or expired")
 	ErrorCannotReadBody           = errors.New("can't read body")
 	ErrorIncorrectBodyContent     = errors.New("incorrect body content")
-	ErrorUserNotPermitted         = errors.New("the user with the current role does not have enough permissions")
 	ErrorUnknownError             = errors.New("unknown error, try again later")
 	ErrorIncorrectQueryParam      = errors.New("invalid query parameter")
+	ErrorDeathDateBeforeBirthday  = errors.New("death date must be after birthday")
+	ErrorTokenExpiresInPast       = errors.New("token expiration time must be in the future")
+	ErrorRoleGrantNotPermitted    = errors.New("the role grants permissions that the current user does not have")
+	ErrorUserManageNotPermitted   = errors.New("the user has permissions that the current user does not have")
+	ErrorRoleIsBuiltIn            = errors.New("built-in role can't be changed or deleted")
+	ErrorIdentityProvider         = errors.New("identity provider is unavailable")
+	ErrorInvalidOIDCState         = errors.New("authorization state is missing or invalid")
+	ErrorExternalAuthFailed       = errors.New("authorization in identity provider failed")
+	ErrorNoRoleForIdentity        = errors.New("identity has no role in the application")
+	ErrorIdentityLoginExists      = errors.New("login of identity is already used by another user")
+	ErrorInvalidLoginChallenge    = errors.New("login is not started or expired, log in again")
+	ErrorIncorrectTwoFactorCode   = errors.New("incorrect two-factor code")
+	ErrorTooManyCodeAttempts      = errors.New("too many incorrect codes, log in again")
+	ErrorTooManyResetRequests     = errors.New("too many password reset requests, try again later")
+	ErrorTwoFactorNotEnrolled     = errors.New("two-factor authentication is not enrolled")
+	ErrorTwoFactorAlreadyEnabled  = errors.New("two-factor authentication is already enabled")
+	ErrorTwoFactorRequired        = errors.New("two-factor authentication is required for the role of user")
+	ErrorAccountNotApproved       = errors.New("account is waiting for approval or was rejected")
+	ErrorRegistrationDisabled     = errors.New("registration is disabled")
+	ErrorRegistrationNotPending   = errors.New("user is not waiting for approval")
+	ErrorInvalidResetToken        = errors.New("password reset token is invalid, used or expired")
+	ErrorNoResetIdentifier        = errors.New("login or email must be specified")
+	ErrorLastAdmin                = errors.New("at least one active admin must remain")
+	ErrorConfirmationRequired     = errors.New("change of own account must be confirmed with confirm=true")
 
-	ErrorUserAlreadyExists = errors.New("user already exists")
-	ErrorActorNotFound     = errors.New("actor not found")
-	ErrorFilmNotFound      = errors.New("film not found")
-	ErrorUserNotFound      = errors.New("user not found")
+	ErrorUserAlreadyExists  = errors.New("user already exists")
+	ErrorEmailAlreadyExists = errors.New("email is already used by another user")
+	ErrorActorNotFound      = errors.New("actor not found")
+	ErrorFilmNotFound       = errors.New("film not found")
+	ErrorUserNotFound       = errors.New("user not found")
+	ErrorTokenAlreadyExists = errors.New("token with the same name already exists")
+	ErrorTokenNotFound      = errors.New("token not found")
+	ErrorSessionNotFound    = errors.New("session not found")
+	ErrorRoleNotFound       = errors.New("role not found")
+	ErrorRoleAlreadyExists  = errors.New("role with the same name already exists")
+	ErrorRoleInUse          = errors.New("role is assigned to users")
 )
@@ -3,6 +3,7 @@ package handlers
 import (
 	"github.com/pkg/errors"
 	"net/http"
+	"net/url"
 	"vk_film/internal/delivery/http/v1/model/request"
 	"vk_film/internal/delivery/http/v1/model/response"
 	"vk_film/internal/delivery/middleware"
@@ -13,10 +14,15 @@ import (
 )
 
 const (
-	OrderKey        = "sort_order"
-	OrderFieldKey   = "sort_by"
-	SearchStringKey = "search_string"
-	SearchFieldKey  = "search_by"
+	OrderKey         = "sort_order"
+	OrderFieldKey    = "sort_by"
+	SearchStringKey  = "search_string"
+	SearchFieldKey   = "search_by"
+	RuntimeFromKey   = "runtime_from"
+	RuntimeToKey     = "runtime_to"
+	CountryKey       = "country"
+	LanguageKey      = "language"
+	CertificationKey = "certification"
 
 	FilmIdField = "film_id"
 )
@@ -29,10 +35,94 @@ func NewFilmHandlers(repository film.Repository) *FilmHandlers {
 	return &FilmHandlers{repository: repository}
 }
 
+// parseRuntimeParam
+// Получает продолжительность фильма в минутах. Значение не должно превышать максимально допустимую продолжительность.
+func parseRuntimeParam(values url.Values, key string) (*uint16, error) {
+	value, err := parseOptionalUintParam(values, key)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	if *value > request.MaxRuntime {
+		return nil, errors.Wrapf(ErrorIncorrectQueryParam, "with field %s and value %d", key, *value)
+	}
+
+	runtime := uint16(*value)
+	return &runtime, nil
+}
+
+func parseFilmsParams(values url.Values) (film.Params, error) {
+	params := film.Params{
+		SearchString: "*",
+		SearchField:  types.FilmField,
+		OrderField:   types.RatingField,
+		Order:        types.DESC,
+	}
+
+	var err error
+
+	if values.Has(SearchStringKey) {
+		params.SearchString = values.Get(SearchStringKey)
+	}
+
+	if values.Has(SearchFieldKey) {
+		field := types.SearchField(values.Get(SearchFieldKey))
+		if field != types.FilmField && field != types.ActorField {
+			return params, errors.Wrapf(ErrorIncorrectQueryParam, "with field %s and value %s", SearchFieldKey, field)
+		}
+		params.SearchField = field
+	}
+
+	if values.Has(OrderKey) {
+		field := types.Order(values.Get(OrderKey))
+		if field != types.DESC && field != types.ASC {
+			return params, errors.Wrapf(ErrorIncorrectQueryParam, "with field %s and value %s", OrderKey, field)
+		}
+		params.Order = field
+	}
+
+	if values.Has(OrderFieldKey) {
+		field := types.OrderField(values.Get(OrderFieldKey))
+		if field != types.RatingField && field != types.NameField && field != types.DataPublishField {
+			return params, errors.Wrapf(ErrorIncorrectQueryParam, "with field %s and value %s", OrderFieldKey, field)
+		}
+		params.OrderField = field
+	}
+
+	if params.RuntimeFrom, err = parseRuntimeParam(values, RuntimeFromKey); err != nil {
+		return params, err
+	}
+
+	if params.RuntimeTo, err = parseRuntimeParam(values, RuntimeToKey); err != nil {
+		return params, err
+	}
+
+	if values.Has(CountryKey) {
+		country := values.Get(CountryKey)
+		params.Country = &country
+	}
+
+	if values.Has(LanguageKey) {
+		language := values.Get(LanguageKey)
+		params.Language = &language
+	}
+
+	// Допускается указание нескольких сертификатов, фильм подходит при совпадении с любым из них
+	for _, value := range values[CertificationKey] {
+		certification := types.Certification(value)
+		if !certification.IsValid() {
+			return params, errors.Wrapf(ErrorIncorrectQueryParam, "with field %s and value %s", CertificationKey, value)
+		}
+		params.Certifications = append(params.Certifications, certification)
+	}
+
+	return params, nil
+}
+
 // CreateFilm
 //
 //	@Summary		Добавление фильма.
-//	@Description	Добавляет фильм включая его название, описание, рейтинг, дату публикации и список игравших в нём актёров.
+//	@Description	Добавляет фильм включая его название, описание, рейтинг, дату публикации и список игравших в нём актёров, а также необязательные продолжительность, страны производства, язык оригинала и возрастной рейтинг.
 //	@Tags			film
 //	@Accept			json
 //	@Param			request	body	request.CreateFilm	true	"Информация о добавляемом фильме"
@@ -48,12 +138,6 @@ func NewFilmHandlers(repository film.Repository) *FilmHandlers {
 func (fh *FilmHandlers) CreateFilm(w http.ResponseWriter, r *http.Request, _ mux.Params) {
 	l := middleware.GetLogger(r)
 
-	// Проверка доступа
-	if usr := middleware.GetUser(r); usr == nil || usr.Role != types.ADMIN {
-		operate.SendError(w, ErrorUserNotPermitted, http.StatusForbidden, l)
-		return
-	}
-
 	// Получение значения тела запроса
 	var createFilm request.CreateFilm
 	if code, err := parseRequestBody(r.Body, &createFilm, request.ValidateCreateFilm, l); err != nil {
@@ -66,6 +150,11 @@ func (fh *FilmHandlers) CreateFilm(w http.ResponseWriter, r *http.Request, _ mux
 		Description: createFilm.Description,
 		DataPublish: createFilm.DataPublish,
 		Rating:      createFilm.Rating,
+
+		Runtime:          createFilm.Runtime,
+		Countries:        createFilm.Countries,
+		OriginalLanguage: createFilm.OriginalLanguage,
+		AgeCertification: createFilm.AgeCertification,
 	}, createFilm.Actors)
 	if err != nil {
 		if errors.Is(err, film.ErrorActorNotFound) {
@@ -99,12 +188,6 @@ func (fh *FilmHandlers) CreateFilm(w http.ResponseWriter, r *http.Request, _ mux
 func (fh *FilmHandlers) DeleteFilm(w http.ResponseWriter, r *http.Request, params mux.Params) {
 	l := middleware.GetLogger(r)
 
-	// Проверка доступа
-	if usr := middleware.GetUser(r); usr == nil || usr.Role != types.ADMIN {
-		operate.SendError(w, ErrorUserNotPermitted, http.StatusForbidden, l)
-		return
-	}
-
 	// Получение уникального идентификатора
 	id, err := params.GetUint64(FilmIdField)
 	if err != nil {
@@ -128,12 +211,17 @@ func (fh *FilmHandlers) DeleteFilm(w http.ResponseWriter, r *http.Request, param
 // GetFilms
 //
 //	@Summary		Получение списка фильмов.
-//	@Description	Позволяет получить список фильмом отсортированный по определённому полю. А также можно делать поиска списка фильма по имени актёра или названии фильма. Если параметры "search_by" и "search_string" не указаны, поиск не производится.
+//	@Description	Позволяет получить список фильмом отсортированный по определённому полю. А также можно делать поиска списка фильма по имени актёра или названии фильма. Если параметры "search_by" и "search_string" не указаны, поиск не производится. Фильмы можно отфильтровать по продолжительности, стране производства, языку оригинала и возрастному рейтингу. Если для пользователя задано ограничение возрастного рейтинга, фильмы с более строгим или неизвестным рейтингом не возвращаются.
 //	@Tags			film
-//	@Param			sort_order		query	string	false	"Порядок сортировки. Возможна сортировка по возрастанию 'asc' или по убыванию 'desc'."											Enums(DESC, ASC)					default(DESC)
-//	@Param			sort_by			query	string	false	"Параметр сортировки. Возможна сортировка по рейтингу 'rating', имени 'name' и дате публикации 'publish_date'."																			Enums(rating, name, publish_date)	default(rating)
-//	@Param			search_by		query	string	false	"Параметр поиска. Возможен поиск по фрагменту имени актёра 'actor' или фрагменту названия фильма 'film'. Обязателен при указании параметра 'search_name'."	Enums(actor, film)
-//	@Param			search_string	query	string	false	"Фргамнет, по которому осуществляется поиск. Обязателен при указании параметра 'search_by'"
+//	@Param			sort_order		query	string		false	"Порядок сортировки. Возможна сортировка по возрастанию 'asc' или по убыванию 'desc'."																		Enums(DESC, ASC)					default(DESC)
+//	@Param			sort_by			query	string		false	"Параметр сортировки. Возможна сортировка по рейтингу 'rating', имени 'name' и дате публикации 'publish_date'."												Enums(rating, name, publish_date)	default(rating)
+//	@Param			search_by		query	string		false	"Параметр поиска. Возможен поиск по фрагменту имени актёра 'actor' или фрагменту названия фильма 'film'. Обязателен при указании параметра 'search_name'."	Enums(actor, film)
+//	@Param			search_string	query	string		false	"Фргамнет, по которому осуществляется поиск. Обязателен при указании параметра 'search_by'"
+//	@Param			runtime_from	query	int			false	"Минимальная продолжительность фильма в минутах"	minimum(0)	maximum(1000)
+//	@Param			runtime_to		query	int			false	"Максимальная продолжительность фильма в минутах"	minimum(0)	maximum(1000)
+//	@Param			country			query	string		false	"Страна производства фильма"
+//	@Param			language		query	string		false	"Код языка оригинала фильма по ISO 639-1"
+//	@Param			certification	query	[]string	false	"Возрастной рейтинг фильма. Можно указать несколько значений."	collectionFormat(multi)	Enums(0+, 6+, 12+, 16+, 18+, G, PG, PG-13, R, NC-17)
 //	@Produce		json
 //	@Success		200	{array}		response.Film		"Список фильмом успешно сформирован"
 //	@Failure		400	{object}	operate.ModelError	"В теле запросе ошибка"
@@ -145,51 +233,15 @@ func (fh *FilmHandlers) GetFilms(w http.ResponseWriter, r *http.Request, _ mux.P
 	l := middleware.GetLogger(r)
 
 	// Формируем параметры получения
-	getParams := film.Params{
-		SearchString: "*",
-		SearchField:  types.FilmField,
-		OrderField:   types.RatingField,
-		Order:        types.DESC,
-	}
-
-	values := r.URL.Query()
-
-	if values.Has(SearchStringKey) {
-		getParams.SearchString = values.Get(SearchStringKey)
-	}
-
-	if values.Has(SearchFieldKey) {
-		field := types.SearchField(values.Get(SearchFieldKey))
-		if field != types.FilmField && field != types.ActorField {
-			operate.SendError(w, ErrorIncorrectQueryParam, http.StatusBadRequest, l)
-			l.Warn(errors.Wrapf(ErrorIncorrectQueryParam, "with field %s and value %s", SearchFieldKey, field))
-			return
-		}
-
-		getParams.SearchField = field
+	getParams, err := parseFilmsParams(r.URL.Query())
+	if err != nil {
+		operate.SendError(w, ErrorIncorrectQueryParam, http.StatusBadRequest, l)
+		l.Warn(err)
+		return
 	}
 
-	if values.Has(OrderKey) {
-		field := types.Order(values.Get(OrderKey))
-		if field != types.DESC && field != types.ASC {
-			operate.SendError(w, ErrorIncorrectQueryParam, http.StatusBadRequest, l)
-			l.Warn(errors.Wrapf(ErrorIncorrectQueryParam, "with field %s and value %s", OrderKey, field))
-			return
-		}
-
-		getParams.Order = field
-	}
-
-	if values.Has(OrderFieldKey) {
-		field := types.OrderField(values.Get(OrderFieldKey))
-		if field != types.RatingField && field != types.NameField && field != types.DataPublishField {
-			operate.SendError(w, ErrorIncorrectQueryParam, http.StatusBadRequest, l)
-			l.Warn(errors.Wrapf(ErrorIncorrectQueryParam, "with field %s and value %s", OrderFieldKey, field))
-			return
-		}
-
-		getParams.OrderField = field
-	}
+	// Фильмы скрываются согласно ограничению возрастного рейтинга пользователя
+	getParams.MaxCertification = getMaxCertification(r)
 
 	films, err := fh.repository.GetFilms(getParams)
 	if err != nil {
@@ -223,12 +275,6 @@ func (fh *FilmHandlers) GetFilms(w http.ResponseWriter, r *http.Request, _ mux.P
 func (fh *FilmHandlers) UpdateFilm(w http.ResponseWriter, r *http.Request, params mux.Params) {
 	l := middleware.GetLogger(r)
 
-	// Проверка доступа
-	if usr := middleware.GetUser(r); usr == nil || usr.Role != types.ADMIN {
-		operate.SendError(w, ErrorUserNotPermitted, http.StatusForbidden, l)
-		return
-	}
-
 	// Получение уникального идентификатора
 	id, err := params.GetUint64(FilmIdField)
 	if err != nil {
@@ -244,11 +290,17 @@ func (fh *FilmHandlers) UpdateFilm(w http.ResponseWriter, r *http.Request, param
 	}
 
 	toUpdateFilm := &film.UpdateFilm{
-		ID:           types.Id(id),
-		Name:         updateFilm.Name,
-		Description:  updateFilm.Description,
-		DataPublish:  updateFilm.DataPublish,
-		Rating:       updateFilm.Rating,
+		ID:          types.Id(id),
+		Name:        updateFilm.Name,
+		Description: updateFilm.Description,
+		DataPublish: updateFilm.DataPublish,
+		Rating:      updateFilm.Rating,
+
+		Runtime:          updateFilm.Runtime,
+		Countries:        updateFilm.Countries,
+		OriginalLanguage: updateFilm.OriginalLanguage,
+		AgeCertification: updateFilm.AgeCertification,
+
 		UpdateActors: updateFilm.Actors != nil,
 		Actors:       nil,
 	}
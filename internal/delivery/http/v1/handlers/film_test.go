@@ -17,6 +17,7 @@ import (
 	"vk_film/internal/pkg/types"
 	"vk_film/internal/repository/film"
 	mrf "vk_film/internal/repository/film/mocks"
+	"vk_film/internal/repository/user"
 	"vk_film/pkg/mux"
 )
 
@@ -72,6 +73,31 @@ func (fhs *FilmHandlersSuite) TestGetFilmsHandler(t provider.T) {
 		t.Require().EqualValues(expectedFilms, flms)
 	})
 
+	t.WithNewStep("Correct user max certification execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		maxCertification := types.AGE12
+		fhs.mockFilm.EXPECT().GetFilms(film.Params{
+			SearchString:     "*",
+			SearchField:      types.FilmField,
+			OrderField:       types.RatingField,
+			Order:            types.DESC,
+			MaxCertification: &maxCertification,
+		}).Return(films, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{
+			middleware.UserField: &user.User{Role: types.USER, MaxCertification: &maxCertification},
+		})
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		fhs.handlers.GetFilms(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
 	t.WithNewStep("Correct all params set with rating, film, desc in execution", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		fhs.mockFilm.EXPECT().GetFilms(film.Params{
@@ -168,6 +194,68 @@ func (fhs *FilmHandlersSuite) TestGetFilmsHandler(t provider.T) {
 		t.Require().EqualValues(expectedFilms, flms)
 	})
 
+	t.WithNewStep("Correct metadata filters in execution", func(t provider.StepCtx) {
+		t.NewStep("Init test data")
+		runtimeFrom, runtimeTo := uint16(90), uint16(180)
+		country, language := "USA", "en"
+
+		t.NewStep("Init mock")
+		fhs.mockFilm.EXPECT().GetFilms(film.Params{
+			SearchString:   "*",
+			SearchField:    types.FilmField,
+			OrderField:     types.RatingField,
+			Order:          types.DESC,
+			RuntimeFrom:    &runtimeFrom,
+			RuntimeTo:      &runtimeTo,
+			Country:        &country,
+			Language:       &language,
+			Certifications: []types.Certification{types.AGE12, types.MPAAPG13},
+		}).Return(films, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		vals := req.URL.Query()
+		vals.Set(RuntimeFromKey, "90")
+		vals.Set(RuntimeToKey, "180")
+		vals.Set(CountryKey, country)
+		vals.Set(LanguageKey, language)
+		vals.Add(CertificationKey, string(types.AGE12))
+		vals.Add(CertificationKey, string(types.MPAAPG13))
+		req.URL.RawQuery = vals.Encode()
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		fhs.handlers.GetFilms(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
+	for _, param := range [][2]string{
+		{RuntimeFromKey, "-1"},
+		{RuntimeToKey, "1001"},
+		{CertificationKey, "21+"},
+	} {
+		t.WithNewStep("Incorrect "+param[0]+" param value in execution", func(t provider.StepCtx) {
+			t.NewStep("Init http")
+			req, err := initRequest(nil, nil)
+			t.Require().NoError(err)
+
+			vals := req.URL.Query()
+			vals.Set(param[0], param[1])
+			req.URL.RawQuery = vals.Encode()
+
+			recorder := httptest.NewRecorder()
+
+			t.NewStep("Check result")
+			fhs.handlers.GetFilms(recorder, req, mux.Params{})
+
+			t.Require().Equal(http.StatusBadRequest, recorder.Code)
+		})
+	}
+
 	t.WithNewStep("Incorrect search field param value in execution", func(t provider.StepCtx) {
 		t.NewStep("Init http")
 		req, err := initRequest(nil, nil)
@@ -428,19 +516,6 @@ func (fhs *FilmHandlersSuite) TestUpdateFilmHandler(t provider.T) {
 
 		t.Require().Equal(http.StatusBadRequest, recorder.Code)
 	})
-
-	t.WithNewStep("No user permissions in execution", func(t provider.StepCtx) {
-		t.NewStep("Init http")
-		req, err := initRequest(errReader(1), map[types.ContextField]any{middleware.UserField: userUser})
-		t.Require().NoError(err)
-
-		recorder := httptest.NewRecorder()
-
-		t.NewStep("Check result")
-		fhs.handlers.UpdateFilm(recorder, req, *mux.NewParams(req))
-
-		t.Require().Equal(http.StatusForbidden, recorder.Code)
-	})
 }
 
 func (fhs *FilmHandlersSuite) TestDeleteUserHandler(t provider.T) {
@@ -508,18 +583,6 @@ func (fhs *FilmHandlersSuite) TestDeleteUserHandler(t provider.T) {
 
 		t.Require().Equal(http.StatusBadRequest, recorder.Code)
 	})
-
-	t.WithNewStep("No user permissions in in execution", func(t provider.StepCtx) {
-		t.NewStep("Init http")
-		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: userUser})
-		t.Require().NoError(err)
-		recorder := httptest.NewRecorder()
-
-		t.NewStep("Check result")
-		fhs.handlers.DeleteFilm(recorder, req, *mux.NewParams(req))
-
-		t.Require().Equal(http.StatusForbidden, recorder.Code)
-	})
 }
 
 func (fhs *FilmHandlersSuite) TestCreateActorHandler(t provider.T) {
@@ -558,6 +621,29 @@ func (fhs *FilmHandlersSuite) TestCreateActorHandler(t provider.T) {
 		t.Require().EqualValues(*expectedFilm, responseFilm)
 	})
 
+	for _, field := range []string{
+		`"runtime": 0`,
+		`"countries": [""]`,
+		`"original_language": "eng"`,
+		`"age_certification": "21+"`,
+	} {
+		t.WithNewStep("Incorrect metadata "+field+" in execution", func(t provider.StepCtx) {
+			t.NewStep("Init test data")
+			incorrectBody := strings.TrimSuffix(string(body), "}") + ", " + field + "}"
+
+			t.NewStep("Init http")
+			req, err := initRequest(strings.NewReader(incorrectBody), map[types.ContextField]any{middleware.UserField: adminUser})
+			t.Require().NoError(err)
+
+			recorder := httptest.NewRecorder()
+
+			t.NewStep("Check result")
+			fhs.handlers.CreateFilm(recorder, req, *mux.NewParams(req))
+
+			t.Require().Equal(http.StatusBadRequest, recorder.Code)
+		})
+	}
+
 	t.WithNewStep("Film repository error in execution", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		fhs.mockFilm.EXPECT().CreateFilm(createdFilm, actors).Return(flm, testError).Times(1)
@@ -601,19 +687,6 @@ func (fhs *FilmHandlersSuite) TestCreateActorHandler(t provider.T) {
 
 		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
 	})
-
-	t.WithNewStep("No user permissions in execution", func(t provider.StepCtx) {
-		t.NewStep("Init http")
-		req, err := initRequest(errReader(1), map[types.ContextField]any{middleware.UserField: userUser})
-		t.Require().NoError(err)
-
-		recorder := httptest.NewRecorder()
-
-		t.NewStep("Check result")
-		fhs.handlers.CreateFilm(recorder, req, *mux.NewParams(req))
-
-		t.Require().Equal(http.StatusForbidden, recorder.Code)
-	})
 }
 
 func TestRunFilmHandlersSuite(t *testing.T) {
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"github.com/pkg/errors"
+	"net/http"
+	"vk_film/internal/delivery/http/v1/model/request"
+	"vk_film/internal/delivery/middleware"
+	"vk_film/internal/usecase/auth"
+	"vk_film/internal/usecase/recovery"
+	"vk_film/pkg/mux"
+	"vk_film/pkg/operate"
+)
+
+type RecoveryHandlers struct {
+	usecase recovery.Usecase
+}
+
+func NewRecoveryHandlers(usecase recovery.Usecase) *RecoveryHandlers {
+	return &RecoveryHandlers{usecase: usecase}
+}
+
+// RequestPasswordReset
+//
+//	@Summary		Запрос сброса пароля.
+//	@Description	Отправляет одноразовый код сброса пароля на почту пользователя с указанной почтой или, если почта не указана, с указанным логином. Ответ не зависит от того, существует ли пользователь, поэтому письмо может и не прийти. Письмо отправляется после ответа. Число запросов для одного пользователя и с одного адреса ограничено, письма сверх ограничения для пользователя не отправляются.
+//	@Tags			user
+//	@Accept			json
+//	@Param			request	body	request.RequestPasswordReset	true	"Логин или почта пользователя"
+//	@Produce		json
+//	@Success		202	"Запрос принят"
+//	@Failure		400	{object}	operate.ModelError	"В теле запроса ошибка"
+//	@Failure		429	{object}	operate.ModelError	"Слишком много запросов сброса с адреса клиента"
+//	@Router			/password/reset [post]
+func (rh *RecoveryHandlers) RequestPasswordReset(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	// Получение значения тела запроса
+	var resetRequest request.RequestPasswordReset
+	if code, err := parseRequestBody(r.Body, &resetRequest, request.ValidateRequestPasswordReset, l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	if resetRequest.Login == "" && resetRequest.Email == "" {
+		operate.SendError(w, ErrorNoResetIdentifier, http.StatusBadRequest, l)
+		return
+	}
+
+	// Остальные ошибки не передаются клиенту, чтобы ответ не зависел от пользователя
+	client := clientInfo(r)
+	if err := rh.usecase.RequestReset(r.Context(), resetRequest.Login, resetRequest.Email, client.IP); err != nil {
+		if errors.Is(err, recovery.ErrorTooManyRequests) {
+			operate.SendError(w, ErrorTooManyResetRequests, http.StatusTooManyRequests, l)
+			l.Warn("[Security] too many password reset requests from %s", client.IP)
+			return
+		}
+		l.Error(errors.Wrapf(err, "can't request password reset"))
+	}
+
+	l.Info("[Security] password reset requested from %s", client.IP)
+	operate.SendStatus(w, http.StatusAccepted, nil, l)
+}
+
+// ConfirmPasswordReset
+//
+//	@Summary		Подтверждение сброса пароля.
+//	@Description	Устанавливает новый пароль по одноразовому коду из письма. Новый пароль должен соответствовать политике паролей. Код можно использовать только один раз, все сессии пользователя завершаются.
+//	@Tags			user
+//	@Accept			json
+//	@Param			request	body	request.ConfirmPasswordReset	true	"Код из письма и новый пароль"
+//	@Produce		json
+//	@Success		200	"Пароль успешно изменён"
+//	@Failure		400	{object}	operate.ModelError	"В теле запроса ошибка, код недействителен или пароль не соответствует политике"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/password/reset/confirm [post]
+func (rh *RecoveryHandlers) ConfirmPasswordReset(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	// Получение значения тела запроса
+	var confirm request.ConfirmPasswordReset
+	if code, err := parseRequestBody(r.Body, &confirm, request.ValidateConfirmPasswordReset, l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	userId, err := rh.usecase.ConfirmReset(confirm.Token, confirm.Password, clientInfo(r))
+	if err != nil {
+		if errors.Is(err, recovery.ErrorInvalidToken) {
+			operate.SendError(w, ErrorInvalidResetToken, http.StatusBadRequest, l)
+			l.Warn("[Security] invalid password reset token from %s", clientInfo(r).IP)
+			return
+		}
+		if errors.Is(err, auth.ErrorPasswordPolicy) {
+			operate.SendError(w, err, http.StatusBadRequest, l)
+			return
+		}
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't confirm password reset"))
+		return
+	}
+
+	l.Warn("[Security] password of user %d is reset by token from %s", userId, clientInfo(r).IP)
+	operate.SendStatus(w, http.StatusOK, nil, l)
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/usecase/auth"
+	"vk_film/internal/usecase/recovery"
+	mrc "vk_film/internal/usecase/recovery/mocks"
+	"vk_film/pkg/mux"
+)
+
+type RecoveryHandlersSuite struct {
+	suite.Suite
+	handlers     *RecoveryHandlers
+	mockRecovery *mrc.RecoveryUsecase
+	gmc          *gomock.Controller
+}
+
+func (rhs *RecoveryHandlersSuite) BeforeEach(t provider.T) {
+	rhs.gmc = gomock.NewController(t)
+	rhs.mockRecovery = mrc.NewRecoveryUsecase(rhs.gmc)
+	rhs.handlers = NewRecoveryHandlers(rhs.mockRecovery)
+}
+
+func (rhs *RecoveryHandlersSuite) AfterEach(t provider.T) {
+	rhs.gmc.Finish()
+}
+
+func (rhs *RecoveryHandlersSuite) TestRequestPasswordResetHandler(t provider.T) {
+	t.Title("RequestPasswordReset handler of recovery handlers")
+
+	for _, requestCase := range []struct {
+		name  string
+		body  string
+		login string
+		email string
+		err   error
+	}{
+		{"Correct by login", `{"login": "user"}`, "user", "", nil},
+		{"Correct by email", `{"email": "user@example.com"}`, "", "user@example.com", nil},
+		{"Usecase error", `{"login": "user"}`, "user", "", testError},
+	} {
+		t.WithNewStep(requestCase.name+" execute", func(t provider.StepCtx) {
+			t.NewStep("Init mock")
+			rhs.mockRecovery.EXPECT().RequestReset(gomock.Any(), requestCase.login, requestCase.email, gomock.Any()).
+				Return(requestCase.err).Times(1)
+
+			t.NewStep("Init http")
+			req, err := initRequest(strings.NewReader(requestCase.body), nil)
+			t.Require().NoError(err)
+			recorder := httptest.NewRecorder()
+
+			t.NewStep("Check result")
+			rhs.handlers.RequestPasswordReset(recorder, req, mux.Params{})
+
+			// Ошибка не должна раскрываться клиенту
+			t.Require().Equal(http.StatusAccepted, recorder.Code)
+		})
+	}
+
+	t.WithNewStep("Too many requests execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRecovery.EXPECT().RequestReset(gomock.Any(), "user", "", gomock.Any()).
+			Return(errors.Wrap(recovery.ErrorTooManyRequests, "client 127.0.0.1")).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(`{"login": "user"}`), nil)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.RequestPasswordReset(recorder, req, mux.Params{})
+		t.Require().Equal(http.StatusTooManyRequests, recorder.Code)
+	})
+
+	for _, incorrectCase := range []struct {
+		name string
+		body string
+	}{
+		{"Without login and email", `{}`},
+		{"Incorrect email", `{"email": "user"}`},
+	} {
+		t.WithNewStep(incorrectCase.name+" execute", func(t provider.StepCtx) {
+			t.NewStep("Init http")
+			req, err := initRequest(strings.NewReader(incorrectCase.body), nil)
+			t.Require().NoError(err)
+			recorder := httptest.NewRecorder()
+
+			t.NewStep("Check result")
+			rhs.handlers.RequestPasswordReset(recorder, req, mux.Params{})
+			t.Require().Equal(http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
+
+func (rhs *RecoveryHandlersSuite) TestConfirmPasswordResetHandler(t provider.T) {
+	t.Title("ConfirmPasswordReset handler of recovery handlers")
+	t.NewStep("Init test data")
+	token := recovery.TokenPrefix + "secret"
+	password := "new password"
+	body := `{"token": "` + token + `", "password": "` + password + `"}`
+
+	for _, confirmCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"Correct", nil, http.StatusOK},
+		{"Invalid token", recovery.ErrorInvalidToken, http.StatusBadRequest},
+		{"Weak password", errors.Wrap(auth.ErrorPasswordPolicy, "too short"), http.StatusBadRequest},
+		{"Usecase error", testError, http.StatusInternalServerError},
+	} {
+		t.WithNewStep(confirmCase.name+" execute", func(t provider.StepCtx) {
+			t.NewStep("Init mock")
+			rhs.mockRecovery.EXPECT().ConfirmReset(token, password, auth.ClientInfo{}).Return(types.Id(1), confirmCase.err).Times(1)
+
+			t.NewStep("Init http")
+			req, err := initRequest(strings.NewReader(body), nil)
+			t.Require().NoError(err)
+			recorder := httptest.NewRecorder()
+
+			t.NewStep("Check result")
+			rhs.handlers.ConfirmPasswordReset(recorder, req, mux.Params{})
+			t.Require().Equal(confirmCase.code, recorder.Code)
+		})
+	}
+
+	t.WithNewStep("Incorrect body execute", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(`{"token": "`+token+`"}`), nil)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.ConfirmPasswordReset(recorder, req, mux.Params{})
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestRunRecoveryHandlersSuite(t *testing.T) {
+	suite.RunSuite(t, new(RecoveryHandlersSuite))
+}
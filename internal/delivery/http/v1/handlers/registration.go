@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"github.com/pkg/errors"
+	"net/http"
+	"strings"
+	"vk_film/internal/delivery/http/v1/model/request"
+	"vk_film/internal/delivery/http/v1/model/response"
+	"vk_film/internal/delivery/middleware"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/user"
+	"vk_film/internal/usecase/registration"
+	"vk_film/pkg/mux"
+	"vk_film/pkg/operate"
+	"vk_film/pkg/slices"
+)
+
+type RegistrationHandlers struct {
+	usecase registration.Usecase
+}
+
+func NewRegistrationHandlers(usecase registration.Usecase) *RegistrationHandlers {
+	return &RegistrationHandlers{usecase: usecase}
+}
+
+// Register
+//
+//	@Summary		Регистрация пользователя.
+//	@Description	Создаёт учётную запись с ролью по умолчанию, которая ожидает одобрения администратором. До одобрения вход невозможен. Логин и пароль должны соответствовать политике регистрации.
+//	@Tags			user
+//	@Accept			json
+//	@Param			request	body	request.Register	true	"Логин, пароль и необязательная почта нового пользователя"
+//	@Produce		json
+//	@Success		202	{object}	response.User		"Учётная запись создана и ожидает одобрения"
+//	@Failure		400	{object}	operate.ModelError	"В теле запроса ошибка или логин и пароль не соответствуют политике"
+//	@Failure		403	{object}	operate.ModelError	"Регистрация отключена"
+//	@Failure		409	{object}	operate.ModelError	"Пользователь с таким же логином или почтой уже существует"
+//	@Failure		418	{object}	operate.ModelError	"Пользователь уже авторизован"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/register [post]
+func (rh *RegistrationHandlers) Register(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	// Получение значения тела запроса
+	var register request.Register
+	if code, err := parseRequestBody(r.Body, &register, request.ValidateRegister, l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	created, err := rh.usecase.Register(register.Login, register.Password, strings.ToLower(register.Email))
+	if err != nil {
+		switch {
+		case errors.Is(err, registration.ErrorRegistrationDisabled):
+			operate.SendError(w, ErrorRegistrationDisabled, http.StatusForbidden, l)
+		case errors.Is(err, registration.ErrorLoginPolicy), errors.Is(err, registration.ErrorPasswordPolicy):
+			operate.SendError(w, err, http.StatusBadRequest, l)
+		case errors.Is(err, user.ErrorLoginAlreadyExists):
+			operate.SendError(w, ErrorUserAlreadyExists, http.StatusConflict, l)
+			l.Info(errors.Wrapf(err, "can't register user"))
+		case errors.Is(err, user.ErrorEmailAlreadyExists):
+			operate.SendError(w, ErrorEmailAlreadyExists, http.StatusConflict, l)
+			l.Info(errors.Wrapf(err, "can't register user"))
+		default:
+			operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+			l.Error(errors.Wrapf(err, "can't register user"))
+		}
+		return
+	}
+
+	l.Info("[Security] user %d registered from %s and waits for approval", created.ID, clientInfo(r).IP)
+	operate.SendStatus(w, http.StatusAccepted, response.FromRepositoryUser(created), l)
+}
+
+// GetRegistrations
+//
+//	@Summary		Очередь регистраций.
+//	@Description	Возвращает зарегистрированных пользователей, ожидающих одобрения, в порядке регистрации.
+//	@Tags			user
+//	@Produce		json
+//	@Success		200	{array}		response.User		"Очередь успешно получена"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		403	{object}	operate.ModelError	"У пользователя нет прав на управление пользователями"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/registrations [get]
+//	@Security		sessionCookie
+func (rh *RegistrationHandlers) GetRegistrations(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	users, err := rh.usecase.GetPending()
+	if err != nil {
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't get pending users"))
+		return
+	}
+
+	operate.SendStatus(w, http.StatusOK, slices.Map(users, func(usr user.User) response.User {
+		return response.FromRepositoryUser(&usr)
+	}), l)
+}
+
+// ApproveRegistration
+//
+//	@Summary		Одобрение регистрации.
+//	@Description	Активирует ожидающего одобрения пользователя, после чего он может войти в систему.
+//	@Tags			user
+//	@Param			user_id	path	uint64	true	"Уникальный идентификатор пользователя"
+//	@Produce		json
+//	@Success		200	{object}	response.User		"Регистрация одобрена"
+//	@Failure		400	{object}	operate.ModelError	"В параметрах запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		403	{object}	operate.ModelError	"У пользователя нет прав на управление пользователями"
+//	@Failure		404	{object}	operate.ModelError	"Пользователь с указанным id не найден"
+//	@Failure		409	{object}	operate.ModelError	"Пользователь не ожидает одобрения"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/registrations/{user_id}/approve [post]
+//	@Security		sessionCookie
+func (rh *RegistrationHandlers) ApproveRegistration(w http.ResponseWriter, r *http.Request, params mux.Params) {
+	rh.decide(w, r, params, rh.usecase.Approve, "approved")
+}
+
+// RejectRegistration
+//
+//	@Summary		Отклонение регистрации.
+//	@Description	Отклоняет регистрацию пользователя. Логин остаётся занятым, пока пользователь не будет удалён.
+//	@Tags			user
+//	@Param			user_id	path	uint64	true	"Уникальный идентификатор пользователя"
+//	@Produce		json
+//	@Success		200	{object}	response.User		"Регистрация отклонена"
+//	@Failure		400	{object}	operate.ModelError	"В параметрах запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		403	{object}	operate.ModelError	"У пользователя нет прав на управление пользователями"
+//	@Failure		404	{object}	operate.ModelError	"Пользователь с указанным id не найден"
+//	@Failure		409	{object}	operate.ModelError	"Пользователь не ожидает одобрения"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/registrations/{user_id}/reject [post]
+//	@Security		sessionCookie
+func (rh *RegistrationHandlers) RejectRegistration(w http.ResponseWriter, r *http.Request, params mux.Params) {
+	rh.decide(w, r, params, rh.usecase.Reject, "rejected")
+}
+
+// decide
+// Принимает решение по регистрации пользователя из параметров запроса
+func (rh *RegistrationHandlers) decide(w http.ResponseWriter, r *http.Request, params mux.Params,
+	decision func(userId types.Id) (*user.User, error), verdict string) {
+	l := middleware.GetLogger(r)
+
+	// Получение уникального идентификатора
+	id, err := params.GetUint64(UserIdField)
+	if err != nil {
+		operate.SendError(w, errors.Wrapf(err, "try get user id"), http.StatusBadRequest, l)
+		return
+	}
+
+	usr, err := decision(types.Id(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrorUserNotFound):
+			operate.SendError(w, ErrorUserNotFound, http.StatusNotFound, l)
+		case errors.Is(err, user.ErrorStatusMismatch):
+			operate.SendError(w, ErrorRegistrationNotPending, http.StatusConflict, l)
+		default:
+			operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+			l.Error(errors.Wrapf(err, "can't decide on registration of user %d", id))
+		}
+		return
+	}
+
+	l.Warn("[Security] registration of user %d is %s by user %d", usr.ID, verdict, middleware.GetUser(r).ID)
+	operate.SendStatus(w, http.StatusOK, response.FromRepositoryUser(usr), l)
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"vk_film/internal/delivery/http/v1/model/response"
+	"vk_film/internal/delivery/middleware"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/user"
+	"vk_film/internal/usecase/registration"
+	mrg "vk_film/internal/usecase/registration/mocks"
+	"vk_film/pkg/mux"
+)
+
+type RegistrationHandlersSuite struct {
+	suite.Suite
+	handlers         *RegistrationHandlers
+	mockRegistration *mrg.RegistrationUsecase
+	gmc              *gomock.Controller
+}
+
+func (rhs *RegistrationHandlersSuite) BeforeEach(t provider.T) {
+	rhs.gmc = gomock.NewController(t)
+	rhs.mockRegistration = mrg.NewRegistrationUsecase(rhs.gmc)
+	rhs.handlers = NewRegistrationHandlers(rhs.mockRegistration)
+}
+
+func (rhs *RegistrationHandlersSuite) AfterEach(t provider.T) {
+	rhs.gmc.Finish()
+}
+
+func (rhs *RegistrationHandlersSuite) TestRegisterHandler(t provider.T) {
+	t.Title("Register handler of registration handlers")
+	t.NewStep("Init test data")
+	login := "new.user"
+	password := "Secret-password"
+	email := "new.user@example.com"
+	body := fmt.Sprintf(`{"login": "%s", "password": "%s", "email": "New.User@example.com"}`, login, password)
+	pending := &user.User{ID: 1, Login: login, Role: types.USER, Status: user.StatusPending}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRegistration.EXPECT().Register(login, password, email).Return(pending, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), nil)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.Register(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusAccepted, recorder.Code)
+		var res response.User
+		t.Require().NoError(json.NewDecoder(recorder.Body).Decode(&res))
+		t.Require().Equal(pending.ID, res.ID)
+		t.Require().Equal(string(user.StatusPending), res.Status)
+	})
+
+	t.WithNewStep("Incorrect body execute", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(`{"login": "new.user"}`), nil)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.Register(recorder, req, mux.Params{})
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	for _, errorCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"Registration disabled", registration.ErrorRegistrationDisabled, http.StatusForbidden},
+		{"Password policy violation", errors.Wrap(registration.ErrorPasswordPolicy, "too short"), http.StatusBadRequest},
+		{"Login policy violation", registration.ErrorLoginPolicy, http.StatusBadRequest},
+		{"Login already exists", user.ErrorLoginAlreadyExists, http.StatusConflict},
+		{"Email already exists", user.ErrorEmailAlreadyExists, http.StatusConflict},
+		{"Usecase error", testError, http.StatusInternalServerError},
+	} {
+		t.WithNewStep(errorCase.name+" execute", func(t provider.StepCtx) {
+			t.NewStep("Init mock")
+			rhs.mockRegistration.EXPECT().Register(login, password, email).Return(nil, errorCase.err).Times(1)
+
+			t.NewStep("Init http")
+			req, err := initRequest(strings.NewReader(body), nil)
+			t.Require().NoError(err)
+			recorder := httptest.NewRecorder()
+
+			t.NewStep("Check result")
+			rhs.handlers.Register(recorder, req, mux.Params{})
+			t.Require().Equal(errorCase.code, recorder.Code)
+		})
+	}
+}
+
+func (rhs *RegistrationHandlersSuite) TestGetRegistrationsHandler(t provider.T) {
+	t.Title("GetRegistrations handler of registration handlers")
+	t.NewStep("Init test data")
+	pending := []user.User{{ID: 1, Login: "new.user", Role: types.USER, Status: user.StatusPending}}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRegistration.EXPECT().GetPending().Return(pending, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.GetRegistrations(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var res []response.User
+		t.Require().NoError(json.NewDecoder(recorder.Body).Decode(&res))
+		t.Require().Len(res, 1)
+		t.Require().Equal(pending[0].Login, res[0].Login)
+		t.Require().Equal(string(user.StatusPending), res[0].Status)
+	})
+
+	t.WithNewStep("Usecase error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRegistration.EXPECT().GetPending().Return(nil, testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.GetRegistrations(recorder, req, mux.Params{})
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (rhs *RegistrationHandlersSuite) TestDecideRegistrationHandlers(t provider.T) {
+	t.Title("ApproveRegistration and RejectRegistration handlers of registration handlers")
+	t.NewStep("Init test data")
+	var userId types.Id = 1
+	approved := &user.User{ID: userId, Login: "new.user", Role: types.USER, Status: user.StatusActive}
+
+	newRequest := func(t provider.StepCtx, id string) *http.Request {
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, id)
+		return req
+	}
+
+	t.WithNewStep("Approve execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRegistration.EXPECT().Approve(userId).Return(approved, nil).Times(1)
+
+		t.NewStep("Check result")
+		recorder := httptest.NewRecorder()
+		req := newRequest(t, "1")
+		rhs.handlers.ApproveRegistration(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var res response.User
+		t.Require().NoError(json.NewDecoder(recorder.Body).Decode(&res))
+		t.Require().Equal(approved.ID, res.ID)
+		t.Require().Equal(string(user.StatusActive), res.Status)
+	})
+
+	t.WithNewStep("Reject not pending user execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRegistration.EXPECT().Reject(userId).Return(nil, user.ErrorStatusMismatch).Times(1)
+
+		t.NewStep("Check result")
+		recorder := httptest.NewRecorder()
+		req := newRequest(t, "1")
+		rhs.handlers.RejectRegistration(recorder, req, *mux.NewParams(req))
+		t.Require().Equal(http.StatusConflict, recorder.Code)
+	})
+
+	t.WithNewStep("Reject unknown user execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRegistration.EXPECT().Reject(userId).Return(nil, user.ErrorUserNotFound).Times(1)
+
+		t.NewStep("Check result")
+		recorder := httptest.NewRecorder()
+		req := newRequest(t, "1")
+		rhs.handlers.RejectRegistration(recorder, req, *mux.NewParams(req))
+		t.Require().Equal(http.StatusNotFound, recorder.Code)
+	})
+
+	t.WithNewStep("Incorrect user id execute", func(t provider.StepCtx) {
+		t.NewStep("Check result")
+		recorder := httptest.NewRecorder()
+		req := newRequest(t, "id")
+		rhs.handlers.ApproveRegistration(recorder, req, *mux.NewParams(req))
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("Usecase error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRegistration.EXPECT().Approve(userId).Return(nil, testError).Times(1)
+
+		t.NewStep("Check result")
+		recorder := httptest.NewRecorder()
+		req := newRequest(t, "1")
+		rhs.handlers.ApproveRegistration(recorder, req, *mux.NewParams(req))
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func TestRunRegistrationHandlersSuite(t *testing.T) {
+	suite.RunSuite(t, new(RegistrationHandlersSuite))
+}
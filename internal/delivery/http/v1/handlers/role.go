@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"github.com/pkg/errors"
+	"net/http"
+	"vk_film/internal/delivery/http/v1/model/request"
+	"vk_film/internal/delivery/http/v1/model/response"
+	"vk_film/internal/delivery/middleware"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/role"
+	"vk_film/internal/usecase/auth"
+	"vk_film/pkg/logger"
+	"vk_film/pkg/mux"
+	"vk_film/pkg/operate"
+	"vk_film/pkg/slices"
+)
+
+const (
+	RoleField = "role"
+)
+
+type RoleHandlers struct {
+	repository role.Repository
+	auth       auth.Manager
+}
+
+func NewRoleHandlers(repository role.Repository, auth auth.Manager) *RoleHandlers {
+	return &RoleHandlers{repository: repository, auth: auth}
+}
+
+// GetRoles
+//
+//	@Summary		Получение списка ролей.
+//	@Description	Возвращает все роли вместе с их описанием и правами.
+//	@Tags			role
+//	@Produce		json
+//	@Success		200	{array}		response.Role		"Роли успешно получены"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		403	{object}	operate.ModelError	"У пользователя нет прав на управление ролями"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/roles [get]
+//	@Security		sessionCookie
+func (rh *RoleHandlers) GetRoles(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	roles, err := rh.repository.GetRoles()
+	if err != nil {
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't get roles"))
+		return
+	}
+
+	operate.SendStatus(w, http.StatusOK, slices.Map(roles, func(rl role.Role) response.Role {
+		return response.FromRepositoryRole(&rl)
+	}), l)
+}
+
+// CreateRole
+//
+//	@Summary		Добавление роли.
+//	@Description	Добавляет роль как именованный набор прав. Название роли состоит из строчных латинских букв, цифр, '-' и '_' и начинается с буквы. Роль может содержать только права, которые есть у текущего пользователя.
+//	@Tags			role
+//	@Accept			json
+//	@Param			request	body	request.CreateRole	true	"Название, описание и права роли"
+//	@Produce		json
+//	@Success		201	{object}	response.Role		"Роль успешно добавлена"
+//	@Failure		400	{object}	operate.ModelError	"В теле запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		403	{object}	operate.ModelError	"У пользователя нет прав на управление ролями или на права роли"
+//	@Failure		409	{object}	operate.ModelError	"Роль с таким же названием уже существует"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/roles [post]
+//	@Security		sessionCookie
+func (rh *RoleHandlers) CreateRole(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	// Получение значения тела запроса
+	var createRole request.CreateRole
+	if code, err := parseRequestBody(r.Body, &createRole, request.ValidateCreateRole, l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	if err := checkRolePermissions(r, types.Roles(createRole.Name), createRole.Permissions, l); err != nil {
+		operate.SendError(w, err, http.StatusForbidden, l)
+		return
+	}
+
+	createdRole, err := rh.repository.CreateRole(&role.Role{
+		Name:        types.Roles(createRole.Name),
+		Description: createRole.Description,
+		Permissions: createRole.Permissions,
+	})
+	if err != nil {
+		if errors.Is(err, role.ErrorRoleAlreadyExists) {
+			operate.SendError(w, ErrorRoleAlreadyExists, http.StatusConflict, l)
+			return
+		}
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't create role"))
+		return
+	}
+
+	l.Warn("[Security] role %s with permissions %v is created by user %d",
+		createdRole.Name, createdRole.Permissions, middleware.GetUser(r).ID)
+	operate.SendStatus(w, http.StatusCreated, response.FromRepositoryRole(createdRole), l)
+}
+
+// UpdateRole
+//
+//	@Summary		Обновление роли.
+//	@Description	Заменяет описание и права роли. Изменения применяются ко всем пользователям с этой ролью. Встроенную роль 'admin' изменить нельзя, как и роль с правами, которых нет у текущего пользователя.
+//	@Tags			role
+//	@Accept			json
+//	@Param			role	path	string							true	"Название роли"
+//	@Param			request	body	request.UpdateRolePermissions	true	"Описание и права роли"
+//	@Produce		json
+//	@Success		200	{object}	response.Role		"Роль успешно обновлена"
+//	@Failure		400	{object}	operate.ModelError	"В теле запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		403	{object}	operate.ModelError	"У пользователя нет прав на управление ролями или на права роли"
+//	@Failure		404	{object}	operate.ModelError	"Роль с указанным названием не найдена"
+//	@Failure		409	{object}	operate.ModelError	"Встроенную роль нельзя изменить"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/roles/{role} [put]
+//	@Security		sessionCookie
+func (rh *RoleHandlers) UpdateRole(w http.ResponseWriter, r *http.Request, params mux.Params) {
+	l := middleware.GetLogger(r)
+
+	// Получение названия роли
+	name, err := params.GetString(RoleField)
+	if err != nil {
+		operate.SendError(w, errors.Wrapf(err, "try get role name"), http.StatusBadRequest, l)
+		return
+	}
+
+	// Права администратора не меняются, чтобы не потерять доступ к управлению ролями
+	if types.Roles(name) == types.ADMIN {
+		operate.SendError(w, ErrorRoleIsBuiltIn, http.StatusConflict, l)
+		return
+	}
+
+	// Получение значения тела запроса
+	var updateRole request.UpdateRolePermissions
+	if code, err := parseRequestBody(r.Body, &updateRole, request.ValidateUpdateRolePermissions, l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	// Нельзя менять роль с правами, которых нет у текущего пользователя, и добавлять такие права в роль
+	currentRole, err := rh.repository.GetRole(types.Roles(name))
+	if err != nil {
+		if errors.Is(err, role.ErrorRoleNotFound) {
+			operate.SendError(w, ErrorRoleNotFound, http.StatusNotFound, l)
+			return
+		}
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't get role %s", name))
+		return
+	}
+
+	if err := checkRolePermissions(r, currentRole.Name,
+		append(currentRole.Permissions, updateRole.Permissions...), l); err != nil {
+		operate.SendError(w, err, http.StatusForbidden, l)
+		return
+	}
+
+	updatedRole, err := rh.repository.UpdateRole(&role.Role{
+		Name:        types.Roles(name),
+		Description: updateRole.Description,
+		Permissions: updateRole.Permissions,
+	})
+	if err != nil {
+		if errors.Is(err, role.ErrorRoleNotFound) {
+			operate.SendError(w, ErrorRoleNotFound, http.StatusNotFound, l)
+			return
+		}
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't update role"))
+		return
+	}
+
+	// Права пользователей хранятся в кеше вместе с ролью, поэтому кеш сбрасывается целиком
+	rh.auth.InvalidateAll()
+
+	l.Warn("[Security] permissions of role %s are changed to %v by user %d",
+		updatedRole.Name, updatedRole.Permissions, middleware.GetUser(r).ID)
+	operate.SendStatus(w, http.StatusOK, response.FromRepositoryRole(updatedRole), l)
+}
+
+// checkRolePermissions
+// Проверяет, что в роли нет прав, которых нет у текущего пользователя
+func checkRolePermissions(r *http.Request, name types.Roles, permissions []types.Permission, l logger.Interface) error {
+	usr := middleware.GetUser(r)
+	for _, permission := range permissions {
+		if !usr.HasPermission(permission) {
+			l.Warn("[Security] user %d tried to manage role %s with permission %s", usr.ID, name, permission)
+			return ErrorRoleGrantNotPermitted
+		}
+	}
+
+	return nil
+}
+
+// DeleteRole
+//
+//	@Summary		Удаление роли.
+//	@Description	Удаляет роль, которая не назначена ни одному пользователю. Встроенные роли 'admin' и 'user' удалить нельзя.
+//	@Tags			role
+//	@Param			role	path	string	true	"Название роли"
+//	@Produce		json
+//	@Success		200	"Роль успешно удалена"
+//	@Failure		400	{object}	operate.ModelError	"В параметрах запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		403	{object}	operate.ModelError	"У пользователя нет прав на управление ролями"
+//	@Failure		404	{object}	operate.ModelError	"Роль с указанным названием не найдена"
+//	@Failure		409	{object}	operate.ModelError	"Роль встроенная или назначена пользователям"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/roles/{role} [delete]
+//	@Security		sessionCookie
+func (rh *RoleHandlers) DeleteRole(w http.ResponseWriter, r *http.Request, params mux.Params) {
+	l := middleware.GetLogger(r)
+
+	// Получение названия роли
+	name, err := params.GetString(RoleField)
+	if err != nil {
+		operate.SendError(w, errors.Wrapf(err, "try get role name"), http.StatusBadRequest, l)
+		return
+	}
+
+	if types.Roles(name) == types.ADMIN || types.Roles(name) == types.USER {
+		operate.SendError(w, ErrorRoleIsBuiltIn, http.StatusConflict, l)
+		return
+	}
+
+	if err := rh.repository.DeleteRole(types.Roles(name)); err != nil {
+		switch {
+		case errors.Is(err, role.ErrorRoleNotFound):
+			operate.SendError(w, ErrorRoleNotFound, http.StatusNotFound, l)
+		case errors.Is(err, role.ErrorRoleInUse):
+			operate.SendError(w, ErrorRoleInUse, http.StatusConflict, l)
+		default:
+			operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+			l.Error(errors.Wrapf(err, "can't delete role"))
+		}
+		return
+	}
+
+	rh.auth.InvalidateAll()
+
+	l.Warn("[Security] role %s is deleted by user %d", name, middleware.GetUser(r).ID)
+	operate.SendStatus(w, http.StatusOK, nil, l)
+}
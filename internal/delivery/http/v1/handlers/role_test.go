@@ -0,0 +1,387 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"vk_film/internal/delivery/http/v1/model/response"
+	"vk_film/internal/delivery/middleware"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/role"
+	mrr "vk_film/internal/repository/role/mocks"
+	"vk_film/internal/repository/user"
+	mua "vk_film/internal/usecase/auth/mocks"
+	"vk_film/pkg/mux"
+)
+
+type RoleHandlersSuite struct {
+	suite.Suite
+	handlers *RoleHandlers
+	mockRole *mrr.RoleRepository
+	mockAuth *mua.SessionManager
+	gmc      *gomock.Controller
+}
+
+func (rhs *RoleHandlersSuite) BeforeEach(t provider.T) {
+	rhs.gmc = gomock.NewController(t)
+	rhs.mockRole = mrr.NewRoleRepository(rhs.gmc)
+	rhs.mockAuth = mua.NewSessionManager(rhs.gmc)
+	rhs.handlers = NewRoleHandlers(rhs.mockRole, rhs.mockAuth)
+}
+
+func (rhs *RoleHandlersSuite) AfterEach(t provider.T) {
+	rhs.gmc.Finish()
+}
+
+// roleManagerUser
+// Пользователь с правом управления ролями без остальных прав
+var roleManagerUser = &user.User{
+	ID:          5,
+	Role:        "roles",
+	Permissions: []types.Permission{types.RoleManage},
+}
+
+func newTestRole() *role.Role {
+	return &role.Role{
+		Name:        "editor",
+		Description: "editor",
+		Permissions: []types.Permission{types.FilmWrite, types.ActorWrite},
+	}
+}
+
+func (rhs *RoleHandlersSuite) TestGetRolesHandler(t provider.T) {
+	t.Title("GetRoles handler of role handlers")
+	t.NewStep("Init test data")
+	roles := []role.Role{*newTestRole(), {Name: types.USER, Permissions: []types.Permission{}}}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRole.EXPECT().GetRoles().Return(roles, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.GetRoles(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var res []response.Role
+		t.Require().NoError(json.NewDecoder(recorder.Body).Decode(&res))
+		t.Require().EqualValues([]response.Role{
+			response.FromRepositoryRole(&roles[0]),
+			response.FromRepositoryRole(&roles[1]),
+		}, res)
+	})
+
+	t.WithNewStep("Role repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRole.EXPECT().GetRoles().Return(nil, testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.GetRoles(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (rhs *RoleHandlersSuite) TestCreateRoleHandler(t provider.T) {
+	t.Title("CreateRole handler of role handlers")
+	t.NewStep("Init test data")
+	rl := newTestRole()
+	body := `{"name": "editor", "description": "editor", "permissions": ["film:write", "actor:write"]}`
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRole.EXPECT().CreateRole(rl).Return(rl, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.CreateRole(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusCreated, recorder.Code)
+		var res response.Role
+		t.Require().NoError(json.NewDecoder(recorder.Body).Decode(&res))
+		t.Require().EqualValues(response.FromRepositoryRole(rl), res)
+	})
+
+	t.WithNewStep("Role already exists in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRole.EXPECT().CreateRole(rl).Return(nil, role.ErrorRoleAlreadyExists).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.CreateRole(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusConflict, recorder.Code)
+	})
+
+	t.WithNewStep("Role repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRole.EXPECT().CreateRole(rl).Return(nil, testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.CreateRole(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.WithNewStep("Permission of role not owned in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(
+			strings.NewReader(`{"name": "roles", "permissions": ["role:manage", "user:manage"]}`),
+			map[types.ContextField]any{middleware.UserField: roleManagerUser},
+		)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.CreateRole(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusForbidden, recorder.Code)
+	})
+
+	t.WithNewStep("Unknown permission in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(
+			strings.NewReader(`{"name": "editor", "permissions": ["film:read"]}`),
+			map[types.ContextField]any{middleware.UserField: adminUser},
+		)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.CreateRole(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("Incorrect role name in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(
+			strings.NewReader(`{"name": "Editor role", "permissions": []}`),
+			map[types.ContextField]any{middleware.UserField: adminUser},
+		)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.CreateRole(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func (rhs *RoleHandlersSuite) TestUpdateRoleHandler(t provider.T) {
+	t.Title("UpdateRole handler of role handlers")
+	t.NewStep("Init test data")
+	rl := newTestRole()
+	body := `{"description": "editor", "permissions": ["film:write", "actor:write"]}`
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRole.EXPECT().GetRole(rl.Name).Return(rl, nil).Times(1)
+		rhs.mockRole.EXPECT().UpdateRole(rl).Return(rl, nil).Times(1)
+		rhs.mockAuth.EXPECT().InvalidateAll().Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(RoleField, string(rl.Name))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.UpdateRole(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var res response.Role
+		t.Require().NoError(json.NewDecoder(recorder.Body).Decode(&res))
+		t.Require().EqualValues(response.FromRepositoryRole(rl), res)
+	})
+
+	t.WithNewStep("Role not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRole.EXPECT().GetRole(rl.Name).Return(nil, role.ErrorRoleNotFound).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(RoleField, string(rl.Name))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.UpdateRole(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusNotFound, recorder.Code)
+	})
+
+	t.WithNewStep("Role repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRole.EXPECT().GetRole(rl.Name).Return(rl, nil).Times(1)
+		rhs.mockRole.EXPECT().UpdateRole(rl).Return(nil, testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(RoleField, string(rl.Name))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.UpdateRole(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.WithNewStep("Get role error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRole.EXPECT().GetRole(rl.Name).Return(nil, testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(RoleField, string(rl.Name))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.UpdateRole(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.WithNewStep("Own role escalation in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRole.EXPECT().GetRole(roleManagerUser.Role).
+			Return(&role.Role{Name: roleManagerUser.Role, Permissions: roleManagerUser.Permissions}, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(`{"permissions": ["role:manage", "user:manage"]}`),
+			map[types.ContextField]any{middleware.UserField: roleManagerUser})
+		t.Require().NoError(err)
+		req.SetPathValue(RoleField, string(roleManagerUser.Role))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.UpdateRole(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusForbidden, recorder.Code)
+	})
+
+	t.WithNewStep("Role with permissions not owned in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRole.EXPECT().GetRole(rl.Name).Return(rl, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(`{"permissions": ["role:manage"]}`),
+			map[types.ContextField]any{middleware.UserField: roleManagerUser})
+		t.Require().NoError(err)
+		req.SetPathValue(RoleField, string(rl.Name))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.UpdateRole(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusForbidden, recorder.Code)
+	})
+
+	t.WithNewStep("Built-in role in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(RoleField, string(types.ADMIN))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.UpdateRole(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusConflict, recorder.Code)
+	})
+
+	t.WithNewStep("No role name in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.UpdateRole(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func (rhs *RoleHandlersSuite) TestDeleteRoleHandler(t provider.T) {
+	t.Title("DeleteRole handler of role handlers")
+	t.NewStep("Init test data")
+	name := types.Roles("editor")
+
+	checkDelete := func(t provider.StepCtx, roleName types.Roles, code int) {
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(RoleField, string(roleName))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.DeleteRole(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(code, recorder.Code)
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRole.EXPECT().DeleteRole(name).Return(nil).Times(1)
+		rhs.mockAuth.EXPECT().InvalidateAll().Times(1)
+		checkDelete(t, name, http.StatusOK)
+	})
+
+	t.WithNewStep("Role not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRole.EXPECT().DeleteRole(name).Return(role.ErrorRoleNotFound).Times(1)
+		checkDelete(t, name, http.StatusNotFound)
+	})
+
+	t.WithNewStep("Role in use in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRole.EXPECT().DeleteRole(name).Return(role.ErrorRoleInUse).Times(1)
+		checkDelete(t, name, http.StatusConflict)
+	})
+
+	t.WithNewStep("Role repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rhs.mockRole.EXPECT().DeleteRole(name).Return(testError).Times(1)
+		checkDelete(t, name, http.StatusInternalServerError)
+	})
+
+	t.WithNewStep("Built-in role in execution", func(t provider.StepCtx) {
+		checkDelete(t, types.ADMIN, http.StatusConflict)
+		checkDelete(t, types.USER, http.StatusConflict)
+	})
+}
+
+func TestRunRoleHandlersSuite(t *testing.T) {
+	suite.RunSuite(t, new(RoleHandlersSuite))
+}
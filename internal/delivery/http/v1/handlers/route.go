@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+	"vk_film/internal/delivery/http/v1/model/response"
+	"vk_film/internal/delivery/middleware"
+	"vk_film/pkg/mux"
+	"vk_film/pkg/operate"
+)
+
+type RouteHandlers struct {
+	routes []response.Route
+}
+
+func NewRouteHandlers() *RouteHandlers {
+	return &RouteHandlers{routes: []response.Route{}}
+}
+
+// SetRoutes
+// Задаёт таблицу маршрутов, которую возвращает GetRoutes. Вызывается после построения всех маршрутов.
+func (rh *RouteHandlers) SetRoutes(routes []response.Route) {
+	rh.routes = routes
+}
+
+// GetRoutes
+//
+//	@Summary		Получение таблицы маршрутов.
+//	@Description	Возвращает все маршруты API с требованиями к доступу: нужна ли авторизация, какие права должны быть у пользователя и какие роли допускаются.
+//	@Tags			role
+//	@Produce		json
+//	@Success		200	{array}		response.Route		"Таблица маршрутов успешно получена"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		403	{object}	operate.ModelError	"У пользователя нет прав на управление ролями"
+//	@Router			/routes [get]
+//	@Security		sessionCookie
+func (rh *RouteHandlers) GetRoutes(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	operate.SendStatus(w, http.StatusOK, rh.routes, middleware.GetLogger(r))
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"vk_film/internal/delivery/http/v1/model/response"
+	"vk_film/internal/delivery/middleware"
+	"vk_film/internal/pkg/types"
+	"vk_film/pkg/mux"
+)
+
+type RouteHandlersSuite struct {
+	suite.Suite
+	handlers *RouteHandlers
+}
+
+func (rhs *RouteHandlersSuite) BeforeEach(t provider.T) {
+	rhs.handlers = NewRouteHandlers()
+}
+
+func (rhs *RouteHandlersSuite) TestGetRoutesHandler(t provider.T) {
+	t.Title("GetRoutes handler of route handlers")
+	t.NewStep("Init test data")
+	routes := []response.Route{
+		{
+			Method:       http.MethodGet,
+			Path:         "/api/v1/film/list",
+			AuthRequired: true,
+			Permissions:  []string{},
+			Roles:        []string{},
+		},
+		{
+			Method:       http.MethodDelete,
+			Path:         "/api/v1/film/{film_id}",
+			AuthRequired: true,
+			Permissions:  []string{string(types.FilmDelete)},
+			Roles:        []string{},
+		},
+	}
+
+	t.WithNewStep("Empty routes execute", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.GetRoutes(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var res []response.Route
+		t.Require().NoError(json.NewDecoder(recorder.Body).Decode(&res))
+		t.Require().Empty(res)
+	})
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init routes")
+		rhs.handlers.SetRoutes(routes)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		rhs.handlers.GetRoutes(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var res []response.Route
+		t.Require().NoError(json.NewDecoder(recorder.Body).Decode(&res))
+		t.Require().EqualValues(routes, res)
+	})
+}
+
+func TestRunRouteHandlersSuite(t *testing.T) {
+	suite.RunSuite(t, new(RouteHandlersSuite))
+}
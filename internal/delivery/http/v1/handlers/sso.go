@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"github.com/pkg/errors"
+	"net/http"
+	"strings"
+	"time"
+	"vk_film/internal/delivery/http/v1/model/response"
+	"vk_film/internal/delivery/middleware"
+	"vk_film/internal/usecase/sso"
+	"vk_film/pkg/mux"
+	"vk_film/pkg/operate"
+)
+
+const (
+	OIDCCookie   = "oidc_auth"
+	OIDCCodeKey  = "code"
+	OIDCStateKey = "state"
+	OIDCErrorKey = "error"
+
+	oidcCookieTTL = 10 * time.Minute
+)
+
+type SSOHandlers struct {
+	usecase      sso.Usecase
+	cookie       *middleware.SessionCookie
+	postLoginURL string
+}
+
+// NewSSOHandlers
+// После успешного входа в режиме сессий пользователь перенаправляется на postLoginURL, если он задан
+func NewSSOHandlers(usecase sso.Usecase, cookie *middleware.SessionCookie, postLoginURL string) *SSOHandlers {
+	return &SSOHandlers{usecase: usecase, cookie: cookie, postLoginURL: postLoginURL}
+}
+
+// OIDCLogin
+//
+//	@Summary		Вход через внешний провайдер.
+//	@Description	Начинает вход через OpenID Connect провайдер с PKCE и перенаправляет пользователя на страницу авторизации провайдера. Параметры авторизации сохраняются в cookie до возврата пользователя.
+//	@Tags			user
+//	@Success		302	"Перенаправление на страницу авторизации провайдера"
+//	@Header			302	{string}	Location			"Адрес страницы авторизации провайдера"
+//	@Header			302	{string}	Set-Cookie			"Сохраняет параметры авторизации"
+//	@Failure		502	{object}	operate.ModelError	"Провайдер недоступен"
+//	@Router			/oidc/login [get]
+func (sh *SSOHandlers) OIDCLogin(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	authorization, err := sh.usecase.Begin(r.Context())
+	if err != nil {
+		operate.SendError(w, ErrorIdentityProvider, http.StatusBadGateway, l)
+		l.Error(errors.Wrapf(err, "can't begin oidc authorization"))
+		return
+	}
+
+	// Возврат от провайдера — это переход с другого сайта, поэтому cookie не может быть SameSite=Strict
+	http.SetCookie(w, &http.Cookie{
+		Name:     OIDCCookie,
+		Value:    strings.Join([]string{authorization.State, authorization.Nonce, authorization.Verifier}, "."),
+		Path:     "/",
+		MaxAge:   int(oidcCookieTTL.Seconds()),
+		Secure:   sh.cookie.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, authorization.URL, http.StatusFound)
+}
+
+// OIDCCallback
+//
+//	@Summary		Завершение входа через внешний провайдер.
+//	@Description	Принимает код авторизации от провайдера, проверяет id токен и выдаёт сессию. Если у пользователя подключена двухфакторная аутентификация, сессия выдаётся только после ввода кода в /login/2fa. При первом входе пользователь создаётся, его роль определяется заявкой провайдера и обновляется при каждом входе.
+//	@Tags			user
+//	@Param			code	query	string	true	"Код авторизации"
+//	@Param			state	query	string	true	"Состояние авторизации"
+//	@Produce		json
+//	@Success		200	{object}	response.Credentials	"Пользователь успешно авторизован, токены возвращаются только в режиме jwt"
+//	@Header			200	{string}	Set-Cookie				"Устанавливает сессию текущего пользователя"
+//	@Success		202	{object}	response.PendingLogin	"Личность подтверждена провайдером, для входа нужен код второго фактора"
+//	@Success		303	"Пользователь успешно авторизован и перенаправлен на адрес из настроек"
+//	@Failure		400	{object}	operate.ModelError	"Состояние авторизации не совпадает или отсутствует код"
+//	@Failure		401	{object}	operate.ModelError	"Провайдер отклонил авторизацию или id токен недействителен"
+//	@Failure		403	{object}	operate.ModelError	"Пользователю провайдера не соответствует ни одна роль"
+//	@Failure		409	{object}	operate.ModelError	"Логин пользователя провайдера уже занят"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/oidc/callback [get]
+func (sh *SSOHandlers) OIDCCallback(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	authorization, err := sh.popAuthorization(w, r)
+	if err != nil {
+		operate.SendError(w, ErrorInvalidOIDCState, http.StatusBadRequest, l)
+		l.Warn("[Security] oidc callback with invalid state: %s", err)
+		return
+	}
+
+	query := r.URL.Query()
+	if providerError := query.Get(OIDCErrorKey); providerError != "" {
+		operate.SendError(w, ErrorExternalAuthFailed, http.StatusUnauthorized, l)
+		l.Info("oidc provider returned error %s", providerError)
+		return
+	}
+
+	code := query.Get(OIDCCodeKey)
+	if code == "" {
+		operate.SendError(w, errors.Wrapf(ErrorIncorrectQueryParam, "field %s is empty", OIDCCodeKey),
+			http.StatusBadRequest, l)
+		return
+	}
+
+	client := clientInfo(r)
+	credentials, userId, err := sh.usecase.Complete(r.Context(), code, authorization, client)
+	if err != nil {
+		switch {
+		case errors.Is(err, sso.ErrorAuthorizationFailed), errors.Is(err, sso.ErrorNoLogin):
+			operate.SendError(w, ErrorExternalAuthFailed, http.StatusUnauthorized, l)
+			l.Warn("[Security] oidc login from %s failed: %s", client.IP, err)
+		case errors.Is(err, sso.ErrorNoRole):
+			operate.SendError(w, ErrorNoRoleForIdentity, http.StatusForbidden, l)
+			l.Warn("[Security] oidc login from %s denied: %s", client.IP, err)
+		case errors.Is(err, sso.ErrorLoginAlreadyExists):
+			operate.SendError(w, ErrorIdentityLoginExists, http.StatusConflict, l)
+			l.Warn("[Security] oidc login from %s conflicts with local user: %s", client.IP, err)
+		default:
+			operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+			l.Error(errors.Wrapf(err, "can't complete oidc authorization"))
+		}
+		return
+	}
+
+	// Ожидающий вход не является сессией, код второго фактора передаётся в /login/2fa
+	if credentials.Pending {
+		l.Info("[Security] user %d passed oidc from %s and waits for second factor", userId, client.IP)
+		operate.SendStatus(w, http.StatusAccepted, response.FromPendingCredentials(credentials), l)
+		return
+	}
+
+	l.Info("[Security] user %d logged in with oidc from %s", userId, client.IP)
+
+	// В режиме jwt токены возвращаются в теле ответа, поэтому перенаправление возможно только для сессий
+	if sh.postLoginURL != "" && credentials.RefreshToken == "" {
+		sh.cookie.Set(w, credentials.SessionId, credentials.ExpiresIn)
+		http.Redirect(w, r, sh.postLoginURL, http.StatusSeeOther)
+		return
+	}
+
+	sendCredentials(w, sh.cookie, credentials, l)
+}
+
+// popAuthorization
+// Достаёт параметры авторизации из cookie, удаляет её и сверяет состояние с переданным провайдером
+func (sh *SSOHandlers) popAuthorization(w http.ResponseWriter, r *http.Request) (*sso.Authorization, error) {
+	cookie, err := r.Cookie(OIDCCookie)
+	if err != nil {
+		return nil, errors.Wrap(err, "try get authorization cookie")
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     OIDCCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   sh.cookie.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	parts := strings.Split(cookie.Value, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed authorization cookie")
+	}
+
+	state := r.URL.Query().Get(OIDCStateKey)
+	if state == "" || subtle.ConstantTimeCompare([]byte(parts[0]), []byte(state)) != 1 {
+		return nil, errors.New("state mismatch")
+	}
+
+	return &sso.Authorization{State: parts[0], Nonce: parts[1], Verifier: parts[2]}, nil
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+	"vk_film/internal/delivery/middleware"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/usecase/auth"
+	"vk_film/internal/usecase/sso"
+	ms "vk_film/internal/usecase/sso/mocks"
+	"vk_film/pkg/mux"
+)
+
+type SSOHandlersSuite struct {
+	suite.Suite
+	handlers *SSOHandlers
+	mockSSO  *ms.SSOUsecase
+	gmc      *gomock.Controller
+}
+
+func (shs *SSOHandlersSuite) BeforeEach(t provider.T) {
+	shs.gmc = gomock.NewController(t)
+	shs.mockSSO = ms.NewSSOUsecase(shs.gmc)
+	shs.handlers = NewSSOHandlers(shs.mockSSO, &middleware.DefaultSessionCookie, "")
+}
+
+func (shs *SSOHandlersSuite) AfterEach(t provider.T) {
+	shs.gmc.Finish()
+}
+
+func findCookie(cookies []*http.Cookie, name string) *http.Cookie {
+	for _, cookie := range cookies {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func (shs *SSOHandlersSuite) TestOIDCLoginHandler(t provider.T) {
+	t.Title("OIDCLogin handler of sso handlers")
+	t.NewStep("Init test data")
+	authorization := &sso.Authorization{
+		URL:      "https://sso.example.com/authorize?state=state",
+		State:    "state",
+		Nonce:    "nonce",
+		Verifier: "verifier",
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		shs.mockSSO.EXPECT().Begin(gomock.Any()).Return(authorization, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.OIDCLogin(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusFound, recorder.Code)
+		t.Require().Equal(authorization.URL, recorder.Header().Get("Location"))
+		cookie := findCookie(recorder.Result().Cookies(), OIDCCookie)
+		t.Require().NotNil(cookie)
+		t.Require().Equal("state.nonce.verifier", cookie.Value)
+		t.Require().True(cookie.HttpOnly)
+		t.Require().Equal(http.SameSiteLaxMode, cookie.SameSite)
+	})
+
+	t.WithNewStep("Provider error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		shs.mockSSO.EXPECT().Begin(gomock.Any()).Return(nil, sso.ErrorAuthorizationFailed).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.OIDCLogin(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusBadGateway, recorder.Code)
+	})
+}
+
+func (shs *SSOHandlersSuite) TestOIDCCallbackHandler(t provider.T) {
+	t.Title("OIDCCallback handler of sso handlers")
+	t.NewStep("Init test data")
+	authorization := &sso.Authorization{State: "state", Nonce: "nonce", Verifier: "verifier"}
+	credentials := &auth.Credentials{SessionId: "session", ExpiresIn: time.Hour}
+	userId := types.Id(5)
+
+	newRequest := func(t provider.StepCtx, query url.Values, cookie string) *http.Request {
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+		req.URL.RawQuery = query.Encode()
+		if cookie != "" {
+			req.AddCookie(&http.Cookie{Name: OIDCCookie, Value: cookie})
+		}
+		return req
+	}
+	correctQuery := url.Values{OIDCCodeKey: {"code"}, OIDCStateKey: {"state"}}
+	correctCookie := "state.nonce.verifier"
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		shs.mockSSO.EXPECT().Complete(gomock.Any(), "code", authorization, gomock.Any()).
+			Return(credentials, userId, nil).Times(1)
+
+		t.NewStep("Init http")
+		req := newRequest(t, correctQuery, correctCookie)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.OIDCCallback(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		cookies := recorder.Result().Cookies()
+		t.Require().Equal(credentials.SessionId, findCookie(cookies, middleware.DefaultSessionCookieName).Value)
+		t.Require().Equal(-1, findCookie(cookies, OIDCCookie).MaxAge)
+	})
+
+	t.WithNewStep("Correct execute with redirect", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		handlers := NewSSOHandlers(shs.mockSSO, &middleware.DefaultSessionCookie, "/films")
+		shs.mockSSO.EXPECT().Complete(gomock.Any(), "code", authorization, gomock.Any()).
+			Return(credentials, userId, nil).Times(1)
+
+		t.NewStep("Init http")
+		req := newRequest(t, correctQuery, correctCookie)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		handlers.OIDCCallback(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusSeeOther, recorder.Code)
+		t.Require().Equal("/films", recorder.Header().Get("Location"))
+		t.Require().NotNil(findCookie(recorder.Result().Cookies(), middleware.DefaultSessionCookieName))
+	})
+
+	t.WithNewStep("Pending second factor execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		handlers := NewSSOHandlers(shs.mockSSO, &middleware.DefaultSessionCookie, "/films")
+		pending := &auth.Credentials{SessionId: auth.ChallengeTokenPrefix + "token", ExpiresIn: 5 * time.Minute, Pending: true}
+		shs.mockSSO.EXPECT().Complete(gomock.Any(), "code", authorization, gomock.Any()).
+			Return(pending, userId, nil).Times(1)
+
+		t.NewStep("Init http")
+		req := newRequest(t, correctQuery, correctCookie)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		handlers.OIDCCallback(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusAccepted, recorder.Code)
+		t.Require().Contains(recorder.Body.String(), pending.SessionId)
+		t.Require().Nil(findCookie(recorder.Result().Cookies(), middleware.DefaultSessionCookieName))
+	})
+
+	t.WithNewStep("No authorization cookie in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req := newRequest(t, correctQuery, "")
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.OIDCCallback(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("State mismatch in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req := newRequest(t, url.Values{OIDCCodeKey: {"code"}, OIDCStateKey: {"other"}}, correctCookie)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.OIDCCallback(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("Malformed cookie in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req := newRequest(t, correctQuery, "state")
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.OIDCCallback(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("Provider error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req := newRequest(t, url.Values{OIDCErrorKey: {"access_denied"}, OIDCStateKey: {"state"}}, correctCookie)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.OIDCCallback(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusUnauthorized, recorder.Code)
+	})
+
+	t.WithNewStep("No code in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req := newRequest(t, url.Values{OIDCStateKey: {"state"}}, correctCookie)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.OIDCCallback(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	errorCases := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"Authorization failed", sso.ErrorAuthorizationFailed, http.StatusUnauthorized},
+		{"No login", sso.ErrorNoLogin, http.StatusUnauthorized},
+		{"No role", sso.ErrorNoRole, http.StatusForbidden},
+		{"Login already exists", sso.ErrorLoginAlreadyExists, http.StatusConflict},
+		{"Unknown error", testError, http.StatusInternalServerError},
+	}
+
+	for _, cs := range errorCases {
+		t.WithNewStep(cs.name+" in execution", func(t provider.StepCtx) {
+			t.NewStep("Init mock")
+			shs.mockSSO.EXPECT().Complete(gomock.Any(), "code", authorization, gomock.Any()).
+				Return(nil, types.Id(0), errors.Wrap(cs.err, "test")).Times(1)
+
+			t.NewStep("Init http")
+			req := newRequest(t, correctQuery, correctCookie)
+			recorder := httptest.NewRecorder()
+
+			t.NewStep("Check result")
+			shs.handlers.OIDCCallback(recorder, req, mux.Params{})
+
+			t.Require().Equal(cs.expected, recorder.Code)
+		})
+	}
+}
+
+func TestRunSSOHandlersSuite(t *testing.T) {
+	suite.RunSuite(t, new(SSOHandlersSuite))
+}
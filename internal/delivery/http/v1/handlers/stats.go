@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"github.com/pkg/errors"
+	"net/http"
+	"net/url"
+	"vk_film/internal/delivery/http/v1/model/response"
+	"vk_film/internal/delivery/middleware"
+	"vk_film/internal/repository/stats"
+	"vk_film/pkg/logger"
+	"vk_film/pkg/mux"
+	"vk_film/pkg/operate"
+)
+
+const (
+	DateFromKey = "date_from"
+	DateToKey   = "date_to"
+	FormatKey   = "format"
+
+	JSONFormat = "json"
+	CSVFormat  = "csv"
+
+	DefaultStatsLimit = 10
+	MaxStatsLimit     = 100
+)
+
+type csvRecord interface {
+	CSVHeader() []string
+	CSVRecord() []string
+}
+
+type StatsHandlers struct {
+	repository stats.Repository
+}
+
+func NewStatsHandlers(repository stats.Repository) *StatsHandlers {
+	return &StatsHandlers{repository: repository}
+}
+
+// parseStatsParams
+// Разбирает общие для всех статистик параметры: диапазон дат, формат вывода и ограничение размера выборки.
+func parseStatsParams(values url.Values, withLimit bool) (stats.Params, string, error) {
+	params := stats.Params{}
+	format := JSONFormat
+
+	var err error
+	if params.DateFrom, err = parseDateParam(values, DateFromKey); err != nil {
+		return params, format, err
+	}
+
+	if params.DateTo, err = parseDateParam(values, DateToKey); err != nil {
+		return params, format, err
+	}
+
+	if params.DateFrom != nil && params.DateTo != nil && params.DateTo.Before(params.DateFrom.Time) {
+		return params, format, errors.Wrapf(ErrorIncorrectQueryParam,
+			"%s is before %s", DateToKey, DateFromKey)
+	}
+
+	if values.Has(FormatKey) {
+		format = values.Get(FormatKey)
+		if format != JSONFormat && format != CSVFormat {
+			return params, format, errors.Wrapf(ErrorIncorrectQueryParam,
+				"with field %s and value %s", FormatKey, format)
+		}
+	}
+
+	if withLimit {
+		if params.Limit, err = parseLimitParam(values, DefaultStatsLimit, MaxStatsLimit); err != nil {
+			return params, format, err
+		}
+	}
+
+	return params, format, nil
+}
+
+func sendStats[T csvRecord](w http.ResponseWriter, format string, data []T, l logger.Interface) {
+	if format != CSVFormat {
+		operate.SendStatus(w, http.StatusOK, data, l)
+		return
+	}
+
+	var header T
+	records := make([][]string, 0, len(data)+1)
+	records = append(records, header.CSVHeader())
+
+	for _, record := range data {
+		records = append(records, record.CSVRecord())
+	}
+
+	operate.SendCSV(w, http.StatusOK, records, l)
+}
+
+// GetFilmsPerYear
+//
+//	@Summary		Количество фильмов по годам.
+//	@Description	Возвращает количество фильмов, выпущенных в каждом году.
+//	@Tags			stats
+//	@Param			date_from	query	string	false	"Учитывать фильмы, выпущенные не раньше указанной даты"	format(date)
+//	@Param			date_to		query	string	false	"Учитывать фильмы, выпущенные не позже указанной даты"	format(date)
+//	@Param			format		query	string	false	"Формат ответа"											Enums(json, csv)	default(json)
+//	@Produce		json
+//	@Produce		text/csv
+//	@Success		200	{array}		response.FilmsPerYear	"Статистика успешно сформирована"
+//	@Failure		400	{object}	operate.ModelError		"В параметрах запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError		"Пользователь не авторизован"
+//	@Failure		500	{object}	operate.ModelError		"Ошибка сервера"
+//	@Router			/stats/films/years [get]
+//	@Security		sessionCookie
+func (sh *StatsHandlers) GetFilmsPerYear(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	params, format, err := parseStatsParams(r.URL.Query(), false)
+	if err != nil {
+		operate.SendError(w, ErrorIncorrectQueryParam, http.StatusBadRequest, l)
+		l.Warn(err)
+		return
+	}
+
+	years, err := sh.repository.GetFilmsPerYear(params)
+	if err != nil {
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't get films per year"))
+		return
+	}
+
+	sendStats(w, format, response.FromRepositoryFilmsPerYear(years), l)
+}
+
+// GetRatingDistribution
+//
+//	@Summary		Распределение рейтингов фильмов.
+//	@Description	Возвращает гистограмму рейтингов: количество фильмов для каждого значения рейтинга от 0 до 10.
+//	@Tags			stats
+//	@Param			date_from	query	string	false	"Учитывать фильмы, выпущенные не раньше указанной даты"	format(date)
+//	@Param			date_to		query	string	false	"Учитывать фильмы, выпущенные не позже указанной даты"	format(date)
+//	@Param			format		query	string	false	"Формат ответа"											Enums(json, csv)	default(json)
+//	@Produce		json
+//	@Produce		text/csv
+//	@Success		200	{array}		response.RatingCount	"Статистика успешно сформирована"
+//	@Failure		400	{object}	operate.ModelError		"В параметрах запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError		"Пользователь не авторизован"
+//	@Failure		500	{object}	operate.ModelError		"Ошибка сервера"
+//	@Router			/stats/films/ratings [get]
+//	@Security		sessionCookie
+func (sh *StatsHandlers) GetRatingDistribution(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	params, format, err := parseStatsParams(r.URL.Query(), false)
+	if err != nil {
+		operate.SendError(w, ErrorIncorrectQueryParam, http.StatusBadRequest, l)
+		l.Warn(err)
+		return
+	}
+
+	ratings, err := sh.repository.GetRatingDistribution(params)
+	if err != nil {
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't get rating distribution"))
+		return
+	}
+
+	sendStats(w, format, response.FromRepositoryRatingDistribution(ratings), l)
+}
+
+// GetFilmsWithoutActors
+//
+//	@Summary		Фильмы без актёров.
+//	@Description	Возвращает список фильмов, для которых не указан ни один актёр. Учитывается ограничение возрастного рейтинга пользователя.
+//	@Tags			stats
+//	@Param			date_from	query	string	false	"Учитывать фильмы, выпущенные не раньше указанной даты"	format(date)
+//	@Param			date_to		query	string	false	"Учитывать фильмы, выпущенные не позже указанной даты"	format(date)
+//	@Param			format		query	string	false	"Формат ответа"											Enums(json, csv)	default(json)
+//	@Produce		json
+//	@Produce		text/csv
+//	@Success		200	{array}		response.StatsFilm	"Статистика успешно сформирована"
+//	@Failure		400	{object}	operate.ModelError	"В параметрах запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/stats/films/without-actors [get]
+//	@Security		sessionCookie
+func (sh *StatsHandlers) GetFilmsWithoutActors(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	params, format, err := parseStatsParams(r.URL.Query(), false)
+	if err != nil {
+		operate.SendError(w, ErrorIncorrectQueryParam, http.StatusBadRequest, l)
+		l.Warn(err)
+		return
+	}
+
+	// Фильмы скрываются согласно ограничению возрастного рейтинга пользователя
+	params.MaxCertification = getMaxCertification(r)
+
+	films, err := sh.repository.GetFilmsWithoutActors(params)
+	if err != nil {
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't get films without actors"))
+		return
+	}
+
+	sendStats(w, format, response.FromRepositoryStatsFilms(films), l)
+}
+
+// GetTopActors
+//
+//	@Summary		Самые снимаемые актёры.
+//	@Description	Возвращает актёров с наибольшим количеством фильмов.
+//	@Tags			stats
+//	@Param			date_from	query	string	false	"Учитывать фильмы, выпущенные не раньше указанной даты"	format(date)
+//	@Param			date_to		query	string	false	"Учитывать фильмы, выпущенные не позже указанной даты"	format(date)
+//	@Param			limit		query	int		false	"Размер выборки"										minimum(1)			maximum(100)	default(10)
+//	@Param			format		query	string	false	"Формат ответа"											Enums(json, csv)	default(json)
+//	@Produce		json
+//	@Produce		text/csv
+//	@Success		200	{array}		response.ActorFilmsCount	"Статистика успешно сформирована"
+//	@Failure		400	{object}	operate.ModelError			"В параметрах запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError			"Пользователь не авторизован"
+//	@Failure		500	{object}	operate.ModelError			"Ошибка сервера"
+//	@Router			/stats/actors/top [get]
+//	@Security		sessionCookie
+func (sh *StatsHandlers) GetTopActors(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	params, format, err := parseStatsParams(r.URL.Query(), true)
+	if err != nil {
+		operate.SendError(w, ErrorIncorrectQueryParam, http.StatusBadRequest, l)
+		l.Warn(err)
+		return
+	}
+
+	actors, err := sh.repository.GetTopActors(params)
+	if err != nil {
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't get top actors"))
+		return
+	}
+
+	sendStats(w, format, response.FromRepositoryTopActors(actors), l)
+}
+
+// GetActorsAverageRating
+//
+//	@Summary		Средний рейтинг фильмов актёров.
+//	@Description	Возвращает средний рейтинг фильмов для каждого актёра, отсортированный по убыванию.
+//	@Tags			stats
+//	@Param			date_from	query	string	false	"Учитывать фильмы, выпущенные не раньше указанной даты"	format(date)
+//	@Param			date_to		query	string	false	"Учитывать фильмы, выпущенные не позже указанной даты"	format(date)
+//	@Param			limit		query	int		false	"Размер выборки"										minimum(1)			maximum(100)	default(10)
+//	@Param			format		query	string	false	"Формат ответа"											Enums(json, csv)	default(json)
+//	@Produce		json
+//	@Produce		text/csv
+//	@Success		200	{array}		response.ActorRating	"Статистика успешно сформирована"
+//	@Failure		400	{object}	operate.ModelError		"В параметрах запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError		"Пользователь не авторизован"
+//	@Failure		500	{object}	operate.ModelError		"Ошибка сервера"
+//	@Router			/stats/actors/ratings [get]
+//	@Security		sessionCookie
+func (sh *StatsHandlers) GetActorsAverageRating(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	params, format, err := parseStatsParams(r.URL.Query(), true)
+	if err != nil {
+		operate.SendError(w, ErrorIncorrectQueryParam, http.StatusBadRequest, l)
+		l.Warn(err)
+		return
+	}
+
+	actors, err := sh.repository.GetActorsAverageRating(params)
+	if err != nil {
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't get actors average rating"))
+		return
+	}
+
+	sendStats(w, format, response.FromRepositoryActorsRating(actors), l)
+}
+
+// GetActorsWithoutFilms
+//
+//	@Summary		Актёры без фильмов.
+//	@Description	Возвращает список актёров, не снявшихся ни в одном фильме из указанного диапазона дат.
+//	@Tags			stats
+//	@Param			date_from	query	string	false	"Учитывать фильмы, выпущенные не раньше указанной даты"	format(date)
+//	@Param			date_to		query	string	false	"Учитывать фильмы, выпущенные не позже указанной даты"	format(date)
+//	@Param			format		query	string	false	"Формат ответа"											Enums(json, csv)	default(json)
+//	@Produce		json
+//	@Produce		text/csv
+//	@Success		200	{array}		response.StatsActor	"Статистика успешно сформирована"
+//	@Failure		400	{object}	operate.ModelError	"В параметрах запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/stats/actors/without-films [get]
+//	@Security		sessionCookie
+func (sh *StatsHandlers) GetActorsWithoutFilms(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	params, format, err := parseStatsParams(r.URL.Query(), false)
+	if err != nil {
+		operate.SendError(w, ErrorIncorrectQueryParam, http.StatusBadRequest, l)
+		l.Warn(err)
+		return
+	}
+
+	actors, err := sh.repository.GetActorsWithoutFilms(params)
+	if err != nil {
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't get actors without films"))
+		return
+	}
+
+	sendStats(w, format, response.FromRepositoryStatsActors(actors), l)
+}
@@ -0,0 +1,431 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"vk_film/internal/delivery/http/v1/model/response"
+	"vk_film/internal/pkg/time"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/actor"
+	"vk_film/internal/repository/film"
+	"vk_film/internal/repository/stats"
+	mrs "vk_film/internal/repository/stats/mocks"
+	"vk_film/pkg/mux"
+)
+
+type StatsHandlersSuite struct {
+	suite.Suite
+	handlers  *StatsHandlers
+	mockStats *mrs.StatsRepository
+	gmc       *gomock.Controller
+}
+
+func (shs *StatsHandlersSuite) BeforeEach(t provider.T) {
+	shs.gmc = gomock.NewController(t)
+	shs.mockStats = mrs.NewStatsRepository(shs.gmc)
+	shs.handlers = NewStatsHandlers(shs.mockStats)
+}
+
+func (shs *StatsHandlersSuite) AfterEach(t provider.T) {
+	shs.gmc.Finish()
+}
+
+func (shs *StatsHandlersSuite) TestGetFilmsPerYearHandler(t provider.T) {
+	t.Title("GetFilmsPerYear handler of stats handlers")
+	t.NewStep("Init test data")
+	years := []stats.FilmsPerYear{{Year: 2001, Count: 2}, {Year: 2002, Count: 3}}
+	expectedYears := response.FromRepositoryFilmsPerYear(years)
+	dateFrom := time.MustParse("01.01.2001")
+	dateTo := time.MustParse("31.12.2002")
+
+	t.WithNewStep("Correct json execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		shs.mockStats.EXPECT().GetFilmsPerYear(stats.Params{DateFrom: &dateFrom, DateTo: &dateTo}).
+			Return(years, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		vals := req.URL.Query()
+		vals.Set(DateFromKey, dateFrom.String())
+		vals.Set(DateToKey, dateTo.String())
+		req.URL.RawQuery = vals.Encode()
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.GetFilmsPerYear(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var res []response.FilmsPerYear
+		dec := json.NewDecoder(recorder.Body)
+		t.Require().NoError(dec.Decode(&res))
+		t.Require().EqualValues(expectedYears, res)
+	})
+
+	t.WithNewStep("Correct csv execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		shs.mockStats.EXPECT().GetFilmsPerYear(stats.Params{}).Return(years, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		vals := req.URL.Query()
+		vals.Set(FormatKey, CSVFormat)
+		req.URL.RawQuery = vals.Encode()
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.GetFilmsPerYear(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		records, err := csv.NewReader(recorder.Body).ReadAll()
+		t.Require().NoError(err)
+		t.Require().EqualValues([][]string{{"year", "count"}, {"2001", "2"}, {"2002", "3"}}, records)
+	})
+
+	t.WithNewStep("Incorrect date param in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		vals := req.URL.Query()
+		vals.Set(DateFromKey, "2001-01-01")
+		req.URL.RawQuery = vals.Encode()
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.GetFilmsPerYear(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("Reversed date range in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		vals := req.URL.Query()
+		vals.Set(DateFromKey, dateTo.String())
+		vals.Set(DateToKey, dateFrom.String())
+		req.URL.RawQuery = vals.Encode()
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.GetFilmsPerYear(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("Incorrect format param in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		vals := req.URL.Query()
+		vals.Set(FormatKey, "xml")
+		req.URL.RawQuery = vals.Encode()
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.GetFilmsPerYear(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("Stats repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		shs.mockStats.EXPECT().GetFilmsPerYear(stats.Params{}).Return(nil, testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.GetFilmsPerYear(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (shs *StatsHandlersSuite) TestGetRatingDistributionHandler(t provider.T) {
+	t.Title("GetRatingDistribution handler of stats handlers")
+	t.NewStep("Init test data")
+	ratings := []stats.RatingCount{{Rating: 0, Count: 0}, {Rating: 1, Count: 3}}
+	expectedRatings := response.FromRepositoryRatingDistribution(ratings)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		shs.mockStats.EXPECT().GetRatingDistribution(stats.Params{}).Return(ratings, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.GetRatingDistribution(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var res []response.RatingCount
+		dec := json.NewDecoder(recorder.Body)
+		t.Require().NoError(dec.Decode(&res))
+		t.Require().EqualValues(expectedRatings, res)
+	})
+
+	t.WithNewStep("Stats repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		shs.mockStats.EXPECT().GetRatingDistribution(stats.Params{}).Return(nil, testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.GetRatingDistribution(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (shs *StatsHandlersSuite) TestGetTopActorsHandler(t provider.T) {
+	t.Title("GetTopActors handler of stats handlers")
+	t.NewStep("Init test data")
+	actors := []stats.ActorFilmsCount{{ID: 1, Name: "actor", FilmsCount: 5}}
+	expectedActors := response.FromRepositoryTopActors(actors)
+
+	t.WithNewStep("Correct default limit execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		shs.mockStats.EXPECT().GetTopActors(stats.Params{Limit: DefaultStatsLimit}).Return(actors, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.GetTopActors(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var res []response.ActorFilmsCount
+		dec := json.NewDecoder(recorder.Body)
+		t.Require().NoError(dec.Decode(&res))
+		t.Require().EqualValues(expectedActors, res)
+	})
+
+	t.WithNewStep("Correct custom limit execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		shs.mockStats.EXPECT().GetTopActors(stats.Params{Limit: 3}).Return(actors, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		vals := req.URL.Query()
+		vals.Set(LimitKey, "3")
+		req.URL.RawQuery = vals.Encode()
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.GetTopActors(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
+	t.WithNewStep("Incorrect limit param in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		vals := req.URL.Query()
+		vals.Set(LimitKey, "1000")
+		req.URL.RawQuery = vals.Encode()
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.GetTopActors(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("Stats repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		shs.mockStats.EXPECT().GetTopActors(stats.Params{Limit: DefaultStatsLimit}).Return(nil, testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.GetTopActors(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (shs *StatsHandlersSuite) TestGetActorsAverageRatingHandler(t provider.T) {
+	t.Title("GetActorsAverageRating handler of stats handlers")
+	t.NewStep("Init test data")
+	actors := []stats.ActorRating{{ID: 1, Name: "actor", AverageRating: 7.25, FilmsCount: 4}}
+
+	t.WithNewStep("Correct csv execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		shs.mockStats.EXPECT().GetActorsAverageRating(stats.Params{Limit: DefaultStatsLimit}).
+			Return(actors, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		vals := req.URL.Query()
+		vals.Set(FormatKey, CSVFormat)
+		req.URL.RawQuery = vals.Encode()
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.GetActorsAverageRating(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		records, err := csv.NewReader(recorder.Body).ReadAll()
+		t.Require().NoError(err)
+		t.Require().EqualValues([][]string{
+			{"id", "name", "average_rating", "films_count"},
+			{"1", "actor", "7.25", "4"},
+		}, records)
+	})
+
+	t.WithNewStep("Stats repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		shs.mockStats.EXPECT().GetActorsAverageRating(stats.Params{Limit: DefaultStatsLimit}).
+			Return(nil, testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.GetActorsAverageRating(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (shs *StatsHandlersSuite) TestGetFilmsWithoutActorsHandler(t provider.T) {
+	t.Title("GetFilmsWithoutActors handler of stats handlers")
+	t.NewStep("Init test data")
+	films := []film.Film{{ID: 1, Name: "film", DataPublish: time.MustParse("12.03.2003"), Rating: 5}}
+	expectedFilms := response.FromRepositoryStatsFilms(films)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		shs.mockStats.EXPECT().GetFilmsWithoutActors(stats.Params{}).Return(films, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.GetFilmsWithoutActors(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var res []response.StatsFilm
+		dec := json.NewDecoder(recorder.Body)
+		t.Require().NoError(dec.Decode(&res))
+		t.Require().EqualValues(expectedFilms, res)
+	})
+
+	t.WithNewStep("Stats repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		shs.mockStats.EXPECT().GetFilmsWithoutActors(stats.Params{}).Return(nil, testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.GetFilmsWithoutActors(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (shs *StatsHandlersSuite) TestGetActorsWithoutFilmsHandler(t provider.T) {
+	t.Title("GetActorsWithoutFilms handler of stats handlers")
+	t.NewStep("Init test data")
+	actors := []actor.Actor{{ID: 1, Name: "actor", Sex: types.MALE, Birthday: time.MustParse("12.03.2003")}}
+
+	t.WithNewStep("Correct csv execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		shs.mockStats.EXPECT().GetActorsWithoutFilms(stats.Params{}).Return(actors, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		vals := req.URL.Query()
+		vals.Set(FormatKey, CSVFormat)
+		req.URL.RawQuery = vals.Encode()
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.GetActorsWithoutFilms(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		records, err := csv.NewReader(recorder.Body).ReadAll()
+		t.Require().NoError(err)
+		t.Require().EqualValues([][]string{
+			{"id", "name", "sex", "birthday"},
+			{"1", "actor", "male", "12.03.2003"},
+		}, records)
+	})
+
+	t.WithNewStep("Stats repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		shs.mockStats.EXPECT().GetActorsWithoutFilms(stats.Params{}).Return(nil, testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		shs.handlers.GetActorsWithoutFilms(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func TestRunStatsHandlersSuite(t *testing.T) {
+	suite.RunSuite(t, new(StatsHandlersSuite))
+}
@@ -8,19 +8,40 @@ import (
 	"vk_film/internal/delivery/middleware"
 	"vk_film/internal/pkg/types"
 	"vk_film/internal/repository/user"
+	"vk_film/internal/usecase/auth"
 	"vk_film/pkg/logger"
 )
 
 var testError = errors.New("test error")
 
 var adminUser = &user.User{
-	Role: types.ADMIN,
+	Role:        types.ADMIN,
+	Permissions: types.Permissions,
 }
 
 var userUser = &user.User{
 	Role: types.USER,
 }
 
+var managerUser = &user.User{
+	ID:          3,
+	Role:        "manager",
+	Permissions: []types.Permission{types.UserManage},
+}
+
+var moderatorUser = &user.User{
+	ID:          4,
+	Role:        "moderator",
+	Permissions: []types.Permission{types.UserModerate},
+}
+
+// verifyPassword
+// Проверяет, что хеш получен из пароля с текущими параметрами
+func verifyPassword(hash, password string) bool {
+	outdated, err := auth.NewTestPasswordHasher().Verify(hash, password)
+	return err == nil && !outdated
+}
+
 type errReader int
 
 func (errReader) Read(p []byte) (n int, err error) {
@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"github.com/pkg/errors"
+	"net/http"
+	"vk_film/internal/delivery/http/v1/model/request"
+	"vk_film/internal/delivery/http/v1/model/response"
+	"vk_film/internal/delivery/middleware"
+	"vk_film/internal/repository/session"
+	"vk_film/internal/repository/twofactor"
+	"vk_film/internal/usecase/auth"
+	"vk_film/pkg/mux"
+	"vk_film/pkg/operate"
+)
+
+// VerifyLogin
+//
+//	@Summary		Ввод кода второго фактора.
+//	@Description	Завершает вход, ожидающий второй фактор, кодом из приложения-аутентификатора или одноразовым кодом восстановления. Если вход подключает второй фактор, принимается только код из приложения, а в ответе один раз возвращаются коды восстановления. После нескольких неверных кодов вход нужно начинать заново.
+//	@Tags			user
+//	@Accept			json
+//	@Param			request	body	request.VerifyLogin	true	"Токен ожидающего входа, код и необходимость запомнить вход"
+//	@Produce		json
+//	@Success		200	{object}	response.Credentials	"Пользователь успешно авторизован, токены возвращаются только в режиме jwt"
+//	@Header			200	{string}	Set-Cookie				"Устанавливает сессию текущего пользователя"
+//	@Failure		400	{object}	operate.ModelError		"В теле запроса ошибка или второй фактор ещё не подключён"
+//	@Failure		401	{object}	operate.ModelError		"Ожидающий вход не найден или истёк"
+//	@Failure		409	{object}	operate.ModelError		"Неверный код"
+//	@Failure		429	{object}	operate.ModelError		"Слишком много неверных кодов"
+//	@Header			429	{integer}	Retry-After				"Через сколько секунд можно повторить попытку"
+//	@Failure		500	{object}	operate.ModelError		"Ошибка сервера"
+//	@Router			/login/2fa [post]
+func (uh *UserHandlers) VerifyLogin(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	// Получение значения тела запроса
+	var verify request.VerifyLogin
+	if code, err := parseRequestBody(r.Body, &verify, request.ValidateVerifyLogin, l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	client := clientInfo(r)
+	client.RememberMe = verify.RememberMe
+	credentials, err := uh.auth.VerifyLogin(verify.Token, verify.Code, client)
+	if err != nil {
+		var lockout *auth.LockoutError
+		switch {
+		case errors.As(err, &lockout):
+			w.Header().Set(RetryAfterHeader, retryAfterSeconds(lockout.RetryAfter))
+			operate.SendError(w, ErrorTooManyCodeAttempts, http.StatusTooManyRequests, l)
+			l.Warn("[Security] second factor from %s is locked for %s: %s", client.IP, lockout.RetryAfter, err)
+		case errors.Is(err, auth.ErrorTooManyAttempts):
+			operate.SendError(w, ErrorTooManyCodeAttempts, http.StatusTooManyRequests, l)
+			l.Warn("[Security] login challenge from %s is dropped: %s", client.IP, err)
+		case errors.Is(err, auth.ErrorIncorrectCode):
+			operate.SendError(w, ErrorIncorrectTwoFactorCode, http.StatusConflict, l)
+			l.Warn("[Security] incorrect second factor from %s: %s", client.IP, err)
+		case errors.Is(err, session.ErrorNoSession):
+			operate.SendError(w, ErrorInvalidLoginChallenge, http.StatusUnauthorized, l)
+		case errors.Is(err, twofactor.ErrorSecretNotFound):
+			operate.SendError(w, ErrorTwoFactorNotEnrolled, http.StatusBadRequest, l)
+		default:
+			operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+			l.Error(errors.Wrapf(err, "can't verify second factor"))
+		}
+		return
+	}
+
+	if len(credentials.RecoveryCodes) != 0 {
+		l.Info("[Security] two-factor authentication is enabled on login from %s", client.IP)
+	}
+
+	sendCredentials(w, uh.cookie, credentials, l)
+}
+
+// EnrollLogin
+//
+//	@Summary		Подключение второго фактора при входе.
+//	@Description	Создаёт секрет для входа, который требует подключить второй фактор. Вход завершается первым кодом из приложения в /login/2fa.
+//	@Tags			user
+//	@Accept			json
+//	@Param			request	body	request.EnrollLogin	true	"Токен ожидающего входа"
+//	@Produce		json
+//	@Success		200	{object}	response.Enrollment	"Секрет и ссылка для приложения-аутентификатора"
+//	@Failure		400	{object}	operate.ModelError	"В теле запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError	"Ожидающий вход не найден или истёк"
+//	@Failure		409	{object}	operate.ModelError	"Второй фактор уже подключён"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/login/2fa/enroll [post]
+func (uh *UserHandlers) EnrollLogin(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	// Получение значения тела запроса
+	var enroll request.EnrollLogin
+	if code, err := parseRequestBody(r.Body, &enroll, request.ValidateEnrollLogin, l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	enrollment, err := uh.auth.EnrollLogin(enroll.Token)
+	if err != nil {
+		switch {
+		case errors.Is(err, session.ErrorNoSession):
+			operate.SendError(w, ErrorInvalidLoginChallenge, http.StatusUnauthorized, l)
+		case errors.Is(err, twofactor.ErrorAlreadyEnabled):
+			operate.SendError(w, ErrorTwoFactorAlreadyEnabled, http.StatusConflict, l)
+		default:
+			operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+			l.Error(errors.Wrapf(err, "can't enroll second factor on login"))
+		}
+		return
+	}
+
+	operate.SendStatus(w, http.StatusOK, response.FromEnrollment(enrollment), l)
+}
+
+// EnrollTwoFactor
+//
+//	@Summary		Подключение второго фактора.
+//	@Description	Создаёт секрет двухфакторной аутентификации текущего пользователя. Ссылку из ответа можно показать в виде QR-кода. Второй фактор начинает действовать после подтверждения кодом из приложения, повторный запрос до подтверждения заменяет секрет.
+//	@Tags			user
+//	@Produce		json
+//	@Success		200	{object}	response.Enrollment	"Секрет и ссылка для приложения-аутентификатора"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		409	{object}	operate.ModelError	"Второй фактор уже подключён"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/user/me/2fa [post]
+//	@Security		sessionCookie
+func (uh *UserHandlers) EnrollTwoFactor(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	usr := middleware.GetUser(r)
+	if usr == nil {
+		operate.SendError(w, ErrorUserNotAuthorized, http.StatusUnauthorized, l)
+		return
+	}
+
+	enrollment, err := uh.auth.EnrollTwoFactor(usr.ID)
+	if err != nil {
+		if errors.Is(err, twofactor.ErrorAlreadyEnabled) {
+			operate.SendError(w, ErrorTwoFactorAlreadyEnabled, http.StatusConflict, l)
+			return
+		}
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't enroll second factor of user %d", usr.ID))
+		return
+	}
+
+	operate.SendStatus(w, http.StatusOK, response.FromEnrollment(enrollment), l)
+}
+
+// ConfirmTwoFactor
+//
+//	@Summary		Подтверждение второго фактора.
+//	@Description	Включает двухфакторную аутентификацию текущего пользователя кодом из приложения. Одноразовые коды восстановления возвращаются только в этом ответе.
+//	@Tags			user
+//	@Accept			json
+//	@Param			request	body	request.TwoFactorCode	true	"Код из приложения"
+//	@Produce		json
+//	@Success		200	{object}	response.RecoveryCodes	"Второй фактор подключён"
+//	@Failure		400	{object}	operate.ModelError		"В теле запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError		"Пользователь не авторизован"
+//	@Failure		404	{object}	operate.ModelError		"Подключение второго фактора не начато"
+//	@Failure		409	{object}	operate.ModelError		"Неверный код или второй фактор уже подключён"
+//	@Failure		429	{object}	operate.ModelError		"Слишком много неверных кодов"
+//	@Header			429	{integer}	Retry-After				"Через сколько секунд можно повторить попытку"
+//	@Failure		500	{object}	operate.ModelError		"Ошибка сервера"
+//	@Router			/user/me/2fa/confirm [post]
+//	@Security		sessionCookie
+func (uh *UserHandlers) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	usr := middleware.GetUser(r)
+	if usr == nil {
+		operate.SendError(w, ErrorUserNotAuthorized, http.StatusUnauthorized, l)
+		return
+	}
+
+	// Получение значения тела запроса
+	var confirm request.TwoFactorCode
+	if code, err := parseRequestBody(r.Body, &confirm, request.ValidateTwoFactorCode, l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	recoveryCodes, err := uh.auth.ConfirmTwoFactor(usr.ID, confirm.Code)
+	if err != nil {
+		sendTwoFactorError(w, r, err, "can't confirm second factor")
+		return
+	}
+
+	l.Info("[Security] user %d enabled two-factor authentication", usr.ID)
+	operate.SendStatus(w, http.StatusOK, response.RecoveryCodes{RecoveryCodes: recoveryCodes}, l)
+}
+
+// DisableTwoFactor
+//
+//	@Summary		Отключение второго фактора.
+//	@Description	Отключает двухфакторную аутентификацию текущего пользователя. Требуется код из приложения или код восстановления.
+//	@Tags			user
+//	@Accept			json
+//	@Param			request	body	request.TwoFactorCode	true	"Код из приложения или код восстановления"
+//	@Produce		json
+//	@Success		200	"Второй фактор отключён"
+//	@Failure		400	{object}	operate.ModelError	"В теле запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		403	{object}	operate.ModelError	"Второй фактор обязателен для роли пользователя"
+//	@Failure		404	{object}	operate.ModelError	"Второй фактор не подключён"
+//	@Failure		409	{object}	operate.ModelError	"Неверный код"
+//	@Failure		429	{object}	operate.ModelError	"Слишком много неверных кодов"
+//	@Header			429	{integer}	Retry-After			"Через сколько секунд можно повторить попытку"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/user/me/2fa [delete]
+//	@Security		sessionCookie
+func (uh *UserHandlers) DisableTwoFactor(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	usr := middleware.GetUser(r)
+	if usr == nil {
+		operate.SendError(w, ErrorUserNotAuthorized, http.StatusUnauthorized, l)
+		return
+	}
+
+	// Получение значения тела запроса
+	var disable request.TwoFactorCode
+	if code, err := parseRequestBody(r.Body, &disable, request.ValidateTwoFactorCode, l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	if err := uh.auth.DisableTwoFactor(usr.ID, disable.Code); err != nil {
+		if errors.Is(err, auth.ErrorTwoFactorRequired) {
+			operate.SendError(w, ErrorTwoFactorRequired, http.StatusForbidden, l)
+			return
+		}
+		sendTwoFactorError(w, r, err, "can't disable second factor")
+		return
+	}
+
+	l.Info("[Security] user %d disabled two-factor authentication", usr.ID)
+	operate.SendStatus(w, http.StatusOK, nil, l)
+}
+
+// sendTwoFactorError
+// Отправляет ошибку проверки кода второго фактора текущего пользователя
+func sendTwoFactorError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	l := middleware.GetLogger(r)
+
+	var lockout *auth.LockoutError
+	switch {
+	case errors.As(err, &lockout):
+		w.Header().Set(RetryAfterHeader, retryAfterSeconds(lockout.RetryAfter))
+		operate.SendError(w, ErrorTooManyCodeAttempts, http.StatusTooManyRequests, l)
+		l.Warn("[Security] second factor is locked for %s: %s", lockout.RetryAfter, err)
+	case errors.Is(err, auth.ErrorIncorrectCode):
+		operate.SendError(w, ErrorIncorrectTwoFactorCode, http.StatusConflict, l)
+		l.Warn("[Security] %s", err)
+	case errors.Is(err, twofactor.ErrorSecretNotFound):
+		operate.SendError(w, ErrorTwoFactorNotEnrolled, http.StatusNotFound, l)
+	case errors.Is(err, twofactor.ErrorAlreadyEnabled):
+		operate.SendError(w, ErrorTwoFactorAlreadyEnabled, http.StatusConflict, l)
+	default:
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, msg))
+	}
+}
@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"time"
+	"vk_film/internal/delivery/http/v1/model/response"
+	"vk_film/internal/delivery/middleware"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/session"
+	"vk_film/internal/repository/twofactor"
+	"vk_film/internal/repository/user"
+	"vk_film/internal/usecase/auth"
+	"vk_film/pkg/mux"
+)
+
+func (uhs *UserHandlersSuite) TestVerifyLoginHandler(t provider.T) {
+	t.Title("VerifyLogin handler of user handlers")
+	t.NewStep("Init test data")
+	challengeToken := "challenge"
+	code := "123456"
+	clientIP := "192.168.0.1"
+	client := auth.ClientInfo{IP: clientIP, UserAgent: "curl/8.0"}
+	body := `{ "token": "challenge", "code": "123456" }`
+
+	send := func(t provider.StepCtx, body string) *httptest.ResponseRecorder {
+		req, err := initRequest(strings.NewReader(body), nil)
+		t.Require().NoError(err)
+		req.RemoteAddr = clientIP + ":43210"
+		req.Header.Set("User-Agent", client.UserAgent)
+
+		recorder := httptest.NewRecorder()
+		uhs.handlers.VerifyLogin(recorder, req, mux.Params{})
+		return recorder
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().VerifyLogin(challengeToken, code, client).
+			Return(&auth.Credentials{SessionId: "id", ExpiresIn: 48 * time.Hour}, nil).Times(1)
+
+		t.NewStep("Check result")
+		recorder := send(t, body)
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		cks := recorder.Result().Cookies()
+		i := slices.IndexFunc(cks,
+			func(ck *http.Cookie) bool { return ck != nil && ck.Name == middleware.DefaultSessionCookieName },
+		)
+		t.Require().NotEqual(-1, i)
+		t.Require().Equal("id", cks[i].Value)
+		t.Require().Empty(recorder.Body.String())
+	})
+
+	t.WithNewStep("Correct execute with enrollment", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		recoveryCodes := []string{"3f7a0-c9d12", "8e21b-04fa9"}
+		uhs.mockAuth.EXPECT().VerifyLogin(challengeToken, code, client).
+			Return(&auth.Credentials{SessionId: "id", ExpiresIn: 48 * time.Hour,
+				RecoveryCodes: recoveryCodes}, nil).Times(1)
+
+		t.NewStep("Check result")
+		recorder := send(t, body)
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var res response.RecoveryCodes
+		t.Require().NoError(json.NewDecoder(recorder.Body).Decode(&res))
+		t.Require().Equal(recoveryCodes, res.RecoveryCodes)
+	})
+
+	t.WithNewStep("Incorrect body execute", func(t provider.StepCtx) {
+		t.NewStep("Check result")
+		recorder := send(t, `{ "token": "challenge" }`)
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	errorCases := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"Incorrect code", auth.ErrorIncorrectCode, http.StatusConflict},
+		{"Challenge not found", session.ErrorNoSession, http.StatusUnauthorized},
+		{"Too many attempts", auth.ErrorTooManyAttempts, http.StatusTooManyRequests},
+		{"Not enrolled", twofactor.ErrorSecretNotFound, http.StatusBadRequest},
+		{"Session manager error", testError, http.StatusInternalServerError},
+	}
+
+	for _, errorCase := range errorCases {
+		t.WithNewStep(errorCase.name+" in execution", func(t provider.StepCtx) {
+			t.NewStep("Init mock")
+			uhs.mockAuth.EXPECT().VerifyLogin(challengeToken, code, client).Return(nil, errorCase.err).Times(1)
+
+			t.NewStep("Check result")
+			t.Require().Equal(errorCase.status, send(t, body).Code)
+		})
+	}
+
+	t.WithNewStep("Locked user in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().VerifyLogin(challengeToken, code, client).
+			Return(nil, &auth.LockoutError{RetryAfter: time.Minute}).Times(1)
+
+		t.NewStep("Check result")
+		recorder := send(t, body)
+		t.Require().Equal(http.StatusTooManyRequests, recorder.Code)
+		t.Require().Equal("60", recorder.Header().Get(RetryAfterHeader))
+	})
+}
+
+func (uhs *UserHandlersSuite) TestEnrollLoginHandler(t provider.T) {
+	t.Title("EnrollLogin handler of user handlers")
+	t.NewStep("Init test data")
+	enrollment := &auth.Enrollment{Secret: "JBSWY3DPEHPK3PXP", URI: "otpauth://totp/VK%20Film:admin"}
+	body := `{ "token": "challenge" }`
+
+	send := func(t provider.StepCtx) *httptest.ResponseRecorder {
+		req, err := initRequest(strings.NewReader(body), nil)
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		uhs.handlers.EnrollLogin(recorder, req, mux.Params{})
+		return recorder
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().EnrollLogin("challenge").Return(enrollment, nil).Times(1)
+
+		t.NewStep("Check result")
+		recorder := send(t)
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var res response.Enrollment
+		t.Require().NoError(json.NewDecoder(recorder.Body).Decode(&res))
+		t.Require().Equal(response.FromEnrollment(enrollment), res)
+	})
+
+	t.WithNewStep("Challenge not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().EnrollLogin("challenge").Return(nil, session.ErrorNoSession).Times(1)
+
+		t.NewStep("Check result")
+		t.Require().Equal(http.StatusUnauthorized, send(t).Code)
+	})
+
+	t.WithNewStep("Already enabled in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().EnrollLogin("challenge").Return(nil, twofactor.ErrorAlreadyEnabled).Times(1)
+
+		t.NewStep("Check result")
+		t.Require().Equal(http.StatusConflict, send(t).Code)
+	})
+}
+
+func (uhs *UserHandlersSuite) TestEnrollTwoFactorHandler(t provider.T) {
+	t.Title("EnrollTwoFactor handler of user handlers")
+	t.NewStep("Init test data")
+	usr := &user.User{ID: 1, Role: types.USER}
+	enrollment := &auth.Enrollment{Secret: "JBSWY3DPEHPK3PXP", URI: "otpauth://totp/VK%20Film:login"}
+	contextValues := map[types.ContextField]any{middleware.UserField: usr}
+
+	send := func(t provider.StepCtx, contextValues map[types.ContextField]any) *httptest.ResponseRecorder {
+		req, err := initRequest(nil, contextValues)
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		uhs.handlers.EnrollTwoFactor(recorder, req, mux.Params{})
+		return recorder
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().EnrollTwoFactor(usr.ID).Return(enrollment, nil).Times(1)
+
+		t.NewStep("Check result")
+		recorder := send(t, contextValues)
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var res response.Enrollment
+		t.Require().NoError(json.NewDecoder(recorder.Body).Decode(&res))
+		t.Require().Equal(enrollment.URI, res.URI)
+	})
+
+	t.WithNewStep("Not authorized execute", func(t provider.StepCtx) {
+		t.NewStep("Check result")
+		t.Require().Equal(http.StatusUnauthorized, send(t, nil).Code)
+	})
+
+	t.WithNewStep("Already enabled in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().EnrollTwoFactor(usr.ID).Return(nil, twofactor.ErrorAlreadyEnabled).Times(1)
+
+		t.NewStep("Check result")
+		t.Require().Equal(http.StatusConflict, send(t, contextValues).Code)
+	})
+
+	t.WithNewStep("Session manager error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().EnrollTwoFactor(usr.ID).Return(nil, testError).Times(1)
+
+		t.NewStep("Check result")
+		t.Require().Equal(http.StatusInternalServerError, send(t, contextValues).Code)
+	})
+}
+
+func (uhs *UserHandlersSuite) TestConfirmTwoFactorHandler(t provider.T) {
+	t.Title("ConfirmTwoFactor handler of user handlers")
+	t.NewStep("Init test data")
+	usr := &user.User{ID: 1, Role: types.USER}
+	recoveryCodes := []string{"3f7a0-c9d12", "8e21b-04fa9"}
+	body := `{ "code": "123456" }`
+	contextValues := map[types.ContextField]any{middleware.UserField: usr}
+
+	send := func(t provider.StepCtx, body string) *httptest.ResponseRecorder {
+		req, err := initRequest(strings.NewReader(body), contextValues)
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		uhs.handlers.ConfirmTwoFactor(recorder, req, mux.Params{})
+		return recorder
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().ConfirmTwoFactor(usr.ID, "123456").Return(recoveryCodes, nil).Times(1)
+
+		t.NewStep("Check result")
+		recorder := send(t, body)
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var res response.RecoveryCodes
+		t.Require().NoError(json.NewDecoder(recorder.Body).Decode(&res))
+		t.Require().Equal(recoveryCodes, res.RecoveryCodes)
+	})
+
+	t.WithNewStep("Incorrect body execute", func(t provider.StepCtx) {
+		t.NewStep("Check result")
+		t.Require().Equal(http.StatusBadRequest, send(t, `{ "code": "" }`).Code)
+	})
+
+	errorCases := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"Incorrect code", auth.ErrorIncorrectCode, http.StatusConflict},
+		{"Enrollment not started", twofactor.ErrorSecretNotFound, http.StatusNotFound},
+		{"Already enabled", twofactor.ErrorAlreadyEnabled, http.StatusConflict},
+		{"Locked user", &auth.LockoutError{RetryAfter: time.Minute}, http.StatusTooManyRequests},
+		{"Session manager error", testError, http.StatusInternalServerError},
+	}
+
+	for _, errorCase := range errorCases {
+		t.WithNewStep(errorCase.name+" in execution", func(t provider.StepCtx) {
+			t.NewStep("Init mock")
+			uhs.mockAuth.EXPECT().ConfirmTwoFactor(usr.ID, "123456").Return(nil, errorCase.err).Times(1)
+
+			t.NewStep("Check result")
+			t.Require().Equal(errorCase.status, send(t, body).Code)
+		})
+	}
+}
+
+func (uhs *UserHandlersSuite) TestDisableTwoFactorHandler(t provider.T) {
+	t.Title("DisableTwoFactor handler of user handlers")
+	t.NewStep("Init test data")
+	usr := &user.User{ID: 1, Role: types.ADMIN}
+	body := `{ "code": "3f7a0-c9d12" }`
+	contextValues := map[types.ContextField]any{middleware.UserField: usr}
+
+	send := func(t provider.StepCtx) *httptest.ResponseRecorder {
+		req, err := initRequest(strings.NewReader(body), contextValues)
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+		uhs.handlers.DisableTwoFactor(recorder, req, mux.Params{})
+		return recorder
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().DisableTwoFactor(usr.ID, "3f7a0-c9d12").Return(nil).Times(1)
+
+		t.NewStep("Check result")
+		t.Require().Equal(http.StatusOK, send(t).Code)
+	})
+
+	errorCases := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"Required for role", auth.ErrorTwoFactorRequired, http.StatusForbidden},
+		{"Incorrect code", auth.ErrorIncorrectCode, http.StatusConflict},
+		{"Not enabled", twofactor.ErrorSecretNotFound, http.StatusNotFound},
+		{"Session manager error", testError, http.StatusInternalServerError},
+	}
+
+	for _, errorCase := range errorCases {
+		t.WithNewStep(errorCase.name+" in execution", func(t provider.StepCtx) {
+			t.NewStep("Init mock")
+			uhs.mockAuth.EXPECT().DisableTwoFactor(usr.ID, "3f7a0-c9d12").Return(errorCase.err).Times(1)
+
+			t.NewStep("Check result")
+			t.Require().Equal(errorCase.status, send(t).Code)
+		})
+	}
+}
@@ -2,59 +2,80 @@ package handlers
 
 import (
 	"github.com/pkg/errors"
-	"golang.org/x/crypto/bcrypt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 	"vk_film/internal/delivery/http/v1/model/request"
 	"vk_film/internal/delivery/http/v1/model/response"
 	"vk_film/internal/delivery/middleware"
 	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/event"
+	"vk_film/internal/repository/role"
+	"vk_film/internal/repository/session"
+	"vk_film/internal/repository/token"
 	"vk_film/internal/repository/user"
+	"vk_film/internal/usecase/accounts"
 	"vk_film/internal/usecase/auth"
+	"vk_film/pkg/logger"
 	"vk_film/pkg/mux"
 	"vk_film/pkg/operate"
 	"vk_film/pkg/slices"
 )
 
 const (
-	DefaultRole = "user"
-	UserIdField = "user_id"
+	DefaultRole      = "user"
+	UserIdField      = "user_id"
+	TokenIdField     = "token_id"
+	SessionIdField   = "session_id"
+	RetryAfterHeader = "Retry-After"
+	ConfirmKey       = "confirm"
+	EventTypeKey     = "type"
+	FromKey          = "from"
+	ToKey            = "to"
+	IPKey            = "ip"
+	UserIdKey        = "user_id"
+
+	DefaultEventsLimit = 50
+	MaxEventsLimit     = 500
 )
 
 type UserHandlers struct {
-	repository user.Repository
-	auth       auth.Manager
+	repository     user.Repository
+	roles          role.Repository
+	auth           auth.Manager
+	accounts       accounts.Usecase
+	passwords      auth.PasswordHasher
+	passwordPolicy auth.PasswordPolicy
+	cookie         *middleware.SessionCookie
 }
 
-func NewUserHandlers(repository user.Repository, auth auth.Manager) *UserHandlers {
-	return &UserHandlers{repository: repository, auth: auth}
+func NewUserHandlers(repository user.Repository, roles role.Repository, auth auth.Manager, accounts accounts.Usecase,
+	passwords auth.PasswordHasher, passwordPolicy auth.PasswordPolicy, cookie *middleware.SessionCookie) *UserHandlers {
+	return &UserHandlers{repository: repository, roles: roles, auth: auth, accounts: accounts, passwords: passwords,
+		passwordPolicy: passwordPolicy, cookie: cookie}
 }
 
 // CreateUser
 //
 //	@Summary		Добавление пользователя.
-//	@Description	Добавляет пользователя включая его логин, пароль и роль. По умолчанию роль 'user'.
+//	@Description	Добавляет пользователя включая его логин, пароль и роль. По умолчанию роль 'user'. Нельзя выдать роль с правами, которых нет у текущего пользователя. Пароль должен соответствовать политике паролей.
 //	@Tags			user
 //	@Accept			json
 //	@Param			request	body	request.CreateUser	true	"Информация о добавляемом пользователе"
 //	@Produce		json
 //	@Success		201	{object}	response.User		"Пользователь успешно добавлен в базу"
-//	@Failure		400	{object}	operate.ModelError	"В теле запроса ошибка"
+//	@Failure		400	{object}	operate.ModelError	"В теле запроса ошибка или пароль не соответствует политике"
 //	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
-//	@Failure		403	{object}	operate.ModelError	"У пользователя нет прав на создание пользователя"
-//	@Failure		409	{object}	operate.ModelError	"Пользователь с таким же логином уже существует"
+//	@Failure		403	{object}	operate.ModelError	"У пользователя нет прав на создание пользователя или выдачу роли"
+//	@Failure		409	{object}	operate.ModelError	"Пользователь с таким же логином или почтой уже существует"
 //	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
 //	@Router			/user [post]
 //	@Security		sessionCookie
 func (uh *UserHandlers) CreateUser(w http.ResponseWriter, r *http.Request, _ mux.Params) {
 	l := middleware.GetLogger(r)
 
-	// Проверка доступа
-	if usr := middleware.GetUser(r); usr == nil || usr.Role != types.ADMIN {
-		operate.SendError(w, ErrorUserNotPermitted, http.StatusForbidden, l)
-		return
-	}
-
 	// Получение значения тела запроса
 	var createUser request.CreateUser
 	if code, err := parseRequestBody(r.Body, &createUser, request.ValidateCreateUser, l); err != nil {
@@ -62,22 +83,33 @@ func (uh *UserHandlers) CreateUser(w http.ResponseWriter, r *http.Request, _ mux
 		return
 	}
 
-	// Шифруем пароль пользователя
-	enc, err := bcrypt.GenerateFromPassword([]byte(createUser.Password), bcrypt.DefaultCost)
-	if err != nil {
-		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
-		l.Warn(errors.Wrapf(err, "try encrypt password for user"))
+	if createUser.Role == "" {
+		createUser.Role = DefaultRole
+	}
+
+	if code, err := uh.checkRoleGrant(r, types.Roles(createUser.Role), l); err != nil {
+		operate.SendError(w, err, code, l)
 		return
 	}
 
-	if createUser.Role == "" {
-		createUser.Role = DefaultRole
+	if err := uh.passwordPolicy.Check(createUser.Password); err != nil {
+		operate.SendError(w, err, http.StatusBadRequest, l)
+		return
+	}
+
+	// Хешируем пароль пользователя
+	hash, err := uh.passwords.Hash(createUser.Password)
+	if err != nil {
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Warn(errors.Wrapf(err, "try hash password for user"))
+		return
 	}
 
 	createdUser, err := uh.repository.CreateUser(&user.User{
 		Login:    createUser.Login,
-		Password: string(enc),
+		Password: hash,
 		Role:     types.Roles(createUser.Role),
+		Email:    strings.ToLower(createUser.Email),
 	})
 	if err != nil {
 		if errors.Is(err, user.ErrorLoginAlreadyExists) {
@@ -85,42 +117,43 @@ func (uh *UserHandlers) CreateUser(w http.ResponseWriter, r *http.Request, _ mux
 			l.Info(errors.Wrapf(err, "can't create user"))
 			return
 		}
+		if errors.Is(err, user.ErrorEmailAlreadyExists) {
+			operate.SendError(w, ErrorEmailAlreadyExists, http.StatusConflict, l)
+			l.Info(errors.Wrapf(err, "can't create user"))
+			return
+		}
+		if errors.Is(err, user.ErrorRoleNotFound) {
+			operate.SendError(w, ErrorRoleNotFound, http.StatusBadRequest, l)
+			return
+		}
 		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
 		l.Error(errors.Wrapf(err, "can't create user"))
 		return
 	}
 
-	operate.SendStatus(w, http.StatusCreated, &response.User{
-		ID:    createdUser.ID,
-		Login: createdUser.Login,
-		Role:  string(createdUser.Role),
-	}, l)
+	operate.SendStatus(w, http.StatusCreated, response.FromRepositoryUser(createdUser), l)
 }
 
 // DeleteUser
 //
 //	@Summary		Удаление пользователя.
-//	@Description	Удаляет пользователя по его id.
+//	@Description	Удаляет пользователя по его id. Удаление собственной учётной записи нужно подтвердить параметром confirm=true. Последнего активного администратора удалить нельзя.
 //	@Tags			user
 //	@Param			user_id	path	uint64	true	"Уникальный идентификатор пользователя"
+//	@Param			confirm	query	bool	false	"Подтверждение удаления собственной учётной записи"
 //	@Produce		json
 //	@Success		200	"Пользователь успешно удалён"
 //	@Failure		400	{object}	operate.ModelError	"В теле запроса ошибка"
 //	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
-//	@Failure		403	{object}	operate.ModelError	"У пользователя нет прав на удаление пользователя"
+//	@Failure		403	{object}	operate.ModelError	"У пользователя нет прав на удаление пользователя или у удаляемого пользователя больше прав"
 //	@Failure		404	{object}	operate.ModelError	"Пользователь с указанным id не найден"
+//	@Failure		409	{object}	operate.ModelError	"Удаление не подтверждено или пользователь последний активный администратор"
 //	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
 //	@Router			/user/{user_id} [delete]
 //	@Security		sessionCookie
 func (uh *UserHandlers) DeleteUser(w http.ResponseWriter, r *http.Request, params mux.Params) {
 	l := middleware.GetLogger(r)
 
-	// Проверка доступа
-	if usr := middleware.GetUser(r); usr == nil || usr.Role != types.ADMIN {
-		operate.SendError(w, ErrorUserNotPermitted, http.StatusForbidden, l)
-		return
-	}
-
 	// Получение уникального идентификатора
 	id, err := params.GetUint64(UserIdField)
 	if err != nil {
@@ -128,45 +161,213 @@ func (uh *UserHandlers) DeleteUser(w http.ResponseWriter, r *http.Request, param
 		return
 	}
 
-	if err = uh.repository.DeleteUser(types.Id(id)); err != nil {
+	confirmed, err := parseConfirm(r)
+	if err != nil {
+		operate.SendError(w, ErrorIncorrectQueryParam, http.StatusBadRequest, l)
+		l.Warn(err)
+		return
+	}
+
+	if code, err := uh.checkManageTarget(r, types.Id(id), l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	if err = uh.accounts.Delete(middleware.GetUser(r), types.Id(id), confirmed); err != nil {
 		if errors.Is(err, user.ErrorUserNotFound) {
 			operate.SendError(w, ErrorUserNotFound, http.StatusNotFound, l)
 			return
 		}
+		if code, err := checkAccountsError(err, l); err != nil {
+			operate.SendError(w, err, code, l)
+			return
+		}
 		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
 		l.Error(errors.Wrapf(err, "can't delete user"))
 		return
 	}
 
+	// Сессии удалённого пользователя завершаются
+	uh.revokeUserSessions(types.Id(id), l)
+
 	operate.SendStatus(w, http.StatusOK, nil, l)
 }
 
 // UpdateUserRole
 //
 //	@Summary		Обновление роли пользователя.
-//	@Description	Обновляет пользовательскую роль.
+//	@Description	Обновляет пользовательскую роль. Нельзя выдать роль с правами, которых нет у текущего пользователя. Смену собственной роли нужно подтвердить параметром confirm=true. Последний активный администратор не может лишиться роли 'admin'.
 //	@Tags			user
 //	@Accept			json
 //	@Param			user_id	path	uint64				true	"Уникальный идентификатор пользователя"
+//	@Param			confirm	query	bool				false	"Подтверждение смены собственной роли"
 //	@Param			request	body	request.UpdateRole	true	"Информация о добавляемом пользователе"
 //	@Produce		json
 //	@Success		200	{object}	response.User		"Роль пользователя успешно обновлена"
-//	@Failure		400	{object}	operate.ModelError	"В теле запроса ошибка"
+//	@Failure		400	{object}	operate.ModelError	"В теле запроса ошибка или роль не найдена"
 //	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
-//	@Failure		403	{object}	operate.ModelError	"У пользователя нет прав на создание пользователя"
+//	@Failure		403	{object}	operate.ModelError	"У пользователя нет прав на изменение роли, выдачу этой роли или у изменяемого пользователя больше прав"
 //	@Failure		404	{object}	operate.ModelError	"Пользователь с указанным id не найден"
+//	@Failure		409	{object}	operate.ModelError	"Смена своей роли не подтверждена или пользователь последний активный администратор"
 //	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
 //	@Router			/user/{user_id}/role [put]
 //	@Security		sessionCookie
 func (uh *UserHandlers) UpdateUserRole(w http.ResponseWriter, r *http.Request, params mux.Params) {
 	l := middleware.GetLogger(r)
 
-	// Проверка доступа
-	if usr := middleware.GetUser(r); usr == nil || usr.Role != types.ADMIN {
-		operate.SendError(w, ErrorUserNotPermitted, http.StatusForbidden, l)
+	// Получение уникального идентификатора
+	id, err := params.GetUint64(UserIdField)
+	if err != nil {
+		operate.SendError(w, errors.Wrapf(err, "try get user id"), http.StatusBadRequest, l)
+		return
+	}
+
+	confirmed, err := parseConfirm(r)
+	if err != nil {
+		operate.SendError(w, ErrorIncorrectQueryParam, http.StatusBadRequest, l)
+		l.Warn(err)
+		return
+	}
+
+	// Получение значения тела запроса
+	var updateRole request.UpdateRole
+	if code, err := parseRequestBody(r.Body, &updateRole, request.ValidateUpdateRole, l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	if code, err := uh.checkRoleGrant(r, types.Roles(updateRole.Role), l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	if code, err := uh.checkManageTarget(r, types.Id(id), l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	updatedUser, err := uh.accounts.UpdateRole(middleware.GetUser(r), types.Id(id), types.Roles(updateRole.Role),
+		confirmed)
+
+	if err != nil {
+		if errors.Is(err, user.ErrorUserNotFound) {
+			operate.SendError(w, ErrorUserNotFound, http.StatusNotFound, l)
+			return
+		}
+		if errors.Is(err, user.ErrorRoleNotFound) {
+			operate.SendError(w, ErrorRoleNotFound, http.StatusBadRequest, l)
+			return
+		}
+		if code, err := checkAccountsError(err, l); err != nil {
+			operate.SendError(w, err, code, l)
+			return
+		}
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't update user"))
 		return
 	}
 
+	// После смены роли пользователь должен авторизоваться заново
+	uh.revokeUserSessions(updatedUser.ID, l)
+
+	operate.SendStatus(w, http.StatusOK, response.FromRepositoryUser(updatedUser), l)
+}
+
+// parseConfirm
+// Читает из параметров запроса подтверждение изменения собственной учётной записи
+func parseConfirm(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get(ConfirmKey)
+	if value == "" {
+		return false, nil
+	}
+
+	confirmed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.Wrapf(ErrorIncorrectQueryParam, "with field %s and value %s", ConfirmKey, value)
+	}
+
+	return confirmed, nil
+}
+
+// checkAccountsError
+// Сопоставляет нарушения правил учётных записей ответам с кодом 409
+func checkAccountsError(err error, l logger.Interface) (int, error) {
+	if errors.Is(err, accounts.ErrorLastAdmin) {
+		l.Warn(err)
+		return http.StatusConflict, ErrorLastAdmin
+	}
+	if errors.Is(err, accounts.ErrorConfirmationRequired) {
+		return http.StatusConflict, ErrorConfirmationRequired
+	}
+	return http.StatusOK, nil
+}
+
+// checkRoleGrant
+// Проверяет, что роль существует и текущий пользователь не выдаёт прав, которых нет у него самого
+func (uh *UserHandlers) checkRoleGrant(r *http.Request, name types.Roles, l logger.Interface) (int, error) {
+	grantedRole, err := uh.roles.GetRole(name)
+	if err != nil {
+		if errors.Is(err, role.ErrorRoleNotFound) {
+			return http.StatusBadRequest, ErrorRoleNotFound
+		}
+		l.Error(errors.Wrapf(err, "can't get role %s", name))
+		return http.StatusInternalServerError, ErrorUnknownError
+	}
+
+	usr := middleware.GetUser(r)
+	for _, permission := range grantedRole.Permissions {
+		if !usr.HasPermission(permission) {
+			l.Warn("[Security] user %d tried to grant role %s with permission %s", usr.ID, name, permission)
+			return http.StatusForbidden, ErrorRoleGrantNotPermitted
+		}
+	}
+
+	return http.StatusOK, nil
+}
+
+// checkManageTarget
+// Проверяет, что пользователь существует и у него нет прав, которых нет у текущего пользователя
+func (uh *UserHandlers) checkManageTarget(r *http.Request, id types.Id, l logger.Interface) (int, error) {
+	target, err := uh.repository.GetUserById(id)
+	if err != nil {
+		if errors.Is(err, user.ErrorUserNotFound) {
+			return http.StatusNotFound, ErrorUserNotFound
+		}
+		l.Error(errors.Wrapf(err, "can't get user %d", id))
+		return http.StatusInternalServerError, ErrorUnknownError
+	}
+
+	usr := middleware.GetUser(r)
+	for _, permission := range target.Permissions {
+		if !usr.HasPermission(permission) {
+			l.Warn("[Security] user %d tried to manage user %d with permission %s", usr.ID, id, permission)
+			return http.StatusForbidden, ErrorUserManageNotPermitted
+		}
+	}
+
+	return http.StatusOK, nil
+}
+
+// UpdateUserMaxCertification
+//
+//	@Summary		Обновление ограничения возрастного рейтинга пользователя.
+//	@Description	Задаёт максимальный возрастной рейтинг фильмов, доступных пользователю. Фильмы с более строгим или неизвестным рейтингом скрываются из списков фильмов, фильмографий актёров и статистики фильмов без актёров. Значение null или отсутствие поля снимает ограничение. Изменять ограничение может только администратор.
+//	@Tags			user
+//	@Accept			json
+//	@Param			user_id	path	uint64							true	"Уникальный идентификатор пользователя"
+//	@Param			request	body	request.UpdateMaxCertification	true	"Максимальный возрастной рейтинг"
+//	@Produce		json
+//	@Success		200	{object}	response.User		"Ограничение пользователя успешно обновлено"
+//	@Failure		400	{object}	operate.ModelError	"В теле запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		403	{object}	operate.ModelError	"У пользователя нет прав на изменение ограничения или у изменяемого пользователя больше прав"
+//	@Failure		404	{object}	operate.ModelError	"Пользователь с указанным id не найден"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/user/{user_id}/certification [put]
+//	@Security		sessionCookie
+func (uh *UserHandlers) UpdateUserMaxCertification(w http.ResponseWriter, r *http.Request, params mux.Params) {
+	l := middleware.GetLogger(r)
+
 	// Получение уникального идентификатора
 	id, err := params.GetUint64(UserIdField)
 	if err != nil {
@@ -175,15 +376,20 @@ func (uh *UserHandlers) UpdateUserRole(w http.ResponseWriter, r *http.Request, p
 	}
 
 	// Получение значения тела запроса
-	var updateRole request.UpdateRole
-	if code, err := parseRequestBody(r.Body, &updateRole, request.ValidateUpdateRole, l); err != nil {
+	var updateCertification request.UpdateMaxCertification
+	if code, err := parseRequestBody(r.Body, &updateCertification, request.ValidateUpdateMaxCertification, l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	if code, err := uh.checkManageTarget(r, types.Id(id), l); err != nil {
 		operate.SendError(w, err, code, l)
 		return
 	}
 
-	updatedUser, err := uh.repository.UpdateUserRole(&user.User{
-		ID:   types.Id(id),
-		Role: types.Roles(updateRole.Role),
+	updatedUser, err := uh.repository.UpdateUserMaxCertification(&user.User{
+		ID:               types.Id(id),
+		MaxCertification: updateCertification.MaxCertification,
 	})
 
 	if err != nil {
@@ -192,25 +398,25 @@ func (uh *UserHandlers) UpdateUserRole(w http.ResponseWriter, r *http.Request, p
 			return
 		}
 		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
-		l.Error(errors.Wrapf(err, "can't update user"))
+		l.Error(errors.Wrapf(err, "can't update user max certification"))
 		return
 	}
 
-	operate.SendStatus(w, http.StatusOK, &response.User{
-		ID:    updatedUser.ID,
-		Login: updatedUser.Login,
-		Role:  string(updatedUser.Role),
-	}, l)
+	// Сессии пользователя остаются, но закешированные данные пользователя устарели
+	uh.auth.InvalidateUser(updatedUser.ID)
+
+	operate.SendStatus(w, http.StatusOK, response.FromRepositoryUser(updatedUser), l)
 }
 
 // GetUsers
 //
 //	@Summary		Получение списка пользователей.
-//	@Description	Возвращает список пользователей системы.
+//	@Description	Возвращает список пользователей системы вместе со статусом учётных записей и возрастными ограничениями. Доступно только пользователям с правом 'user:manage'.
 //	@Tags			user
 //	@Produce		json
 //	@Success		200	{array}		response.User		"Список пользователей успешно сформирован"
 //	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		403	{object}	operate.ModelError	"У пользователя нет прав на просмотр пользователей"
 //	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
 //	@Router			/user/list [get]
 //	@Security		sessionCookie
@@ -225,96 +431,801 @@ func (uh *UserHandlers) GetUsers(w http.ResponseWriter, r *http.Request, _ mux.P
 	}
 
 	operate.SendStatus(w, http.StatusOK, slices.Map(users, func(usr user.User) response.User {
-		return response.User{
-			ID:    usr.ID,
-			Login: usr.Login,
-			Role:  string(usr.Role),
-		}
+		return response.FromRepositoryUser(&usr)
 	}), l)
 }
 
-// Login
+// GetMe
 //
-//	@Summary		Авторизация.
-//	@Description	Авторизация пользователя в системе.
+//	@Summary		Получение текущего пользователя.
+//	@Description	Возвращает информацию об авторизованном пользователе.
+//	@Tags			user
+//	@Produce		json
+//	@Success		200	{object}	response.User		"Информация о пользователе успешно получена"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Router			/user/me [get]
+//	@Security		sessionCookie
+func (uh *UserHandlers) GetMe(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	usr := middleware.GetUser(r)
+	if usr == nil {
+		operate.SendError(w, ErrorUserNotAuthorized, http.StatusUnauthorized, l)
+		return
+	}
+
+	operate.SendStatus(w, http.StatusOK, response.FromRepositoryUser(usr), l)
+}
+
+// ChangePassword
+//
+//	@Summary		Смена пароля текущего пользователя.
+//	@Description	Меняет пароль авторизованного пользователя. Требуется указать текущий пароль, новый пароль должен соответствовать политике паролей. Все остальные сессии пользователя завершаются.
 //	@Tags			user
 //	@Accept			json
-//	@Param			request	body	request.Login	true	"Логин и пароль пользователя"
+//	@Param			request	body	request.ChangePassword	true	"Текущий и новый пароли"
 //	@Produce		json
-//	@Success		200	"Пользователь успешно авторизован"
-//	@Header			200	{string}	Set-Cookie			"Устанавливает сессию текущего пользователя"
-//	@Failure		400	{object}	operate.ModelError	"В теле запроса ошибка"
-//	@Failure		409	{object}	operate.ModelError	"Неверный логин или пароль"
-//	@Failure		418	{object}	operate.ModelError	"Пользователь уже авторизован"
+//	@Success		200	"Пароль успешно изменён"
+//	@Failure		400	{object}	operate.ModelError	"В теле запроса ошибка или пароль не соответствует политике"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		409	{object}	operate.ModelError	"Неверный текущий пароль"
 //	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
-//	@Router			/login [post]
-func (uh *UserHandlers) Login(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+//	@Router			/user/me/password [put]
+//	@Security		sessionCookie
+func (uh *UserHandlers) ChangePassword(w http.ResponseWriter, r *http.Request, _ mux.Params) {
 	l := middleware.GetLogger(r)
 
+	usr, sessionId := middleware.GetUser(r), middleware.GetSession(r)
+	if usr == nil || sessionId == nil {
+		operate.SendError(w, ErrorUserNotAuthorized, http.StatusUnauthorized, l)
+		return
+	}
+
 	// Получение значения тела запроса
-	var login request.Login
-	if code, err := parseRequestBody(r.Body, &login, request.ValidateLogin, l); err != nil {
+	var changePassword request.ChangePassword
+	if code, err := parseRequestBody(r.Body, &changePassword, request.ValidateChangePassword, l); err != nil {
 		operate.SendError(w, err, code, l)
 		return
 	}
 
-	// Проверка верности логина и пароля
-	sessionId, err := uh.auth.Login(login.Login, login.Password)
+	err := uh.auth.ChangePassword(usr.ID, *sessionId, changePassword.CurrentPassword,
+		changePassword.NewPassword, clientInfo(r))
 	if err != nil {
-		if errors.Is(err, auth.ErrorIncorrectPassword) || errors.Is(err, user.ErrorUserNotFound) {
-			operate.SendError(w, ErrorIncorrectLoginOrPassword, http.StatusConflict, l)
-			l.Info(errors.Wrapf(err, "inccorect login info"))
-		} else {
-			w.WriteHeader(http.StatusInternalServerError)
-			l.Error(errors.Wrapf(err, "can't check login info"))
+		if errors.Is(err, auth.ErrorIncorrectPassword) {
+			operate.SendError(w, ErrorIncorrectPassword, http.StatusConflict, l)
+			l.Info(errors.Wrapf(err, "incorrect current password"))
+			return
+		}
+		if errors.Is(err, auth.ErrorPasswordPolicy) {
+			operate.SendError(w, err, http.StatusBadRequest, l)
+			return
 		}
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't change password"))
 		return
 	}
 
-	cookie := &http.Cookie{
-		Name:     string(middleware.SessionField),
-		Value:    sessionId,
-		Path:     "/",
-		Expires:  time.Now().Add(auth.ExpiredSessionTime),
-		HttpOnly: true,
-	}
-	http.SetCookie(w, cookie)
 	operate.SendStatus(w, http.StatusOK, nil, l)
 }
 
-// Logout
+// UpdateEmail
 //
-//	@Summary		Выход из системы.
-//	@Description	Позволяет выйти пользователю из системы.
+//	@Summary		Смена почты текущего пользователя.
+//	@Description	Меняет адрес почты авторизованного пользователя, на который отправляются письма для сброса пароля. Требуется указать текущий пароль. Пустой адрес удаляет почту.
 //	@Tags			user
+//	@Accept			json
+//	@Param			request	body	request.UpdateEmail	true	"Текущий пароль и новый адрес почты"
 //	@Produce		json
-//	@Success		200	"Пользователь успешно вышел из системы"
+//	@Success		200	"Почта успешно изменена"
+//	@Failure		400	{object}	operate.ModelError	"В теле запроса ошибка"
 //	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
-//	@Router			/logout [post]
+//	@Failure		409	{object}	operate.ModelError	"Неверный текущий пароль или почта уже используется другим пользователем"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/user/me/email [put]
 //	@Security		sessionCookie
-func (uh *UserHandlers) Logout(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+func (uh *UserHandlers) UpdateEmail(w http.ResponseWriter, r *http.Request, _ mux.Params) {
 	l := middleware.GetLogger(r)
 
-	sessionId := middleware.GetSession(r)
-	if sessionId != nil {
-		// Уничтожение сессии
-		if err := uh.auth.Logout(*sessionId); err != nil {
-			l.Warn(errors.Wrapf(err, "try delete session id %s", *sessionId))
-		} else {
-			l.Info("logged out")
+	usr := middleware.GetUser(r)
+	if usr == nil {
+		operate.SendError(w, ErrorUserNotAuthorized, http.StatusUnauthorized, l)
+		return
+	}
+
+	// Получение значения тела запроса
+	var updateEmail request.UpdateEmail
+	if code, err := parseRequestBody(r.Body, &updateEmail, request.ValidateUpdateEmail, l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	// Почта позволяет сбросить пароль, поэтому её смена требует знания текущего пароля
+	if err := uh.auth.VerifyPassword(usr.ID, updateEmail.CurrentPassword); err != nil {
+		if errors.Is(err, auth.ErrorIncorrectPassword) {
+			operate.SendError(w, ErrorIncorrectPassword, http.StatusConflict, l)
+			l.Info(errors.Wrapf(err, "incorrect current password"))
+			return
 		}
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't verify password"))
+		return
+	}
 
-		cookie := &http.Cookie{
-			Name:     string(middleware.SessionField),
-			Value:    "",
-			Path:     "/",
-			Expires:  time.Now().AddDate(0, 0, -1),
-			HttpOnly: true,
+	if err := uh.repository.UpdateUserEmail(usr.ID, strings.ToLower(updateEmail.Email)); err != nil {
+		if errors.Is(err, user.ErrorEmailAlreadyExists) {
+			operate.SendError(w, ErrorEmailAlreadyExists, http.StatusConflict, l)
+			l.Info(errors.Wrapf(err, "can't update email"))
+			return
 		}
-		http.SetCookie(w, cookie)
-		operate.SendStatus(w, http.StatusOK, nil, l)
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't update email"))
+		return
+	}
+
+	uh.auth.InvalidateUser(usr.ID)
+
+	l.Info("[Security] user %d changed email", usr.ID)
+	operate.SendStatus(w, http.StatusOK, nil, l)
+}
+
+// ResetUserPassword
+//
+//	@Summary		Сброс пароля пользователя.
+//	@Description	Устанавливает пользователю новый пароль, соответствующий политике паролей. Все сессии пользователя завершаются.
+//	@Tags			user
+//	@Accept			json
+//	@Param			user_id	path	uint64					true	"Уникальный идентификатор пользователя"
+//	@Param			request	body	request.ResetPassword	true	"Новый пароль"
+//	@Produce		json
+//	@Success		200	"Пароль успешно сброшен"
+//	@Failure		400	{object}	operate.ModelError	"В теле запроса ошибка или пароль не соответствует политике"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		403	{object}	operate.ModelError	"У пользователя нет прав на сброс пароля или у изменяемого пользователя больше прав"
+//	@Failure		404	{object}	operate.ModelError	"Пользователь с указанным id не найден"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/user/{user_id}/password [put]
+//	@Security		sessionCookie
+func (uh *UserHandlers) ResetUserPassword(w http.ResponseWriter, r *http.Request, params mux.Params) {
+	l := middleware.GetLogger(r)
+
+	// Получение уникального идентификатора
+	id, err := params.GetUint64(UserIdField)
+	if err != nil {
+		operate.SendError(w, errors.Wrapf(err, "try get user id"), http.StatusBadRequest, l)
+		return
+	}
+
+	// Получение значения тела запроса
+	var resetPassword request.ResetPassword
+	if code, err := parseRequestBody(r.Body, &resetPassword, request.ValidateResetPassword, l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	if code, err := uh.checkManageTarget(r, types.Id(id), l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	if err := uh.auth.ResetPassword(types.Id(id), resetPassword.Password, clientInfo(r)); err != nil {
+		if errors.Is(err, user.ErrorUserNotFound) {
+			operate.SendError(w, ErrorUserNotFound, http.StatusNotFound, l)
+			return
+		}
+		if errors.Is(err, auth.ErrorPasswordPolicy) {
+			operate.SendError(w, err, http.StatusBadRequest, l)
+			return
+		}
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't reset user password"))
+		return
+	}
+
+	operate.SendStatus(w, http.StatusOK, nil, l)
+}
+
+// UnlockUser
+//
+//	@Summary		Снятие блокировки входа пользователя.
+//	@Description	Сбрасывает счётчик неудачных попыток входа и снимает временную блокировку логина пользователя.
+//	@Tags			user
+//	@Param			user_id	path	uint64	true	"Уникальный идентификатор пользователя"
+//	@Produce		json
+//	@Success		200	"Блокировка успешно снята"
+//	@Failure		400	{object}	operate.ModelError	"В параметрах запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		403	{object}	operate.ModelError	"У пользователя нет прав на снятие блокировки или у пользователя больше прав"
+//	@Failure		404	{object}	operate.ModelError	"Пользователь с указанным id не найден"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/user/{user_id}/lock [delete]
+//	@Security		sessionCookie
+func (uh *UserHandlers) UnlockUser(w http.ResponseWriter, r *http.Request, params mux.Params) {
+	l := middleware.GetLogger(r)
 
+	// Получение уникального идентификатора
+	id, err := params.GetUint64(UserIdField)
+	if err != nil {
+		operate.SendError(w, errors.Wrapf(err, "try get user id"), http.StatusBadRequest, l)
+		return
+	}
+
+	if code, err := uh.checkManageTarget(r, types.Id(id), l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	if err := uh.auth.UnlockUser(types.Id(id)); err != nil {
+		if errors.Is(err, user.ErrorUserNotFound) {
+			operate.SendError(w, ErrorUserNotFound, http.StatusNotFound, l)
+			return
+		}
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't unlock user"))
+		return
+	}
+
+	l.Warn("[Security] login lock of user %d is removed by user %d", id, middleware.GetUser(r).ID)
+	operate.SendStatus(w, http.StatusOK, nil, l)
+}
+
+// CreateToken
+//
+//	@Summary		Создание персонального токена.
+//	@Description	Создаёт именованный персональный токен текущего пользователя для доступа к API через заголовок `Authorization: Bearer <token>`. Секрет токена возвращается только один раз. Токен только для чтения разрешает лишь GET-запросы.
+//	@Tags			user
+//	@Accept			json
+//	@Param			request	body	request.CreateToken	true	"Название, область и срок действия токена"
+//	@Produce		json
+//	@Success		201	{object}	response.CreatedToken	"Токен успешно создан"
+//	@Failure		400	{object}	operate.ModelError		"В теле запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError		"Пользователь не авторизован"
+//	@Failure		409	{object}	operate.ModelError		"Токен с таким же названием уже существует"
+//	@Failure		500	{object}	operate.ModelError		"Ошибка сервера"
+//	@Router			/user/me/tokens [post]
+//	@Security		sessionCookie
+//	@Security		bearerToken
+func (uh *UserHandlers) CreateToken(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	usr := middleware.GetUser(r)
+	if usr == nil {
+		operate.SendError(w, ErrorUserNotAuthorized, http.StatusUnauthorized, l)
+		return
+	}
+
+	// Получение значения тела запроса
+	var createToken request.CreateToken
+	if code, err := parseRequestBody(r.Body, &createToken, request.ValidateCreateToken, l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	if createToken.ExpiresAt != nil && !createToken.ExpiresAt.After(time.Now()) {
+		operate.SendError(w, ErrorTokenExpiresInPast, http.StatusBadRequest, l)
+		return
+	}
+
+	secret, tkn, err := uh.auth.CreateToken(&token.Token{
+		UserID:    usr.ID,
+		Name:      createToken.Name,
+		ReadOnly:  createToken.ReadOnly,
+		ExpiresAt: createToken.ExpiresAt,
+	})
+	if err != nil {
+		if errors.Is(err, token.ErrorTokenNameAlreadyExists) {
+			operate.SendError(w, ErrorTokenAlreadyExists, http.StatusConflict, l)
+			return
+		}
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't create token"))
+		return
+	}
+
+	l.Info("[Security] api token %d is created by user %d", tkn.ID, usr.ID)
+	operate.SendStatus(w, http.StatusCreated, response.CreatedToken{
+		Token:  response.FromRepositoryToken(tkn),
+		Secret: secret,
+	}, l)
+}
+
+// GetTokens
+//
+//	@Summary		Получение списка персональных токенов.
+//	@Description	Возвращает персональные токены текущего пользователя вместе со временем их последнего использования. Секреты токенов не возвращаются.
+//	@Tags			user
+//	@Produce		json
+//	@Success		200	{array}		response.Token		"Токены успешно получены"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/user/me/tokens [get]
+//	@Security		sessionCookie
+//	@Security		bearerToken
+func (uh *UserHandlers) GetTokens(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	usr := middleware.GetUser(r)
+	if usr == nil {
+		operate.SendError(w, ErrorUserNotAuthorized, http.StatusUnauthorized, l)
+		return
+	}
+
+	tokens, err := uh.auth.GetTokens(usr.ID)
+	if err != nil {
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't get tokens"))
+		return
+	}
+
+	operate.SendStatus(w, http.StatusOK, slices.Map(tokens, func(tkn token.Token) response.Token {
+		return response.FromRepositoryToken(&tkn)
+	}), l)
+}
+
+// RevokeToken
+//
+//	@Summary		Отзыв персонального токена.
+//	@Description	Удаляет персональный токен текущего пользователя, после чего токен перестаёт приниматься.
+//	@Tags			user
+//	@Param			token_id	path	uint64	true	"Уникальный идентификатор токена"
+//	@Produce		json
+//	@Success		200	"Токен успешно отозван"
+//	@Failure		400	{object}	operate.ModelError	"В параметрах запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		404	{object}	operate.ModelError	"Токен с указанным id не найден"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/user/me/tokens/{token_id} [delete]
+//	@Security		sessionCookie
+//	@Security		bearerToken
+func (uh *UserHandlers) RevokeToken(w http.ResponseWriter, r *http.Request, params mux.Params) {
+	l := middleware.GetLogger(r)
+
+	usr := middleware.GetUser(r)
+	if usr == nil {
+		operate.SendError(w, ErrorUserNotAuthorized, http.StatusUnauthorized, l)
+		return
+	}
+
+	// Получение уникального идентификатора
+	id, err := params.GetUint64(TokenIdField)
+	if err != nil {
+		operate.SendError(w, errors.Wrapf(err, "try get token id"), http.StatusBadRequest, l)
+		return
+	}
+
+	if err := uh.auth.RevokeToken(usr.ID, types.Id(id)); err != nil {
+		if errors.Is(err, token.ErrorTokenNotFound) {
+			operate.SendError(w, ErrorTokenNotFound, http.StatusNotFound, l)
+			return
+		}
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't revoke token"))
+		return
+	}
+
+	l.Info("[Security] api token %d is revoked by user %d", id, usr.ID)
+	operate.SendStatus(w, http.StatusOK, nil, l)
+}
+
+// GetSessions
+//
+//	@Summary		Получение списка активных сессий.
+//	@Description	Возвращает активные сессии текущего пользователя со временем создания и последней активности, адресом клиента и его User-Agent. Сессия, которой авторизован запрос, отмечена полем current.
+//	@Tags			user
+//	@Produce		json
+//	@Success		200	{array}		response.Session	"Сессии успешно получены"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/user/me/sessions [get]
+//	@Security		sessionCookie
+//	@Security		bearerToken
+func (uh *UserHandlers) GetSessions(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	usr := middleware.GetUser(r)
+	if usr == nil {
+		operate.SendError(w, ErrorUserNotAuthorized, http.StatusUnauthorized, l)
+		return
+	}
+
+	// Запрос может быть авторизован персональным токеном, тогда текущей сессии нет
+	currentSessionId := ""
+	if sessionId := middleware.GetSession(r); sessionId != nil {
+		currentSessionId = *sessionId
+	}
+
+	sessions, err := uh.auth.GetSessions(usr.ID, currentSessionId)
+	if err != nil {
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't get sessions"))
+		return
+	}
+
+	operate.SendStatus(w, http.StatusOK, slices.Map(sessions, func(ses session.Session) response.Session {
+		return response.FromRepositorySession(&ses)
+	}), l)
+}
+
+// RevokeSession
+//
+//	@Summary		Завершение сессии.
+//	@Description	Завершает сессию текущего пользователя по её идентификатору из списка сессий.
+//	@Tags			user
+//	@Param			session_id	path	string	true	"Идентификатор сессии"
+//	@Produce		json
+//	@Success		200	"Сессия успешно завершена"
+//	@Failure		400	{object}	operate.ModelError	"В параметрах запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		404	{object}	operate.ModelError	"Сессия с указанным id не найдена"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/user/me/sessions/{session_id} [delete]
+//	@Security		sessionCookie
+//	@Security		bearerToken
+func (uh *UserHandlers) RevokeSession(w http.ResponseWriter, r *http.Request, params mux.Params) {
+	l := middleware.GetLogger(r)
+
+	usr := middleware.GetUser(r)
+	if usr == nil {
+		operate.SendError(w, ErrorUserNotAuthorized, http.StatusUnauthorized, l)
+		return
+	}
+
+	// Получение идентификатора сессии
+	id, err := params.GetString(SessionIdField)
+	if err != nil {
+		operate.SendError(w, errors.Wrapf(err, "try get session id"), http.StatusBadRequest, l)
+		return
+	}
+
+	if err := uh.auth.RevokeSession(usr.ID, id); err != nil {
+		if errors.Is(err, session.ErrorNoSession) {
+			operate.SendError(w, ErrorSessionNotFound, http.StatusNotFound, l)
+			return
+		}
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't revoke session"))
+		return
+	}
+
+	l.Info("[Security] session %s is revoked by user %d", id, usr.ID)
+	operate.SendStatus(w, http.StatusOK, nil, l)
+}
+
+// RevokeUserSessions
+//
+//	@Summary		Завершение всех сессий пользователя.
+//	@Description	Завершает все сессии пользователя по его id. Персональные токены пользователя не отзываются.
+//	@Tags			user
+//	@Param			user_id	path	uint64	true	"Уникальный идентификатор пользователя"
+//	@Produce		json
+//	@Success		200	"Сессии успешно завершены"
+//	@Failure		400	{object}	operate.ModelError	"В параметрах запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Failure		403	{object}	operate.ModelError	"У пользователя нет прав на завершение сессий или у пользователя больше прав"
+//	@Failure		404	{object}	operate.ModelError	"Пользователь с указанным id не найден"
+//	@Failure		500	{object}	operate.ModelError	"Ошибка сервера"
+//	@Router			/user/{user_id}/sessions [delete]
+//	@Security		sessionCookie
+func (uh *UserHandlers) RevokeUserSessions(w http.ResponseWriter, r *http.Request, params mux.Params) {
+	l := middleware.GetLogger(r)
+
+	// Получение уникального идентификатора
+	id, err := params.GetUint64(UserIdField)
+	if err != nil {
+		operate.SendError(w, errors.Wrapf(err, "try get user id"), http.StatusBadRequest, l)
+		return
+	}
+
+	if code, err := uh.checkManageTarget(r, types.Id(id), l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	if err := uh.auth.RevokeUserSessions(types.Id(id)); err != nil {
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't revoke user sessions"))
+		return
+	}
+
+	l.Warn("[Security] all sessions of user %d are revoked by user %d", id, middleware.GetUser(r).ID)
+	operate.SendStatus(w, http.StatusOK, nil, l)
+}
+
+// revokeUserSessions
+// Завершает сессии пользователя после изменения его прав. Ошибка не прерывает запрос, так как изменение уже сохранено
+func (uh *UserHandlers) revokeUserSessions(userId types.Id, l logger.Interface) {
+	if err := uh.auth.RevokeUserSessions(userId); err != nil {
+		l.Error(errors.Wrapf(err, "can't revoke sessions of user %d", userId))
+		return
+	}
+
+	l.Info("[Security] all sessions of user %d are revoked", userId)
+}
+
+// parseEventsFilter
+// Получает фильтр событий безопасности из параметров запроса. Фильтры по пользователю
+// и адресу клиента доступны только администраторам
+func parseEventsFilter(values url.Values, admin bool) (event.Filter, error) {
+	var filter event.Filter
+	var err error
+
+	for _, value := range values[EventTypeKey] {
+		eventType := event.Type(value)
+		if !eventType.IsValid() {
+			return filter, errors.Wrapf(ErrorIncorrectQueryParam, "with field %s and value %s", EventTypeKey, value)
+		}
+		filter.Types = append(filter.Types, eventType)
+	}
+
+	if filter.From, err = parseTimeParam(values, FromKey); err != nil {
+		return filter, err
+	}
+
+	if filter.To, err = parseTimeParam(values, ToKey); err != nil {
+		return filter, err
+	}
+
+	if filter.Limit, err = parseLimitParam(values, DefaultEventsLimit, MaxEventsLimit); err != nil {
+		return filter, err
+	}
+
+	if filter.Offset, err = parseUintParam(values, OffsetKey, 0); err != nil {
+		return filter, err
+	}
+
+	if !admin {
+		return filter, nil
+	}
+
+	userId, err := parseUintParam(values, UserIdKey, 0)
+	if err != nil {
+		return filter, err
+	}
+	filter.UserID = types.Id(userId)
+	filter.IP = values.Get(IPKey)
+
+	return filter, nil
+}
+
+// GetSecurityEvents
+//
+//	@Summary		Получение журнала событий безопасности.
+//	@Description	Возвращает события безопасности текущего пользователя от новых к старым: успешные и неудачные входы, выходы, смены и сбросы пароля, блокировки входа. Для каждого события указаны адрес клиента и его User-Agent. События старше срока хранения удаляются.
+//	@Tags			user
+//	@Param			type	query	[]string	false	"Тип события. Можно указать несколько значений."	collectionFormat(multi)	Enums(login_succeeded, login_failed, logout, password_changed, password_reset, lockout)
+//	@Param			from	query	string		false	"Начало периода в формате RFC 3339 включительно"	format(date-time)
+//	@Param			to		query	string		false	"Конец периода в формате RFC 3339 не включительно"	format(date-time)
+//	@Param			limit	query	int			false	"Количество событий"								minimum(1)	maximum(500)	default(50)
+//	@Param			offset	query	int			false	"Количество пропускаемых событий"					minimum(0)	default(0)
+//	@Produce		json
+//	@Success		200	{array}		response.SecurityEvent	"События успешно получены"
+//	@Failure		400	{object}	operate.ModelError		"В параметрах запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError		"Пользователь не авторизован"
+//	@Failure		500	{object}	operate.ModelError		"Ошибка сервера"
+//	@Router			/user/me/security-events [get]
+//	@Security		sessionCookie
+//	@Security		bearerToken
+func (uh *UserHandlers) GetSecurityEvents(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	usr := middleware.GetUser(r)
+	if usr == nil {
+		operate.SendError(w, ErrorUserNotAuthorized, http.StatusUnauthorized, l)
+		return
+	}
+
+	filter, err := parseEventsFilter(r.URL.Query(), false)
+	if err != nil {
+		operate.SendError(w, err, http.StatusBadRequest, l)
+		return
+	}
+	filter.UserID = usr.ID
+
+	uh.sendEvents(w, filter, l)
+}
+
+// GetUsersSecurityEvents
+//
+//	@Summary		Получение журнала событий безопасности всех пользователей.
+//	@Description	Возвращает события безопасности пользователей от новых к старым с фильтрами по пользователю, типу события, адресу клиента и периоду.
+//	@Tags			user
+//	@Param			user_id	query	int			false	"Уникальный идентификатор пользователя"
+//	@Param			type	query	[]string	false	"Тип события. Можно указать несколько значений."	collectionFormat(multi)	Enums(login_succeeded, login_failed, logout, password_changed, password_reset, lockout)
+//	@Param			ip		query	string		false	"Адрес клиента"
+//	@Param			from	query	string		false	"Начало периода в формате RFC 3339 включительно"	format(date-time)
+//	@Param			to		query	string		false	"Конец периода в формате RFC 3339 не включительно"	format(date-time)
+//	@Param			limit	query	int			false	"Количество событий"								minimum(1)	maximum(500)	default(50)
+//	@Param			offset	query	int			false	"Количество пропускаемых событий"					minimum(0)	default(0)
+//	@Produce		json
+//	@Success		200	{array}		response.SecurityEvent	"События успешно получены"
+//	@Failure		400	{object}	operate.ModelError		"В параметрах запроса ошибка"
+//	@Failure		401	{object}	operate.ModelError		"Пользователь не авторизован"
+//	@Failure		403	{object}	operate.ModelError		"У пользователя нет прав на просмотр событий"
+//	@Failure		500	{object}	operate.ModelError		"Ошибка сервера"
+//	@Router			/user/security-events [get]
+//	@Security		sessionCookie
+func (uh *UserHandlers) GetUsersSecurityEvents(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	filter, err := parseEventsFilter(r.URL.Query(), true)
+	if err != nil {
+		operate.SendError(w, err, http.StatusBadRequest, l)
+		return
+	}
+
+	uh.sendEvents(w, filter, l)
+}
+
+// sendEvents
+// Получает события безопасности по фильтру и отправляет их клиенту
+func (uh *UserHandlers) sendEvents(w http.ResponseWriter, filter event.Filter, l logger.Interface) {
+	events, err := uh.auth.GetEvents(filter)
+	if err != nil {
+		operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+		l.Error(errors.Wrapf(err, "can't get security events"))
+		return
+	}
+
+	operate.SendStatus(w, http.StatusOK, slices.Map(events, func(e event.Event) response.SecurityEvent {
+		return response.FromRepositoryEvent(&e)
+	}), l)
+}
+
+// Login
+//
+//	@Summary		Авторизация.
+//	@Description	Авторизация пользователя в системе. После нескольких неудачных попыток логин и адрес клиента временно блокируются, время блокировки растёт с каждой следующей неудачной попыткой. Если у пользователя подключена двухфакторная аутентификация, сессия выдаётся только после ввода кода в /login/2fa. С remember_me сессия живёт дольше, а cookie сохраняется после закрытия браузера; на сессии администраторов remember_me не действует.
+//	@Tags			user
+//	@Accept			json
+//	@Param			request	body	request.Login	true	"Логин и пароль пользователя"
+//	@Produce		json
+//	@Success		200	{object}	response.Credentials	"Пользователь успешно авторизован, токены возвращаются только в режиме jwt"
+//	@Header			200	{string}	Set-Cookie				"Устанавливает сессию текущего пользователя"
+//	@Success		202	{object}	response.PendingLogin	"Пароль верный, для входа нужен код второго фактора"
+//	@Failure		400	{object}	operate.ModelError		"В теле запроса ошибка"
+//	@Failure		403	{object}	operate.ModelError		"Учётная запись ожидает одобрения или отклонена"
+//	@Failure		409	{object}	operate.ModelError		"Неверный логин или пароль"
+//	@Failure		418	{object}	operate.ModelError		"Пользователь уже авторизован"
+//	@Failure		429	{object}	operate.ModelError		"Слишком много неудачных попыток входа, логин или адрес клиента временно заблокирован"
+//	@Header			429	{integer}	Retry-After				"Через сколько секунд можно повторить попытку"
+//	@Failure		500	{object}	operate.ModelError		"Ошибка сервера"
+//	@Router			/login [post]
+func (uh *UserHandlers) Login(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	// Получение значения тела запроса
+	var login request.Login
+	if code, err := parseRequestBody(r.Body, &login, request.ValidateLogin, l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	// Проверка верности логина и пароля
+	client := clientInfo(r)
+	client.RememberMe = login.RememberMe
+	credentials, err := uh.auth.Login(login.Login, login.Password, client)
+	if err != nil {
+		var lockout *auth.LockoutError
+		if errors.As(err, &lockout) {
+			w.Header().Set(RetryAfterHeader, retryAfterSeconds(lockout.RetryAfter))
+			operate.SendError(w, ErrorTooManyLoginAttempts, http.StatusTooManyRequests, l)
+			l.Warn("[Security] login %q from %s is locked for %s", login.Login, client.IP, lockout.RetryAfter)
+		} else if errors.Is(err, auth.ErrorAccountNotActive) {
+			operate.SendError(w, ErrorAccountNotApproved, http.StatusForbidden, l)
+			l.Info(errors.Wrapf(err, "login of not approved user"))
+		} else if errors.Is(err, auth.ErrorIncorrectPassword) || errors.Is(err, user.ErrorUserNotFound) {
+			operate.SendError(w, ErrorIncorrectLoginOrPassword, http.StatusConflict, l)
+			l.Info(errors.Wrapf(err, "inccorect login info"))
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			l.Error(errors.Wrapf(err, "can't check login info"))
+		}
+		return
+	}
+
+	// Ожидающий вход не является сессией, поэтому cookie не устанавливается
+	if credentials.Pending {
+		operate.SendStatus(w, http.StatusAccepted, response.FromPendingCredentials(credentials), l)
+		return
+	}
+
+	sendCredentials(w, uh.cookie, credentials, l)
+}
+
+// Refresh
+//
+//	@Summary		Обновление access токена.
+//	@Description	Выдаёт новую пару access и refresh токенов в режиме авторизации jwt. Переданный refresh токен становится недействительным, его повторное использование завершает сессию.
+//	@Tags			user
+//	@Accept			json
+//	@Param			request	body	request.Refresh	true	"Refresh токен"
+//	@Produce		json
+//	@Success		200	{object}	response.Credentials	"Токены успешно обновлены"
+//	@Header			200	{string}	Set-Cookie				"Устанавливает access токен текущего пользователя"
+//	@Failure		400	{object}	operate.ModelError		"В теле запроса ошибка или сервер работает не в режиме jwt"
+//	@Failure		401	{object}	operate.ModelError		"Refresh токен недействителен"
+//	@Failure		500	{object}	operate.ModelError		"Ошибка сервера"
+//	@Router			/refresh [post]
+func (uh *UserHandlers) Refresh(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	// Получение значения тела запроса
+	var refresh request.Refresh
+	if code, err := parseRequestBody(r.Body, &refresh, request.ValidateRefresh, l); err != nil {
+		operate.SendError(w, err, code, l)
+		return
+	}
+
+	credentials, err := uh.auth.Refresh(refresh.RefreshToken, clientInfo(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrorRefreshNotSupported):
+			operate.SendError(w, auth.ErrorRefreshNotSupported, http.StatusBadRequest, l)
+		case errors.Is(err, auth.ErrorRefreshTokenReused):
+			operate.SendError(w, ErrorInvalidRefreshToken, http.StatusUnauthorized, l)
+			l.Warn("[Security] %s", err)
+		case errors.Is(err, session.ErrorNoSession):
+			operate.SendError(w, ErrorInvalidRefreshToken, http.StatusUnauthorized, l)
+		default:
+			operate.SendError(w, ErrorUnknownError, http.StatusInternalServerError, l)
+			l.Error(errors.Wrapf(err, "can't refresh tokens"))
+		}
+		return
+	}
+
+	sendCredentials(w, uh.cookie, credentials, l)
+}
+
+// sendCredentials
+// Устанавливает cookie сессии, а в режиме jwt также возвращает выданные токены
+func sendCredentials(w http.ResponseWriter, cookie *middleware.SessionCookie, credentials *auth.Credentials,
+	l logger.Interface) {
+	// Cookie незапомненного входа удаляется браузером при закрытии
+	var expiresIn time.Duration
+	if credentials.Persistent {
+		expiresIn = credentials.ExpiresIn
+	}
+	cookie.Set(w, credentials.SessionId, expiresIn)
+
+	if credentials.RefreshToken == "" {
+		var body any
+		if len(credentials.RecoveryCodes) != 0 {
+			body = response.RecoveryCodes{RecoveryCodes: credentials.RecoveryCodes}
+		}
+		operate.SendStatus(w, http.StatusOK, body, l)
+		return
+	}
+
+	operate.SendStatus(w, http.StatusOK, response.FromCredentials(credentials), l)
+}
+
+// Logout
+//
+//	@Summary		Выход из системы.
+//	@Description	Позволяет выйти пользователю из системы.
+//	@Tags			user
+//	@Produce		json
+//	@Success		200	"Пользователь успешно вышел из системы"
+//	@Failure		400	{object}	operate.ModelError	"Запрос авторизован не сессией"
+//	@Failure		401	{object}	operate.ModelError	"Пользователь не авторизован"
+//	@Router			/logout [post]
+//	@Security		sessionCookie
+func (uh *UserHandlers) Logout(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+	l := middleware.GetLogger(r)
+
+	sessionId := middleware.GetSession(r)
+	if sessionId != nil {
+		// Уничтожение сессии
+		if err := uh.auth.Logout(*sessionId, clientInfo(r)); err != nil {
+			l.Warn(errors.Wrapf(err, "try delete session id %s", *sessionId))
+		} else {
+			l.Info("logged out")
+		}
+
+		uh.cookie.Clear(w)
+		operate.SendStatus(w, http.StatusOK, nil, l)
+		return
 	}
 
-	l.Panic("no session was found in logout")
+	// Запрос авторизован персональным токеном, завершать нечего
+	operate.SendError(w, ErrorUserNotAuthorized, http.StatusBadRequest, l)
 }
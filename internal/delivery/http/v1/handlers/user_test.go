@@ -5,19 +5,27 @@ import (
 	"fmt"
 	"github.com/ozontech/allure-go/pkg/framework/provider"
 	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/pkg/errors"
 	"go.uber.org/mock/gomock"
-	"golang.org/x/crypto/bcrypt"
 	"net/http"
 	"net/http/httptest"
 	"slices"
 	"strings"
 	"testing"
+	"time"
 	"vk_film/internal/delivery/http/v1/model/request"
 	"vk_film/internal/delivery/http/v1/model/response"
 	"vk_film/internal/delivery/middleware"
 	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/event"
+	"vk_film/internal/repository/role"
+	mrr "vk_film/internal/repository/role/mocks"
+	"vk_film/internal/repository/session"
+	"vk_film/internal/repository/token"
 	"vk_film/internal/repository/user"
 	mru "vk_film/internal/repository/user/mocks"
+	"vk_film/internal/usecase/accounts"
+	mac "vk_film/internal/usecase/accounts/mocks"
 	"vk_film/internal/usecase/auth"
 	mua "vk_film/internal/usecase/auth/mocks"
 	"vk_film/pkg/mux"
@@ -25,17 +33,22 @@ import (
 
 type UserHandlersSuite struct {
 	suite.Suite
-	handlers *UserHandlers
-	mockUser *mru.UserRepository
-	mockAuth *mua.SessionManager
-	gmc      *gomock.Controller
+	handlers     *UserHandlers
+	mockUser     *mru.UserRepository
+	mockRole     *mrr.RoleRepository
+	mockAuth     *mua.SessionManager
+	mockAccounts *mac.AccountsUsecase
+	gmc          *gomock.Controller
 }
 
 func (uhs *UserHandlersSuite) BeforeEach(t provider.T) {
 	uhs.gmc = gomock.NewController(t)
 	uhs.mockUser = mru.NewUserRepository(uhs.gmc)
+	uhs.mockRole = mrr.NewRoleRepository(uhs.gmc)
 	uhs.mockAuth = mua.NewSessionManager(uhs.gmc)
-	uhs.handlers = NewUserHandlers(uhs.mockUser, uhs.mockAuth)
+	uhs.mockAccounts = mac.NewAccountsUsecase(uhs.gmc)
+	uhs.handlers = NewUserHandlers(uhs.mockUser, uhs.mockRole, uhs.mockAuth, uhs.mockAccounts, auth.NewTestPasswordHasher(),
+		auth.DefaultPasswordPolicy, &middleware.DefaultSessionCookie)
 }
 
 func (uhs *UserHandlersSuite) AfterEach(t provider.T) {
@@ -48,17 +61,21 @@ func (uhs *UserHandlersSuite) TestLoginHandler(t provider.T) {
 	login := "login"
 	password := "password"
 	sessionId := "id"
+	clientIP := "192.168.0.1"
+	client := auth.ClientInfo{IP: clientIP, UserAgent: "curl/8.0"}
 	body := "{ \"login\": \"login\", \"password\": \"password\" }"
 
 	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		uhs.mockAuth.EXPECT().Login(login, password).
-			Return(sessionId, nil).Times(1)
+		uhs.mockAuth.EXPECT().Login(login, password, client).
+			Return(&auth.Credentials{SessionId: sessionId, ExpiresIn: 48 * time.Hour}, nil).Times(1)
 
 		t.NewStep("Init http")
 
 		req, err := initRequest(strings.NewReader(body), nil)
 		t.Require().NoError(err)
+		req.RemoteAddr = clientIP + ":43210"
+		req.Header.Set("User-Agent", client.UserAgent)
 
 		recorder := httptest.NewRecorder()
 
@@ -68,10 +85,66 @@ func (uhs *UserHandlersSuite) TestLoginHandler(t provider.T) {
 		t.Require().Equal(http.StatusOK, recorder.Code)
 		cks := recorder.Result().Cookies()
 		i := slices.IndexFunc(cks,
-			func(ck *http.Cookie) bool { return ck != nil && ck.Name == string(middleware.SessionField) },
+			func(ck *http.Cookie) bool { return ck != nil && ck.Name == middleware.DefaultSessionCookieName },
 		)
 		t.Require().NotEqual(-1, i)
 		t.Require().Equal(sessionId, cks[i].Value)
+		t.Require().True(cks[i].HttpOnly)
+		t.Require().Equal(http.SameSiteLaxMode, cks[i].SameSite)
+		t.Require().True(cks[i].Expires.IsZero())
+
+		i = slices.IndexFunc(cks, func(ck *http.Cookie) bool { return ck != nil && ck.Name == middleware.CSRFCookie })
+		t.Require().NotEqual(-1, i)
+		t.Require().Equal(middleware.CSRFToken(sessionId), cks[i].Value)
+		t.Require().False(cks[i].HttpOnly)
+	})
+
+	t.WithNewStep("Remembered login execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		remembered := client
+		remembered.RememberMe = true
+		uhs.mockAuth.EXPECT().Login(login, password, remembered).
+			Return(&auth.Credentials{SessionId: sessionId, ExpiresIn: 720 * time.Hour, Persistent: true}, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(`{"login": "login", "password": "password", "remember_me": true}`), nil)
+		t.Require().NoError(err)
+		req.RemoteAddr = clientIP + ":43210"
+		req.Header.Set("User-Agent", client.UserAgent)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.Login(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		for _, ck := range recorder.Result().Cookies() {
+			t.Require().WithinDuration(time.Now().Add(720*time.Hour), ck.Expires, time.Minute, ck.Name)
+		}
+	})
+
+	t.WithNewStep("Pending login execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().Login(login, password, client).
+			Return(&auth.Credentials{SessionId: "challenge", ExpiresIn: 5 * time.Minute, Pending: true}, nil).Times(1)
+
+		t.NewStep("Init http")
+
+		req, err := initRequest(strings.NewReader(body), nil)
+		t.Require().NoError(err)
+		req.RemoteAddr = clientIP + ":43210"
+		req.Header.Set("User-Agent", client.UserAgent)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.Login(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusAccepted, recorder.Code)
+		t.Require().Empty(recorder.Result().Cookies())
+		var res response.PendingLogin
+		t.Require().NoError(json.NewDecoder(recorder.Body).Decode(&res))
+		t.Require().Equal(response.PendingLogin{Token: "challenge", ExpiresIn: 300}, res)
 	})
 
 	t.WithNewStep("Incorrect Body execute", func(t provider.StepCtx) {
@@ -90,13 +163,15 @@ func (uhs *UserHandlersSuite) TestLoginHandler(t provider.T) {
 
 	t.WithNewStep("Session manager error in execution", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		uhs.mockAuth.EXPECT().Login(login, password).
-			Return(sessionId, testError).Times(1)
+		uhs.mockAuth.EXPECT().Login(login, password, client).
+			Return(nil, testError).Times(1)
 
 		t.NewStep("Init http")
 
 		req, err := initRequest(strings.NewReader(body), nil)
 		t.Require().NoError(err)
+		req.RemoteAddr = clientIP + ":43210"
+		req.Header.Set("User-Agent", client.UserAgent)
 
 		recorder := httptest.NewRecorder()
 
@@ -108,13 +183,15 @@ func (uhs *UserHandlersSuite) TestLoginHandler(t provider.T) {
 
 	t.WithNewStep("Incorrect password in execution", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		uhs.mockAuth.EXPECT().Login(login, password).
-			Return(sessionId, auth.ErrorIncorrectPassword).Times(1)
+		uhs.mockAuth.EXPECT().Login(login, password, client).
+			Return(nil, auth.ErrorIncorrectPassword).Times(1)
 
 		t.NewStep("Init http")
 
 		req, err := initRequest(strings.NewReader(body), nil)
 		t.Require().NoError(err)
+		req.RemoteAddr = clientIP + ":43210"
+		req.Header.Set("User-Agent", client.UserAgent)
 
 		recorder := httptest.NewRecorder()
 
@@ -124,15 +201,37 @@ func (uhs *UserHandlersSuite) TestLoginHandler(t provider.T) {
 		t.Require().Equal(http.StatusConflict, recorder.Code)
 	})
 
+	t.WithNewStep("Not approved account in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().Login(login, password, client).
+			Return(nil, errors.Wrap(auth.ErrorAccountNotActive, "user is pending")).Times(1)
+
+		t.NewStep("Init http")
+
+		req, err := initRequest(strings.NewReader(body), nil)
+		t.Require().NoError(err)
+		req.RemoteAddr = clientIP + ":43210"
+		req.Header.Set("User-Agent", client.UserAgent)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.Login(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusForbidden, recorder.Code)
+	})
+
 	t.WithNewStep("Incorrect login in execution", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		uhs.mockAuth.EXPECT().Login(login, password).
-			Return(sessionId, user.ErrorUserNotFound).Times(1)
+		uhs.mockAuth.EXPECT().Login(login, password, client).
+			Return(nil, user.ErrorUserNotFound).Times(1)
 
 		t.NewStep("Init http")
 
 		req, err := initRequest(strings.NewReader(body), nil)
 		t.Require().NoError(err)
+		req.RemoteAddr = clientIP + ":43210"
+		req.Header.Set("User-Agent", client.UserAgent)
 
 		recorder := httptest.NewRecorder()
 
@@ -141,6 +240,133 @@ func (uhs *UserHandlersSuite) TestLoginHandler(t provider.T) {
 
 		t.Require().Equal(http.StatusConflict, recorder.Code)
 	})
+
+	t.WithNewStep("Too many attempts in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().Login(login, password, client).
+			Return(nil, &auth.LockoutError{RetryAfter: 1500 * time.Millisecond}).Times(1)
+
+		t.NewStep("Init http")
+
+		req, err := initRequest(strings.NewReader(body), nil)
+		t.Require().NoError(err)
+		req.RemoteAddr = clientIP + ":43210"
+		req.Header.Set("User-Agent", client.UserAgent)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.Login(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusTooManyRequests, recorder.Code)
+		t.Require().Equal("2", recorder.Header().Get(RetryAfterHeader))
+	})
+}
+
+func (uhs *UserHandlersSuite) TestLoginHandlerJWTMode(t provider.T) {
+	t.Title("Login handler of user handlers in jwt mode")
+	t.NewStep("Init test data")
+	credentials := &auth.Credentials{SessionId: "access", RefreshToken: "refresh", ExpiresIn: 15 * time.Minute}
+	body := "{ \"login\": \"login\", \"password\": \"password\" }"
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().Login("login", "password", gomock.Any()).Return(credentials, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), nil)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.Login(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var res response.Credentials
+		t.Require().NoError(json.NewDecoder(recorder.Body).Decode(&res))
+		t.Require().Equal(response.Credentials{
+			AccessToken:  "access",
+			RefreshToken: "refresh",
+			TokenType:    "Bearer",
+			ExpiresIn:    900,
+		}, res)
+	})
+}
+
+func (uhs *UserHandlersSuite) TestRefreshHandler(t provider.T) {
+	t.Title("Refresh handler of user handlers")
+	t.NewStep("Init test data")
+	refreshToken := "refresh"
+	credentials := &auth.Credentials{SessionId: "access", RefreshToken: "new refresh", ExpiresIn: 15 * time.Minute}
+	body := "{ \"refresh_token\": \"refresh\" }"
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().Refresh(refreshToken, gomock.Any()).Return(credentials, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), nil)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.Refresh(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var res response.Credentials
+		t.Require().NoError(json.NewDecoder(recorder.Body).Decode(&res))
+		t.Require().Equal("access", res.AccessToken)
+		t.Require().Equal("new refresh", res.RefreshToken)
+		cks := recorder.Result().Cookies()
+		i := slices.IndexFunc(cks,
+			func(ck *http.Cookie) bool { return ck != nil && ck.Name == middleware.DefaultSessionCookieName },
+		)
+		t.Require().NotEqual(-1, i)
+		t.Require().Equal("access", cks[i].Value)
+	})
+
+	checkError := func(t provider.StepCtx, err error, code int) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().Refresh(refreshToken, gomock.Any()).Return(nil, err).Times(1)
+
+		t.NewStep("Init http")
+		req, reqErr := initRequest(strings.NewReader(body), nil)
+		t.Require().NoError(reqErr)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.Refresh(recorder, req, mux.Params{})
+
+		t.Require().Equal(code, recorder.Code)
+	}
+
+	t.WithNewStep("Session mode in execution", func(t provider.StepCtx) {
+		checkError(t, auth.ErrorRefreshNotSupported, http.StatusBadRequest)
+	})
+
+	t.WithNewStep("Invalid refresh token in execution", func(t provider.StepCtx) {
+		checkError(t, session.ErrorNoSession, http.StatusUnauthorized)
+	})
+
+	t.WithNewStep("Reused refresh token in execution", func(t provider.StepCtx) {
+		checkError(t, auth.ErrorRefreshTokenReused, http.StatusUnauthorized)
+	})
+
+	t.WithNewStep("Session manager error in execution", func(t provider.StepCtx) {
+		checkError(t, testError, http.StatusInternalServerError)
+	})
+
+	t.WithNewStep("Incorrect body in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader("{}"), nil)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.Refresh(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
 }
 
 func (uhs *UserHandlersSuite) TestLogoutHandler(t provider.T) {
@@ -150,7 +376,7 @@ func (uhs *UserHandlersSuite) TestLogoutHandler(t provider.T) {
 
 	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		uhs.mockAuth.EXPECT().Logout(sessionId).Return(nil).Times(1)
+		uhs.mockAuth.EXPECT().Logout(sessionId, auth.ClientInfo{}).Return(nil).Times(1)
 
 		t.NewStep("Init http")
 		req, err := initRequest(nil, map[types.ContextField]any{middleware.SessionField: sessionId})
@@ -164,7 +390,7 @@ func (uhs *UserHandlersSuite) TestLogoutHandler(t provider.T) {
 		t.Require().Equal(http.StatusOK, recorder.Code)
 		cks := recorder.Result().Cookies()
 		i := slices.IndexFunc(cks,
-			func(ck *http.Cookie) bool { return ck != nil && ck.Name == string(middleware.SessionField) },
+			func(ck *http.Cookie) bool { return ck != nil && ck.Name == middleware.DefaultSessionCookieName },
 		)
 		t.Require().NotEqual(-1, i)
 		t.Require().Equal("", cks[i].Value)
@@ -172,7 +398,7 @@ func (uhs *UserHandlersSuite) TestLogoutHandler(t provider.T) {
 
 	t.WithNewStep("Session manager error in execution", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		uhs.mockAuth.EXPECT().Logout(sessionId).Return(testError).Times(1)
+		uhs.mockAuth.EXPECT().Logout(sessionId, auth.ClientInfo{}).Return(testError).Times(1)
 
 		t.NewStep("Init http")
 		req, err := initRequest(nil, map[types.ContextField]any{middleware.SessionField: sessionId})
@@ -186,11 +412,24 @@ func (uhs *UserHandlersSuite) TestLogoutHandler(t provider.T) {
 		t.Require().Equal(http.StatusOK, recorder.Code)
 		cks := recorder.Result().Cookies()
 		i := slices.IndexFunc(cks,
-			func(ck *http.Cookie) bool { return ck != nil && ck.Name == string(middleware.SessionField) },
+			func(ck *http.Cookie) bool { return ck != nil && ck.Name == middleware.DefaultSessionCookieName },
 		)
 		t.Require().NotEqual(-1, i)
 		t.Require().Equal("", cks[i].Value)
 	})
+
+	t.WithNewStep("No session in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: userUser})
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.Logout(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
 }
 
 func (uhs *UserHandlersSuite) TestGetUsersHandler(t provider.T) {
@@ -250,7 +489,10 @@ func (uhs *UserHandlersSuite) TestUpdateUserRoleHandler(t provider.T) {
 
 	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		uhs.mockUser.EXPECT().UpdateUserRole(usr).Return(usr, nil).Times(1)
+		uhs.mockRole.EXPECT().GetRole(types.USER).Return(&role.Role{Name: types.USER}, nil).Times(1)
+		uhs.mockUser.EXPECT().GetUserById(usr.ID).Return(usr, nil).Times(1)
+		uhs.mockAccounts.EXPECT().UpdateRole(adminUser, usr.ID, usr.Role, false).Return(usr, nil).Times(1)
+		uhs.mockAuth.EXPECT().RevokeUserSessions(usr.ID).Return(nil).Times(1)
 
 		t.NewStep("Init http")
 		req, err := initRequest(strings.NewReader(string(body)), map[types.ContextField]any{middleware.UserField: adminUser})
@@ -270,7 +512,9 @@ func (uhs *UserHandlersSuite) TestUpdateUserRoleHandler(t provider.T) {
 
 	t.WithNewStep("User repository error in execution", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		uhs.mockUser.EXPECT().UpdateUserRole(usr).Return(usr, testError).Times(1)
+		uhs.mockRole.EXPECT().GetRole(types.USER).Return(&role.Role{Name: types.USER}, nil).Times(1)
+		uhs.mockUser.EXPECT().GetUserById(usr.ID).Return(usr, nil).Times(1)
+		uhs.mockAccounts.EXPECT().UpdateRole(adminUser, usr.ID, usr.Role, false).Return(usr, testError).Times(1)
 
 		t.NewStep("Init http")
 		req, err := initRequest(strings.NewReader(string(body)), map[types.ContextField]any{middleware.UserField: adminUser})
@@ -286,7 +530,9 @@ func (uhs *UserHandlersSuite) TestUpdateUserRoleHandler(t provider.T) {
 
 	t.WithNewStep("User not found error in execution", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		uhs.mockUser.EXPECT().UpdateUserRole(usr).Return(usr, user.ErrorUserNotFound).Times(1)
+		uhs.mockRole.EXPECT().GetRole(types.USER).Return(&role.Role{Name: types.USER}, nil).Times(1)
+		uhs.mockUser.EXPECT().GetUserById(usr.ID).Return(usr, nil).Times(1)
+		uhs.mockAccounts.EXPECT().UpdateRole(adminUser, usr.ID, usr.Role, false).Return(usr, user.ErrorUserNotFound).Times(1)
 
 		t.NewStep("Init http")
 		req, err := initRequest(strings.NewReader(string(body)), map[types.ContextField]any{middleware.UserField: adminUser})
@@ -300,9 +546,13 @@ func (uhs *UserHandlersSuite) TestUpdateUserRoleHandler(t provider.T) {
 		t.Require().Equal(http.StatusNotFound, recorder.Code)
 	})
 
-	t.WithNewStep("Body error in execution", func(t provider.StepCtx) {
+	t.WithNewStep("Target with more permissions in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockRole.EXPECT().GetRole(types.USER).Return(&role.Role{Name: types.USER}, nil).Times(1)
+		uhs.mockUser.EXPECT().GetUserById(usr.ID).Return(&user.User{ID: usr.ID, Role: types.ADMIN, Permissions: types.Permissions}, nil).Times(1)
+
 		t.NewStep("Init http")
-		req, err := initRequest(errReader(1), map[types.ContextField]any{middleware.UserField: adminUser})
+		req, err := initRequest(strings.NewReader(string(body)), map[types.ContextField]any{middleware.UserField: managerUser})
 		t.Require().NoError(err)
 		req.SetPathValue(UserIdField, fmt.Sprintf("%d", usr.ID))
 		recorder := httptest.NewRecorder()
@@ -310,206 +560,1609 @@ func (uhs *UserHandlersSuite) TestUpdateUserRoleHandler(t provider.T) {
 		t.NewStep("Check result")
 		uhs.handlers.UpdateUserRole(recorder, req, *mux.NewParams(req))
 
-		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+		t.Require().Equal(http.StatusForbidden, recorder.Code)
 	})
 
-	t.WithNewStep("No user id in execution", func(t provider.StepCtx) {
+	t.WithNewStep("Role repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockRole.EXPECT().GetRole(types.USER).Return(nil, testError).Times(1)
+
 		t.NewStep("Init http")
-		req, err := initRequest(errReader(1), map[types.ContextField]any{middleware.UserField: adminUser})
+		req, err := initRequest(strings.NewReader(string(body)), map[types.ContextField]any{middleware.UserField: adminUser})
 		t.Require().NoError(err)
-
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", usr.ID))
 		recorder := httptest.NewRecorder()
 
 		t.NewStep("Check result")
 		uhs.handlers.UpdateUserRole(recorder, req, *mux.NewParams(req))
 
-		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
 	})
 
-	t.WithNewStep("No user permissions in execution", func(t provider.StepCtx) {
+	t.WithNewStep("Role removed before update in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockRole.EXPECT().GetRole(types.USER).Return(&role.Role{Name: types.USER}, nil).Times(1)
+		uhs.mockUser.EXPECT().GetUserById(usr.ID).Return(usr, nil).Times(1)
+		uhs.mockAccounts.EXPECT().UpdateRole(adminUser, usr.ID, usr.Role, false).Return(nil, user.ErrorRoleNotFound).Times(1)
+
 		t.NewStep("Init http")
-		req, err := initRequest(errReader(1), map[types.ContextField]any{middleware.UserField: userUser})
+		req, err := initRequest(strings.NewReader(string(body)), map[types.ContextField]any{middleware.UserField: adminUser})
 		t.Require().NoError(err)
-
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", usr.ID))
 		recorder := httptest.NewRecorder()
 
 		t.NewStep("Check result")
 		uhs.handlers.UpdateUserRole(recorder, req, *mux.NewParams(req))
 
-		t.Require().Equal(http.StatusForbidden, recorder.Code)
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
 	})
-}
-
-func (uhs *UserHandlersSuite) TestDeleteUserHandler(t provider.T) {
-	t.Title("DeleteUser handler of user handlers")
-	t.NewStep("Init test data")
-
-	userId := types.Id(1)
 
-	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+	t.WithNewStep("Last admin in execution", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		uhs.mockUser.EXPECT().DeleteUser(userId).Return(nil).Times(1)
+		uhs.mockRole.EXPECT().GetRole(types.USER).Return(&role.Role{Name: types.USER}, nil).Times(1)
+		uhs.mockUser.EXPECT().GetUserById(usr.ID).Return(usr, nil).Times(1)
+		uhs.mockAccounts.EXPECT().UpdateRole(adminUser, usr.ID, usr.Role, true).
+			Return(nil, accounts.ErrorLastAdmin).Times(1)
 
 		t.NewStep("Init http")
-		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		req, err := initRequest(strings.NewReader(string(body)), map[types.ContextField]any{middleware.UserField: adminUser})
 		t.Require().NoError(err)
-		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", usr.ID))
+		req.URL.RawQuery = "confirm=true"
 		recorder := httptest.NewRecorder()
 
 		t.NewStep("Check result")
-		uhs.handlers.DeleteUser(recorder, req, *mux.NewParams(req))
+		uhs.handlers.UpdateUserRole(recorder, req, *mux.NewParams(req))
 
-		t.Require().Equal(http.StatusOK, recorder.Code)
+		t.Require().Equal(http.StatusConflict, recorder.Code)
 	})
 
-	t.WithNewStep("User repository error execute", func(t provider.StepCtx) {
+	t.WithNewStep("Confirmation required in execution", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		uhs.mockUser.EXPECT().DeleteUser(userId).Return(testError).Times(1)
+		uhs.mockRole.EXPECT().GetRole(types.USER).Return(&role.Role{Name: types.USER}, nil).Times(1)
+		uhs.mockUser.EXPECT().GetUserById(usr.ID).Return(usr, nil).Times(1)
+		uhs.mockAccounts.EXPECT().UpdateRole(adminUser, usr.ID, usr.Role, false).
+			Return(nil, accounts.ErrorConfirmationRequired).Times(1)
 
 		t.NewStep("Init http")
-		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		req, err := initRequest(strings.NewReader(string(body)), map[types.ContextField]any{middleware.UserField: adminUser})
 		t.Require().NoError(err)
-		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", usr.ID))
 		recorder := httptest.NewRecorder()
 
 		t.NewStep("Check result")
-		uhs.handlers.DeleteUser(recorder, req, *mux.NewParams(req))
+		uhs.handlers.UpdateUserRole(recorder, req, *mux.NewParams(req))
 
-		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+		t.Require().Equal(http.StatusConflict, recorder.Code)
 	})
 
-	t.WithNewStep("User repository unknown user execute", func(t provider.StepCtx) {
-		t.NewStep("Init mock")
-		uhs.mockUser.EXPECT().DeleteUser(userId).Return(user.ErrorUserNotFound).Times(1)
-
+	t.WithNewStep("Incorrect confirm in execution", func(t provider.StepCtx) {
 		t.NewStep("Init http")
-		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		req, err := initRequest(strings.NewReader(string(body)), map[types.ContextField]any{middleware.UserField: adminUser})
 		t.Require().NoError(err)
-		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", usr.ID))
+		req.URL.RawQuery = "confirm=maybe"
 		recorder := httptest.NewRecorder()
 
 		t.NewStep("Check result")
-		uhs.handlers.DeleteUser(recorder, req, *mux.NewParams(req))
+		uhs.handlers.UpdateUserRole(recorder, req, *mux.NewParams(req))
 
-		t.Require().Equal(http.StatusNotFound, recorder.Code)
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
 	})
 
-	t.WithNewStep("User id not presented in execution", func(t provider.StepCtx) {
+	t.WithNewStep("Body error in execution", func(t provider.StepCtx) {
 		t.NewStep("Init http")
-		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		req, err := initRequest(errReader(1), map[types.ContextField]any{middleware.UserField: adminUser})
 		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", usr.ID))
 		recorder := httptest.NewRecorder()
 
 		t.NewStep("Check result")
-		uhs.handlers.DeleteUser(recorder, req, *mux.NewParams(req))
+		uhs.handlers.UpdateUserRole(recorder, req, *mux.NewParams(req))
 
-		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
 	})
 
-	t.WithNewStep("No user permissions in in execution", func(t provider.StepCtx) {
+	t.WithNewStep("No user id in execution", func(t provider.StepCtx) {
 		t.NewStep("Init http")
-		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: userUser})
+		req, err := initRequest(errReader(1), map[types.ContextField]any{middleware.UserField: adminUser})
 		t.Require().NoError(err)
+
 		recorder := httptest.NewRecorder()
 
 		t.NewStep("Check result")
-		uhs.handlers.DeleteUser(recorder, req, *mux.NewParams(req))
+		uhs.handlers.UpdateUserRole(recorder, req, *mux.NewParams(req))
 
-		t.Require().Equal(http.StatusForbidden, recorder.Code)
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
 	})
 }
 
-type CreateUserMather user.User
-
-func (um *CreateUserMather) Matches(x any) bool {
-	if usr, ok := x.(*user.User); ok {
-		return usr.Login == um.Login && usr.Role == um.Role &&
-			bcrypt.CompareHashAndPassword([]byte(usr.Password), []byte(um.Password)) == nil
-	}
-	return false
-}
-
-func (um *CreateUserMather) String() string {
-	return fmt.Sprintf("is equal to %v (%T)", *um, um)
-}
-
-func (uhs *UserHandlersSuite) TestCreateUserHandler(t provider.T) {
-	t.Title("CreateUser handler of user handlers")
+func (uhs *UserHandlersSuite) TestUpdateUserMaxCertificationHandler(t provider.T) {
+	t.Title("UpdateUserMaxCertification handler of user handlers")
 	t.NewStep("Init test data")
-	createUser := &request.CreateUser{
-		Role:     string(types.USER),
-		Password: "1",
-	}
-	body, err := json.Marshal(createUser)
-	t.Require().NoError(err)
-
-	createUserWithoutRole := &request.CreateUser{
-		Password: "1",
-	}
-	bodyWithOutRole, err := json.Marshal(createUserWithoutRole)
-	t.Require().NoError(err)
+	maxCertification := types.AGE12
+	body := `{"max_certification": "12+"}`
 
-	usr := &user.User{ID: 1, Role: types.USER, Password: "1"}
-	expectedUser := &response.User{ID: 1, Role: string(types.USER)}
+	usr := &user.User{ID: 1, MaxCertification: &maxCertification}
+	expectedUser := &response.User{ID: 1, MaxCertification: (*string)(&maxCertification)}
 
 	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		uhs.mockUser.EXPECT().CreateUser((*CreateUserMather)(usr)).Return(usr, nil).Times(1)
+		uhs.mockUser.EXPECT().GetUserById(usr.ID).Return(&user.User{ID: usr.ID}, nil).Times(1)
+		uhs.mockUser.EXPECT().UpdateUserMaxCertification(usr).Return(usr, nil).Times(1)
+		uhs.mockAuth.EXPECT().InvalidateUser(usr.ID).Times(1)
 
 		t.NewStep("Init http")
-		req, err := initRequest(strings.NewReader(string(body)), map[types.ContextField]any{middleware.UserField: adminUser})
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: adminUser})
 		t.Require().NoError(err)
-
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", usr.ID))
 		recorder := httptest.NewRecorder()
 
 		t.NewStep("Check result")
-		uhs.handlers.CreateUser(recorder, req, *mux.NewParams(req))
+		uhs.handlers.UpdateUserMaxCertification(recorder, req, *mux.NewParams(req))
 
-		t.Require().Equal(http.StatusCreated, recorder.Code)
+		t.Require().Equal(http.StatusOK, recorder.Code)
 		var responseUser response.User
 		dec := json.NewDecoder(recorder.Body)
 		t.Require().NoError(dec.Decode(&responseUser))
 		t.Require().EqualValues(*expectedUser, responseUser)
 	})
 
-	t.WithNewStep("Correct execute default role", func(t provider.StepCtx) {
+	t.WithNewStep("Correct execute with removing restriction", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		uhs.mockUser.EXPECT().CreateUser((*CreateUserMather)(usr)).Return(usr, nil).Times(1)
+		uhs.mockUser.EXPECT().GetUserById(usr.ID).Return(&user.User{ID: usr.ID}, nil).Times(1)
+		uhs.mockUser.EXPECT().UpdateUserMaxCertification(&user.User{ID: usr.ID}).Return(&user.User{ID: usr.ID}, nil).Times(1)
+		uhs.mockAuth.EXPECT().InvalidateUser(usr.ID).Times(1)
 
 		t.NewStep("Init http")
-		req, err := initRequest(strings.NewReader(string(bodyWithOutRole)), map[types.ContextField]any{middleware.UserField: adminUser})
+		req, err := initRequest(strings.NewReader(`{"max_certification": null}`), map[types.ContextField]any{middleware.UserField: adminUser})
 		t.Require().NoError(err)
-
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", usr.ID))
 		recorder := httptest.NewRecorder()
 
 		t.NewStep("Check result")
-		uhs.handlers.CreateUser(recorder, req, *mux.NewParams(req))
+		uhs.handlers.UpdateUserMaxCertification(recorder, req, *mux.NewParams(req))
 
-		t.Require().Equal(http.StatusCreated, recorder.Code)
+		t.Require().Equal(http.StatusOK, recorder.Code)
 		var responseUser response.User
 		dec := json.NewDecoder(recorder.Body)
 		t.Require().NoError(dec.Decode(&responseUser))
-		t.Require().EqualValues(*expectedUser, responseUser)
+		t.Require().Nil(responseUser.MaxCertification)
 	})
 
 	t.WithNewStep("User repository error in execution", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		uhs.mockUser.EXPECT().CreateUser((*CreateUserMather)(usr)).Return(usr, testError).Times(1)
+		uhs.mockUser.EXPECT().GetUserById(usr.ID).Return(&user.User{ID: usr.ID}, nil).Times(1)
+		uhs.mockUser.EXPECT().UpdateUserMaxCertification(usr).Return(nil, testError).Times(1)
 
 		t.NewStep("Init http")
-		req, err := initRequest(strings.NewReader(string(body)), map[types.ContextField]any{middleware.UserField: adminUser})
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: adminUser})
 		t.Require().NoError(err)
-
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", usr.ID))
 		recorder := httptest.NewRecorder()
 
 		t.NewStep("Check result")
-		uhs.handlers.CreateUser(recorder, req, *mux.NewParams(req))
+		uhs.handlers.UpdateUserMaxCertification(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.WithNewStep("User not found error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(usr.ID).Return(&user.User{ID: usr.ID}, nil).Times(1)
+		uhs.mockUser.EXPECT().UpdateUserMaxCertification(usr).Return(nil, user.ErrorUserNotFound).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", usr.ID))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.UpdateUserMaxCertification(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusNotFound, recorder.Code)
+	})
+
+	t.WithNewStep("Target with more permissions in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(usr.ID).Return(&user.User{ID: usr.ID, Role: types.ADMIN, Permissions: types.Permissions}, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: managerUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", usr.ID))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.UpdateUserMaxCertification(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusForbidden, recorder.Code)
+	})
+
+	t.WithNewStep("Incorrect certification in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(`{"max_certification": "21+"}`), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", usr.ID))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.UpdateUserMaxCertification(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func (uhs *UserHandlersSuite) TestGetMeHandler(t provider.T) {
+	t.Title("GetMe handler of user handlers")
+	t.NewStep("Init test data")
+	maxCertification := types.AGE16
+	usr := &user.User{ID: 1, Login: "login", Role: types.USER, MaxCertification: &maxCertification}
+	expectedUser := response.User{ID: 1, Login: "login", Role: string(types.USER), MaxCertification: (*string)(&maxCertification)}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: usr})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.GetMe(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var responseUser response.User
+		dec := json.NewDecoder(recorder.Body)
+		t.Require().NoError(dec.Decode(&responseUser))
+		t.Require().EqualValues(expectedUser, responseUser)
+	})
+
+	t.WithNewStep("No user in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.GetMe(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusUnauthorized, recorder.Code)
+	})
+}
+
+func (uhs *UserHandlersSuite) TestChangePasswordHandler(t provider.T) {
+	t.Title("ChangePassword handler of user handlers")
+	t.NewStep("Init test data")
+	usr := &user.User{ID: 1, Role: types.USER}
+	sessionId := "id"
+	changePassword := &request.ChangePassword{CurrentPassword: "password", NewPassword: "new password"}
+	body, err := json.Marshal(changePassword)
+	t.Require().NoError(err)
+
+	contextValues := map[types.ContextField]any{middleware.UserField: usr, middleware.SessionField: sessionId}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().
+			ChangePassword(usr.ID, sessionId, changePassword.CurrentPassword, changePassword.NewPassword,
+				auth.ClientInfo{}).
+			Return(nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(string(body)), contextValues)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.ChangePassword(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
+	t.WithNewStep("Incorrect current password in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().
+			ChangePassword(usr.ID, sessionId, changePassword.CurrentPassword, changePassword.NewPassword,
+				auth.ClientInfo{}).
+			Return(auth.ErrorIncorrectPassword).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(string(body)), contextValues)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.ChangePassword(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusConflict, recorder.Code)
+	})
+
+	t.WithNewStep("Weak new password in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().
+			ChangePassword(usr.ID, sessionId, changePassword.CurrentPassword, changePassword.NewPassword,
+				auth.ClientInfo{}).
+			Return(errors.Wrap(auth.ErrorPasswordPolicy, "too short")).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(string(body)), contextValues)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.ChangePassword(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("Session manager error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().
+			ChangePassword(usr.ID, sessionId, changePassword.CurrentPassword, changePassword.NewPassword,
+				auth.ClientInfo{}).
+			Return(testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(string(body)), contextValues)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.ChangePassword(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.WithNewStep("Incorrect body in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(`{"current_password": "password"}`), contextValues)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.ChangePassword(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("No session in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(string(body)), map[types.ContextField]any{middleware.UserField: usr})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.ChangePassword(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusUnauthorized, recorder.Code)
+	})
+}
+
+func (uhs *UserHandlersSuite) TestUpdateEmailHandler(t provider.T) {
+	t.Title("UpdateEmail handler of user handlers")
+	t.NewStep("Init test data")
+	usr := &user.User{ID: 1, Role: types.USER}
+	contextValues := map[types.ContextField]any{middleware.UserField: usr}
+
+	for _, updateCase := range []struct {
+		name  string
+		body  string
+		email string
+		err   error
+		code  int
+	}{
+		{"Correct", `{"current_password": "password", "email": "User@Example.com"}`, "user@example.com",
+			nil, http.StatusOK},
+		{"Remove email", `{"current_password": "password", "email": ""}`, "", nil, http.StatusOK},
+		{"Email already exists", `{"current_password": "password", "email": "user@example.com"}`,
+			"user@example.com", user.ErrorEmailAlreadyExists, http.StatusConflict},
+		{"User repository error", `{"current_password": "password", "email": "user@example.com"}`,
+			"user@example.com", testError, http.StatusInternalServerError},
+	} {
+		t.WithNewStep(updateCase.name+" execute", func(t provider.StepCtx) {
+			t.NewStep("Init mock")
+			uhs.mockAuth.EXPECT().VerifyPassword(usr.ID, "password").Return(nil).Times(1)
+			uhs.mockUser.EXPECT().UpdateUserEmail(usr.ID, updateCase.email).Return(updateCase.err).Times(1)
+			if updateCase.err == nil {
+				uhs.mockAuth.EXPECT().InvalidateUser(usr.ID).Times(1)
+			}
+
+			t.NewStep("Init http")
+			req, err := initRequest(strings.NewReader(updateCase.body), contextValues)
+			t.Require().NoError(err)
+			recorder := httptest.NewRecorder()
+
+			t.NewStep("Check result")
+			uhs.handlers.UpdateEmail(recorder, req, mux.Params{})
+
+			t.Require().Equal(updateCase.code, recorder.Code)
+		})
+	}
+
+	for _, verifyCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"Incorrect current password", auth.ErrorIncorrectPassword, http.StatusConflict},
+		{"Session manager error", testError, http.StatusInternalServerError},
+	} {
+		t.WithNewStep(verifyCase.name+" in execution", func(t provider.StepCtx) {
+			t.NewStep("Init mock")
+			uhs.mockAuth.EXPECT().VerifyPassword(usr.ID, "wrong").Return(verifyCase.err).Times(1)
+
+			t.NewStep("Init http")
+			body := `{"current_password": "wrong", "email": "attacker@example.com"}`
+			req, err := initRequest(strings.NewReader(body), contextValues)
+			t.Require().NoError(err)
+			recorder := httptest.NewRecorder()
+
+			t.NewStep("Check result")
+			uhs.handlers.UpdateEmail(recorder, req, mux.Params{})
+
+			t.Require().Equal(verifyCase.code, recorder.Code)
+		})
+	}
+
+	t.WithNewStep("Without current password in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(`{"email": "user@example.com"}`), contextValues)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.UpdateEmail(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("Incorrect email in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(`{"current_password": "password", "email": "user"}`), contextValues)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.UpdateEmail(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("No user in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(`{"email": "user@example.com"}`), nil)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.UpdateEmail(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusUnauthorized, recorder.Code)
+	})
+}
+
+func (uhs *UserHandlersSuite) TestResetUserPasswordHandler(t provider.T) {
+	t.Title("ResetUserPassword handler of user handlers")
+	t.NewStep("Init test data")
+	userId := types.Id(2)
+	body := `{"password": "new password"}`
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId}, nil).Times(1)
+		uhs.mockAuth.EXPECT().ResetPassword(userId, "new password", auth.ClientInfo{}).Return(nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.ResetUserPassword(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
+	t.WithNewStep("User not found error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId}, nil).Times(1)
+		uhs.mockAuth.EXPECT().ResetPassword(userId, "new password", auth.ClientInfo{}).Return(user.ErrorUserNotFound).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.ResetUserPassword(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusNotFound, recorder.Code)
+	})
+
+	t.WithNewStep("Weak password in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId}, nil).Times(1)
+		uhs.mockAuth.EXPECT().ResetPassword(userId, "new password", auth.ClientInfo{}).
+			Return(errors.Wrap(auth.ErrorPasswordPolicy, "too short")).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.ResetUserPassword(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("Session manager error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId}, nil).Times(1)
+		uhs.mockAuth.EXPECT().ResetPassword(userId, "new password", auth.ClientInfo{}).Return(testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.ResetUserPassword(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.WithNewStep("Target with more permissions in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId, Role: types.ADMIN, Permissions: types.Permissions}, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: managerUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.ResetUserPassword(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusForbidden, recorder.Code)
+	})
+
+	t.WithNewStep("Target with same permissions in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId, Permissions: managerUser.Permissions}, nil).Times(1)
+		uhs.mockAuth.EXPECT().ResetPassword(userId, "new password", auth.ClientInfo{}).Return(nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: managerUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.ResetUserPassword(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
+	t.WithNewStep("Target not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(nil, user.ErrorUserNotFound).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: managerUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.ResetUserPassword(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusNotFound, recorder.Code)
+	})
+
+	t.WithNewStep("Target repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(nil, testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: managerUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.ResetUserPassword(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.WithNewStep("Empty password in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(`{"password": ""}`), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.ResetUserPassword(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("No user id in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(body), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.ResetUserPassword(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func (uhs *UserHandlersSuite) TestUnlockUserHandler(t provider.T) {
+	t.Title("UnlockUser handler of user handlers")
+	t.NewStep("Init test data")
+	userId := types.Id(2)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId}, nil).Times(1)
+		uhs.mockAuth.EXPECT().UnlockUser(userId).Return(nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.UnlockUser(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
+	t.WithNewStep("User not found error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId}, nil).Times(1)
+		uhs.mockAuth.EXPECT().UnlockUser(userId).Return(user.ErrorUserNotFound).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.UnlockUser(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusNotFound, recorder.Code)
+	})
+
+	t.WithNewStep("Session manager error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId}, nil).Times(1)
+		uhs.mockAuth.EXPECT().UnlockUser(userId).Return(testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.UnlockUser(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.WithNewStep("Moderator targets admin execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId, Role: types.ADMIN, Permissions: types.Permissions}, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: moderatorUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.UnlockUser(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusForbidden, recorder.Code)
+	})
+
+	t.WithNewStep("Unknown target execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(nil, user.ErrorUserNotFound).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.UnlockUser(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusNotFound, recorder.Code)
+	})
+
+	t.WithNewStep("No user id in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.UnlockUser(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func (uhs *UserHandlersSuite) TestCreateTokenHandler(t provider.T) {
+	t.Title("CreateToken handler of user handlers")
+	t.NewStep("Init test data")
+	usr := &user.User{ID: 1, Role: types.USER}
+	secret := "vkf_secret"
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	createToken := &request.CreateToken{Name: "ci", ReadOnly: true, ExpiresAt: &expiresAt}
+	body, err := json.Marshal(createToken)
+	t.Require().NoError(err)
+	tkn := &token.Token{UserID: usr.ID, Name: "ci", ReadOnly: true, ExpiresAt: &expiresAt}
+	createdToken := &token.Token{ID: 2, UserID: usr.ID, Name: "ci", ReadOnly: true, ExpiresAt: &expiresAt}
+
+	contextValues := map[types.ContextField]any{middleware.UserField: usr}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().CreateToken(gomock.Any()).
+			Do(func(res *token.Token) {
+				t.Require().Equal(tkn.Name, res.Name)
+				t.Require().Equal(tkn.UserID, res.UserID)
+				t.Require().Equal(tkn.ReadOnly, res.ReadOnly)
+				t.Require().True(tkn.ExpiresAt.Equal(*res.ExpiresAt))
+			}).
+			Return(secret, createdToken, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(string(body)), contextValues)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.CreateToken(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusCreated, recorder.Code)
+		var res response.CreatedToken
+		t.Require().NoError(json.NewDecoder(recorder.Body).Decode(&res))
+		t.Require().Equal(secret, res.Secret)
+		t.Require().Equal(createdToken.ID, res.ID)
+	})
+
+	t.WithNewStep("Token already exists in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().CreateToken(gomock.Any()).
+			Return("", nil, token.ErrorTokenNameAlreadyExists).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(string(body)), contextValues)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.CreateToken(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusConflict, recorder.Code)
+	})
+
+	t.WithNewStep("Session manager error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().CreateToken(gomock.Any()).Return("", nil, testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(string(body)), contextValues)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.CreateToken(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.WithNewStep("Expiration in the past in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(`{"name":"ci","expires_at":"2001-01-01T00:00:00Z"}`), contextValues)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.CreateToken(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("Incorrect body in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(`{"name":""}`), contextValues)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.CreateToken(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("Not authorized in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(string(body)), map[types.ContextField]any{})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.CreateToken(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusUnauthorized, recorder.Code)
+	})
+}
+
+func (uhs *UserHandlersSuite) TestGetTokensHandler(t provider.T) {
+	t.Title("GetTokens handler of user handlers")
+	t.NewStep("Init test data")
+	usr := &user.User{ID: 1, Role: types.USER}
+	lastUsedAt := time.Now().UTC().Truncate(time.Second)
+	tokens := []token.Token{{ID: 1, Name: "ci", LastUsedAt: &lastUsedAt}, {ID: 2, Name: "backup"}}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().GetTokens(usr.ID).Return(tokens, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: usr})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.GetTokens(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var res []response.Token
+		t.Require().NoError(json.NewDecoder(recorder.Body).Decode(&res))
+		t.Require().Len(res, 2)
+		t.Require().NotNil(res[0].LastUsedAt)
+		t.Require().True(lastUsedAt.Equal(*res[0].LastUsedAt))
+		t.Require().Nil(res[1].LastUsedAt)
+	})
+
+	t.WithNewStep("Session manager error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().GetTokens(usr.ID).Return(nil, testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: usr})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.GetTokens(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (uhs *UserHandlersSuite) TestRevokeTokenHandler(t provider.T) {
+	t.Title("RevokeToken handler of user handlers")
+	t.NewStep("Init test data")
+	usr := &user.User{ID: 1, Role: types.USER}
+	tokenId := types.Id(2)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().RevokeToken(usr.ID, tokenId).Return(nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: usr})
+		t.Require().NoError(err)
+		req.SetPathValue(TokenIdField, fmt.Sprintf("%d", tokenId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.RevokeToken(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
+	t.WithNewStep("Token not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().RevokeToken(usr.ID, tokenId).Return(token.ErrorTokenNotFound).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: usr})
+		t.Require().NoError(err)
+		req.SetPathValue(TokenIdField, fmt.Sprintf("%d", tokenId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.RevokeToken(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusNotFound, recorder.Code)
+	})
+
+	t.WithNewStep("Incorrect token id in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: usr})
+		t.Require().NoError(err)
+		req.SetPathValue(TokenIdField, "abc")
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.RevokeToken(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func (uhs *UserHandlersSuite) TestGetSessionsHandler(t provider.T) {
+	t.Title("GetSessions handler of user handlers")
+	t.NewStep("Init test data")
+	usr := &user.User{ID: 1, Role: types.USER}
+	sessionId := "id"
+	sessions := []session.Session{
+		{ID: "first", UserID: usr.ID, IP: "192.168.0.1", UserAgent: "curl/8.0", Current: true},
+		{ID: "second", UserID: usr.ID, IP: "192.168.0.2", UserAgent: "Mozilla/5.0"},
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().GetSessions(usr.ID, sessionId).Return(sessions, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{
+			middleware.UserField:    usr,
+			middleware.SessionField: sessionId,
+		})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.GetSessions(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var res []response.Session
+		t.Require().NoError(json.NewDecoder(recorder.Body).Decode(&res))
+		t.Require().Len(res, 2)
+		t.Require().Equal(response.FromRepositorySession(&sessions[0]), res[0])
+		t.Require().False(res[1].Current)
+	})
+
+	t.WithNewStep("Correct execute without session", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().GetSessions(usr.ID, "").Return(sessions, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: usr})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.GetSessions(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
+	t.WithNewStep("Auth manager error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().GetSessions(usr.ID, "").Return(nil, testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: usr})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.GetSessions(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.WithNewStep("Not authorized user in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.GetSessions(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusUnauthorized, recorder.Code)
+	})
+}
+
+func (uhs *UserHandlersSuite) TestRevokeSessionHandler(t provider.T) {
+	t.Title("RevokeSession handler of user handlers")
+	t.NewStep("Init test data")
+	usr := &user.User{ID: 1, Role: types.USER}
+	publicId := session.PublicId("id")
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().RevokeSession(usr.ID, publicId).Return(nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: usr})
+		t.Require().NoError(err)
+		req.SetPathValue(SessionIdField, publicId)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.RevokeSession(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
+	t.WithNewStep("Session not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().RevokeSession(usr.ID, publicId).Return(session.ErrorNoSession).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: usr})
+		t.Require().NoError(err)
+		req.SetPathValue(SessionIdField, publicId)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.RevokeSession(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusNotFound, recorder.Code)
+	})
+
+	t.WithNewStep("Auth manager error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().RevokeSession(usr.ID, publicId).Return(testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: usr})
+		t.Require().NoError(err)
+		req.SetPathValue(SessionIdField, publicId)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.RevokeSession(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.WithNewStep("Empty session id in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: usr})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.RevokeSession(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func (uhs *UserHandlersSuite) TestGetSecurityEventsHandler(t provider.T) {
+	t.Title("GetSecurityEvents handler of user handlers")
+	t.NewStep("Init test data")
+	usr := &user.User{ID: 1, Role: types.USER}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := []event.Event{
+		{ID: 2, UserID: usr.ID, Type: event.LoginFailed, IP: "192.168.0.1", UserAgent: "curl/8.0", CreatedAt: from},
+		{ID: 1, UserID: usr.ID, Type: event.LoginSucceeded, IP: "192.168.0.1", UserAgent: "curl/8.0", CreatedAt: from},
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().GetEvents(event.Filter{
+			UserID: usr.ID,
+			Types:  []event.Type{event.LoginFailed, event.LoginSucceeded},
+			From:   &from,
+			Limit:  10,
+			Offset: 20,
+		}).Return(events, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: usr})
+		t.Require().NoError(err)
+
+		vals := req.URL.Query()
+		vals.Add(EventTypeKey, string(event.LoginFailed))
+		vals.Add(EventTypeKey, string(event.LoginSucceeded))
+		vals.Set(FromKey, from.Format(time.RFC3339))
+		vals.Set(LimitKey, "10")
+		vals.Set(OffsetKey, "20")
+		// Фильтры администратора игнорируются для собственного журнала
+		vals.Set(UserIdKey, "2")
+		vals.Set(IPKey, "192.168.0.2")
+		req.URL.RawQuery = vals.Encode()
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.GetSecurityEvents(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var res []response.SecurityEvent
+		t.Require().NoError(json.NewDecoder(recorder.Body).Decode(&res))
+		t.Require().Equal([]response.SecurityEvent{
+			response.FromRepositoryEvent(&events[0]),
+			response.FromRepositoryEvent(&events[1]),
+		}, res)
+	})
+
+	t.WithNewStep("Correct execute with default limit", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().GetEvents(event.Filter{UserID: usr.ID, Limit: DefaultEventsLimit}).
+			Return([]event.Event{}, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: usr})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.GetSecurityEvents(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
+	for _, param := range [][2]string{
+		{EventTypeKey, "unknown"},
+		{FromKey, "01.01.2024"},
+		{ToKey, "yesterday"},
+		{LimitKey, "0"},
+		{LimitKey, "501"},
+		{OffsetKey, "-5"},
+	} {
+		t.WithNewStep("Incorrect "+param[0]+" param in execution", func(t provider.StepCtx) {
+			t.NewStep("Init http")
+			req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: usr})
+			t.Require().NoError(err)
+
+			vals := req.URL.Query()
+			vals.Set(param[0], param[1])
+			req.URL.RawQuery = vals.Encode()
+
+			recorder := httptest.NewRecorder()
+
+			t.NewStep("Check result")
+			uhs.handlers.GetSecurityEvents(recorder, req, mux.Params{})
+
+			t.Require().Equal(http.StatusBadRequest, recorder.Code)
+		})
+	}
+
+	t.WithNewStep("Auth manager error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().GetEvents(gomock.Any()).Return(nil, testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: usr})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.GetSecurityEvents(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.WithNewStep("Not authorized user in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(nil, nil)
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.GetSecurityEvents(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusUnauthorized, recorder.Code)
+	})
+}
+
+func (uhs *UserHandlersSuite) TestGetUsersSecurityEventsHandler(t provider.T) {
+	t.Title("GetUsersSecurityEvents handler of user handlers")
+	t.NewStep("Init test data")
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	events := []event.Event{
+		{ID: 1, UserID: 2, Type: event.Lockout, IP: "192.168.0.2", UserAgent: "curl/8.0", CreatedAt: to},
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().GetEvents(event.Filter{
+			UserID: 2,
+			Types:  []event.Type{event.Lockout},
+			IP:     "192.168.0.2",
+			To:     &to,
+			Limit:  DefaultEventsLimit,
+		}).Return(events, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+
+		vals := req.URL.Query()
+		vals.Set(UserIdKey, "2")
+		vals.Set(EventTypeKey, string(event.Lockout))
+		vals.Set(IPKey, "192.168.0.2")
+		vals.Set(ToKey, to.Format(time.RFC3339))
+		req.URL.RawQuery = vals.Encode()
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.GetUsersSecurityEvents(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		var res []response.SecurityEvent
+		t.Require().NoError(json.NewDecoder(recorder.Body).Decode(&res))
+		t.Require().Equal([]response.SecurityEvent{response.FromRepositoryEvent(&events[0])}, res)
+	})
+
+	t.WithNewStep("Incorrect user_id param in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+
+		vals := req.URL.Query()
+		vals.Set(UserIdKey, "admin")
+		req.URL.RawQuery = vals.Encode()
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.GetUsersSecurityEvents(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("Auth manager error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockAuth.EXPECT().GetEvents(gomock.Any()).Return(nil, testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.GetUsersSecurityEvents(recorder, req, mux.Params{})
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (uhs *UserHandlersSuite) TestRevokeUserSessionsHandler(t provider.T) {
+	t.Title("RevokeUserSessions handler of user handlers")
+	t.NewStep("Init test data")
+	userId := types.Id(2)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId}, nil).Times(1)
+		uhs.mockAuth.EXPECT().RevokeUserSessions(userId).Return(nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.RevokeUserSessions(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
+	t.WithNewStep("Auth manager error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId}, nil).Times(1)
+		uhs.mockAuth.EXPECT().RevokeUserSessions(userId).Return(testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.RevokeUserSessions(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.WithNewStep("Moderator targets admin execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId, Role: types.ADMIN, Permissions: types.Permissions}, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: moderatorUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.RevokeUserSessions(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusForbidden, recorder.Code)
+	})
+
+	t.WithNewStep("Unknown target execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(nil, user.ErrorUserNotFound).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.RevokeUserSessions(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusNotFound, recorder.Code)
+	})
+
+	t.WithNewStep("Incorrect user id in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, "abc")
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.RevokeUserSessions(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func (uhs *UserHandlersSuite) TestDeleteUserHandler(t provider.T) {
+	t.Title("DeleteUser handler of user handlers")
+	t.NewStep("Init test data")
+
+	userId := types.Id(1)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId}, nil).Times(1)
+		uhs.mockAccounts.EXPECT().Delete(adminUser, userId, false).Return(nil).Times(1)
+		uhs.mockAuth.EXPECT().RevokeUserSessions(userId).Return(nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.DeleteUser(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
+	t.WithNewStep("User repository error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId}, nil).Times(1)
+		uhs.mockAccounts.EXPECT().Delete(adminUser, userId, false).Return(testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.DeleteUser(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.WithNewStep("User repository unknown user execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId}, nil).Times(1)
+		uhs.mockAccounts.EXPECT().Delete(adminUser, userId, false).Return(user.ErrorUserNotFound).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.DeleteUser(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusNotFound, recorder.Code)
+	})
+
+	t.WithNewStep("Last admin execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId}, nil).Times(1)
+		uhs.mockAccounts.EXPECT().Delete(adminUser, userId, true).Return(accounts.ErrorLastAdmin).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		req.URL.RawQuery = "confirm=true"
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.DeleteUser(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusConflict, recorder.Code)
+	})
+
+	t.WithNewStep("Confirmation required execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId}, nil).Times(1)
+		uhs.mockAccounts.EXPECT().Delete(adminUser, userId, false).Return(accounts.ErrorConfirmationRequired).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.DeleteUser(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusConflict, recorder.Code)
+	})
+
+	t.WithNewStep("Target with more permissions execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId, Role: types.ADMIN, Permissions: types.Permissions}, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: managerUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.DeleteUser(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusForbidden, recorder.Code)
+	})
+
+	t.WithNewStep("Incorrect confirm execute", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		req.SetPathValue(UserIdField, fmt.Sprintf("%d", userId))
+		req.URL.RawQuery = "confirm=maybe"
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.DeleteUser(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("User id not presented in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(nil, map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.DeleteUser(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+}
+
+type CreateUserMather user.User
+
+func (um *CreateUserMather) Matches(x any) bool {
+	if usr, ok := x.(*user.User); ok {
+		return usr.Login == um.Login && usr.Role == um.Role && usr.Email == um.Email &&
+			verifyPassword(usr.Password, um.Password)
+	}
+	return false
+}
+
+func (um *CreateUserMather) String() string {
+	return fmt.Sprintf("is equal to %v (%T)", *um, um)
+}
+
+func (uhs *UserHandlersSuite) TestCreateUserHandler(t provider.T) {
+	t.Title("CreateUser handler of user handlers")
+	t.NewStep("Init test data")
+	createUser := &request.CreateUser{
+		Role:     string(types.USER),
+		Password: "Password1",
+	}
+	body, err := json.Marshal(createUser)
+	t.Require().NoError(err)
+
+	createUserWithoutRole := &request.CreateUser{
+		Password: "Password1",
+	}
+	bodyWithOutRole, err := json.Marshal(createUserWithoutRole)
+	t.Require().NoError(err)
+
+	usr := &user.User{ID: 1, Role: types.USER, Password: "Password1"}
+	expectedUser := &response.User{ID: 1, Role: string(types.USER)}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockRole.EXPECT().GetRole(types.USER).Return(&role.Role{Name: types.USER}, nil).Times(1)
+		uhs.mockUser.EXPECT().CreateUser((*CreateUserMather)(usr)).Return(usr, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(string(body)), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.CreateUser(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusCreated, recorder.Code)
+		var responseUser response.User
+		dec := json.NewDecoder(recorder.Body)
+		t.Require().NoError(dec.Decode(&responseUser))
+		t.Require().EqualValues(*expectedUser, responseUser)
+	})
+
+	t.WithNewStep("Correct execute default role", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockRole.EXPECT().GetRole(types.USER).Return(&role.Role{Name: types.USER}, nil).Times(1)
+		uhs.mockUser.EXPECT().CreateUser((*CreateUserMather)(usr)).Return(usr, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(string(bodyWithOutRole)), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.CreateUser(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusCreated, recorder.Code)
+		var responseUser response.User
+		dec := json.NewDecoder(recorder.Body)
+		t.Require().NoError(dec.Decode(&responseUser))
+		t.Require().EqualValues(*expectedUser, responseUser)
+	})
+
+	t.WithNewStep("User repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockRole.EXPECT().GetRole(types.USER).Return(&role.Role{Name: types.USER}, nil).Times(1)
+		uhs.mockUser.EXPECT().CreateUser((*CreateUserMather)(usr)).Return(usr, testError).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(string(body)), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.CreateUser(recorder, req, *mux.NewParams(req))
 
 		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
 	})
 
 	t.WithNewStep("User already exists error in execution", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
+		uhs.mockRole.EXPECT().GetRole(types.USER).Return(&role.Role{Name: types.USER}, nil).Times(1)
 		uhs.mockUser.EXPECT().CreateUser((*CreateUserMather)(usr)).Return(usr, user.ErrorLoginAlreadyExists).Times(1)
 
 		t.NewStep("Init http")
@@ -524,21 +2177,83 @@ func (uhs *UserHandlersSuite) TestCreateUserHandler(t provider.T) {
 		t.Require().Equal(http.StatusConflict, recorder.Code)
 	})
 
-	t.WithNewStep("Body error in execution", func(t provider.StepCtx) {
+	t.WithNewStep("Email already exists error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		withEmail := *usr
+		withEmail.Email = "user@example.com"
+		uhs.mockRole.EXPECT().GetRole(types.USER).Return(&role.Role{Name: types.USER}, nil).Times(1)
+		uhs.mockUser.EXPECT().CreateUser((*CreateUserMather)(&withEmail)).
+			Return(nil, user.ErrorEmailAlreadyExists).Times(1)
+
 		t.NewStep("Init http")
-		req, err := initRequest(errReader(1), map[types.ContextField]any{middleware.UserField: adminUser})
+		req, err := initRequest(strings.NewReader(`{"login": "", "password": "Password1", "email": "User@Example.com"}`),
+			map[types.ContextField]any{middleware.UserField: adminUser})
 		t.Require().NoError(err)
+
 		recorder := httptest.NewRecorder()
 
 		t.NewStep("Check result")
 		uhs.handlers.CreateUser(recorder, req, *mux.NewParams(req))
 
-		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+		t.Require().Equal(http.StatusConflict, recorder.Code)
+	})
+
+	t.WithNewStep("Incorrect email in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(`{"login": "", "password": "Password1", "email": "user"}`),
+			map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.CreateUser(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("Weak password in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockRole.EXPECT().GetRole(types.USER).Return(&role.Role{Name: types.USER}, nil).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(`{"login": "user", "password": "password"}`),
+			map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.CreateUser(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
+	})
+
+	t.WithNewStep("Role not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockRole.EXPECT().GetRole(types.USER).Return(nil, role.ErrorRoleNotFound).Times(1)
+
+		t.NewStep("Init http")
+		req, err := initRequest(strings.NewReader(string(body)), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.CreateUser(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusBadRequest, recorder.Code)
 	})
 
-	t.WithNewStep("No user permissions in execution", func(t provider.StepCtx) {
+	t.WithNewStep("Role with not owned permissions in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		uhs.mockRole.EXPECT().GetRole(types.USER).
+			Return(&role.Role{Name: types.USER, Permissions: []types.Permission{types.RoleManage}}, nil).Times(1)
+
 		t.NewStep("Init http")
-		req, err := initRequest(errReader(1), map[types.ContextField]any{middleware.UserField: userUser})
+		req, err := initRequest(strings.NewReader(string(body)), map[types.ContextField]any{
+			middleware.UserField: &user.User{ID: 2, Permissions: []types.Permission{types.UserManage}},
+		})
 		t.Require().NoError(err)
 
 		recorder := httptest.NewRecorder()
@@ -548,6 +2263,18 @@ func (uhs *UserHandlersSuite) TestCreateUserHandler(t provider.T) {
 
 		t.Require().Equal(http.StatusForbidden, recorder.Code)
 	})
+
+	t.WithNewStep("Body error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		req, err := initRequest(errReader(1), map[types.ContextField]any{middleware.UserField: adminUser})
+		t.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+
+		t.NewStep("Check result")
+		uhs.handlers.CreateUser(recorder, req, *mux.NewParams(req))
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
 }
 
 func TestRunUserHandlersSuite(t *testing.T) {
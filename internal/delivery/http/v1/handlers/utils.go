@@ -4,11 +4,25 @@ import (
 	"encoding/json"
 	"github.com/pkg/errors"
 	"io"
+	"math"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
+	stdTime "time"
+	"vk_film/internal/delivery/middleware"
 	"vk_film/internal/pkg/evjson"
+	"vk_film/internal/pkg/time"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/usecase/auth"
 	"vk_film/pkg/logger"
 )
 
+const (
+	LimitKey  = "limit"
+	OffsetKey = "offset"
+)
+
 func parseRequestBody(reqBody io.ReadCloser, out any, validation func([]byte) error, l logger.Interface) (int, error) {
 	body, err := io.ReadAll(reqBody)
 	if err != nil {
@@ -33,3 +47,121 @@ func parseRequestBody(reqBody io.ReadCloser, out any, validation func([]byte) er
 
 	return http.StatusOK, nil
 }
+
+func parseDateParam(values url.Values, key string) (*time.FormattedTime, error) {
+	if !values.Has(key) {
+		return nil, nil
+	}
+
+	date, err := time.Parse(values.Get(key))
+	if err != nil {
+		return nil, errors.Wrapf(ErrorIncorrectQueryParam, "with field %s and value %s", key, values.Get(key))
+	}
+
+	return &date, nil
+}
+
+// parseTimeParam
+// Получает момент времени в формате RFC 3339. Nil означает, что параметр не указан.
+func parseTimeParam(values url.Values, key string) (*stdTime.Time, error) {
+	if !values.Has(key) {
+		return nil, nil
+	}
+
+	value, err := stdTime.Parse(stdTime.RFC3339, values.Get(key))
+	if err != nil {
+		return nil, errors.Wrapf(ErrorIncorrectQueryParam, "with field %s and value %s", key, values.Get(key))
+	}
+
+	return &value, nil
+}
+
+func parseUintParam(values url.Values, key string, defaultValue uint64) (uint64, error) {
+	if !values.Has(key) {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.ParseUint(values.Get(key), 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(ErrorIncorrectQueryParam, "with field %s and value %s", key, values.Get(key))
+	}
+
+	return value, nil
+}
+
+func parseOptionalUintParam(values url.Values, key string) (*uint64, error) {
+	if !values.Has(key) {
+		return nil, nil
+	}
+
+	value, err := parseUintParam(values, key, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &value, nil
+}
+
+func parseBoolParam(values url.Values, key string, defaultValue bool) (bool, error) {
+	if !values.Has(key) {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.ParseBool(values.Get(key))
+	if err != nil {
+		return false, errors.Wrapf(ErrorIncorrectQueryParam, "with field %s and value %s", key, values.Get(key))
+	}
+
+	return value, nil
+}
+
+// parseLimitParam
+// Получает размер выборки. Значение должно быть в диапазоне от 1 до maxValue.
+func parseLimitParam(values url.Values, defaultValue, maxValue uint64) (uint64, error) {
+	limit, err := parseUintParam(values, LimitKey, defaultValue)
+	if err != nil {
+		return 0, err
+	}
+
+	if limit == 0 || limit > maxValue {
+		return 0, errors.Wrapf(ErrorIncorrectQueryParam, "with field %s and value %d", LimitKey, limit)
+	}
+
+	return limit, nil
+}
+
+// getMaxCertification
+// Получает ограничение возрастного рейтинга текущего пользователя. Nil означает отсутствие ограничения.
+func getMaxCertification(r *http.Request) *types.Certification {
+	if usr := middleware.GetUser(r); usr != nil {
+		return usr.MaxCertification
+	}
+
+	return nil
+}
+
+// clientIP
+// Получает адрес клиента без порта. Заголовки прокси не учитываются, так как их может подделать клиент.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// clientInfo
+// Получает адрес и User-Agent клиента для сохранения вместе с сессией
+func clientInfo(r *http.Request) auth.ClientInfo {
+	return auth.ClientInfo{
+		IP:        clientIP(r),
+		UserAgent: r.UserAgent(),
+	}
+}
+
+// retryAfterSeconds
+// Переводит время ожидания в значение заголовка Retry-After, округляя вверх до секунд
+func retryAfterSeconds(retryAfter stdTime.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(retryAfter.Seconds())), 10)
+}
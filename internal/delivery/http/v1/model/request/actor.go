@@ -7,9 +7,14 @@ import (
 )
 
 type CreateActor struct {
-	Name     string             `json:"name" swaggertype:"string" example:"Тимоти Шаламе"`
-	Sex      string             `json:"sex" swaggertype:"string" example:"male" enums:"male,female"`
-	Birthday time.FormattedTime `json:"birthday" swaggertype:"string" format:"date" example:"12.02.2002"`
+	Name       string              `json:"name" swaggertype:"string" example:"Тимоти Шаламе"`
+	Sex        string              `json:"sex" swaggertype:"string" example:"male" enums:"male,female"`
+	Birthday   time.FormattedTime  `json:"birthday" swaggertype:"string" format:"date" example:"12.02.2002"`
+	DeathDate  *time.FormattedTime `json:"death_date,omitempty" swaggertype:"string" format:"date" example:"12.02.2092"`
+	BirthPlace *string             `json:"birth_place,omitempty" swaggertype:"string" example:"Нью-Йорк"`
+	Country    *string             `json:"country,omitempty" swaggertype:"string" example:"США"`
+	Biography  *string             `json:"biography,omitempty" swaggertype:"string" example:"Американский актёр"`
+	Aliases    []string            `json:"aliases,omitempty" example:"Timothée Chalamet,Тимоти Шаламэ"`
 }
 
 func ValidateCreateActor(data []byte) error {
@@ -17,14 +22,24 @@ func ValidateCreateActor(data []byte) error {
 		vjson.String("name").Required(),
 		vjson.String("sex").Choices("male", "female").Required(),
 		vjson.String("birthday").Required(),
+		vjson.String("death_date"),
+		vjson.String("birth_place").MaxLength(200),
+		vjson.String("country").MaxLength(100),
+		vjson.String("biography").MaxLength(5000),
+		vjson.Array("aliases", vjson.String("item").MinLength(1)),
 	)
 	return schema.ValidateBytes(data)
 }
 
 type UpdateActor struct {
-	Name     *string             `json:"name,omitempty" swaggertype:"string" example:"Тимоти Шаламе"`
-	Sex      *string             `json:"sex,omitempty" swaggertype:"string" example:"male" enums:"male,female"`
-	Birthday *time.FormattedTime `json:"birthday,omitempty" swaggertype:"string" format:"date" example:"12.02.2002"`
+	Name       *string             `json:"name,omitempty" swaggertype:"string" example:"Тимоти Шаламе"`
+	Sex        *string             `json:"sex,omitempty" swaggertype:"string" example:"male" enums:"male,female"`
+	Birthday   *time.FormattedTime `json:"birthday,omitempty" swaggertype:"string" format:"date" example:"12.02.2002"`
+	DeathDate  *time.FormattedTime `json:"death_date,omitempty" swaggertype:"string" format:"date" example:"12.02.2092"`
+	BirthPlace *string             `json:"birth_place,omitempty" swaggertype:"string" example:"Нью-Йорк"`
+	Country    *string             `json:"country,omitempty" swaggertype:"string" example:"США"`
+	Biography  *string             `json:"biography,omitempty" swaggertype:"string" example:"Американский актёр"`
+	Aliases    *[]string           `json:"aliases,omitempty" example:"Timothée Chalamet,Тимоти Шаламэ"`
 }
 
 func ValidateUpdateActor(data []byte) error {
@@ -32,6 +47,11 @@ func ValidateUpdateActor(data []byte) error {
 		vjson.String("name"),
 		vjson.String("sex").Choices("male", "female"),
 		vjson.String("birthday"),
+		vjson.String("death_date"),
+		vjson.String("birth_place").MaxLength(200),
+		vjson.String("country").MaxLength(100),
+		vjson.String("biography").MaxLength(5000),
+		vjson.Array("aliases", vjson.String("item").MinLength(1)),
 	)
 	return schema.ValidateBytes(data)
 }
@@ -5,6 +5,7 @@ import (
 	"vk_film/internal/pkg/evjson"
 	"vk_film/internal/pkg/time"
 	"vk_film/internal/pkg/types"
+	"vk_film/pkg/slices"
 )
 
 type CreateFilm struct {
@@ -13,6 +14,23 @@ type CreateFilm struct {
 	DataPublish time.FormattedTime `json:"data_publish" swaggertype:"string" format:"date" example:"12.02.2023"`
 	Rating      types.Rating       `json:"rating" swaggertype:"integer" format:"uint8" example:"9"`
 	Actors      []types.Id         `json:"actors"`
+
+	Runtime          *uint16              `json:"runtime,omitempty" swaggertype:"integer" format:"uint16" example:"155"`
+	Countries        []string             `json:"countries,omitempty" example:"США,Канада"`
+	OriginalLanguage *string              `json:"original_language,omitempty" swaggertype:"string" example:"en"`
+	AgeCertification *types.Certification `json:"age_certification,omitempty" swaggertype:"string" example:"PG-13" enums:"0+,6+,12+,16+,18+,G,PG,PG-13,R,NC-17"`
+}
+
+const (
+	MaxRuntime         = 1000
+	MaxCountryLength   = 100
+	languageCodeFormat = "^[a-z]{2}$"
+)
+
+func certificationChoices() []string {
+	return slices.Map(types.Certifications, func(c types.Certification) string {
+		return string(c)
+	})
 }
 
 func ValidateCreateFilm(data []byte) error {
@@ -22,6 +40,10 @@ func ValidateCreateFilm(data []byte) error {
 		vjson.String("data_publish").Required(),
 		vjson.Integer("rating").Range(0, 10).Required(),
 		vjson.Array("actors", vjson.Integer("item").Positive()).Required(),
+		vjson.Integer("runtime").Range(1, MaxRuntime),
+		vjson.Array("countries", vjson.String("item").MinLength(1).MaxLength(MaxCountryLength)),
+		vjson.String("original_language").Format(languageCodeFormat),
+		vjson.String("age_certification").Choices(certificationChoices()...),
 	)
 	return schema.ValidateBytes(data)
 }
@@ -32,6 +54,11 @@ type UpdateFilm struct {
 	DataPublish *time.FormattedTime `json:"data_publish,omitempty" swaggertype:"string" format:"date" example:"12.02.2023"`
 	Rating      *types.Rating       `json:"rating,omitempty" swaggertype:"integer" format:"uint8" example:"9"`
 	Actors      *[]types.Id         `json:"actors,omitempty"`
+
+	Runtime          *uint16              `json:"runtime,omitempty" swaggertype:"integer" format:"uint16" example:"155"`
+	Countries        *[]string            `json:"countries,omitempty" example:"США,Канада"`
+	OriginalLanguage *string              `json:"original_language,omitempty" swaggertype:"string" example:"en"`
+	AgeCertification *types.Certification `json:"age_certification,omitempty" swaggertype:"string" example:"PG-13" enums:"0+,6+,12+,16+,18+,G,PG,PG-13,R,NC-17"`
 }
 
 func ValidateUpdateFilm(data []byte) error {
@@ -41,6 +68,10 @@ func ValidateUpdateFilm(data []byte) error {
 		vjson.String("data_publish"),
 		vjson.Integer("rating").Range(0, 10),
 		vjson.Array("actors", vjson.Integer("item").Positive()),
+		vjson.Integer("runtime").Range(1, MaxRuntime),
+		vjson.Array("countries", vjson.String("item").MinLength(1).MaxLength(MaxCountryLength)),
+		vjson.String("original_language").Format(languageCodeFormat),
+		vjson.String("age_certification").Choices(certificationChoices()...),
 	)
 	return schema.ValidateBytes(data)
 }
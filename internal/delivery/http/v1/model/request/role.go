@@ -0,0 +1,49 @@
+package request
+
+import (
+	"github.com/miladibra10/vjson"
+	"vk_film/internal/pkg/evjson"
+	"vk_film/internal/pkg/types"
+	"vk_film/pkg/slices"
+)
+
+const (
+	RoleNameFormat           = "^[a-z][a-z0-9_-]{0,49}$"
+	MaxRoleDescriptionLength = 200
+)
+
+func permissionChoices() []string {
+	return slices.Map(types.Permissions, func(p types.Permission) string {
+		return string(p)
+	})
+}
+
+type CreateRole struct {
+	Name        string             `json:"name" swaggertype:"string" example:"editor"`
+	Description string             `json:"description,omitempty" swaggertype:"string" example:"Редактор каталога"`
+	Permissions []types.Permission `json:"permissions" swaggertype:"array,string" example:"film:write,actor:write" enums:"film:write,film:delete,actor:write,actor:delete,user:manage,user:moderate,role:manage"`
+}
+
+func ValidateCreateRole(data []byte) error {
+	schema := evjson.NewSchema(
+		vjson.String("name").Format(RoleNameFormat).Required(),
+		vjson.String("description").MaxLength(MaxRoleDescriptionLength),
+		vjson.Array("permissions", vjson.String("item").Choices(permissionChoices()...)).Required(),
+	)
+
+	return schema.ValidateBytes(data)
+}
+
+type UpdateRolePermissions struct {
+	Description string             `json:"description,omitempty" swaggertype:"string" example:"Редактор каталога"`
+	Permissions []types.Permission `json:"permissions" swaggertype:"array,string" example:"film:write,actor:write" enums:"film:write,film:delete,actor:write,actor:delete,user:manage,user:moderate,role:manage"`
+}
+
+func ValidateUpdateRolePermissions(data []byte) error {
+	schema := evjson.NewSchema(
+		vjson.String("description").MaxLength(MaxRoleDescriptionLength),
+		vjson.Array("permissions", vjson.String("item").Choices(permissionChoices()...)).Required(),
+	)
+
+	return schema.ValidateBytes(data)
+}
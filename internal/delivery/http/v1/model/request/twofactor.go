@@ -0,0 +1,46 @@
+package request
+
+import (
+	"github.com/miladibra10/vjson"
+	"vk_film/internal/pkg/evjson"
+)
+
+type VerifyLogin struct {
+	Token      string `json:"token" swaggertype:"string" example:"vkc_5b1e9a7f..."`
+	Code       string `json:"code" swaggertype:"string" example:"123456"`
+	RememberMe bool   `json:"remember_me,omitempty" swaggertype:"boolean" example:"true" default:"false"`
+}
+
+func ValidateVerifyLogin(data []byte) error {
+	schema := evjson.NewSchema(
+		vjson.String("token").Required(),
+		vjson.String("code").MinLength(1).Required(),
+		vjson.Boolean("remember_me"),
+	)
+
+	return schema.ValidateBytes(data)
+}
+
+type EnrollLogin struct {
+	Token string `json:"token" swaggertype:"string" example:"vkc_5b1e9a7f..."`
+}
+
+func ValidateEnrollLogin(data []byte) error {
+	schema := evjson.NewSchema(
+		vjson.String("token").Required(),
+	)
+
+	return schema.ValidateBytes(data)
+}
+
+type TwoFactorCode struct {
+	Code string `json:"code" swaggertype:"string" example:"123456"`
+}
+
+func ValidateTwoFactorCode(data []byte) error {
+	schema := evjson.NewSchema(
+		vjson.String("code").MinLength(1).Required(),
+	)
+
+	return schema.ValidateBytes(data)
+}
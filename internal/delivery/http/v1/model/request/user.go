@@ -2,46 +2,181 @@ package request
 
 import (
 	"github.com/miladibra10/vjson"
+	"time"
 	"vk_film/internal/pkg/evjson"
+	"vk_film/internal/pkg/types"
 )
 
+// EmailFormat
+// Проверяется только общий вид адреса, существование ящика подтверждает доставка письма
+const EmailFormat = `^[^@\s]+@[^@\s]+\.[^@\s]+$`
+
 type CreateUser struct {
 	Login    string `json:"login" swaggertype:"string" example:"login"`
 	Password string `json:"password" swaggertype:"string" example:"password"`
-	Role     string `json:"role,omitempty" swaggertype:"string" example:"user" enums:"user,admin" default:"user"`
+	Role     string `json:"role,omitempty" swaggertype:"string" example:"editor" default:"user"`
+	Email    string `json:"email,omitempty" swaggertype:"string" example:"user@example.com"`
 }
 
 func ValidateCreateUser(data []byte) error {
 	schema := evjson.NewSchema(
 		vjson.String("login").Required(),
 		vjson.String("password").Required(),
-		vjson.String("role").Choices("user", "admin"),
+		vjson.String("role").Format(RoleNameFormat),
+		vjson.String("email").Format(EmailFormat),
 	)
 
 	return schema.ValidateBytes(data)
 }
 
 type Login struct {
-	Login    string `json:"login" swaggertype:"string" example:"login"`
-	Password string `json:"password" swaggertype:"string" example:"password"`
+	Login      string `json:"login" swaggertype:"string" example:"login"`
+	Password   string `json:"password" swaggertype:"string" example:"password"`
+	RememberMe bool   `json:"remember_me,omitempty" swaggertype:"boolean" example:"true" default:"false"`
 }
 
 func ValidateLogin(data []byte) error {
 	schema := evjson.NewSchema(
 		vjson.String("login").Required(),
 		vjson.String("password").Required(),
+		vjson.Boolean("remember_me"),
+	)
+
+	return schema.ValidateBytes(data)
+}
+
+type Register struct {
+	Login    string `json:"login" swaggertype:"string" example:"login"`
+	Password string `json:"password" swaggertype:"string" example:"Str0ng-password"`
+	Email    string `json:"email,omitempty" swaggertype:"string" example:"user@example.com"`
+}
+
+func ValidateRegister(data []byte) error {
+	schema := evjson.NewSchema(
+		vjson.String("login").Required(),
+		vjson.String("password").Required(),
+		vjson.String("email").Format(EmailFormat),
+	)
+
+	return schema.ValidateBytes(data)
+}
+
+type UpdateEmail struct {
+	CurrentPassword string `json:"current_password" swaggertype:"string" example:"password"`
+	Email           string `json:"email" swaggertype:"string" example:"user@example.com"`
+}
+
+func ValidateUpdateEmail(data []byte) error {
+	// Пустой адрес удаляет почту пользователя
+	schema := evjson.NewSchema(
+		vjson.String("current_password").Required(),
+		vjson.String("email").Format("^$|"+EmailFormat).Required(),
+	)
+
+	return schema.ValidateBytes(data)
+}
+
+type RequestPasswordReset struct {
+	Login string `json:"login,omitempty" swaggertype:"string" example:"login"`
+	Email string `json:"email,omitempty" swaggertype:"string" example:"user@example.com"`
+}
+
+func ValidateRequestPasswordReset(data []byte) error {
+	schema := evjson.NewSchema(
+		vjson.String("login").MinLength(1),
+		vjson.String("email").Format(EmailFormat),
+	)
+
+	return schema.ValidateBytes(data)
+}
+
+type ConfirmPasswordReset struct {
+	Token    string `json:"token" swaggertype:"string" example:"vkp_3f7a0c9d..."`
+	Password string `json:"password" swaggertype:"string" example:"new password"`
+}
+
+func ValidateConfirmPasswordReset(data []byte) error {
+	schema := evjson.NewSchema(
+		vjson.String("token").Required(),
+		vjson.String("password").MinLength(1).Required(),
 	)
 
 	return schema.ValidateBytes(data)
 }
 
 type UpdateRole struct {
-	Role string `json:"role" swaggertype:"string" example:"user" enums:"user,admin"`
+	Role string `json:"role" swaggertype:"string" example:"editor"`
 }
 
 func ValidateUpdateRole(data []byte) error {
 	schema := evjson.NewSchema(
-		vjson.String("role").Choices("user", "admin").Required(),
+		vjson.String("role").Format(RoleNameFormat).Required(),
+	)
+
+	return schema.ValidateBytes(data)
+}
+
+type UpdateMaxCertification struct {
+	MaxCertification *types.Certification `json:"max_certification" swaggertype:"string" example:"12+" enums:"0+,6+,12+,16+,18+,G,PG,PG-13,R,NC-17"`
+}
+
+func ValidateUpdateMaxCertification(data []byte) error {
+	schema := evjson.NewSchema(
+		vjson.String("max_certification").Choices(certificationChoices()...),
+	)
+
+	return schema.ValidateBytes(data)
+}
+
+type ChangePassword struct {
+	CurrentPassword string `json:"current_password" swaggertype:"string" example:"password"`
+	NewPassword     string `json:"new_password" swaggertype:"string" example:"new password"`
+}
+
+func ValidateChangePassword(data []byte) error {
+	schema := evjson.NewSchema(
+		vjson.String("current_password").Required(),
+		vjson.String("new_password").MinLength(1).Required(),
+	)
+
+	return schema.ValidateBytes(data)
+}
+
+type ResetPassword struct {
+	Password string `json:"password" swaggertype:"string" example:"password"`
+}
+
+func ValidateResetPassword(data []byte) error {
+	schema := evjson.NewSchema(
+		vjson.String("password").MinLength(1).Required(),
+	)
+
+	return schema.ValidateBytes(data)
+}
+
+type CreateToken struct {
+	Name      string     `json:"name" swaggertype:"string" example:"ci"`
+	ReadOnly  bool       `json:"read_only,omitempty" swaggertype:"boolean" example:"true" default:"false"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" swaggertype:"string" format:"date-time" example:"2030-01-02T15:04:05Z"`
+}
+
+func ValidateCreateToken(data []byte) error {
+	schema := evjson.NewSchema(
+		vjson.String("name").MinLength(1).MaxLength(100).Required(),
+		vjson.Boolean("read_only"),
+		vjson.String("expires_at"),
+	)
+
+	return schema.ValidateBytes(data)
+}
+
+type Refresh struct {
+	RefreshToken string `json:"refresh_token" swaggertype:"string" example:"vkr_3f7a0c9d..."`
+}
+
+func ValidateRefresh(data []byte) error {
+	schema := evjson.NewSchema(
+		vjson.String("refresh_token").Required(),
 	)
 
 	return schema.ValidateBytes(data)
@@ -9,10 +9,15 @@ import (
 )
 
 type Actor struct {
-	ID       types.Id           `json:"id" swaggertype:"integer" format:"uint64" example:"5"`
-	Name     string             `json:"name" swaggertype:"string" example:"Тимоти Шаламе"`
-	Sex      string             `json:"sex" swaggertype:"string" example:"male" enums:"male,female"`
-	Birthday time.FormattedTime `json:"birthday" swaggertype:"string" format:"date" example:"12.02.2002"`
+	ID         types.Id            `json:"id" swaggertype:"integer" format:"uint64" example:"5"`
+	Name       string              `json:"name" swaggertype:"string" example:"Тимоти Шаламе"`
+	Sex        string              `json:"sex" swaggertype:"string" example:"male" enums:"male,female"`
+	Birthday   time.FormattedTime  `json:"birthday" swaggertype:"string" format:"date" example:"12.02.2002"`
+	DeathDate  *time.FormattedTime `json:"death_date,omitempty" swaggertype:"string" format:"date" example:"12.02.2092"`
+	BirthPlace *string             `json:"birth_place,omitempty" swaggertype:"string" example:"Нью-Йорк"`
+	Country    *string             `json:"country,omitempty" swaggertype:"string" example:"США"`
+	Biography  *string             `json:"biography,omitempty" swaggertype:"string" example:"Американский актёр"`
+	Aliases    []string            `json:"aliases,omitempty" example:"Timothée Chalamet,Тимоти Шаламэ"`
 }
 
 type ActorWithFilms struct {
@@ -34,14 +39,23 @@ func FromRepositoryActorsWithFilms(actorsRepository []actor.ActorWithFilms) []Ac
 	})
 }
 
+func FromRepositoryActor(actorRepository *actor.Actor) *Actor {
+	return &Actor{
+		ID:         actorRepository.ID,
+		Name:       actorRepository.Name,
+		Sex:        string(actorRepository.Sex),
+		Birthday:   actorRepository.Birthday,
+		DeathDate:  actorRepository.DeathDate,
+		BirthPlace: actorRepository.BirthPlace,
+		Country:    actorRepository.Country,
+		Biography:  actorRepository.Biography,
+		Aliases:    actorRepository.Aliases,
+	}
+}
+
 func FromRepositoryActorWithFilms(actorRepository *actor.ActorWithFilms) *ActorWithFilms {
 	return &ActorWithFilms{
-		Actor: Actor{
-			ID:       actorRepository.ID,
-			Name:     actorRepository.Name,
-			Sex:      string(actorRepository.Sex),
-			Birthday: actorRepository.Birthday,
-		},
+		Actor: *FromRepositoryActor(&actorRepository.Actor),
 		Films: slices.Map(actorRepository.Films, func(flm film.Film) ActorFilms {
 			return ActorFilms{
 				ID:          flm.ID,
@@ -0,0 +1,29 @@
+package response
+
+import (
+	"time"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/event"
+)
+
+type SecurityEvent struct {
+	ID        types.Id  `json:"id" swaggertype:"integer" format:"uint64" example:"42"`
+	UserID    types.Id  `json:"user_id,omitempty" swaggertype:"integer" format:"uint64" example:"1"`
+	Login     string    `json:"login" swaggertype:"string" example:"login"`
+	Type      string    `json:"type" swaggertype:"string" example:"login_failed" enums:"login_succeeded,login_failed,logout,password_changed,password_reset,lockout"`
+	IP        string    `json:"ip" swaggertype:"string" example:"192.168.0.1"`
+	UserAgent string    `json:"user_agent" swaggertype:"string" example:"Mozilla/5.0"`
+	CreatedAt time.Time `json:"created_at" swaggertype:"string" format:"date-time" example:"2024-01-02T15:04:05Z"`
+}
+
+func FromRepositoryEvent(eventRepository *event.Event) SecurityEvent {
+	return SecurityEvent{
+		ID:        eventRepository.ID,
+		UserID:    eventRepository.UserID,
+		Login:     eventRepository.Login,
+		Type:      string(eventRepository.Type),
+		IP:        eventRepository.IP,
+		UserAgent: eventRepository.UserAgent,
+		CreatedAt: eventRepository.CreatedAt,
+	}
+}
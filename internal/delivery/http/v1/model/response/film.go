@@ -13,7 +13,13 @@ type Film struct {
 	Description string             `json:"description" swaggertype:"string" example:"Futuristic film"`
 	DataPublish time.FormattedTime `json:"data_publish" swaggertype:"string" format:"date" example:"12.02.2023"`
 	Rating      types.Rating       `json:"rating" swaggertype:"integer" format:"uint8" example:"9"`
-	Actors      []FilmActors       `json:"actors,omitempty"`
+
+	Runtime          *uint16  `json:"runtime,omitempty" swaggertype:"integer" format:"uint16" example:"155"`
+	Countries        []string `json:"countries,omitempty" example:"США,Канада"`
+	OriginalLanguage *string  `json:"original_language,omitempty" swaggertype:"string" example:"en"`
+	AgeCertification *string  `json:"age_certification,omitempty" swaggertype:"string" example:"PG-13" enums:"0+,6+,12+,16+,18+,G,PG,PG-13,R,NC-17"`
+
+	Actors []FilmActors `json:"actors,omitempty"`
 }
 
 type FilmActors struct {
@@ -33,6 +39,12 @@ func FromRepositoryFilmWithActor(filmRepository *film.FilmWithActors) *Film {
 		Description: filmRepository.Description,
 		DataPublish: filmRepository.DataPublish,
 		Rating:      filmRepository.Rating,
+
+		Runtime:          filmRepository.Runtime,
+		Countries:        filmRepository.Countries,
+		OriginalLanguage: filmRepository.OriginalLanguage,
+		AgeCertification: (*string)(filmRepository.AgeCertification),
+
 		Actors: slices.Map(filmRepository.Actors, func(act film.Actor) FilmActors {
 			return FilmActors{
 				Actor: Actor{
@@ -0,0 +1,27 @@
+package response
+
+import (
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/role"
+	"vk_film/pkg/slices"
+)
+
+type Role struct {
+	Name        string   `json:"name" swaggertype:"string" example:"editor"`
+	Description string   `json:"description" swaggertype:"string" example:"Редактор каталога"`
+	Permissions []string `json:"permissions" swaggertype:"array,string" example:"film:write,actor:write"`
+}
+
+func FromRepositoryRole(roleRepository *role.Role) Role {
+	return Role{
+		Name:        string(roleRepository.Name),
+		Description: roleRepository.Description,
+		Permissions: permissionsToStrings(roleRepository.Permissions),
+	}
+}
+
+func permissionsToStrings(permissions []types.Permission) []string {
+	return slices.Map(permissions, func(p types.Permission) string {
+		return string(p)
+	})
+}
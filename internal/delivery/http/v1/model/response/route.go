@@ -0,0 +1,9 @@
+package response
+
+type Route struct {
+	Method       string   `json:"method" swaggertype:"string" example:"DELETE"`
+	Path         string   `json:"path" swaggertype:"string" example:"/api/v1/film/{film_id}"`
+	AuthRequired bool     `json:"auth_required" swaggertype:"boolean" example:"true"`
+	Permissions  []string `json:"permissions" swaggertype:"array,string" example:"film:delete"`
+	Roles        []string `json:"roles" swaggertype:"array,string" example:"admin"`
+}
@@ -0,0 +1,26 @@
+package response
+
+import (
+	"time"
+	"vk_film/internal/repository/session"
+)
+
+type Session struct {
+	ID         string    `json:"id" swaggertype:"string" example:"3f7a0c9d1b2e4f56"`
+	CreatedAt  time.Time `json:"created_at" swaggertype:"string" format:"date-time" example:"2024-01-02T15:04:05Z"`
+	LastSeenAt time.Time `json:"last_seen_at" swaggertype:"string" format:"date-time" example:"2024-03-02T15:04:05Z"`
+	IP         string    `json:"ip" swaggertype:"string" example:"192.168.0.1"`
+	UserAgent  string    `json:"user_agent" swaggertype:"string" example:"Mozilla/5.0"`
+	Current    bool      `json:"current" swaggertype:"boolean" example:"true"`
+}
+
+func FromRepositorySession(sessionRepository *session.Session) Session {
+	return Session{
+		ID:         sessionRepository.ID,
+		CreatedAt:  sessionRepository.CreatedAt,
+		LastSeenAt: sessionRepository.LastSeenAt,
+		IP:         sessionRepository.IP,
+		UserAgent:  sessionRepository.UserAgent,
+		Current:    sessionRepository.Current,
+	}
+}
@@ -0,0 +1,142 @@
+package response
+
+import (
+	"strconv"
+	"vk_film/internal/pkg/time"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/actor"
+	"vk_film/internal/repository/film"
+	"vk_film/internal/repository/stats"
+	"vk_film/pkg/slices"
+)
+
+type FilmsPerYear struct {
+	Year  uint64 `json:"year" swaggertype:"integer" format:"uint64" example:"2021"`
+	Count uint64 `json:"count" swaggertype:"integer" format:"uint64" example:"12"`
+}
+
+func (FilmsPerYear) CSVHeader() []string {
+	return []string{"year", "count"}
+}
+
+func (f FilmsPerYear) CSVRecord() []string {
+	return []string{strconv.FormatUint(f.Year, 10), strconv.FormatUint(f.Count, 10)}
+}
+
+type RatingCount struct {
+	Rating types.Rating `json:"rating" swaggertype:"integer" format:"uint8" example:"9"`
+	Count  uint64       `json:"count" swaggertype:"integer" format:"uint64" example:"12"`
+}
+
+func (RatingCount) CSVHeader() []string {
+	return []string{"rating", "count"}
+}
+
+func (r RatingCount) CSVRecord() []string {
+	return []string{strconv.FormatUint(uint64(r.Rating), 10), strconv.FormatUint(r.Count, 10)}
+}
+
+type ActorFilmsCount struct {
+	ID         types.Id `json:"id" swaggertype:"integer" format:"uint64" example:"5"`
+	Name       string   `json:"name" swaggertype:"string" example:"Тимоти Шаламе"`
+	FilmsCount uint64   `json:"films_count" swaggertype:"integer" format:"uint64" example:"12"`
+}
+
+func (ActorFilmsCount) CSVHeader() []string {
+	return []string{"id", "name", "films_count"}
+}
+
+func (a ActorFilmsCount) CSVRecord() []string {
+	return []string{strconv.FormatUint(uint64(a.ID), 10), a.Name, strconv.FormatUint(a.FilmsCount, 10)}
+}
+
+type ActorRating struct {
+	ID            types.Id `json:"id" swaggertype:"integer" format:"uint64" example:"5"`
+	Name          string   `json:"name" swaggertype:"string" example:"Тимоти Шаламе"`
+	AverageRating float64  `json:"average_rating" swaggertype:"number" format:"double" example:"7.5"`
+	FilmsCount    uint64   `json:"films_count" swaggertype:"integer" format:"uint64" example:"12"`
+}
+
+func (ActorRating) CSVHeader() []string {
+	return []string{"id", "name", "average_rating", "films_count"}
+}
+
+func (a ActorRating) CSVRecord() []string {
+	return []string{
+		strconv.FormatUint(uint64(a.ID), 10),
+		a.Name,
+		strconv.FormatFloat(a.AverageRating, 'f', 2, 64),
+		strconv.FormatUint(a.FilmsCount, 10),
+	}
+}
+
+type StatsFilm struct {
+	ID          types.Id           `json:"id" swaggertype:"integer" format:"uint64" example:"5"`
+	Name        string             `json:"name" swaggertype:"string" example:"Dune"`
+	DataPublish time.FormattedTime `json:"data_publish" swaggertype:"string" format:"date" example:"12.02.2023"`
+	Rating      types.Rating       `json:"rating" swaggertype:"integer" format:"uint8" example:"9"`
+}
+
+func (StatsFilm) CSVHeader() []string {
+	return []string{"id", "name", "data_publish", "rating"}
+}
+
+func (f StatsFilm) CSVRecord() []string {
+	return []string{
+		strconv.FormatUint(uint64(f.ID), 10),
+		f.Name,
+		f.DataPublish.String(),
+		strconv.FormatUint(uint64(f.Rating), 10),
+	}
+}
+
+type StatsActor struct {
+	ID       types.Id           `json:"id" swaggertype:"integer" format:"uint64" example:"5"`
+	Name     string             `json:"name" swaggertype:"string" example:"Тимоти Шаламе"`
+	Sex      string             `json:"sex" swaggertype:"string" example:"male" enums:"male,female"`
+	Birthday time.FormattedTime `json:"birthday" swaggertype:"string" format:"date" example:"12.02.2002"`
+}
+
+func (StatsActor) CSVHeader() []string {
+	return []string{"id", "name", "sex", "birthday"}
+}
+
+func (a StatsActor) CSVRecord() []string {
+	return []string{strconv.FormatUint(uint64(a.ID), 10), a.Name, a.Sex, a.Birthday.String()}
+}
+
+func FromRepositoryFilmsPerYear(years []stats.FilmsPerYear) []FilmsPerYear {
+	return slices.Map(years, func(year stats.FilmsPerYear) FilmsPerYear {
+		return FilmsPerYear{Year: year.Year, Count: year.Count}
+	})
+}
+
+func FromRepositoryRatingDistribution(ratings []stats.RatingCount) []RatingCount {
+	return slices.Map(ratings, func(rating stats.RatingCount) RatingCount {
+		return RatingCount{Rating: rating.Rating, Count: rating.Count}
+	})
+}
+
+func FromRepositoryTopActors(actors []stats.ActorFilmsCount) []ActorFilmsCount {
+	return slices.Map(actors, func(act stats.ActorFilmsCount) ActorFilmsCount {
+		return ActorFilmsCount{ID: act.ID, Name: act.Name, FilmsCount: act.FilmsCount}
+	})
+}
+
+func FromRepositoryActorsRating(actors []stats.ActorRating) []ActorRating {
+	return slices.Map(actors, func(act stats.ActorRating) ActorRating {
+		return ActorRating{ID: act.ID, Name: act.Name, AverageRating: act.AverageRating, FilmsCount: act.FilmsCount}
+	})
+}
+
+func FromRepositoryStatsFilms(films []film.Film) []StatsFilm {
+	return slices.Map(films, func(flm film.Film) StatsFilm {
+		return StatsFilm{ID: flm.ID, Name: flm.Name, DataPublish: flm.DataPublish, Rating: flm.Rating}
+	})
+}
+
+func FromRepositoryStatsActors(actors []actor.Actor) []StatsActor {
+	return slices.Map(actors, func(act actor.Actor) StatsActor {
+		return StatsActor{ID: act.ID, Name: act.Name, Sex: string(act.Sex), Birthday: act.Birthday}
+	})
+}
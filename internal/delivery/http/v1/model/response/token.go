@@ -0,0 +1,32 @@
+package response
+
+import (
+	"time"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/token"
+)
+
+type Token struct {
+	ID         types.Id   `json:"id" swaggertype:"integer" format:"uint64" example:"5"`
+	Name       string     `json:"name" swaggertype:"string" example:"ci"`
+	ReadOnly   bool       `json:"read_only" swaggertype:"boolean" example:"true"`
+	CreatedAt  time.Time  `json:"created_at" swaggertype:"string" format:"date-time" example:"2024-01-02T15:04:05Z"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" swaggertype:"string" format:"date-time" example:"2030-01-02T15:04:05Z"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" swaggertype:"string" format:"date-time" example:"2024-03-02T15:04:05Z"`
+}
+
+func FromRepositoryToken(tokenRepository *token.Token) Token {
+	return Token{
+		ID:         tokenRepository.ID,
+		Name:       tokenRepository.Name,
+		ReadOnly:   tokenRepository.ReadOnly,
+		CreatedAt:  tokenRepository.CreatedAt,
+		ExpiresAt:  tokenRepository.ExpiresAt,
+		LastUsedAt: tokenRepository.LastUsedAt,
+	}
+}
+
+type CreatedToken struct {
+	Token
+	Secret string `json:"token" swaggertype:"string" example:"vkf_3f7a0c9d..."`
+}
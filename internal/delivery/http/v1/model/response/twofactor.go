@@ -0,0 +1,35 @@
+package response
+
+import (
+	"vk_film/internal/usecase/auth"
+)
+
+type PendingLogin struct {
+	Token          string `json:"token" swaggertype:"string" example:"vkc_5b1e9a7f..."`
+	ExpiresIn      int64  `json:"expires_in" swaggertype:"integer" format:"int64" example:"300"`
+	EnrollRequired bool   `json:"enroll_required" swaggertype:"boolean" example:"false"`
+}
+
+func FromPendingCredentials(credentials *auth.Credentials) PendingLogin {
+	return PendingLogin{
+		Token:          credentials.SessionId,
+		ExpiresIn:      int64(credentials.ExpiresIn.Seconds()),
+		EnrollRequired: credentials.EnrollRequired,
+	}
+}
+
+type Enrollment struct {
+	Secret string `json:"secret" swaggertype:"string" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	URI    string `json:"uri" swaggertype:"string" example:"otpauth://totp/VK%20Film:admin?algorithm=SHA1&digits=6&issuer=VK+Film&period=30&secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+}
+
+func FromEnrollment(enrollment *auth.Enrollment) Enrollment {
+	return Enrollment{
+		Secret: enrollment.Secret,
+		URI:    enrollment.URI,
+	}
+}
+
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes" swaggertype:"array,string" example:"3f7a0-c9d12,8e21b-04fa9"`
+}
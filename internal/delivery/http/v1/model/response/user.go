@@ -1,9 +1,47 @@
 package response
 
-import "vk_film/internal/pkg/types"
+import (
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/user"
+	"vk_film/internal/usecase/auth"
+)
 
 type User struct {
-	ID    types.Id `json:"id" swaggertype:"integer" format:"uint64" example:"5"`
-	Login string   `json:"login" swaggertype:"string" example:"login"`
-	Role  string   `json:"role" swaggertype:"string" example:"user" enums:"user,admin"`
+	ID               types.Id `json:"id" swaggertype:"integer" format:"uint64" example:"5"`
+	Login            string   `json:"login" swaggertype:"string" example:"login"`
+	Email            string   `json:"email,omitempty" swaggertype:"string" example:"user@example.com"`
+	Role             string   `json:"role" swaggertype:"string" example:"editor"`
+	Status           string   `json:"status,omitempty" swaggertype:"string" example:"active" enums:"active,pending,rejected"`
+	MaxCertification *string  `json:"max_certification,omitempty" swaggertype:"string" example:"12+" enums:"0+,6+,12+,16+,18+,G,PG,PG-13,R,NC-17"`
+	Permissions      []string `json:"permissions,omitempty" swaggertype:"array,string" example:"film:write,actor:write"`
+}
+
+func FromRepositoryUser(userRepository *user.User) User {
+	return User{
+		ID:               userRepository.ID,
+		Login:            userRepository.Login,
+		Email:            userRepository.Email,
+		Role:             string(userRepository.Role),
+		Status:           string(userRepository.Status),
+		MaxCertification: (*string)(userRepository.MaxCertification),
+		Permissions:      permissionsToStrings(userRepository.Permissions),
+	}
+}
+
+type Credentials struct {
+	AccessToken   string   `json:"access_token" swaggertype:"string" example:"eyJhbGciOiJIUzI1NiIs..."`
+	RefreshToken  string   `json:"refresh_token" swaggertype:"string" example:"vkr_3f7a0c9d..."`
+	TokenType     string   `json:"token_type" swaggertype:"string" example:"Bearer"`
+	ExpiresIn     int64    `json:"expires_in" swaggertype:"integer" format:"int64" example:"900"`
+	RecoveryCodes []string `json:"recovery_codes,omitempty" swaggertype:"array,string" example:"3f7a0-c9d12"`
+}
+
+func FromCredentials(credentials *auth.Credentials) Credentials {
+	return Credentials{
+		AccessToken:   credentials.SessionId,
+		RefreshToken:  credentials.RefreshToken,
+		TokenType:     "Bearer",
+		ExpiresIn:     int64(credentials.ExpiresIn.Seconds()),
+		RecoveryCodes: credentials.RecoveryCodes,
+	}
 }
@@ -1,35 +1,116 @@
 package v1
 
 import (
+	"github.com/pkg/errors"
 	"net/url"
+	"vk_film/internal/delivery/http/v1/model/response"
 	middleware2 "vk_film/internal/delivery/middleware"
+	"vk_film/internal/pkg/types"
 	"vk_film/pkg/logger"
 	"vk_film/pkg/mux"
+	"vk_film/pkg/slices"
 )
 
 const version = "v1"
 
+var ErrorNoAuthMiddleware = errors.New("route requires authentication, but auth middleware is not set")
+
 type Route struct {
 	Method      string
 	Pattern     string
 	HandlerFunc mux.ExtendedHandleFunc
+	// Auth требует авторизации пользователя. Если указаны права или роли, авторизация требуется всегда.
+	Auth bool
+	// Permissions права, которые должны быть у пользователя одновременно
+	Permissions []types.Permission
+	// Roles роли, одна из которых должна быть у пользователя
+	Roles []types.Roles
+}
+
+// AuthRequired
+// Проверяет, требует ли маршрут авторизации пользователя
+func (rt *Route) AuthRequired() bool {
+	return rt.Auth || len(rt.Permissions) > 0 || len(rt.Roles) > 0
+}
+
+// handler
+// Оборачивает обработчик маршрута проверкой авторизации, прав и ролей
+func (rt *Route) handler(auth mux.MiddlewareFunc) (mux.ExtendedHandleFunc, error) {
+	handler := rt.HandlerFunc
+	if !rt.AuthRequired() {
+		return handler, nil
+	}
+
+	if auth == nil {
+		return nil, errors.Wrapf(ErrorNoAuthMiddleware, "route %s %s", rt.Method, rt.Pattern)
+	}
+
+	if len(rt.Permissions) > 0 {
+		handler = middleware2.RequirePermissions(rt.Permissions...)(handler)
+	}
+
+	if len(rt.Roles) > 0 {
+		handler = middleware2.RequireRoles(rt.Roles...)(handler)
+	}
+
+	return auth(handler), nil
 }
 
 type Routes []Route
 
-func NewRouter(root string, l logger.Interface, routes Routes) (*mux.Mux, error) {
+func routePath(root string, pattern string) (string, error) {
+	base, err := url.Parse(root)
+	if err != nil {
+		return "", err
+	}
+
+	return base.JoinPath(version, pattern).Path, nil
+}
+
+// NewRouter
+// Регистрирует маршруты, оборачивая требующие авторизации обработчики в auth и проверку прав
+func NewRouter(root string, l logger.Interface, auth mux.MiddlewareFunc, routes Routes) (*mux.Mux, error) {
 	router := mux.NewMux(l, middleware2.CheckPanic, middleware2.RequestLogger(l))
 
 	for _, route := range routes {
-		base, err := url.Parse(root)
+		uRL, err := routePath(root, route.Pattern)
 		if err != nil {
 			return nil, err
 		}
 
-		uRL := base.JoinPath(version, route.Pattern).Path
+		handler, err := route.handler(auth)
+		if err != nil {
+			return nil, err
+		}
 
-		router.HandleFunc(route.Method, uRL, route.HandlerFunc)
+		router.HandleFunc(route.Method, uRL, handler)
 	}
 
 	return router, nil
 }
+
+// Describe
+// Возвращает таблицу маршрутов с требованиями к доступу для каждого из них
+func (rs Routes) Describe(root string) ([]response.Route, error) {
+	res := make([]response.Route, 0, len(rs))
+	for _, route := range rs {
+		uRL, err := routePath(root, route.Pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, response.Route{
+			Method:       route.Method,
+			Path:         uRL,
+			AuthRequired: route.AuthRequired(),
+			Permissions: slices.Map(route.Permissions, func(p types.Permission) string {
+				return string(p)
+			}),
+			Roles: slices.Map(route.Roles, func(r types.Roles) string {
+				return string(r)
+			}),
+		})
+	}
+
+	return res, nil
+}
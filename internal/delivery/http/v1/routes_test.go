@@ -0,0 +1,130 @@
+package v1
+
+import (
+	"context"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"vk_film/internal/delivery/http/v1/model/response"
+	"vk_film/internal/delivery/middleware"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/user"
+	"vk_film/pkg/logger"
+	"vk_film/pkg/mux"
+)
+
+const testUserHeader = "X-Test-User"
+
+var testUsers = map[string]*user.User{
+	"admin":  {ID: 1, Role: types.ADMIN, Permissions: types.Permissions},
+	"editor": {ID: 2, Role: "editor", Permissions: []types.Permission{types.FilmWrite, types.FilmDelete}},
+	"user":   {ID: 3, Role: types.USER},
+}
+
+// testAuth
+// Авторизует пользователя по имени из заголовка запроса
+func testAuth(fun mux.ExtendedHandleFunc) mux.ExtendedHandleFunc {
+	return func(w http.ResponseWriter, r *http.Request, params mux.Params) {
+		usr, ok := testUsers[r.Header.Get(testUserHeader)]
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		fun(w, r.WithContext(context.WithValue(r.Context(), middleware.UserField, usr)), params)
+	}
+}
+
+func okHandler(w http.ResponseWriter, _ *http.Request, _ mux.Params) {
+	w.WriteHeader(http.StatusOK)
+}
+
+type RouterSuite struct {
+	suite.Suite
+	routes Routes
+}
+
+func (rs *RouterSuite) BeforeEach(t provider.T) {
+	rs.routes = Routes{
+		{Method: http.MethodGet, Pattern: "/public", HandlerFunc: okHandler},
+		{Method: http.MethodGet, Pattern: "/private", HandlerFunc: okHandler, Auth: true},
+		{
+			Method:      http.MethodDelete,
+			Pattern:     "/film/{film_id}",
+			HandlerFunc: okHandler,
+			Permissions: []types.Permission{types.FilmDelete},
+		},
+		{Method: http.MethodGet, Pattern: "/admin", HandlerFunc: okHandler, Roles: []types.Roles{types.ADMIN}},
+	}
+}
+
+func (rs *RouterSuite) TestNewRouter(t provider.T) {
+	t.Title("NewRouter wraps routes by their requirements")
+	t.NewStep("Init router")
+	router, err := NewRouter("/api", logger.DefaultLogger, testAuth, rs.routes)
+	t.Require().NoError(err)
+
+	cases := []struct {
+		name     string
+		method   string
+		path     string
+		user     string
+		expected int
+	}{
+		{"Public route without user", http.MethodGet, "/api/v1/public", "", http.StatusOK},
+		{"Private route without user", http.MethodGet, "/api/v1/private", "", http.StatusUnauthorized},
+		{"Private route with user", http.MethodGet, "/api/v1/private", "user", http.StatusOK},
+		{"Permission route without user", http.MethodDelete, "/api/v1/film/1", "", http.StatusUnauthorized},
+		{"Permission route without permission", http.MethodDelete, "/api/v1/film/1", "user", http.StatusForbidden},
+		{"Permission route with permission", http.MethodDelete, "/api/v1/film/1", "editor", http.StatusOK},
+		{"Role route with other role", http.MethodGet, "/api/v1/admin", "editor", http.StatusForbidden},
+		{"Role route with role", http.MethodGet, "/api/v1/admin", "admin", http.StatusOK},
+	}
+
+	for _, cs := range cases {
+		t.WithNewStep(cs.name, func(t provider.StepCtx) {
+			t.NewStep("Init http")
+			req, err := http.NewRequest(cs.method, cs.path, nil)
+			t.Require().NoError(err)
+			if cs.user != "" {
+				req.Header.Set(testUserHeader, cs.user)
+			}
+			recorder := httptest.NewRecorder()
+
+			t.NewStep("Check result")
+			router.ServeHTTP(recorder, req)
+
+			t.Require().Equal(cs.expected, recorder.Code)
+		})
+	}
+
+	t.WithNewStep("No auth middleware for private route", func(t provider.StepCtx) {
+		_, err := NewRouter("/api", logger.DefaultLogger, nil, rs.routes)
+		t.Require().ErrorIs(err, ErrorNoAuthMiddleware)
+	})
+}
+
+func (rs *RouterSuite) TestDescribe(t provider.T) {
+	t.Title("Describe of routes")
+
+	routes, err := rs.routes.Describe("/api")
+	t.Require().NoError(err)
+	t.Require().EqualValues([]response.Route{
+		{Method: http.MethodGet, Path: "/api/v1/public", Permissions: []string{}, Roles: []string{}},
+		{Method: http.MethodGet, Path: "/api/v1/private", AuthRequired: true, Permissions: []string{}, Roles: []string{}},
+		{
+			Method:       http.MethodDelete,
+			Path:         "/api/v1/film/{film_id}",
+			AuthRequired: true,
+			Permissions:  []string{"film:delete"},
+			Roles:        []string{},
+		},
+		{Method: http.MethodGet, Path: "/api/v1/admin", AuthRequired: true, Permissions: []string{}, Roles: []string{"admin"}},
+	}, routes)
+}
+
+func TestRunRouterSuite(t *testing.T) {
+	suite.RunSuite(t, new(RouterSuite))
+}
@@ -14,4 +14,9 @@ package v1
 //	@securityDefinitions.apikey	sessionCookie
 //	@name						session_id
 //	@in							cookie
-//	@description				Уникальный идентификационный номер сессии
+//	@description				Уникальный идентификационный номер сессии. Запросы, кроме GET, HEAD и OPTIONS, должны передавать в заголовке X-CSRF-Token значение cookie csrf_token
+
+//	@securityDefinitions.apikey	bearerToken
+//	@name						Authorization
+//	@in							header
+//	@description				Персональный токен пользователя в формате `Bearer <token>`
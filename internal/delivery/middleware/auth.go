@@ -4,9 +4,10 @@ import (
 	"context"
 	"github.com/pkg/errors"
 	"net/http"
-	"time"
+	"strings"
 	"vk_film/internal/pkg/types"
 	"vk_film/internal/repository/session"
+	"vk_film/internal/repository/token"
 	"vk_film/internal/repository/user"
 	"vk_film/internal/usecase/auth"
 	"vk_film/pkg/mux"
@@ -15,38 +16,47 @@ import (
 const (
 	UserField    = types.ContextField("user_info")
 	SessionField = types.ContextField("session_id")
+	TokenField   = types.ContextField("api_token")
 )
 
-func updateCookie(w http.ResponseWriter, cook *http.Cookie) {
-	cook.Expires = time.Now().Add(auth.ExpiredSessionTime)
-	cook.Path = "/"
-	cook.HttpOnly = true
-	http.SetCookie(w, cook)
-}
-
-func clearCookie(w http.ResponseWriter, cook *http.Cookie) {
-	cook.Expires = time.Now().AddDate(0, 0, -1)
-	cook.Path = "/"
-	cook.HttpOnly = true
-	http.SetCookie(w, cook)
-}
+const (
+	AuthorizationHeader = "Authorization"
+	BearerPrefix        = "Bearer "
+)
 
-func CheckSession(sessionManager auth.Manager) mux.MiddlewareFunc {
+// CheckSession
+// Проверяет сессию в cookie или токен из заголовка Authorization, access токены
+// принимаются только при accessTokens (режим авторизации jwt)
+func CheckSession(sessionManager auth.Manager, cookie *SessionCookie, accessTokens bool) mux.MiddlewareFunc {
 	return func(fun mux.ExtendedHandleFunc) mux.ExtendedHandleFunc {
 		return func(w http.ResponseWriter, r *http.Request, params mux.Params) {
-			sessionCookie, err := r.Cookie(string(SessionField))
+			// Персональный токен или access токен имеют приоритет над сессией в cookie
+			if secret, ok := getBearerToken(r); ok {
+				switch {
+				case strings.HasPrefix(secret, auth.TokenPrefix):
+					checkToken(sessionManager, fun, secret, w, r, params)
+				case accessTokens:
+					checkAccessToken(sessionManager, fun, secret, w, r, params)
+				default:
+					// В режиме сессий идентификатор сессии не должен приниматься вместо access токена
+					GetLogger(r).Warn("[Security] bearer token is not a personal token in session mode")
+					w.WriteHeader(http.StatusUnauthorized)
+				}
+				return
+			}
+
+			sessionID, err := cookie.Get(r)
 			if err != nil {
 				GetLogger(r).Warn("in parsing cookie: %s", err)
 				w.WriteHeader(http.StatusUnauthorized)
 				return
 			}
 
-			sessionID := sessionCookie.Value
 			res, err := sessionManager.GetUserId(sessionID)
 			if err != nil {
 				if errors.Is(err, session.ErrorNoSession) {
 					GetLogger(r).Debug("no session by id %s", sessionID)
-					clearCookie(w, sessionCookie)
+					cookie.Clear(w)
 					w.WriteHeader(http.StatusUnauthorized)
 				} else {
 					GetLogger(r).Error(errors.Wrapf(err, "error with session id %s", sessionID))
@@ -55,8 +65,16 @@ func CheckSession(sessionManager auth.Manager) mux.MiddlewareFunc {
 				return
 			}
 
+			// Браузер отправляет cookie и с запросами чужих сайтов, поэтому изменяющий запрос
+			// должен подтвердить, что пришёл со страницы, прочитавшей CSRF токен
+			if !cookie.CheckCSRF(r, sessionID) {
+				GetLogger(r).Warn("[Security] missing or invalid csrf token for user %d, method %s", res.ID, r.Method)
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			// Срок сессии продлевается на сервере, cookie выдана при входе до абсолютного срока сессии
 			GetLogger(r).Debug("get session for user: %d", res.ID)
-			updateCookie(w, sessionCookie)
 
 			contextWithFields := context.WithValue(r.Context(), UserField, res)
 			contextedRequest := r.WithContext(context.WithValue(contextWithFields, SessionField, sessionID))
@@ -66,25 +84,89 @@ func CheckSession(sessionManager auth.Manager) mux.MiddlewareFunc {
 	}
 }
 
-func CheckNoSession(sessionManager auth.Manager) mux.MiddlewareFunc {
+// getBearerToken
+// Достаёт персональный токен из заголовка Authorization
+func getBearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get(AuthorizationHeader)
+	if !strings.HasPrefix(header, BearerPrefix) {
+		return "", false
+	}
+
+	return strings.TrimSpace(strings.TrimPrefix(header, BearerPrefix)), true
+}
+
+// checkToken
+// Авторизует запрос по персональному токену
+func checkToken(sessionManager auth.Manager, fun mux.ExtendedHandleFunc, secret string,
+	w http.ResponseWriter, r *http.Request, params mux.Params) {
+	usr, tkn, err := sessionManager.GetUserByToken(secret)
+	if err != nil {
+		if errors.Is(err, token.ErrorTokenNotFound) {
+			GetLogger(r).Warn("[Security] unknown or expired api token")
+			w.WriteHeader(http.StatusUnauthorized)
+		} else {
+			GetLogger(r).Error(errors.Wrap(err, "error with api token"))
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Токен только для чтения не позволяет изменять данные
+	if tkn.ReadOnly && r.Method != http.MethodGet && r.Method != http.MethodHead {
+		GetLogger(r).Warn("[Security] read-only token %d used with method %s", tkn.ID, r.Method)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	GetLogger(r).Debug("get api token %d for user: %d", tkn.ID, usr.ID)
+
+	contextWithFields := context.WithValue(r.Context(), UserField, usr)
+	contextedRequest := r.WithContext(context.WithValue(contextWithFields, TokenField, tkn))
+	// Process request
+	fun(w, contextedRequest, params)
+}
+
+// checkAccessToken
+// Авторизует запрос по access токену, выданному в режиме jwt
+func checkAccessToken(sessionManager auth.Manager, fun mux.ExtendedHandleFunc, accessToken string,
+	w http.ResponseWriter, r *http.Request, params mux.Params) {
+	usr, err := sessionManager.GetUserId(accessToken)
+	if err != nil {
+		if errors.Is(err, session.ErrorNoSession) {
+			GetLogger(r).Debug("invalid access token: %s", err)
+			w.WriteHeader(http.StatusUnauthorized)
+		} else {
+			GetLogger(r).Error(errors.Wrap(err, "error with access token"))
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	GetLogger(r).Debug("get access token for user: %d", usr.ID)
+
+	contextWithFields := context.WithValue(r.Context(), UserField, usr)
+	contextedRequest := r.WithContext(context.WithValue(contextWithFields, SessionField, accessToken))
+	// Process request
+	fun(w, contextedRequest, params)
+}
+
+func CheckNoSession(sessionManager auth.Manager, cookie *SessionCookie) mux.MiddlewareFunc {
 	return func(fun mux.ExtendedHandleFunc) mux.ExtendedHandleFunc {
 		return func(w http.ResponseWriter, r *http.Request, params mux.Params) {
-			sessionCookie, err := r.Cookie(string(SessionField))
+			sessionID, err := cookie.Get(r)
 			if err != nil {
 				fun(w, r, params)
 				return
 			}
 
-			sessionID := sessionCookie.Value
 			res, err := sessionManager.GetUserId(sessionID)
 			if err != nil {
-				clearCookie(w, sessionCookie)
+				cookie.Clear(w)
 				fun(w, r, params)
 				return
 			}
 
 			GetLogger(r).Debug("user already authorized: %d", res.ID)
-			updateCookie(w, sessionCookie)
 
 			w.WriteHeader(http.StatusTeapot)
 		}
@@ -109,3 +191,14 @@ func GetSession(r *http.Request) *string {
 
 	return nil
 }
+
+func GetToken(r *http.Request) *token.Token {
+	if lg := r.Context().Value(TokenField); lg != nil {
+		if tkn, ok := lg.(*token.Token); ok {
+			return tkn
+		}
+		return nil
+	}
+
+	return nil
+}
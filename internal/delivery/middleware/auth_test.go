@@ -8,7 +8,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 	"vk_film/internal/repository/session"
+	"vk_film/internal/repository/token"
 	"vk_film/internal/repository/user"
 	"vk_film/internal/usecase/auth/mocks"
 	"vk_film/pkg/mux"
@@ -37,7 +39,7 @@ func (ams *AuthMiddlewareSuite) TestSessionMiddleware(t provider.T) {
 	expectedSessionId := "12314"
 	cok := &http.Cookie{}
 	cok.Value = expectedSessionId
-	cok.Name = string(SessionField)
+	cok.Name = DefaultSessionCookieName
 
 	expectedUsr := &user.User{
 		ID: 1,
@@ -52,9 +54,10 @@ func (ams *AuthMiddlewareSuite) TestSessionMiddleware(t provider.T) {
 		reader, err := http.NewRequest(http.MethodPost, "/any", nil)
 		t.Require().NoError(err)
 		reader.AddCookie(cok)
+		reader.Header.Set(CSRFHeader, CSRFToken(expectedSessionId))
 
 		t.NewStep("Check result")
-		CheckSession(ams.mockSession)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+		CheckSession(ams.mockSession, &DefaultSessionCookie, false)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
 			usr := r.Context().Value(UserField)
 			sessionId := r.Context().Value(SessionField)
 
@@ -76,6 +79,104 @@ func (ams *AuthMiddlewareSuite) TestSessionMiddleware(t provider.T) {
 		t.Require().Equal(http.StatusOK, recorder.Code)
 	})
 
+	t.WithNewStep("Missing csrf token execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ams.mockSession.EXPECT().GetUserId(expectedSessionId).Return(expectedUsr, nil)
+
+		t.NewStep("Init http")
+		recorder := httptest.NewRecorder()
+		reader, err := http.NewRequest(http.MethodPost, "/any", nil)
+		t.Require().NoError(err)
+		reader.AddCookie(cok)
+
+		t.NewStep("Check result")
+		CheckSession(ams.mockSession, &DefaultSessionCookie, false)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+			t.Require().True(false)
+		})(recorder, reader, mux.Params{})
+
+		t.Require().Equal(http.StatusForbidden, recorder.Code)
+	})
+
+	t.WithNewStep("Csrf token of other session execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ams.mockSession.EXPECT().GetUserId(expectedSessionId).Return(expectedUsr, nil)
+
+		t.NewStep("Init http")
+		recorder := httptest.NewRecorder()
+		reader, err := http.NewRequest(http.MethodDelete, "/any", nil)
+		t.Require().NoError(err)
+		reader.AddCookie(cok)
+		reader.Header.Set(CSRFHeader, CSRFToken("other"))
+
+		t.NewStep("Check result")
+		CheckSession(ams.mockSession, &DefaultSessionCookie, false)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+			t.Require().True(false)
+		})(recorder, reader, mux.Params{})
+
+		t.Require().Equal(http.StatusForbidden, recorder.Code)
+	})
+
+	t.WithNewStep("Safe method without csrf token execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ams.mockSession.EXPECT().GetUserId(expectedSessionId).Return(expectedUsr, nil)
+
+		t.NewStep("Init http")
+		recorder := httptest.NewRecorder()
+		reader, err := http.NewRequest(http.MethodGet, "/any", nil)
+		t.Require().NoError(err)
+		reader.AddCookie(cok)
+
+		t.NewStep("Check result")
+		CheckSession(ams.mockSession, &DefaultSessionCookie, false)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+			w.WriteHeader(http.StatusOK)
+		})(recorder, reader, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
+	t.WithNewStep("Configured cookie without refresh execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ams.mockSession.EXPECT().GetUserId(expectedSessionId).Return(expectedUsr, nil)
+		cookie := &SessionCookie{
+			Name:     "vk_session",
+			Domain:   "example.com",
+			Secure:   true,
+			SameSite: http.SameSiteStrictMode,
+		}
+
+		t.NewStep("Init http")
+		recorder := httptest.NewRecorder()
+		reader, err := http.NewRequest(http.MethodGet, "/any", nil)
+		t.Require().NoError(err)
+		reader.AddCookie(&http.Cookie{Name: cookie.Name, Value: expectedSessionId})
+
+		t.NewStep("Check result")
+		CheckSession(ams.mockSession, cookie, false)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+			w.WriteHeader(http.StatusOK)
+		})(recorder, reader, mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+		t.Require().Empty(recorder.Result().Cookies())
+
+		t.NewStep("Check cookie attributes")
+		recorder = httptest.NewRecorder()
+		cookie.Set(recorder, expectedSessionId, time.Hour)
+
+		cookies := recorder.Result().Cookies()
+		t.Require().Len(cookies, 2)
+		t.Require().Equal(cookie.Name, cookies[0].Name)
+		t.Require().Equal(expectedSessionId, cookies[0].Value)
+		t.Require().True(cookies[0].HttpOnly)
+		t.Require().Equal(CSRFCookie, cookies[1].Name)
+		t.Require().Equal(CSRFToken(expectedSessionId), cookies[1].Value)
+		t.Require().False(cookies[1].HttpOnly)
+		for _, c := range cookies {
+			t.Require().True(c.Secure)
+			t.Require().Equal("example.com", c.Domain)
+			t.Require().Equal(http.SameSiteStrictMode, c.SameSite)
+		}
+	})
+
 	t.WithNewStep("Session manager error execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		ams.mockSession.EXPECT().GetUserId(expectedSessionId).Return(expectedUsr, testError)
@@ -87,7 +188,7 @@ func (ams *AuthMiddlewareSuite) TestSessionMiddleware(t provider.T) {
 		reader.AddCookie(cok)
 
 		t.NewStep("Check result")
-		CheckSession(ams.mockSession)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+		CheckSession(ams.mockSession, &DefaultSessionCookie, false)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
 			t.Require().True(false)
 		})(recorder, reader, mux.Params{})
 
@@ -105,7 +206,7 @@ func (ams *AuthMiddlewareSuite) TestSessionMiddleware(t provider.T) {
 		reader.AddCookie(cok)
 
 		t.NewStep("Check result")
-		CheckSession(ams.mockSession)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+		CheckSession(ams.mockSession, &DefaultSessionCookie, false)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
 			t.Require().True(false)
 		})(recorder, reader, mux.Params{})
 
@@ -119,7 +220,7 @@ func (ams *AuthMiddlewareSuite) TestSessionMiddleware(t provider.T) {
 		t.Require().NoError(err)
 
 		t.NewStep("Check result")
-		CheckSession(ams.mockSession)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+		CheckSession(ams.mockSession, &DefaultSessionCookie, false)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
 			t.Require().True(false)
 		})(recorder, reader, mux.Params{})
 
@@ -127,13 +228,176 @@ func (ams *AuthMiddlewareSuite) TestSessionMiddleware(t provider.T) {
 	})
 }
 
+func (ams *AuthMiddlewareSuite) TestTokenMiddleware(t provider.T) {
+	t.Title("Session Middleware with api token")
+	t.NewStep("Init test data")
+	secret := "vkf_secret"
+	expectedUsr := &user.User{
+		ID: 1,
+	}
+	expectedToken := &token.Token{
+		ID:     2,
+		UserID: 1,
+	}
+	readOnlyToken := &token.Token{
+		ID:       3,
+		UserID:   1,
+		ReadOnly: true,
+	}
+
+	newRequest := func(method string) *http.Request {
+		reader, err := http.NewRequest(method, "/any", nil)
+		t.Require().NoError(err)
+		reader.Header.Set(AuthorizationHeader, BearerPrefix+secret)
+		return reader
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ams.mockSession.EXPECT().GetUserByToken(secret).Return(expectedUsr, expectedToken, nil)
+
+		t.NewStep("Check result")
+		recorder := httptest.NewRecorder()
+		CheckSession(ams.mockSession, &DefaultSessionCookie, false)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+			t.Require().Equal(expectedUsr, GetUser(r))
+			t.Require().Equal(expectedToken, GetToken(r))
+			t.Require().Nil(GetSession(r))
+
+			w.WriteHeader(http.StatusOK)
+		})(recorder, newRequest(http.MethodPost), mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
+	t.WithNewStep("Read-only token with get execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ams.mockSession.EXPECT().GetUserByToken(secret).Return(expectedUsr, readOnlyToken, nil)
+
+		t.NewStep("Check result")
+		recorder := httptest.NewRecorder()
+		CheckSession(ams.mockSession, &DefaultSessionCookie, false)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+			w.WriteHeader(http.StatusOK)
+		})(recorder, newRequest(http.MethodGet), mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
+	t.WithNewStep("Read-only token with post execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ams.mockSession.EXPECT().GetUserByToken(secret).Return(expectedUsr, readOnlyToken, nil)
+
+		t.NewStep("Check result")
+		recorder := httptest.NewRecorder()
+		CheckSession(ams.mockSession, &DefaultSessionCookie, false)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+			t.Require().True(false)
+		})(recorder, newRequest(http.MethodPost), mux.Params{})
+
+		t.Require().Equal(http.StatusForbidden, recorder.Code)
+	})
+
+	t.WithNewStep("Token not found execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ams.mockSession.EXPECT().GetUserByToken(secret).Return(nil, nil, token.ErrorTokenNotFound)
+
+		t.NewStep("Check result")
+		recorder := httptest.NewRecorder()
+		CheckSession(ams.mockSession, &DefaultSessionCookie, false)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+			t.Require().True(false)
+		})(recorder, newRequest(http.MethodGet), mux.Params{})
+
+		t.Require().Equal(http.StatusUnauthorized, recorder.Code)
+	})
+
+	t.WithNewStep("Session manager error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ams.mockSession.EXPECT().GetUserByToken(secret).Return(nil, nil, testError)
+
+		t.NewStep("Check result")
+		recorder := httptest.NewRecorder()
+		CheckSession(ams.mockSession, &DefaultSessionCookie, false)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+			t.Require().True(false)
+		})(recorder, newRequest(http.MethodGet), mux.Params{})
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func (ams *AuthMiddlewareSuite) TestAccessTokenMiddleware(t provider.T) {
+	t.Title("Session Middleware with access token")
+	t.NewStep("Init test data")
+	accessToken := "header.payload.signature"
+	expectedUsr := &user.User{
+		ID: 1,
+	}
+
+	newRequest := func() *http.Request {
+		reader, err := http.NewRequest(http.MethodPost, "/any", nil)
+		t.Require().NoError(err)
+		reader.Header.Set(AuthorizationHeader, BearerPrefix+accessToken)
+		return reader
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ams.mockSession.EXPECT().GetUserId(accessToken).Return(expectedUsr, nil)
+
+		t.NewStep("Check result")
+		recorder := httptest.NewRecorder()
+		CheckSession(ams.mockSession, &DefaultSessionCookie, true)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+			t.Require().Equal(expectedUsr, GetUser(r))
+			t.Require().Equal(accessToken, *GetSession(r))
+			t.Require().Nil(GetToken(r))
+
+			w.WriteHeader(http.StatusOK)
+		})(recorder, newRequest(), mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
+	t.WithNewStep("Invalid access token execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ams.mockSession.EXPECT().GetUserId(accessToken).Return(nil, session.ErrorNoSession)
+
+		t.NewStep("Check result")
+		recorder := httptest.NewRecorder()
+		CheckSession(ams.mockSession, &DefaultSessionCookie, true)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+			t.Require().True(false)
+		})(recorder, newRequest(), mux.Params{})
+
+		t.Require().Equal(http.StatusUnauthorized, recorder.Code)
+	})
+
+	t.WithNewStep("Session manager error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ams.mockSession.EXPECT().GetUserId(accessToken).Return(nil, testError)
+
+		t.NewStep("Check result")
+		recorder := httptest.NewRecorder()
+		CheckSession(ams.mockSession, &DefaultSessionCookie, true)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+			t.Require().True(false)
+		})(recorder, newRequest(), mux.Params{})
+
+		t.Require().Equal(http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.WithNewStep("Session mode execute", func(t provider.StepCtx) {
+		t.NewStep("Check result")
+		recorder := httptest.NewRecorder()
+		CheckSession(ams.mockSession, &DefaultSessionCookie, false)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+			t.Require().True(false)
+		})(recorder, newRequest(), mux.Params{})
+
+		t.Require().Equal(http.StatusUnauthorized, recorder.Code)
+	})
+}
+
 func (ams *AuthMiddlewareSuite) TestNoSessionMiddleware(t provider.T) {
 	t.Title("NoSession Middleware")
 	t.NewStep("Init test data")
 	expectedSessionId := "12314"
 	cok := &http.Cookie{}
 	cok.Value = expectedSessionId
-	cok.Name = string(SessionField)
+	cok.Name = DefaultSessionCookieName
 
 	expectedUsr := &user.User{
 		ID: 1,
@@ -146,7 +410,7 @@ func (ams *AuthMiddlewareSuite) TestNoSessionMiddleware(t provider.T) {
 		t.Require().NoError(err)
 
 		t.NewStep("Check result")
-		CheckNoSession(ams.mockSession)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+		CheckNoSession(ams.mockSession, &DefaultSessionCookie)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
 			w.WriteHeader(http.StatusOK)
 		})(recorder, reader, mux.Params{})
 
@@ -164,7 +428,7 @@ func (ams *AuthMiddlewareSuite) TestNoSessionMiddleware(t provider.T) {
 		reader.AddCookie(cok)
 
 		t.NewStep("Check result")
-		CheckNoSession(ams.mockSession)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+		CheckNoSession(ams.mockSession, &DefaultSessionCookie)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
 			w.WriteHeader(http.StatusOK)
 		})(recorder, reader, mux.Params{})
 
@@ -182,7 +446,7 @@ func (ams *AuthMiddlewareSuite) TestNoSessionMiddleware(t provider.T) {
 		reader.AddCookie(cok)
 
 		t.NewStep("Check result")
-		CheckNoSession(ams.mockSession)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+		CheckNoSession(ams.mockSession, &DefaultSessionCookie)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
 			w.WriteHeader(http.StatusOK)
 		})(recorder, reader, mux.Params{})
 
@@ -200,7 +464,7 @@ func (ams *AuthMiddlewareSuite) TestNoSessionMiddleware(t provider.T) {
 		reader.AddCookie(cok)
 
 		t.NewStep("Check result")
-		CheckNoSession(ams.mockSession)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+		CheckNoSession(ams.mockSession, &DefaultSessionCookie)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
 			t.Require().True(false)
 		})(recorder, reader, mux.Params{})
 
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"github.com/pkg/errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultSessionCookieName = "session_id"
+	CSRFCookie               = "csrf_token"
+	CSRFHeader               = "X-CSRF-Token"
+
+	csrfContext = "csrf:"
+)
+
+// SessionCookie
+// Атрибуты cookie, в которой хранится сессия (или access токен в режиме jwt).
+// Вместе с ней выдаётся cookie с CSRF токеном, доступная скриптам страницы.
+type SessionCookie struct {
+	Name     string
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+}
+
+// DefaultSessionCookie
+// Атрибуты cookie сессии по умолчанию
+var DefaultSessionCookie = SessionCookie{
+	Name:     DefaultSessionCookieName,
+	SameSite: http.SameSiteLaxMode,
+}
+
+// ParseSameSite
+// Переводит значение SameSite из конфигурации
+func ParseSameSite(value string) (http.SameSite, error) {
+	switch strings.ToLower(value) {
+	case "", "lax":
+		return http.SameSiteLaxMode, nil
+	case "strict":
+		return http.SameSiteStrictMode, nil
+	case "none":
+		return http.SameSiteNoneMode, nil
+	}
+
+	return 0, errors.Errorf("unknown same_site value %q", value)
+}
+
+// CSRFToken
+// Вычисляет CSRF токен сессии. Токен привязан к сессии, поэтому подложенная
+// с соседнего домена cookie с CSRF токеном не подойдёт к чужой сессии.
+func CSRFToken(sessionId string) string {
+	sum := sha256.Sum256([]byte(csrfContext + sessionId))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get
+// Достаёт значение cookie сессии из запроса
+func (sc *SessionCookie) Get(r *http.Request) (string, error) {
+	cookie, err := r.Cookie(sc.Name)
+	if err != nil {
+		return "", err
+	}
+
+	return cookie.Value, nil
+}
+
+// Set
+// Устанавливает cookie сессии и cookie с CSRF токеном на время expiresIn.
+// При нулевом времени cookie живут до закрытия браузера
+func (sc *SessionCookie) Set(w http.ResponseWriter, sessionId string, expiresIn time.Duration) {
+	var expires time.Time
+	if expiresIn > 0 {
+		expires = time.Now().Add(expiresIn)
+	}
+	http.SetCookie(w, sc.cookie(sc.Name, sessionId, expires, true))
+	http.SetCookie(w, sc.cookie(CSRFCookie, CSRFToken(sessionId), expires, false))
+}
+
+// Clear
+// Удаляет cookie сессии и cookie с CSRF токеном
+func (sc *SessionCookie) Clear(w http.ResponseWriter) {
+	expires := time.Now().AddDate(0, 0, -1)
+	http.SetCookie(w, sc.cookie(sc.Name, "", expires, true))
+	http.SetCookie(w, sc.cookie(CSRFCookie, "", expires, false))
+}
+
+// CheckCSRF
+// Проверяет, что изменяющий запрос передал в заголовке CSRF токен своей сессии
+func (sc *SessionCookie) CheckCSRF(r *http.Request, sessionId string) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+
+	header := r.Header.Get(CSRFHeader)
+	return header != "" && subtle.ConstantTimeCompare([]byte(header), []byte(CSRFToken(sessionId))) == 1
+}
+
+// cookie
+// Собирает cookie с настроенными атрибутами
+func (sc *SessionCookie) cookie(name, value string, expires time.Time, httpOnly bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Domain:   sc.Domain,
+		Expires:  expires,
+		Secure:   sc.Secure,
+		HttpOnly: httpOnly,
+		SameSite: sc.SameSite,
+	}
+}
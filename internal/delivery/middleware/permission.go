@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"vk_film/internal/pkg/types"
+	"vk_film/pkg/mux"
+)
+
+// RequirePermissions
+// Пропускает запрос, только если у авторизованного пользователя есть все перечисленные права
+func RequirePermissions(permissions ...types.Permission) mux.MiddlewareFunc {
+	return func(fun mux.ExtendedHandleFunc) mux.ExtendedHandleFunc {
+		return func(w http.ResponseWriter, r *http.Request, params mux.Params) {
+			usr := GetUser(r)
+			if usr == nil {
+				GetLogger(r).Warn("[Security] request without user to route with permissions %v", permissions)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			for _, permission := range permissions {
+				if !usr.HasPermission(permission) {
+					GetLogger(r).Warn("[Security] user %d has no permission %s", usr.ID, permission)
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+			}
+
+			fun(w, r, params)
+		}
+	}
+}
+
+// RequireRoles
+// Пропускает запрос, только если авторизованный пользователь имеет одну из перечисленных ролей
+func RequireRoles(roles ...types.Roles) mux.MiddlewareFunc {
+	return func(fun mux.ExtendedHandleFunc) mux.ExtendedHandleFunc {
+		return func(w http.ResponseWriter, r *http.Request, params mux.Params) {
+			usr := GetUser(r)
+			if usr == nil {
+				GetLogger(r).Warn("[Security] request without user to route with roles %v", roles)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			if !slices.Contains(roles, usr.Role) {
+				GetLogger(r).Warn("[Security] user %d with role %s has no access to route with roles %v",
+					usr.ID, usr.Role, roles)
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			fun(w, r, params)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"context"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/user"
+	"vk_film/pkg/mux"
+)
+
+type PermissionMiddlewareSuite struct {
+	suite.Suite
+}
+
+func withUser(r *http.Request, usr *user.User) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), UserField, usr))
+}
+
+func okHandler(w http.ResponseWriter, _ *http.Request, _ mux.Params) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (pms *PermissionMiddlewareSuite) TestRequirePermissionsMiddleware(t provider.T) {
+	t.Title("RequirePermissions Middleware")
+	t.NewStep("Init test data")
+	editor := &user.User{ID: 1, Role: "editor", Permissions: []types.Permission{types.FilmWrite, types.FilmDelete}}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		recorder := httptest.NewRecorder()
+		reader, err := http.NewRequest(http.MethodDelete, "/any", nil)
+		t.Require().NoError(err)
+
+		t.NewStep("Check result")
+		RequirePermissions(types.FilmWrite, types.FilmDelete)(okHandler)(recorder, withUser(reader, editor), mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
+	t.WithNewStep("Missing one of permissions execute", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		recorder := httptest.NewRecorder()
+		reader, err := http.NewRequest(http.MethodDelete, "/any", nil)
+		t.Require().NoError(err)
+
+		t.NewStep("Check result")
+		RequirePermissions(types.FilmWrite, types.ActorDelete)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+			t.Require().True(false)
+		})(recorder, withUser(reader, editor), mux.Params{})
+
+		t.Require().Equal(http.StatusForbidden, recorder.Code)
+	})
+
+	t.WithNewStep("No user execute", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		recorder := httptest.NewRecorder()
+		reader, err := http.NewRequest(http.MethodDelete, "/any", nil)
+		t.Require().NoError(err)
+
+		t.NewStep("Check result")
+		RequirePermissions(types.FilmWrite)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+			t.Require().True(false)
+		})(recorder, reader, mux.Params{})
+
+		t.Require().Equal(http.StatusUnauthorized, recorder.Code)
+	})
+}
+
+func (pms *PermissionMiddlewareSuite) TestRequireRolesMiddleware(t provider.T) {
+	t.Title("RequireRoles Middleware")
+	t.NewStep("Init test data")
+	admin := &user.User{ID: 1, Role: types.ADMIN}
+	usr := &user.User{ID: 2, Role: types.USER}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		recorder := httptest.NewRecorder()
+		reader, err := http.NewRequest(http.MethodGet, "/any", nil)
+		t.Require().NoError(err)
+
+		t.NewStep("Check result")
+		RequireRoles(types.ADMIN, "moderator")(okHandler)(recorder, withUser(reader, admin), mux.Params{})
+
+		t.Require().Equal(http.StatusOK, recorder.Code)
+	})
+
+	t.WithNewStep("Not allowed role execute", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		recorder := httptest.NewRecorder()
+		reader, err := http.NewRequest(http.MethodGet, "/any", nil)
+		t.Require().NoError(err)
+
+		t.NewStep("Check result")
+		RequireRoles(types.ADMIN, "moderator")(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+			t.Require().True(false)
+		})(recorder, withUser(reader, usr), mux.Params{})
+
+		t.Require().Equal(http.StatusForbidden, recorder.Code)
+	})
+
+	t.WithNewStep("No user execute", func(t provider.StepCtx) {
+		t.NewStep("Init http")
+		recorder := httptest.NewRecorder()
+		reader, err := http.NewRequest(http.MethodGet, "/any", nil)
+		t.Require().NoError(err)
+
+		t.NewStep("Check result")
+		RequireRoles(types.ADMIN)(func(w http.ResponseWriter, r *http.Request, _ mux.Params) {
+			t.Require().True(false)
+		})(recorder, reader, mux.Params{})
+
+		t.Require().Equal(http.StatusUnauthorized, recorder.Code)
+	})
+}
+
+func TestRunPermissionMiddlewareSuite(t *testing.T) {
+	suite.RunSuite(t, new(PermissionMiddlewareSuite))
+}
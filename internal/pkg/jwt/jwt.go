@@ -0,0 +1,246 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"strings"
+	"time"
+)
+
+const (
+	HS256 = "HS256"
+	EdDSA = "EdDSA"
+
+	minSecretLength = 32
+)
+
+var (
+	ErrorMalformedToken    = errors.New("malformed token")
+	ErrorUnknownKey        = errors.New("unknown signing key")
+	ErrorInvalidSignature  = errors.New("invalid token signature")
+	ErrorTokenExpired      = errors.New("token is expired")
+	ErrorUnknownAlgorithm  = errors.New("unknown signing algorithm")
+	ErrorInvalidKey        = errors.New("invalid key")
+	ErrorVerificationOnly  = errors.New("key can be used only for verification")
+	ErrorDuplicatedKeyId   = errors.New("duplicated key id")
+	ErrorNoSigningKey      = errors.New("signing key is not found")
+	ErrorUnsupportedHeader = errors.New("unsupported token header")
+)
+
+var encoding = base64.RawURLEncoding
+
+// Claims
+// Registered claims used by the application. SessionId binds the access token
+// to the refresh token family it was issued for.
+type Claims struct {
+	Subject   string `json:"sub"`
+	SessionId string `json:"sid,omitempty"`
+	ID        string `json:"jti,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyId     string `json:"kid"`
+}
+
+// Key
+// Key for signing and verification of tokens. Ed25519 key without private part
+// can be used only for verification, that allows to keep old keys after rotation.
+type Key struct {
+	ID        string
+	Algorithm string
+	secret    []byte
+	private   ed25519.PrivateKey
+	public    ed25519.PublicKey
+}
+
+func NewHS256Key(kid string, secret []byte) (*Key, error) {
+	if len(secret) < minSecretLength {
+		return nil, errors.Wrapf(ErrorInvalidKey, "secret of key %s must be at least %d bytes", kid, minSecretLength)
+	}
+
+	return &Key{ID: kid, Algorithm: HS256, secret: secret}, nil
+}
+
+// NewEd25519Key
+// Creates Ed25519 key from a private key or its seed
+func NewEd25519Key(kid string, private []byte) (*Key, error) {
+	var privateKey ed25519.PrivateKey
+	switch len(private) {
+	case ed25519.SeedSize:
+		privateKey = ed25519.NewKeyFromSeed(private)
+	case ed25519.PrivateKeySize:
+		privateKey = private
+	default:
+		return nil, errors.Wrapf(ErrorInvalidKey, "private key %s has wrong size %d", kid, len(private))
+	}
+
+	return &Key{
+		ID:        kid,
+		Algorithm: EdDSA,
+		private:   privateKey,
+		public:    privateKey.Public().(ed25519.PublicKey),
+	}, nil
+}
+
+// NewEd25519PublicKey
+// Creates Ed25519 key which can be used only for verification
+func NewEd25519PublicKey(kid string, public []byte) (*Key, error) {
+	if len(public) != ed25519.PublicKeySize {
+		return nil, errors.Wrapf(ErrorInvalidKey, "public key %s has wrong size %d", kid, len(public))
+	}
+
+	return &Key{ID: kid, Algorithm: EdDSA, public: public}, nil
+}
+
+// sign
+// Подписывает данные ключом
+func (k *Key) sign(data []byte) ([]byte, error) {
+	switch k.Algorithm {
+	case HS256:
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write(data)
+		return mac.Sum(nil), nil
+	case EdDSA:
+		if k.private == nil {
+			return nil, errors.Wrapf(ErrorVerificationOnly, "key %s", k.ID)
+		}
+		return ed25519.Sign(k.private, data), nil
+	}
+
+	return nil, errors.Wrapf(ErrorUnknownAlgorithm, "algorithm %s", k.Algorithm)
+}
+
+// verify
+// Проверяет подпись данных ключом
+func (k *Key) verify(data, signature []byte) bool {
+	switch k.Algorithm {
+	case HS256:
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write(data)
+		return hmac.Equal(mac.Sum(nil), signature)
+	case EdDSA:
+		return ed25519.Verify(k.public, data, signature)
+	}
+
+	return false
+}
+
+// KeySet
+// Set of keys identified by kid. New tokens are signed with the signing key,
+// tokens signed with any key of the set are accepted.
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+}
+
+func NewKeySet(signingKid string, keys ...*Key) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*Key, len(keys))}
+
+	for _, key := range keys {
+		if _, exists := ks.keys[key.ID]; exists {
+			return nil, errors.Wrapf(ErrorDuplicatedKeyId, "key id %s", key.ID)
+		}
+		ks.keys[key.ID] = key
+	}
+
+	signing, exists := ks.keys[signingKid]
+	if !exists {
+		return nil, errors.Wrapf(ErrorNoSigningKey, "key id %s", signingKid)
+	}
+
+	if signing.Algorithm == EdDSA && signing.private == nil {
+		return nil, errors.Wrapf(ErrorVerificationOnly, "signing key %s", signingKid)
+	}
+
+	ks.signing = signing
+
+	return ks, nil
+}
+
+func (ks *KeySet) Sign(claims *Claims) (string, error) {
+	hdr, err := json.Marshal(header{Algorithm: ks.signing.Algorithm, Type: "JWT", KeyId: ks.signing.ID})
+	if err != nil {
+		return "", errors.Wrap(err, "try marshal token header")
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", errors.Wrap(err, "try marshal token claims")
+	}
+
+	unsigned := encoding.EncodeToString(hdr) + "." + encoding.EncodeToString(payload)
+
+	signature, err := ks.signing.sign([]byte(unsigned))
+	if err != nil {
+		return "", errors.Wrap(err, "try sign token")
+	}
+
+	return unsigned + "." + encoding.EncodeToString(signature), nil
+}
+
+// Parse
+// Verifies the signature and the expiration time of the token and returns its claims
+func (ks *KeySet) Parse(token string, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrorMalformedToken
+	}
+
+	var hdr header
+	if err := decodePart(parts[0], &hdr); err != nil {
+		return nil, err
+	}
+
+	key, exists := ks.keys[hdr.KeyId]
+	if !exists {
+		return nil, errors.Wrapf(ErrorUnknownKey, "key id %s", hdr.KeyId)
+	}
+
+	// Алгоритм задаётся ключом, а не заголовком токена
+	if hdr.Algorithm != key.Algorithm {
+		return nil, errors.Wrapf(ErrorUnsupportedHeader, "algorithm %s for key %s", hdr.Algorithm, key.ID)
+	}
+
+	signature, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrap(ErrorMalformedToken, err.Error())
+	}
+
+	if !key.verify([]byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrorInvalidSignature
+	}
+
+	var claims Claims
+	if err := decodePart(parts[1], &claims); err != nil {
+		return nil, err
+	}
+
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, ErrorTokenExpired
+	}
+
+	return &claims, nil
+}
+
+// decodePart
+// Декодирует часть токена в формате base64url JSON
+func decodePart(part string, value any) error {
+	data, err := encoding.DecodeString(part)
+	if err != nil {
+		return errors.Wrap(ErrorMalformedToken, err.Error())
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		return errors.Wrap(ErrorMalformedToken, err.Error())
+	}
+
+	return nil
+}
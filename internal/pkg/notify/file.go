@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"os"
+	"sync"
+	"time"
+	"vk_film/pkg/logger"
+)
+
+// FileNotifier
+// Appends messages to the local file instead of sending them, for development only
+type FileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+var _ = Notifier(&FileNotifier{})
+
+func (fn *FileNotifier) Send(_ context.Context, msg Message) error {
+	if err := checkHeaders(msg); err != nil {
+		return err
+	}
+
+	fn.mu.Lock()
+	defer fn.mu.Unlock()
+
+	// Письма могут содержать токены, поэтому файл доступен только владельцу
+	file, err := os.OpenFile(fn.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "try open notification file %s", fn.path)
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC1123Z), msg.To, msg.Subject, msg.Body)
+	if err != nil {
+		return errors.Wrapf(err, "try write notification to %s", fn.path)
+	}
+
+	return nil
+}
+
+// LogNotifier
+// Writes recipients and subjects of messages to the log instead of sending them, for development only.
+// Bodies are not written, because they contain secrets like password reset tokens.
+type LogNotifier struct {
+	l logger.Interface
+}
+
+func NewLogNotifier(l logger.Interface) *LogNotifier {
+	return &LogNotifier{l: l}
+}
+
+var _ = Notifier(&LogNotifier{})
+
+func (ln *LogNotifier) Send(_ context.Context, msg Message) error {
+	if err := checkHeaders(msg); err != nil {
+		return err
+	}
+
+	ln.l.Info("[Notify] message to %s: %s", msg.To, msg.Subject)
+	return nil
+}
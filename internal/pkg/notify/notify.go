@@ -0,0 +1,33 @@
+package notify
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+var ErrorMalformedMessage = errors.New("message headers must not contain line breaks")
+
+// Message
+// Plain text letter to the single recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier
+// Delivers messages to users
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// checkHeaders
+// Запрещает переводы строк в заголовках, чтобы нельзя было подставить свои заголовки письма
+func checkHeaders(msg Message) error {
+	if strings.ContainsAny(msg.To, "\r\n") || strings.ContainsAny(msg.Subject, "\r\n") {
+		return ErrorMalformedMessage
+	}
+
+	return nil
+}
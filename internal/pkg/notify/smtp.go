@@ -0,0 +1,117 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"github.com/pkg/errors"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTPConfig
+// Empty Username disables authentication
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPNotifier
+// Sends messages through the SMTP server, STARTTLS is used when the server supports it
+type SMTPNotifier struct {
+	cfg SMTPConfig
+}
+
+func NewSMTPNotifier(cfg SMTPConfig) *SMTPNotifier {
+	return &SMTPNotifier{cfg: cfg}
+}
+
+var _ = Notifier(&SMTPNotifier{})
+
+func (sn *SMTPNotifier) Send(ctx context.Context, msg Message) error {
+	if err := checkHeaders(msg); err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(sn.cfg.Host, strconv.Itoa(sn.cfg.Port))
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return errors.Wrapf(err, "try connect to smtp server %s", addr)
+	}
+
+	// Срок контекста ограничивает весь диалог с сервером, а не только подключение
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, sn.cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return errors.Wrapf(err, "try start smtp session with %s", addr)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: sn.cfg.Host}); err != nil {
+			return errors.Wrap(err, "try start tls")
+		}
+	}
+
+	// smtp.PlainAuth сам отказывается передавать пароль по незашифрованному соединению
+	if sn.cfg.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", sn.cfg.Username, sn.cfg.Password, sn.cfg.Host)); err != nil {
+			return errors.Wrap(err, "try authenticate on smtp server")
+		}
+	}
+
+	if err = client.Mail(sn.cfg.From); err != nil {
+		return errors.Wrapf(err, "try set sender %s", sn.cfg.From)
+	}
+	if err = client.Rcpt(msg.To); err != nil {
+		return errors.Wrapf(err, "try set recipient %s", msg.To)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return errors.Wrap(err, "try start message data")
+	}
+	if _, err = w.Write(sn.compose(msg)); err != nil {
+		_ = w.Close()
+		return errors.Wrap(err, "try write message data")
+	}
+	if err = w.Close(); err != nil {
+		return errors.Wrap(err, "try send message")
+	}
+
+	return client.Quit()
+}
+
+// compose
+// Собирает письмо в кодировке UTF-8, тело кодируется в base64 с переносом строк по 76 символов
+func (sn *SMTPNotifier) compose(msg Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", sn.cfg.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	body := base64.StdEncoding.EncodeToString([]byte(msg.Body))
+	for len(body) > 76 {
+		buf.WriteString(body[:76] + "\r\n")
+		body = body[76:]
+	}
+	buf.WriteString(body + "\r\n")
+
+	return buf.Bytes()
+}
@@ -0,0 +1,280 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+const (
+	RS256 = "RS256"
+	ES256 = "ES256"
+	EdDSA = "EdDSA"
+)
+
+var (
+	ErrorMalformedToken       = errors.New("malformed id token")
+	ErrorUnknownKey           = errors.New("unknown signing key")
+	ErrorInvalidSignature     = errors.New("invalid id token signature")
+	ErrorUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrorJWKS                 = errors.New("can't load provider keys")
+)
+
+type header struct {
+	Algorithm string `json:"alg"`
+	KeyId     string `json:"kid"`
+}
+
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyId   string `json:"kid"`
+	Use     string `json:"use"`
+	Curve   string `json:"crv"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// publicKey
+// Ключ провайдера и алгоритм, которым им можно проверять подпись
+type publicKey struct {
+	algorithm string
+	key       crypto.PublicKey
+}
+
+// verifySignature
+// Проверяет подпись токена ключом провайдера и возвращает его полезную нагрузку
+func (p *Provider) verifySignature(ctx context.Context, token string) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrorMalformedToken
+	}
+
+	var hdr header
+	if err := decodePart(parts[0], &hdr); err != nil {
+		return nil, err
+	}
+
+	key, err := p.key(ctx, hdr.KeyId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Алгоритм задаётся типом ключа, а не заголовком токена
+	if hdr.Algorithm != key.algorithm {
+		return nil, errors.Wrapf(ErrorUnsupportedAlgorithm, "algorithm %s for key %s", hdr.Algorithm, hdr.KeyId)
+	}
+
+	signature, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrap(ErrorMalformedToken, err.Error())
+	}
+
+	if !key.verify([]byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrorInvalidSignature
+	}
+
+	payload, err := encoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.Wrap(ErrorMalformedToken, err.Error())
+	}
+
+	return payload, nil
+}
+
+// key
+// Ищет ключ по kid, при отсутствии ключа заново загружает JWKS провайдера
+func (p *Provider) key(ctx context.Context, kid string) (*publicKey, error) {
+	if key, ok := p.cachedKey(kid); ok {
+		return key, nil
+	}
+
+	if err := p.loadKeys(ctx); err != nil {
+		return nil, err
+	}
+
+	if key, ok := p.cachedKey(kid); ok {
+		return key, nil
+	}
+
+	return nil, errors.Wrapf(ErrorUnknownKey, "key id %s", kid)
+}
+
+// cachedKey
+// Ищет ключ среди загруженных. Токен без kid допустим, только если у провайдера один ключ.
+func (p *Provider) cachedKey(kid string) (*publicKey, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+// loadKeys
+// Загружает JWKS провайдера, неподдерживаемые ключи пропускаются
+func (p *Provider) loadKeys(ctx context.Context) error {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadata.JWKSURI, nil)
+	if err != nil {
+		return errors.Wrap(err, "try create jwks request")
+	}
+
+	var set jsonWebKeySet
+	status, err := p.do(req, &set)
+	if err != nil {
+		return errors.Wrap(ErrorJWKS, err.Error())
+	}
+
+	if status != http.StatusOK {
+		return errors.Wrapf(ErrorJWKS, "status %d", status)
+	}
+
+	keys := make(map[string]*publicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.KeyId] = key
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+
+	return nil
+}
+
+// publicKey
+// Создаёт ключ из JWK. Поддерживаются ключи RSA, EC P-256 и Ed25519.
+func (jwk *jsonWebKey) publicKey() (*publicKey, error) {
+	switch jwk.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+
+		if !e.IsInt64() || e.Int64() > int64(^uint32(0)>>1) {
+			return nil, errors.Wrap(ErrorJWKS, "invalid rsa exponent")
+		}
+
+		return &publicKey{algorithm: RS256, key: &rsa.PublicKey{N: n, E: int(e.Int64())}}, nil
+	case "EC":
+		if jwk.Curve != "P-256" {
+			return nil, errors.Wrapf(ErrorUnsupportedAlgorithm, "curve %s", jwk.Curve)
+		}
+
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		if !key.Curve.IsOnCurve(x, y) {
+			return nil, errors.Wrap(ErrorJWKS, "point is not on curve")
+		}
+
+		return &publicKey{algorithm: ES256, key: key}, nil
+	case "OKP":
+		if jwk.Curve != "Ed25519" {
+			return nil, errors.Wrapf(ErrorUnsupportedAlgorithm, "curve %s", jwk.Curve)
+		}
+
+		x, err := encoding.DecodeString(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.Wrap(ErrorJWKS, "invalid ed25519 key")
+		}
+
+		return &publicKey{algorithm: EdDSA, key: ed25519.PublicKey(x)}, nil
+	}
+
+	return nil, errors.Wrapf(ErrorUnsupportedAlgorithm, "key type %s", jwk.KeyType)
+}
+
+// verify
+// Проверяет подпись данных ключом
+func (pk *publicKey) verify(data, signature []byte) bool {
+	switch key := pk.key.(type) {
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+	case *ecdsa.PublicKey:
+		// Подпись ES256 — это последовательно записанные r и s по 32 байта
+		if len(signature) != 64 {
+			return false
+		}
+		digest := sha256.Sum256(data)
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		return ecdsa.Verify(key, digest[:], r, s)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, data, signature)
+	}
+
+	return false
+}
+
+// decodeBigInt
+// Декодирует число в формате base64url
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := encoding.DecodeString(value)
+	if err != nil || len(data) == 0 {
+		return nil, errors.Wrap(ErrorJWKS, "invalid key parameter")
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
+
+// decodePart
+// Декодирует часть токена в формате base64url JSON
+func decodePart(part string, value any) error {
+	data, err := encoding.DecodeString(part)
+	if err != nil {
+		return errors.Wrap(ErrorMalformedToken, err.Error())
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		return errors.Wrap(ErrorMalformedToken, err.Error())
+	}
+
+	return nil
+}
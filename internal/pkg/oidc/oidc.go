@@ -0,0 +1,341 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DiscoveryPath = "/.well-known/openid-configuration"
+
+	maxResponseSize = 1 << 20
+	randomSize      = 32
+	clockSkew       = time.Minute
+)
+
+var DefaultScopes = []string{"openid", "profile", "email"}
+
+var (
+	ErrorDiscovery       = errors.New("can't discover provider configuration")
+	ErrorIssuerMismatch  = errors.New("issuer of provider configuration doesn't match the configured one")
+	ErrorExchange        = errors.New("can't exchange authorization code")
+	ErrorNoIdToken       = errors.New("token response doesn't contain id token")
+	ErrorInvalidClaims   = errors.New("invalid id token claims")
+	ErrorNonceMismatch   = errors.New("nonce of id token doesn't match")
+	ErrorTokenExpired    = errors.New("id token is expired")
+	ErrorAudienceInvalid = errors.New("id token is issued for another client")
+)
+
+var encoding = base64.RawURLEncoding
+
+// Config
+// Settings of the client registered in the identity provider
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Metadata
+// Part of the provider configuration returned by the discovery endpoint
+type Metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Claims
+// Claims of a verified id token
+type Claims map[string]any
+
+// String
+// Returns the claim if it is a string, otherwise empty string
+func (c Claims) String(name string) string {
+	value, _ := c[name].(string)
+	return value
+}
+
+// Strings
+// Returns the claim as a list of strings. A single string claim is returned as a list with one element.
+func (c Claims) Strings(name string) []string {
+	switch value := c[name].(type) {
+	case string:
+		return []string{value}
+	case []any:
+		res := make([]string, 0, len(value))
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				res = append(res, s)
+			}
+		}
+		return res
+	}
+
+	return nil
+}
+
+func (c Claims) Subject() string {
+	return c.String("sub")
+}
+
+// Provider
+// OpenID Connect client of the authorization code flow with PKCE. The provider configuration
+// is discovered on first use, signing keys are loaded from JWKS and reloaded when a token
+// is signed with an unknown key.
+type Provider struct {
+	config Config
+	client *http.Client
+	now    func() time.Time
+
+	mu       sync.Mutex
+	metadata *Metadata
+	keys     map[string]*publicKey
+}
+
+func NewProvider(config Config, client *http.Client) *Provider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = DefaultScopes
+	}
+
+	return &Provider{
+		config: config,
+		client: client,
+		now:    time.Now,
+	}
+}
+
+func (p *Provider) Issuer() string {
+	return p.config.Issuer
+}
+
+// NewRandom
+// Generates a random value for state, nonce or PKCE code verifier
+func NewRandom() (string, error) {
+	buf := make([]byte, randomSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.Wrap(err, "try generate random value")
+	}
+
+	return encoding.EncodeToString(buf), nil
+}
+
+// Challenge
+// Returns the S256 PKCE code challenge of the code verifier
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return encoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL
+// Returns the address of the provider authorization page
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return "", errors.Wrap(ErrorDiscovery, err.Error())
+	}
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", Challenge(verifier))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
+
+type tokenResponse struct {
+	IdToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Exchange
+// Exchanges the authorization code for tokens and returns the id token
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (string, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("code_verifier", verifier)
+	form.Set("client_id", p.config.ClientID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint,
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", errors.Wrap(err, "try create token request")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	var res tokenResponse
+	status, err := p.do(req, &res)
+	if err != nil {
+		return "", errors.Wrap(ErrorExchange, err.Error())
+	}
+
+	if status != http.StatusOK {
+		return "", errors.Wrapf(ErrorExchange, "status %d, error %s: %s", status, res.Error, res.ErrorDescription)
+	}
+
+	if res.IdToken == "" {
+		return "", ErrorNoIdToken
+	}
+
+	return res.IdToken, nil
+}
+
+// Verify
+// Verifies the signature and the claims of the id token: issuer, audience, expiration time and nonce
+func (p *Provider) Verify(ctx context.Context, idToken, nonce string) (Claims, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := p.verifySignature(ctx, idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, errors.Wrap(ErrorMalformedToken, err.Error())
+	}
+
+	if claims.String("iss") != metadata.Issuer {
+		return nil, errors.Wrapf(ErrorInvalidClaims, "issuer %q", claims.String("iss"))
+	}
+
+	if claims.Subject() == "" {
+		return nil, errors.Wrap(ErrorInvalidClaims, "empty subject")
+	}
+
+	if err := p.checkAudience(claims); err != nil {
+		return nil, err
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, errors.Wrap(ErrorInvalidClaims, "no expiration time")
+	}
+
+	if p.now().Add(-clockSkew).Unix() >= int64(exp) {
+		return nil, ErrorTokenExpired
+	}
+
+	if claims.String("nonce") != nonce {
+		return nil, ErrorNonceMismatch
+	}
+
+	return claims, nil
+}
+
+// checkAudience
+// Проверяет, что токен выпущен для текущего клиента
+func (p *Provider) checkAudience(claims Claims) error {
+	audience := claims.Strings("aud")
+	for _, aud := range audience {
+		if aud != p.config.ClientID {
+			continue
+		}
+
+		// При нескольких получателях токен должен быть выпущен именно для текущего клиента
+		if len(audience) > 1 && claims.String("azp") != p.config.ClientID {
+			return errors.Wrapf(ErrorAudienceInvalid, "authorized party %q", claims.String("azp"))
+		}
+		return nil
+	}
+
+	return errors.Wrapf(ErrorAudienceInvalid, "audience %v", audience)
+}
+
+// discover
+// Загружает конфигурацию провайдера при первом обращении
+func (p *Provider) discover(ctx context.Context) (*Metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		strings.TrimSuffix(p.config.Issuer, "/")+DiscoveryPath, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "try create discovery request")
+	}
+
+	var metadata Metadata
+	status, err := p.do(req, &metadata)
+	if err != nil {
+		return nil, errors.Wrap(ErrorDiscovery, err.Error())
+	}
+
+	if status != http.StatusOK {
+		return nil, errors.Wrapf(ErrorDiscovery, "status %d", status)
+	}
+
+	if metadata.Issuer != p.config.Issuer {
+		return nil, errors.Wrapf(ErrorIssuerMismatch, "got %q", metadata.Issuer)
+	}
+
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, errors.Wrap(ErrorDiscovery, "endpoints are not set")
+	}
+
+	p.metadata = &metadata
+
+	return p.metadata, nil
+}
+
+// do
+// Выполняет запрос к провайдеру и разбирает JSON ответ
+func (p *Provider) do(req *http.Request, value any) (int, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return resp.StatusCode, errors.Wrap(err, "try read response")
+	}
+
+	if err := json.Unmarshal(body, value); err != nil && resp.StatusCode == http.StatusOK {
+		return resp.StatusCode, errors.Wrap(err, "try parse response")
+	}
+
+	return resp.StatusCode, nil
+}
@@ -49,6 +49,89 @@ const (
 	DataPublishField OrderField = "publish_date"
 )
 
+type ActorOrderField string
+
+const (
+	ActorNameField  ActorOrderField = "name"
+	BirthdayField   ActorOrderField = "birthday"
+	FilmsCountField ActorOrderField = "films_count"
+)
+
+type Certification string
+
+func (c *Certification) Scan(src any) error {
+	if str, ok := src.(string); ok {
+		*c = Certification(str)
+		return nil
+	}
+
+	if str, ok := src.([]byte); ok {
+		*c = Certification(str)
+		return nil
+	}
+
+	return errors.Errorf("invalid type of data for Certification %v", src)
+}
+
+func (c *Certification) Value() (driver.Value, error) {
+	return driver.Value(string(*c)), nil
+}
+
+const (
+	AGE0  Certification = "0+"
+	AGE6  Certification = "6+"
+	AGE12 Certification = "12+"
+	AGE16 Certification = "16+"
+	AGE18 Certification = "18+"
+
+	MPAAG    Certification = "G"
+	MPAAPG   Certification = "PG"
+	MPAAPG13 Certification = "PG-13"
+	MPAAR    Certification = "R"
+	MPAANC17 Certification = "NC-17"
+)
+
+var Certifications = []Certification{AGE0, AGE6, AGE12, AGE16, AGE18, MPAAG, MPAAPG, MPAAPG13, MPAAR, MPAANC17}
+
+// certificationAges
+// Минимальный возраст зрителя для каждого возрастного рейтинга. Рейтинги MPAA приведены к возрасту,
+// начиная с которого фильм можно смотреть без сопровождения взрослых.
+var certificationAges = map[Certification]uint8{
+	AGE0:     0,
+	AGE6:     6,
+	AGE12:    12,
+	AGE16:    16,
+	AGE18:    18,
+	MPAAG:    0,
+	MPAAPG:   6,
+	MPAAPG13: 13,
+	MPAAR:    17,
+	MPAANC17: 18,
+}
+
+func (c Certification) IsValid() bool {
+	_, ok := certificationAges[c]
+	return ok
+}
+
+// MinAge
+// Возвращает минимальный возраст зрителя для возрастного рейтинга.
+func (c Certification) MinAge() uint8 {
+	return certificationAges[c]
+}
+
+// AllowedCertifications
+// Возвращает все возрастные рейтинги, которые не строже maxCertification.
+func AllowedCertifications(maxCertification Certification) []Certification {
+	allowed := make([]Certification, 0, len(Certifications))
+	for _, certification := range Certifications {
+		if certification.MinAge() <= maxCertification.MinAge() {
+			allowed = append(allowed, certification)
+		}
+	}
+	return allowed
+}
+
 type SearchField string
 
 const (
@@ -58,9 +141,24 @@ const (
 
 type Roles string
 
+// ADMIN и USER встроенные роли, остальные роли задаются администратором в базе данных
 const (
 	ADMIN Roles = "admin"
 	USER  Roles = "user"
 )
 
+type Permission string
+
+const (
+	FilmWrite    Permission = "film:write"
+	FilmDelete   Permission = "film:delete"
+	ActorWrite   Permission = "actor:write"
+	ActorDelete  Permission = "actor:delete"
+	UserManage   Permission = "user:manage"
+	UserModerate Permission = "user:moderate"
+	RoleManage   Permission = "role:manage"
+)
+
+var Permissions = []Permission{FilmWrite, FilmDelete, ActorWrite, ActorDelete, UserManage, UserModerate, RoleManage}
+
 type ContextField string
@@ -2,6 +2,10 @@ package actor
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/ozontech/allure-go/pkg/framework/provider"
 	"github.com/ozontech/allure-go/pkg/framework/suite"
 	"github.com/pkg/errors"
@@ -10,10 +14,38 @@ import (
 	"vk_film/internal/pkg/time"
 	"vk_film/internal/pkg/types"
 	"vk_film/internal/repository/film"
+	"vk_film/pkg/slices"
 )
 
 var testError = errors.New("test error")
 
+var deathDateError = &pq.Error{Code: checkViolationCode, Constraint: deathDateConstraintName}
+
+func newTestActor() *Actor {
+	deathDate := time.MustParse("12.03.2093")
+	birthPlace := "Moscow"
+	country := "Russia"
+	biography := "Famous actor"
+
+	return &Actor{
+		ID:         1,
+		Name:       "actor",
+		Sex:        types.FEMALE,
+		Birthday:   time.MustParse("12.03.2003"),
+		DeathDate:  &deathDate,
+		BirthPlace: &birthPlace,
+		Country:    &country,
+		Biography:  &biography,
+		Aliases:    []string{"alias", "other alias"},
+	}
+}
+
+func actorWithId(actor *Actor, id types.Id) Actor {
+	act := *actor
+	act.ID = id
+	return act
+}
+
 type ActorRepositorySuite struct {
 	suite.Suite
 	actorRepository *PostgresActor
@@ -34,23 +66,21 @@ func (ars *ActorRepositorySuite) AfterEach(t provider.T) {
 func (ars *ActorRepositorySuite) TestCreateFunction(t provider.T) {
 	t.Title("CreateActor function of Actor repository")
 	t.NewStep("Init test data")
-	actor := &Actor{
-		ID:       1,
-		Name:     "actor",
-		Sex:      types.FEMALE,
-		Birthday: time.MustParse("12.03.2003"),
-	}
+	actor := newTestActor()
 
 	actorColumns := []string{
-		"id", "name", "sex", "birthday",
+		"id", "name", "sex", "birthday", "death_date", "birth_place", "country", "biography", "aliases",
 	}
 
 	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		ars.mock.ExpectQuery(createQuery).
-			WithArgs(actor.Name, actor.Sex, actor.Birthday.Time).
+			WithArgs(actor.Name, actor.Sex, actor.Birthday.Time, getNullTime(actor.DeathDate),
+				getNullString(actor.BirthPlace), getNullString(actor.Country), getNullString(actor.Biography),
+				pq.Array(actor.Aliases)).
 			WillReturnRows(sqlxmock.NewRows(actorColumns).
-				AddRow(actor.ID, actor.Name, actor.Sex, actor.Birthday.Time),
+				AddRow(actor.ID, actor.Name, actor.Sex, actor.Birthday.Time, actor.DeathDate.Time,
+					*actor.BirthPlace, *actor.Country, *actor.Biography, `{alias,"other alias"}`),
 			)
 
 		t.NewStep("Check result")
@@ -62,7 +92,9 @@ func (ars *ActorRepositorySuite) TestCreateFunction(t provider.T) {
 	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		ars.mock.ExpectQuery(createQuery).
-			WithArgs(actor.Name, actor.Sex, actor.Birthday.Time).
+			WithArgs(actor.Name, actor.Sex, actor.Birthday.Time, getNullTime(actor.DeathDate),
+				getNullString(actor.BirthPlace), getNullString(actor.Country), getNullString(actor.Biography),
+				pq.Array(actor.Aliases)).
 			WillReturnError(testError)
 
 		t.NewStep("Check result")
@@ -70,10 +102,25 @@ func (ars *ActorRepositorySuite) TestCreateFunction(t provider.T) {
 		t.Require().ErrorIs(err, testError)
 	})
 
+	t.WithNewStep("Death date before birthday error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ars.mock.ExpectQuery(createQuery).
+			WithArgs(actor.Name, actor.Sex, actor.Birthday.Time, getNullTime(actor.DeathDate),
+				getNullString(actor.BirthPlace), getNullString(actor.Country), getNullString(actor.Biography),
+				pq.Array(actor.Aliases)).
+			WillReturnError(deathDateError)
+
+		t.NewStep("Check result")
+		_, err := ars.actorRepository.CreateActor(actor)
+		t.Require().ErrorIs(err, ErrorDeathDateBeforeBirthday)
+	})
+
 	t.WithNewStep("Empty result of execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		ars.mock.ExpectQuery(createQuery).
-			WithArgs(actor.Name, actor.Sex, actor.Birthday.Time).
+			WithArgs(actor.Name, actor.Sex, actor.Birthday.Time, getNullTime(actor.DeathDate),
+				getNullString(actor.BirthPlace), getNullString(actor.Country), getNullString(actor.Biography),
+				pq.Array(actor.Aliases)).
 			WillReturnRows(sqlxmock.NewRows(actorColumns))
 
 		t.NewStep("Check result")
@@ -85,12 +132,7 @@ func (ars *ActorRepositorySuite) TestCreateFunction(t provider.T) {
 func (ars *ActorRepositorySuite) TestDeleteFunction(t provider.T) {
 	t.Title("DeleteActor function of Actor repository")
 	t.NewStep("Init test data")
-	actor := &Actor{
-		ID:       1,
-		Name:     "actor",
-		Sex:      types.FEMALE,
-		Birthday: time.MustParse("12.03.2003"),
-	}
+	actor := newTestActor()
 
 	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
@@ -140,15 +182,10 @@ func (ars *ActorRepositorySuite) TestDeleteFunction(t provider.T) {
 func (ars *ActorRepositorySuite) TestGetFunction(t provider.T) {
 	t.Title("GetActors function of Actor repository")
 	t.NewStep("Init test data")
-	actor := &Actor{
-		ID:       1,
-		Name:     "actor",
-		Sex:      types.FEMALE,
-		Birthday: time.MustParse("12.03.2003"),
-	}
+	actor := newTestActor()
 
 	actorColumns := []string{
-		"id", "name", "sex", "birthday",
+		"id", "name", "sex", "birthday", "death_date", "birth_place", "country", "biography", "aliases",
 	}
 
 	flm := &film.Film{
@@ -165,9 +202,12 @@ func (ars *ActorRepositorySuite) TestGetFunction(t provider.T) {
 
 	actorsRows := func() *sqlxmock.Rows {
 		return sqlxmock.NewRows(actorColumns).
-			AddRow(actor.ID, actor.Name, actor.Sex, actor.Birthday.Time).
-			AddRow(actor.ID+1, actor.Name, actor.Sex, actor.Birthday.Time).
-			AddRow(actor.ID+2, actor.Name, actor.Sex, actor.Birthday.Time)
+			AddRow(actor.ID, actor.Name, actor.Sex, actor.Birthday.Time, actor.DeathDate.Time,
+				*actor.BirthPlace, *actor.Country, *actor.Biography, `{alias,"other alias"}`).
+			AddRow(actor.ID+1, actor.Name, actor.Sex, actor.Birthday.Time, actor.DeathDate.Time,
+				*actor.BirthPlace, *actor.Country, *actor.Biography, `{alias,"other alias"}`).
+			AddRow(actor.ID+2, actor.Name, actor.Sex, actor.Birthday.Time, actor.DeathDate.Time,
+				*actor.BirthPlace, *actor.Country, *actor.Biography, `{alias,"other alias"}`)
 	}
 
 	filmsRows := func() *sqlxmock.Rows {
@@ -179,142 +219,250 @@ func (ars *ActorRepositorySuite) TestGetFunction(t provider.T) {
 			AddRow(actor.ID+2, flm.ID, flm.Name, flm.Description, flm.DataPublish.Time, flm.Rating)
 	}
 
+	params := Params{
+		OrderField: types.ActorNameField,
+		Order:      types.ASC,
+		WithFilms:  true,
+	}
+
+	getActorsQuery := ars.actorRepository.db.Rebind(fmt.Sprintf(getActors, "", "actors.name", types.ASC))
+	getActorsArgs := []driver.Value{sql.NullInt64{}, uint64(0)}
+
+	getFilmsQuery, getFilmsArgs, err := sqlx.In(getActorsFilms, []types.Id{1, 2, 3})
+	t.Require().NoError(err)
+	getFilmsQuery = ars.actorRepository.db.Rebind(getFilmsQuery)
+	getFilmsDriverArgs := slices.Map(getFilmsArgs, func(i interface{}) driver.Value { return i })
+
 	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		ars.mock.ExpectBegin()
-		ars.mock.ExpectQuery(getActors).WillReturnRows(actorsRows())
-		ars.mock.ExpectQuery(getActorsFilms).WillReturnRows(filmsRows())
+		ars.mock.ExpectQuery(getActorsQuery).WithArgs(getActorsArgs...).WillReturnRows(actorsRows())
+		ars.mock.ExpectQuery(getFilmsQuery).WithArgs(getFilmsDriverArgs...).WillReturnRows(filmsRows())
 		ars.mock.ExpectCommit()
 
 		t.NewStep("Check result")
-		actors, err := ars.actorRepository.GetActors()
+		actors, err := ars.actorRepository.GetActors(params)
 		t.Require().NoError(err)
 		t.Require().EqualValues([]ActorWithFilms{
 			{
-				Actor: Actor{ID: actor.ID, Name: actor.Name, Sex: actor.Sex, Birthday: actor.Birthday},
+				Actor: *actor,
 				Films: []film.Film{*flm, *flm, *flm},
 			},
 			{
-				Actor: Actor{ID: actor.ID + 1, Name: actor.Name, Sex: actor.Sex, Birthday: actor.Birthday},
+				Actor: actorWithId(actor, actor.ID+1),
 				Films: []film.Film{},
 			},
 			{
-				Actor: Actor{ID: actor.ID + 2, Name: actor.Name, Sex: actor.Sex, Birthday: actor.Birthday},
+				Actor: actorWithId(actor, actor.ID+2),
 				Films: []film.Film{*flm, *flm},
 			},
 		}, actors)
 	})
 
+	t.WithNewStep("Correct execute with max certification", func(t provider.StepCtx) {
+		t.NewStep("Init test data")
+		maxCertification := types.AGE0
+		certificationParams := params
+		certificationParams.MaxCertification = &maxCertification
+
+		filmsQuery, filmsArgs, err := sqlx.In(getActorsFilms+certificationCondition,
+			[]types.Id{1, 2, 3}, []string{string(types.AGE0), string(types.MPAAG)})
+		t.Require().NoError(err)
+		filmsQuery = ars.actorRepository.db.Rebind(filmsQuery)
+
+		t.NewStep("Init mock")
+		ars.mock.ExpectBegin()
+		ars.mock.ExpectQuery(getActorsQuery).WithArgs(getActorsArgs...).WillReturnRows(actorsRows())
+		ars.mock.ExpectQuery(filmsQuery).
+			WithArgs(slices.Map(filmsArgs, func(i interface{}) driver.Value { return i })...).
+			WillReturnRows(filmsRows())
+		ars.mock.ExpectCommit()
+
+		t.NewStep("Check result")
+		actors, err := ars.actorRepository.GetActors(certificationParams)
+		t.Require().NoError(err)
+		t.Require().Len(actors, 3)
+	})
+
+	t.WithNewStep("Correct all params without films execute", func(t provider.StepCtx) {
+		t.NewStep("Init test data")
+		sex := types.FEMALE
+		birthdayFrom := time.MustParse("01.01.1990")
+		birthdayTo := time.MustParse("31.12.2000")
+		query := ars.actorRepository.db.Rebind(fmt.Sprintf(getActors,
+			"WHERE "+searchCondition+" AND "+sexCondition+" AND "+birthdayFromCondition+" AND "+birthdayToCondition,
+			orderFields[types.FilmsCountField], types.DESC,
+		))
+
+		t.NewStep("Init mock")
+		ars.mock.ExpectBegin()
+		ars.mock.ExpectQuery(query).
+			WithArgs("act", "act", string(sex), birthdayFrom.Time, birthdayTo.Time,
+				sql.NullInt64{Valid: true, Int64: 10}, uint64(5)).
+			WillReturnRows(actorsRows())
+		ars.mock.ExpectCommit()
+
+		t.NewStep("Check result")
+		actors, err := ars.actorRepository.GetActors(Params{
+			SearchString: "act",
+			Sex:          &sex,
+			BirthdayFrom: &birthdayFrom,
+			BirthdayTo:   &birthdayTo,
+			OrderField:   types.FilmsCountField,
+			Order:        types.DESC,
+			Limit:        10,
+			Offset:       5,
+			WithFilms:    false,
+		})
+		t.Require().NoError(err)
+		t.Require().EqualValues([]ActorWithFilms{
+			{Actor: *actor},
+			{Actor: actorWithId(actor, actor.ID+1)},
+			{Actor: actorWithId(actor, actor.ID+2)},
+		}, actors)
+	})
+
+	t.WithNewStep("Correct search with like wildcards execute", func(t provider.StepCtx) {
+		t.NewStep("Init test data")
+		query := ars.actorRepository.db.Rebind(fmt.Sprintf(getActors,
+			"WHERE "+searchCondition, orderFields[types.ActorNameField], types.ASC,
+		))
+
+		t.NewStep("Init mock")
+		ars.mock.ExpectBegin()
+		ars.mock.ExpectQuery(query).
+			WithArgs(`100\% a\_b\\c`, `100\% a\_b\\c`, sql.NullInt64{Valid: false}, uint64(0)).
+			WillReturnRows(actorsRows())
+		ars.mock.ExpectCommit()
+
+		t.NewStep("Check result")
+		actors, err := ars.actorRepository.GetActors(Params{SearchString: `100% a_b\c`})
+		t.Require().NoError(err)
+		t.Require().Len(actors, 3)
+	})
+
+	t.WithNewStep("Correct empty result execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ars.mock.ExpectBegin()
+		ars.mock.ExpectQuery(getActorsQuery).WithArgs(getActorsArgs...).WillReturnRows(sqlxmock.NewRows(actorColumns))
+		ars.mock.ExpectCommit()
+
+		t.NewStep("Check result")
+		actors, err := ars.actorRepository.GetActors(params)
+		t.Require().NoError(err)
+		t.Require().EqualValues([]ActorWithFilms{}, actors)
+	})
+
 	t.WithNewStep("Postgres error on begin transaction", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		ars.mock.ExpectBegin().WillReturnError(testError)
 
 		t.NewStep("Check result")
-		_, err := ars.actorRepository.GetActors()
+		_, err := ars.actorRepository.GetActors(params)
 		t.Require().ErrorIs(err, testError)
 	})
 
 	t.WithNewStep("Postgres error on getActors query", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		ars.mock.ExpectBegin()
-		ars.mock.ExpectQuery(getActors).WillReturnError(testError)
+		ars.mock.ExpectQuery(getActorsQuery).WillReturnError(testError)
 		ars.mock.ExpectRollback()
 
 		t.NewStep("Check result")
-		_, err := ars.actorRepository.GetActors()
+		_, err := ars.actorRepository.GetActors(params)
 		t.Require().ErrorIs(err, testError)
 	})
 
 	t.WithNewStep("Rows error on getActors query", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		ars.mock.ExpectBegin()
-		ars.mock.ExpectQuery(getActors).WillReturnRows(actorsRows().RowError(1, testError))
+		ars.mock.ExpectQuery(getActorsQuery).WillReturnRows(actorsRows().RowError(1, testError))
 		ars.mock.ExpectRollback()
 
 		t.NewStep("Check result")
-		_, err := ars.actorRepository.GetActors()
+		_, err := ars.actorRepository.GetActors(params)
 		t.Require().ErrorIs(err, testError)
 	})
 
 	t.WithNewStep("Incorrect field in row of getActors query", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		ars.mock.ExpectBegin()
-		ars.mock.ExpectQuery(getActors).WillReturnRows(actorsRows().AddRow(1, 1, 1, 1))
+		ars.mock.ExpectQuery(getActorsQuery).WillReturnRows(actorsRows().AddRow(1, 1, 1, 1, 1, 1, 1, 1, 1))
 		ars.mock.ExpectRollback()
 
 		t.NewStep("Check result")
-		_, err := ars.actorRepository.GetActors()
+		_, err := ars.actorRepository.GetActors(params)
 		t.Require().Error(err)
 	})
 
 	t.WithNewStep("Rows close error on getActors query", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		ars.mock.ExpectBegin()
-		ars.mock.ExpectQuery(getActors).WillReturnRows(actorsRows().CloseError(testError))
+		ars.mock.ExpectQuery(getActorsQuery).WillReturnRows(actorsRows().CloseError(testError))
 		ars.mock.ExpectRollback()
 
 		t.NewStep("Check result")
-		_, err := ars.actorRepository.GetActors()
+		_, err := ars.actorRepository.GetActors(params)
 		t.Require().ErrorIs(err, testError)
 	})
 
 	t.WithNewStep("Postgres error on getActorFilms query", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		ars.mock.ExpectBegin()
-		ars.mock.ExpectQuery(getActors).WillReturnRows(actorsRows())
-		ars.mock.ExpectQuery(getActorsFilms).WillReturnError(testError)
+		ars.mock.ExpectQuery(getActorsQuery).WillReturnRows(actorsRows())
+		ars.mock.ExpectQuery(getFilmsQuery).WillReturnError(testError)
 		ars.mock.ExpectRollback()
 
 		t.NewStep("Check result")
-		_, err := ars.actorRepository.GetActors()
+		_, err := ars.actorRepository.GetActors(params)
 		t.Require().ErrorIs(err, testError)
 	})
 
 	t.WithNewStep("Rows error on getActorFilms query", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		ars.mock.ExpectBegin()
-		ars.mock.ExpectQuery(getActors).WillReturnRows(actorsRows())
-		ars.mock.ExpectQuery(getActorsFilms).WillReturnRows(filmsRows().RowError(1, testError))
+		ars.mock.ExpectQuery(getActorsQuery).WillReturnRows(actorsRows())
+		ars.mock.ExpectQuery(getFilmsQuery).WillReturnRows(filmsRows().RowError(1, testError))
 		ars.mock.ExpectRollback()
 
 		t.NewStep("Check result")
-		_, err := ars.actorRepository.GetActors()
+		_, err := ars.actorRepository.GetActors(params)
 		t.Require().ErrorIs(err, testError)
 	})
 
 	t.WithNewStep("Rows close error on getActorFilms query", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		ars.mock.ExpectBegin()
-		ars.mock.ExpectQuery(getActors).WillReturnRows(actorsRows())
-		ars.mock.ExpectQuery(getActorsFilms).WillReturnRows(filmsRows().CloseError(testError))
+		ars.mock.ExpectQuery(getActorsQuery).WillReturnRows(actorsRows())
+		ars.mock.ExpectQuery(getFilmsQuery).WillReturnRows(filmsRows().CloseError(testError))
 		ars.mock.ExpectRollback()
 
 		t.NewStep("Check result")
-		_, err := ars.actorRepository.GetActors()
+		_, err := ars.actorRepository.GetActors(params)
 		t.Require().ErrorIs(err, testError)
 	})
 
 	t.WithNewStep("Incorrect field in row of getActorFilms query", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		ars.mock.ExpectBegin()
-		ars.mock.ExpectQuery(getActors).WillReturnRows(actorsRows())
-		ars.mock.ExpectQuery(getActorsFilms).WillReturnRows(filmsRows().AddRow(1, 1, 1, 1, 1, 1))
+		ars.mock.ExpectQuery(getActorsQuery).WillReturnRows(actorsRows())
+		ars.mock.ExpectQuery(getFilmsQuery).WillReturnRows(filmsRows().AddRow(1, 1, 1, 1, 1, 1))
 		ars.mock.ExpectRollback()
 
 		t.NewStep("Check result")
-		_, err := ars.actorRepository.GetActors()
+		_, err := ars.actorRepository.GetActors(params)
 		t.Require().Error(err)
 	})
 
 	t.WithNewStep("Postgres error on commit transaction", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		ars.mock.ExpectBegin()
-		ars.mock.ExpectQuery(getActors).WillReturnRows(actorsRows())
-		ars.mock.ExpectQuery(getActorsFilms).WillReturnRows(filmsRows())
+		ars.mock.ExpectQuery(getActorsQuery).WillReturnRows(actorsRows())
+		ars.mock.ExpectQuery(getFilmsQuery).WillReturnRows(filmsRows())
 		ars.mock.ExpectCommit().WillReturnError(testError)
 
 		t.NewStep("Check result")
-		_, err := ars.actorRepository.GetActors()
+		_, err := ars.actorRepository.GetActors(params)
 		t.Require().ErrorIs(err, testError)
 	})
 }
@@ -322,15 +470,10 @@ func (ars *ActorRepositorySuite) TestGetFunction(t provider.T) {
 func (ars *ActorRepositorySuite) TestUpdateFunction(t provider.T) {
 	t.Title("UpdateActor function of Actor repository")
 	t.NewStep("Init test data")
-	actor := &Actor{
-		ID:       1,
-		Name:     "actor",
-		Sex:      types.FEMALE,
-		Birthday: time.MustParse("12.03.2003"),
-	}
+	actor := newTestActor()
 
 	actorColumns := []string{
-		"id", "name", "sex", "birthday",
+		"id", "name", "sex", "birthday", "death_date", "birth_place", "country", "biography", "aliases",
 	}
 
 	flm := &film.Film{
@@ -347,7 +490,8 @@ func (ars *ActorRepositorySuite) TestUpdateFunction(t provider.T) {
 
 	actorsRows := func() *sqlxmock.Rows {
 		return sqlxmock.NewRows(actorColumns).
-			AddRow(actor.ID, actor.Name, actor.Sex, actor.Birthday.Time)
+			AddRow(actor.ID, actor.Name, actor.Sex, actor.Birthday.Time, actor.DeathDate.Time,
+				*actor.BirthPlace, *actor.Country, *actor.Biography, `{alias,"other alias"}`)
 	}
 
 	filmsRows := func() *sqlxmock.Rows {
@@ -365,6 +509,11 @@ func (ars *ActorRepositorySuite) TestUpdateFunction(t provider.T) {
 				getNullString(&actor.Name),
 				getNullString((*string)(&actor.Sex)),
 				sql.NullTime{Valid: true, Time: actor.Birthday.Time},
+				sql.NullTime{Valid: false},
+				getNullString(nil),
+				getNullString(nil),
+				getNullString(nil),
+				nil,
 			).WillReturnRows(actorsRows())
 		ars.mock.ExpectQuery(getActorFilms).WillReturnRows(filmsRows())
 		ars.mock.ExpectCommit()
@@ -378,7 +527,40 @@ func (ars *ActorRepositorySuite) TestUpdateFunction(t provider.T) {
 		})
 		t.Require().NoError(err)
 		t.Require().EqualValues(&ActorWithFilms{
-			Actor: Actor{ID: actor.ID, Name: actor.Name, Sex: actor.Sex, Birthday: actor.Birthday},
+			Actor: *actor,
+			Films: []film.Film{*flm, *flm, *flm},
+		}, actors)
+	})
+
+	t.WithNewStep("Correct profile execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ars.mock.ExpectBegin()
+		ars.mock.ExpectQuery(updateActors).
+			WithArgs(actor.ID,
+				getNullString(nil),
+				getNullString(nil),
+				sql.NullTime{Valid: false},
+				sql.NullTime{Valid: true, Time: actor.DeathDate.Time},
+				getNullString(actor.BirthPlace),
+				getNullString(actor.Country),
+				getNullString(actor.Biography),
+				pq.Array(actor.Aliases),
+			).WillReturnRows(actorsRows())
+		ars.mock.ExpectQuery(getActorFilms).WillReturnRows(filmsRows())
+		ars.mock.ExpectCommit()
+
+		t.NewStep("Check result")
+		actors, err := ars.actorRepository.UpdateActor(&UpdateActor{
+			ID:         actor.ID,
+			DeathDate:  actor.DeathDate,
+			BirthPlace: actor.BirthPlace,
+			Country:    actor.Country,
+			Biography:  actor.Biography,
+			Aliases:    &actor.Aliases,
+		})
+		t.Require().NoError(err)
+		t.Require().EqualValues(&ActorWithFilms{
+			Actor: *actor,
 			Films: []film.Film{*flm, *flm, *flm},
 		}, actors)
 	})
@@ -391,6 +573,11 @@ func (ars *ActorRepositorySuite) TestUpdateFunction(t provider.T) {
 				getNullString(&actor.Name),
 				getNullString(nil),
 				sql.NullTime{Valid: false},
+				sql.NullTime{Valid: false},
+				getNullString(nil),
+				getNullString(nil),
+				getNullString(nil),
+				nil,
 			).WillReturnRows(actorsRows())
 		ars.mock.ExpectQuery(getActorFilms).WillReturnRows(filmsRows())
 		ars.mock.ExpectCommit()
@@ -404,7 +591,7 @@ func (ars *ActorRepositorySuite) TestUpdateFunction(t provider.T) {
 		})
 		t.Require().NoError(err)
 		t.Require().EqualValues(&ActorWithFilms{
-			Actor: Actor{ID: actor.ID, Name: actor.Name, Sex: actor.Sex, Birthday: actor.Birthday},
+			Actor: *actor,
 			Films: []film.Film{*flm, *flm, *flm},
 		}, actors)
 	})
@@ -417,6 +604,11 @@ func (ars *ActorRepositorySuite) TestUpdateFunction(t provider.T) {
 				getNullString(nil),
 				getNullString((*string)(&actor.Sex)),
 				sql.NullTime{Valid: false},
+				sql.NullTime{Valid: false},
+				getNullString(nil),
+				getNullString(nil),
+				getNullString(nil),
+				nil,
 			).WillReturnRows(actorsRows())
 		ars.mock.ExpectQuery(getActorFilms).WillReturnRows(filmsRows())
 		ars.mock.ExpectCommit()
@@ -430,7 +622,7 @@ func (ars *ActorRepositorySuite) TestUpdateFunction(t provider.T) {
 		})
 		t.Require().NoError(err)
 		t.Require().EqualValues(&ActorWithFilms{
-			Actor: Actor{ID: actor.ID, Name: actor.Name, Sex: actor.Sex, Birthday: actor.Birthday},
+			Actor: *actor,
 			Films: []film.Film{*flm, *flm, *flm},
 		}, actors)
 	})
@@ -443,6 +635,11 @@ func (ars *ActorRepositorySuite) TestUpdateFunction(t provider.T) {
 				getNullString(nil),
 				getNullString(nil),
 				sql.NullTime{Valid: true, Time: actor.Birthday.Time},
+				sql.NullTime{Valid: false},
+				getNullString(nil),
+				getNullString(nil),
+				getNullString(nil),
+				nil,
 			).WillReturnRows(actorsRows())
 		ars.mock.ExpectQuery(getActorFilms).WillReturnRows(filmsRows())
 		ars.mock.ExpectCommit()
@@ -456,7 +653,40 @@ func (ars *ActorRepositorySuite) TestUpdateFunction(t provider.T) {
 		})
 		t.Require().NoError(err)
 		t.Require().EqualValues(&ActorWithFilms{
-			Actor: Actor{ID: actor.ID, Name: actor.Name, Sex: actor.Sex, Birthday: actor.Birthday},
+			Actor: *actor,
+			Films: []film.Film{*flm, *flm, *flm},
+		}, actors)
+	})
+
+	t.WithNewStep("Correct execute with max certification", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		maxCertification := types.AGE12
+		ars.mock.ExpectBegin()
+		ars.mock.ExpectQuery(updateActors).
+			WithArgs(actor.ID,
+				getNullString(&actor.Name),
+				getNullString(nil),
+				sql.NullTime{Valid: false},
+				sql.NullTime{Valid: false},
+				getNullString(nil),
+				getNullString(nil),
+				getNullString(nil),
+				nil,
+			).WillReturnRows(actorsRows())
+		ars.mock.ExpectQuery(getActorFilms).
+			WithArgs(actor.ID, pq.StringArray{"0+", "6+", "12+", "G", "PG"}).
+			WillReturnRows(filmsRows())
+		ars.mock.ExpectCommit()
+
+		t.NewStep("Check result")
+		actors, err := ars.actorRepository.UpdateActor(&UpdateActor{
+			ID:               actor.ID,
+			Name:             &actor.Name,
+			MaxCertification: &maxCertification,
+		})
+		t.Require().NoError(err)
+		t.Require().EqualValues(&ActorWithFilms{
+			Actor: *actor,
 			Films: []film.Film{*flm, *flm, *flm},
 		}, actors)
 	})
@@ -491,6 +721,20 @@ func (ars *ActorRepositorySuite) TestUpdateFunction(t provider.T) {
 		t.Require().ErrorIs(err, testError)
 	})
 
+	t.WithNewStep("Death date before birthday error on updateActors query", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ars.mock.ExpectBegin()
+		ars.mock.ExpectQuery(updateActors).WillReturnError(deathDateError)
+		ars.mock.ExpectRollback()
+
+		t.NewStep("Check result")
+		_, err := ars.actorRepository.UpdateActor(&UpdateActor{
+			ID:        actor.ID,
+			DeathDate: actor.DeathDate,
+		})
+		t.Require().ErrorIs(err, ErrorDeathDateBeforeBirthday)
+	})
+
 	t.WithNewStep("No actor found in updateActors query", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		ars.mock.ExpectBegin()
@@ -515,6 +759,11 @@ func (ars *ActorRepositorySuite) TestUpdateFunction(t provider.T) {
 				getNullString(nil),
 				getNullString(nil),
 				sql.NullTime{Valid: true, Time: actor.Birthday.Time},
+				sql.NullTime{Valid: false},
+				getNullString(nil),
+				getNullString(nil),
+				getNullString(nil),
+				nil,
 			).WillReturnRows(actorsRows())
 		ars.mock.ExpectQuery(getActorFilms).WillReturnRows(filmsRows().RowError(1, testError))
 		ars.mock.ExpectRollback()
@@ -537,6 +786,11 @@ func (ars *ActorRepositorySuite) TestUpdateFunction(t provider.T) {
 				getNullString(nil),
 				getNullString(nil),
 				sql.NullTime{Valid: true, Time: actor.Birthday.Time},
+				sql.NullTime{Valid: false},
+				getNullString(nil),
+				getNullString(nil),
+				getNullString(nil),
+				nil,
 			).WillReturnRows(actorsRows())
 		ars.mock.ExpectQuery(getActorFilms).WillReturnError(testError)
 		ars.mock.ExpectRollback()
@@ -559,6 +813,11 @@ func (ars *ActorRepositorySuite) TestUpdateFunction(t provider.T) {
 				getNullString(nil),
 				getNullString(nil),
 				sql.NullTime{Valid: true, Time: actor.Birthday.Time},
+				sql.NullTime{Valid: false},
+				getNullString(nil),
+				getNullString(nil),
+				getNullString(nil),
+				nil,
 			).WillReturnRows(actorsRows())
 		ars.mock.ExpectQuery(getActorFilms).WillReturnRows(filmsRows().CloseError(testError))
 		ars.mock.ExpectRollback()
@@ -581,6 +840,11 @@ func (ars *ActorRepositorySuite) TestUpdateFunction(t provider.T) {
 				getNullString(nil),
 				getNullString(nil),
 				sql.NullTime{Valid: true, Time: actor.Birthday.Time},
+				sql.NullTime{Valid: false},
+				getNullString(nil),
+				getNullString(nil),
+				getNullString(nil),
+				nil,
 			).WillReturnRows(actorsRows())
 		ars.mock.ExpectQuery(getActorFilms).WillReturnRows(filmsRows().AddRow(1, 1, 1, 1, 1))
 		ars.mock.ExpectRollback()
@@ -603,6 +867,11 @@ func (ars *ActorRepositorySuite) TestUpdateFunction(t provider.T) {
 				getNullString(nil),
 				getNullString(nil),
 				sql.NullTime{Valid: true, Time: actor.Birthday.Time},
+				sql.NullTime{Valid: false},
+				getNullString(nil),
+				getNullString(nil),
+				getNullString(nil),
+				nil,
 			).WillReturnRows(actorsRows())
 		ars.mock.ExpectQuery(getActorFilms).WillReturnRows(filmsRows())
 		ars.mock.ExpectCommit().WillReturnError(testError)
@@ -2,25 +2,48 @@ package actor
 
 import (
 	"github.com/pkg/errors"
+	"vk_film/internal/pkg/time"
 	"vk_film/internal/pkg/types"
 )
 
 var (
-	ErrorActorNotFound = errors.New("actor with id not found")
+	ErrorActorNotFound           = errors.New("actor with id not found")
+	ErrorDeathDateBeforeBirthday = errors.New("actor death date is not after birthday")
 )
 
 //go:generate mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=ActorRepository . Repository
 
+// Params
+// SearchString matches both the name and the aliases of the actor.
+// Empty SearchString and nil filters mean that the actors are not filtered by the field.
+// Zero Limit means that the number of actors is not limited.
+// Non nil MaxCertification hides films with a stricter or unknown age certification from the filmography.
+type Params struct {
+	SearchString string
+	Sex          *types.Sexes
+	BirthdayFrom *time.FormattedTime
+	BirthdayTo   *time.FormattedTime
+	OrderField   types.ActorOrderField
+	Order        types.Order
+	Limit        uint64
+	Offset       uint64
+	WithFilms    bool
+
+	MaxCertification *types.Certification
+}
+
 type Repository interface {
 	// CreateActor
 	// Returns Error:
 	//   - SQLError
+	//   - ErrorDeathDateBeforeBirthday
 	CreateActor(actor *Actor) (*Actor, error)
 
 	// UpdateActor
 	// Returns Error:
 	//   - SQLError
 	//   - ErrorActorNotFound
+	//   - ErrorDeathDateBeforeBirthday
 	UpdateActor(actor *UpdateActor) (*ActorWithFilms, error)
 
 	// DeleteActor
@@ -32,5 +55,5 @@ type Repository interface {
 	// GetActors
 	// Returns Error:
 	//   - SQLError
-	GetActors() ([]ActorWithFilms, error)
+	GetActors(params Params) ([]ActorWithFilms, error)
 }
@@ -70,18 +70,18 @@ func (mr *ActorRepositoryMockRecorder) DeleteActor(arg0 any) *gomock.Call {
 }
 
 // GetActors mocks base method.
-func (m *ActorRepository) GetActors() ([]actor.ActorWithFilms, error) {
+func (m *ActorRepository) GetActors(arg0 actor.Params) ([]actor.ActorWithFilms, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetActors")
+	ret := m.ctrl.Call(m, "GetActors", arg0)
 	ret0, _ := ret[0].([]actor.ActorWithFilms)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetActors indicates an expected call of GetActors.
-func (mr *ActorRepositoryMockRecorder) GetActors() *gomock.Call {
+func (mr *ActorRepositoryMockRecorder) GetActors(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActors", reflect.TypeOf((*ActorRepository)(nil).GetActors))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActors", reflect.TypeOf((*ActorRepository)(nil).GetActors), arg0)
 }
 
 // UpdateActor mocks base method.
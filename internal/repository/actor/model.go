@@ -6,18 +6,33 @@ import (
 	"vk_film/internal/repository/film"
 )
 
+// UpdateActor
+// Nil fields are not updated.
+// Non nil MaxCertification hides films with a stricter or unknown age certification from the returned filmography.
 type UpdateActor struct {
-	ID       types.Id
-	Name     *string
-	Sex      *types.Sexes
-	Birthday *time.FormattedTime
+	ID         types.Id
+	Name       *string
+	Sex        *types.Sexes
+	Birthday   *time.FormattedTime
+	DeathDate  *time.FormattedTime
+	BirthPlace *string
+	Country    *string
+	Biography  *string
+	Aliases    *[]string
+
+	MaxCertification *types.Certification
 }
 
 type Actor struct {
-	ID       types.Id
-	Name     string
-	Sex      types.Sexes
-	Birthday time.FormattedTime
+	ID         types.Id
+	Name       string
+	Sex        types.Sexes
+	Birthday   time.FormattedTime
+	DeathDate  *time.FormattedTime
+	BirthPlace *string
+	Country    *string
+	Biography  *string
+	Aliases    []string
 }
 
 type ActorWithFilms struct {
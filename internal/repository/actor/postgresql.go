@@ -2,17 +2,22 @@ package actor
 
 import (
 	"database/sql"
+	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
+	"strings"
+	"vk_film/internal/pkg/time"
 	"vk_film/internal/pkg/types"
 	"vk_film/internal/repository/film"
+	"vk_film/pkg/slices"
 )
 
 const (
 	createQuery = `
-		INSERT INTO actors (name, sex, birthday)
-		VALUES ($1, $2, $3)
-		RETURNING id, name, sex, birthday
+		INSERT INTO actors (name, sex, birthday, death_date, birth_place, country, biography, aliases)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, name, sex, birthday, death_date, birth_place, country, biography, aliases
 	`
 
 	deleteActor = `
@@ -20,34 +25,65 @@ const (
 	`
 
 	updateActors = `
-		UPDATE actors SET name = upd_actor.upd_name, sex = upd_actor.upd_sex, birthday = upd_actor.upd_birthday 
+		UPDATE actors SET name = upd_actor.upd_name, sex = upd_actor.upd_sex, birthday = upd_actor.upd_birthday,
+						  death_date = upd_actor.upd_death_date, birth_place = upd_actor.upd_birth_place,
+						  country = upd_actor.upd_country, biography = upd_actor.upd_biography,
+						  aliases = upd_actor.upd_aliases
 			FROM (
 				SELECT COALESCE($2, actors.name) as upd_name, 
 					   COALESCE($3, actors.sex) as upd_sex, 
-					   COALESCE($4, actors.birthday) as upd_birthday 
+					   COALESCE($4, actors.birthday) as upd_birthday,
+					   COALESCE($5, actors.death_date) as upd_death_date,
+					   COALESCE($6, actors.birth_place) as upd_birth_place,
+					   COALESCE($7, actors.country) as upd_country,
+					   COALESCE($8, actors.biography) as upd_biography,
+					   COALESCE($9, actors.aliases) as upd_aliases
 				FROM actors WHERE id = $1
 			) as upd_actor
 			WHERE id = $1
-			RETURNING id, name, sex, birthday
+			RETURNING id, name, sex, birthday, death_date, birth_place, country, biography, aliases
 	`
 
 	getActorFilms = `
 		SELECT films.id, films.name, films.description, films.publish_date, films.rating FROM film_actor
 			JOIN films on (films.id = film_actor.film_id)
 		 	WHERE film_actor.actor_id = $1
+		 		AND ($2::certifications[] IS NULL OR films.age_certification = ANY($2))
 	`
 
 	getActors = `
-		SELECT id, name, sex, birthday FROM actors
+		SELECT actors.id, actors.name, actors.sex, actors.birthday, actors.death_date,
+			   actors.birth_place, actors.country, actors.biography, actors.aliases FROM actors
+		%s
+		ORDER BY %s %s, actors.id
+		LIMIT ? OFFSET ?
 	`
 
 	getActorsFilms = `
 		SELECT actors.id, films.id, films.name, films.description, films.publish_date, films.rating FROM actors 
 			JOIN film_actor on (actors.id = film_actor.actor_id)
 			JOIN films on (films.id = film_actor.film_id)
+			WHERE actors.id in (?)
 	`
+
+	searchCondition       = "(actors.name LIKE '%' || ? || '%' ESCAPE '\\' OR EXISTS (SELECT 1 FROM unnest(actors.aliases) as alias WHERE alias LIKE '%' || ? || '%' ESCAPE '\\'))"
+	sexCondition          = "actors.sex = ?"
+	birthdayFromCondition = "actors.birthday >= ?"
+	birthdayToCondition   = "actors.birthday <= ?"
+
+	certificationCondition = " AND films.age_certification IN (?)"
 )
 
+// likeEscaper
+// Экранирует спецсимволы шаблона LIKE, чтобы строка поиска сравнивалась буквально
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+var orderFields = map[types.ActorOrderField]string{
+	types.ActorNameField:  "actors.name",
+	types.BirthdayField:   "actors.birthday",
+	types.FilmsCountField: "(SELECT count(*) FROM film_actor WHERE film_actor.actor_id = actors.id)",
+}
+
 type PostgresActor struct {
 	db *sqlx.DB
 }
@@ -58,17 +94,32 @@ func NewPostgresActor(db *sqlx.DB) *PostgresActor {
 	}
 }
 
+var _ = Repository(&PostgresActor{})
+
 func (pa *PostgresActor) CreateActor(actor *Actor) (*Actor, error) {
 	newActor := &Actor{}
 
-	if err := pa.db.QueryRowx(createQuery, actor.Name, actor.Sex, &actor.Birthday).
+	deathDate := getNullTime(actor.DeathDate)
+
+	aliases := actor.Aliases
+	if aliases == nil {
+		aliases = make([]string, 0)
+	}
+
+	if err := pa.db.QueryRowx(createQuery, actor.Name, actor.Sex, &actor.Birthday, deathDate,
+		getNullString(actor.BirthPlace), getNullString(actor.Country), getNullString(actor.Biography), pq.Array(aliases)).
 		Scan(
 			&newActor.ID,
 			&newActor.Name,
 			&newActor.Sex,
 			&newActor.Birthday,
+			&newActor.DeathDate,
+			&newActor.BirthPlace,
+			&newActor.Country,
+			&newActor.Biography,
+			pq.Array(&newActor.Aliases),
 		); err != nil {
-		return nil, errors.Wrap(err, "can't create actor")
+		return nil, errors.Wrap(checkDeathDateError(err), "can't create actor")
 	}
 
 	return newActor, nil
@@ -81,14 +132,42 @@ func getNullString(value *string) sql.NullString {
 	return sql.NullString{Valid: true, String: *value}
 }
 
+func getNullTime(value *time.FormattedTime) sql.NullTime {
+	if value == nil {
+		return sql.NullTime{Valid: false}
+	}
+	return sql.NullTime{Valid: true, Time: value.Time}
+}
+
+func getNullAliases(value *[]string) any {
+	if value == nil {
+		return nil
+	}
+	return pq.Array(*value)
+}
+
+const (
+	checkViolationCode      = "23514"
+	deathDateConstraintName = "actors_death_date_check"
+)
+
+func checkDeathDateError(err error) error {
+	var e *pq.Error
+	if errors.As(err, &e) && e.Code == checkViolationCode && e.Constraint == deathDateConstraintName {
+		return ErrorDeathDateBeforeBirthday
+	}
+	return err
+}
+
 func (pa *PostgresActor) UpdateActor(actor *UpdateActor) (*ActorWithFilms, error) {
 	name := getNullString(actor.Name)
 	sex := getNullString((*string)(actor.Sex))
-
-	birthday := sql.NullTime{Valid: false}
-	if actor.Birthday != nil {
-		birthday = sql.NullTime{Valid: true, Time: actor.Birthday.Time}
-	}
+	birthday := getNullTime(actor.Birthday)
+	deathDate := getNullTime(actor.DeathDate)
+	birthPlace := getNullString(actor.BirthPlace)
+	country := getNullString(actor.Country)
+	biography := getNullString(actor.Biography)
+	aliases := getNullAliases(actor.Aliases)
 
 	tx, err := pa.db.Beginx()
 	if err != nil {
@@ -97,22 +176,27 @@ func (pa *PostgresActor) UpdateActor(actor *UpdateActor) (*ActorWithFilms, error
 
 	updatedActor := &ActorWithFilms{}
 
-	if err := tx.QueryRowx(updateActors, actor.ID, name, sex, birthday).
+	if err := tx.QueryRowx(updateActors, actor.ID, name, sex, birthday, deathDate, birthPlace, country, biography, aliases).
 		Scan(
 			&updatedActor.ID,
 			&updatedActor.Name,
 			&updatedActor.Sex,
 			&updatedActor.Birthday,
+			&updatedActor.DeathDate,
+			&updatedActor.BirthPlace,
+			&updatedActor.Country,
+			&updatedActor.Biography,
+			pq.Array(&updatedActor.Aliases),
 		); err != nil {
 		_ = tx.Rollback()
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrorActorNotFound
 		}
-		return nil, errors.Wrapf(err, "can't update actor with id %d", actor.ID)
+		return nil, errors.Wrapf(checkDeathDateError(err), "can't update actor with id %d", actor.ID)
 	}
 
 	// Получаем список фильмов для автора
-	rows, err := tx.Queryx(getActorFilms, actor.ID)
+	rows, err := tx.Queryx(getActorFilms, actor.ID, getAllowedCertifications(actor.MaxCertification))
 	if err != nil {
 		_ = tx.Rollback()
 		return nil, errors.Wrap(err, "can't execute get updated actor films query")
@@ -167,21 +251,77 @@ func (pa *PostgresActor) DeleteActor(id types.Id) error {
 	return nil
 }
 
-func (pa *PostgresActor) GetActors() ([]ActorWithFilms, error) {
+// buildGetActorsQuery
+// Формирует запрос получения актёров с учётом фильтров, сортировки и пагинации.
+// Запрос использует плейсхолдеры '?', которые необходимо привести к формату драйвера.
+func buildGetActorsQuery(params Params) (string, []any) {
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+
+	if params.SearchString != "" {
+		search := likeEscaper.Replace(params.SearchString)
+		conditions = append(conditions, searchCondition)
+		args = append(args, search, search)
+	}
+
+	if params.Sex != nil {
+		conditions = append(conditions, sexCondition)
+		args = append(args, string(*params.Sex))
+	}
+
+	if params.BirthdayFrom != nil {
+		conditions = append(conditions, birthdayFromCondition)
+		args = append(args, params.BirthdayFrom.Time)
+	}
+
+	if params.BirthdayTo != nil {
+		conditions = append(conditions, birthdayToCondition)
+		args = append(args, params.BirthdayTo.Time)
+	}
+
+	where := ""
+	if len(conditions) != 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	orderField, ok := orderFields[params.OrderField]
+	if !ok {
+		orderField = orderFields[types.ActorNameField]
+	}
+
+	order := params.Order
+	if order != types.DESC {
+		order = types.ASC
+	}
+
+	limit := sql.NullInt64{Valid: false}
+	if params.Limit != 0 {
+		limit = sql.NullInt64{Valid: true, Int64: int64(params.Limit)}
+	}
+
+	args = append(args, limit, params.Offset)
+
+	return fmt.Sprintf(getActors, where, orderField, order), args
+}
+
+func (pa *PostgresActor) GetActors(params Params) ([]ActorWithFilms, error) {
 	tx, err := pa.db.Beginx()
 	if err != nil {
 		return nil, errors.Wrap(err, "can't create transaction for get actors")
 	}
 
-	// Получаем список всех авторов
-	rows, err := tx.Queryx(getActors)
+	query, args := buildGetActorsQuery(params)
+
+	// Получаем список авторов
+	rows, err := tx.Queryx(tx.Rebind(query), args...)
 	if err != nil {
 		_ = tx.Rollback()
 		return nil, errors.Wrap(err, "can't execute get actors query")
 	}
 
 	actors := make([]ActorWithFilms, 0)
-	actorsId := make(map[types.Id]uint64)
+	actorsIndx := make(map[types.Id]uint64)
+	actorsId := make([]types.Id, 0)
 	i := uint64(0)
 
 	for rows.Next() {
@@ -192,6 +332,11 @@ func (pa *PostgresActor) GetActors() ([]ActorWithFilms, error) {
 			&actor.Name,
 			&actor.Sex,
 			&actor.Birthday,
+			&actor.DeathDate,
+			&actor.BirthPlace,
+			&actor.Country,
+			&actor.Biography,
+			pq.Array(&actor.Aliases),
 		)
 
 		if err != nil {
@@ -199,10 +344,13 @@ func (pa *PostgresActor) GetActors() ([]ActorWithFilms, error) {
 			return nil, errors.Wrap(err, "can't scan get actors query result")
 		}
 
-		actor.Films = make([]film.Film, 0)
+		if params.WithFilms {
+			actor.Films = make([]film.Film, 0)
+		}
 
 		actors = append(actors, actor)
-		actorsId[actor.ID] = i
+		actorsIndx[actor.ID] = i
+		actorsId = append(actorsId, actor.ID)
 		i++
 	}
 
@@ -211,8 +359,30 @@ func (pa *PostgresActor) GetActors() ([]ActorWithFilms, error) {
 		return nil, errors.Wrap(err, "can't end scan get actors query result")
 	}
 
+	if !params.WithFilms || len(actors) == 0 {
+		if err := tx.Commit(); err != nil {
+			return nil, errors.Wrap(err, "can't commit transaction for get actors")
+		}
+
+		return actors, nil
+	}
+
+	filmsQuery, filmsArgs := getActorsFilms, []any{actorsId}
+
+	// Фильмы с более строгим или неизвестным возрастным рейтингом скрываются
+	if params.MaxCertification != nil {
+		filmsQuery += certificationCondition
+		filmsArgs = append(filmsArgs, []string(getAllowedCertifications(params.MaxCertification)))
+	}
+
+	filmsQuery, filmsArgs, err = sqlx.In(filmsQuery, filmsArgs...)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, errors.Wrap(err, "can't prepare query to get actors films query")
+	}
+
 	// Получаем список фильмов для каждого автора
-	rows, err = tx.Queryx(getActorsFilms)
+	rows, err = tx.Queryx(tx.Rebind(filmsQuery), filmsArgs...)
 	if err != nil {
 		_ = tx.Rollback()
 		return nil, errors.Wrap(err, "can't execute get actors films query")
@@ -236,7 +406,7 @@ func (pa *PostgresActor) GetActors() ([]ActorWithFilms, error) {
 			return nil, errors.Wrap(err, "can't scan get actors films query result")
 		}
 
-		actors[actorsId[actorId]].Films = append(actors[actorsId[actorId]].Films, actorFilms)
+		actors[actorsIndx[actorId]].Films = append(actors[actorsIndx[actorId]].Films, actorFilms)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -250,3 +420,15 @@ func (pa *PostgresActor) GetActors() ([]ActorWithFilms, error) {
 
 	return actors, nil
 }
+
+// getAllowedCertifications
+// Возвращает возрастные рейтинги не строже максимального, nil — без ограничения
+func getAllowedCertifications(maxCertification *types.Certification) pq.StringArray {
+	if maxCertification == nil {
+		return nil
+	}
+
+	return slices.Map(types.AllowedCertifications(*maxCertification), func(c types.Certification) string {
+		return string(c)
+	})
+}
@@ -0,0 +1,26 @@
+package attempts
+
+import (
+	"time"
+)
+
+//go:generate mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=AttemptsRepository . Repository
+
+type Repository interface {
+	// Add
+	// Increments the number of failed attempts for the key and returns the new number.
+	// The counter is removed after window passes without new failures.
+	Add(key string, window time.Duration) (uint64, error)
+
+	// Lock
+	// Locks the key for lockTime.
+	Lock(key string, lockTime time.Duration) error
+
+	// GetLockTime
+	// Returns the remaining lock time of the key. Zero value means that the key is not locked.
+	GetLockTime(key string) (time.Duration, error)
+
+	// Reset
+	// Removes both the counter and the lock of the key.
+	Reset(key string) error
+}
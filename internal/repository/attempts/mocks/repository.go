@@ -0,0 +1,98 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: vk_film/internal/repository/attempts (interfaces: Repository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=AttemptsRepository . Repository
+//
+
+// Package mr is a generated GoMock package.
+package mr
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// AttemptsRepository is a mock of Repository interface.
+type AttemptsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *AttemptsRepositoryMockRecorder
+}
+
+// AttemptsRepositoryMockRecorder is the mock recorder for AttemptsRepository.
+type AttemptsRepositoryMockRecorder struct {
+	mock *AttemptsRepository
+}
+
+// NewAttemptsRepository creates a new mock instance.
+func NewAttemptsRepository(ctrl *gomock.Controller) *AttemptsRepository {
+	mock := &AttemptsRepository{ctrl: ctrl}
+	mock.recorder = &AttemptsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *AttemptsRepository) EXPECT() *AttemptsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *AttemptsRepository) Add(arg0 string, arg1 time.Duration) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", arg0, arg1)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *AttemptsRepositoryMockRecorder) Add(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*AttemptsRepository)(nil).Add), arg0, arg1)
+}
+
+// GetLockTime mocks base method.
+func (m *AttemptsRepository) GetLockTime(arg0 string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLockTime", arg0)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLockTime indicates an expected call of GetLockTime.
+func (mr *AttemptsRepositoryMockRecorder) GetLockTime(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLockTime", reflect.TypeOf((*AttemptsRepository)(nil).GetLockTime), arg0)
+}
+
+// Lock mocks base method.
+func (m *AttemptsRepository) Lock(arg0 string, arg1 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *AttemptsRepositoryMockRecorder) Lock(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*AttemptsRepository)(nil).Lock), arg0, arg1)
+}
+
+// Reset mocks base method.
+func (m *AttemptsRepository) Reset(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *AttemptsRepositoryMockRecorder) Reset(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*AttemptsRepository)(nil).Reset), arg0)
+}
@@ -0,0 +1,66 @@
+package attempts
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+const (
+	counterKeyPrefix = "login_attempts:"
+	lockKeyPrefix    = "login_lock:"
+)
+
+type RedisAttempts struct {
+	client *redis.Client
+	ctx    context.Context
+}
+
+func NewRedisAttempts(client *redis.Client) *RedisAttempts {
+	return &RedisAttempts{client: client, ctx: context.Background()}
+}
+
+func (ra *RedisAttempts) Add(key string, window time.Duration) (uint64, error) {
+	count, err := ra.client.Incr(ra.ctx, counterKeyPrefix+key).Uint64()
+	if err != nil {
+		return 0, errors.Wrapf(err,
+			"error when try increment attempts with key: %s", key)
+	}
+
+	if err := ra.client.Expire(ra.ctx, counterKeyPrefix+key, window).Err(); err != nil {
+		return 0, errors.Wrapf(err,
+			"error when try update expired time of attempts with key: %s", key)
+	}
+	return count, nil
+}
+
+func (ra *RedisAttempts) Lock(key string, lockTime time.Duration) error {
+	if err := ra.client.Set(ra.ctx, lockKeyPrefix+key, 1, lockTime).Err(); err != nil {
+		return errors.Wrapf(err,
+			"error when try lock key: %s", key)
+	}
+	return nil
+}
+
+func (ra *RedisAttempts) GetLockTime(key string) (time.Duration, error) {
+	lockTime, err := ra.client.PTTL(ra.ctx, lockKeyPrefix+key).Result()
+	if err != nil {
+		return 0, errors.Wrapf(err,
+			"error when try get lock time of key: %s", key)
+	}
+
+	// Отрицательное значение означает отсутствие блокировки
+	if lockTime < 0 {
+		return 0, nil
+	}
+	return lockTime, nil
+}
+
+func (ra *RedisAttempts) Reset(key string) error {
+	if err := ra.client.Del(ra.ctx, counterKeyPrefix+key, lockKeyPrefix+key).Err(); err != nil {
+		return errors.Wrapf(err,
+			"error when try reset attempts with key: %s", key)
+	}
+	return nil
+}
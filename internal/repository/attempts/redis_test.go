@@ -0,0 +1,150 @@
+package attempts
+
+import (
+	"github.com/go-redis/redismock/v9"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/pkg/errors"
+	"testing"
+	"time"
+)
+
+var testError = errors.New("test error")
+
+type RedisAttemptsSuite struct {
+	suite.Suite
+	attemptsRepository *RedisAttempts
+	mock               redismock.ClientMock
+}
+
+func (ras *RedisAttemptsSuite) BeforeEach(t provider.T) {
+	db, mock := redismock.NewClientMock()
+	ras.attemptsRepository = NewRedisAttempts(db)
+	ras.mock = mock
+}
+
+func (ras *RedisAttemptsSuite) AfterEach(t provider.T) {
+	t.Assert().NoError(ras.mock.ExpectationsWereMet())
+	t.Require().NoError(ras.attemptsRepository.client.Close())
+}
+
+func (ras *RedisAttemptsSuite) TestAddFunction(t provider.T) {
+	t.Title("Add function of Redis attempts repository")
+	t.NewStep("Init test data")
+	key := "login:admin"
+	window := time.Hour
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ras.mock.ExpectIncr(counterKeyPrefix + key).SetVal(3)
+		ras.mock.ExpectExpire(counterKeyPrefix+key, window).SetVal(true)
+
+		t.NewStep("Check result")
+		count, err := ras.attemptsRepository.Add(key, window)
+		t.Require().NoError(err)
+		t.Require().Equal(uint64(3), count)
+	})
+
+	t.WithNewStep("Redis error execute of redis incr", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ras.mock.ExpectIncr(counterKeyPrefix + key).SetErr(testError)
+
+		t.NewStep("Check result")
+		_, err := ras.attemptsRepository.Add(key, window)
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Redis error execute of redis expire", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ras.mock.ExpectIncr(counterKeyPrefix + key).SetVal(1)
+		ras.mock.ExpectExpire(counterKeyPrefix+key, window).SetErr(testError)
+
+		t.NewStep("Check result")
+		_, err := ras.attemptsRepository.Add(key, window)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (ras *RedisAttemptsSuite) TestLockFunction(t provider.T) {
+	t.Title("Lock function of Redis attempts repository")
+	t.NewStep("Init test data")
+	key := "login:admin"
+	lockTime := time.Minute
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ras.mock.ExpectSet(lockKeyPrefix+key, 1, lockTime).SetVal("OK")
+
+		t.NewStep("Check result")
+		t.Require().NoError(ras.attemptsRepository.Lock(key, lockTime))
+	})
+
+	t.WithNewStep("Redis error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ras.mock.ExpectSet(lockKeyPrefix+key, 1, lockTime).SetErr(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(ras.attemptsRepository.Lock(key, lockTime), testError)
+	})
+}
+
+func (ras *RedisAttemptsSuite) TestGetLockTimeFunction(t provider.T) {
+	t.Title("GetLockTime function of Redis attempts repository")
+	t.NewStep("Init test data")
+	key := "ip:127.0.0.1"
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ras.mock.ExpectPTTL(lockKeyPrefix + key).SetVal(30 * time.Second)
+
+		t.NewStep("Check result")
+		lockTime, err := ras.attemptsRepository.GetLockTime(key)
+		t.Require().NoError(err)
+		t.Require().Equal(30*time.Second, lockTime)
+	})
+
+	t.WithNewStep("Correct execute without lock", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ras.mock.ExpectPTTL(lockKeyPrefix + key).SetVal(-2)
+
+		t.NewStep("Check result")
+		lockTime, err := ras.attemptsRepository.GetLockTime(key)
+		t.Require().NoError(err)
+		t.Require().Zero(lockTime)
+	})
+
+	t.WithNewStep("Redis error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ras.mock.ExpectPTTL(lockKeyPrefix + key).SetErr(testError)
+
+		t.NewStep("Check result")
+		_, err := ras.attemptsRepository.GetLockTime(key)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (ras *RedisAttemptsSuite) TestResetFunction(t provider.T) {
+	t.Title("Reset function of Redis attempts repository")
+	t.NewStep("Init test data")
+	key := "login:admin"
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ras.mock.ExpectDel(counterKeyPrefix+key, lockKeyPrefix+key).SetVal(2)
+
+		t.NewStep("Check result")
+		t.Require().NoError(ras.attemptsRepository.Reset(key))
+	})
+
+	t.WithNewStep("Redis error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ras.mock.ExpectDel(counterKeyPrefix+key, lockKeyPrefix+key).SetErr(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(ras.attemptsRepository.Reset(key), testError)
+	})
+}
+
+func TestRunRedisAttemptsSuite(t *testing.T) {
+	suite.RunSuite(t, new(RedisAttemptsSuite))
+}
@@ -0,0 +1,164 @@
+package event
+
+import (
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/pkg/errors"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+var testError = errors.New("test error")
+
+type EventRepositorySuite struct {
+	suite.Suite
+	eventRepository *PostgresEvent
+	mock            sqlxmock.Sqlmock
+}
+
+func (ers *EventRepositorySuite) BeforeEach(t provider.T) {
+	db, mock, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	t.Require().NoError(err)
+	ers.eventRepository = NewPostgresEvent(db)
+	ers.mock = mock
+}
+
+func (ers *EventRepositorySuite) AfterEach(t provider.T) {
+	t.Require().NoError(ers.mock.ExpectationsWereMet())
+}
+
+func (ers *EventRepositorySuite) TestAddEventFunction(t provider.T) {
+	t.Title("AddEvent function of Event repository")
+	t.NewStep("Init test data")
+	event := &Event{
+		UserID:    1,
+		Type:      LoginFailed,
+		IP:        "127.0.0.1",
+		UserAgent: "curl/8.0",
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ers.mock.ExpectExec(addEvent).
+			WithArgs(event.UserID, event.Type, event.IP, event.UserAgent).
+			WillReturnResult(sqlxmock.NewResult(1, 1))
+
+		t.NewStep("Check result")
+		t.Require().NoError(ers.eventRepository.AddEvent(event))
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ers.mock.ExpectExec(addEvent).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(ers.eventRepository.AddEvent(event), testError)
+	})
+}
+
+func (ers *EventRepositorySuite) TestGetEventsFunction(t provider.T) {
+	t.Title("GetEvents function of Event repository")
+	t.NewStep("Init test data")
+	createdAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "user_id", "login", "type", "ip", "user_agent", "created_at"}
+	expected := []Event{
+		{ID: 2, UserID: 1, Login: "login", Type: Logout, IP: "127.0.0.1", UserAgent: "curl/8.0", CreatedAt: createdAt},
+		{ID: 1, Login: "deleted", Type: LoginSucceeded, IP: "127.0.0.1", UserAgent: "curl/8.0", CreatedAt: createdAt},
+	}
+
+	t.WithNewStep("Correct execute without filter", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		query, _, err := buildGetEventsQuery(Filter{})
+		t.Require().NoError(err)
+		ers.mock.ExpectQuery(query).
+			WillReturnRows(sqlxmock.NewRows(columns))
+
+		t.NewStep("Check result")
+		events, err := ers.eventRepository.GetEvents(Filter{})
+		t.Require().NoError(err)
+		t.Require().Empty(events)
+	})
+
+	t.WithNewStep("Correct execute with filter", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		filter := Filter{
+			UserID: 1,
+			Types:  []Type{LoginSucceeded, Logout},
+			IP:     "127.0.0.1",
+			From:   &from,
+			Limit:  10,
+			Offset: 5,
+		}
+		query, _, err := buildGetEventsQuery(filter)
+		t.Require().NoError(err)
+		ers.mock.ExpectQuery(ers.eventRepository.db.Rebind(query)).
+			WithArgs(filter.UserID, "login_succeeded", "logout", filter.IP, from, filter.Limit, filter.Offset).
+			WillReturnRows(
+				sqlxmock.NewRows(columns).
+					AddRow(2, 1, "login", "logout", "127.0.0.1", "curl/8.0", createdAt).
+					AddRow(1, 0, "deleted", "login_succeeded", "127.0.0.1", "curl/8.0", createdAt),
+			)
+
+		t.NewStep("Check result")
+		events, err := ers.eventRepository.GetEvents(filter)
+		t.Require().NoError(err)
+		t.Require().Equal(expected, events)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		query, _, err := buildGetEventsQuery(Filter{})
+		t.Require().NoError(err)
+		ers.mock.ExpectQuery(query).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err = ers.eventRepository.GetEvents(Filter{})
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Scan error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		query, _, err := buildGetEventsQuery(Filter{})
+		t.Require().NoError(err)
+		ers.mock.ExpectQuery(query).
+			WillReturnRows(sqlxmock.NewRows(columns).AddRow("first", 1, "login", "logout", "", "", createdAt)).
+			RowsWillBeClosed()
+
+		t.NewStep("Check result")
+		_, err = ers.eventRepository.GetEvents(Filter{})
+		t.Require().Error(err)
+	})
+}
+
+func (ers *EventRepositorySuite) TestDeleteEventsBeforeFunction(t provider.T) {
+	t.Title("DeleteEventsBefore function of Event repository")
+	t.NewStep("Init test data")
+	before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ers.mock.ExpectExec(deleteEventsBefore).
+			WithArgs(before).
+			WillReturnResult(sqlxmock.NewResult(0, 3))
+
+		t.NewStep("Check result")
+		n, err := ers.eventRepository.DeleteEventsBefore(before)
+		t.Require().NoError(err)
+		t.Require().Equal(int64(3), n)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		ers.mock.ExpectExec(deleteEventsBefore).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := ers.eventRepository.DeleteEventsBefore(before)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func TestRunEventRepositorySuite(t *testing.T) {
+	suite.RunSuite(t, new(EventRepositorySuite))
+}
@@ -0,0 +1,36 @@
+package event
+
+import (
+	"time"
+	"vk_film/internal/pkg/types"
+)
+
+//go:generate mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=EventRepository . Repository
+
+// Filter
+// Zero UserID, empty Types and empty IP mean that events are not filtered by them.
+// Nil From and To mean that the bound is not set, From is inclusive and To is exclusive.
+// Zero Limit means that the number of events is not limited.
+type Filter struct {
+	UserID types.Id
+	Types  []Type
+	IP     string
+	From   *time.Time
+	To     *time.Time
+	Limit  uint64
+	Offset uint64
+}
+
+type Repository interface {
+	// AddEvent
+	// Saves the event. Creation time is set to the current time.
+	AddEvent(event *Event) error
+
+	// GetEvents
+	// Returns events matched by the filter ordered from newest to oldest.
+	GetEvents(filter Filter) ([]Event, error)
+
+	// DeleteEventsBefore
+	// Deletes events created before the time and returns their number.
+	DeleteEventsBefore(before time.Time) (int64, error)
+}
@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: vk_film/internal/repository/event (interfaces: Repository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=EventRepository . Repository
+//
+
+// Package mr is a generated GoMock package.
+package mr
+
+import (
+	reflect "reflect"
+	time "time"
+	event "vk_film/internal/repository/event"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// EventRepository is a mock of Repository interface.
+type EventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *EventRepositoryMockRecorder
+}
+
+// EventRepositoryMockRecorder is the mock recorder for EventRepository.
+type EventRepositoryMockRecorder struct {
+	mock *EventRepository
+}
+
+// NewEventRepository creates a new mock instance.
+func NewEventRepository(ctrl *gomock.Controller) *EventRepository {
+	mock := &EventRepository{ctrl: ctrl}
+	mock.recorder = &EventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *EventRepository) EXPECT() *EventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddEvent mocks base method.
+func (m *EventRepository) AddEvent(arg0 *event.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddEvent", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddEvent indicates an expected call of AddEvent.
+func (mr *EventRepositoryMockRecorder) AddEvent(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEvent", reflect.TypeOf((*EventRepository)(nil).AddEvent), arg0)
+}
+
+// DeleteEventsBefore mocks base method.
+func (m *EventRepository) DeleteEventsBefore(arg0 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEventsBefore", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteEventsBefore indicates an expected call of DeleteEventsBefore.
+func (mr *EventRepositoryMockRecorder) DeleteEventsBefore(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEventsBefore", reflect.TypeOf((*EventRepository)(nil).DeleteEventsBefore), arg0)
+}
+
+// GetEvents mocks base method.
+func (m *EventRepository) GetEvents(arg0 event.Filter) ([]event.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvents", arg0)
+	ret0, _ := ret[0].([]event.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvents indicates an expected call of GetEvents.
+func (mr *EventRepositoryMockRecorder) GetEvents(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*EventRepository)(nil).GetEvents), arg0)
+}
@@ -0,0 +1,39 @@
+package event
+
+import (
+	"slices"
+	"time"
+	"vk_film/internal/pkg/types"
+)
+
+type Type string
+
+const (
+	LoginSucceeded  Type = "login_succeeded"
+	LoginFailed     Type = "login_failed"
+	Logout          Type = "logout"
+	PasswordChanged Type = "password_changed"
+	PasswordReset   Type = "password_reset"
+	Lockout         Type = "lockout"
+)
+
+// Types
+// All known types of security events
+var Types = []Type{LoginSucceeded, LoginFailed, Logout, PasswordChanged, PasswordReset, Lockout}
+
+func (t Type) IsValid() bool {
+	return slices.Contains(Types, t)
+}
+
+// Event
+// Security event of the user with the client metadata of the request that caused it.
+// Login is saved with the event, so events of deleted users keep it while their UserID becomes zero.
+type Event struct {
+	ID        types.Id
+	UserID    types.Id
+	Login     string
+	Type      Type
+	IP        string
+	UserAgent string
+	CreatedAt time.Time
+}
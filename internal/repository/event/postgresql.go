@@ -0,0 +1,161 @@
+package event
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"time"
+	"vk_film/pkg/slices"
+)
+
+const (
+	addEvent = `
+		INSERT INTO security_events (user_id, login, type, ip, user_agent)
+		SELECT id, login, $2, $3, $4 FROM users WHERE id = $1
+	`
+
+	getEvents = `
+		SELECT id, coalesce(user_id, 0), login, type, ip, user_agent, created_at FROM security_events
+		WHERE true%s
+		ORDER BY created_at DESC, id DESC%s
+	`
+
+	deleteEventsBefore = `
+		DELETE FROM security_events WHERE created_at < $1
+	`
+
+	userCondition  = "user_id = ?"
+	typesCondition = "type IN (?)"
+	ipCondition    = "ip = ?"
+	fromCondition  = "created_at >= ?"
+	toCondition    = "created_at < ?"
+	limitClause    = " LIMIT ?"
+	offsetClause   = " OFFSET ?"
+)
+
+type PostgresEvent struct {
+	db *sqlx.DB
+}
+
+func NewPostgresEvent(db *sqlx.DB) *PostgresEvent {
+	return &PostgresEvent{
+		db: db,
+	}
+}
+
+var _ = Repository(&PostgresEvent{})
+
+func (pe *PostgresEvent) AddEvent(event *Event) error {
+	if _, err := pe.db.Exec(addEvent, event.UserID, event.Type, event.IP, event.UserAgent); err != nil {
+		return errors.Wrapf(err, "can't add event %s of user %d", event.Type, event.UserID)
+	}
+
+	return nil
+}
+
+// buildGetEventsQuery
+// Собирает запрос получения событий только из заданных условий фильтра
+func buildGetEventsQuery(filter Filter) (string, []any, error) {
+	conditions := ""
+	args := make([]any, 0)
+
+	if filter.UserID != 0 {
+		conditions += " AND " + userCondition
+		args = append(args, filter.UserID)
+	}
+
+	if len(filter.Types) != 0 {
+		conditions += " AND " + typesCondition
+		args = append(args, slices.Map(filter.Types, func(t Type) string {
+			return string(t)
+		}))
+	}
+
+	if filter.IP != "" {
+		conditions += " AND " + ipCondition
+		args = append(args, filter.IP)
+	}
+
+	if filter.From != nil {
+		conditions += " AND " + fromCondition
+		args = append(args, *filter.From)
+	}
+
+	if filter.To != nil {
+		conditions += " AND " + toCondition
+		args = append(args, *filter.To)
+	}
+
+	pagination := ""
+
+	if filter.Limit != 0 {
+		pagination += limitClause
+		args = append(args, filter.Limit)
+	}
+
+	if filter.Offset != 0 {
+		pagination += offsetClause
+		args = append(args, filter.Offset)
+	}
+
+	preparedQuery := fmt.Sprintf(getEvents, conditions, pagination)
+
+	if len(filter.Types) == 0 {
+		return preparedQuery, args, nil
+	}
+
+	return sqlx.In(preparedQuery, args...)
+}
+
+func (pe *PostgresEvent) GetEvents(filter Filter) ([]Event, error) {
+	preparedQuery, queryArgs, err := buildGetEventsQuery(filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't prepare get events query")
+	}
+
+	rows, err := pe.db.Queryx(pe.db.Rebind(preparedQuery), queryArgs...)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't execute get events query")
+	}
+	defer rows.Close()
+
+	events := make([]Event, 0)
+
+	for rows.Next() {
+		var event Event
+
+		if err := rows.Scan(
+			&event.ID,
+			&event.UserID,
+			&event.Login,
+			&event.Type,
+			&event.IP,
+			&event.UserAgent,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, "can't scan get events query result")
+		}
+
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "can't end scan get events query result")
+	}
+
+	return events, nil
+}
+
+func (pe *PostgresEvent) DeleteEventsBefore(before time.Time) (int64, error) {
+	res, err := pe.db.Exec(deleteEventsBefore, before)
+	if err != nil {
+		return 0, errors.Wrap(err, "can't delete old events")
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "can't get number of deleted old events")
+	}
+
+	return n, nil
+}
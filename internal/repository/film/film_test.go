@@ -18,6 +18,30 @@ import (
 
 var testError = errors.New("test error")
 
+func newTestFilm() *Film {
+	runtime := uint16(155)
+	language := "en"
+	certification := types.MPAAPG13
+
+	return &Film{
+		ID:               1,
+		Name:             "Dune",
+		Description:      "good film",
+		DataPublish:      time.MustParse("12.03.2003"),
+		Rating:           10,
+		Runtime:          &runtime,
+		Countries:        []string{"USA", "Canada"},
+		OriginalLanguage: &language,
+		AgeCertification: &certification,
+	}
+}
+
+func filmWithId(film *Film, id types.Id) Film {
+	flm := *film
+	flm.ID = id
+	return flm
+}
+
 type FilmRepositorySuite struct {
 	suite.Suite
 	filmRepository *PostgresFilm
@@ -134,13 +158,7 @@ func (frs *FilmRepositorySuite) TestGetActorsFunction(t provider.T) {
 func (frs *FilmRepositorySuite) TestCreateFunction(t provider.T) {
 	t.Title("CreateFilm function of Film repository")
 	t.NewStep("Init test data")
-	film := &Film{
-		ID:          1,
-		Name:        "Dune",
-		Description: "good film",
-		DataPublish: time.MustParse("12.03.2003"),
-		Rating:      10,
-	}
+	film := newTestFilm()
 
 	actorsId := []types.Id{1, 2, 3}
 
@@ -153,6 +171,7 @@ func (frs *FilmRepositorySuite) TestCreateFunction(t provider.T) {
 
 	filmColumns := []string{
 		"id", "name", "description", "publish_date", "rating",
+		"runtime", "countries", "original_language", "age_certification",
 	}
 
 	actorColumns := []string{
@@ -170,9 +189,11 @@ func (frs *FilmRepositorySuite) TestCreateFunction(t provider.T) {
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
 		frs.mock.ExpectQuery(createQuery).
-			WithArgs(film.Name, film.Description, film.DataPublish.Time, film.Rating).
+			WithArgs(film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				getNull(film.Runtime), pq.Array(film.Countries), getNull(film.OriginalLanguage), getNull(film.AgeCertification)).
 			WillReturnRows(sqlxmock.NewRows(filmColumns).
-				AddRow(film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating),
+				AddRow(film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+					*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification)),
 			)
 		frs.mock.ExpectExec(addActors).WithArgs(film.ID, pq.Array(actorsId)).WillReturnResult(sqlxmock.NewResult(1, 1))
 		frs.mock.ExpectQuery(getFilmActors).WithArgs(film.ID).WillReturnRows(actorsRows())
@@ -191,9 +212,11 @@ func (frs *FilmRepositorySuite) TestCreateFunction(t provider.T) {
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
 		frs.mock.ExpectQuery(createQuery).
-			WithArgs(film.Name, film.Description, film.DataPublish.Time, film.Rating).
+			WithArgs(film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				getNull(film.Runtime), pq.Array(film.Countries), getNull(film.OriginalLanguage), getNull(film.AgeCertification)).
 			WillReturnRows(sqlxmock.NewRows(filmColumns).
-				AddRow(film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating),
+				AddRow(film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+					*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification)),
 			)
 		frs.mock.ExpectCommit()
 
@@ -210,9 +233,11 @@ func (frs *FilmRepositorySuite) TestCreateFunction(t provider.T) {
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
 		frs.mock.ExpectQuery(createQuery).
-			WithArgs(film.Name, film.Description, film.DataPublish.Time, film.Rating).
+			WithArgs(film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				getNull(film.Runtime), pq.Array(film.Countries), getNull(film.OriginalLanguage), getNull(film.AgeCertification)).
 			WillReturnRows(sqlxmock.NewRows(filmColumns).
-				AddRow(film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating),
+				AddRow(film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+					*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification)),
 			)
 		frs.mock.ExpectExec(addActors).WithArgs(film.ID, pq.Array(actorsId)).WillReturnError(&pq.Error{
 			Code:       actorIdConflictCode,
@@ -238,9 +263,11 @@ func (frs *FilmRepositorySuite) TestCreateFunction(t provider.T) {
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
 		frs.mock.ExpectQuery(createQuery).
-			WithArgs(film.Name, film.Description, film.DataPublish.Time, film.Rating).
+			WithArgs(film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				getNull(film.Runtime), pq.Array(film.Countries), getNull(film.OriginalLanguage), getNull(film.AgeCertification)).
 			WillReturnRows(sqlxmock.NewRows(filmColumns).
-				AddRow(film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating),
+				AddRow(film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+					*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification)),
 			).WillReturnError(testError)
 		frs.mock.ExpectRollback()
 
@@ -253,9 +280,11 @@ func (frs *FilmRepositorySuite) TestCreateFunction(t provider.T) {
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
 		frs.mock.ExpectQuery(createQuery).
-			WithArgs(film.Name, film.Description, film.DataPublish.Time, film.Rating).
+			WithArgs(film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				getNull(film.Runtime), pq.Array(film.Countries), getNull(film.OriginalLanguage), getNull(film.AgeCertification)).
 			WillReturnRows(sqlxmock.NewRows(filmColumns).
-				AddRow(film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating),
+				AddRow(film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+					*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification)),
 			)
 		frs.mock.ExpectExec(addActors).WillReturnError(testError)
 		frs.mock.ExpectRollback()
@@ -269,9 +298,11 @@ func (frs *FilmRepositorySuite) TestCreateFunction(t provider.T) {
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
 		frs.mock.ExpectQuery(createQuery).
-			WithArgs(film.Name, film.Description, film.DataPublish.Time, film.Rating).
+			WithArgs(film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				getNull(film.Runtime), pq.Array(film.Countries), getNull(film.OriginalLanguage), getNull(film.AgeCertification)).
 			WillReturnRows(sqlxmock.NewRows(filmColumns).
-				AddRow(film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating),
+				AddRow(film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+					*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification)),
 			)
 		frs.mock.ExpectExec(addActors).WithArgs(film.ID, pq.Array(actorsId)).WillReturnResult(sqlxmock.NewResult(1, 1))
 		frs.mock.ExpectQuery(getFilmActors).WithArgs(film.ID).WillReturnError(testError)
@@ -286,9 +317,11 @@ func (frs *FilmRepositorySuite) TestCreateFunction(t provider.T) {
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
 		frs.mock.ExpectQuery(createQuery).
-			WithArgs(film.Name, film.Description, film.DataPublish.Time, film.Rating).
+			WithArgs(film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				getNull(film.Runtime), pq.Array(film.Countries), getNull(film.OriginalLanguage), getNull(film.AgeCertification)).
 			WillReturnRows(sqlxmock.NewRows(filmColumns).
-				AddRow(film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating),
+				AddRow(film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+					*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification)),
 			)
 		frs.mock.ExpectExec(addActors).WithArgs(film.ID, pq.Array(actorsId)).WillReturnResult(sqlxmock.NewResult(1, 1))
 		frs.mock.ExpectQuery(getFilmActors).WithArgs(film.ID).WillReturnRows(actorsRows())
@@ -303,13 +336,7 @@ func (frs *FilmRepositorySuite) TestCreateFunction(t provider.T) {
 func (frs *FilmRepositorySuite) TestDeleteFunction(t provider.T) {
 	t.Title("DeleteFilm function of Film repository")
 	t.NewStep("Init test data")
-	film := &Film{
-		ID:          1,
-		Name:        "Dune",
-		Description: "good film",
-		DataPublish: time.MustParse("12.03.2003"),
-		Rating:      10,
-	}
+	film := newTestFilm()
 
 	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
@@ -359,13 +386,7 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 	t.Title("GetFilms function of Film repository")
 	t.NewStep("Init test data")
 
-	film := &Film{
-		ID:          1,
-		Name:        "Dune",
-		Description: "good film",
-		DataPublish: time.MustParse("12.03.2003"),
-		Rating:      10,
-	}
+	film := newTestFilm()
 
 	actor := &Actor{
 		ID:       1,
@@ -376,6 +397,7 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 
 	filmColumns := []string{
 		"id", "name", "description", "publish_date", "rating",
+		"runtime", "countries", "original_language", "age_certification",
 	}
 
 	actorColumns := []string{
@@ -393,9 +415,12 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 
 	filmsRows := func() *sqlxmock.Rows {
 		return sqlxmock.NewRows(filmColumns).
-			AddRow(film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating).
-			AddRow(film.ID+1, film.Name, film.Description, film.DataPublish.Time, film.Rating).
-			AddRow(film.ID+2, film.Name, film.Description, film.DataPublish.Time, film.Rating)
+			AddRow(film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification)).
+			AddRow(film.ID+1, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification)).
+			AddRow(film.ID+2, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification))
 	}
 
 	params := Params{
@@ -412,7 +437,7 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 		query = frs.filmRepository.db.Rebind(query)
 		driverArgs := slices.Map(args, func(i interface{}) driver.Value { return i })
 
-		getFilms := fmt.Sprintf(getFilmsSearchFilm, params.OrderField, params.Order)
+		getFilms := frs.filmRepository.db.Rebind(fmt.Sprintf(getFilmsSearchFilm, "", params.OrderField, params.Order))
 
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
@@ -425,18 +450,15 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 		t.Require().NoError(err)
 		t.Require().EqualValues([]FilmWithActors{
 			{
-				Film: Film{ID: film.ID, Name: film.Name,
-					Description: film.Description, DataPublish: film.DataPublish, Rating: film.Rating},
+				Film:   *film,
 				Actors: []Actor{*actor, *actor, *actor},
 			},
 			{
-				Film: Film{ID: film.ID + 1, Name: film.Name,
-					Description: film.Description, DataPublish: film.DataPublish, Rating: film.Rating},
+				Film:   filmWithId(film, film.ID+1),
 				Actors: []Actor{},
 			},
 			{
-				Film: Film{ID: film.ID + 2, Name: film.Name,
-					Description: film.Description, DataPublish: film.DataPublish, Rating: film.Rating},
+				Film:   filmWithId(film, film.ID+2),
 				Actors: []Actor{*actor, *actor},
 			},
 		}, films)
@@ -449,7 +471,7 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 		query = frs.filmRepository.db.Rebind(query)
 		driverArgs := slices.Map(args, func(i interface{}) driver.Value { return i })
 
-		getFilms := fmt.Sprintf(getFilmsSearchFilm, types.NameField, types.ASC)
+		getFilms := frs.filmRepository.db.Rebind(fmt.Sprintf(getFilmsSearchFilm, "", types.NameField, types.ASC))
 
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
@@ -467,18 +489,15 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 		t.Require().NoError(err)
 		t.Require().EqualValues([]FilmWithActors{
 			{
-				Film: Film{ID: film.ID, Name: film.Name,
-					Description: film.Description, DataPublish: film.DataPublish, Rating: film.Rating},
+				Film:   *film,
 				Actors: []Actor{*actor, *actor, *actor},
 			},
 			{
-				Film: Film{ID: film.ID + 1, Name: film.Name,
-					Description: film.Description, DataPublish: film.DataPublish, Rating: film.Rating},
+				Film:   filmWithId(film, film.ID+1),
 				Actors: []Actor{},
 			},
 			{
-				Film: Film{ID: film.ID + 2, Name: film.Name,
-					Description: film.Description, DataPublish: film.DataPublish, Rating: film.Rating},
+				Film:   filmWithId(film, film.ID+2),
 				Actors: []Actor{*actor, *actor},
 			},
 		}, films)
@@ -491,7 +510,7 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 		query = frs.filmRepository.db.Rebind(query)
 		driverArgs := slices.Map(args, func(i interface{}) driver.Value { return i })
 
-		getFilms := fmt.Sprintf(getFilmsSearchFilm, types.DataPublishField, types.ASC)
+		getFilms := frs.filmRepository.db.Rebind(fmt.Sprintf(getFilmsSearchFilm, "", types.DataPublishField, types.ASC))
 
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
@@ -509,18 +528,15 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 		t.Require().NoError(err)
 		t.Require().EqualValues([]FilmWithActors{
 			{
-				Film: Film{ID: film.ID, Name: film.Name,
-					Description: film.Description, DataPublish: film.DataPublish, Rating: film.Rating},
+				Film:   *film,
 				Actors: []Actor{*actor, *actor, *actor},
 			},
 			{
-				Film: Film{ID: film.ID + 1, Name: film.Name,
-					Description: film.Description, DataPublish: film.DataPublish, Rating: film.Rating},
+				Film:   filmWithId(film, film.ID+1),
 				Actors: []Actor{},
 			},
 			{
-				Film: Film{ID: film.ID + 2, Name: film.Name,
-					Description: film.Description, DataPublish: film.DataPublish, Rating: film.Rating},
+				Film:   filmWithId(film, film.ID+2),
 				Actors: []Actor{*actor, *actor},
 			},
 		}, films)
@@ -533,7 +549,7 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 		query = frs.filmRepository.db.Rebind(query)
 		driverArgs := slices.Map(args, func(i interface{}) driver.Value { return i })
 
-		getFilms := fmt.Sprintf(getFilmsSearchActor, params.OrderField, params.Order)
+		getFilms := frs.filmRepository.db.Rebind(fmt.Sprintf(getFilmsSearchActor, "", params.OrderField, params.Order))
 
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
@@ -551,18 +567,15 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 		t.Require().NoError(err)
 		t.Require().EqualValues([]FilmWithActors{
 			{
-				Film: Film{ID: film.ID, Name: film.Name,
-					Description: film.Description, DataPublish: film.DataPublish, Rating: film.Rating},
+				Film:   *film,
 				Actors: []Actor{*actor, *actor, *actor},
 			},
 			{
-				Film: Film{ID: film.ID + 1, Name: film.Name,
-					Description: film.Description, DataPublish: film.DataPublish, Rating: film.Rating},
+				Film:   filmWithId(film, film.ID+1),
 				Actors: []Actor{},
 			},
 			{
-				Film: Film{ID: film.ID + 2, Name: film.Name,
-					Description: film.Description, DataPublish: film.DataPublish, Rating: film.Rating},
+				Film:   filmWithId(film, film.ID+2),
 				Actors: []Actor{*actor, *actor},
 			},
 		}, films)
@@ -575,7 +588,7 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 		query = frs.filmRepository.db.Rebind(query)
 		driverArgs := slices.Map(args, func(i interface{}) driver.Value { return i })
 
-		getFilms := fmt.Sprintf(getFilmsSearchActor, types.NameField, types.ASC)
+		getFilms := frs.filmRepository.db.Rebind(fmt.Sprintf(getFilmsSearchActor, "", types.NameField, types.ASC))
 
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
@@ -593,18 +606,15 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 		t.Require().NoError(err)
 		t.Require().EqualValues([]FilmWithActors{
 			{
-				Film: Film{ID: film.ID, Name: film.Name,
-					Description: film.Description, DataPublish: film.DataPublish, Rating: film.Rating},
+				Film:   *film,
 				Actors: []Actor{*actor, *actor, *actor},
 			},
 			{
-				Film: Film{ID: film.ID + 1, Name: film.Name,
-					Description: film.Description, DataPublish: film.DataPublish, Rating: film.Rating},
+				Film:   filmWithId(film, film.ID+1),
 				Actors: []Actor{},
 			},
 			{
-				Film: Film{ID: film.ID + 2, Name: film.Name,
-					Description: film.Description, DataPublish: film.DataPublish, Rating: film.Rating},
+				Film:   filmWithId(film, film.ID+2),
 				Actors: []Actor{*actor, *actor},
 			},
 		}, films)
@@ -617,7 +627,7 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 		query = frs.filmRepository.db.Rebind(query)
 		driverArgs := slices.Map(args, func(i interface{}) driver.Value { return i })
 
-		getFilms := fmt.Sprintf(getFilmsSearchActor, types.DataPublishField, types.ASC)
+		getFilms := frs.filmRepository.db.Rebind(fmt.Sprintf(getFilmsSearchActor, "", types.DataPublishField, types.ASC))
 
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
@@ -635,26 +645,98 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 		t.Require().NoError(err)
 		t.Require().EqualValues([]FilmWithActors{
 			{
-				Film: Film{ID: film.ID, Name: film.Name,
-					Description: film.Description, DataPublish: film.DataPublish, Rating: film.Rating},
+				Film:   *film,
 				Actors: []Actor{*actor, *actor, *actor},
 			},
 			{
-				Film: Film{ID: film.ID + 1, Name: film.Name,
-					Description: film.Description, DataPublish: film.DataPublish, Rating: film.Rating},
+				Film:   filmWithId(film, film.ID+1),
 				Actors: []Actor{},
 			},
 			{
-				Film: Film{ID: film.ID + 2, Name: film.Name,
-					Description: film.Description, DataPublish: film.DataPublish, Rating: film.Rating},
+				Film:   filmWithId(film, film.ID+2),
 				Actors: []Actor{*actor, *actor},
 			},
 		}, films)
 	})
 
+	t.WithNewStep("Correct all filters on actor execute", func(t provider.StepCtx) {
+		t.NewStep("Init test data")
+		runtimeFrom, runtimeTo := uint16(90), uint16(180)
+		country, language := "USA", "en"
+		filterParams := Params{
+			Order:          types.ASC,
+			OrderField:     types.NameField,
+			SearchField:    types.ActorField,
+			SearchString:   "a",
+			RuntimeFrom:    &runtimeFrom,
+			RuntimeTo:      &runtimeTo,
+			Country:        &country,
+			Language:       &language,
+			Certifications: []types.Certification{types.AGE12, types.MPAAPG13},
+		}
+
+		t.NewStep("Init queries")
+		query, args, err := sqlx.In(getFilmsActors, []types.Id{1, 2, 3})
+		t.Require().NoError(err)
+		query = frs.filmRepository.db.Rebind(query)
+		driverArgs := slices.Map(args, func(i interface{}) driver.Value { return i })
+
+		getFilms := frs.filmRepository.db.Rebind(fmt.Sprintf(getFilmsSearchActor,
+			" AND "+runtimeFromCondition+" AND "+runtimeToCondition+" AND "+countryCondition+
+				" AND "+languageCondition+" AND films.age_certification IN (?, ?)",
+			types.NameField, types.ASC))
+
+		t.NewStep("Init mock")
+		frs.mock.ExpectBegin()
+		frs.mock.ExpectQuery(getFilms).
+			WithArgs("a", int64(runtimeFrom), int64(runtimeTo), country, language,
+				string(types.AGE12), string(types.MPAAPG13)).
+			WillReturnRows(filmsRows())
+		frs.mock.ExpectQuery(query).WithArgs(driverArgs...).WillReturnRows(actorsRows())
+		frs.mock.ExpectCommit()
+
+		t.NewStep("Check result")
+		films, err := frs.filmRepository.GetFilms(filterParams)
+		t.Require().NoError(err)
+		t.Require().Len(films, 3)
+	})
+
+	t.WithNewStep("Correct max certification on film execute", func(t provider.StepCtx) {
+		t.NewStep("Init test data")
+		maxCertification := types.AGE6
+		filterParams := Params{
+			Order:            types.DESC,
+			OrderField:       types.RatingField,
+			SearchField:      types.FilmField,
+			MaxCertification: &maxCertification,
+		}
+
+		t.NewStep("Init queries")
+		query, args, err := sqlx.In(getFilmsActors, []types.Id{1, 2, 3})
+		t.Require().NoError(err)
+		query = frs.filmRepository.db.Rebind(query)
+		driverArgs := slices.Map(args, func(i interface{}) driver.Value { return i })
+
+		getFilms := frs.filmRepository.db.Rebind(fmt.Sprintf(getFilmsSearchFilm,
+			" AND films.age_certification IN (?, ?, ?, ?)", types.RatingField, types.DESC))
+
+		t.NewStep("Init mock")
+		frs.mock.ExpectBegin()
+		frs.mock.ExpectQuery(getFilms).
+			WithArgs("", string(types.AGE0), string(types.AGE6), string(types.MPAAG), string(types.MPAAPG)).
+			WillReturnRows(filmsRows())
+		frs.mock.ExpectQuery(query).WithArgs(driverArgs...).WillReturnRows(actorsRows())
+		frs.mock.ExpectCommit()
+
+		t.NewStep("Check result")
+		films, err := frs.filmRepository.GetFilms(filterParams)
+		t.Require().NoError(err)
+		t.Require().Len(films, 3)
+	})
+
 	t.WithNewStep("Correct empty list of films", func(t provider.StepCtx) {
 		t.NewStep("Init queries")
-		getFilms := fmt.Sprintf(getFilmsSearchFilm, params.OrderField, params.Order)
+		getFilms := frs.filmRepository.db.Rebind(fmt.Sprintf(getFilmsSearchFilm, "", params.OrderField, params.Order))
 
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
@@ -669,7 +751,7 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 
 	t.WithNewStep("Postgres error on commit with empty list of films", func(t provider.StepCtx) {
 		t.NewStep("Init queries")
-		getFilms := fmt.Sprintf(getFilmsSearchFilm, params.OrderField, params.Order)
+		getFilms := frs.filmRepository.db.Rebind(fmt.Sprintf(getFilmsSearchFilm, "", params.OrderField, params.Order))
 
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
@@ -692,7 +774,7 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 
 	t.WithNewStep("Postgres error on getFilms query", func(t provider.StepCtx) {
 		t.NewStep("Init queries")
-		getFilms := fmt.Sprintf(getFilmsSearchFilm, params.OrderField, params.Order)
+		getFilms := frs.filmRepository.db.Rebind(fmt.Sprintf(getFilmsSearchFilm, "", params.OrderField, params.Order))
 
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
@@ -706,7 +788,7 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 
 	t.WithNewStep("Rows error on getFilms query", func(t provider.StepCtx) {
 		t.NewStep("Init queries")
-		getFilms := fmt.Sprintf(getFilmsSearchFilm, params.OrderField, params.Order)
+		getFilms := frs.filmRepository.db.Rebind(fmt.Sprintf(getFilmsSearchFilm, "", params.OrderField, params.Order))
 
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
@@ -720,11 +802,11 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 
 	t.WithNewStep("Incorrect field in row of getFilms query", func(t provider.StepCtx) {
 		t.NewStep("Init queries")
-		getFilms := fmt.Sprintf(getFilmsSearchFilm, params.OrderField, params.Order)
+		getFilms := frs.filmRepository.db.Rebind(fmt.Sprintf(getFilmsSearchFilm, "", params.OrderField, params.Order))
 
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
-		frs.mock.ExpectQuery(getFilms).WithArgs("").WillReturnRows(filmsRows().AddRow(1, 1, 1, 1, 1))
+		frs.mock.ExpectQuery(getFilms).WithArgs("").WillReturnRows(filmsRows().AddRow(1, 1, 1, 1, 1, 1, 1, 1, 1))
 		frs.mock.ExpectRollback()
 
 		t.NewStep("Check result")
@@ -734,7 +816,7 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 
 	t.WithNewStep("Rows close error on getFilms query", func(t provider.StepCtx) {
 		t.NewStep("Init queries")
-		getFilms := fmt.Sprintf(getFilmsSearchFilm, params.OrderField, params.Order)
+		getFilms := frs.filmRepository.db.Rebind(fmt.Sprintf(getFilmsSearchFilm, "", params.OrderField, params.Order))
 
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
@@ -753,7 +835,7 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 		query = frs.filmRepository.db.Rebind(query)
 		driverArgs := slices.Map(args, func(i interface{}) driver.Value { return i })
 
-		getFilms := fmt.Sprintf(getFilmsSearchFilm, params.OrderField, params.Order)
+		getFilms := frs.filmRepository.db.Rebind(fmt.Sprintf(getFilmsSearchFilm, "", params.OrderField, params.Order))
 
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
@@ -773,7 +855,7 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 		query = frs.filmRepository.db.Rebind(query)
 		driverArgs := slices.Map(args, func(i interface{}) driver.Value { return i })
 
-		getFilms := fmt.Sprintf(getFilmsSearchFilm, params.OrderField, params.Order)
+		getFilms := frs.filmRepository.db.Rebind(fmt.Sprintf(getFilmsSearchFilm, "", params.OrderField, params.Order))
 
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
@@ -793,7 +875,7 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 		query = frs.filmRepository.db.Rebind(query)
 		driverArgs := slices.Map(args, func(i interface{}) driver.Value { return i })
 
-		getFilms := fmt.Sprintf(getFilmsSearchFilm, params.OrderField, params.Order)
+		getFilms := frs.filmRepository.db.Rebind(fmt.Sprintf(getFilmsSearchFilm, "", params.OrderField, params.Order))
 
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
@@ -813,7 +895,7 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 		query = frs.filmRepository.db.Rebind(query)
 		driverArgs := slices.Map(args, func(i interface{}) driver.Value { return i })
 
-		getFilms := fmt.Sprintf(getFilmsSearchFilm, params.OrderField, params.Order)
+		getFilms := frs.filmRepository.db.Rebind(fmt.Sprintf(getFilmsSearchFilm, "", params.OrderField, params.Order))
 
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
@@ -833,7 +915,7 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 		query = frs.filmRepository.db.Rebind(query)
 		driverArgs := slices.Map(args, func(i interface{}) driver.Value { return i })
 
-		getFilms := fmt.Sprintf(getFilmsSearchFilm, params.OrderField, params.Order)
+		getFilms := frs.filmRepository.db.Rebind(fmt.Sprintf(getFilmsSearchFilm, "", params.OrderField, params.Order))
 
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
@@ -850,13 +932,7 @@ func (frs *FilmRepositorySuite) TestGetFunction(t provider.T) {
 func (frs *FilmRepositorySuite) TestUpdateFunction(t provider.T) {
 	t.Title("UpdateFilm function of Film repository")
 	t.NewStep("Init test data")
-	film := &Film{
-		ID:          1,
-		Name:        "Dune",
-		Description: "good film",
-		DataPublish: time.MustParse("12.03.2003"),
-		Rating:      10,
-	}
+	film := newTestFilm()
 
 	actorsId := []types.Id{1, 2, 3}
 
@@ -869,6 +945,7 @@ func (frs *FilmRepositorySuite) TestUpdateFunction(t provider.T) {
 
 	filmColumns := []string{
 		"id", "name", "description", "publish_date", "rating",
+		"runtime", "countries", "original_language", "age_certification",
 	}
 
 	actorColumns := []string{
@@ -891,9 +968,14 @@ func (frs *FilmRepositorySuite) TestUpdateFunction(t provider.T) {
 				getNull(&film.Description),
 				sql.NullTime{Valid: true, Time: film.DataPublish.Time},
 				sql.NullInt64{Valid: true, Int64: int64(film.Rating)},
+				sql.Null[uint16]{Valid: false},
+				nil,
+				sql.Null[string]{Valid: false},
+				sql.Null[types.Certification]{Valid: false},
 			).
 			WillReturnRows(sqlxmock.NewRows(filmColumns).AddRow(
 				film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification),
 			))
 		frs.mock.ExpectExec(deleteActors).WithArgs(film.ID).WillReturnResult(sqlxmock.NewResult(2, 2))
 		frs.mock.ExpectExec(addActors).WithArgs(film.ID, pq.Array(actorsId)).WillReturnResult(sqlxmock.NewResult(2, 2))
@@ -917,6 +999,42 @@ func (frs *FilmRepositorySuite) TestUpdateFunction(t provider.T) {
 		}, actors)
 	})
 
+	t.WithNewStep("Correct only metadata execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		frs.mock.ExpectBegin()
+		frs.mock.ExpectQuery(updateFilms).
+			WithArgs(film.ID,
+				getNull((*string)(nil)),
+				getNull((*string)(nil)),
+				sql.NullTime{Valid: false},
+				sql.NullInt64{Valid: false},
+				getNull(film.Runtime),
+				pq.Array(film.Countries),
+				getNull(film.OriginalLanguage),
+				getNull(film.AgeCertification),
+			).
+			WillReturnRows(sqlxmock.NewRows(filmColumns).AddRow(
+				film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification),
+			))
+		frs.mock.ExpectQuery(getFilmActors).WithArgs(film.ID).WillReturnRows(actorsRows())
+		frs.mock.ExpectCommit()
+
+		t.NewStep("Check result")
+		actors, err := frs.filmRepository.UpdateFilm(&UpdateFilm{
+			ID:               film.ID,
+			Runtime:          film.Runtime,
+			Countries:        &film.Countries,
+			OriginalLanguage: film.OriginalLanguage,
+			AgeCertification: film.AgeCertification,
+		})
+		t.Require().NoError(err)
+		t.Require().EqualValues(&FilmWithActors{
+			Film:   *film,
+			Actors: []Actor{*actor, *actor, *actor},
+		}, actors)
+	})
+
 	t.WithNewStep("Correct only name execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		frs.mock.ExpectBegin()
@@ -926,9 +1044,14 @@ func (frs *FilmRepositorySuite) TestUpdateFunction(t provider.T) {
 				getNull((*string)(nil)),
 				sql.NullTime{Valid: false},
 				sql.NullInt64{Valid: false},
+				sql.Null[uint16]{Valid: false},
+				nil,
+				sql.Null[string]{Valid: false},
+				sql.Null[types.Certification]{Valid: false},
 			).
 			WillReturnRows(sqlxmock.NewRows(filmColumns).AddRow(
 				film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification),
 			))
 		frs.mock.ExpectQuery(getFilmActors).WithArgs(film.ID).WillReturnRows(actorsRows())
 		frs.mock.ExpectCommit()
@@ -958,9 +1081,14 @@ func (frs *FilmRepositorySuite) TestUpdateFunction(t provider.T) {
 				getNull(&film.Description),
 				sql.NullTime{Valid: false},
 				sql.NullInt64{Valid: false},
+				sql.Null[uint16]{Valid: false},
+				nil,
+				sql.Null[string]{Valid: false},
+				sql.Null[types.Certification]{Valid: false},
 			).
 			WillReturnRows(sqlxmock.NewRows(filmColumns).AddRow(
 				film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification),
 			))
 		frs.mock.ExpectQuery(getFilmActors).WithArgs(film.ID).WillReturnRows(actorsRows())
 		frs.mock.ExpectCommit()
@@ -990,9 +1118,14 @@ func (frs *FilmRepositorySuite) TestUpdateFunction(t provider.T) {
 				getNull((*string)(nil)),
 				sql.NullTime{Valid: true, Time: film.DataPublish.Time},
 				sql.NullInt64{Valid: false},
+				sql.Null[uint16]{Valid: false},
+				nil,
+				sql.Null[string]{Valid: false},
+				sql.Null[types.Certification]{Valid: false},
 			).
 			WillReturnRows(sqlxmock.NewRows(filmColumns).AddRow(
 				film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification),
 			))
 		frs.mock.ExpectQuery(getFilmActors).WithArgs(film.ID).WillReturnRows(actorsRows())
 		frs.mock.ExpectCommit()
@@ -1022,9 +1155,14 @@ func (frs *FilmRepositorySuite) TestUpdateFunction(t provider.T) {
 				getNull((*string)(nil)),
 				sql.NullTime{Valid: false},
 				sql.NullInt64{Valid: true, Int64: int64(film.Rating)},
+				sql.Null[uint16]{Valid: false},
+				nil,
+				sql.Null[string]{Valid: false},
+				sql.Null[types.Certification]{Valid: false},
 			).
 			WillReturnRows(sqlxmock.NewRows(filmColumns).AddRow(
 				film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification),
 			))
 		frs.mock.ExpectQuery(getFilmActors).WithArgs(film.ID).WillReturnRows(actorsRows())
 		frs.mock.ExpectCommit()
@@ -1054,9 +1192,14 @@ func (frs *FilmRepositorySuite) TestUpdateFunction(t provider.T) {
 				getNull((*string)(nil)),
 				sql.NullTime{Valid: false},
 				sql.NullInt64{Valid: false},
+				sql.Null[uint16]{Valid: false},
+				nil,
+				sql.Null[string]{Valid: false},
+				sql.Null[types.Certification]{Valid: false},
 			).
 			WillReturnRows(sqlxmock.NewRows(filmColumns).AddRow(
 				film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification),
 			))
 		frs.mock.ExpectExec(deleteActors).WithArgs(film.ID).WillReturnResult(sqlxmock.NewResult(2, 2))
 		frs.mock.ExpectExec(addActors).WithArgs(film.ID, pq.Array(actorsId)).WillReturnResult(sqlxmock.NewResult(2, 2))
@@ -1144,9 +1287,14 @@ func (frs *FilmRepositorySuite) TestUpdateFunction(t provider.T) {
 				getNull(&film.Description),
 				sql.NullTime{Valid: true, Time: film.DataPublish.Time},
 				sql.NullInt64{Valid: true, Int64: int64(film.Rating)},
+				sql.Null[uint16]{Valid: false},
+				nil,
+				sql.Null[string]{Valid: false},
+				sql.Null[types.Certification]{Valid: false},
 			).
 			WillReturnRows(sqlxmock.NewRows(filmColumns).AddRow(
 				film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification),
 			))
 		frs.mock.ExpectExec(deleteActors).WithArgs(film.ID).WillReturnError(testError)
 		frs.mock.ExpectRollback()
@@ -1173,9 +1321,14 @@ func (frs *FilmRepositorySuite) TestUpdateFunction(t provider.T) {
 				getNull(&film.Description),
 				sql.NullTime{Valid: true, Time: film.DataPublish.Time},
 				sql.NullInt64{Valid: true, Int64: int64(film.Rating)},
+				sql.Null[uint16]{Valid: false},
+				nil,
+				sql.Null[string]{Valid: false},
+				sql.Null[types.Certification]{Valid: false},
 			).
 			WillReturnRows(sqlxmock.NewRows(filmColumns).AddRow(
 				film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification),
 			))
 		frs.mock.ExpectExec(deleteActors).WithArgs(film.ID).WillReturnResult(sqlxmock.NewResult(2, 2))
 		frs.mock.ExpectExec(addActors).WithArgs(film.ID, pq.Array(actorsId)).WillReturnError(testError)
@@ -1203,9 +1356,14 @@ func (frs *FilmRepositorySuite) TestUpdateFunction(t provider.T) {
 				getNull(&film.Description),
 				sql.NullTime{Valid: true, Time: film.DataPublish.Time},
 				sql.NullInt64{Valid: true, Int64: int64(film.Rating)},
+				sql.Null[uint16]{Valid: false},
+				nil,
+				sql.Null[string]{Valid: false},
+				sql.Null[types.Certification]{Valid: false},
 			).
 			WillReturnRows(sqlxmock.NewRows(filmColumns).AddRow(
 				film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification),
 			))
 		frs.mock.ExpectExec(deleteActors).WithArgs(film.ID).WillReturnResult(sqlxmock.NewResult(2, 2))
 		frs.mock.ExpectExec(addActors).WithArgs(film.ID, pq.Array(actorsId)).
@@ -1237,9 +1395,14 @@ func (frs *FilmRepositorySuite) TestUpdateFunction(t provider.T) {
 				getNull(&film.Description),
 				sql.NullTime{Valid: true, Time: film.DataPublish.Time},
 				sql.NullInt64{Valid: true, Int64: int64(film.Rating)},
+				sql.Null[uint16]{Valid: false},
+				nil,
+				sql.Null[string]{Valid: false},
+				sql.Null[types.Certification]{Valid: false},
 			).
 			WillReturnRows(sqlxmock.NewRows(filmColumns).AddRow(
 				film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification),
 			))
 		frs.mock.ExpectExec(deleteActors).WithArgs(film.ID).WillReturnResult(sqlxmock.NewResult(2, 2))
 		frs.mock.ExpectExec(addActors).WithArgs(film.ID, pq.Array(actorsId)).WillReturnResult(sqlxmock.NewResult(2, 2))
@@ -1268,9 +1431,14 @@ func (frs *FilmRepositorySuite) TestUpdateFunction(t provider.T) {
 				getNull(&film.Description),
 				sql.NullTime{Valid: true, Time: film.DataPublish.Time},
 				sql.NullInt64{Valid: true, Int64: int64(film.Rating)},
+				sql.Null[uint16]{Valid: false},
+				nil,
+				sql.Null[string]{Valid: false},
+				sql.Null[types.Certification]{Valid: false},
 			).
 			WillReturnRows(sqlxmock.NewRows(filmColumns).AddRow(
 				film.ID, film.Name, film.Description, film.DataPublish.Time, film.Rating,
+				*film.Runtime, "{USA,Canada}", *film.OriginalLanguage, string(*film.AgeCertification),
 			))
 		frs.mock.ExpectExec(deleteActors).WithArgs(film.ID).WillReturnResult(sqlxmock.NewResult(2, 2))
 		frs.mock.ExpectExec(addActors).WithArgs(film.ID, pq.Array(actorsId)).WillReturnResult(sqlxmock.NewResult(2, 2))
@@ -12,11 +12,21 @@ var (
 
 //go:generate mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=FilmRepository . Repository
 
+// Params
+// Nil filters and empty Certifications mean that the films are not filtered by the field.
+// Non nil MaxCertification excludes films with a stricter or unknown age certification.
 type Params struct {
-	OrderField   types.OrderField
-	Order        types.Order
-	SearchField  types.SearchField
-	SearchString string
+	OrderField     types.OrderField
+	Order          types.Order
+	SearchField    types.SearchField
+	SearchString   string
+	RuntimeFrom    *uint16
+	RuntimeTo      *uint16
+	Country        *string
+	Language       *string
+	Certifications []types.Certification
+
+	MaxCertification *types.Certification
 }
 
 type Repository interface {
@@ -6,21 +6,29 @@ import (
 )
 
 type UpdateFilm struct {
-	ID           types.Id
-	Name         *string
-	Description  *string
-	DataPublish  *time.FormattedTime
-	Rating       *types.Rating
-	Actors       []types.Id
-	UpdateActors bool
+	ID               types.Id
+	Name             *string
+	Description      *string
+	DataPublish      *time.FormattedTime
+	Rating           *types.Rating
+	Runtime          *uint16
+	Countries        *[]string
+	OriginalLanguage *string
+	AgeCertification *types.Certification
+	Actors           []types.Id
+	UpdateActors     bool
 }
 
 type Film struct {
-	ID          types.Id
-	Name        string
-	Description string
-	DataPublish time.FormattedTime
-	Rating      types.Rating
+	ID               types.Id
+	Name             string
+	Description      string
+	DataPublish      time.FormattedTime
+	Rating           types.Rating
+	Runtime          *uint16
+	Countries        []string
+	OriginalLanguage *string
+	AgeCertification *types.Certification
 }
 
 type FilmWithActors struct {
@@ -7,13 +7,14 @@ import (
 	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"vk_film/internal/pkg/types"
+	"vk_film/pkg/slices"
 )
 
 const (
 	createQuery = `
-		INSERT INTO films (name, description, publish_date, rating)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, name, description, publish_date, rating
+		INSERT INTO films (name, description, publish_date, rating, runtime, countries, original_language, age_certification)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, name, description, publish_date, rating, runtime, countries, original_language, age_certification
 	`
 
 	addActors = `
@@ -28,16 +29,23 @@ const (
 
 	updateFilms = `
 		UPDATE films SET name = upd_film.upd_name, description = upd_film.upd_description, 
-		                 publish_date = upd_film.upd_publish_date, rating = upd_film.upd_rating
+		                 publish_date = upd_film.upd_publish_date, rating = upd_film.upd_rating,
+		                 runtime = upd_film.upd_runtime, countries = upd_film.upd_countries,
+		                 original_language = upd_film.upd_original_language,
+		                 age_certification = upd_film.upd_age_certification
 			FROM (
 				SELECT COALESCE($2, films.name) as upd_name, 
 					   COALESCE($3, films.description) as upd_description, 
 					   COALESCE($4, films.publish_date) as upd_publish_date,
-					   COALESCE($5, films.rating) as upd_rating 
+					   COALESCE($5, films.rating) as upd_rating,
+					   COALESCE($6, films.runtime) as upd_runtime,
+					   COALESCE($7, films.countries) as upd_countries,
+					   COALESCE($8, films.original_language) as upd_original_language,
+					   COALESCE($9, films.age_certification) as upd_age_certification
 				FROM films WHERE id = $1
 			) as upd_film
 			WHERE id = $1
-			RETURNING id, name, description, publish_date, rating
+			RETURNING id, name, description, publish_date, rating, runtime, countries, original_language, age_certification
 	`
 
 	deleteActors = `
@@ -51,16 +59,17 @@ const (
 	`
 
 	getFilmsSearchFilm = `
-		SELECT id, name, description, publish_date, rating FROM films 
-		WHERE name LIKE '%%' || $1 || '%%'
+		SELECT id, name, description, publish_date, rating, runtime, countries, original_language, age_certification FROM films 
+		WHERE name LIKE '%%' || ? || '%%'%s
 		ORDER BY %s %s
 	`
 
 	getFilmsSearchActor = `
-		SELECT films.id, films.name, films.description, films.publish_date, films.rating FROM films 
+		SELECT films.id, films.name, films.description, films.publish_date, films.rating, films.runtime, films.countries,
+			   films.original_language, films.age_certification FROM films 
 		JOIN film_actor on (films.id = film_actor.film_id)
 			JOIN actors on (actors.id = film_actor.actor_id)
-		WHERE actors.name LIKE '%%' || $1 || '%%'
+		WHERE actors.name LIKE '%%' || ? || '%%'%s
 		ORDER BY films.%s %s
 	`
 
@@ -70,6 +79,12 @@ const (
 			JOIN actors on (actors.id = film_actor.actor_id)
 			WHERE films.id in (?)
 	`
+
+	runtimeFromCondition   = "films.runtime >= ?"
+	runtimeToCondition     = "films.runtime <= ?"
+	countryCondition       = "? = ANY(films.countries)"
+	languageCondition      = "films.original_language = ?"
+	certificationCondition = "films.age_certification IN (?)"
 )
 
 type PostgresFilm struct {
@@ -122,14 +137,24 @@ func (pf *PostgresFilm) CreateFilm(film *Film, actors []types.Id) (*FilmWithActo
 		return nil, errors.Wrap(err, "can't create transaction for create film")
 	}
 
+	countries := film.Countries
+	if countries == nil {
+		countries = make([]string, 0)
+	}
+
 	newFilm := &FilmWithActors{}
-	if err := tx.QueryRowx(createQuery, film.Name, film.Description, &film.DataPublish, film.Rating).
+	if err := tx.QueryRowx(createQuery, film.Name, film.Description, &film.DataPublish, film.Rating,
+		getNull(film.Runtime), pq.Array(countries), getNull(film.OriginalLanguage), getNull(film.AgeCertification)).
 		Scan(
 			&newFilm.ID,
 			&newFilm.Name,
 			&newFilm.Description,
 			&newFilm.DataPublish,
 			&newFilm.Rating,
+			&newFilm.Runtime,
+			pq.Array(&newFilm.Countries),
+			&newFilm.OriginalLanguage,
+			&newFilm.AgeCertification,
 		); err != nil {
 		_ = tx.Rollback()
 		return nil, errors.Wrap(err, "can't create film")
@@ -162,9 +187,20 @@ func getNull[T any](value *T) sql.Null[T] {
 	return sql.Null[T]{Valid: true, V: *value}
 }
 
+func getNullArray(value *[]string) any {
+	if value == nil {
+		return nil
+	}
+	return pq.Array(*value)
+}
+
 func (pf *PostgresFilm) UpdateFilm(film *UpdateFilm) (*FilmWithActors, error) {
 	name := getNull(film.Name)
 	description := getNull(film.Description)
+	runtime := getNull(film.Runtime)
+	countries := getNullArray(film.Countries)
+	originalLanguage := getNull(film.OriginalLanguage)
+	ageCertification := getNull(film.AgeCertification)
 
 	rating := sql.NullInt64{Valid: false}
 	if film.Rating != nil {
@@ -182,13 +218,18 @@ func (pf *PostgresFilm) UpdateFilm(film *UpdateFilm) (*FilmWithActors, error) {
 	}
 
 	updatedFilm := &FilmWithActors{}
-	if err := tx.QueryRowx(updateFilms, film.ID, name, description, dataPublish, rating).
+	if err := tx.QueryRowx(updateFilms, film.ID, name, description, dataPublish, rating,
+		runtime, countries, originalLanguage, ageCertification).
 		Scan(
 			&updatedFilm.ID,
 			&updatedFilm.Name,
 			&updatedFilm.Description,
 			&updatedFilm.DataPublish,
 			&updatedFilm.Rating,
+			&updatedFilm.Runtime,
+			pq.Array(&updatedFilm.Countries),
+			&updatedFilm.OriginalLanguage,
+			&updatedFilm.AgeCertification,
 		); err != nil {
 		_ = tx.Rollback()
 		if errors.Is(err, sql.ErrNoRows) {
@@ -245,7 +286,10 @@ func (pf *PostgresFilm) DeleteFilm(id types.Id) error {
 	return nil
 }
 
-func (pf *PostgresFilm) GetFilms(params Params) ([]FilmWithActors, error) {
+// buildGetFilmsQuery
+// Формирует запрос получения фильмов с учётом поиска, фильтров и сортировки.
+// Запрос использует плейсхолдеры '?', которые необходимо привести к формату драйвера.
+func buildGetFilmsQuery(params Params) (string, []any, error) {
 	// По умолчанию ищем в фильмах. Если не задана строка будет поиск всего
 	preparedQuery := getFilmsSearchFilm
 	if params.SearchField == types.ActorField {
@@ -256,7 +300,60 @@ func (pf *PostgresFilm) GetFilms(params Params) ([]FilmWithActors, error) {
 		params.SearchString = ""
 	}
 
-	preparedQuery = fmt.Sprintf(preparedQuery, params.OrderField, params.Order)
+	conditions := ""
+	args := []any{params.SearchString}
+
+	if params.RuntimeFrom != nil {
+		conditions += " AND " + runtimeFromCondition
+		args = append(args, *params.RuntimeFrom)
+	}
+
+	if params.RuntimeTo != nil {
+		conditions += " AND " + runtimeToCondition
+		args = append(args, *params.RuntimeTo)
+	}
+
+	if params.Country != nil {
+		conditions += " AND " + countryCondition
+		args = append(args, *params.Country)
+	}
+
+	if params.Language != nil {
+		conditions += " AND " + languageCondition
+		args = append(args, *params.Language)
+	}
+
+	if len(params.Certifications) != 0 {
+		conditions += " AND " + certificationCondition
+		args = append(args, certificationsArg(params.Certifications))
+	}
+
+	// Фильмы с более строгим или неизвестным возрастным рейтингом скрываются
+	if params.MaxCertification != nil {
+		conditions += " AND " + certificationCondition
+		args = append(args, certificationsArg(types.AllowedCertifications(*params.MaxCertification)))
+	}
+
+	preparedQuery = fmt.Sprintf(preparedQuery, conditions, params.OrderField, params.Order)
+
+	if len(params.Certifications) == 0 && params.MaxCertification == nil {
+		return preparedQuery, args, nil
+	}
+
+	return sqlx.In(preparedQuery, args...)
+}
+
+func certificationsArg(certifications []types.Certification) []string {
+	return slices.Map(certifications, func(c types.Certification) string {
+		return string(c)
+	})
+}
+
+func (pf *PostgresFilm) GetFilms(params Params) ([]FilmWithActors, error) {
+	preparedQuery, queryArgs, err := buildGetFilmsQuery(params)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't prepare get films query")
+	}
 
 	tx, err := pf.db.Beginx()
 	if err != nil {
@@ -264,7 +361,7 @@ func (pf *PostgresFilm) GetFilms(params Params) ([]FilmWithActors, error) {
 	}
 
 	// Получаем список всех фильмов
-	rows, err := tx.Queryx(preparedQuery, params.SearchString)
+	rows, err := tx.Queryx(tx.Rebind(preparedQuery), queryArgs...)
 	if err != nil {
 		_ = tx.Rollback()
 		return nil, errors.Wrap(err, "can't execute get films query")
@@ -284,6 +381,10 @@ func (pf *PostgresFilm) GetFilms(params Params) ([]FilmWithActors, error) {
 			&film.Description,
 			&film.DataPublish,
 			&film.Rating,
+			&film.Runtime,
+			pq.Array(&film.Countries),
+			&film.OriginalLanguage,
+			&film.AgeCertification,
 		)
 
 		if err != nil {
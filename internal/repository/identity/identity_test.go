@@ -0,0 +1,135 @@
+package identity
+
+import (
+	"github.com/lib/pq"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/pkg/errors"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/user"
+)
+
+var testError = errors.New("test error")
+
+type IdentityRepositorySuite struct {
+	suite.Suite
+	identityRepository *PostgresIdentity
+	mock               sqlxmock.Sqlmock
+}
+
+func (irs *IdentityRepositorySuite) BeforeEach(t provider.T) {
+	db, mock, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	t.Require().NoError(err)
+	irs.identityRepository = NewPostgresIdentity(db)
+	irs.mock = mock
+}
+
+func (irs *IdentityRepositorySuite) AfterEach(t provider.T) {
+	t.Require().NoError(irs.mock.ExpectationsWereMet())
+}
+
+func (irs *IdentityRepositorySuite) TestGetUserIdFunction(t provider.T) {
+	t.Title("GetUserId function of Identity repository")
+	t.NewStep("Init test data")
+	issuer, subject := "https://sso.example.com", "248289761001"
+	userId := types.Id(3)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		irs.mock.ExpectQuery(getUserId).
+			WithArgs(issuer, subject).
+			WillReturnRows(sqlxmock.NewRows([]string{"user_id"}).AddRow(userId))
+
+		t.NewStep("Check result")
+		res, err := irs.identityRepository.GetUserId(issuer, subject)
+		t.Require().NoError(err)
+		t.Require().Equal(userId, res)
+	})
+
+	t.WithNewStep("Identity not found execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		irs.mock.ExpectQuery(getUserId).
+			WithArgs(issuer, subject).
+			WillReturnRows(sqlxmock.NewRows([]string{"user_id"}))
+
+		t.NewStep("Check result")
+		_, err := irs.identityRepository.GetUserId(issuer, subject)
+		t.Require().ErrorIs(err, ErrorIdentityNotFound)
+	})
+
+	t.WithNewStep("SQL error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		irs.mock.ExpectQuery(getUserId).
+			WithArgs(issuer, subject).
+			WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := irs.identityRepository.GetUserId(issuer, subject)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (irs *IdentityRepositorySuite) TestCreateUserFunction(t provider.T) {
+	t.Title("CreateUser function of Identity repository")
+	t.NewStep("Init test data")
+	identity := &Identity{Issuer: "https://sso.example.com", Subject: "248289761001"}
+	usr := &user.User{ID: 3, Login: "jane", Role: "editor"}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		irs.mock.ExpectQuery(createUser).
+			WithArgs(usr.Login, usr.Role, identity.Issuer, identity.Subject).
+			WillReturnRows(sqlxmock.NewRows([]string{"id", "login", "role"}).AddRow(usr.ID, usr.Login, usr.Role))
+
+		t.NewStep("Check result")
+		createdUser, err := irs.identityRepository.CreateUser(&user.User{Login: usr.Login, Role: usr.Role}, identity)
+		t.Require().NoError(err)
+		t.Require().EqualValues(usr, createdUser)
+	})
+
+	errorCases := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{
+			name:     "Login already exists",
+			err:      &pq.Error{Code: uniqueViolationCode, Constraint: loginConstraintName},
+			expected: ErrorLoginAlreadyExists,
+		},
+		{
+			name:     "Identity already exists",
+			err:      &pq.Error{Code: uniqueViolationCode, Constraint: identityConstraintName},
+			expected: ErrorIdentityAlreadyExists,
+		},
+		{
+			name:     "Role not found",
+			err:      &pq.Error{Code: foreignKeyViolationCode, Constraint: roleConstraintName},
+			expected: ErrorRoleNotFound,
+		},
+		{
+			name:     "SQL error",
+			err:      testError,
+			expected: testError,
+		},
+	}
+
+	for _, cs := range errorCases {
+		t.WithNewStep(cs.name+" execute", func(t provider.StepCtx) {
+			t.NewStep("Init mock")
+			irs.mock.ExpectQuery(createUser).
+				WithArgs(usr.Login, usr.Role, identity.Issuer, identity.Subject).
+				WillReturnError(cs.err)
+
+			t.NewStep("Check result")
+			_, err := irs.identityRepository.CreateUser(&user.User{Login: usr.Login, Role: usr.Role}, identity)
+			t.Require().ErrorIs(err, cs.expected)
+		})
+	}
+}
+
+func TestRunIdentityRepositorySuite(t *testing.T) {
+	suite.RunSuite(t, new(IdentityRepositorySuite))
+}
@@ -0,0 +1,34 @@
+package identity
+
+import (
+	"github.com/pkg/errors"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/user"
+)
+
+var (
+	ErrorIdentityNotFound      = errors.New("external identity not found")
+	ErrorIdentityAlreadyExists = errors.New("external identity is already linked to a user")
+	ErrorLoginAlreadyExists    = errors.New("user with this login already exists")
+	ErrorRoleNotFound          = errors.New("role of user not found")
+)
+
+//go:generate mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=IdentityRepository . Repository
+
+type Repository interface {
+	// GetUserId
+	// Returns the id of the user linked to the identity of the provider.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorIdentityNotFound
+	GetUserId(issuer, subject string) (types.Id, error)
+
+	// CreateUser
+	// Creates the user without password and links the identity to him.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorIdentityAlreadyExists
+	//   - ErrorLoginAlreadyExists
+	//   - ErrorRoleNotFound
+	CreateUser(usr *user.User, identity *Identity) (*user.User, error)
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: vk_film/internal/repository/identity (interfaces: Repository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=IdentityRepository . Repository
+//
+
+// Package mr is a generated GoMock package.
+package mr
+
+import (
+	reflect "reflect"
+	types "vk_film/internal/pkg/types"
+	identity "vk_film/internal/repository/identity"
+	user "vk_film/internal/repository/user"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// IdentityRepository is a mock of Repository interface.
+type IdentityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *IdentityRepositoryMockRecorder
+}
+
+// IdentityRepositoryMockRecorder is the mock recorder for IdentityRepository.
+type IdentityRepositoryMockRecorder struct {
+	mock *IdentityRepository
+}
+
+// NewIdentityRepository creates a new mock instance.
+func NewIdentityRepository(ctrl *gomock.Controller) *IdentityRepository {
+	mock := &IdentityRepository{ctrl: ctrl}
+	mock.recorder = &IdentityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *IdentityRepository) EXPECT() *IdentityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateUser mocks base method.
+func (m *IdentityRepository) CreateUser(arg0 *user.User, arg1 *identity.Identity) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *IdentityRepositoryMockRecorder) CreateUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*IdentityRepository)(nil).CreateUser), arg0, arg1)
+}
+
+// GetUserId mocks base method.
+func (m *IdentityRepository) GetUserId(arg0, arg1 string) (types.Id, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserId", arg0, arg1)
+	ret0, _ := ret[0].(types.Id)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserId indicates an expected call of GetUserId.
+func (mr *IdentityRepositoryMockRecorder) GetUserId(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserId", reflect.TypeOf((*IdentityRepository)(nil).GetUserId), arg0, arg1)
+}
@@ -0,0 +1,8 @@
+package identity
+
+// Identity
+// Account of the user in an external identity provider
+type Identity struct {
+	Issuer  string
+	Subject string
+}
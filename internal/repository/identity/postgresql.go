@@ -0,0 +1,96 @@
+package identity
+
+import (
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/user"
+)
+
+const (
+	getUserId = `
+		SELECT user_id FROM user_identities WHERE issuer = $1 AND subject = $2
+	`
+
+	createUser = `
+		WITH ins AS (
+			INSERT INTO users (login, password, role)
+				VALUES ($1, '', $2)
+				RETURNING id, login, role
+		), link AS (
+			INSERT INTO user_identities (issuer, subject, user_id)
+				SELECT $3, $4, id FROM ins
+		)
+		SELECT id, login, role FROM ins
+	`
+)
+
+const (
+	uniqueViolationCode     = "23505"
+	foreignKeyViolationCode = "23503"
+
+	loginConstraintName    = "users_login_key"
+	identityConstraintName = "user_identities_pkey"
+	roleConstraintName     = "users_role_fkey"
+)
+
+type PostgresIdentity struct {
+	db *sqlx.DB
+}
+
+func NewPostgresIdentity(db *sqlx.DB) *PostgresIdentity {
+	return &PostgresIdentity{
+		db: db,
+	}
+}
+
+var _ = Repository(&PostgresIdentity{})
+
+func (pi *PostgresIdentity) GetUserId(issuer, subject string) (types.Id, error) {
+	var userId types.Id
+	if err := pi.db.QueryRowx(getUserId, issuer, subject).Scan(&userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrorIdentityNotFound
+		}
+		return 0, errors.Wrapf(err, "can't get user of identity %s from %s", subject, issuer)
+	}
+
+	return userId, nil
+}
+
+func (pi *PostgresIdentity) CreateUser(usr *user.User, identity *Identity) (*user.User, error) {
+	createdUser := &user.User{}
+	if err := pi.db.QueryRowx(createUser, usr.Login, usr.Role, identity.Issuer, identity.Subject).
+		Scan(
+			&createdUser.ID,
+			&createdUser.Login,
+			&createdUser.Role,
+		); err != nil {
+		return nil, errors.Wrapf(checkConstraintError(err), "can't create user for identity %s from %s",
+			identity.Subject, identity.Issuer)
+	}
+
+	return createdUser, nil
+}
+
+// checkConstraintError
+// Преобразует нарушения ограничений таблиц в ошибки репозитория
+func checkConstraintError(err error) error {
+	var e *pq.Error
+	if !errors.As(err, &e) {
+		return err
+	}
+
+	switch {
+	case e.Code == uniqueViolationCode && e.Constraint == loginConstraintName:
+		return ErrorLoginAlreadyExists
+	case e.Code == uniqueViolationCode && e.Constraint == identityConstraintName:
+		return ErrorIdentityAlreadyExists
+	case e.Code == foreignKeyViolationCode && e.Constraint == roleConstraintName:
+		return ErrorRoleNotFound
+	}
+
+	return err
+}
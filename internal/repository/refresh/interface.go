@@ -0,0 +1,63 @@
+package refresh
+
+import (
+	"github.com/pkg/errors"
+	"vk_film/internal/pkg/types"
+)
+
+var (
+	ErrorTokenNotFound    = errors.New("refresh token not found")
+	ErrorTokenAlreadyUsed = errors.New("refresh token already used")
+	ErrorSessionNotFound  = errors.New("session not found")
+)
+
+//go:generate mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=RefreshRepository . Repository
+
+type Repository interface {
+	// CreateToken
+	// Only the hash of the token is stored.
+	// Returns Error:
+	//   - SQLError
+	CreateToken(token *Token, hash string) error
+
+	// GetToken
+	// Finds not expired token by its hash, including already used tokens.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorTokenNotFound
+	GetToken(hash string) (*Token, error)
+
+	// UseToken
+	// Marks the token as used.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorTokenAlreadyUsed
+	UseToken(tokenId types.Id) error
+
+	// DelSession
+	// Deletes all tokens of the session.
+	// Returns Error:
+	//   - SQLError
+	DelSession(sessionId string) error
+
+	// GetUserSessions
+	// Returns sessions of the user with not expired tokens ordered by creation time.
+	// Client metadata is taken from the last token of the session.
+	// Returns Error:
+	//   - SQLError
+	GetUserSessions(userId types.Id) ([]Session, error)
+
+	// DelUserSession
+	// Deletes all tokens of the session of the user.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorSessionNotFound
+	DelUserSession(userId types.Id, sessionId string) error
+
+	// DelUserSessions
+	// Deletes all tokens of the user except tokens of exceptSessionId.
+	// Empty exceptSessionId means that all tokens of the user are deleted.
+	// Returns Error:
+	//   - SQLError
+	DelUserSessions(userId types.Id, exceptSessionId string) error
+}
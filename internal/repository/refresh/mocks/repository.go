@@ -0,0 +1,141 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: vk_film/internal/repository/refresh (interfaces: Repository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=RefreshRepository . Repository
+//
+
+// Package mr is a generated GoMock package.
+package mr
+
+import (
+	reflect "reflect"
+	types "vk_film/internal/pkg/types"
+	refresh "vk_film/internal/repository/refresh"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// RefreshRepository is a mock of Repository interface.
+type RefreshRepository struct {
+	ctrl     *gomock.Controller
+	recorder *RefreshRepositoryMockRecorder
+}
+
+// RefreshRepositoryMockRecorder is the mock recorder for RefreshRepository.
+type RefreshRepositoryMockRecorder struct {
+	mock *RefreshRepository
+}
+
+// NewRefreshRepository creates a new mock instance.
+func NewRefreshRepository(ctrl *gomock.Controller) *RefreshRepository {
+	mock := &RefreshRepository{ctrl: ctrl}
+	mock.recorder = &RefreshRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *RefreshRepository) EXPECT() *RefreshRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateToken mocks base method.
+func (m *RefreshRepository) CreateToken(arg0 *refresh.Token, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateToken", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateToken indicates an expected call of CreateToken.
+func (mr *RefreshRepositoryMockRecorder) CreateToken(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToken", reflect.TypeOf((*RefreshRepository)(nil).CreateToken), arg0, arg1)
+}
+
+// DelSession mocks base method.
+func (m *RefreshRepository) DelSession(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DelSession", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DelSession indicates an expected call of DelSession.
+func (mr *RefreshRepositoryMockRecorder) DelSession(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DelSession", reflect.TypeOf((*RefreshRepository)(nil).DelSession), arg0)
+}
+
+// DelUserSession mocks base method.
+func (m *RefreshRepository) DelUserSession(arg0 types.Id, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DelUserSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DelUserSession indicates an expected call of DelUserSession.
+func (mr *RefreshRepositoryMockRecorder) DelUserSession(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DelUserSession", reflect.TypeOf((*RefreshRepository)(nil).DelUserSession), arg0, arg1)
+}
+
+// DelUserSessions mocks base method.
+func (m *RefreshRepository) DelUserSessions(arg0 types.Id, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DelUserSessions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DelUserSessions indicates an expected call of DelUserSessions.
+func (mr *RefreshRepositoryMockRecorder) DelUserSessions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DelUserSessions", reflect.TypeOf((*RefreshRepository)(nil).DelUserSessions), arg0, arg1)
+}
+
+// GetToken mocks base method.
+func (m *RefreshRepository) GetToken(arg0 string) (*refresh.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetToken", arg0)
+	ret0, _ := ret[0].(*refresh.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetToken indicates an expected call of GetToken.
+func (mr *RefreshRepositoryMockRecorder) GetToken(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetToken", reflect.TypeOf((*RefreshRepository)(nil).GetToken), arg0)
+}
+
+// GetUserSessions mocks base method.
+func (m *RefreshRepository) GetUserSessions(arg0 types.Id) ([]refresh.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSessions", arg0)
+	ret0, _ := ret[0].([]refresh.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSessions indicates an expected call of GetUserSessions.
+func (mr *RefreshRepositoryMockRecorder) GetUserSessions(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSessions", reflect.TypeOf((*RefreshRepository)(nil).GetUserSessions), arg0)
+}
+
+// UseToken mocks base method.
+func (m *RefreshRepository) UseToken(arg0 types.Id) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseToken", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseToken indicates an expected call of UseToken.
+func (mr *RefreshRepositoryMockRecorder) UseToken(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseToken", reflect.TypeOf((*RefreshRepository)(nil).UseToken), arg0)
+}
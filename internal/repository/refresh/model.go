@@ -0,0 +1,30 @@
+package refresh
+
+import (
+	"time"
+	"vk_film/internal/pkg/types"
+)
+
+// Token
+// Refresh token of the session. All tokens issued by rotation of one login
+// share the same SessionId.
+type Token struct {
+	ID        types.Id
+	SessionId string
+	UserID    types.Id
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	IP        string
+	UserAgent string
+}
+
+// Session
+// Session built from its refresh tokens. LastSeenAt is the time of the last refresh.
+type Session struct {
+	SessionId  string
+	UserID     types.Id
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	IP         string
+	UserAgent  string
+}
@@ -0,0 +1,167 @@
+package refresh
+
+import (
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"vk_film/internal/pkg/types"
+)
+
+const (
+	createToken = `
+		INSERT INTO refresh_tokens (session_id, user_id, token_hash, expires_at, ip, user_agent)
+			VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	getToken = `
+		SELECT id, session_id, user_id, expires_at, used_at, ip, user_agent FROM refresh_tokens
+			WHERE token_hash = $1 AND expires_at > now()
+	`
+
+	useToken = `
+		UPDATE refresh_tokens SET used_at = now() WHERE id = $1 AND used_at IS NULL
+	`
+
+	delSession = `
+		DELETE FROM refresh_tokens WHERE session_id = $1
+	`
+
+	getUserSessions = `
+		SELECT session_id, user_id, min(created_at), max(created_at),
+				(array_agg(ip ORDER BY created_at DESC))[1], (array_agg(user_agent ORDER BY created_at DESC))[1]
+			FROM refresh_tokens
+			WHERE user_id = $1 AND expires_at > now()
+			GROUP BY session_id, user_id
+			ORDER BY min(created_at), session_id
+	`
+
+	delUserSession = `
+		DELETE FROM refresh_tokens WHERE user_id = $1 AND session_id = $2
+	`
+
+	delUserSessions = `
+		DELETE FROM refresh_tokens WHERE user_id = $1 AND session_id != $2
+	`
+)
+
+type PostgresRefresh struct {
+	db *sqlx.DB
+}
+
+func NewPostgresRefresh(db *sqlx.DB) *PostgresRefresh {
+	return &PostgresRefresh{
+		db: db,
+	}
+}
+
+var _ = Repository(&PostgresRefresh{})
+
+func (pr *PostgresRefresh) CreateToken(token *Token, hash string) error {
+	if _, err := pr.db.Exec(createToken, token.SessionId, token.UserID, hash, token.ExpiresAt,
+		token.IP, token.UserAgent); err != nil {
+		return errors.Wrapf(err, "can't create refresh token of session %s", token.SessionId)
+	}
+
+	return nil
+}
+
+func (pr *PostgresRefresh) GetToken(hash string) (*Token, error) {
+	token := &Token{}
+
+	row := pr.db.QueryRowx(getToken, hash)
+	if err := row.Scan(&token.ID, &token.SessionId, &token.UserID, &token.ExpiresAt, &token.UsedAt,
+		&token.IP, &token.UserAgent); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrorTokenNotFound
+		}
+		return nil, errors.Wrap(err, "can't get refresh token")
+	}
+
+	return token, nil
+}
+
+func (pr *PostgresRefresh) UseToken(tokenId types.Id) error {
+	res, err := pr.db.Exec(useToken, tokenId)
+	if err != nil {
+		return errors.Wrapf(err, "can't execute using query for refresh token %d", tokenId)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrapf(err, "can't get number affected rows of using query for refresh token %d", tokenId)
+	}
+
+	// Токен уже был использован параллельным запросом
+	if n != 1 {
+		return errors.Wrapf(ErrorTokenAlreadyUsed, "with id %d", tokenId)
+	}
+
+	return nil
+}
+
+func (pr *PostgresRefresh) DelSession(sessionId string) error {
+	if _, err := pr.db.Exec(delSession, sessionId); err != nil {
+		return errors.Wrapf(err, "can't delete refresh tokens of session %s", sessionId)
+	}
+
+	return nil
+}
+
+func (pr *PostgresRefresh) GetUserSessions(userId types.Id) ([]Session, error) {
+	rows, err := pr.db.Queryx(getUserSessions, userId)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't execute get user sessions query")
+	}
+	defer rows.Close()
+
+	sessions := make([]Session, 0)
+
+	for rows.Next() {
+		var session Session
+
+		if err := rows.Scan(
+			&session.SessionId,
+			&session.UserID,
+			&session.CreatedAt,
+			&session.LastSeenAt,
+			&session.IP,
+			&session.UserAgent,
+		); err != nil {
+			return nil, errors.Wrap(err, "can't scan get user sessions query result")
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "can't end scan get user sessions query result")
+	}
+
+	return sessions, nil
+}
+
+func (pr *PostgresRefresh) DelUserSession(userId types.Id, sessionId string) error {
+	res, err := pr.db.Exec(delUserSession, userId, sessionId)
+	if err != nil {
+		return errors.Wrapf(err, "can't execute deleting query for session %s", sessionId)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrapf(err, "can't get number affected rows of deleting query for session %s", sessionId)
+	}
+
+	if n == 0 {
+		return errors.Wrapf(ErrorSessionNotFound, "with id %s", sessionId)
+	}
+
+	return nil
+}
+
+func (pr *PostgresRefresh) DelUserSessions(userId types.Id, exceptSessionId string) error {
+	if _, err := pr.db.Exec(delUserSessions, userId, exceptSessionId); err != nil {
+		return errors.Wrapf(err, "can't delete refresh tokens of user %d", userId)
+	}
+
+	return nil
+}
@@ -0,0 +1,333 @@
+package refresh
+
+import (
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/pkg/errors"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+var testError = errors.New("test error")
+
+type RefreshRepositorySuite struct {
+	suite.Suite
+	refreshRepository *PostgresRefresh
+	mock              sqlxmock.Sqlmock
+}
+
+func (rrs *RefreshRepositorySuite) BeforeEach(t provider.T) {
+	db, mock, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	t.Require().NoError(err)
+	rrs.refreshRepository = NewPostgresRefresh(db)
+	rrs.mock = mock
+}
+
+func (rrs *RefreshRepositorySuite) AfterEach(t provider.T) {
+	t.Require().NoError(rrs.mock.ExpectationsWereMet())
+}
+
+func newTestToken() *Token {
+	return &Token{
+		ID:        1,
+		SessionId: "session",
+		UserID:    2,
+		ExpiresAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		IP:        "127.0.0.1",
+		UserAgent: "curl/8.0",
+	}
+}
+
+func (rrs *RefreshRepositorySuite) TestCreateFunction(t provider.T) {
+	t.Title("CreateToken function of Refresh repository")
+	t.NewStep("Init test data")
+	token := newTestToken()
+	hash := "hash"
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(createToken).
+			WithArgs(token.SessionId, token.UserID, hash, token.ExpiresAt, token.IP, token.UserAgent).
+			WillReturnResult(sqlxmock.NewResult(1, 1))
+
+		t.NewStep("Check result")
+		t.Require().NoError(rrs.refreshRepository.CreateToken(token, hash))
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(createToken).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.refreshRepository.CreateToken(token, hash), testError)
+	})
+}
+
+func (rrs *RefreshRepositorySuite) TestGetFunction(t provider.T) {
+	t.Title("GetToken function of Refresh repository")
+	t.NewStep("Init test data")
+	token := newTestToken()
+	usedAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	hash := "hash"
+	columns := []string{"id", "session_id", "user_id", "expires_at", "used_at", "ip", "user_agent"}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(getToken).
+			WithArgs(hash).
+			WillReturnRows(
+				sqlxmock.NewRows(columns).AddRow(
+					token.ID, token.SessionId, token.UserID, token.ExpiresAt, nil, token.IP, token.UserAgent,
+				),
+			)
+
+		t.NewStep("Check result")
+		res, err := rrs.refreshRepository.GetToken(hash)
+		t.Require().NoError(err)
+		t.Require().EqualValues(token, res)
+	})
+
+	t.WithNewStep("Correct execute with used token", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(getToken).
+			WithArgs(hash).
+			WillReturnRows(
+				sqlxmock.NewRows(columns).AddRow(
+					token.ID, token.SessionId, token.UserID, token.ExpiresAt, usedAt, token.IP, token.UserAgent,
+				),
+			)
+
+		t.NewStep("Check result")
+		res, err := rrs.refreshRepository.GetToken(hash)
+		t.Require().NoError(err)
+		t.Require().NotNil(res.UsedAt)
+		t.Require().Equal(usedAt, *res.UsedAt)
+	})
+
+	t.WithNewStep("Token not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(getToken).WithArgs(hash).WillReturnRows(sqlxmock.NewRows(columns))
+
+		t.NewStep("Check result")
+		_, err := rrs.refreshRepository.GetToken(hash)
+		t.Require().ErrorIs(err, ErrorTokenNotFound)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(getToken).WithArgs(hash).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := rrs.refreshRepository.GetToken(hash)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (rrs *RefreshRepositorySuite) TestUseFunction(t provider.T) {
+	t.Title("UseToken function of Refresh repository")
+	t.NewStep("Init test data")
+	token := newTestToken()
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(useToken).WithArgs(token.ID).WillReturnResult(sqlxmock.NewResult(0, 1))
+
+		t.NewStep("Check result")
+		t.Require().NoError(rrs.refreshRepository.UseToken(token.ID))
+	})
+
+	t.WithNewStep("Token already used in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(useToken).WithArgs(token.ID).WillReturnResult(sqlxmock.NewResult(0, 0))
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.refreshRepository.UseToken(token.ID), ErrorTokenAlreadyUsed)
+	})
+
+	t.WithNewStep("Row affected error of execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(useToken).WithArgs(token.ID).WillReturnResult(sqlxmock.NewErrorResult(testError))
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.refreshRepository.UseToken(token.ID), testError)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(useToken).WithArgs(token.ID).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.refreshRepository.UseToken(token.ID), testError)
+	})
+}
+
+func (rrs *RefreshRepositorySuite) TestDelSessionFunction(t provider.T) {
+	t.Title("DelSession function of Refresh repository")
+	t.NewStep("Init test data")
+	sessionId := "session"
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(delSession).WithArgs(sessionId).WillReturnResult(sqlxmock.NewResult(0, 2))
+
+		t.NewStep("Check result")
+		t.Require().NoError(rrs.refreshRepository.DelSession(sessionId))
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(delSession).WithArgs(sessionId).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.refreshRepository.DelSession(sessionId), testError)
+	})
+}
+
+func (rrs *RefreshRepositorySuite) TestGetUserSessionsFunction(t provider.T) {
+	t.Title("GetUserSessions function of Refresh repository")
+	t.NewStep("Init test data")
+	sessions := []Session{
+		{
+			SessionId:  "first",
+			UserID:     2,
+			CreatedAt:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			LastSeenAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			IP:         "127.0.0.1",
+			UserAgent:  "curl/8.0",
+		},
+		{
+			SessionId:  "second",
+			UserID:     2,
+			CreatedAt:  time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+			LastSeenAt: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+			IP:         "10.0.0.1",
+			UserAgent:  "Mozilla/5.0",
+		},
+	}
+	userId := sessions[0].UserID
+	columns := []string{"session_id", "user_id", "created_at", "last_seen_at", "ip", "user_agent"}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rows := sqlxmock.NewRows(columns)
+		for _, session := range sessions {
+			rows.AddRow(
+				session.SessionId, session.UserID, session.CreatedAt,
+				session.LastSeenAt, session.IP, session.UserAgent,
+			)
+		}
+		rrs.mock.ExpectQuery(getUserSessions).WithArgs(userId).WillReturnRows(rows)
+
+		t.NewStep("Check result")
+		res, err := rrs.refreshRepository.GetUserSessions(userId)
+		t.Require().NoError(err)
+		t.Require().EqualValues(sessions, res)
+	})
+
+	t.WithNewStep("Correct execute without sessions", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(getUserSessions).WithArgs(userId).WillReturnRows(sqlxmock.NewRows(columns))
+
+		t.NewStep("Check result")
+		res, err := rrs.refreshRepository.GetUserSessions(userId)
+		t.Require().NoError(err)
+		t.Require().Len(res, 0)
+	})
+
+	t.WithNewStep("Scan error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(getUserSessions).
+			WithArgs(userId).
+			WillReturnRows(sqlxmock.NewRows(columns).AddRow("first", "user", nil, nil, "", "")).
+			RowsWillBeClosed()
+
+		t.NewStep("Check result")
+		_, err := rrs.refreshRepository.GetUserSessions(userId)
+		t.Require().Error(err)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(getUserSessions).WithArgs(userId).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := rrs.refreshRepository.GetUserSessions(userId)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (rrs *RefreshRepositorySuite) TestDelUserSessionFunction(t provider.T) {
+	t.Title("DelUserSession function of Refresh repository")
+	t.NewStep("Init test data")
+	token := newTestToken()
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(delUserSession).
+			WithArgs(token.UserID, token.SessionId).
+			WillReturnResult(sqlxmock.NewResult(0, 2))
+
+		t.NewStep("Check result")
+		t.Require().NoError(rrs.refreshRepository.DelUserSession(token.UserID, token.SessionId))
+	})
+
+	t.WithNewStep("Session not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(delUserSession).
+			WithArgs(token.UserID, token.SessionId).
+			WillReturnResult(sqlxmock.NewResult(0, 0))
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(
+			rrs.refreshRepository.DelUserSession(token.UserID, token.SessionId),
+			ErrorSessionNotFound,
+		)
+	})
+
+	t.WithNewStep("Row affected error of execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(delUserSession).
+			WithArgs(token.UserID, token.SessionId).
+			WillReturnResult(sqlxmock.NewErrorResult(testError))
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.refreshRepository.DelUserSession(token.UserID, token.SessionId), testError)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(delUserSession).WithArgs(token.UserID, token.SessionId).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.refreshRepository.DelUserSession(token.UserID, token.SessionId), testError)
+	})
+}
+
+func (rrs *RefreshRepositorySuite) TestDelUserSessionsFunction(t provider.T) {
+	t.Title("DelUserSessions function of Refresh repository")
+	t.NewStep("Init test data")
+	token := newTestToken()
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(delUserSessions).
+			WithArgs(token.UserID, token.SessionId).
+			WillReturnResult(sqlxmock.NewResult(0, 3))
+
+		t.NewStep("Check result")
+		t.Require().NoError(rrs.refreshRepository.DelUserSessions(token.UserID, token.SessionId))
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(delUserSessions).WithArgs(token.UserID, "").WillReturnError(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.refreshRepository.DelUserSessions(token.UserID, ""), testError)
+	})
+}
+
+func TestRunRefreshRepositorySuite(t *testing.T) {
+	suite.RunSuite(t, new(RefreshRepositorySuite))
+}
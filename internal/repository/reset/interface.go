@@ -0,0 +1,29 @@
+package reset
+
+import (
+	"github.com/pkg/errors"
+	"time"
+	"vk_film/internal/pkg/types"
+)
+
+var (
+	ErrorTokenNotFound = errors.New("password reset token not found, used or expired")
+)
+
+//go:generate mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=ResetRepository . Repository
+
+type Repository interface {
+	// CreateToken
+	// Saves the hash of a new reset token of the user. Previous tokens of the user
+	// and expired tokens of all users are deleted.
+	// Returns Error:
+	//   - SQLError
+	CreateToken(userId types.Id, hash string, expiresAt time.Time) error
+
+	// UseToken
+	// Marks the not expired token as used and returns the id of its user.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorTokenNotFound
+	UseToken(hash string) (types.Id, error)
+}
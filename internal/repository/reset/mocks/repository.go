@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: vk_film/internal/repository/reset (interfaces: Repository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=ResetRepository . Repository
+//
+
+// Package mr is a generated GoMock package.
+package mr
+
+import (
+	reflect "reflect"
+	time "time"
+	types "vk_film/internal/pkg/types"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// ResetRepository is a mock of Repository interface.
+type ResetRepository struct {
+	ctrl     *gomock.Controller
+	recorder *ResetRepositoryMockRecorder
+}
+
+// ResetRepositoryMockRecorder is the mock recorder for ResetRepository.
+type ResetRepositoryMockRecorder struct {
+	mock *ResetRepository
+}
+
+// NewResetRepository creates a new mock instance.
+func NewResetRepository(ctrl *gomock.Controller) *ResetRepository {
+	mock := &ResetRepository{ctrl: ctrl}
+	mock.recorder = &ResetRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *ResetRepository) EXPECT() *ResetRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateToken mocks base method.
+func (m *ResetRepository) CreateToken(arg0 types.Id, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateToken", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateToken indicates an expected call of CreateToken.
+func (mr *ResetRepositoryMockRecorder) CreateToken(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToken", reflect.TypeOf((*ResetRepository)(nil).CreateToken), arg0, arg1, arg2)
+}
+
+// UseToken mocks base method.
+func (m *ResetRepository) UseToken(arg0 string) (types.Id, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseToken", arg0)
+	ret0, _ := ret[0].(types.Id)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseToken indicates an expected call of UseToken.
+func (mr *ResetRepositoryMockRecorder) UseToken(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseToken", reflect.TypeOf((*ResetRepository)(nil).UseToken), arg0)
+}
@@ -0,0 +1,57 @@
+package reset
+
+import (
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"time"
+	"vk_film/internal/pkg/types"
+)
+
+const (
+	createToken = `
+		WITH del AS (
+			DELETE FROM password_resets WHERE user_id = $1 OR expires_at <= now()
+		)
+		INSERT INTO password_resets (token_hash, user_id, expires_at) VALUES ($2, $1, $3)
+	`
+
+	useToken = `
+		UPDATE password_resets SET used_at = now()
+			WHERE token_hash = $1 AND used_at IS NULL AND expires_at > now()
+			RETURNING user_id
+	`
+)
+
+type PostgresReset struct {
+	db *sqlx.DB
+}
+
+func NewPostgresReset(db *sqlx.DB) *PostgresReset {
+	return &PostgresReset{
+		db: db,
+	}
+}
+
+var _ = Repository(&PostgresReset{})
+
+func (pr *PostgresReset) CreateToken(userId types.Id, hash string, expiresAt time.Time) error {
+	if _, err := pr.db.Exec(createToken, userId, hash, expiresAt); err != nil {
+		return errors.Wrapf(err, "can't create password reset token of user %d", userId)
+	}
+
+	return nil
+}
+
+func (pr *PostgresReset) UseToken(hash string) (types.Id, error) {
+	var userId types.Id
+
+	if err := pr.db.QueryRowx(useToken, hash).Scan(&userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrorTokenNotFound
+		}
+		return 0, errors.Wrap(err, "can't use password reset token")
+	}
+
+	return userId, nil
+}
@@ -0,0 +1,94 @@
+package reset
+
+import (
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/pkg/errors"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+	"vk_film/internal/pkg/types"
+)
+
+var testError = errors.New("test error")
+
+type ResetRepositorySuite struct {
+	suite.Suite
+	resetRepository *PostgresReset
+	mock            sqlxmock.Sqlmock
+}
+
+func (rrs *ResetRepositorySuite) BeforeEach(t provider.T) {
+	db, mock, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	t.Require().NoError(err)
+	rrs.resetRepository = NewPostgresReset(db)
+	rrs.mock = mock
+}
+
+func (rrs *ResetRepositorySuite) AfterEach(t provider.T) {
+	t.Require().NoError(rrs.mock.ExpectationsWereMet())
+}
+
+func (rrs *ResetRepositorySuite) TestCreateTokenFunction(t provider.T) {
+	t.Title("CreateToken function of Reset repository")
+	t.NewStep("Init test data")
+	var userId types.Id = 1
+	hash := "hash"
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(createToken).WithArgs(userId, hash, expiresAt).WillReturnResult(sqlxmock.NewResult(0, 1))
+
+		t.NewStep("Check result")
+		t.Require().NoError(rrs.resetRepository.CreateToken(userId, hash, expiresAt))
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(createToken).WithArgs(userId, hash, expiresAt).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.resetRepository.CreateToken(userId, hash, expiresAt), testError)
+	})
+}
+
+func (rrs *ResetRepositorySuite) TestUseTokenFunction(t provider.T) {
+	t.Title("UseToken function of Reset repository")
+	t.NewStep("Init test data")
+	var userId types.Id = 1
+	hash := "hash"
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(useToken).WithArgs(hash).
+			WillReturnRows(sqlxmock.NewRows([]string{"user_id"}).AddRow(userId))
+
+		t.NewStep("Check result")
+		id, err := rrs.resetRepository.UseToken(hash)
+		t.Require().NoError(err)
+		t.Require().Equal(userId, id)
+	})
+
+	t.WithNewStep("Token not found execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(useToken).WithArgs(hash).WillReturnRows(sqlxmock.NewRows([]string{"user_id"}))
+
+		t.NewStep("Check result")
+		_, err := rrs.resetRepository.UseToken(hash)
+		t.Require().ErrorIs(err, ErrorTokenNotFound)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(useToken).WithArgs(hash).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := rrs.resetRepository.UseToken(hash)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func TestRunResetRepositorySuite(t *testing.T) {
+	suite.RunSuite(t, new(ResetRepositorySuite))
+}
@@ -0,0 +1,47 @@
+package role
+
+import (
+	"github.com/pkg/errors"
+	"vk_film/internal/pkg/types"
+)
+
+var (
+	ErrorRoleNotFound      = errors.New("role not found")
+	ErrorRoleAlreadyExists = errors.New("role with this name already exists")
+	ErrorRoleInUse         = errors.New("role is assigned to users")
+)
+
+//go:generate mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=RoleRepository . Repository
+
+type Repository interface {
+	// CreateRole
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorRoleAlreadyExists
+	CreateRole(role *Role) (*Role, error)
+
+	// UpdateRole
+	// Replaces the description and the permissions of the role.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorRoleNotFound
+	UpdateRole(role *Role) (*Role, error)
+
+	// DeleteRole
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorRoleNotFound
+	//   - ErrorRoleInUse
+	DeleteRole(name types.Roles) error
+
+	// GetRole
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorRoleNotFound
+	GetRole(name types.Roles) (*Role, error)
+
+	// GetRoles
+	// Returns Error:
+	//   - SQLError
+	GetRoles() ([]Role, error)
+}
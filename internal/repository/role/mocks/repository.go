@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: vk_film/internal/repository/role (interfaces: Repository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=RoleRepository . Repository
+//
+
+// Package mr is a generated GoMock package.
+package mr
+
+import (
+	reflect "reflect"
+	types "vk_film/internal/pkg/types"
+	role "vk_film/internal/repository/role"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// RoleRepository is a mock of Repository interface.
+type RoleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *RoleRepositoryMockRecorder
+}
+
+// RoleRepositoryMockRecorder is the mock recorder for RoleRepository.
+type RoleRepositoryMockRecorder struct {
+	mock *RoleRepository
+}
+
+// NewRoleRepository creates a new mock instance.
+func NewRoleRepository(ctrl *gomock.Controller) *RoleRepository {
+	mock := &RoleRepository{ctrl: ctrl}
+	mock.recorder = &RoleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *RoleRepository) EXPECT() *RoleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateRole mocks base method.
+func (m *RoleRepository) CreateRole(arg0 *role.Role) (*role.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRole", arg0)
+	ret0, _ := ret[0].(*role.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRole indicates an expected call of CreateRole.
+func (mr *RoleRepositoryMockRecorder) CreateRole(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRole", reflect.TypeOf((*RoleRepository)(nil).CreateRole), arg0)
+}
+
+// DeleteRole mocks base method.
+func (m *RoleRepository) DeleteRole(arg0 types.Roles) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRole", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRole indicates an expected call of DeleteRole.
+func (mr *RoleRepositoryMockRecorder) DeleteRole(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRole", reflect.TypeOf((*RoleRepository)(nil).DeleteRole), arg0)
+}
+
+// GetRole mocks base method.
+func (m *RoleRepository) GetRole(arg0 types.Roles) (*role.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRole", arg0)
+	ret0, _ := ret[0].(*role.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRole indicates an expected call of GetRole.
+func (mr *RoleRepositoryMockRecorder) GetRole(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRole", reflect.TypeOf((*RoleRepository)(nil).GetRole), arg0)
+}
+
+// GetRoles mocks base method.
+func (m *RoleRepository) GetRoles() ([]role.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoles")
+	ret0, _ := ret[0].([]role.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoles indicates an expected call of GetRoles.
+func (mr *RoleRepositoryMockRecorder) GetRoles() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoles", reflect.TypeOf((*RoleRepository)(nil).GetRoles))
+}
+
+// UpdateRole mocks base method.
+func (m *RoleRepository) UpdateRole(arg0 *role.Role) (*role.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", arg0)
+	ret0, _ := ret[0].(*role.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *RoleRepositoryMockRecorder) UpdateRole(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*RoleRepository)(nil).UpdateRole), arg0)
+}
@@ -0,0 +1,34 @@
+package role
+
+import (
+	"github.com/lib/pq"
+	"vk_film/internal/pkg/types"
+)
+
+// Role
+// Named set of permissions
+type Role struct {
+	Name        types.Roles
+	Description string
+	Permissions []types.Permission
+}
+
+// PermissionsArray
+// Converts permissions to the postgres array
+func PermissionsArray(permissions []types.Permission) pq.StringArray {
+	array := make(pq.StringArray, 0, len(permissions))
+	for _, permission := range permissions {
+		array = append(array, string(permission))
+	}
+	return array
+}
+
+// FromPermissionsArray
+// Converts the postgres array to permissions
+func FromPermissionsArray(array pq.StringArray) []types.Permission {
+	permissions := make([]types.Permission, 0, len(array))
+	for _, permission := range array {
+		permissions = append(permissions, types.Permission(permission))
+	}
+	return permissions
+}
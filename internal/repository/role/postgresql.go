@@ -0,0 +1,156 @@
+package role
+
+import (
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"vk_film/internal/pkg/types"
+)
+
+const (
+	createRole = `
+		INSERT INTO roles (name, description, permissions) VALUES ($1, $2, $3)
+			ON CONFLICT (name) DO NOTHING
+			RETURNING name, description, permissions
+	`
+
+	updateRole = `
+		UPDATE roles SET description = $2, permissions = $3 WHERE name = $1
+			RETURNING name, description, permissions
+	`
+
+	deleteRole = `
+		DELETE FROM roles WHERE name = $1
+	`
+
+	getRole = `
+		SELECT name, description, permissions FROM roles WHERE name = $1
+	`
+
+	getRoles = `
+		SELECT name, description, permissions FROM roles ORDER BY name
+	`
+)
+
+type PostgresRole struct {
+	db *sqlx.DB
+}
+
+func NewPostgresRole(db *sqlx.DB) *PostgresRole {
+	return &PostgresRole{
+		db: db,
+	}
+}
+
+var _ = Repository(&PostgresRole{})
+
+func scanRole(row interface{ Scan(...any) error }, role *Role) error {
+	var permissions pq.StringArray
+	if err := row.Scan(
+		&role.Name,
+		&role.Description,
+		&permissions,
+	); err != nil {
+		return err
+	}
+
+	role.Permissions = FromPermissionsArray(permissions)
+	return nil
+}
+
+func (pr *PostgresRole) CreateRole(role *Role) (*Role, error) {
+	createdRole := &Role{}
+	row := pr.db.QueryRowx(createRole, role.Name, role.Description, PermissionsArray(role.Permissions))
+	if err := scanRole(row, createdRole); err != nil {
+		// Пустой результат означает, что роль с таким названием уже есть
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrapf(ErrorRoleAlreadyExists, "with name %s", role.Name)
+		}
+		return nil, errors.Wrapf(err, "can't create role %s", role.Name)
+	}
+
+	return createdRole, nil
+}
+
+func (pr *PostgresRole) UpdateRole(role *Role) (*Role, error) {
+	updatedRole := &Role{}
+	row := pr.db.QueryRowx(updateRole, role.Name, role.Description, PermissionsArray(role.Permissions))
+	if err := scanRole(row, updatedRole); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrapf(ErrorRoleNotFound, "with name %s", role.Name)
+		}
+		return nil, errors.Wrapf(err, "can't update role %s", role.Name)
+	}
+
+	return updatedRole, nil
+}
+
+func (pr *PostgresRole) DeleteRole(name types.Roles) error {
+	res, err := pr.db.Exec(deleteRole, name)
+	if err != nil {
+		return errors.Wrapf(checkRoleInUseError(err), "can't execute deleting query for role %s", name)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrapf(err, "can't get number affected rows of deleting query for role %s", name)
+	}
+
+	if n != 1 {
+		return errors.Wrapf(ErrorRoleNotFound, "with name %s", name)
+	}
+
+	return nil
+}
+
+func (pr *PostgresRole) GetRole(name types.Roles) (*Role, error) {
+	role := &Role{}
+	if err := scanRole(pr.db.QueryRowx(getRole, name), role); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrapf(ErrorRoleNotFound, "with name %s", name)
+		}
+		return nil, errors.Wrapf(err, "can't found role %s", name)
+	}
+
+	return role, nil
+}
+
+func (pr *PostgresRole) GetRoles() ([]Role, error) {
+	rows, err := pr.db.Queryx(getRoles)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't execute get roles query")
+	}
+	defer rows.Close()
+
+	roles := make([]Role, 0)
+
+	for rows.Next() {
+		var role Role
+
+		if err := scanRole(rows, &role); err != nil {
+			return nil, errors.Wrap(err, "can't scan get roles query result")
+		}
+
+		roles = append(roles, role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "can't end scan get roles query result")
+	}
+
+	return roles, nil
+}
+
+const (
+	roleInUseCode          = "23503"
+	userRoleConstraintName = "users_role_fkey"
+)
+
+func checkRoleInUseError(err error) error {
+	var e *pq.Error
+	if errors.As(err, &e) && e.Code == roleInUseCode && e.Constraint == userRoleConstraintName {
+		return ErrorRoleInUse
+	}
+	return err
+}
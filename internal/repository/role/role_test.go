@@ -0,0 +1,249 @@
+package role
+
+import (
+	"github.com/lib/pq"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/pkg/errors"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"vk_film/internal/pkg/types"
+)
+
+var testError = errors.New("test error")
+
+type RoleRepositorySuite struct {
+	suite.Suite
+	roleRepository *PostgresRole
+	mock           sqlxmock.Sqlmock
+}
+
+func (rrs *RoleRepositorySuite) BeforeEach(t provider.T) {
+	db, mock, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	t.Require().NoError(err)
+	rrs.roleRepository = NewPostgresRole(db)
+	rrs.mock = mock
+}
+
+func (rrs *RoleRepositorySuite) AfterEach(t provider.T) {
+	t.Require().NoError(rrs.mock.ExpectationsWereMet())
+}
+
+var roleColumns = []string{"name", "description", "permissions"}
+
+func newTestRole() *Role {
+	return &Role{
+		Name:        "editor",
+		Description: "Catalogue editor",
+		Permissions: []types.Permission{types.FilmWrite, types.ActorWrite},
+	}
+}
+
+const testPermissions = "{film:write,actor:write}"
+
+func (rrs *RoleRepositorySuite) TestCreateFunction(t provider.T) {
+	t.Title("CreateRole function of Role repository")
+	t.NewStep("Init test data")
+	role := newTestRole()
+	permissions := PermissionsArray(role.Permissions)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(createRole).
+			WithArgs(role.Name, role.Description, permissions).
+			WillReturnRows(sqlxmock.NewRows(roleColumns).AddRow(role.Name, role.Description, testPermissions))
+
+		t.NewStep("Check result")
+		createdRole, err := rrs.roleRepository.CreateRole(role)
+		t.Require().NoError(err)
+		t.Require().EqualValues(role, createdRole)
+	})
+
+	t.WithNewStep("Role already exists in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(createRole).
+			WithArgs(role.Name, role.Description, permissions).
+			WillReturnRows(sqlxmock.NewRows(roleColumns))
+
+		t.NewStep("Check result")
+		_, err := rrs.roleRepository.CreateRole(role)
+		t.Require().ErrorIs(err, ErrorRoleAlreadyExists)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(createRole).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := rrs.roleRepository.CreateRole(role)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (rrs *RoleRepositorySuite) TestUpdateFunction(t provider.T) {
+	t.Title("UpdateRole function of Role repository")
+	t.NewStep("Init test data")
+	role := newTestRole()
+	permissions := PermissionsArray(role.Permissions)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(updateRole).
+			WithArgs(role.Name, role.Description, permissions).
+			WillReturnRows(sqlxmock.NewRows(roleColumns).AddRow(role.Name, role.Description, testPermissions))
+
+		t.NewStep("Check result")
+		updatedRole, err := rrs.roleRepository.UpdateRole(role)
+		t.Require().NoError(err)
+		t.Require().EqualValues(role, updatedRole)
+	})
+
+	t.WithNewStep("Role not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(updateRole).
+			WithArgs(role.Name, role.Description, permissions).
+			WillReturnRows(sqlxmock.NewRows(roleColumns))
+
+		t.NewStep("Check result")
+		_, err := rrs.roleRepository.UpdateRole(role)
+		t.Require().ErrorIs(err, ErrorRoleNotFound)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(updateRole).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := rrs.roleRepository.UpdateRole(role)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (rrs *RoleRepositorySuite) TestDeleteFunction(t provider.T) {
+	t.Title("DeleteRole function of Role repository")
+	t.NewStep("Init test data")
+	name := types.Roles("editor")
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(deleteRole).WithArgs(name).WillReturnResult(sqlxmock.NewResult(0, 1))
+
+		t.NewStep("Check result")
+		t.Require().NoError(rrs.roleRepository.DeleteRole(name))
+	})
+
+	t.WithNewStep("Role not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(deleteRole).WithArgs(name).WillReturnResult(sqlxmock.NewResult(0, 0))
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.roleRepository.DeleteRole(name), ErrorRoleNotFound)
+	})
+
+	t.WithNewStep("Role in use in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(deleteRole).
+			WithArgs(name).
+			WillReturnError(&pq.Error{Code: roleInUseCode, Constraint: userRoleConstraintName})
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.roleRepository.DeleteRole(name), ErrorRoleInUse)
+	})
+
+	t.WithNewStep("Row affected error of execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(deleteRole).WithArgs(name).WillReturnResult(sqlxmock.NewErrorResult(testError))
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.roleRepository.DeleteRole(name), testError)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectExec(deleteRole).WithArgs(name).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.roleRepository.DeleteRole(name), testError)
+	})
+}
+
+func (rrs *RoleRepositorySuite) TestGetFunction(t provider.T) {
+	t.Title("GetRole function of Role repository")
+	t.NewStep("Init test data")
+	role := newTestRole()
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(getRole).
+			WithArgs(role.Name).
+			WillReturnRows(sqlxmock.NewRows(roleColumns).AddRow(role.Name, role.Description, testPermissions))
+
+		t.NewStep("Check result")
+		res, err := rrs.roleRepository.GetRole(role.Name)
+		t.Require().NoError(err)
+		t.Require().EqualValues(role, res)
+	})
+
+	t.WithNewStep("Role not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(getRole).WithArgs(role.Name).WillReturnRows(sqlxmock.NewRows(roleColumns))
+
+		t.NewStep("Check result")
+		_, err := rrs.roleRepository.GetRole(role.Name)
+		t.Require().ErrorIs(err, ErrorRoleNotFound)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(getRole).WithArgs(role.Name).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := rrs.roleRepository.GetRole(role.Name)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (rrs *RoleRepositorySuite) TestGetAllFunction(t provider.T) {
+	t.Title("GetRoles function of Role repository")
+	t.NewStep("Init test data")
+	role := newTestRole()
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(getRoles).
+			WillReturnRows(
+				sqlxmock.NewRows(roleColumns).
+					AddRow(role.Name, role.Description, testPermissions).
+					AddRow(types.USER, "", "{}"),
+			)
+
+		t.NewStep("Check result")
+		roles, err := rrs.roleRepository.GetRoles()
+		t.Require().NoError(err)
+		t.Require().EqualValues([]Role{*role, {Name: types.USER, Permissions: []types.Permission{}}}, roles)
+	})
+
+	t.WithNewStep("Scan error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(getRoles).
+			WillReturnRows(sqlxmock.NewRows(roleColumns).AddRow(role.Name, role.Description, 5)).
+			RowsWillBeClosed()
+
+		t.NewStep("Check result")
+		_, err := rrs.roleRepository.GetRoles()
+		t.Require().Error(err)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectQuery(getRoles).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := rrs.roleRepository.GetRoles()
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func TestRunRoleRepositorySuite(t *testing.T) {
+	suite.RunSuite(t, new(RoleRepositorySuite))
+}
@@ -0,0 +1,330 @@
+package session
+
+import (
+	"github.com/google/uuid"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/redis/go-redis/v9"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+	"vk_film/internal/pkg/types"
+)
+
+// redisTestURL
+// Переменная окружения со строкой подключения к отдельной базе Redis 7 для поведенческих тестов
+const redisTestURL = "REDIS_TEST_URL"
+
+// testClock
+// Часы хранилища: для хранилища в памяти время переводится мгновенно,
+// реальный Redis отсчитывает время жизни ключей сам, поэтому для него приходится ждать
+type testClock interface {
+	Now() time.Time
+	Advance(d time.Duration)
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (fc *fakeClock) Now() time.Time {
+	return fc.now
+}
+
+func (fc *fakeClock) Advance(d time.Duration) {
+	fc.now = fc.now.Add(d)
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Advance(d time.Duration) {
+	time.Sleep(d)
+}
+
+var lastUserId = atomic.Uint64{}
+
+func init() {
+	// Пользователи разных запусков не пересекаются в общей базе Redis
+	lastUserId.Store(uint64(time.Now().UnixNano()))
+}
+
+func nextUserId() types.Id {
+	return types.Id(lastUserId.Add(1))
+}
+
+// RepositorySuite
+// Поведенческие тесты, которые должна проходить каждая реализация Repository
+type RepositorySuite struct {
+	suite.Suite
+	clock         testClock
+	newRepository func(now func() time.Time) (Repository, func())
+
+	repository Repository
+	close      func()
+}
+
+func (rs *RepositorySuite) BeforeEach(t provider.T) {
+	rs.repository, rs.close = rs.newRepository(rs.clock.Now)
+}
+
+func (rs *RepositorySuite) AfterEach(t provider.T) {
+	rs.close()
+}
+
+// newSession
+// Создаёт сессию пользователя и возвращает её идентификатор
+func (rs *RepositorySuite) newSession(t provider.StepCtx, userId types.Id, idleTimeout, maxLifetime time.Duration) string {
+	sessionId := uuid.New().String()
+	t.Require().NoError(rs.repository.Set(sessionId, &Session{
+		UserID:      userId,
+		IP:          "127.0.0.1",
+		UserAgent:   "curl/8.0",
+		IdleTimeout: idleTimeout,
+		ExpiresAt:   rs.clock.Now().Add(maxLifetime),
+	}))
+	return sessionId
+}
+
+func (rs *RepositorySuite) TestSetAndGet(t provider.T) {
+	t.Title("Set and GetUserId of session repository")
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		userId := nextUserId()
+		sessionId := rs.newSession(t, userId, 2*time.Second, 10*time.Second)
+
+		res, err := rs.repository.GetUserId(sessionId)
+		t.Require().NoError(err)
+		t.Require().Equal(userId, res)
+	})
+
+	t.WithNewStep("Unknown session execute", func(t provider.StepCtx) {
+		_, err := rs.repository.GetUserId(uuid.New().String())
+		t.Require().ErrorIs(err, ErrorNoSession)
+	})
+}
+
+func (rs *RepositorySuite) TestIdleTimeout(t provider.T) {
+	t.Title("Idle timeout of session repository")
+	t.NewStep("Init test data")
+	userId := nextUserId()
+	var sessionId string
+	t.WithNewStep("Create session", func(t provider.StepCtx) {
+		sessionId = rs.newSession(t, userId, 2*time.Second, 10*time.Second)
+	})
+
+	t.WithNewStep("Requests extend session", func(t provider.StepCtx) {
+		rs.clock.Advance(time.Second)
+		_, err := rs.repository.GetUserId(sessionId)
+		t.Require().NoError(err)
+
+		rs.clock.Advance(1500 * time.Millisecond)
+		res, err := rs.repository.GetUserId(sessionId)
+		t.Require().NoError(err)
+		t.Require().Equal(userId, res)
+	})
+
+	t.WithNewStep("Idle session expires", func(t provider.StepCtx) {
+		rs.clock.Advance(2500 * time.Millisecond)
+		_, err := rs.repository.GetUserId(sessionId)
+		t.Require().ErrorIs(err, ErrorNoSession)
+
+		sessions, err := rs.repository.GetUserSessions(userId)
+		t.Require().NoError(err)
+		t.Require().Empty(sessions)
+	})
+}
+
+func (rs *RepositorySuite) TestMaxLifetime(t provider.T) {
+	t.Title("Absolute lifetime of session repository")
+	t.NewStep("Init test data")
+	userId := nextUserId()
+	var sessionId string
+	t.WithNewStep("Create session", func(t provider.StepCtx) {
+		sessionId = rs.newSession(t, userId, 2*time.Second, 3*time.Second)
+	})
+
+	t.WithNewStep("Session is active before expiration", func(t provider.StepCtx) {
+		rs.clock.Advance(1500 * time.Millisecond)
+		_, err := rs.repository.GetUserId(sessionId)
+		t.Require().NoError(err)
+	})
+
+	t.WithNewStep("Requests don't extend session beyond expiration", func(t provider.StepCtx) {
+		rs.clock.Advance(1500 * time.Millisecond)
+		_, err := rs.repository.GetUserId(sessionId)
+		t.Require().ErrorIs(err, ErrorNoSession)
+	})
+}
+
+func (rs *RepositorySuite) TestGetUserSessions(t provider.T) {
+	t.Title("GetUserSessions of session repository")
+	t.NewStep("Init test data")
+	userId := nextUserId()
+	var first, second string
+	t.WithNewStep("Create sessions", func(t provider.StepCtx) {
+		first = rs.newSession(t, userId, 2*time.Second, 10*time.Second)
+		rs.clock.Advance(time.Second)
+		second = rs.newSession(t, userId, 5*time.Second, 10*time.Second)
+		rs.newSession(t, nextUserId(), 5*time.Second, 10*time.Second)
+	})
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		sessions, err := rs.repository.GetUserSessions(userId)
+		t.Require().NoError(err)
+		t.Require().Len(sessions, 2)
+
+		t.Require().Equal(PublicId(first), sessions[0].ID)
+		t.Require().Equal(PublicId(second), sessions[1].ID)
+		for _, s := range sessions {
+			t.Require().Equal(userId, s.UserID)
+			t.Require().Equal("127.0.0.1", s.IP)
+			t.Require().Equal("curl/8.0", s.UserAgent)
+			t.Require().False(s.Current)
+		}
+		t.Require().Equal(5*time.Second, sessions[1].IdleTimeout)
+		t.Require().WithinDuration(rs.clock.Now(), sessions[1].CreatedAt, time.Second)
+		t.Require().WithinDuration(rs.clock.Now().Add(10*time.Second), sessions[1].ExpiresAt, time.Second)
+	})
+
+	t.WithNewStep("Expired session execute", func(t provider.StepCtx) {
+		rs.clock.Advance(1500 * time.Millisecond)
+
+		sessions, err := rs.repository.GetUserSessions(userId)
+		t.Require().NoError(err)
+		t.Require().Len(sessions, 1)
+		t.Require().Equal(PublicId(second), sessions[0].ID)
+	})
+}
+
+func (rs *RepositorySuite) TestDel(t provider.T) {
+	t.Title("Del of session repository")
+	t.NewStep("Init test data")
+	userId := nextUserId()
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		sessionId := rs.newSession(t, userId, 2*time.Second, 10*time.Second)
+		t.Require().NoError(rs.repository.Del(sessionId))
+
+		_, err := rs.repository.GetUserId(sessionId)
+		t.Require().ErrorIs(err, ErrorNoSession)
+
+		sessions, err := rs.repository.GetUserSessions(userId)
+		t.Require().NoError(err)
+		t.Require().Empty(sessions)
+	})
+
+	t.WithNewStep("Unknown session execute", func(t provider.StepCtx) {
+		t.Require().NoError(rs.repository.Del(uuid.New().String()))
+	})
+}
+
+func (rs *RepositorySuite) TestDelUserSession(t provider.T) {
+	t.Title("DelUserSession of session repository")
+	t.NewStep("Init test data")
+	userId := nextUserId()
+	otherId := nextUserId()
+	var first, second, other string
+	t.WithNewStep("Create sessions", func(t provider.StepCtx) {
+		first = rs.newSession(t, userId, 2*time.Second, 10*time.Second)
+		second = rs.newSession(t, userId, 2*time.Second, 10*time.Second)
+		other = rs.newSession(t, otherId, 2*time.Second, 10*time.Second)
+	})
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.Require().NoError(rs.repository.DelUserSession(userId, PublicId(first)))
+
+		_, err := rs.repository.GetUserId(first)
+		t.Require().ErrorIs(err, ErrorNoSession)
+		_, err = rs.repository.GetUserId(second)
+		t.Require().NoError(err)
+	})
+
+	t.WithNewStep("Unknown session execute", func(t provider.StepCtx) {
+		err := rs.repository.DelUserSession(userId, PublicId(uuid.New().String()))
+		t.Require().ErrorIs(err, ErrorNoSession)
+	})
+
+	t.WithNewStep("Session of other user execute", func(t provider.StepCtx) {
+		err := rs.repository.DelUserSession(userId, PublicId(other))
+		t.Require().ErrorIs(err, ErrorNoSession)
+
+		_, err = rs.repository.GetUserId(other)
+		t.Require().NoError(err)
+	})
+}
+
+func (rs *RepositorySuite) TestDelUserSessions(t provider.T) {
+	t.Title("DelUserSessions of session repository")
+	t.NewStep("Init test data")
+	userId := nextUserId()
+	otherId := nextUserId()
+	var current, first, second, other string
+	t.WithNewStep("Create sessions", func(t provider.StepCtx) {
+		current = rs.newSession(t, userId, 2*time.Second, 10*time.Second)
+		first = rs.newSession(t, userId, 2*time.Second, 10*time.Second)
+		second = rs.newSession(t, userId, 2*time.Second, 10*time.Second)
+		other = rs.newSession(t, otherId, 2*time.Second, 10*time.Second)
+	})
+
+	t.WithNewStep("Except current execute", func(t provider.StepCtx) {
+		t.Require().NoError(rs.repository.DelUserSessions(userId, current))
+
+		for _, sessionId := range []string{first, second} {
+			_, err := rs.repository.GetUserId(sessionId)
+			t.Require().ErrorIs(err, ErrorNoSession)
+		}
+		_, err := rs.repository.GetUserId(current)
+		t.Require().NoError(err)
+		_, err = rs.repository.GetUserId(other)
+		t.Require().NoError(err)
+	})
+
+	t.WithNewStep("All sessions execute", func(t provider.StepCtx) {
+		t.Require().NoError(rs.repository.DelUserSessions(userId, ""))
+
+		_, err := rs.repository.GetUserId(current)
+		t.Require().ErrorIs(err, ErrorNoSession)
+
+		sessions, err := rs.repository.GetUserSessions(userId)
+		t.Require().NoError(err)
+		t.Require().Empty(sessions)
+	})
+}
+
+func TestRunMemoryBehaviourSuite(t *testing.T) {
+	suite.RunSuite(t, &RepositorySuite{
+		clock: &fakeClock{now: testNow},
+		newRepository: func(now func() time.Time) (Repository, func()) {
+			repository := NewMemorySession(0)
+			repository.now = now
+			return repository, repository.Close
+		},
+	})
+}
+
+func TestRunRedisBehaviourSuite(t *testing.T) {
+	url := os.Getenv(redisTestURL)
+	if url == "" {
+		t.Skipf("%s is not set", redisTestURL)
+	}
+
+	opt, err := redis.ParseURL(url)
+	if err != nil {
+		t.Fatalf("parse %s: %s", redisTestURL, err)
+	}
+
+	suite.RunSuite(t, &RepositorySuite{
+		clock: realClock{},
+		newRepository: func(now func() time.Time) (Repository, func()) {
+			client := redis.NewClient(opt)
+			repository := NewRedisSession(client)
+			repository.now = now
+			return repository, func() { _ = client.Close() }
+		},
+	})
+}
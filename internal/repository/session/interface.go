@@ -2,16 +2,45 @@ package session
 
 import (
 	"github.com/pkg/errors"
-	"time"
 	"vk_film/internal/pkg/types"
 )
 
 var ErrorNoSession = errors.New("session was not found")
 
+const (
+	RedisBackend    = "redis"
+	PostgresBackend = "postgres"
+	MemoryBackend   = "memory"
+)
+
 //go:generate mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=SessionRepository . Repository
 
 type Repository interface {
-	Set(sessionId string, userId types.Id, expiredTime time.Duration) error
-	GetUserId(sessionId string, updateExpiredTime time.Duration) (types.Id, error)
+	// Set
+	// Creates the session of info.UserID with the client metadata and the lifetime of info.
+	// Creation and last seen times are set to the current time.
+	Set(sessionId string, info *Session) error
+
+	// GetUserId
+	// Updates the last seen time of the session and extends it by its idle timeout,
+	// but not beyond its absolute expiration time.
+	// Returns Error:
+	//   - ErrorNoSession
+	GetUserId(sessionId string) (types.Id, error)
 	Del(sessionId string) error
+
+	// GetUserSessions
+	// Returns active sessions of the user ordered by creation time.
+	GetUserSessions(userId types.Id) ([]Session, error)
+
+	// DelUserSession
+	// Deletes the session of the user by its public identifier.
+	// Returns Error:
+	//   - ErrorNoSession
+	DelUserSession(userId types.Id, publicId string) error
+
+	// DelUserSessions
+	// Deletes all sessions of the user except exceptSessionId.
+	// Empty exceptSessionId means that all sessions of the user are deleted.
+	DelUserSessions(userId types.Id, exceptSessionId string) error
 }
@@ -0,0 +1,224 @@
+package session
+
+import (
+	"github.com/pkg/errors"
+	"slices"
+	"sync"
+	"time"
+	"vk_film/internal/pkg/types"
+)
+
+// MemorySession
+// Stores sessions in the memory of the process, for development and tests only:
+// sessions are lost on restart and are not shared between instances.
+// Expired sessions are not returned and are evicted in the background every evictInterval.
+type MemorySession struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+	users    map[types.Id]map[string]struct{}
+	now      func() time.Time
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewMemorySession
+// Non-positive evictInterval disables the background eviction, expired sessions
+// are then removed only when they are requested
+func NewMemorySession(evictInterval time.Duration) *MemorySession {
+	ms := &MemorySession{
+		sessions: make(map[string]*Session),
+		users:    make(map[types.Id]map[string]struct{}),
+		now:      time.Now,
+		done:     make(chan struct{}),
+	}
+
+	if evictInterval > 0 {
+		go ms.evictLoop(evictInterval)
+	}
+	return ms
+}
+
+var _ = Repository(&MemorySession{})
+
+// Close
+// Stops the background eviction
+func (ms *MemorySession) Close() {
+	ms.closeOnce.Do(func() { close(ms.done) })
+}
+
+func (ms *MemorySession) Set(sessionId string, info *Session) error {
+	now := ms.now()
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	// Повторная запись сессии с тем же идентификатором заменяет прежнюю
+	if old, found := ms.sessions[sessionId]; found {
+		ms.delSessions(old.UserID, []string{sessionId})
+	}
+
+	ms.sessions[sessionId] = &Session{
+		ID:          PublicId(sessionId),
+		UserID:      info.UserID,
+		CreatedAt:   now,
+		LastSeenAt:  now,
+		ExpiresAt:   info.ExpiresAt,
+		IdleTimeout: info.IdleTimeout,
+		IP:          info.IP,
+		UserAgent:   info.UserAgent,
+	}
+
+	if ms.users[info.UserID] == nil {
+		ms.users[info.UserID] = make(map[string]struct{})
+	}
+	ms.users[info.UserID][sessionId] = struct{}{}
+	return nil
+}
+
+func (ms *MemorySession) GetUserId(sessionId string) (types.Id, error) {
+	now := ms.now()
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	info, found := ms.alive(sessionId, now)
+	if !found {
+		return 0, errors.Wrapf(ErrorNoSession,
+			"error when try found session with sessionId: %s", sessionId)
+	}
+
+	info.LastSeenAt = now
+	return info.UserID, nil
+}
+
+func (ms *MemorySession) Del(sessionId string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if info, found := ms.sessions[sessionId]; found {
+		ms.delSessions(info.UserID, []string{sessionId})
+	}
+	return nil
+}
+
+func (ms *MemorySession) GetUserSessions(userId types.Id) ([]Session, error) {
+	now := ms.now()
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	sessions := make([]Session, 0, len(ms.users[userId]))
+	for sessionId := range ms.users[userId] {
+		if info, found := ms.alive(sessionId, now); found {
+			sessions = append(sessions, *info)
+		}
+	}
+
+	slices.SortFunc(sessions, func(a, b Session) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return sessions, nil
+}
+
+func (ms *MemorySession) DelUserSession(userId types.Id, publicId string) error {
+	now := ms.now()
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for sessionId := range ms.users[userId] {
+		if PublicId(sessionId) != publicId {
+			continue
+		}
+
+		if _, found := ms.alive(sessionId, now); found {
+			ms.delSessions(userId, []string{sessionId})
+			return nil
+		}
+	}
+
+	return errors.Wrapf(ErrorNoSession, "with public id %s of user %d", publicId, userId)
+}
+
+func (ms *MemorySession) DelUserSessions(userId types.Id, exceptSessionId string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	deleted := make([]string, 0, len(ms.users[userId]))
+	for sessionId := range ms.users[userId] {
+		if sessionId != exceptSessionId {
+			deleted = append(deleted, sessionId)
+		}
+	}
+
+	ms.delSessions(userId, deleted)
+	return nil
+}
+
+// alive
+// Возвращает сессию, если она ещё не истекла, истёкшая сессия удаляется.
+// Вызывается под блокировкой
+func (ms *MemorySession) alive(sessionId string, now time.Time) (*Session, bool) {
+	info, found := ms.sessions[sessionId]
+	if !found {
+		return nil, false
+	}
+
+	if expired(info, now) {
+		ms.delSessions(info.UserID, []string{sessionId})
+		return nil, false
+	}
+	return info, true
+}
+
+// delSessions
+// Удаляет сессии и убирает их из множества сессий пользователя. Вызывается под блокировкой
+func (ms *MemorySession) delSessions(userId types.Id, sessionIds []string) {
+	for _, sessionId := range sessionIds {
+		delete(ms.sessions, sessionId)
+		delete(ms.users[userId], sessionId)
+	}
+
+	if len(ms.users[userId]) == 0 {
+		delete(ms.users, userId)
+	}
+}
+
+// evict
+// Удаляет все истёкшие сессии
+func (ms *MemorySession) evict() {
+	now := ms.now()
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for sessionId, info := range ms.sessions {
+		if expired(info, now) {
+			ms.delSessions(info.UserID, []string{sessionId})
+		}
+	}
+}
+
+// evictLoop
+// Периодически удаляет истёкшие сессии до вызова Close
+func (ms *MemorySession) evictLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ms.evict()
+		case <-ms.done:
+			return
+		}
+	}
+}
+
+// expired
+// Сессия истекла, если с последнего обращения прошло больше её времени жизни
+func expired(info *Session, now time.Time) bool {
+	return now.Sub(info.LastSeenAt) >= info.ttl(info.LastSeenAt)
+}
@@ -0,0 +1,83 @@
+package session
+
+import (
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"testing"
+	"time"
+)
+
+type MemoryRepositorySuite struct {
+	suite.Suite
+}
+
+// count
+// Число сессий и пользователей в хранилище
+func count(ms *MemorySession) (int, int) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	return len(ms.sessions), len(ms.users)
+}
+
+func (mrs *MemoryRepositorySuite) TestEvict(t provider.T) {
+	t.Title("Eviction of expired sessions from memory repository")
+	t.NewStep("Init test data")
+	clock := &fakeClock{now: testNow}
+	repository := NewMemorySession(0)
+	repository.now = clock.Now
+	defer repository.Close()
+
+	t.Require().NoError(repository.Set("idle", &Session{UserID: 1, IdleTimeout: time.Minute, ExpiresAt: testNow.Add(time.Hour)}))
+	t.Require().NoError(repository.Set("active", &Session{UserID: 2, IdleTimeout: time.Hour, ExpiresAt: testNow.Add(time.Hour)}))
+
+	t.WithNewStep("Nothing expired execute", func(t provider.StepCtx) {
+		repository.evict()
+
+		sessions, users := count(repository)
+		t.Require().Equal(2, sessions)
+		t.Require().Equal(2, users)
+	})
+
+	t.WithNewStep("Idle session execute", func(t provider.StepCtx) {
+		clock.Advance(time.Minute)
+		repository.evict()
+
+		sessions, users := count(repository)
+		t.Require().Equal(1, sessions)
+		t.Require().Equal(1, users)
+	})
+
+	t.WithNewStep("Expired session execute", func(t provider.StepCtx) {
+		clock.Advance(time.Hour)
+		repository.evict()
+
+		sessions, users := count(repository)
+		t.Require().Zero(sessions)
+		t.Require().Zero(users)
+	})
+}
+
+func (mrs *MemoryRepositorySuite) TestBackgroundEvict(t provider.T) {
+	t.Title("Background eviction of memory repository")
+	t.NewStep("Init test data")
+	repository := NewMemorySession(10 * time.Millisecond)
+	defer repository.Close()
+
+	t.Require().NoError(repository.Set("id", &Session{
+		UserID:      1,
+		IdleTimeout: time.Millisecond,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}))
+
+	t.NewStep("Check result")
+	deadline := time.Now().Add(time.Second)
+	for sessions, _ := count(repository); sessions != 0; sessions, _ = count(repository) {
+		t.Require().True(time.Now().Before(deadline), "expired session was not evicted")
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRunMemoryRepositorySuite(t *testing.T) {
+	suite.RunSuite(t, new(MemoryRepositorySuite))
+}
@@ -11,8 +11,8 @@ package mr
 
 import (
 	reflect "reflect"
-	time "time"
 	types "vk_film/internal/pkg/types"
+	session "vk_film/internal/repository/session"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -54,31 +54,74 @@ func (mr *SessionRepositoryMockRecorder) Del(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*SessionRepository)(nil).Del), arg0)
 }
 
+// DelUserSession mocks base method.
+func (m *SessionRepository) DelUserSession(arg0 types.Id, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DelUserSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DelUserSession indicates an expected call of DelUserSession.
+func (mr *SessionRepositoryMockRecorder) DelUserSession(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DelUserSession", reflect.TypeOf((*SessionRepository)(nil).DelUserSession), arg0, arg1)
+}
+
+// DelUserSessions mocks base method.
+func (m *SessionRepository) DelUserSessions(arg0 types.Id, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DelUserSessions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DelUserSessions indicates an expected call of DelUserSessions.
+func (mr *SessionRepositoryMockRecorder) DelUserSessions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DelUserSessions", reflect.TypeOf((*SessionRepository)(nil).DelUserSessions), arg0, arg1)
+}
+
 // GetUserId mocks base method.
-func (m *SessionRepository) GetUserId(arg0 string, arg1 time.Duration) (types.Id, error) {
+func (m *SessionRepository) GetUserId(arg0 string) (types.Id, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserId", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetUserId", arg0)
 	ret0, _ := ret[0].(types.Id)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserId indicates an expected call of GetUserId.
-func (mr *SessionRepositoryMockRecorder) GetUserId(arg0, arg1 any) *gomock.Call {
+func (mr *SessionRepositoryMockRecorder) GetUserId(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserId", reflect.TypeOf((*SessionRepository)(nil).GetUserId), arg0)
+}
+
+// GetUserSessions mocks base method.
+func (m *SessionRepository) GetUserSessions(arg0 types.Id) ([]session.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSessions", arg0)
+	ret0, _ := ret[0].([]session.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSessions indicates an expected call of GetUserSessions.
+func (mr *SessionRepositoryMockRecorder) GetUserSessions(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserId", reflect.TypeOf((*SessionRepository)(nil).GetUserId), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSessions", reflect.TypeOf((*SessionRepository)(nil).GetUserSessions), arg0)
 }
 
 // Set mocks base method.
-func (m *SessionRepository) Set(arg0 string, arg1 types.Id, arg2 time.Duration) error {
+func (m *SessionRepository) Set(arg0 string, arg1 *session.Session) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Set", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "Set", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Set indicates an expected call of Set.
-func (mr *SessionRepositoryMockRecorder) Set(arg0, arg1, arg2 any) *gomock.Call {
+func (mr *SessionRepositoryMockRecorder) Set(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*SessionRepository)(nil).Set), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*SessionRepository)(nil).Set), arg0, arg1)
 }
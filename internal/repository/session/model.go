@@ -0,0 +1,45 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+	"vk_film/internal/pkg/types"
+)
+
+const publicIdLength = 16
+
+// Session
+// Metadata of the session. ID is the public identifier of the session,
+// it can't be used for authorization. Current is set by the use-case layer.
+// The session ends after IdleTimeout without requests or at ExpiresAt, whichever comes first.
+type Session struct {
+	ID          string
+	UserID      types.Id
+	CreatedAt   time.Time
+	LastSeenAt  time.Time
+	ExpiresAt   time.Time
+	IdleTimeout time.Duration
+	IP          string
+	UserAgent   string
+	Current     bool
+}
+
+// ttl
+// Время жизни сессии от момента now: не дольше таймаута бездействия и не позже абсолютного срока
+func (s *Session) ttl(now time.Time) time.Duration {
+	return min(s.IdleTimeout, s.ExpiresAt.Sub(now))
+}
+
+// PublicId
+// Returns the public identifier of the session
+func PublicId(sessionId string) string {
+	return sessionHash(sessionId)[:publicIdLength]
+}
+
+// sessionHash
+// Хеш идентификатора сессии, начало хеша служит её публичным идентификатором
+func sessionHash(sessionId string) string {
+	hash := sha256.Sum256([]byte(sessionId))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,287 @@
+package session
+
+import (
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+	"vk_film/internal/pkg/types"
+)
+
+type PostgresRepositorySuite struct {
+	suite.Suite
+	postgresRepository *PostgresSession
+	mock               sqlxmock.Sqlmock
+}
+
+func (prs *PostgresRepositorySuite) BeforeEach(t provider.T) {
+	db, mock, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	t.Require().NoError(err)
+	prs.postgresRepository = NewPostgresSession(db)
+	prs.mock = mock
+}
+
+func (prs *PostgresRepositorySuite) AfterEach(t provider.T) {
+	t.Require().NoError(prs.mock.ExpectationsWereMet())
+}
+
+func (prs *PostgresRepositorySuite) TestSetFunction(t provider.T) {
+	t.Title("Set function of Postgres repository")
+	t.NewStep("Init test data")
+	sessionId := "id"
+	info := &Session{
+		UserID:      1,
+		IP:          "127.0.0.1",
+		UserAgent:   "curl/8.0",
+		IdleTimeout: time.Hour,
+		ExpiresAt:   testNow.Add(24 * time.Hour),
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		prs.mock.ExpectExec(createSession).
+			WithArgs(sessionHash(sessionId), info.UserID, int64(3600), info.ExpiresAt, info.IP, info.UserAgent).
+			WillReturnResult(sqlxmock.NewResult(0, 1))
+
+		t.NewStep("Check result")
+		t.Require().NoError(prs.postgresRepository.Set(sessionId, info))
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		prs.mock.ExpectExec(createSession).
+			WithArgs(sessionHash(sessionId), info.UserID, int64(3600), info.ExpiresAt, info.IP, info.UserAgent).
+			WillReturnError(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(prs.postgresRepository.Set(sessionId, info), testError)
+	})
+}
+
+func (prs *PostgresRepositorySuite) TestGetUserIdFunction(t provider.T) {
+	t.Title("GetUserId function of Postgres repository")
+	t.NewStep("Init test data")
+	sessionId := "id"
+	var userId types.Id = 1
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		prs.mock.ExpectQuery(getUserId).WithArgs(sessionHash(sessionId)).
+			WillReturnRows(sqlxmock.NewRows([]string{"user_id"}).AddRow(userId))
+
+		t.NewStep("Check result")
+		res, err := prs.postgresRepository.GetUserId(sessionId)
+		t.Require().NoError(err)
+		t.Require().Equal(userId, res)
+	})
+
+	t.WithNewStep("Not found or expired session execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		prs.mock.ExpectQuery(getUserId).WithArgs(sessionHash(sessionId)).
+			WillReturnRows(sqlxmock.NewRows([]string{"user_id"}))
+
+		t.NewStep("Check result")
+		_, err := prs.postgresRepository.GetUserId(sessionId)
+		t.Require().ErrorIs(err, ErrorNoSession)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		prs.mock.ExpectQuery(getUserId).WithArgs(sessionHash(sessionId)).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := prs.postgresRepository.GetUserId(sessionId)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (prs *PostgresRepositorySuite) TestDelFunction(t provider.T) {
+	t.Title("Del function of Postgres repository")
+	t.NewStep("Init test data")
+	sessionId := "id"
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		prs.mock.ExpectExec(delSession).WithArgs(sessionHash(sessionId)).WillReturnResult(sqlxmock.NewResult(0, 1))
+
+		t.NewStep("Check result")
+		t.Require().NoError(prs.postgresRepository.Del(sessionId))
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		prs.mock.ExpectExec(delSession).WithArgs(sessionHash(sessionId)).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(prs.postgresRepository.Del(sessionId), testError)
+	})
+}
+
+func (prs *PostgresRepositorySuite) TestGetUserSessionsFunction(t provider.T) {
+	t.Title("GetUserSessions function of Postgres repository")
+	t.NewStep("Init test data")
+	var userId types.Id = 1
+	columns := []string{"session_hash", "user_id", "created_at", "last_seen_at", "expires_at", "idle_timeout",
+		"ip", "user_agent"}
+	expected := []Session{
+		{
+			ID:          PublicId("first"),
+			UserID:      userId,
+			CreatedAt:   testNow,
+			LastSeenAt:  testNow.Add(time.Minute),
+			ExpiresAt:   testNow.Add(24 * time.Hour),
+			IdleTimeout: time.Hour,
+			IP:          "127.0.0.1",
+			UserAgent:   "curl/8.0",
+		},
+		{
+			ID:          PublicId("second"),
+			UserID:      userId,
+			CreatedAt:   testNow.Add(time.Hour),
+			LastSeenAt:  testNow.Add(time.Hour),
+			ExpiresAt:   testNow.Add(25 * time.Hour),
+			IdleTimeout: 2 * time.Hour,
+			IP:          "10.0.0.1",
+			UserAgent:   "Mozilla/5.0",
+		},
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rows := sqlxmock.NewRows(columns)
+		for i, sessionId := range []string{"first", "second"} {
+			s := expected[i]
+			rows.AddRow(sessionHash(sessionId), s.UserID, s.CreatedAt, s.LastSeenAt, s.ExpiresAt,
+				int64(s.IdleTimeout.Seconds()), s.IP, s.UserAgent)
+		}
+		prs.mock.ExpectQuery(getUserSessions).WithArgs(userId).WillReturnRows(rows)
+
+		t.NewStep("Check result")
+		sessions, err := prs.postgresRepository.GetUserSessions(userId)
+		t.Require().NoError(err)
+		t.Require().Equal(expected, sessions)
+	})
+
+	t.WithNewStep("Empty execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		prs.mock.ExpectQuery(getUserSessions).WithArgs(userId).WillReturnRows(sqlxmock.NewRows(columns))
+
+		t.NewStep("Check result")
+		sessions, err := prs.postgresRepository.GetUserSessions(userId)
+		t.Require().NoError(err)
+		t.Require().Empty(sessions)
+	})
+
+	t.WithNewStep("Scan error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		prs.mock.ExpectQuery(getUserSessions).WithArgs(userId).
+			WillReturnRows(sqlxmock.NewRows(columns).AddRow("first", "user", nil, nil, nil, nil, "", "")).
+			RowsWillBeClosed()
+
+		t.NewStep("Check result")
+		_, err := prs.postgresRepository.GetUserSessions(userId)
+		t.Require().Error(err)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		prs.mock.ExpectQuery(getUserSessions).WithArgs(userId).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := prs.postgresRepository.GetUserSessions(userId)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (prs *PostgresRepositorySuite) TestDelUserSessionFunction(t provider.T) {
+	t.Title("DelUserSession function of Postgres repository")
+	t.NewStep("Init test data")
+	var userId types.Id = 1
+	publicId := PublicId("id")
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		prs.mock.ExpectExec(delUserSession).WithArgs(userId, publicId, publicIdLength).
+			WillReturnResult(sqlxmock.NewResult(0, 1))
+
+		t.NewStep("Check result")
+		t.Require().NoError(prs.postgresRepository.DelUserSession(userId, publicId))
+	})
+
+	t.WithNewStep("Not found execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		prs.mock.ExpectExec(delUserSession).WithArgs(userId, publicId, publicIdLength).
+			WillReturnResult(sqlxmock.NewResult(0, 0))
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(prs.postgresRepository.DelUserSession(userId, publicId), ErrorNoSession)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		prs.mock.ExpectExec(delUserSession).WithArgs(userId, publicId, publicIdLength).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(prs.postgresRepository.DelUserSession(userId, publicId), testError)
+	})
+}
+
+func (prs *PostgresRepositorySuite) TestDelUserSessionsFunction(t provider.T) {
+	t.Title("DelUserSessions function of Postgres repository")
+	t.NewStep("Init test data")
+	var userId types.Id = 1
+	sessionId := "id"
+
+	t.WithNewStep("Except current execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		prs.mock.ExpectExec(delUserSessions).WithArgs(userId, sessionHash(sessionId)).
+			WillReturnResult(sqlxmock.NewResult(0, 2))
+
+		t.NewStep("Check result")
+		t.Require().NoError(prs.postgresRepository.DelUserSessions(userId, sessionId))
+	})
+
+	t.WithNewStep("All sessions execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		prs.mock.ExpectExec(delUserSessions).WithArgs(userId, "").WillReturnResult(sqlxmock.NewResult(0, 3))
+
+		t.NewStep("Check result")
+		t.Require().NoError(prs.postgresRepository.DelUserSessions(userId, ""))
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		prs.mock.ExpectExec(delUserSessions).WithArgs(userId, sessionHash(sessionId)).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(prs.postgresRepository.DelUserSessions(userId, sessionId), testError)
+	})
+}
+
+func (prs *PostgresRepositorySuite) TestCleanupFunction(t provider.T) {
+	t.Title("Cleanup function of Postgres repository")
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		prs.mock.ExpectExec(cleanupSessions).WillReturnResult(sqlxmock.NewResult(0, 5))
+
+		t.NewStep("Check result")
+		n, err := prs.postgresRepository.Cleanup()
+		t.Require().NoError(err)
+		t.Require().Equal(int64(5), n)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		prs.mock.ExpectExec(cleanupSessions).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := prs.postgresRepository.Cleanup()
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func TestRunPostgresRepositorySuite(t *testing.T) {
+	suite.RunSuite(t, new(PostgresRepositorySuite))
+}
@@ -0,0 +1,178 @@
+package session
+
+import (
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"time"
+	"vk_film/internal/pkg/types"
+)
+
+const (
+	createSession = `
+		INSERT INTO sessions (session_hash, user_id, idle_timeout, expires_at, active_until, ip, user_agent)
+			VALUES ($1, $2, $3::bigint, $4, least(now() + $3::bigint * interval '1 second', $4), $5, $6)
+	`
+
+	getUserId = `
+		UPDATE sessions SET last_seen_at = now(),
+				active_until = least(now() + idle_timeout * interval '1 second', expires_at)
+			WHERE session_hash = $1 AND active_until > now()
+			RETURNING user_id
+	`
+
+	delSession = `
+		DELETE FROM sessions WHERE session_hash = $1
+	`
+
+	getUserSessions = `
+		SELECT session_hash, user_id, created_at, last_seen_at, expires_at, idle_timeout, ip, user_agent
+			FROM sessions
+			WHERE user_id = $1 AND active_until > now()
+			ORDER BY created_at, session_hash
+	`
+
+	delUserSession = `
+		DELETE FROM sessions WHERE user_id = $1 AND left(session_hash, $3) = $2 AND active_until > now()
+	`
+
+	delUserSessions = `
+		DELETE FROM sessions WHERE user_id = $1 AND session_hash != $2
+	`
+
+	cleanupSessions = `
+		DELETE FROM sessions WHERE active_until <= now()
+	`
+)
+
+// PostgresSession
+// Stores sessions in Postgres for deployments without Redis. Only hashes of session identifiers
+// are stored. Expired sessions are not returned, but their rows stay in the table until Cleanup.
+type PostgresSession struct {
+	db *sqlx.DB
+}
+
+func NewPostgresSession(db *sqlx.DB) *PostgresSession {
+	return &PostgresSession{
+		db: db,
+	}
+}
+
+var _ = Repository(&PostgresSession{})
+
+func (ps *PostgresSession) Set(sessionId string, info *Session) error {
+	if _, err := ps.db.Exec(createSession, sessionHash(sessionId), info.UserID,
+		int64(info.IdleTimeout.Seconds()), info.ExpiresAt, info.IP, info.UserAgent); err != nil {
+		return errors.Wrapf(err, "can't create session of user %d", info.UserID)
+	}
+
+	return nil
+}
+
+func (ps *PostgresSession) GetUserId(sessionId string) (types.Id, error) {
+	var userId types.Id
+
+	if err := ps.db.QueryRowx(getUserId, sessionHash(sessionId)).Scan(&userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errors.Wrapf(ErrorNoSession, "with public id %s", PublicId(sessionId))
+		}
+		return 0, errors.Wrapf(err, "can't get session with public id %s", PublicId(sessionId))
+	}
+
+	return userId, nil
+}
+
+func (ps *PostgresSession) Del(sessionId string) error {
+	if _, err := ps.db.Exec(delSession, sessionHash(sessionId)); err != nil {
+		return errors.Wrapf(err, "can't delete session with public id %s", PublicId(sessionId))
+	}
+
+	return nil
+}
+
+func (ps *PostgresSession) GetUserSessions(userId types.Id) ([]Session, error) {
+	rows, err := ps.db.Queryx(getUserSessions, userId)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't execute get user sessions query")
+	}
+	defer rows.Close()
+
+	sessions := make([]Session, 0)
+
+	for rows.Next() {
+		var session Session
+		var hash string
+		var idleTimeout int64
+
+		if err := rows.Scan(
+			&hash,
+			&session.UserID,
+			&session.CreatedAt,
+			&session.LastSeenAt,
+			&session.ExpiresAt,
+			&idleTimeout,
+			&session.IP,
+			&session.UserAgent,
+		); err != nil {
+			return nil, errors.Wrap(err, "can't scan get user sessions query result")
+		}
+
+		session.ID = hash[:publicIdLength]
+		session.IdleTimeout = time.Duration(idleTimeout) * time.Second
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "can't end scan get user sessions query result")
+	}
+
+	return sessions, nil
+}
+
+func (ps *PostgresSession) DelUserSession(userId types.Id, publicId string) error {
+	res, err := ps.db.Exec(delUserSession, userId, publicId, publicIdLength)
+	if err != nil {
+		return errors.Wrapf(err, "can't delete session with public id %s of user %d", publicId, userId)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrapf(err, "can't get number affected rows of deleting session of user %d", userId)
+	}
+
+	if n == 0 {
+		return errors.Wrapf(ErrorNoSession, "with public id %s of user %d", publicId, userId)
+	}
+
+	return nil
+}
+
+func (ps *PostgresSession) DelUserSessions(userId types.Id, exceptSessionId string) error {
+	// Пустой хеш не совпадает ни с одной сессией, поэтому удаляются все сессии пользователя
+	exceptHash := ""
+	if exceptSessionId != "" {
+		exceptHash = sessionHash(exceptSessionId)
+	}
+
+	if _, err := ps.db.Exec(delUserSessions, userId, exceptHash); err != nil {
+		return errors.Wrapf(err, "can't delete sessions of user %d", userId)
+	}
+
+	return nil
+}
+
+// Cleanup
+// Deletes rows of expired sessions and returns their number
+func (ps *PostgresSession) Cleanup() (int64, error) {
+	res, err := ps.db.Exec(cleanupSessions)
+	if err != nil {
+		return 0, errors.Wrap(err, "can't delete expired sessions")
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "can't get number of deleted expired sessions")
+	}
+
+	return n, nil
+}
@@ -2,41 +2,125 @@ package session
 
 import (
 	"context"
+	"fmt"
 	"github.com/pkg/errors"
 	"github.com/redis/go-redis/v9"
+	"slices"
+	"strconv"
 	"time"
 	"vk_film/internal/pkg/types"
 )
 
+const (
+	sessionKeyPrefix      = "session:"
+	userSessionsKeyFormat = "user_sessions:%d"
+
+	userIdField      = "user_id"
+	createdAtField   = "created_at"
+	lastSeenAtField  = "last_seen_at"
+	ipField          = "ip"
+	userAgentField   = "user_agent"
+	idleTimeoutField = "idle_timeout"
+	expiresAtField   = "expires_at"
+)
+
 type RedisSession struct {
 	client *redis.Client
 	ctx    context.Context
+	now    func() time.Time
 }
 
 func NewRedisSession(client *redis.Client) *RedisSession {
-	return &RedisSession{client: client, ctx: context.Background()}
+	return &RedisSession{client: client, ctx: context.Background(), now: time.Now}
 }
 
-func (rs *RedisSession) Set(sessionId string, userId types.Id, expiredTime time.Duration) error {
-	if err := rs.client.Set(rs.ctx, sessionId, uint64(userId), expiredTime).Err(); err != nil {
+// sessionKey
+// Ключ хеша с данными сессии
+func sessionKey(sessionId string) string {
+	return sessionKeyPrefix + sessionId
+}
+
+// userSessionsKey
+// Ключ множества сессий пользователя
+func userSessionsKey(userId types.Id) string {
+	return fmt.Sprintf(userSessionsKeyFormat, userId)
+}
+
+func (rs *RedisSession) Set(sessionId string, info *Session) error {
+	now := rs.now()
+
+	if err := rs.client.HSet(rs.ctx, sessionKey(sessionId),
+		userIdField, uint64(info.UserID),
+		createdAtField, now.Unix(),
+		lastSeenAtField, now.Unix(),
+		ipField, info.IP,
+		userAgentField, info.UserAgent,
+		idleTimeoutField, int64(info.IdleTimeout.Seconds()),
+		expiresAtField, info.ExpiresAt.Unix(),
+	).Err(); err != nil {
+		return errors.Wrapf(err,
+			"error when try create session with uniqId: %s, and userId: %d", sessionId, info.UserID)
+	}
+
+	if err := rs.client.Expire(rs.ctx, sessionKey(sessionId), info.ttl(now)).Err(); err != nil {
 		return errors.Wrapf(err,
-			"error when try create session with uniqId: %s, and userId: %d", sessionId, userId)
+			"error when try set expired time with sessionId: %s", sessionId)
+	}
+
+	// Запоминаем сессию в множестве сессий пользователя
+	if err := rs.client.SAdd(rs.ctx, userSessionsKey(info.UserID), sessionId).Err(); err != nil {
+		return errors.Wrapf(err,
+			"error when try add session with uniqId: %s to sessions of user %d", sessionId, info.UserID)
+	}
+
+	// Множество живёт до абсолютного срока самой долгой сессии пользователя:
+	// NX задаёт срок новому множеству, GT только продлевает срок существующего
+	lifetime := info.ExpiresAt.Sub(now)
+	if err := rs.client.ExpireNX(rs.ctx, userSessionsKey(info.UserID), lifetime).Err(); err != nil {
+		return errors.Wrapf(err,
+			"error when try set expired time of sessions of user %d", info.UserID)
+	}
+
+	if err := rs.client.ExpireGT(rs.ctx, userSessionsKey(info.UserID), lifetime).Err(); err != nil {
+		return errors.Wrapf(err,
+			"error when try update expired time of sessions of user %d", info.UserID)
 	}
 	return nil
 }
 
-func (rs *RedisSession) GetUserId(sessionId string, updateExpiredTime time.Duration) (types.Id, error) {
-	userId, err := rs.client.Get(rs.ctx, sessionId).Uint64()
+func (rs *RedisSession) GetUserId(sessionId string) (types.Id, error) {
+	values, err := rs.client.HMGet(rs.ctx, sessionKey(sessionId), userIdField, idleTimeoutField, expiresAtField).Result()
 	if err != nil {
-		if errors.Is(err, redis.Nil) {
-			err = ErrorNoSession
+		return 0, errors.Wrapf(err,
+			"error when try found session with sessionId: %s", sessionId)
+	}
+
+	userId, found := parseInt(values[0])
+	if !found {
+		return 0, errors.Wrapf(ErrorNoSession,
+			"error when try found session with sessionId: %s", sessionId)
+	}
+
+	now := rs.now()
+	idleTimeout, hasIdle := parseInt(values[1])
+	expiresAt, hasExpires := parseInt(values[2])
+	info := &Session{IdleTimeout: time.Duration(idleTimeout) * time.Second, ExpiresAt: time.Unix(expiresAt, 0)}
+
+	// Сессии без сроков созданы до появления политик времени жизни и завершаются
+	if !hasIdle || !hasExpires || info.ttl(now) <= 0 {
+		if err = rs.delSessions(types.Id(userId), []string{sessionId}); err != nil {
+			return 0, err
 		}
+		return 0, errors.Wrapf(ErrorNoSession,
+			"session with sessionId: %s is expired", sessionId)
+	}
 
+	if err = rs.client.HSet(rs.ctx, sessionKey(sessionId), lastSeenAtField, now.Unix()).Err(); err != nil {
 		return 0, errors.Wrapf(err,
-			"error when try found session with sessionId: %s", sessionId)
+			"error when try update last seen time with sessionId: %s", sessionId)
 	}
 
-	if err = rs.client.Expire(rs.ctx, sessionId, updateExpiredTime).Err(); err != nil {
+	if err = rs.client.Expire(rs.ctx, sessionKey(sessionId), info.ttl(now)).Err(); err != nil {
 		return 0, errors.Wrapf(err,
 			"error when try update expired time with sessionId: %s", sessionId)
 	}
@@ -44,9 +128,143 @@ func (rs *RedisSession) GetUserId(sessionId string, updateExpiredTime time.Durat
 }
 
 func (rs *RedisSession) Del(sessionId string) error {
-	if err := rs.client.Del(rs.ctx, sessionId).Err(); err != nil {
+	userId, err := rs.client.HGet(rs.ctx, sessionKey(sessionId), userIdField).Uint64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil
+		}
+		return errors.Wrapf(err,
+			"error when try found session with sessionId: %s", sessionId)
+	}
+
+	return rs.delSessions(types.Id(userId), []string{sessionId})
+}
+
+func (rs *RedisSession) GetUserSessions(userId types.Id) ([]Session, error) {
+	sessionIds, err := rs.client.SMembers(rs.ctx, userSessionsKey(userId)).Result()
+	if err != nil {
+		return nil, errors.Wrapf(err,
+			"error when try get sessions of user %d", userId)
+	}
+
+	sessions := make([]Session, 0, len(sessionIds))
+	expired := make([]string, 0)
+
+	for _, sessionId := range sessionIds {
+		fields, err := rs.client.HGetAll(rs.ctx, sessionKey(sessionId)).Result()
+		if err != nil {
+			return nil, errors.Wrapf(err,
+				"error when try get session with sessionId: %s", sessionId)
+		}
+
+		// Сессия истекла, но ещё осталась в множестве сессий пользователя
+		if len(fields) == 0 {
+			expired = append(expired, sessionId)
+			continue
+		}
+
+		sessions = append(sessions, parseSession(sessionId, userId, fields))
+	}
+
+	if len(expired) != 0 {
+		if err := rs.client.SRem(rs.ctx, userSessionsKey(userId), expired).Err(); err != nil {
+			return nil, errors.Wrapf(err,
+				"error when try remove expired sessions from sessions of user %d", userId)
+		}
+	}
+
+	slices.SortFunc(sessions, func(a, b Session) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return sessions, nil
+}
+
+func (rs *RedisSession) DelUserSession(userId types.Id, publicId string) error {
+	sessionIds, err := rs.client.SMembers(rs.ctx, userSessionsKey(userId)).Result()
+	if err != nil {
+		return errors.Wrapf(err,
+			"error when try get sessions of user %d", userId)
+	}
+
+	i := slices.IndexFunc(sessionIds, func(sessionId string) bool { return PublicId(sessionId) == publicId })
+	if i == -1 {
+		return errors.Wrapf(ErrorNoSession, "with public id %s of user %d", publicId, userId)
+	}
+
+	return rs.delSessions(userId, sessionIds[i:i+1])
+}
+
+func (rs *RedisSession) DelUserSessions(userId types.Id, exceptSessionId string) error {
+	sessions, err := rs.client.SMembers(rs.ctx, userSessionsKey(userId)).Result()
+	if err != nil {
+		return errors.Wrapf(err,
+			"error when try get sessions of user %d", userId)
+	}
+
+	deleted := make([]string, 0, len(sessions))
+	for _, sessionId := range sessions {
+		if sessionId != exceptSessionId {
+			deleted = append(deleted, sessionId)
+		}
+	}
+
+	if len(deleted) == 0 {
+		return nil
+	}
+
+	// Уже истёкшие сессии удаляются из множества вместе с остальными
+	return rs.delSessions(userId, deleted)
+}
+
+// delSessions
+// Удаляет сессии и убирает их из множества сессий пользователя
+func (rs *RedisSession) delSessions(userId types.Id, sessionIds []string) error {
+	keys := make([]string, 0, len(sessionIds))
+	for _, sessionId := range sessionIds {
+		keys = append(keys, sessionKey(sessionId))
+	}
+
+	if err := rs.client.Del(rs.ctx, keys...).Err(); err != nil {
 		return errors.Wrapf(err,
-			"error when try delete session with sessionId: %s", sessionId)
+			"error when try delete sessions of user %d", userId)
+	}
+
+	if err := rs.client.SRem(rs.ctx, userSessionsKey(userId), sessionIds).Err(); err != nil {
+		return errors.Wrapf(err,
+			"error when try remove sessions from sessions of user %d", userId)
 	}
 	return nil
 }
+
+// parseSession
+// Собирает данные сессии из полей хеша
+func parseSession(sessionId string, userId types.Id, fields map[string]string) Session {
+	createdAt, _ := strconv.ParseInt(fields[createdAtField], 10, 64)
+	lastSeenAt, _ := strconv.ParseInt(fields[lastSeenAtField], 10, 64)
+	idleTimeout, _ := strconv.ParseInt(fields[idleTimeoutField], 10, 64)
+	expiresAt, _ := strconv.ParseInt(fields[expiresAtField], 10, 64)
+
+	return Session{
+		ID:          PublicId(sessionId),
+		UserID:      userId,
+		CreatedAt:   time.Unix(createdAt, 0),
+		LastSeenAt:  time.Unix(lastSeenAt, 0),
+		ExpiresAt:   time.Unix(expiresAt, 0),
+		IdleTimeout: time.Duration(idleTimeout) * time.Second,
+		IP:          fields[ipField],
+		UserAgent:   fields[userAgentField],
+	}
+}
+
+// parseInt
+// Разбирает число из ответа HMGET, отсутствующее поле возвращается как nil
+func parseInt(value any) (int64, bool) {
+	str, ok := value.(string)
+	if !ok {
+		return 0, false
+	}
+
+	number, err := strconv.ParseInt(str, 10, 64)
+	return number, err == nil
+}
@@ -13,6 +13,8 @@ import (
 
 var testError = errors.New("test error")
 
+var testNow = time.Unix(1700000000, 0)
+
 type RedisRepositorySuite struct {
 	suite.Suite
 	redisRepository *RedisSession
@@ -22,6 +24,7 @@ type RedisRepositorySuite struct {
 func (rrs *RedisRepositorySuite) BeforeEach(t provider.T) {
 	db, mock := redismock.NewClientMock()
 	rrs.redisRepository = NewRedisSession(db)
+	rrs.redisRepository.now = func() time.Time { return testNow }
 	rrs.mock = mock
 }
 
@@ -33,97 +36,420 @@ func (rrs *RedisRepositorySuite) AfterEach(t provider.T) {
 func (rrs *RedisRepositorySuite) TestSetFunction(t provider.T) {
 	t.Title("Set function of Redis repository")
 	t.NewStep("Init test data")
-	timeExpired := 2 * time.Second
 	sessionId := "id"
-	userId := types.Id(1)
+	info := &Session{
+		UserID:      1,
+		IP:          "127.0.0.1",
+		UserAgent:   "curl/8.0",
+		IdleTimeout: time.Hour,
+		ExpiresAt:   testNow.Add(24 * time.Hour),
+	}
+	userId := info.UserID
+
+	expectHSet := func() *redismock.ExpectedInt {
+		return rrs.mock.ExpectHSet(sessionKey(sessionId),
+			userIdField, uint64(userId),
+			createdAtField, testNow.Unix(),
+			lastSeenAtField, testNow.Unix(),
+			ipField, info.IP,
+			userAgentField, info.UserAgent,
+			idleTimeoutField, int64(3600),
+			expiresAtField, info.ExpiresAt.Unix(),
+		)
+	}
 
 	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectHSet().SetVal(7)
+		rrs.mock.ExpectExpire(sessionKey(sessionId), time.Hour).SetVal(true)
+		rrs.mock.ExpectSAdd(userSessionsKey(userId), sessionId).SetVal(1)
+		rrs.mock.ExpectExpireNX(userSessionsKey(userId), 24*time.Hour).SetVal(true)
+		rrs.mock.ExpectExpireGT(userSessionsKey(userId), 24*time.Hour).SetVal(false)
+
+		t.NewStep("Check result")
+		t.Require().NoError(rrs.redisRepository.Set(sessionId, info))
+	})
 
+	t.WithNewStep("Correct execute with close expiration", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		rrs.mock.ExpectSet(sessionId, uint64(userId), timeExpired).SetVal(sessionId)
+		closeInfo := *info
+		closeInfo.ExpiresAt = testNow.Add(time.Minute)
+		rrs.mock.ExpectHSet(sessionKey(sessionId),
+			userIdField, uint64(userId),
+			createdAtField, testNow.Unix(),
+			lastSeenAtField, testNow.Unix(),
+			ipField, info.IP,
+			userAgentField, info.UserAgent,
+			idleTimeoutField, int64(3600),
+			expiresAtField, closeInfo.ExpiresAt.Unix(),
+		).SetVal(7)
+		rrs.mock.ExpectExpire(sessionKey(sessionId), time.Minute).SetVal(true)
+		rrs.mock.ExpectSAdd(userSessionsKey(userId), sessionId).SetVal(1)
+		rrs.mock.ExpectExpireNX(userSessionsKey(userId), time.Minute).SetVal(false)
+		rrs.mock.ExpectExpireGT(userSessionsKey(userId), time.Minute).SetVal(false)
 
 		t.NewStep("Check result")
-		t.Require().NoError(rrs.redisRepository.Set(sessionId, userId, timeExpired))
+		t.Require().NoError(rrs.redisRepository.Set(sessionId, &closeInfo))
 	})
 
 	t.WithNewStep("Redis error execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		rrs.mock.ExpectSet(sessionId, uint64(userId), timeExpired).SetErr(testError)
+		expectHSet().SetErr(testError)
 
 		t.NewStep("Check result")
-		t.Require().ErrorIs(rrs.redisRepository.Set(sessionId, userId, timeExpired), testError)
+		t.Require().ErrorIs(rrs.redisRepository.Set(sessionId, info), testError)
+	})
+
+	t.WithNewStep("Redis error execute of redis expire of session", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectHSet().SetVal(7)
+		rrs.mock.ExpectExpire(sessionKey(sessionId), time.Hour).SetErr(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.redisRepository.Set(sessionId, info), testError)
+	})
+
+	t.WithNewStep("Redis error execute of redis sadd", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectHSet().SetVal(7)
+		rrs.mock.ExpectExpire(sessionKey(sessionId), time.Hour).SetVal(true)
+		rrs.mock.ExpectSAdd(userSessionsKey(userId), sessionId).SetErr(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.redisRepository.Set(sessionId, info), testError)
+	})
+
+	t.WithNewStep("Redis error execute of redis expire", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectHSet().SetVal(7)
+		rrs.mock.ExpectExpire(sessionKey(sessionId), time.Hour).SetVal(true)
+		rrs.mock.ExpectSAdd(userSessionsKey(userId), sessionId).SetVal(1)
+		rrs.mock.ExpectExpireNX(userSessionsKey(userId), 24*time.Hour).SetVal(true)
+		rrs.mock.ExpectExpireGT(userSessionsKey(userId), 24*time.Hour).SetErr(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.redisRepository.Set(sessionId, info), testError)
 	})
 }
 
 func (rrs *RedisRepositorySuite) TestGetFunction(t provider.T) {
 	t.Title("GetUserId function of Redis repository")
 	t.NewStep("Init test data")
-	timeExpired := 2 * time.Second
 	sessionId := "id"
 	userId := types.Id(1)
 
+	expectHMGet := func() *redismock.ExpectedSlice {
+		return rrs.mock.ExpectHMGet(sessionKey(sessionId), userIdField, idleTimeoutField, expiresAtField)
+	}
+	values := func(expiresAt time.Time) []any {
+		return []any{fmt.Sprintf("%d", userId), "3600", fmt.Sprintf("%d", expiresAt.Unix())}
+	}
+
 	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		rrs.mock.ExpectGet(sessionId).SetVal(fmt.Sprintf("%d", userId))
-		rrs.mock.ExpectExpire(sessionId, timeExpired).SetVal(true)
+		expectHMGet().SetVal(values(testNow.Add(24 * time.Hour)))
+		rrs.mock.ExpectHSet(sessionKey(sessionId), lastSeenAtField, testNow.Unix()).SetVal(0)
+		rrs.mock.ExpectExpire(sessionKey(sessionId), time.Hour).SetVal(true)
 
 		t.NewStep("Check result")
-		resUserId, err := rrs.redisRepository.GetUserId(sessionId, timeExpired)
+		resUserId, err := rrs.redisRepository.GetUserId(sessionId)
 		t.Require().NoError(err)
 		t.Require().Equal(userId, resUserId)
 	})
 
+	t.WithNewStep("Correct execute near absolute expiration", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectHMGet().SetVal(values(testNow.Add(time.Minute)))
+		rrs.mock.ExpectHSet(sessionKey(sessionId), lastSeenAtField, testNow.Unix()).SetVal(0)
+		rrs.mock.ExpectExpire(sessionKey(sessionId), time.Minute).SetVal(true)
+
+		t.NewStep("Check result")
+		_, err := rrs.redisRepository.GetUserId(sessionId)
+		t.Require().NoError(err)
+	})
+
 	t.WithNewStep("No records execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		rrs.mock.ExpectGet(sessionId).RedisNil()
+		expectHMGet().SetVal([]any{nil, nil, nil})
 
 		t.NewStep("Check result")
-		_, err := rrs.redisRepository.GetUserId(sessionId, timeExpired)
+		_, err := rrs.redisRepository.GetUserId(sessionId)
 		t.Require().ErrorIs(err, ErrorNoSession)
 	})
 
-	t.WithNewStep("Redis error execute of redis get", func(t provider.StepCtx) {
+	t.WithNewStep("Expired session execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		rrs.mock.ExpectGet(sessionId).SetErr(testError)
+		expectHMGet().SetVal(values(testNow))
+		rrs.mock.ExpectDel(sessionKey(sessionId)).SetVal(1)
+		rrs.mock.ExpectSRem(userSessionsKey(userId), sessionId).SetVal(1)
 
 		t.NewStep("Check result")
-		_, err := rrs.redisRepository.GetUserId(sessionId, timeExpired)
+		_, err := rrs.redisRepository.GetUserId(sessionId)
+		t.Require().ErrorIs(err, ErrorNoSession)
+	})
+
+	t.WithNewStep("Session without lifetime execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectHMGet().SetVal([]any{fmt.Sprintf("%d", userId), nil, nil})
+		rrs.mock.ExpectDel(sessionKey(sessionId)).SetVal(1)
+		rrs.mock.ExpectSRem(userSessionsKey(userId), sessionId).SetVal(1)
+
+		t.NewStep("Check result")
+		_, err := rrs.redisRepository.GetUserId(sessionId)
+		t.Require().ErrorIs(err, ErrorNoSession)
+	})
+
+	t.WithNewStep("Redis error execute of redis hmget", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectHMGet().SetErr(testError)
+
+		t.NewStep("Check result")
+		_, err := rrs.redisRepository.GetUserId(sessionId)
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Redis error execute of redis hset", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectHMGet().SetVal(values(testNow.Add(24 * time.Hour)))
+		rrs.mock.ExpectHSet(sessionKey(sessionId), lastSeenAtField, testNow.Unix()).SetErr(testError)
+
+		t.NewStep("Check result")
+		_, err := rrs.redisRepository.GetUserId(sessionId)
 		t.Require().ErrorIs(err, testError)
 	})
 
 	t.WithNewStep("Redis error execute of redis expire", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		rrs.mock.ExpectGet(sessionId).SetVal(fmt.Sprintf("%d", userId))
-		rrs.mock.ExpectExpire(sessionId, timeExpired).SetErr(testError)
+		expectHMGet().SetVal(values(testNow.Add(24 * time.Hour)))
+		rrs.mock.ExpectHSet(sessionKey(sessionId), lastSeenAtField, testNow.Unix()).SetVal(0)
+		rrs.mock.ExpectExpire(sessionKey(sessionId), time.Hour).SetErr(testError)
 
 		t.NewStep("Check result")
-		_, err := rrs.redisRepository.GetUserId(sessionId, timeExpired)
+		_, err := rrs.redisRepository.GetUserId(sessionId)
 		t.Require().ErrorIs(err, testError)
 	})
 }
 
 func (rrs *RedisRepositorySuite) TestDeleteFunction(t provider.T) {
-	t.Title("Get function of Redis repository")
+	t.Title("Del function of Redis repository")
 	t.NewStep("Init test data")
 	sessionId := "id"
+	userId := types.Id(1)
 
 	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		rrs.mock.ExpectDel(sessionId).SetVal(0)
+		rrs.mock.ExpectHGet(sessionKey(sessionId), userIdField).SetVal(fmt.Sprintf("%d", userId))
+		rrs.mock.ExpectDel(sessionKey(sessionId)).SetVal(1)
+		rrs.mock.ExpectSRem(userSessionsKey(userId), sessionId).SetVal(1)
+
+		t.NewStep("Check result")
+		t.Require().NoError(rrs.redisRepository.Del(sessionId))
+	})
+
+	t.WithNewStep("No session execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectHGet(sessionKey(sessionId), userIdField).RedisNil()
 
 		t.NewStep("Check result")
 		t.Require().NoError(rrs.redisRepository.Del(sessionId))
 	})
 
+	t.WithNewStep("Redis error execute of redis hget", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectHGet(sessionKey(sessionId), userIdField).SetErr(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.redisRepository.Del(sessionId), testError)
+	})
+
 	t.WithNewStep("Redis error execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		rrs.mock.ExpectDel(sessionId).SetErr(testError)
+		rrs.mock.ExpectHGet(sessionKey(sessionId), userIdField).SetVal(fmt.Sprintf("%d", userId))
+		rrs.mock.ExpectDel(sessionKey(sessionId)).SetErr(testError)
 
 		t.NewStep("Check result")
 		t.Require().ErrorIs(rrs.redisRepository.Del(sessionId), testError)
 	})
 }
 
+func (rrs *RedisRepositorySuite) TestGetUserSessionsFunction(t provider.T) {
+	t.Title("GetUserSessions function of Redis repository")
+	t.NewStep("Init test data")
+	userId := types.Id(1)
+	fields := func(createdAt int64) map[string]string {
+		return map[string]string{
+			userIdField:      "1",
+			createdAtField:   fmt.Sprintf("%d", createdAt),
+			lastSeenAtField:  fmt.Sprintf("%d", createdAt+10),
+			ipField:          "127.0.0.1",
+			userAgentField:   "curl/8.0",
+			idleTimeoutField: "3600",
+			expiresAtField:   fmt.Sprintf("%d", createdAt+86400),
+		}
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectSMembers(userSessionsKey(userId)).SetVal([]string{"new", "expired", "old"})
+		rrs.mock.ExpectHGetAll(sessionKey("new")).SetVal(fields(200))
+		rrs.mock.ExpectHGetAll(sessionKey("expired")).SetVal(map[string]string{})
+		rrs.mock.ExpectHGetAll(sessionKey("old")).SetVal(fields(100))
+		rrs.mock.ExpectSRem(userSessionsKey(userId), "expired").SetVal(1)
+
+		t.NewStep("Check result")
+		sessions, err := rrs.redisRepository.GetUserSessions(userId)
+		t.Require().NoError(err)
+		t.Require().Equal([]Session{
+			{
+				ID:          PublicId("old"),
+				UserID:      userId,
+				CreatedAt:   time.Unix(100, 0),
+				LastSeenAt:  time.Unix(110, 0),
+				ExpiresAt:   time.Unix(86500, 0),
+				IdleTimeout: time.Hour,
+				IP:          "127.0.0.1",
+				UserAgent:   "curl/8.0",
+			},
+			{
+				ID:          PublicId("new"),
+				UserID:      userId,
+				CreatedAt:   time.Unix(200, 0),
+				LastSeenAt:  time.Unix(210, 0),
+				ExpiresAt:   time.Unix(86600, 0),
+				IdleTimeout: time.Hour,
+				IP:          "127.0.0.1",
+				UserAgent:   "curl/8.0",
+			},
+		}, sessions)
+	})
+
+	t.WithNewStep("Redis error execute of redis smembers", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectSMembers(userSessionsKey(userId)).SetErr(testError)
+
+		t.NewStep("Check result")
+		_, err := rrs.redisRepository.GetUserSessions(userId)
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Redis error execute of redis hgetall", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectSMembers(userSessionsKey(userId)).SetVal([]string{"id"})
+		rrs.mock.ExpectHGetAll(sessionKey("id")).SetErr(testError)
+
+		t.NewStep("Check result")
+		_, err := rrs.redisRepository.GetUserSessions(userId)
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Redis error execute of redis srem", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectSMembers(userSessionsKey(userId)).SetVal([]string{"expired"})
+		rrs.mock.ExpectHGetAll(sessionKey("expired")).SetVal(map[string]string{})
+		rrs.mock.ExpectSRem(userSessionsKey(userId), "expired").SetErr(testError)
+
+		t.NewStep("Check result")
+		_, err := rrs.redisRepository.GetUserSessions(userId)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (rrs *RedisRepositorySuite) TestDeleteUserSessionFunction(t provider.T) {
+	t.Title("DelUserSession function of Redis repository")
+	t.NewStep("Init test data")
+	userId := types.Id(1)
+	sessions := []string{"id", "other"}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectSMembers(userSessionsKey(userId)).SetVal(sessions)
+		rrs.mock.ExpectDel(sessionKey("other")).SetVal(1)
+		rrs.mock.ExpectSRem(userSessionsKey(userId), "other").SetVal(1)
+
+		t.NewStep("Check result")
+		t.Require().NoError(rrs.redisRepository.DelUserSession(userId, PublicId("other")))
+	})
+
+	t.WithNewStep("Session not found execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectSMembers(userSessionsKey(userId)).SetVal(sessions)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.redisRepository.DelUserSession(userId, PublicId("unknown")), ErrorNoSession)
+	})
+
+	t.WithNewStep("Redis error execute of redis smembers", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectSMembers(userSessionsKey(userId)).SetErr(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.redisRepository.DelUserSession(userId, PublicId("other")), testError)
+	})
+}
+
+func (rrs *RedisRepositorySuite) TestDeleteUserSessionsFunction(t provider.T) {
+	t.Title("DelUserSessions function of Redis repository")
+	t.NewStep("Init test data")
+	sessionId := "id"
+	userId := types.Id(1)
+	sessions := []string{"id", "other", "another"}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectSMembers(userSessionsKey(userId)).SetVal(sessions)
+		rrs.mock.ExpectDel(sessionKey("other"), sessionKey("another")).SetVal(2)
+		rrs.mock.ExpectSRem(userSessionsKey(userId), "other", "another").SetVal(2)
+
+		t.NewStep("Check result")
+		t.Require().NoError(rrs.redisRepository.DelUserSessions(userId, sessionId))
+	})
+
+	t.WithNewStep("Correct execute of all sessions", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectSMembers(userSessionsKey(userId)).SetVal(sessions)
+		rrs.mock.ExpectDel(sessionKey("id"), sessionKey("other"), sessionKey("another")).SetVal(3)
+		rrs.mock.ExpectSRem(userSessionsKey(userId), "id", "other", "another").SetVal(3)
+
+		t.NewStep("Check result")
+		t.Require().NoError(rrs.redisRepository.DelUserSessions(userId, ""))
+	})
+
+	t.WithNewStep("Correct execute without other sessions", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectSMembers(userSessionsKey(userId)).SetVal([]string{sessionId})
+
+		t.NewStep("Check result")
+		t.Require().NoError(rrs.redisRepository.DelUserSessions(userId, sessionId))
+	})
+
+	t.WithNewStep("Redis error execute of redis smembers", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectSMembers(userSessionsKey(userId)).SetErr(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.redisRepository.DelUserSessions(userId, sessionId), testError)
+	})
+
+	t.WithNewStep("Redis error execute of redis del", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectSMembers(userSessionsKey(userId)).SetVal(sessions)
+		rrs.mock.ExpectDel(sessionKey("other"), sessionKey("another")).SetErr(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.redisRepository.DelUserSessions(userId, sessionId), testError)
+	})
+
+	t.WithNewStep("Redis error execute of redis srem", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rrs.mock.ExpectSMembers(userSessionsKey(userId)).SetVal(sessions)
+		rrs.mock.ExpectDel(sessionKey("other"), sessionKey("another")).SetVal(2)
+		rrs.mock.ExpectSRem(userSessionsKey(userId), "other", "another").SetErr(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rrs.redisRepository.DelUserSessions(userId, sessionId), testError)
+	})
+}
+
 func TestRunRedisRepositorySuite(t *testing.T) {
 	suite.RunSuite(t, new(RedisRepositorySuite))
 }
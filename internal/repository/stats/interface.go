@@ -0,0 +1,55 @@
+package stats
+
+import (
+	"vk_film/internal/pkg/time"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/actor"
+	"vk_film/internal/repository/film"
+)
+
+//go:generate mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=StatsRepository . Repository
+
+// Params
+// DateFrom and DateTo limit the films taken into account by their publish date.
+// Nil value means that the bound is not set.
+// Non nil MaxCertification excludes films with a stricter or unknown age certification
+// from the film lists. Aggregated statistics are not affected.
+type Params struct {
+	DateFrom *time.FormattedTime
+	DateTo   *time.FormattedTime
+	Limit    uint64
+
+	MaxCertification *types.Certification
+}
+
+type Repository interface {
+	// GetFilmsPerYear
+	// Returns Error:
+	//   - SQLError
+	GetFilmsPerYear(params Params) ([]FilmsPerYear, error)
+
+	// GetRatingDistribution
+	// Returns Error:
+	//   - SQLError
+	GetRatingDistribution(params Params) ([]RatingCount, error)
+
+	// GetTopActors
+	// Returns Error:
+	//   - SQLError
+	GetTopActors(params Params) ([]ActorFilmsCount, error)
+
+	// GetActorsAverageRating
+	// Returns Error:
+	//   - SQLError
+	GetActorsAverageRating(params Params) ([]ActorRating, error)
+
+	// GetFilmsWithoutActors
+	// Returns Error:
+	//   - SQLError
+	GetFilmsWithoutActors(params Params) ([]film.Film, error)
+
+	// GetActorsWithoutFilms
+	// Returns Error:
+	//   - SQLError
+	GetActorsWithoutFilms(params Params) ([]actor.Actor, error)
+}
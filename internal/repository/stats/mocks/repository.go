@@ -0,0 +1,132 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: vk_film/internal/repository/stats (interfaces: Repository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=StatsRepository . Repository
+//
+
+// Package mr is a generated GoMock package.
+package mr
+
+import (
+	reflect "reflect"
+	actor "vk_film/internal/repository/actor"
+	film "vk_film/internal/repository/film"
+	stats "vk_film/internal/repository/stats"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// StatsRepository is a mock of Repository interface.
+type StatsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *StatsRepositoryMockRecorder
+}
+
+// StatsRepositoryMockRecorder is the mock recorder for StatsRepository.
+type StatsRepositoryMockRecorder struct {
+	mock *StatsRepository
+}
+
+// NewStatsRepository creates a new mock instance.
+func NewStatsRepository(ctrl *gomock.Controller) *StatsRepository {
+	mock := &StatsRepository{ctrl: ctrl}
+	mock.recorder = &StatsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *StatsRepository) EXPECT() *StatsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetActorsAverageRating mocks base method.
+func (m *StatsRepository) GetActorsAverageRating(arg0 stats.Params) ([]stats.ActorRating, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActorsAverageRating", arg0)
+	ret0, _ := ret[0].([]stats.ActorRating)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActorsAverageRating indicates an expected call of GetActorsAverageRating.
+func (mr *StatsRepositoryMockRecorder) GetActorsAverageRating(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorsAverageRating", reflect.TypeOf((*StatsRepository)(nil).GetActorsAverageRating), arg0)
+}
+
+// GetActorsWithoutFilms mocks base method.
+func (m *StatsRepository) GetActorsWithoutFilms(arg0 stats.Params) ([]actor.Actor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActorsWithoutFilms", arg0)
+	ret0, _ := ret[0].([]actor.Actor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActorsWithoutFilms indicates an expected call of GetActorsWithoutFilms.
+func (mr *StatsRepositoryMockRecorder) GetActorsWithoutFilms(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorsWithoutFilms", reflect.TypeOf((*StatsRepository)(nil).GetActorsWithoutFilms), arg0)
+}
+
+// GetFilmsPerYear mocks base method.
+func (m *StatsRepository) GetFilmsPerYear(arg0 stats.Params) ([]stats.FilmsPerYear, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilmsPerYear", arg0)
+	ret0, _ := ret[0].([]stats.FilmsPerYear)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFilmsPerYear indicates an expected call of GetFilmsPerYear.
+func (mr *StatsRepositoryMockRecorder) GetFilmsPerYear(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmsPerYear", reflect.TypeOf((*StatsRepository)(nil).GetFilmsPerYear), arg0)
+}
+
+// GetFilmsWithoutActors mocks base method.
+func (m *StatsRepository) GetFilmsWithoutActors(arg0 stats.Params) ([]film.Film, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilmsWithoutActors", arg0)
+	ret0, _ := ret[0].([]film.Film)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFilmsWithoutActors indicates an expected call of GetFilmsWithoutActors.
+func (mr *StatsRepositoryMockRecorder) GetFilmsWithoutActors(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmsWithoutActors", reflect.TypeOf((*StatsRepository)(nil).GetFilmsWithoutActors), arg0)
+}
+
+// GetRatingDistribution mocks base method.
+func (m *StatsRepository) GetRatingDistribution(arg0 stats.Params) ([]stats.RatingCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRatingDistribution", arg0)
+	ret0, _ := ret[0].([]stats.RatingCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRatingDistribution indicates an expected call of GetRatingDistribution.
+func (mr *StatsRepositoryMockRecorder) GetRatingDistribution(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRatingDistribution", reflect.TypeOf((*StatsRepository)(nil).GetRatingDistribution), arg0)
+}
+
+// GetTopActors mocks base method.
+func (m *StatsRepository) GetTopActors(arg0 stats.Params) ([]stats.ActorFilmsCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopActors", arg0)
+	ret0, _ := ret[0].([]stats.ActorFilmsCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopActors indicates an expected call of GetTopActors.
+func (mr *StatsRepositoryMockRecorder) GetTopActors(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopActors", reflect.TypeOf((*StatsRepository)(nil).GetTopActors), arg0)
+}
@@ -0,0 +1,28 @@
+package stats
+
+import (
+	"vk_film/internal/pkg/types"
+)
+
+type FilmsPerYear struct {
+	Year  uint64
+	Count uint64
+}
+
+type RatingCount struct {
+	Rating types.Rating
+	Count  uint64
+}
+
+type ActorFilmsCount struct {
+	ID         types.Id
+	Name       string
+	FilmsCount uint64
+}
+
+type ActorRating struct {
+	ID            types.Id
+	Name          string
+	AverageRating float64
+	FilmsCount    uint64
+}
@@ -0,0 +1,295 @@
+package stats
+
+import (
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/actor"
+	"vk_film/internal/repository/film"
+	"vk_film/pkg/slices"
+)
+
+const (
+	getFilmsPerYear = `
+		SELECT date_part('year', publish_date)::bigint as year, count(*) FROM films
+			WHERE ($1::date IS NULL OR publish_date >= $1) AND ($2::date IS NULL OR publish_date <= $2)
+			GROUP BY year
+			ORDER BY year
+	`
+
+	getRatingDistribution = `
+		SELECT ratings.rating, count(films.id) FROM generate_series(0, 10) as ratings(rating)
+			LEFT JOIN films on (films.rating = ratings.rating
+				AND ($1::date IS NULL OR films.publish_date >= $1) AND ($2::date IS NULL OR films.publish_date <= $2))
+			GROUP BY ratings.rating
+			ORDER BY ratings.rating
+	`
+
+	getTopActors = `
+		SELECT actors.id, actors.name, count(films.id) as films_count FROM actors
+			JOIN film_actor on (actors.id = film_actor.actor_id)
+			JOIN films on (films.id = film_actor.film_id)
+			WHERE ($1::date IS NULL OR films.publish_date >= $1) AND ($2::date IS NULL OR films.publish_date <= $2)
+			GROUP BY actors.id, actors.name
+			ORDER BY films_count DESC, actors.name
+			LIMIT $3
+	`
+
+	getActorsAverageRating = `
+		SELECT actors.id, actors.name, avg(films.rating)::float8 as average_rating, count(films.id) FROM actors
+			JOIN film_actor on (actors.id = film_actor.actor_id)
+			JOIN films on (films.id = film_actor.film_id)
+			WHERE ($1::date IS NULL OR films.publish_date >= $1) AND ($2::date IS NULL OR films.publish_date <= $2)
+			GROUP BY actors.id, actors.name
+			ORDER BY average_rating DESC, actors.name
+			LIMIT $3
+	`
+
+	getFilmsWithoutActors = `
+		SELECT id, name, description, publish_date, rating FROM films
+			WHERE NOT EXISTS (SELECT 1 FROM film_actor WHERE film_actor.film_id = films.id)
+				AND ($1::date IS NULL OR publish_date >= $1) AND ($2::date IS NULL OR publish_date <= $2)
+				AND ($3::certifications[] IS NULL OR age_certification = ANY($3))
+			ORDER BY publish_date DESC, name
+	`
+
+	getActorsWithoutFilms = `
+		SELECT id, name, sex, birthday FROM actors
+			WHERE NOT EXISTS (
+				SELECT 1 FROM film_actor
+					JOIN films on (films.id = film_actor.film_id)
+					WHERE film_actor.actor_id = actors.id
+						AND ($1::date IS NULL OR films.publish_date >= $1)
+						AND ($2::date IS NULL OR films.publish_date <= $2)
+			)
+			ORDER BY name
+	`
+)
+
+type PostgresStats struct {
+	db *sqlx.DB
+}
+
+func NewPostgresStats(db *sqlx.DB) *PostgresStats {
+	return &PostgresStats{
+		db: db,
+	}
+}
+
+var _ = Repository(&PostgresStats{})
+
+func getDateRange(params Params) (sql.NullTime, sql.NullTime) {
+	dateFrom := sql.NullTime{Valid: false}
+	if params.DateFrom != nil {
+		dateFrom = sql.NullTime{Valid: true, Time: params.DateFrom.Time}
+	}
+
+	dateTo := sql.NullTime{Valid: false}
+	if params.DateTo != nil {
+		dateTo = sql.NullTime{Valid: true, Time: params.DateTo.Time}
+	}
+
+	return dateFrom, dateTo
+}
+
+func getLimit(params Params) sql.NullInt64 {
+	if params.Limit == 0 {
+		return sql.NullInt64{Valid: false}
+	}
+	return sql.NullInt64{Valid: true, Int64: int64(params.Limit)}
+}
+
+func getAllowedCertifications(params Params) pq.StringArray {
+	if params.MaxCertification == nil {
+		return nil
+	}
+
+	return slices.Map(types.AllowedCertifications(*params.MaxCertification), func(c types.Certification) string {
+		return string(c)
+	})
+}
+
+func (ps *PostgresStats) GetFilmsPerYear(params Params) ([]FilmsPerYear, error) {
+	dateFrom, dateTo := getDateRange(params)
+
+	rows, err := ps.db.Queryx(getFilmsPerYear, dateFrom, dateTo)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't execute get films per year query")
+	}
+	defer rows.Close()
+
+	result := make([]FilmsPerYear, 0)
+
+	for rows.Next() {
+		var year FilmsPerYear
+
+		if err := rows.Scan(&year.Year, &year.Count); err != nil {
+			return nil, errors.Wrap(err, "can't scan get films per year query result")
+		}
+
+		result = append(result, year)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "can't end scan get films per year query result")
+	}
+
+	return result, nil
+}
+
+func (ps *PostgresStats) GetRatingDistribution(params Params) ([]RatingCount, error) {
+	dateFrom, dateTo := getDateRange(params)
+
+	rows, err := ps.db.Queryx(getRatingDistribution, dateFrom, dateTo)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't execute get rating distribution query")
+	}
+	defer rows.Close()
+
+	result := make([]RatingCount, 0)
+
+	for rows.Next() {
+		var rating RatingCount
+
+		if err := rows.Scan(&rating.Rating, &rating.Count); err != nil {
+			return nil, errors.Wrap(err, "can't scan get rating distribution query result")
+		}
+
+		result = append(result, rating)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "can't end scan get rating distribution query result")
+	}
+
+	return result, nil
+}
+
+func (ps *PostgresStats) GetTopActors(params Params) ([]ActorFilmsCount, error) {
+	dateFrom, dateTo := getDateRange(params)
+
+	rows, err := ps.db.Queryx(getTopActors, dateFrom, dateTo, getLimit(params))
+	if err != nil {
+		return nil, errors.Wrap(err, "can't execute get top actors query")
+	}
+	defer rows.Close()
+
+	result := make([]ActorFilmsCount, 0)
+
+	for rows.Next() {
+		var act ActorFilmsCount
+
+		if err := rows.Scan(&act.ID, &act.Name, &act.FilmsCount); err != nil {
+			return nil, errors.Wrap(err, "can't scan get top actors query result")
+		}
+
+		result = append(result, act)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "can't end scan get top actors query result")
+	}
+
+	return result, nil
+}
+
+func (ps *PostgresStats) GetActorsAverageRating(params Params) ([]ActorRating, error) {
+	dateFrom, dateTo := getDateRange(params)
+
+	rows, err := ps.db.Queryx(getActorsAverageRating, dateFrom, dateTo, getLimit(params))
+	if err != nil {
+		return nil, errors.Wrap(err, "can't execute get actors average rating query")
+	}
+	defer rows.Close()
+
+	result := make([]ActorRating, 0)
+
+	for rows.Next() {
+		var act ActorRating
+
+		if err := rows.Scan(&act.ID, &act.Name, &act.AverageRating, &act.FilmsCount); err != nil {
+			return nil, errors.Wrap(err, "can't scan get actors average rating query result")
+		}
+
+		result = append(result, act)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "can't end scan get actors average rating query result")
+	}
+
+	return result, nil
+}
+
+func (ps *PostgresStats) GetFilmsWithoutActors(params Params) ([]film.Film, error) {
+	dateFrom, dateTo := getDateRange(params)
+
+	rows, err := ps.db.Queryx(getFilmsWithoutActors, dateFrom, dateTo, getAllowedCertifications(params))
+	if err != nil {
+		return nil, errors.Wrap(err, "can't execute get films without actors query")
+	}
+	defer rows.Close()
+
+	result := make([]film.Film, 0)
+
+	for rows.Next() {
+		var flm film.Film
+
+		err := rows.Scan(
+			&flm.ID,
+			&flm.Name,
+			&flm.Description,
+			&flm.DataPublish,
+			&flm.Rating,
+		)
+
+		if err != nil {
+			return nil, errors.Wrap(err, "can't scan get films without actors query result")
+		}
+
+		result = append(result, flm)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "can't end scan get films without actors query result")
+	}
+
+	return result, nil
+}
+
+func (ps *PostgresStats) GetActorsWithoutFilms(params Params) ([]actor.Actor, error) {
+	dateFrom, dateTo := getDateRange(params)
+
+	rows, err := ps.db.Queryx(getActorsWithoutFilms, dateFrom, dateTo)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't execute get actors without films query")
+	}
+	defer rows.Close()
+
+	result := make([]actor.Actor, 0)
+
+	for rows.Next() {
+		var act actor.Actor
+
+		err := rows.Scan(
+			&act.ID,
+			&act.Name,
+			&act.Sex,
+			&act.Birthday,
+		)
+
+		if err != nil {
+			return nil, errors.Wrap(err, "can't scan get actors without films query result")
+		}
+
+		result = append(result, act)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "can't end scan get actors without films query result")
+	}
+
+	return result, nil
+}
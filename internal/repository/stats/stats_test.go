@@ -0,0 +1,345 @@
+package stats
+
+import (
+	"database/sql"
+	"github.com/lib/pq"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/pkg/errors"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"vk_film/internal/pkg/time"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/actor"
+	"vk_film/internal/repository/film"
+)
+
+var testError = errors.New("test error")
+
+type StatsRepositorySuite struct {
+	suite.Suite
+	statsRepository *PostgresStats
+	mock            sqlxmock.Sqlmock
+}
+
+func (srs *StatsRepositorySuite) BeforeEach(t provider.T) {
+	db, mock, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	t.Require().NoError(err)
+	srs.statsRepository = NewPostgresStats(db)
+	srs.mock = mock
+}
+
+func (srs *StatsRepositorySuite) AfterEach(t provider.T) {
+	t.Require().NoError(srs.mock.ExpectationsWereMet())
+}
+
+func (srs *StatsRepositorySuite) TestGetFilmsPerYearFunction(t provider.T) {
+	t.Title("GetFilmsPerYear function of Stats repository")
+	t.NewStep("Init test data")
+	dateFrom := time.MustParse("01.01.2000")
+	params := Params{DateFrom: &dateFrom}
+
+	columns := []string{"year", "count"}
+
+	rows := func() *sqlxmock.Rows {
+		return sqlxmock.NewRows(columns).
+			AddRow(2001, 2).
+			AddRow(2002, 5)
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		srs.mock.ExpectQuery(getFilmsPerYear).
+			WithArgs(sql.NullTime{Valid: true, Time: dateFrom.Time}, sql.NullTime{}).
+			WillReturnRows(rows())
+
+		t.NewStep("Check result")
+		res, err := srs.statsRepository.GetFilmsPerYear(params)
+		t.Require().NoError(err)
+		t.Require().EqualValues([]FilmsPerYear{{Year: 2001, Count: 2}, {Year: 2002, Count: 5}}, res)
+	})
+
+	t.WithNewStep("Postgres error on execute query", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		srs.mock.ExpectQuery(getFilmsPerYear).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := srs.statsRepository.GetFilmsPerYear(params)
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Rows error on query", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		srs.mock.ExpectQuery(getFilmsPerYear).WillReturnRows(rows().RowError(1, testError))
+
+		t.NewStep("Check result")
+		_, err := srs.statsRepository.GetFilmsPerYear(params)
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Incorrect field in row of query", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		srs.mock.ExpectQuery(getFilmsPerYear).WillReturnRows(rows().AddRow("year", 1)).
+			RowsWillBeClosed()
+
+		t.NewStep("Check result")
+		_, err := srs.statsRepository.GetFilmsPerYear(params)
+		t.Require().Error(err)
+	})
+}
+
+func (srs *StatsRepositorySuite) TestGetRatingDistributionFunction(t provider.T) {
+	t.Title("GetRatingDistribution function of Stats repository")
+	t.NewStep("Init test data")
+	columns := []string{"rating", "count"}
+
+	rows := func() *sqlxmock.Rows {
+		return sqlxmock.NewRows(columns).
+			AddRow(0, 0).
+			AddRow(1, 3)
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		srs.mock.ExpectQuery(getRatingDistribution).
+			WithArgs(sql.NullTime{}, sql.NullTime{}).
+			WillReturnRows(rows())
+
+		t.NewStep("Check result")
+		res, err := srs.statsRepository.GetRatingDistribution(Params{})
+		t.Require().NoError(err)
+		t.Require().EqualValues([]RatingCount{{Rating: 0, Count: 0}, {Rating: 1, Count: 3}}, res)
+	})
+
+	t.WithNewStep("Postgres error on execute query", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		srs.mock.ExpectQuery(getRatingDistribution).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := srs.statsRepository.GetRatingDistribution(Params{})
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Rows close error on query", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		srs.mock.ExpectQuery(getRatingDistribution).WillReturnRows(rows().CloseError(testError))
+
+		t.NewStep("Check result")
+		_, err := srs.statsRepository.GetRatingDistribution(Params{})
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (srs *StatsRepositorySuite) TestGetTopActorsFunction(t provider.T) {
+	t.Title("GetTopActors function of Stats repository")
+	t.NewStep("Init test data")
+	columns := []string{"id", "name", "films_count"}
+
+	rows := func() *sqlxmock.Rows {
+		return sqlxmock.NewRows(columns).
+			AddRow(1, "actor", 10).
+			AddRow(2, "actor", 7)
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		srs.mock.ExpectQuery(getTopActors).
+			WithArgs(sql.NullTime{}, sql.NullTime{}, sql.NullInt64{Valid: true, Int64: 2}).
+			WillReturnRows(rows())
+
+		t.NewStep("Check result")
+		res, err := srs.statsRepository.GetTopActors(Params{Limit: 2})
+		t.Require().NoError(err)
+		t.Require().EqualValues([]ActorFilmsCount{
+			{ID: 1, Name: "actor", FilmsCount: 10},
+			{ID: 2, Name: "actor", FilmsCount: 7},
+		}, res)
+	})
+
+	t.WithNewStep("Correct without limit execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		srs.mock.ExpectQuery(getTopActors).
+			WithArgs(sql.NullTime{}, sql.NullTime{}, sql.NullInt64{}).
+			WillReturnRows(sqlxmock.NewRows(columns))
+
+		t.NewStep("Check result")
+		res, err := srs.statsRepository.GetTopActors(Params{})
+		t.Require().NoError(err)
+		t.Require().EqualValues([]ActorFilmsCount{}, res)
+	})
+
+	t.WithNewStep("Postgres error on execute query", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		srs.mock.ExpectQuery(getTopActors).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := srs.statsRepository.GetTopActors(Params{})
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Rows error on query", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		srs.mock.ExpectQuery(getTopActors).WillReturnRows(rows().RowError(1, testError))
+
+		t.NewStep("Check result")
+		_, err := srs.statsRepository.GetTopActors(Params{})
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (srs *StatsRepositorySuite) TestGetActorsAverageRatingFunction(t provider.T) {
+	t.Title("GetActorsAverageRating function of Stats repository")
+	t.NewStep("Init test data")
+	columns := []string{"id", "name", "average_rating", "count"}
+
+	rows := func() *sqlxmock.Rows {
+		return sqlxmock.NewRows(columns).
+			AddRow(1, "actor", 7.5, 2)
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		srs.mock.ExpectQuery(getActorsAverageRating).
+			WithArgs(sql.NullTime{}, sql.NullTime{}, sql.NullInt64{}).
+			WillReturnRows(rows())
+
+		t.NewStep("Check result")
+		res, err := srs.statsRepository.GetActorsAverageRating(Params{})
+		t.Require().NoError(err)
+		t.Require().EqualValues([]ActorRating{{ID: 1, Name: "actor", AverageRating: 7.5, FilmsCount: 2}}, res)
+	})
+
+	t.WithNewStep("Postgres error on execute query", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		srs.mock.ExpectQuery(getActorsAverageRating).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := srs.statsRepository.GetActorsAverageRating(Params{})
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Incorrect field in row of query", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		srs.mock.ExpectQuery(getActorsAverageRating).WillReturnRows(rows().AddRow(1, "actor", "rating", 1)).
+			RowsWillBeClosed()
+
+		t.NewStep("Check result")
+		_, err := srs.statsRepository.GetActorsAverageRating(Params{})
+		t.Require().Error(err)
+	})
+}
+
+func (srs *StatsRepositorySuite) TestGetFilmsWithoutActorsFunction(t provider.T) {
+	t.Title("GetFilmsWithoutActors function of Stats repository")
+	t.NewStep("Init test data")
+	flm := film.Film{
+		ID:          1,
+		Name:        "Dune",
+		Description: "good film",
+		DataPublish: time.MustParse("12.03.2003"),
+		Rating:      10,
+	}
+
+	columns := []string{"id", "name", "description", "publish_date", "rating"}
+
+	rows := func() *sqlxmock.Rows {
+		return sqlxmock.NewRows(columns).
+			AddRow(flm.ID, flm.Name, flm.Description, flm.DataPublish.Time, flm.Rating)
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		srs.mock.ExpectQuery(getFilmsWithoutActors).
+			WithArgs(sql.NullTime{}, sql.NullTime{}, pq.StringArray(nil)).
+			WillReturnRows(rows())
+
+		t.NewStep("Check result")
+		res, err := srs.statsRepository.GetFilmsWithoutActors(Params{})
+		t.Require().NoError(err)
+		t.Require().EqualValues([]film.Film{flm}, res)
+	})
+
+	t.WithNewStep("Correct execute with max certification", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		maxCertification := types.AGE12
+		srs.mock.ExpectQuery(getFilmsWithoutActors).
+			WithArgs(sql.NullTime{}, sql.NullTime{}, pq.StringArray{"0+", "6+", "12+", "G", "PG"}).
+			WillReturnRows(rows())
+
+		t.NewStep("Check result")
+		res, err := srs.statsRepository.GetFilmsWithoutActors(Params{MaxCertification: &maxCertification})
+		t.Require().NoError(err)
+		t.Require().EqualValues([]film.Film{flm}, res)
+	})
+
+	t.WithNewStep("Postgres error on execute query", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		srs.mock.ExpectQuery(getFilmsWithoutActors).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := srs.statsRepository.GetFilmsWithoutActors(Params{})
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Rows error on query", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		srs.mock.ExpectQuery(getFilmsWithoutActors).WillReturnRows(rows().RowError(0, testError))
+
+		t.NewStep("Check result")
+		_, err := srs.statsRepository.GetFilmsWithoutActors(Params{})
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (srs *StatsRepositorySuite) TestGetActorsWithoutFilmsFunction(t provider.T) {
+	t.Title("GetActorsWithoutFilms function of Stats repository")
+	t.NewStep("Init test data")
+	act := actor.Actor{
+		ID:       1,
+		Name:     "actor",
+		Sex:      types.FEMALE,
+		Birthday: time.MustParse("12.03.2003"),
+	}
+
+	columns := []string{"id", "name", "sex", "birthday"}
+
+	rows := func() *sqlxmock.Rows {
+		return sqlxmock.NewRows(columns).
+			AddRow(act.ID, act.Name, act.Sex, act.Birthday.Time)
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		srs.mock.ExpectQuery(getActorsWithoutFilms).
+			WithArgs(sql.NullTime{}, sql.NullTime{}).
+			WillReturnRows(rows())
+
+		t.NewStep("Check result")
+		res, err := srs.statsRepository.GetActorsWithoutFilms(Params{})
+		t.Require().NoError(err)
+		t.Require().EqualValues([]actor.Actor{act}, res)
+	})
+
+	t.WithNewStep("Postgres error on execute query", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		srs.mock.ExpectQuery(getActorsWithoutFilms).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := srs.statsRepository.GetActorsWithoutFilms(Params{})
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Incorrect field in row of query", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		srs.mock.ExpectQuery(getActorsWithoutFilms).WillReturnRows(rows().AddRow(1, 1, 1, 1)).
+			RowsWillBeClosed()
+
+		t.NewStep("Check result")
+		_, err := srs.statsRepository.GetActorsWithoutFilms(Params{})
+		t.Require().Error(err)
+	})
+}
+
+func TestRunStatsRepositorySuite(t *testing.T) {
+	suite.RunSuite(t, new(StatsRepositorySuite))
+}
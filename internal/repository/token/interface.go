@@ -0,0 +1,40 @@
+package token
+
+import (
+	"github.com/pkg/errors"
+	"vk_film/internal/pkg/types"
+)
+
+var (
+	ErrorTokenNotFound          = errors.New("token not found")
+	ErrorTokenNameAlreadyExists = errors.New("token with this name already exists")
+)
+
+//go:generate mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=TokenRepository . Repository
+
+type Repository interface {
+	// CreateToken
+	// Only the hash of the token is stored.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorTokenNameAlreadyExists
+	CreateToken(token *Token, hash string) (*Token, error)
+
+	// GetUserTokens
+	// Returns Error:
+	//   - SQLError
+	GetUserTokens(userId types.Id) ([]Token, error)
+
+	// DeleteToken
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorTokenNotFound
+	DeleteToken(userId, tokenId types.Id) error
+
+	// UseToken
+	// Finds not expired token by its hash and updates the last usage time.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorTokenNotFound
+	UseToken(hash string) (*Token, error)
+}
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: vk_film/internal/repository/token (interfaces: Repository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=TokenRepository . Repository
+//
+
+// Package mr is a generated GoMock package.
+package mr
+
+import (
+	reflect "reflect"
+	types "vk_film/internal/pkg/types"
+	token "vk_film/internal/repository/token"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// TokenRepository is a mock of Repository interface.
+type TokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *TokenRepositoryMockRecorder
+}
+
+// TokenRepositoryMockRecorder is the mock recorder for TokenRepository.
+type TokenRepositoryMockRecorder struct {
+	mock *TokenRepository
+}
+
+// NewTokenRepository creates a new mock instance.
+func NewTokenRepository(ctrl *gomock.Controller) *TokenRepository {
+	mock := &TokenRepository{ctrl: ctrl}
+	mock.recorder = &TokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *TokenRepository) EXPECT() *TokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateToken mocks base method.
+func (m *TokenRepository) CreateToken(arg0 *token.Token, arg1 string) (*token.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateToken", arg0, arg1)
+	ret0, _ := ret[0].(*token.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateToken indicates an expected call of CreateToken.
+func (mr *TokenRepositoryMockRecorder) CreateToken(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToken", reflect.TypeOf((*TokenRepository)(nil).CreateToken), arg0, arg1)
+}
+
+// DeleteToken mocks base method.
+func (m *TokenRepository) DeleteToken(arg0, arg1 types.Id) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteToken", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteToken indicates an expected call of DeleteToken.
+func (mr *TokenRepositoryMockRecorder) DeleteToken(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteToken", reflect.TypeOf((*TokenRepository)(nil).DeleteToken), arg0, arg1)
+}
+
+// GetUserTokens mocks base method.
+func (m *TokenRepository) GetUserTokens(arg0 types.Id) ([]token.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserTokens", arg0)
+	ret0, _ := ret[0].([]token.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserTokens indicates an expected call of GetUserTokens.
+func (mr *TokenRepositoryMockRecorder) GetUserTokens(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTokens", reflect.TypeOf((*TokenRepository)(nil).GetUserTokens), arg0)
+}
+
+// UseToken mocks base method.
+func (m *TokenRepository) UseToken(arg0 string) (*token.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseToken", arg0)
+	ret0, _ := ret[0].(*token.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseToken indicates an expected call of UseToken.
+func (mr *TokenRepositoryMockRecorder) UseToken(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseToken", reflect.TypeOf((*TokenRepository)(nil).UseToken), arg0)
+}
@@ -0,0 +1,16 @@
+package token
+
+import (
+	"time"
+	"vk_film/internal/pkg/types"
+)
+
+type Token struct {
+	ID         types.Id
+	UserID     types.Id
+	Name       string
+	ReadOnly   bool
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+}
@@ -0,0 +1,134 @@
+package token
+
+import (
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"time"
+	"vk_film/internal/pkg/types"
+)
+
+const (
+	createToken = `
+		INSERT INTO api_tokens (user_id, name, token_hash, read_only, expires_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (user_id, name) DO NOTHING
+			RETURNING id, user_id, name, read_only, created_at, expires_at, last_used_at
+	`
+
+	getUserTokens = `
+		SELECT id, user_id, name, read_only, created_at, expires_at, last_used_at FROM api_tokens
+			WHERE user_id = $1
+			ORDER BY created_at, id
+	`
+
+	deleteToken = `
+		DELETE FROM api_tokens WHERE user_id = $1 AND id = $2
+	`
+
+	useToken = `
+		UPDATE api_tokens SET last_used_at = now()
+			WHERE token_hash = $1 AND (expires_at IS NULL OR expires_at > now())
+			RETURNING id, user_id, name, read_only, created_at, expires_at, last_used_at
+	`
+)
+
+type PostgresToken struct {
+	db *sqlx.DB
+}
+
+func NewPostgresToken(db *sqlx.DB) *PostgresToken {
+	return &PostgresToken{
+		db: db,
+	}
+}
+
+var _ = Repository(&PostgresToken{})
+
+func scanToken(row interface{ Scan(...any) error }, token *Token) error {
+	return row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Name,
+		&token.ReadOnly,
+		&token.CreatedAt,
+		&token.ExpiresAt,
+		&token.LastUsedAt,
+	)
+}
+
+func (pt *PostgresToken) CreateToken(token *Token, hash string) (*Token, error) {
+	expiresAt := sql.Null[time.Time]{Valid: false}
+	if token.ExpiresAt != nil {
+		expiresAt = sql.Null[time.Time]{Valid: true, V: *token.ExpiresAt}
+	}
+
+	createdToken := &Token{}
+	row := pt.db.QueryRowx(createToken, token.UserID, token.Name, hash, token.ReadOnly, expiresAt)
+	if err := scanToken(row, createdToken); err != nil {
+		// Пустой результат означает, что токен с таким именем уже есть у пользователя
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrapf(ErrorTokenNameAlreadyExists, "with name %s", token.Name)
+		}
+		return nil, errors.Wrapf(err, "can't create token %s for user %d", token.Name, token.UserID)
+	}
+
+	return createdToken, nil
+}
+
+func (pt *PostgresToken) GetUserTokens(userId types.Id) ([]Token, error) {
+	rows, err := pt.db.Queryx(getUserTokens, userId)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't execute get user tokens query")
+	}
+	defer rows.Close()
+
+	tokens := make([]Token, 0)
+
+	for rows.Next() {
+		var token Token
+
+		if err := scanToken(rows, &token); err != nil {
+			return nil, errors.Wrap(err, "can't scan get user tokens query result")
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "can't end scan get user tokens query result")
+	}
+
+	return tokens, nil
+}
+
+func (pt *PostgresToken) DeleteToken(userId, tokenId types.Id) error {
+	res, err := pt.db.Exec(deleteToken, userId, tokenId)
+	if err != nil {
+		return errors.Wrapf(err, "can't execute deleting query for token %d", tokenId)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrapf(err, "can't get number affected rows of deleting query for token %d", tokenId)
+	}
+
+	if n != 1 {
+		return errors.Wrapf(ErrorTokenNotFound, "with id %d", tokenId)
+	}
+
+	return nil
+}
+
+func (pt *PostgresToken) UseToken(hash string) (*Token, error) {
+	token := &Token{}
+
+	if err := scanToken(pt.db.QueryRowx(useToken, hash), token); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrorTokenNotFound
+		}
+		return nil, errors.Wrap(err, "can't use token")
+	}
+
+	return token, nil
+}
@@ -0,0 +1,262 @@
+package token
+
+import (
+	"database/sql"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/pkg/errors"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+var testError = errors.New("test error")
+
+type TokenRepositorySuite struct {
+	suite.Suite
+	tokenRepository *PostgresToken
+	mock            sqlxmock.Sqlmock
+}
+
+func (trs *TokenRepositorySuite) BeforeEach(t provider.T) {
+	db, mock, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	t.Require().NoError(err)
+	trs.tokenRepository = NewPostgresToken(db)
+	trs.mock = mock
+}
+
+func (trs *TokenRepositorySuite) AfterEach(t provider.T) {
+	t.Require().NoError(trs.mock.ExpectationsWereMet())
+}
+
+var tokenColumns = []string{
+	"id", "user_id", "name", "read_only", "created_at", "expires_at", "last_used_at",
+}
+
+func newTestToken() *Token {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &Token{
+		ID:        1,
+		UserID:    2,
+		Name:      "ci",
+		ReadOnly:  true,
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt: &expiresAt,
+	}
+}
+
+func (trs *TokenRepositorySuite) TestCreateFunction(t provider.T) {
+	t.Title("CreateToken function of Token repository")
+	t.NewStep("Init test data")
+	token := newTestToken()
+	hash := "hash"
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(createToken).
+			WithArgs(token.UserID, token.Name, hash, token.ReadOnly, sql.Null[time.Time]{Valid: true, V: *token.ExpiresAt}).
+			WillReturnRows(
+				sqlxmock.NewRows(tokenColumns).
+					AddRow(token.ID, token.UserID, token.Name, token.ReadOnly, token.CreatedAt, *token.ExpiresAt, nil),
+			)
+
+		t.NewStep("Check result")
+		createdToken, err := trs.tokenRepository.CreateToken(token, hash)
+		t.Require().NoError(err)
+		t.Require().EqualValues(token, createdToken)
+	})
+
+	t.WithNewStep("Correct execute without expiration", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(createToken).
+			WithArgs(token.UserID, token.Name, hash, token.ReadOnly, sql.Null[time.Time]{}).
+			WillReturnRows(
+				sqlxmock.NewRows(tokenColumns).
+					AddRow(token.ID, token.UserID, token.Name, token.ReadOnly, token.CreatedAt, nil, nil),
+			)
+
+		t.NewStep("Check result")
+		createdToken, err := trs.tokenRepository.CreateToken(&Token{
+			UserID:   token.UserID,
+			Name:     token.Name,
+			ReadOnly: token.ReadOnly,
+		}, hash)
+		t.Require().NoError(err)
+		t.Require().Nil(createdToken.ExpiresAt)
+	})
+
+	t.WithNewStep("Token name conflict in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(createToken).
+			WithArgs(token.UserID, token.Name, hash, token.ReadOnly, sql.Null[time.Time]{Valid: true, V: *token.ExpiresAt}).
+			WillReturnRows(sqlxmock.NewRows(tokenColumns))
+
+		t.NewStep("Check result")
+		_, err := trs.tokenRepository.CreateToken(token, hash)
+		t.Require().ErrorIs(err, ErrorTokenNameAlreadyExists)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(createToken).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := trs.tokenRepository.CreateToken(token, hash)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (trs *TokenRepositorySuite) TestGetUserTokensFunction(t provider.T) {
+	t.Title("GetUserTokens function of Token repository")
+	t.NewStep("Init test data")
+	token := newTestToken()
+	lastUsedAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	usedToken := *token
+	usedToken.LastUsedAt = &lastUsedAt
+
+	rows := func() *sqlxmock.Rows {
+		return sqlxmock.NewRows(tokenColumns).
+			AddRow(token.ID, token.UserID, token.Name, token.ReadOnly, token.CreatedAt, *token.ExpiresAt, nil).
+			AddRow(token.ID, token.UserID, token.Name, token.ReadOnly, token.CreatedAt, *token.ExpiresAt, lastUsedAt)
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(getUserTokens).WithArgs(token.UserID).WillReturnRows(rows())
+
+		t.NewStep("Check result")
+		tokens, err := trs.tokenRepository.GetUserTokens(token.UserID)
+		t.Require().NoError(err)
+		t.Require().EqualValues([]Token{*token, usedToken}, tokens)
+	})
+
+	t.WithNewStep("Empty list in execute result", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(getUserTokens).WithArgs(token.UserID).WillReturnRows(sqlxmock.NewRows(tokenColumns))
+
+		t.NewStep("Check result")
+		tokens, err := trs.tokenRepository.GetUserTokens(token.UserID)
+		t.Require().NoError(err)
+		t.Require().EqualValues([]Token{}, tokens)
+	})
+
+	t.WithNewStep("Postgres error on execute query", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(getUserTokens).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := trs.tokenRepository.GetUserTokens(token.UserID)
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Rows error on query", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(getUserTokens).WillReturnRows(rows().RowError(1, testError))
+
+		t.NewStep("Check result")
+		_, err := trs.tokenRepository.GetUserTokens(token.UserID)
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Incorrect field in row of query", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(getUserTokens).WillReturnRows(rows().AddRow(1, 1, 1, 1, 1, 1, 1)).
+			RowsWillBeClosed()
+
+		t.NewStep("Check result")
+		_, err := trs.tokenRepository.GetUserTokens(token.UserID)
+		t.Require().Error(err)
+	})
+}
+
+func (trs *TokenRepositorySuite) TestDeleteFunction(t provider.T) {
+	t.Title("DeleteToken function of Token repository")
+	t.NewStep("Init test data")
+	token := newTestToken()
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(deleteToken).
+			WithArgs(token.UserID, token.ID).
+			WillReturnResult(sqlxmock.NewResult(0, 1))
+
+		t.NewStep("Check result")
+		t.Require().NoError(trs.tokenRepository.DeleteToken(token.UserID, token.ID))
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(deleteToken).
+			WithArgs(token.UserID, token.ID).
+			WillReturnError(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(trs.tokenRepository.DeleteToken(token.UserID, token.ID), testError)
+	})
+
+	t.WithNewStep("Row affected error of execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(deleteToken).
+			WithArgs(token.UserID, token.ID).
+			WillReturnResult(sqlxmock.NewErrorResult(testError))
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(trs.tokenRepository.DeleteToken(token.UserID, token.ID), testError)
+	})
+
+	t.WithNewStep("Error not found token in execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(deleteToken).
+			WithArgs(token.UserID, token.ID).
+			WillReturnResult(sqlxmock.NewResult(0, 0))
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(trs.tokenRepository.DeleteToken(token.UserID, token.ID), ErrorTokenNotFound)
+	})
+}
+
+func (trs *TokenRepositorySuite) TestUseFunction(t provider.T) {
+	t.Title("UseToken function of Token repository")
+	t.NewStep("Init test data")
+	token := newTestToken()
+	lastUsedAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	token.LastUsedAt = &lastUsedAt
+	hash := "hash"
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(useToken).
+			WithArgs(hash).
+			WillReturnRows(
+				sqlxmock.NewRows(tokenColumns).
+					AddRow(token.ID, token.UserID, token.Name, token.ReadOnly, token.CreatedAt, *token.ExpiresAt, lastUsedAt),
+			)
+
+		t.NewStep("Check result")
+		usedToken, err := trs.tokenRepository.UseToken(hash)
+		t.Require().NoError(err)
+		t.Require().EqualValues(token, usedToken)
+	})
+
+	t.WithNewStep("Token not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(useToken).WithArgs(hash).WillReturnRows(sqlxmock.NewRows(tokenColumns))
+
+		t.NewStep("Check result")
+		_, err := trs.tokenRepository.UseToken(hash)
+		t.Require().ErrorIs(err, ErrorTokenNotFound)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(useToken).WithArgs(hash).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := trs.tokenRepository.UseToken(hash)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func TestRunTokenRepositorySuite(t *testing.T) {
+	suite.RunSuite(t, new(TokenRepositorySuite))
+}
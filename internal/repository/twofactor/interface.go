@@ -0,0 +1,85 @@
+package twofactor
+
+import (
+	"github.com/pkg/errors"
+	"vk_film/internal/pkg/types"
+)
+
+var (
+	ErrorSecretNotFound       = errors.New("totp secret not found")
+	ErrorAlreadyEnabled       = errors.New("two-factor authentication is already enabled")
+	ErrorCodeAlreadyUsed      = errors.New("totp code was already used")
+	ErrorRecoveryCodeNotFound = errors.New("recovery code not found")
+	ErrorChallengeNotFound    = errors.New("login challenge not found")
+)
+
+//go:generate mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=TwoFactorRepository . Repository
+
+type Repository interface {
+	// GetSecret
+	// Returns the totp secret of the user, both enabled and not confirmed yet.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorSecretNotFound
+	GetSecret(userId types.Id) (*Secret, error)
+
+	// SetSecret
+	// Saves a new not confirmed secret of the user replacing the previous not confirmed one.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorAlreadyEnabled
+	SetSecret(userId types.Id, secret string) error
+
+	// Enable
+	// Enables the not confirmed secret of the user and replaces his recovery codes.
+	// Only hashes of recovery codes are stored.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorSecretNotFound
+	Enable(userId types.Id, recoveryCodeHashes []string) error
+
+	// Delete
+	// Deletes the secret and the recovery codes of the user.
+	// Returns Error:
+	//   - SQLError
+	Delete(userId types.Id) error
+
+	// UseCounter
+	// Remembers the time step of the accepted code. A code of the same or an earlier step can't be used again.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorCodeAlreadyUsed
+	UseCounter(userId types.Id, counter uint64) error
+
+	// UseRecoveryCode
+	// Marks the recovery code of the user as used.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorRecoveryCodeNotFound
+	UseRecoveryCode(userId types.Id, codeHash string) error
+
+	// CreateChallenge
+	// Saves the login waiting for the second factor. Only the hash of the challenge token is stored.
+	// Returns Error:
+	//   - SQLError
+	CreateChallenge(challenge *Challenge, hash string) error
+
+	// GetChallenge
+	// Finds not expired challenge by its hash.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorChallengeNotFound
+	GetChallenge(hash string) (*Challenge, error)
+
+	// AddChallengeAttempt
+	// Increments the number of failed attempts of the challenge and returns the new number.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorChallengeNotFound
+	AddChallengeAttempt(hash string) (uint64, error)
+
+	// DelChallenge
+	// Returns Error:
+	//   - SQLError
+	DelChallenge(hash string) error
+}
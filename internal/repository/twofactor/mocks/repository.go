@@ -0,0 +1,184 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: vk_film/internal/repository/twofactor (interfaces: Repository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=TwoFactorRepository . Repository
+//
+
+// Package mr is a generated GoMock package.
+package mr
+
+import (
+	reflect "reflect"
+	types "vk_film/internal/pkg/types"
+	twofactor "vk_film/internal/repository/twofactor"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// TwoFactorRepository is a mock of Repository interface.
+type TwoFactorRepository struct {
+	ctrl     *gomock.Controller
+	recorder *TwoFactorRepositoryMockRecorder
+}
+
+// TwoFactorRepositoryMockRecorder is the mock recorder for TwoFactorRepository.
+type TwoFactorRepositoryMockRecorder struct {
+	mock *TwoFactorRepository
+}
+
+// NewTwoFactorRepository creates a new mock instance.
+func NewTwoFactorRepository(ctrl *gomock.Controller) *TwoFactorRepository {
+	mock := &TwoFactorRepository{ctrl: ctrl}
+	mock.recorder = &TwoFactorRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *TwoFactorRepository) EXPECT() *TwoFactorRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddChallengeAttempt mocks base method.
+func (m *TwoFactorRepository) AddChallengeAttempt(arg0 string) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddChallengeAttempt", arg0)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddChallengeAttempt indicates an expected call of AddChallengeAttempt.
+func (mr *TwoFactorRepositoryMockRecorder) AddChallengeAttempt(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddChallengeAttempt", reflect.TypeOf((*TwoFactorRepository)(nil).AddChallengeAttempt), arg0)
+}
+
+// CreateChallenge mocks base method.
+func (m *TwoFactorRepository) CreateChallenge(arg0 *twofactor.Challenge, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChallenge", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateChallenge indicates an expected call of CreateChallenge.
+func (mr *TwoFactorRepositoryMockRecorder) CreateChallenge(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChallenge", reflect.TypeOf((*TwoFactorRepository)(nil).CreateChallenge), arg0, arg1)
+}
+
+// DelChallenge mocks base method.
+func (m *TwoFactorRepository) DelChallenge(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DelChallenge", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DelChallenge indicates an expected call of DelChallenge.
+func (mr *TwoFactorRepositoryMockRecorder) DelChallenge(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DelChallenge", reflect.TypeOf((*TwoFactorRepository)(nil).DelChallenge), arg0)
+}
+
+// Delete mocks base method.
+func (m *TwoFactorRepository) Delete(arg0 types.Id) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *TwoFactorRepositoryMockRecorder) Delete(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*TwoFactorRepository)(nil).Delete), arg0)
+}
+
+// Enable mocks base method.
+func (m *TwoFactorRepository) Enable(arg0 types.Id, arg1 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enable", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enable indicates an expected call of Enable.
+func (mr *TwoFactorRepositoryMockRecorder) Enable(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enable", reflect.TypeOf((*TwoFactorRepository)(nil).Enable), arg0, arg1)
+}
+
+// GetChallenge mocks base method.
+func (m *TwoFactorRepository) GetChallenge(arg0 string) (*twofactor.Challenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChallenge", arg0)
+	ret0, _ := ret[0].(*twofactor.Challenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChallenge indicates an expected call of GetChallenge.
+func (mr *TwoFactorRepositoryMockRecorder) GetChallenge(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChallenge", reflect.TypeOf((*TwoFactorRepository)(nil).GetChallenge), arg0)
+}
+
+// GetSecret mocks base method.
+func (m *TwoFactorRepository) GetSecret(arg0 types.Id) (*twofactor.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecret", arg0)
+	ret0, _ := ret[0].(*twofactor.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSecret indicates an expected call of GetSecret.
+func (mr *TwoFactorRepositoryMockRecorder) GetSecret(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecret", reflect.TypeOf((*TwoFactorRepository)(nil).GetSecret), arg0)
+}
+
+// SetSecret mocks base method.
+func (m *TwoFactorRepository) SetSecret(arg0 types.Id, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSecret", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSecret indicates an expected call of SetSecret.
+func (mr *TwoFactorRepositoryMockRecorder) SetSecret(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSecret", reflect.TypeOf((*TwoFactorRepository)(nil).SetSecret), arg0, arg1)
+}
+
+// UseCounter mocks base method.
+func (m *TwoFactorRepository) UseCounter(arg0 types.Id, arg1 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseCounter", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseCounter indicates an expected call of UseCounter.
+func (mr *TwoFactorRepositoryMockRecorder) UseCounter(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseCounter", reflect.TypeOf((*TwoFactorRepository)(nil).UseCounter), arg0, arg1)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *TwoFactorRepository) UseRecoveryCode(arg0 types.Id, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *TwoFactorRepositoryMockRecorder) UseRecoveryCode(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*TwoFactorRepository)(nil).UseRecoveryCode), arg0, arg1)
+}
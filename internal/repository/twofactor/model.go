@@ -0,0 +1,25 @@
+package twofactor
+
+import (
+	"time"
+	"vk_film/internal/pkg/types"
+)
+
+// Secret
+// Totp secret of the user. LastCounter is the time step of the last accepted code.
+type Secret struct {
+	UserID      types.Id
+	Secret      string
+	Enabled     bool
+	LastCounter uint64
+}
+
+// Challenge
+// Login that passed the password check and waits for the second factor
+type Challenge struct {
+	UserID    types.Id
+	ExpiresAt time.Time
+	IP        string
+	UserAgent string
+	Attempts  uint64
+}
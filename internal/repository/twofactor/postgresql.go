@@ -0,0 +1,232 @@
+package twofactor
+
+import (
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"vk_film/internal/pkg/types"
+)
+
+const (
+	getSecret = `
+		SELECT user_id, secret, enabled, last_counter FROM user_totp WHERE user_id = $1
+	`
+
+	setSecret = `
+		INSERT INTO user_totp (user_id, secret)
+			VALUES ($1, $2)
+			ON CONFLICT (user_id) DO UPDATE SET secret = excluded.secret, last_counter = 0, created_at = now()
+				WHERE user_totp.enabled = false
+	`
+
+	enable = `
+		WITH upd AS (
+			UPDATE user_totp SET enabled = true WHERE user_id = $1 AND enabled = false
+				RETURNING user_id
+		), del AS (
+			DELETE FROM totp_recovery_codes WHERE user_id IN (SELECT user_id FROM upd)
+		)
+		INSERT INTO totp_recovery_codes (user_id, code_hash)
+			SELECT upd.user_id, hash FROM upd, unnest($2::text[]) AS hash
+	`
+
+	deleteSecret = `
+		WITH codes AS (
+			DELETE FROM totp_recovery_codes WHERE user_id = $1
+		)
+		DELETE FROM user_totp WHERE user_id = $1
+	`
+
+	useCounter = `
+		UPDATE user_totp SET last_counter = $2 WHERE user_id = $1 AND last_counter < $2
+	`
+
+	useRecoveryCode = `
+		UPDATE totp_recovery_codes SET used_at = now()
+			WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	createChallenge = `
+		INSERT INTO login_challenges (challenge_hash, user_id, expires_at, ip, user_agent)
+			VALUES ($1, $2, $3, $4, $5)
+	`
+
+	getChallenge = `
+		SELECT user_id, expires_at, ip, user_agent, attempts FROM login_challenges
+			WHERE challenge_hash = $1 AND expires_at > now()
+	`
+
+	addChallengeAttempt = `
+		UPDATE login_challenges SET attempts = attempts + 1
+			WHERE challenge_hash = $1 AND expires_at > now()
+			RETURNING attempts
+	`
+
+	delChallenge = `
+		DELETE FROM login_challenges WHERE challenge_hash = $1 OR expires_at <= now()
+	`
+)
+
+type PostgresTwoFactor struct {
+	db *sqlx.DB
+}
+
+func NewPostgresTwoFactor(db *sqlx.DB) *PostgresTwoFactor {
+	return &PostgresTwoFactor{
+		db: db,
+	}
+}
+
+var _ = Repository(&PostgresTwoFactor{})
+
+func (pt *PostgresTwoFactor) GetSecret(userId types.Id) (*Secret, error) {
+	secret := &Secret{}
+
+	if err := pt.db.QueryRowx(getSecret, userId).
+		Scan(
+			&secret.UserID,
+			&secret.Secret,
+			&secret.Enabled,
+			&secret.LastCounter,
+		); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrorSecretNotFound
+		}
+		return nil, errors.Wrapf(err, "can't get totp secret of user %d", userId)
+	}
+
+	return secret, nil
+}
+
+func (pt *PostgresTwoFactor) SetSecret(userId types.Id, secret string) error {
+	res, err := pt.db.Exec(setSecret, userId, secret)
+	if err != nil {
+		return errors.Wrapf(err, "can't execute setting query for totp secret of user %d", userId)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrapf(err, "can't get number affected rows of setting query for totp secret of user %d", userId)
+	}
+
+	// Включённый секрет нельзя заменить, сначала его нужно отключить
+	if n == 0 {
+		return errors.Wrapf(ErrorAlreadyEnabled, "for user %d", userId)
+	}
+
+	return nil
+}
+
+func (pt *PostgresTwoFactor) Enable(userId types.Id, recoveryCodeHashes []string) error {
+	res, err := pt.db.Exec(enable, userId, pq.Array(recoveryCodeHashes))
+	if err != nil {
+		return errors.Wrapf(err, "can't execute enabling query for totp secret of user %d", userId)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrapf(err, "can't get number affected rows of enabling query for totp secret of user %d", userId)
+	}
+
+	if n == 0 {
+		return errors.Wrapf(ErrorSecretNotFound, "not confirmed for user %d", userId)
+	}
+
+	return nil
+}
+
+func (pt *PostgresTwoFactor) Delete(userId types.Id) error {
+	if _, err := pt.db.Exec(deleteSecret, userId); err != nil {
+		return errors.Wrapf(err, "can't delete totp secret of user %d", userId)
+	}
+
+	return nil
+}
+
+func (pt *PostgresTwoFactor) UseCounter(userId types.Id, counter uint64) error {
+	res, err := pt.db.Exec(useCounter, userId, counter)
+	if err != nil {
+		return errors.Wrapf(err, "can't execute using query for totp code of user %d", userId)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrapf(err, "can't get number affected rows of using query for totp code of user %d", userId)
+	}
+
+	// Код того же шага уже был принят, в том числе параллельным запросом
+	if n == 0 {
+		return errors.Wrapf(ErrorCodeAlreadyUsed, "with counter %d of user %d", counter, userId)
+	}
+
+	return nil
+}
+
+func (pt *PostgresTwoFactor) UseRecoveryCode(userId types.Id, codeHash string) error {
+	res, err := pt.db.Exec(useRecoveryCode, userId, codeHash)
+	if err != nil {
+		return errors.Wrapf(err, "can't execute using query for recovery code of user %d", userId)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrapf(err, "can't get number affected rows of using query for recovery code of user %d", userId)
+	}
+
+	if n == 0 {
+		return errors.Wrapf(ErrorRecoveryCodeNotFound, "of user %d", userId)
+	}
+
+	return nil
+}
+
+func (pt *PostgresTwoFactor) CreateChallenge(challenge *Challenge, hash string) error {
+	if _, err := pt.db.Exec(createChallenge, hash, challenge.UserID, challenge.ExpiresAt,
+		challenge.IP, challenge.UserAgent); err != nil {
+		return errors.Wrapf(err, "can't create login challenge of user %d", challenge.UserID)
+	}
+
+	return nil
+}
+
+func (pt *PostgresTwoFactor) GetChallenge(hash string) (*Challenge, error) {
+	challenge := &Challenge{}
+
+	if err := pt.db.QueryRowx(getChallenge, hash).
+		Scan(
+			&challenge.UserID,
+			&challenge.ExpiresAt,
+			&challenge.IP,
+			&challenge.UserAgent,
+			&challenge.Attempts,
+		); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrorChallengeNotFound
+		}
+		return nil, errors.Wrap(err, "can't get login challenge")
+	}
+
+	return challenge, nil
+}
+
+func (pt *PostgresTwoFactor) AddChallengeAttempt(hash string) (uint64, error) {
+	var attempts uint64
+	if err := pt.db.QueryRowx(addChallengeAttempt, hash).Scan(&attempts); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrorChallengeNotFound
+		}
+		return 0, errors.Wrap(err, "can't add attempt of login challenge")
+	}
+
+	return attempts, nil
+}
+
+func (pt *PostgresTwoFactor) DelChallenge(hash string) error {
+	// Вместе с challenge удаляются все истёкшие
+	if _, err := pt.db.Exec(delChallenge, hash); err != nil {
+		return errors.Wrap(err, "can't delete login challenge")
+	}
+
+	return nil
+}
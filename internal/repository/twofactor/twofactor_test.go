@@ -0,0 +1,362 @@
+package twofactor
+
+import (
+	"github.com/lib/pq"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/pkg/errors"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+	"vk_film/internal/pkg/types"
+)
+
+var testError = errors.New("test error")
+
+type TwoFactorRepositorySuite struct {
+	suite.Suite
+	twoFactorRepository *PostgresTwoFactor
+	mock                sqlxmock.Sqlmock
+}
+
+func (trs *TwoFactorRepositorySuite) BeforeEach(t provider.T) {
+	db, mock, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	t.Require().NoError(err)
+	trs.twoFactorRepository = NewPostgresTwoFactor(db)
+	trs.mock = mock
+}
+
+func (trs *TwoFactorRepositorySuite) AfterEach(t provider.T) {
+	t.Require().NoError(trs.mock.ExpectationsWereMet())
+}
+
+func newTestChallenge() *Challenge {
+	return &Challenge{
+		UserID:    2,
+		ExpiresAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		IP:        "127.0.0.1",
+		UserAgent: "curl/8.0",
+	}
+}
+
+func (trs *TwoFactorRepositorySuite) TestGetSecretFunction(t provider.T) {
+	t.Title("GetSecret function of TwoFactor repository")
+	t.NewStep("Init test data")
+	secret := &Secret{UserID: 2, Secret: "JBSWY3DPEHPK3PXP", Enabled: true, LastCounter: 100}
+	columns := []string{"user_id", "secret", "enabled", "last_counter"}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(getSecret).
+			WithArgs(secret.UserID).
+			WillReturnRows(
+				sqlxmock.NewRows(columns).AddRow(secret.UserID, secret.Secret, secret.Enabled, secret.LastCounter),
+			)
+
+		t.NewStep("Check result")
+		res, err := trs.twoFactorRepository.GetSecret(secret.UserID)
+		t.Require().NoError(err)
+		t.Require().EqualValues(secret, res)
+	})
+
+	t.WithNewStep("Secret not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(getSecret).WithArgs(secret.UserID).WillReturnRows(sqlxmock.NewRows(columns))
+
+		t.NewStep("Check result")
+		_, err := trs.twoFactorRepository.GetSecret(secret.UserID)
+		t.Require().ErrorIs(err, ErrorSecretNotFound)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(getSecret).WithArgs(secret.UserID).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := trs.twoFactorRepository.GetSecret(secret.UserID)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (trs *TwoFactorRepositorySuite) TestSetSecretFunction(t provider.T) {
+	t.Title("SetSecret function of TwoFactor repository")
+	t.NewStep("Init test data")
+	userId := types.Id(2)
+	secret := "JBSWY3DPEHPK3PXP"
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(setSecret).WithArgs(userId, secret).WillReturnResult(sqlxmock.NewResult(0, 1))
+
+		t.NewStep("Check result")
+		t.Require().NoError(trs.twoFactorRepository.SetSecret(userId, secret))
+	})
+
+	t.WithNewStep("Already enabled in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(setSecret).WithArgs(userId, secret).WillReturnResult(sqlxmock.NewResult(0, 0))
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(trs.twoFactorRepository.SetSecret(userId, secret), ErrorAlreadyEnabled)
+	})
+
+	t.WithNewStep("Row affected error of execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(setSecret).WithArgs(userId, secret).WillReturnResult(sqlxmock.NewErrorResult(testError))
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(trs.twoFactorRepository.SetSecret(userId, secret), testError)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(setSecret).WithArgs(userId, secret).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(trs.twoFactorRepository.SetSecret(userId, secret), testError)
+	})
+}
+
+func (trs *TwoFactorRepositorySuite) TestEnableFunction(t provider.T) {
+	t.Title("Enable function of TwoFactor repository")
+	t.NewStep("Init test data")
+	userId := types.Id(2)
+	hashes := []string{"first", "second"}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(enable).WithArgs(userId, pq.Array(hashes)).WillReturnResult(sqlxmock.NewResult(0, 2))
+
+		t.NewStep("Check result")
+		t.Require().NoError(trs.twoFactorRepository.Enable(userId, hashes))
+	})
+
+	t.WithNewStep("Secret not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(enable).WithArgs(userId, pq.Array(hashes)).WillReturnResult(sqlxmock.NewResult(0, 0))
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(trs.twoFactorRepository.Enable(userId, hashes), ErrorSecretNotFound)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(enable).WithArgs(userId, pq.Array(hashes)).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(trs.twoFactorRepository.Enable(userId, hashes), testError)
+	})
+}
+
+func (trs *TwoFactorRepositorySuite) TestDeleteFunction(t provider.T) {
+	t.Title("Delete function of TwoFactor repository")
+	t.NewStep("Init test data")
+	userId := types.Id(2)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(deleteSecret).WithArgs(userId).WillReturnResult(sqlxmock.NewResult(0, 1))
+
+		t.NewStep("Check result")
+		t.Require().NoError(trs.twoFactorRepository.Delete(userId))
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(deleteSecret).WithArgs(userId).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(trs.twoFactorRepository.Delete(userId), testError)
+	})
+}
+
+func (trs *TwoFactorRepositorySuite) TestUseCounterFunction(t provider.T) {
+	t.Title("UseCounter function of TwoFactor repository")
+	t.NewStep("Init test data")
+	userId := types.Id(2)
+	counter := uint64(100)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(useCounter).WithArgs(userId, counter).WillReturnResult(sqlxmock.NewResult(0, 1))
+
+		t.NewStep("Check result")
+		t.Require().NoError(trs.twoFactorRepository.UseCounter(userId, counter))
+	})
+
+	t.WithNewStep("Code already used in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(useCounter).WithArgs(userId, counter).WillReturnResult(sqlxmock.NewResult(0, 0))
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(trs.twoFactorRepository.UseCounter(userId, counter), ErrorCodeAlreadyUsed)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(useCounter).WithArgs(userId, counter).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(trs.twoFactorRepository.UseCounter(userId, counter), testError)
+	})
+}
+
+func (trs *TwoFactorRepositorySuite) TestUseRecoveryCodeFunction(t provider.T) {
+	t.Title("UseRecoveryCode function of TwoFactor repository")
+	t.NewStep("Init test data")
+	userId := types.Id(2)
+	hash := "hash"
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(useRecoveryCode).WithArgs(userId, hash).WillReturnResult(sqlxmock.NewResult(0, 1))
+
+		t.NewStep("Check result")
+		t.Require().NoError(trs.twoFactorRepository.UseRecoveryCode(userId, hash))
+	})
+
+	t.WithNewStep("Recovery code not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(useRecoveryCode).WithArgs(userId, hash).WillReturnResult(sqlxmock.NewResult(0, 0))
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(trs.twoFactorRepository.UseRecoveryCode(userId, hash), ErrorRecoveryCodeNotFound)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(useRecoveryCode).WithArgs(userId, hash).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(trs.twoFactorRepository.UseRecoveryCode(userId, hash), testError)
+	})
+}
+
+func (trs *TwoFactorRepositorySuite) TestCreateChallengeFunction(t provider.T) {
+	t.Title("CreateChallenge function of TwoFactor repository")
+	t.NewStep("Init test data")
+	challenge := newTestChallenge()
+	hash := "hash"
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(createChallenge).
+			WithArgs(hash, challenge.UserID, challenge.ExpiresAt, challenge.IP, challenge.UserAgent).
+			WillReturnResult(sqlxmock.NewResult(1, 1))
+
+		t.NewStep("Check result")
+		t.Require().NoError(trs.twoFactorRepository.CreateChallenge(challenge, hash))
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(createChallenge).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(trs.twoFactorRepository.CreateChallenge(challenge, hash), testError)
+	})
+}
+
+func (trs *TwoFactorRepositorySuite) TestGetChallengeFunction(t provider.T) {
+	t.Title("GetChallenge function of TwoFactor repository")
+	t.NewStep("Init test data")
+	challenge := newTestChallenge()
+	challenge.Attempts = 1
+	hash := "hash"
+	columns := []string{"user_id", "expires_at", "ip", "user_agent", "attempts"}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(getChallenge).
+			WithArgs(hash).
+			WillReturnRows(
+				sqlxmock.NewRows(columns).AddRow(
+					challenge.UserID, challenge.ExpiresAt, challenge.IP, challenge.UserAgent, challenge.Attempts,
+				),
+			)
+
+		t.NewStep("Check result")
+		res, err := trs.twoFactorRepository.GetChallenge(hash)
+		t.Require().NoError(err)
+		t.Require().EqualValues(challenge, res)
+	})
+
+	t.WithNewStep("Challenge not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(getChallenge).WithArgs(hash).WillReturnRows(sqlxmock.NewRows(columns))
+
+		t.NewStep("Check result")
+		_, err := trs.twoFactorRepository.GetChallenge(hash)
+		t.Require().ErrorIs(err, ErrorChallengeNotFound)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(getChallenge).WithArgs(hash).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := trs.twoFactorRepository.GetChallenge(hash)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (trs *TwoFactorRepositorySuite) TestAddChallengeAttemptFunction(t provider.T) {
+	t.Title("AddChallengeAttempt function of TwoFactor repository")
+	t.NewStep("Init test data")
+	hash := "hash"
+	columns := []string{"attempts"}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(addChallengeAttempt).WithArgs(hash).WillReturnRows(sqlxmock.NewRows(columns).AddRow(2))
+
+		t.NewStep("Check result")
+		res, err := trs.twoFactorRepository.AddChallengeAttempt(hash)
+		t.Require().NoError(err)
+		t.Require().Equal(uint64(2), res)
+	})
+
+	t.WithNewStep("Challenge not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(addChallengeAttempt).WithArgs(hash).WillReturnRows(sqlxmock.NewRows(columns))
+
+		t.NewStep("Check result")
+		_, err := trs.twoFactorRepository.AddChallengeAttempt(hash)
+		t.Require().ErrorIs(err, ErrorChallengeNotFound)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectQuery(addChallengeAttempt).WithArgs(hash).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := trs.twoFactorRepository.AddChallengeAttempt(hash)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (trs *TwoFactorRepositorySuite) TestDelChallengeFunction(t provider.T) {
+	t.Title("DelChallenge function of TwoFactor repository")
+	t.NewStep("Init test data")
+	hash := "hash"
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(delChallenge).WithArgs(hash).WillReturnResult(sqlxmock.NewResult(0, 1))
+
+		t.NewStep("Check result")
+		t.Require().NoError(trs.twoFactorRepository.DelChallenge(hash))
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		trs.mock.ExpectExec(delChallenge).WithArgs(hash).WillReturnError(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(trs.twoFactorRepository.DelChallenge(hash), testError)
+	})
+}
+
+func TestRunTwoFactorRepositorySuite(t *testing.T) {
+	suite.RunSuite(t, new(TwoFactorRepositorySuite))
+}
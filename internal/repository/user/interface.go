@@ -8,27 +8,69 @@ import (
 var (
 	ErrorUserNotFound       = errors.New("user not found")
 	ErrorLoginAlreadyExists = errors.New("user with this login already exists")
+	ErrorRoleNotFound       = errors.New("role of user not found")
+	ErrorStatusMismatch     = errors.New("user has another status")
+	ErrorEmailAlreadyExists = errors.New("user with this email already exists")
+	ErrorLastAdmin          = errors.New("user is the last active admin")
 )
 
 //go:generate mockgen -destination=mocks/repository.go -package=mr -mock_names=Repository=UserRepository . Repository
 
 type Repository interface {
 	// CreateUser
+	// Empty status creates an active user, empty email is not saved.
 	// Returns Error:
 	//   - SQLError
 	//   - ErrorLoginAlreadyExists
+	//   - ErrorEmailAlreadyExists
+	//   - ErrorRoleNotFound
 	CreateUser(user *User) (*User, error)
 
 	// UpdateUserRole
+	// Refuses to take the admin role away from the last active admin.
 	// Returns Error:
 	//   - SQLError
 	//   - ErrorUserNotFound
+	//   - ErrorRoleNotFound
+	//   - ErrorLastAdmin
 	UpdateUserRole(user *User) (*User, error)
 
+	// UpdateUserMaxCertification
+	// Nil MaxCertification removes the restriction.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorUserNotFound
+	UpdateUserMaxCertification(user *User) (*User, error)
+
+	// UpdateUserStatus
+	// Changes the status of the user only if it is equal to from.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorUserNotFound
+	//   - ErrorStatusMismatch
+	UpdateUserStatus(id types.Id, from, to Status) (*User, error)
+
+	// UpdateUserEmail
+	// Empty email removes the email of the user.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorUserNotFound
+	//   - ErrorEmailAlreadyExists
+	UpdateUserEmail(id types.Id, email string) error
+
+	// UpdateUserPassword
+	// Password must be already hashed.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorUserNotFound
+	UpdateUserPassword(user *User) error
+
 	// DeleteUser
+	// Refuses to delete the last active admin.
 	// Returns Error:
 	//   - SQLError
 	//   - ErrorUserNotFound
+	//   - ErrorLastAdmin
 	DeleteUser(id types.Id) error
 
 	// GetPasswordByLogin
@@ -37,14 +79,47 @@ type Repository interface {
 	//   - ErrorUserNotFound
 	GetPasswordByLogin(login string) (*LoginUser, error)
 
+	// GetPasswordById
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorUserNotFound
+	GetPasswordById(id types.Id) (*LoginUser, error)
+
 	// GetUserById
+	// Returns the user with the permissions of his role.
 	// Returns Error:
 	//   - SQLError
 	//   - ErrorUserNotFound
 	GetUserById(id types.Id) (*User, error)
 
+	// GetUserByLogin
+	// Returns the user without permissions.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorUserNotFound
+	GetUserByLogin(login string) (*User, error)
+
+	// GetUserByEmail
+	// Returns the user without permissions.
+	// Returns Error:
+	//   - SQLError
+	//   - ErrorUserNotFound
+	GetUserByEmail(email string) (*User, error)
+
 	// GetUsers
 	// Returns Error:
 	//   - SQLError
 	GetUsers() ([]User, error)
+
+	// GetUsersByStatus
+	// Returns users with the status in order of creation.
+	// Returns Error:
+	//   - SQLError
+	GetUsersByStatus(status Status) ([]User, error)
+
+	// CountActiveUsers
+	// Returns the number of active users with the role.
+	// Returns Error:
+	//   - SQLError
+	CountActiveUsers(role types.Roles) (int, error)
 }
@@ -40,6 +40,21 @@ func (m *UserRepository) EXPECT() *UserRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CountActiveUsers mocks base method.
+func (m *UserRepository) CountActiveUsers(arg0 types.Roles) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountActiveUsers", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountActiveUsers indicates an expected call of CountActiveUsers.
+func (mr *UserRepositoryMockRecorder) CountActiveUsers(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActiveUsers", reflect.TypeOf((*UserRepository)(nil).CountActiveUsers), arg0)
+}
+
 // CreateUser mocks base method.
 func (m *UserRepository) CreateUser(arg0 *user.User) (*user.User, error) {
 	m.ctrl.T.Helper()
@@ -69,6 +84,21 @@ func (mr *UserRepositoryMockRecorder) DeleteUser(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*UserRepository)(nil).DeleteUser), arg0)
 }
 
+// GetPasswordById mocks base method.
+func (m *UserRepository) GetPasswordById(arg0 types.Id) (*user.LoginUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasswordById", arg0)
+	ret0, _ := ret[0].(*user.LoginUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasswordById indicates an expected call of GetPasswordById.
+func (mr *UserRepositoryMockRecorder) GetPasswordById(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasswordById", reflect.TypeOf((*UserRepository)(nil).GetPasswordById), arg0)
+}
+
 // GetPasswordByLogin mocks base method.
 func (m *UserRepository) GetPasswordByLogin(arg0 string) (*user.LoginUser, error) {
 	m.ctrl.T.Helper()
@@ -84,6 +114,21 @@ func (mr *UserRepositoryMockRecorder) GetPasswordByLogin(arg0 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasswordByLogin", reflect.TypeOf((*UserRepository)(nil).GetPasswordByLogin), arg0)
 }
 
+// GetUserByEmail mocks base method.
+func (m *UserRepository) GetUserByEmail(arg0 string) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", arg0)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *UserRepositoryMockRecorder) GetUserByEmail(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*UserRepository)(nil).GetUserByEmail), arg0)
+}
+
 // GetUserById mocks base method.
 func (m *UserRepository) GetUserById(arg0 types.Id) (*user.User, error) {
 	m.ctrl.T.Helper()
@@ -99,6 +144,21 @@ func (mr *UserRepositoryMockRecorder) GetUserById(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*UserRepository)(nil).GetUserById), arg0)
 }
 
+// GetUserByLogin mocks base method.
+func (m *UserRepository) GetUserByLogin(arg0 string) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByLogin", arg0)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByLogin indicates an expected call of GetUserByLogin.
+func (mr *UserRepositoryMockRecorder) GetUserByLogin(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByLogin", reflect.TypeOf((*UserRepository)(nil).GetUserByLogin), arg0)
+}
+
 // GetUsers mocks base method.
 func (m *UserRepository) GetUsers() ([]user.User, error) {
 	m.ctrl.T.Helper()
@@ -114,6 +174,64 @@ func (mr *UserRepositoryMockRecorder) GetUsers() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*UserRepository)(nil).GetUsers))
 }
 
+// GetUsersByStatus mocks base method.
+func (m *UserRepository) GetUsersByStatus(arg0 user.Status) ([]user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersByStatus", arg0)
+	ret0, _ := ret[0].([]user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersByStatus indicates an expected call of GetUsersByStatus.
+func (mr *UserRepositoryMockRecorder) GetUsersByStatus(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByStatus", reflect.TypeOf((*UserRepository)(nil).GetUsersByStatus), arg0)
+}
+
+// UpdateUserEmail mocks base method.
+func (m *UserRepository) UpdateUserEmail(arg0 types.Id, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserEmail", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserEmail indicates an expected call of UpdateUserEmail.
+func (mr *UserRepositoryMockRecorder) UpdateUserEmail(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserEmail", reflect.TypeOf((*UserRepository)(nil).UpdateUserEmail), arg0, arg1)
+}
+
+// UpdateUserMaxCertification mocks base method.
+func (m *UserRepository) UpdateUserMaxCertification(arg0 *user.User) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserMaxCertification", arg0)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserMaxCertification indicates an expected call of UpdateUserMaxCertification.
+func (mr *UserRepositoryMockRecorder) UpdateUserMaxCertification(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserMaxCertification", reflect.TypeOf((*UserRepository)(nil).UpdateUserMaxCertification), arg0)
+}
+
+// UpdateUserPassword mocks base method.
+func (m *UserRepository) UpdateUserPassword(arg0 *user.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserPassword", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserPassword indicates an expected call of UpdateUserPassword.
+func (mr *UserRepositoryMockRecorder) UpdateUserPassword(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPassword", reflect.TypeOf((*UserRepository)(nil).UpdateUserPassword), arg0)
+}
+
 // UpdateUserRole mocks base method.
 func (m *UserRepository) UpdateUserRole(arg0 *user.User) (*user.User, error) {
 	m.ctrl.T.Helper()
@@ -128,3 +246,18 @@ func (mr *UserRepositoryMockRecorder) UpdateUserRole(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*UserRepository)(nil).UpdateUserRole), arg0)
 }
+
+// UpdateUserStatus mocks base method.
+func (m *UserRepository) UpdateUserStatus(arg0 types.Id, arg1, arg2 user.Status) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserStatus", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserStatus indicates an expected call of UpdateUserStatus.
+func (mr *UserRepositoryMockRecorder) UpdateUserStatus(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserStatus", reflect.TypeOf((*UserRepository)(nil).UpdateUserStatus), arg0, arg1, arg2)
+}
@@ -2,14 +2,41 @@ package user
 
 import "vk_film/internal/pkg/types"
 
+// Status
+// Status of the account. Only active users can log in, self-registered users are pending
+// until an admin approves or rejects them.
+type Status string
+
+const (
+	StatusActive   Status = "active"
+	StatusPending  Status = "pending"
+	StatusRejected Status = "rejected"
+)
+
 type User struct {
-	ID       types.Id
-	Login    string
-	Password string
-	Role     types.Roles
+	ID               types.Id
+	Login            string
+	Email            string
+	Password         string
+	Role             types.Roles
+	Status           Status
+	MaxCertification *types.Certification
+	Permissions      []types.Permission
+}
+
+// HasPermission
+// Checks that the role of the user grants the permission. Permissions are loaded only by GetUserById.
+func (u *User) HasPermission(permission types.Permission) bool {
+	for _, p := range u.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
 }
 
 type LoginUser struct {
 	ID       types.Id
 	Password string
+	Status   Status
 }
@@ -3,8 +3,10 @@ package user
 import (
 	"database/sql"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/role"
 )
 
 const (
@@ -14,8 +16,8 @@ const (
 				FROM users
 				WHERE login = $1 LIMIT 1
 		), ins as (
-			INSERT INTO users (login, password, role)
-				SELECT $1, $2, $3
+			INSERT INTO users (login, password, role, status, email)
+				SELECT $1, $2, $3, $4, NULLIF($5, '')
 			    WHERE not exists (select 1 from sel)
 			RETURNING id, login, role
 		)
@@ -27,7 +29,33 @@ const (
 	`
 
 	updateUser = `
-		UPDATE users SET role = $2 WHERE id = $1 RETURNING id, login, role
+		UPDATE users SET role = $2 WHERE id = $1 RETURNING id, login, role, max_certification
+	`
+
+	updateUserMaxCertification = `
+		UPDATE users SET max_certification = $2 WHERE id = $1 RETURNING id, login, role, max_certification
+	`
+
+	updateUserStatus = `
+		WITH upd AS (
+			UPDATE users SET status = $3
+				WHERE id = $1 AND status = $2
+				RETURNING id, login, role, status, max_certification
+		)
+		SELECT id, login, role, status, max_certification, true
+		FROM upd
+		UNION ALL
+		SELECT id, login, role, status, max_certification, false
+		FROM users
+		WHERE id = $1 AND not exists (select 1 from upd)
+	`
+
+	updateUserEmail = `
+		UPDATE users SET email = NULLIF($2, '') WHERE id = $1
+	`
+
+	updateUserPassword = `
+		UPDATE users SET password = $2 WHERE id = $1
 	`
 
 	deleteUser = `
@@ -35,15 +63,42 @@ const (
 	`
 
 	getUsers = `
-		SELECT id, login, role FROM users
+		SELECT id, login, role, status, max_certification FROM users
+	`
+
+	getUsersByStatus = `
+		SELECT id, login, role, status, max_certification FROM users WHERE status = $1 ORDER BY id
+	`
+
+	lockActiveAdmins = `
+		SELECT id FROM users WHERE role = 'admin' AND status = 'active' ORDER BY id FOR UPDATE
+	`
+
+	countActiveUsers = `
+		SELECT count(*) FROM users WHERE role = $1 AND status = 'active'
 	`
 
 	getPasswordByLogin = `
-		SELECT id, password FROM users WHERE login = $1
+		SELECT id, password, status FROM users WHERE login = $1
+	`
+
+	getPasswordById = `
+		SELECT id, password, status FROM users WHERE id = $1
+	`
+
+	getUserByLogin = `
+		SELECT id, login, coalesce(email, ''), role, status FROM users WHERE login = $1
+	`
+
+	getUserByEmail = `
+		SELECT id, login, coalesce(email, ''), role, status FROM users WHERE email = $1
 	`
 
 	getUserById = `
-		SELECT id, login, role FROM users WHERE id = $1
+		SELECT u.id, u.login, coalesce(u.email, ''), u.role, u.max_certification, r.permissions
+			FROM users u
+			JOIN roles r ON r.name = u.role
+			WHERE u.id = $1
 	`
 )
 
@@ -58,16 +113,21 @@ func NewPostgresUser(db *sqlx.DB) *PostgresUser {
 }
 
 func (pu *PostgresUser) CreateUser(user *User) (*User, error) {
+	status := user.Status
+	if status == "" {
+		status = StatusActive
+	}
+
 	newUser := &User{}
 	exists := false
-	if err := pu.db.QueryRowx(createQuery, user.Login, user.Password, user.Role).
+	if err := pu.db.QueryRowx(createQuery, user.Login, user.Password, user.Role, status, user.Email).
 		Scan(
 			&newUser.ID,
 			&newUser.Login,
 			&newUser.Role,
 			&exists,
 		); err != nil {
-		return nil, errors.Wrap(err, "can't create user")
+		return nil, errors.Wrap(checkEmailExistsError(checkRoleNotFoundError(err)), "can't create user")
 	}
 
 	if exists {
@@ -78,38 +138,160 @@ func (pu *PostgresUser) CreateUser(user *User) (*User, error) {
 }
 
 func (pu *PostgresUser) UpdateUserRole(user *User) (*User, error) {
+	tx, err := pu.db.Beginx()
+	if err != nil {
+		return nil, errors.Wrap(err, "can't create transaction for update role of user")
+	}
+
+	// Назначение роли администратора не может уменьшить число администраторов
+	if user.Role != types.ADMIN {
+		if err := checkLastAdmin(tx, user.ID); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+
+	updatedUser := &User{}
+
+	if err := tx.QueryRowx(updateUser, user.ID, user.Role).
+		Scan(
+			&updatedUser.ID,
+			&updatedUser.Login,
+			&updatedUser.Role,
+			&updatedUser.MaxCertification,
+		); err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrorUserNotFound
+		}
+		return nil, errors.Wrapf(checkRoleNotFoundError(err), "can't update user with id %d", user.ID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "can't commit update role of user with id %d", user.ID)
+	}
+
+	return updatedUser, nil
+}
+
+func (pu *PostgresUser) UpdateUserMaxCertification(user *User) (*User, error) {
+	updatedUser := &User{}
+
+	maxCertification := sql.Null[types.Certification]{Valid: false}
+	if user.MaxCertification != nil {
+		maxCertification = sql.Null[types.Certification]{Valid: true, V: *user.MaxCertification}
+	}
+
+	if err := pu.db.QueryRowx(updateUserMaxCertification, user.ID, maxCertification).
+		Scan(
+			&updatedUser.ID,
+			&updatedUser.Login,
+			&updatedUser.Role,
+			&updatedUser.MaxCertification,
+		); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrorUserNotFound
+		}
+		return nil, errors.Wrapf(err, "can't update max certification of user with id %d", user.ID)
+	}
+
+	return updatedUser, nil
+}
+
+func (pu *PostgresUser) UpdateUserStatus(id types.Id, from, to Status) (*User, error) {
 	updatedUser := &User{}
+	updated := false
 
-	if err := pu.db.QueryRowx(updateUser, user.ID, user.Role).
+	if err := pu.db.QueryRowx(updateUserStatus, id, from, to).
 		Scan(
 			&updatedUser.ID,
 			&updatedUser.Login,
 			&updatedUser.Role,
+			&updatedUser.Status,
+			&updatedUser.MaxCertification,
+			&updated,
 		); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrorUserNotFound
 		}
-		return nil, errors.Wrapf(err, "can't update user with id %d", user.ID)
+		return nil, errors.Wrapf(err, "can't update status of user with id %d", id)
+	}
+
+	if !updated {
+		return nil, errors.Wrapf(ErrorStatusMismatch, "user %d is %s, not %s", id, updatedUser.Status, from)
 	}
 
 	return updatedUser, nil
 }
 
+func (pu *PostgresUser) UpdateUserEmail(id types.Id, email string) error {
+	res, err := pu.db.Exec(updateUserEmail, id, email)
+	if err != nil {
+		return errors.Wrapf(checkEmailExistsError(err), "can't execute update email query for user %d", id)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrapf(err, "can't get number affected rows of update email query for user %d", id)
+	}
+
+	if n != 1 {
+		return errors.Wrapf(ErrorUserNotFound, "with id %d", id)
+	}
+
+	return nil
+}
+
+func (pu *PostgresUser) UpdateUserPassword(user *User) error {
+	res, err := pu.db.Exec(updateUserPassword, user.ID, user.Password)
+	if err != nil {
+		return errors.Wrapf(err, "can't execute update password query for user %d", user.ID)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrapf(err, "can't get number affected rows of update password query for user %d", user.ID)
+	}
+
+	if n != 1 {
+		return errors.Wrapf(ErrorUserNotFound, "with id %d", user.ID)
+	}
+
+	return nil
+}
+
 func (pu *PostgresUser) DeleteUser(id types.Id) error {
-	res, err := pu.db.Exec(deleteUser, id)
+	tx, err := pu.db.Beginx()
 	if err != nil {
+		return errors.Wrap(err, "can't create transaction for delete user")
+	}
+
+	if err := checkLastAdmin(tx, id); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	res, err := tx.Exec(deleteUser, id)
+	if err != nil {
+		_ = tx.Rollback()
 		return errors.Wrapf(err, "can't execute deleting query for user %d", id)
 	}
 
 	n, err := res.RowsAffected()
 	if err != nil {
+		_ = tx.Rollback()
 		return errors.Wrapf(err, "can't get number affected rows of deleting query for user %d", id)
 	}
 
 	if n != 1 {
+		_ = tx.Rollback()
 		return errors.Wrapf(ErrorUserNotFound, "with id %d", id)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return errors.Wrapf(err, "can't commit deleting of user %d", id)
+	}
+
 	return nil
 }
 
@@ -120,6 +302,7 @@ func (pu *PostgresUser) GetPasswordByLogin(login string) (*LoginUser, error) {
 		Scan(
 			&lu.ID,
 			&lu.Password,
+			&lu.Status,
 		); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrorUserNotFound
@@ -130,14 +313,36 @@ func (pu *PostgresUser) GetPasswordByLogin(login string) (*LoginUser, error) {
 	return lu, nil
 }
 
+func (pu *PostgresUser) GetPasswordById(id types.Id) (*LoginUser, error) {
+	lu := &LoginUser{}
+
+	if err := pu.db.QueryRowx(getPasswordById, id).
+		Scan(
+			&lu.ID,
+			&lu.Password,
+			&lu.Status,
+		); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrorUserNotFound
+		}
+		return nil, errors.Wrapf(err, "can't found user by id %d", id)
+	}
+
+	return lu, nil
+}
+
 func (pu *PostgresUser) GetUserById(id types.Id) (*User, error) {
 	foundedUser := &User{}
+	var permissions pq.StringArray
 
 	if err := pu.db.QueryRowx(getUserById, id).
 		Scan(
 			&foundedUser.ID,
 			&foundedUser.Login,
+			&foundedUser.Email,
 			&foundedUser.Role,
+			&foundedUser.MaxCertification,
+			&permissions,
 		); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrorUserNotFound
@@ -145,14 +350,92 @@ func (pu *PostgresUser) GetUserById(id types.Id) (*User, error) {
 		return nil, errors.Wrapf(err, "can't found user by id %d", id)
 	}
 
+	foundedUser.Permissions = role.FromPermissionsArray(permissions)
+	return foundedUser, nil
+}
+
+func (pu *PostgresUser) GetUserByLogin(login string) (*User, error) {
+	return pu.getUserBy(getUserByLogin, login)
+}
+
+func (pu *PostgresUser) GetUserByEmail(email string) (*User, error) {
+	return pu.getUserBy(getUserByEmail, email)
+}
+
+// getUserBy
+// Ищет пользователя по уникальному полю
+func (pu *PostgresUser) getUserBy(query, value string) (*User, error) {
+	foundedUser := &User{}
+
+	if err := pu.db.QueryRowx(query, value).
+		Scan(
+			&foundedUser.ID,
+			&foundedUser.Login,
+			&foundedUser.Email,
+			&foundedUser.Role,
+			&foundedUser.Status,
+		); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrorUserNotFound
+		}
+		return nil, errors.Wrapf(err, "can't found user by %s", value)
+	}
+
 	return foundedUser, nil
 }
 
 func (pu *PostgresUser) GetUsers() ([]User, error) {
-	rows, err := pu.db.Queryx(getUsers)
+	users, err := pu.queryUsers(getUsers)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't get users")
+	}
+
+	return users, nil
+}
+
+func (pu *PostgresUser) GetUsersByStatus(status Status) ([]User, error) {
+	users, err := pu.queryUsers(getUsersByStatus, status)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't get users with status %s", status)
+	}
+
+	return users, nil
+}
+
+func (pu *PostgresUser) CountActiveUsers(role types.Roles) (int, error) {
+	var count int
+
+	if err := pu.db.QueryRowx(countActiveUsers, role).Scan(&count); err != nil {
+		return 0, errors.Wrapf(err, "can't count active users with role %s", role)
+	}
+
+	return count, nil
+}
+
+// checkLastAdmin
+// Блокирует активных администраторов до конца транзакции и проверяет, что пользователь не последний из них.
+// Блокировка не даёт двум параллельным запросам лишить роли или удалить двух последних администраторов
+func checkLastAdmin(tx *sqlx.Tx, id types.Id) error {
+	admins := make([]types.Id, 0)
+	if err := tx.Select(&admins, lockActiveAdmins); err != nil {
+		return errors.Wrap(err, "can't lock active admins")
+	}
+
+	if len(admins) == 1 && admins[0] == id {
+		return errors.Wrapf(ErrorLastAdmin, "user %d", id)
+	}
+
+	return nil
+}
+
+// queryUsers
+// Выполняет запрос списка пользователей
+func (pu *PostgresUser) queryUsers(query string, args ...any) ([]User, error) {
+	rows, err := pu.db.Queryx(query, args...)
 	if err != nil {
 		return nil, errors.Wrap(err, "can't execute get users query")
 	}
+	defer rows.Close()
 
 	users := make([]User, 0)
 
@@ -163,6 +446,8 @@ func (pu *PostgresUser) GetUsers() ([]User, error) {
 			&user.ID,
 			&user.Login,
 			&user.Role,
+			&user.Status,
+			&user.MaxCertification,
 		)
 
 		if err != nil {
@@ -178,3 +463,26 @@ func (pu *PostgresUser) GetUsers() ([]User, error) {
 
 	return users, nil
 }
+
+const (
+	roleNotFoundCode    = "23503"
+	roleConstraintName  = "users_role_fkey"
+	uniqueViolationCode = "23505"
+	emailConstraintName = "users_email_key"
+)
+
+func checkRoleNotFoundError(err error) error {
+	var e *pq.Error
+	if errors.As(err, &e) && e.Code == roleNotFoundCode && e.Constraint == roleConstraintName {
+		return ErrorRoleNotFound
+	}
+	return err
+}
+
+func checkEmailExistsError(err error) error {
+	var e *pq.Error
+	if errors.As(err, &e) && e.Code == uniqueViolationCode && e.Constraint == emailConstraintName {
+		return ErrorEmailAlreadyExists
+	}
+	return err
+}
@@ -1,6 +1,8 @@
 package user
 
 import (
+	"database/sql"
+	"github.com/lib/pq"
 	"github.com/ozontech/allure-go/pkg/framework/provider"
 	"github.com/ozontech/allure-go/pkg/framework/suite"
 	"github.com/pkg/errors"
@@ -45,7 +47,7 @@ func (urs *UserRepositorySuite) TestCreateFunction(t provider.T) {
 	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		urs.mock.ExpectQuery(createQuery).
-			WithArgs(user.Login, user.Password, user.Role).
+			WithArgs(user.Login, user.Password, user.Role, StatusActive, user.Email).
 			WillReturnRows(sqlxmock.NewRows(userColumns).
 				AddRow(user.ID, user.Login, user.Role, 0),
 			)
@@ -63,7 +65,7 @@ func (urs *UserRepositorySuite) TestCreateFunction(t provider.T) {
 	t.WithNewStep("Error user already exists in create Query", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		urs.mock.ExpectQuery(createQuery).
-			WithArgs(user.Login, user.Password, user.Role).
+			WithArgs(user.Login, user.Password, user.Role, StatusActive, user.Email).
 			WillReturnRows(sqlxmock.NewRows(userColumns).
 				AddRow(user.ID, user.Login, user.Role, 1),
 			)
@@ -76,7 +78,7 @@ func (urs *UserRepositorySuite) TestCreateFunction(t provider.T) {
 	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		urs.mock.ExpectQuery(createQuery).
-			WithArgs(user.Login, user.Password, user.Role).
+			WithArgs(user.Login, user.Password, user.Role, StatusActive, user.Email).
 			WillReturnError(testError)
 
 		t.NewStep("Check result")
@@ -84,10 +86,21 @@ func (urs *UserRepositorySuite) TestCreateFunction(t provider.T) {
 		t.Require().ErrorIs(err, testError)
 	})
 
+	t.WithNewStep("Role not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectQuery(createQuery).
+			WithArgs(user.Login, user.Password, user.Role, StatusActive, user.Email).
+			WillReturnError(&pq.Error{Code: roleNotFoundCode, Constraint: roleConstraintName})
+
+		t.NewStep("Check result")
+		_, err := urs.userRepository.CreateUser(user)
+		t.Require().ErrorIs(err, ErrorRoleNotFound)
+	})
+
 	t.WithNewStep("Empty result of execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
 		urs.mock.ExpectQuery(createQuery).
-			WithArgs(user.Login, user.Password, user.Role).
+			WithArgs(user.Login, user.Password, user.Role, StatusActive, user.Email).
 			WillReturnRows(sqlxmock.NewRows(userColumns))
 
 		t.NewStep("Check result")
@@ -106,22 +119,69 @@ func (urs *UserRepositorySuite) TestDeleteFunction(t provider.T) {
 		Role:     types.USER,
 	}
 
+	expectLock := func(admins ...types.Id) {
+		rows := sqlxmock.NewRows([]string{"id"})
+		for _, id := range admins {
+			rows.AddRow(id)
+		}
+		urs.mock.ExpectBegin()
+		urs.mock.ExpectQuery(lockActiveAdmins).WillReturnRows(rows)
+	}
+
 	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
+		expectLock(2)
 		urs.mock.ExpectExec(deleteUser).
 			WithArgs(user.ID).
 			WillReturnResult(sqlxmock.NewResult(0, 1))
+		urs.mock.ExpectCommit()
 
 		t.NewStep("Check result")
 		err := urs.userRepository.DeleteUser(user.ID)
 		t.Require().NoError(err)
 	})
 
+	t.WithNewStep("Not last admin execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectLock(1, 2)
+		urs.mock.ExpectExec(deleteUser).
+			WithArgs(user.ID).
+			WillReturnResult(sqlxmock.NewResult(0, 1))
+		urs.mock.ExpectCommit()
+
+		t.NewStep("Check result")
+		err := urs.userRepository.DeleteUser(user.ID)
+		t.Require().NoError(err)
+	})
+
+	t.WithNewStep("Last admin execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectLock(1)
+		urs.mock.ExpectRollback()
+
+		t.NewStep("Check result")
+		err := urs.userRepository.DeleteUser(user.ID)
+		t.Require().ErrorIs(err, ErrorLastAdmin)
+	})
+
+	t.WithNewStep("Lock error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectBegin()
+		urs.mock.ExpectQuery(lockActiveAdmins).WillReturnError(testError)
+		urs.mock.ExpectRollback()
+
+		t.NewStep("Check result")
+		err := urs.userRepository.DeleteUser(user.ID)
+		t.Require().ErrorIs(err, testError)
+	})
+
 	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
+		expectLock()
 		urs.mock.ExpectExec(deleteUser).
 			WithArgs(user.ID).
 			WillReturnError(testError)
+		urs.mock.ExpectRollback()
 
 		t.NewStep("Check result")
 		err := urs.userRepository.DeleteUser(user.ID)
@@ -130,9 +190,11 @@ func (urs *UserRepositorySuite) TestDeleteFunction(t provider.T) {
 
 	t.WithNewStep("Row affected error of execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
+		expectLock()
 		urs.mock.ExpectExec(deleteUser).
 			WithArgs(user.ID).
 			WillReturnResult(sqlxmock.NewErrorResult(testError))
+		urs.mock.ExpectRollback()
 
 		t.NewStep("Check result")
 		err := urs.userRepository.DeleteUser(user.ID)
@@ -141,9 +203,11 @@ func (urs *UserRepositorySuite) TestDeleteFunction(t provider.T) {
 
 	t.WithNewStep("Error not found user in execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
+		expectLock()
 		urs.mock.ExpectExec(deleteUser).
 			WithArgs(user.ID).
 			WillReturnResult(sqlxmock.NewResult(2, 0))
+		urs.mock.ExpectRollback()
 
 		t.NewStep("Check result")
 		err := urs.userRepository.DeleteUser(user.ID)
@@ -151,24 +215,80 @@ func (urs *UserRepositorySuite) TestDeleteFunction(t provider.T) {
 	})
 }
 
+func (urs *UserRepositorySuite) TestUpdatePasswordFunction(t provider.T) {
+	t.Title("UpdateUserPassword function of User repository")
+	t.NewStep("Init test data")
+	user := &User{
+		ID:       1,
+		Password: "password",
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectExec(updateUserPassword).
+			WithArgs(user.ID, user.Password).
+			WillReturnResult(sqlxmock.NewResult(0, 1))
+
+		t.NewStep("Check result")
+		err := urs.userRepository.UpdateUserPassword(user)
+		t.Require().NoError(err)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectExec(updateUserPassword).
+			WithArgs(user.ID, user.Password).
+			WillReturnError(testError)
+
+		t.NewStep("Check result")
+		err := urs.userRepository.UpdateUserPassword(user)
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Row affected error of execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectExec(updateUserPassword).
+			WithArgs(user.ID, user.Password).
+			WillReturnResult(sqlxmock.NewErrorResult(testError))
+
+		t.NewStep("Check result")
+		err := urs.userRepository.UpdateUserPassword(user)
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Error not found user in execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectExec(updateUserPassword).
+			WithArgs(user.ID, user.Password).
+			WillReturnResult(sqlxmock.NewResult(0, 0))
+
+		t.NewStep("Check result")
+		err := urs.userRepository.UpdateUserPassword(user)
+		t.Require().ErrorIs(err, ErrorUserNotFound)
+	})
+}
+
 func (urs *UserRepositorySuite) TestGetFunction(t provider.T) {
 	t.Title("GetUsers function of User repository")
 	t.NewStep("Init test data")
+	maxCertification := types.AGE12
 	user := &User{
-		ID:    1,
-		Login: "actor",
-		Role:  types.USER,
+		ID:               1,
+		Login:            "actor",
+		Role:             types.USER,
+		Status:           StatusActive,
+		MaxCertification: &maxCertification,
 	}
 
 	userColumns := []string{
-		"id", "login", "role",
+		"id", "login", "role", "status", "max_certification",
 	}
 
 	usersRows := func() *sqlxmock.Rows {
 		return sqlxmock.NewRows(userColumns).
-			AddRow(user.ID, user.Login, user.Role).
-			AddRow(user.ID, user.Login, user.Role).
-			AddRow(user.ID, user.Login, user.Role)
+			AddRow(user.ID, user.Login, user.Role, user.Status, maxCertification).
+			AddRow(user.ID, user.Login, user.Role, user.Status, maxCertification).
+			AddRow(user.ID, user.Login, user.Role, user.Status, maxCertification)
 	}
 
 	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
@@ -211,7 +331,8 @@ func (urs *UserRepositorySuite) TestGetFunction(t provider.T) {
 
 	t.WithNewStep("Incorrect field in row of getUsers query", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		urs.mock.ExpectQuery(getUsers).WillReturnRows(usersRows().AddRow(1, 1, 1))
+		urs.mock.ExpectQuery(getUsers).WillReturnRows(usersRows().AddRow(1, 1, 1, 1, nil)).
+			RowsWillBeClosed()
 
 		t.NewStep("Check result")
 		_, err := urs.userRepository.GetUsers()
@@ -238,17 +359,28 @@ func (urs *UserRepositorySuite) TestUpdateRoleFunction(t provider.T) {
 	}
 
 	userColumns := []string{
-		"id", "login", "role",
+		"id", "login", "role", "max_certification",
+	}
+
+	expectLock := func(admins ...types.Id) {
+		rows := sqlxmock.NewRows([]string{"id"})
+		for _, id := range admins {
+			rows.AddRow(id)
+		}
+		urs.mock.ExpectBegin()
+		urs.mock.ExpectQuery(lockActiveAdmins).WillReturnRows(rows)
 	}
 
 	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
+		expectLock(2)
 		urs.mock.ExpectQuery(updateUser).
 			WithArgs(user.ID, user.Role).
 			WillReturnRows(
 				sqlxmock.NewRows(userColumns).
-					AddRow(user.ID, user.Login, user.Role),
+					AddRow(user.ID, user.Login, user.Role, nil),
 			)
+		urs.mock.ExpectCommit()
 
 		t.NewStep("Check result")
 		usr, err := urs.userRepository.UpdateUserRole(user)
@@ -256,22 +388,66 @@ func (urs *UserRepositorySuite) TestUpdateRoleFunction(t provider.T) {
 		t.Require().EqualValues(user, usr)
 	})
 
+	t.WithNewStep("Grant admin execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectBegin()
+		urs.mock.ExpectQuery(updateUser).
+			WithArgs(user.ID, types.ADMIN).
+			WillReturnRows(
+				sqlxmock.NewRows(userColumns).
+					AddRow(user.ID, user.Login, types.ADMIN, nil),
+			)
+		urs.mock.ExpectCommit()
+
+		t.NewStep("Check result")
+		usr, err := urs.userRepository.UpdateUserRole(&User{ID: user.ID, Role: types.ADMIN})
+		t.Require().NoError(err)
+		t.Require().Equal(types.ADMIN, usr.Role)
+	})
+
+	t.WithNewStep("Last admin execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectLock(1)
+		urs.mock.ExpectRollback()
+
+		t.NewStep("Check result")
+		_, err := urs.userRepository.UpdateUserRole(user)
+		t.Require().ErrorIs(err, ErrorLastAdmin)
+	})
+
 	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
+		expectLock()
 		urs.mock.ExpectQuery(updateUser).
 			WithArgs(user.ID, user.Role).
 			WillReturnError(testError)
+		urs.mock.ExpectRollback()
 
 		t.NewStep("Check result")
 		_, err := urs.userRepository.UpdateUserRole(user)
 		t.Require().ErrorIs(err, testError)
 	})
 
+	t.WithNewStep("Role not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectLock()
+		urs.mock.ExpectQuery(updateUser).
+			WithArgs(user.ID, user.Role).
+			WillReturnError(&pq.Error{Code: roleNotFoundCode, Constraint: roleConstraintName})
+		urs.mock.ExpectRollback()
+
+		t.NewStep("Check result")
+		_, err := urs.userRepository.UpdateUserRole(user)
+		t.Require().ErrorIs(err, ErrorRoleNotFound)
+	})
+
 	t.WithNewStep("User not found to update on execution", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
+		expectLock()
 		urs.mock.ExpectQuery(updateUser).
 			WithArgs(user.ID, user.Role).
 			WillReturnRows(sqlxmock.NewRows(userColumns))
+		urs.mock.ExpectRollback()
 
 		t.NewStep("Check result")
 		_, err := urs.userRepository.UpdateUserRole(user)
@@ -279,17 +455,87 @@ func (urs *UserRepositorySuite) TestUpdateRoleFunction(t provider.T) {
 	})
 }
 
+func (urs *UserRepositorySuite) TestUpdateMaxCertificationFunction(t provider.T) {
+	t.Title("UpdateUserMaxCertification function of User repository")
+	t.NewStep("Init test data")
+	maxCertification := types.MPAAPG13
+	user := &User{
+		ID:               1,
+		Login:            "actor",
+		Role:             types.USER,
+		MaxCertification: &maxCertification,
+	}
+
+	userColumns := []string{
+		"id", "login", "role", "max_certification",
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectQuery(updateUserMaxCertification).
+			WithArgs(user.ID, sql.Null[types.Certification]{Valid: true, V: maxCertification}).
+			WillReturnRows(
+				sqlxmock.NewRows(userColumns).
+					AddRow(user.ID, user.Login, user.Role, maxCertification),
+			)
+
+		t.NewStep("Check result")
+		usr, err := urs.userRepository.UpdateUserMaxCertification(user)
+		t.Require().NoError(err)
+		t.Require().EqualValues(user, usr)
+	})
+
+	t.WithNewStep("Correct execute with removing restriction", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectQuery(updateUserMaxCertification).
+			WithArgs(user.ID, sql.Null[types.Certification]{}).
+			WillReturnRows(
+				sqlxmock.NewRows(userColumns).
+					AddRow(user.ID, user.Login, user.Role, nil),
+			)
+
+		t.NewStep("Check result")
+		usr, err := urs.userRepository.UpdateUserMaxCertification(&User{ID: user.ID})
+		t.Require().NoError(err)
+		t.Require().Nil(usr.MaxCertification)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectQuery(updateUserMaxCertification).
+			WithArgs(user.ID, sql.Null[types.Certification]{Valid: true, V: maxCertification}).
+			WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := urs.userRepository.UpdateUserMaxCertification(user)
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("User not found to update on execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectQuery(updateUserMaxCertification).
+			WithArgs(user.ID, sql.Null[types.Certification]{Valid: true, V: maxCertification}).
+			WillReturnRows(sqlxmock.NewRows(userColumns))
+
+		t.NewStep("Check result")
+		_, err := urs.userRepository.UpdateUserMaxCertification(user)
+		t.Require().ErrorIs(err, ErrorUserNotFound)
+	})
+}
+
 func (urs *UserRepositorySuite) TestGetUserByIdFunction(t provider.T) {
 	t.Title("GetUserById function of User repository")
 	t.NewStep("Init test data")
 	user := &User{
-		ID:    1,
-		Login: "actor",
-		Role:  types.USER,
+		ID:          1,
+		Login:       "actor",
+		Email:       "actor@example.com",
+		Role:        "editor",
+		Permissions: []types.Permission{types.FilmWrite, types.ActorWrite},
 	}
 
 	userColumns := []string{
-		"id", "login", "role",
+		"id", "login", "email", "role", "max_certification", "permissions",
 	}
 
 	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
@@ -298,13 +544,15 @@ func (urs *UserRepositorySuite) TestGetUserByIdFunction(t provider.T) {
 			WithArgs(user.ID).
 			WillReturnRows(
 				sqlxmock.NewRows(userColumns).
-					AddRow(user.ID, user.Login, user.Role),
+					AddRow(user.ID, user.Login, user.Email, user.Role, nil, "{film:write,actor:write}"),
 			)
 
 		t.NewStep("Check result")
 		usr, err := urs.userRepository.GetUserById(user.ID)
 		t.Require().NoError(err)
 		t.Require().EqualValues(user, usr)
+		t.Require().True(usr.HasPermission(types.FilmWrite))
+		t.Require().False(usr.HasPermission(types.UserManage))
 	})
 
 	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
@@ -330,6 +578,57 @@ func (urs *UserRepositorySuite) TestGetUserByIdFunction(t provider.T) {
 	})
 }
 
+func (urs *UserRepositorySuite) TestGetPasswordByIdFunction(t provider.T) {
+	t.Title("GetPasswordById function of User repository")
+	t.NewStep("Init test data")
+	lu := &LoginUser{
+		ID:       1,
+		Password: "password",
+		Status:   StatusActive,
+	}
+
+	userColumns := []string{
+		"id", "password", "status",
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectQuery(getPasswordById).
+			WithArgs(lu.ID).
+			WillReturnRows(
+				sqlxmock.NewRows(userColumns).
+					AddRow(lu.ID, lu.Password, lu.Status),
+			)
+
+		t.NewStep("Check result")
+		usr, err := urs.userRepository.GetPasswordById(lu.ID)
+		t.Require().NoError(err)
+		t.Require().EqualValues(lu, usr)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectQuery(getPasswordById).
+			WithArgs(lu.ID).
+			WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := urs.userRepository.GetPasswordById(lu.ID)
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("User not found to get on execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectQuery(getPasswordById).
+			WithArgs(lu.ID).
+			WillReturnRows(sqlxmock.NewRows(userColumns))
+
+		t.NewStep("Check result")
+		_, err := urs.userRepository.GetPasswordById(lu.ID)
+		t.Require().ErrorIs(err, ErrorUserNotFound)
+	})
+}
+
 func (urs *UserRepositorySuite) TestGetPasswordByLoginFunction(t provider.T) {
 	t.Title("GetPasswordByLogin function of User repository")
 	t.NewStep("Init test data")
@@ -340,7 +639,7 @@ func (urs *UserRepositorySuite) TestGetPasswordByLoginFunction(t provider.T) {
 	}
 
 	userColumns := []string{
-		"id", "password",
+		"id", "password", "status",
 	}
 
 	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
@@ -349,7 +648,7 @@ func (urs *UserRepositorySuite) TestGetPasswordByLoginFunction(t provider.T) {
 			WithArgs(user.Login).
 			WillReturnRows(
 				sqlxmock.NewRows(userColumns).
-					AddRow(user.ID, user.Password),
+					AddRow(user.ID, user.Password, StatusPending),
 			)
 
 		t.NewStep("Check result")
@@ -358,6 +657,7 @@ func (urs *UserRepositorySuite) TestGetPasswordByLoginFunction(t provider.T) {
 		t.Require().EqualValues(&LoginUser{
 			ID:       user.ID,
 			Password: user.Password,
+			Status:   StatusPending,
 		}, usr)
 	})
 
@@ -384,6 +684,245 @@ func (urs *UserRepositorySuite) TestGetPasswordByLoginFunction(t provider.T) {
 	})
 }
 
+func (urs *UserRepositorySuite) TestUpdateEmailFunction(t provider.T) {
+	t.Title("UpdateUserEmail function of User repository")
+	t.NewStep("Init test data")
+	var id types.Id = 1
+	email := "actor@example.com"
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectExec(updateUserEmail).
+			WithArgs(id, email).
+			WillReturnResult(sqlxmock.NewResult(0, 1))
+
+		t.NewStep("Check result")
+		t.Require().NoError(urs.userRepository.UpdateUserEmail(id, email))
+	})
+
+	t.WithNewStep("Email already exists execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectExec(updateUserEmail).
+			WithArgs(id, email).
+			WillReturnError(&pq.Error{Code: uniqueViolationCode, Constraint: emailConstraintName})
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(urs.userRepository.UpdateUserEmail(id, email), ErrorEmailAlreadyExists)
+	})
+
+	t.WithNewStep("User not found execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectExec(updateUserEmail).
+			WithArgs(id, email).
+			WillReturnResult(sqlxmock.NewResult(0, 0))
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(urs.userRepository.UpdateUserEmail(id, email), ErrorUserNotFound)
+	})
+}
+
+func (urs *UserRepositorySuite) TestGetUserByLoginAndEmailFunctions(t provider.T) {
+	t.Title("GetUserByLogin and GetUserByEmail functions of User repository")
+	t.NewStep("Init test data")
+	user := &User{
+		ID:     1,
+		Login:  "actor",
+		Email:  "actor@example.com",
+		Role:   types.USER,
+		Status: StatusActive,
+	}
+
+	userColumns := []string{
+		"id", "login", "email", "role", "status",
+	}
+
+	t.WithNewStep("Correct execute by login", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectQuery(getUserByLogin).
+			WithArgs(user.Login).
+			WillReturnRows(
+				sqlxmock.NewRows(userColumns).
+					AddRow(user.ID, user.Login, user.Email, user.Role, user.Status),
+			)
+
+		t.NewStep("Check result")
+		usr, err := urs.userRepository.GetUserByLogin(user.Login)
+		t.Require().NoError(err)
+		t.Require().EqualValues(user, usr)
+	})
+
+	t.WithNewStep("Correct execute by email", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectQuery(getUserByEmail).
+			WithArgs(user.Email).
+			WillReturnRows(
+				sqlxmock.NewRows(userColumns).
+					AddRow(user.ID, user.Login, user.Email, user.Role, user.Status),
+			)
+
+		t.NewStep("Check result")
+		usr, err := urs.userRepository.GetUserByEmail(user.Email)
+		t.Require().NoError(err)
+		t.Require().EqualValues(user, usr)
+	})
+
+	t.WithNewStep("User not found execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectQuery(getUserByEmail).
+			WithArgs(user.Email).
+			WillReturnRows(sqlxmock.NewRows(userColumns))
+
+		t.NewStep("Check result")
+		_, err := urs.userRepository.GetUserByEmail(user.Email)
+		t.Require().ErrorIs(err, ErrorUserNotFound)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectQuery(getUserByLogin).
+			WithArgs(user.Login).
+			WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := urs.userRepository.GetUserByLogin(user.Login)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (urs *UserRepositorySuite) TestUpdateStatusFunction(t provider.T) {
+	t.Title("UpdateUserStatus function of User repository")
+	t.NewStep("Init test data")
+	user := &User{
+		ID:     1,
+		Login:  "actor",
+		Role:   types.USER,
+		Status: StatusActive,
+	}
+
+	userColumns := []string{
+		"id", "login", "role", "status", "max_certification", "updated",
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectQuery(updateUserStatus).
+			WithArgs(user.ID, StatusPending, StatusActive).
+			WillReturnRows(
+				sqlxmock.NewRows(userColumns).
+					AddRow(user.ID, user.Login, user.Role, user.Status, nil, true),
+			)
+
+		t.NewStep("Check result")
+		usr, err := urs.userRepository.UpdateUserStatus(user.ID, StatusPending, StatusActive)
+		t.Require().NoError(err)
+		t.Require().EqualValues(user, usr)
+	})
+
+	t.WithNewStep("User has another status execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectQuery(updateUserStatus).
+			WithArgs(user.ID, StatusPending, StatusActive).
+			WillReturnRows(
+				sqlxmock.NewRows(userColumns).
+					AddRow(user.ID, user.Login, user.Role, StatusRejected, nil, false),
+			)
+
+		t.NewStep("Check result")
+		_, err := urs.userRepository.UpdateUserStatus(user.ID, StatusPending, StatusActive)
+		t.Require().ErrorIs(err, ErrorStatusMismatch)
+	})
+
+	t.WithNewStep("User not found execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectQuery(updateUserStatus).
+			WithArgs(user.ID, StatusPending, StatusActive).
+			WillReturnRows(sqlxmock.NewRows(userColumns))
+
+		t.NewStep("Check result")
+		_, err := urs.userRepository.UpdateUserStatus(user.ID, StatusPending, StatusActive)
+		t.Require().ErrorIs(err, ErrorUserNotFound)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectQuery(updateUserStatus).
+			WithArgs(user.ID, StatusPending, StatusActive).
+			WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := urs.userRepository.UpdateUserStatus(user.ID, StatusPending, StatusActive)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (urs *UserRepositorySuite) TestGetByStatusFunction(t provider.T) {
+	t.Title("GetUsersByStatus function of User repository")
+	t.NewStep("Init test data")
+	user := User{
+		ID:     1,
+		Login:  "actor",
+		Role:   types.USER,
+		Status: StatusPending,
+	}
+
+	userColumns := []string{
+		"id", "login", "role", "status", "max_certification",
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectQuery(getUsersByStatus).
+			WithArgs(StatusPending).
+			WillReturnRows(
+				sqlxmock.NewRows(userColumns).
+					AddRow(user.ID, user.Login, user.Role, user.Status, nil),
+			)
+
+		t.NewStep("Check result")
+		users, err := urs.userRepository.GetUsersByStatus(StatusPending)
+		t.Require().NoError(err)
+		t.Require().EqualValues([]User{user}, users)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectQuery(getUsersByStatus).
+			WithArgs(StatusPending).
+			WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := urs.userRepository.GetUsersByStatus(StatusPending)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (urs *UserRepositorySuite) TestCountActiveUsersFunction(t provider.T) {
+	t.Title("CountActiveUsers function of User repository")
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectQuery(countActiveUsers).
+			WithArgs(types.ADMIN).
+			WillReturnRows(sqlxmock.NewRows([]string{"count"}).AddRow(2))
+
+		t.NewStep("Check result")
+		count, err := urs.userRepository.CountActiveUsers(types.ADMIN)
+		t.Require().NoError(err)
+		t.Require().Equal(2, count)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		urs.mock.ExpectQuery(countActiveUsers).
+			WithArgs(types.ADMIN).
+			WillReturnError(testError)
+
+		t.NewStep("Check result")
+		_, err := urs.userRepository.CountActiveUsers(types.ADMIN)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
 func TestRunUserRepositorySuite(t *testing.T) {
 	suite.RunSuite(t, new(UserRepositorySuite))
 }
@@ -0,0 +1,140 @@
+package accounts
+
+import (
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/user"
+	mru "vk_film/internal/repository/user/mocks"
+)
+
+var testError = errors.New("test error")
+
+type AccountsUsecaseSuite struct {
+	suite.Suite
+	usecase  *AccountsUsecase
+	mockUser *mru.UserRepository
+	gmc      *gomock.Controller
+}
+
+func (aus *AccountsUsecaseSuite) BeforeEach(t provider.T) {
+	aus.gmc = gomock.NewController(t)
+	aus.mockUser = mru.NewUserRepository(aus.gmc)
+	aus.usecase = NewAccountsUsecase(aus.mockUser)
+}
+
+func (aus *AccountsUsecaseSuite) AfterEach(t provider.T) {
+	aus.gmc.Finish()
+}
+
+func (aus *AccountsUsecaseSuite) TestUpdateRoleFunction(t provider.T) {
+	t.Title("UpdateRole function of Accounts usecase")
+	t.NewStep("Init test data")
+	actor := &user.User{ID: 1, Role: types.ADMIN}
+	other := &user.User{ID: 2, Role: types.USER}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		aus.mockUser.EXPECT().UpdateUserRole(other).Return(other, nil)
+
+		t.NewStep("Check result")
+		usr, err := aus.usecase.UpdateRole(actor, other.ID, other.Role, false)
+		t.Require().NoError(err)
+		t.Require().Equal(other, usr)
+	})
+
+	t.WithNewStep("Not confirmed self demotion execute", func(t provider.StepCtx) {
+		t.NewStep("Check result")
+		_, err := aus.usecase.UpdateRole(actor, actor.ID, types.USER, false)
+		t.Require().ErrorIs(err, ErrorConfirmationRequired)
+	})
+
+	t.WithNewStep("Confirmed self demotion execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		demoted := &user.User{ID: actor.ID, Role: types.USER}
+		aus.mockUser.EXPECT().UpdateUserRole(demoted).Return(demoted, nil)
+
+		t.NewStep("Check result")
+		usr, err := aus.usecase.UpdateRole(actor, actor.ID, types.USER, true)
+		t.Require().NoError(err)
+		t.Require().Equal(demoted, usr)
+	})
+
+	t.WithNewStep("Same own role execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		aus.mockUser.EXPECT().UpdateUserRole(&user.User{ID: actor.ID, Role: types.ADMIN}).Return(actor, nil)
+
+		t.NewStep("Check result")
+		_, err := aus.usecase.UpdateRole(actor, actor.ID, types.ADMIN, false)
+		t.Require().NoError(err)
+	})
+
+	t.WithNewStep("Last admin execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		aus.mockUser.EXPECT().UpdateUserRole(gomock.Any()).Return(nil, user.ErrorLastAdmin)
+
+		t.NewStep("Check result")
+		_, err := aus.usecase.UpdateRole(actor, actor.ID, types.USER, true)
+		t.Require().ErrorIs(err, ErrorLastAdmin)
+	})
+
+	t.WithNewStep("User repository error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		aus.mockUser.EXPECT().UpdateUserRole(other).Return(nil, testError)
+
+		t.NewStep("Check result")
+		_, err := aus.usecase.UpdateRole(actor, other.ID, other.Role, false)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (aus *AccountsUsecaseSuite) TestDeleteFunction(t provider.T) {
+	t.Title("Delete function of Accounts usecase")
+	t.NewStep("Init test data")
+	actor := &user.User{ID: 1, Role: types.ADMIN}
+	var otherId types.Id = 2
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		aus.mockUser.EXPECT().DeleteUser(otherId).Return(nil)
+
+		t.NewStep("Check result")
+		t.Require().NoError(aus.usecase.Delete(actor, otherId, false))
+	})
+
+	t.WithNewStep("Not confirmed self deletion execute", func(t provider.StepCtx) {
+		t.NewStep("Check result")
+		t.Require().ErrorIs(aus.usecase.Delete(actor, actor.ID, false), ErrorConfirmationRequired)
+	})
+
+	t.WithNewStep("Confirmed self deletion execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		aus.mockUser.EXPECT().DeleteUser(actor.ID).Return(nil)
+
+		t.NewStep("Check result")
+		t.Require().NoError(aus.usecase.Delete(actor, actor.ID, true))
+	})
+
+	t.WithNewStep("Last admin execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		aus.mockUser.EXPECT().DeleteUser(actor.ID).Return(user.ErrorLastAdmin)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(aus.usecase.Delete(actor, actor.ID, true), ErrorLastAdmin)
+	})
+
+	t.WithNewStep("User not found execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		aus.mockUser.EXPECT().DeleteUser(otherId).Return(user.ErrorUserNotFound)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(aus.usecase.Delete(actor, otherId, false), user.ErrorUserNotFound)
+	})
+}
+
+func TestRunAccountsUsecaseSuite(t *testing.T) {
+	suite.RunSuite(t, new(AccountsUsecaseSuite))
+}
@@ -0,0 +1,35 @@
+package accounts
+
+import (
+	"github.com/pkg/errors"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/user"
+)
+
+var (
+	ErrorLastAdmin            = errors.New("at least one active admin must remain")
+	ErrorConfirmationRequired = errors.New("change of own account must be confirmed")
+)
+
+//go:generate mockgen -destination=mocks/usecase.go -package=mac -mock_names=Usecase=AccountsUsecase . Usecase
+
+type Usecase interface {
+	// UpdateRole
+	// Changes the role of the user on behalf of the actor. The actor must confirm the change
+	// of his own role, the last active admin can't lose the admin role.
+	// Returns Error:
+	//   - ErrorConfirmationRequired
+	//   - ErrorLastAdmin
+	//   - user.ErrorUserNotFound
+	//   - user.ErrorRoleNotFound
+	UpdateRole(actor *user.User, userId types.Id, role types.Roles, confirmed bool) (*user.User, error)
+
+	// Delete
+	// Deletes the user on behalf of the actor. The actor must confirm the deletion
+	// of his own account, the last active admin can't be deleted.
+	// Returns Error:
+	//   - ErrorConfirmationRequired
+	//   - ErrorLastAdmin
+	//   - user.ErrorUserNotFound
+	Delete(actor *user.User, userId types.Id, confirmed bool) error
+}
@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: vk_film/internal/usecase/accounts (interfaces: Usecase)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/usecase.go -package=mac -mock_names=Usecase=AccountsUsecase . Usecase
+//
+
+// Package mac is a generated GoMock package.
+package mac
+
+import (
+	reflect "reflect"
+	types "vk_film/internal/pkg/types"
+	user "vk_film/internal/repository/user"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// AccountsUsecase is a mock of Usecase interface.
+type AccountsUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *AccountsUsecaseMockRecorder
+}
+
+// AccountsUsecaseMockRecorder is the mock recorder for AccountsUsecase.
+type AccountsUsecaseMockRecorder struct {
+	mock *AccountsUsecase
+}
+
+// NewAccountsUsecase creates a new mock instance.
+func NewAccountsUsecase(ctrl *gomock.Controller) *AccountsUsecase {
+	mock := &AccountsUsecase{ctrl: ctrl}
+	mock.recorder = &AccountsUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *AccountsUsecase) EXPECT() *AccountsUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *AccountsUsecase) Delete(arg0 *user.User, arg1 types.Id, arg2 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *AccountsUsecaseMockRecorder) Delete(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*AccountsUsecase)(nil).Delete), arg0, arg1, arg2)
+}
+
+// UpdateRole mocks base method.
+func (m *AccountsUsecase) UpdateRole(arg0 *user.User, arg1 types.Id, arg2 types.Roles, arg3 bool) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *AccountsUsecaseMockRecorder) UpdateRole(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*AccountsUsecase)(nil).UpdateRole), arg0, arg1, arg2, arg3)
+}
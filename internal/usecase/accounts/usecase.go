@@ -0,0 +1,55 @@
+package accounts
+
+import (
+	"github.com/pkg/errors"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/user"
+)
+
+type AccountsUsecase struct {
+	users user.Repository
+}
+
+func NewAccountsUsecase(users user.Repository) *AccountsUsecase {
+	return &AccountsUsecase{
+		users: users,
+	}
+}
+
+var _ = Usecase(&AccountsUsecase{})
+
+func (au *AccountsUsecase) UpdateRole(actor *user.User, userId types.Id, role types.Roles,
+	confirmed bool) (*user.User, error) {
+	// Назначение себе той же роли ничего не меняет и не требует подтверждения
+	if actor.ID == userId && actor.Role != role && !confirmed {
+		return nil, errors.Wrapf(ErrorConfirmationRequired, "user %d changes own role to %s", userId, role)
+	}
+
+	updated, err := au.users.UpdateUserRole(&user.User{ID: userId, Role: role})
+	if err != nil {
+		return nil, errors.Wrapf(checkLastAdminError(err), "try update role of user %d", userId)
+	}
+
+	return updated, nil
+}
+
+func (au *AccountsUsecase) Delete(actor *user.User, userId types.Id, confirmed bool) error {
+	if actor.ID == userId && !confirmed {
+		return errors.Wrapf(ErrorConfirmationRequired, "user %d deletes own account", userId)
+	}
+
+	if err := au.users.DeleteUser(userId); err != nil {
+		return errors.Wrapf(checkLastAdminError(err), "try delete user %d", userId)
+	}
+
+	return nil
+}
+
+// checkLastAdminError
+// Заменяет ошибку хранилища о последнем администраторе на ошибку сценария
+func checkLastAdminError(err error) error {
+	if errors.Is(err, user.ErrorLastAdmin) {
+		return ErrorLastAdmin
+	}
+	return err
+}
@@ -6,22 +6,61 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/mock/gomock"
 	"golang.org/x/crypto/bcrypt"
+	"strings"
 	"testing"
 	"time"
 	"vk_film/internal/pkg/types"
+	mra "vk_film/internal/repository/attempts/mocks"
 	"vk_film/internal/repository/session"
 	mrs "vk_film/internal/repository/session/mocks"
+	"vk_film/internal/repository/token"
+	mrt "vk_film/internal/repository/token/mocks"
 	"vk_film/internal/repository/user"
 	mru "vk_film/internal/repository/user/mocks"
 )
 
 var testError = errors.New("test error")
 
+var testNow = time.Unix(1700000000, 0)
+
+// countingHasher
+// Считает проверки паролей, чтобы убедиться, что хеш проверяется и для неизвестных логинов
+type countingHasher struct {
+	PasswordHasher
+	verified int
+}
+
+func (ch *countingHasher) Verify(hash, password string) (bool, error) {
+	ch.verified++
+	return ch.PasswordHasher.Verify(hash, password)
+}
+
+// testSessionPolicy
+// Политика без отдельного времени жизни сессий администраторов, вход с ней не запрашивает роль пользователя
+var testSessionPolicy = SessionPolicy{
+	Default:  DefaultSessionPolicy.Default,
+	Remember: DefaultSessionPolicy.Remember,
+}
+
+// testSession
+// Ожидаемая сессия, созданная в момент testNow
+func testSession(userId types.Id, client ClientInfo, lifetime SessionLifetime) *session.Session {
+	return &session.Session{
+		UserID:      userId,
+		IP:          client.IP,
+		UserAgent:   client.UserAgent,
+		IdleTimeout: lifetime.IdleTimeout,
+		ExpiresAt:   testNow.Add(lifetime.MaxLifetime),
+	}
+}
+
 type SessionManagerSuite struct {
 	suite.Suite
 	sessionManager *SessionManager
 	mockUser       *mru.UserRepository
 	mockSession    *mrs.SessionRepository
+	mockAttempts   *mra.AttemptsRepository
+	mockToken      *mrt.TokenRepository
 	gmc            *gomock.Controller
 }
 
@@ -29,7 +68,11 @@ func (sms *SessionManagerSuite) BeforeEach(t provider.T) {
 	sms.gmc = gomock.NewController(t)
 	sms.mockUser = mru.NewUserRepository(sms.gmc)
 	sms.mockSession = mrs.NewSessionRepository(sms.gmc)
-	sms.sessionManager = NewSessionManager(sms.mockUser, sms.mockSession)
+	sms.mockAttempts = mra.NewAttemptsRepository(sms.gmc)
+	sms.mockToken = mrt.NewTokenRepository(sms.gmc)
+	sms.sessionManager = NewSessionManager(sms.mockUser, sms.mockSession, sms.mockAttempts, sms.mockToken, nil,
+		DefaultLoginProtection, DefaultTwoFactorPolicy, testSessionPolicy, nil, NewTestPasswordHasher(), DefaultPasswordPolicy, nil, nil)
+	sms.sessionManager.now = func() time.Time { return testNow }
 }
 
 func (sms *SessionManagerSuite) AfterEach(t provider.T) {
@@ -41,70 +84,460 @@ func (sms *SessionManagerSuite) TestLoginFunction(t provider.T) {
 	t.NewStep("Init test data")
 	login := "login"
 	password := "password"
-	encryptPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	encryptPassword, err := NewTestPasswordHasher().Hash(password)
 	t.Require().NoError(err)
 	sessionId := "id"
 	userId := types.Id(1)
+	client := ClientInfo{IP: "127.0.0.1", UserAgent: "curl/8.0"}
+	info := testSession(userId, client, DefaultSessionPolicy.Default)
+	loginKey, ipKey := "login:"+login, "ip:"+client.IP
+	window := DefaultLoginProtection.AttemptsWindow
+
+	expectNoLock := func() {
+		sms.mockAttempts.EXPECT().GetLockTime(loginKey).Return(time.Duration(0), nil)
+		sms.mockAttempts.EXPECT().GetLockTime(ipKey).Return(time.Duration(0), nil)
+	}
 
 	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
+		expectNoLock()
 		sms.mockUser.EXPECT().GetPasswordByLogin(login).
-			Return(&user.LoginUser{ID: userId, Password: string(encryptPassword)}, nil)
-		sms.mockSession.EXPECT().Set(gomock.Any(), userId, ExpiredSessionTime).
+			Return(&user.LoginUser{ID: userId, Password: encryptPassword, Status: user.StatusActive}, nil)
+		sms.mockAttempts.EXPECT().Reset(loginKey).Return(nil)
+		sms.mockSession.EXPECT().Set(gomock.Any(), info).
 			Do(
-				func(sesId string, _ types.Id, _ time.Duration) {
+				func(sesId string, _ *session.Session) {
 					sessionId = sesId
 				},
 			).Return(nil)
 
 		t.NewStep("Check result")
-		sesId, err := sms.sessionManager.Login(login, password)
+		credentials, err := sms.sessionManager.Login(login, password, client)
 		t.Require().NoError(err)
-		t.Require().Equal(sessionId, sesId)
+		t.Require().Equal(sessionId, credentials.SessionId)
+		t.Require().Empty(credentials.RefreshToken)
+		t.Require().Equal(DefaultSessionPolicy.Default.MaxLifetime, credentials.ExpiresIn)
+		t.Require().False(credentials.Persistent)
 	})
 
 	t.WithNewStep("Incorrect password execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
+		expectNoLock()
+		sms.mockUser.EXPECT().GetPasswordByLogin(login).
+			Return(&user.LoginUser{ID: userId, Password: encryptPassword, Status: user.StatusActive}, nil)
+		sms.mockAttempts.EXPECT().Add(loginKey, window).Return(uint64(1), nil)
+		sms.mockAttempts.EXPECT().Add(ipKey, window).Return(uint64(1), nil)
+
+		t.NewStep("Check result")
+		_, err := sms.sessionManager.Login(login, login, client)
+		t.Require().ErrorIs(err, ErrorIncorrectPassword)
+	})
+
+	t.WithNewStep("Not approved user execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectNoLock()
 		sms.mockUser.EXPECT().GetPasswordByLogin(login).
-			Return(&user.LoginUser{ID: userId, Password: string(encryptPassword)}, nil)
+			Return(&user.LoginUser{ID: userId, Password: encryptPassword, Status: user.StatusPending}, nil)
+		sms.mockAttempts.EXPECT().Reset(loginKey).Return(nil)
+
+		t.NewStep("Check result")
+		_, err := sms.sessionManager.Login(login, password, client)
+		t.Require().ErrorIs(err, ErrorAccountNotActive)
+	})
+
+	t.WithNewStep("User without password execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectNoLock()
+		sms.mockUser.EXPECT().GetPasswordByLogin(login).Return(&user.LoginUser{ID: userId}, nil)
+		sms.mockAttempts.EXPECT().Add(loginKey, window).Return(uint64(1), nil)
+		sms.mockAttempts.EXPECT().Add(ipKey, window).Return(uint64(1), nil)
 
 		t.NewStep("Check result")
-		_, err := sms.sessionManager.Login(login, login)
+		_, err := sms.sessionManager.Login(login, "", client)
 		t.Require().ErrorIs(err, ErrorIncorrectPassword)
 	})
 
+	t.WithNewStep("Unknown login execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectNoLock()
+		sms.mockUser.EXPECT().GetPasswordByLogin(login).Return(nil, user.ErrorUserNotFound)
+		sms.mockAttempts.EXPECT().Add(loginKey, window).Return(uint64(2), nil)
+		sms.mockAttempts.EXPECT().Add(ipKey, window).Return(uint64(2), nil)
+
+		t.NewStep("Check result")
+		_, err := sms.sessionManager.Login(login, password, client)
+		t.Require().ErrorIs(err, user.ErrorUserNotFound)
+	})
+
+	t.WithNewStep("Unknown login verifies dummy hash execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		passwords := &countingHasher{PasswordHasher: NewTestPasswordHasher()}
+		sessionManager := NewSessionManager(sms.mockUser, sms.mockSession, sms.mockAttempts, sms.mockToken, nil,
+			DefaultLoginProtection, DefaultTwoFactorPolicy, testSessionPolicy, nil, passwords, DefaultPasswordPolicy, nil, nil)
+		sms.mockUser.EXPECT().GetPasswordByLogin(login).Return(nil, user.ErrorUserNotFound).Times(2)
+		sms.mockAttempts.EXPECT().Add(gomock.Any(), window).Return(uint64(2), nil).Times(4)
+
+		t.NewStep("Check result")
+		for i := 1; i <= 2; i++ {
+			expectNoLock()
+			_, err := sessionManager.Login(login, password, client)
+			t.Require().ErrorIs(err, user.ErrorUserNotFound)
+			t.Require().Equal(i, passwords.verified)
+		}
+	})
+
+	t.WithNewStep("Lock login on max attempts execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectNoLock()
+		sms.mockUser.EXPECT().GetPasswordByLogin(login).
+			Return(&user.LoginUser{ID: userId, Password: encryptPassword, Status: user.StatusActive}, nil)
+		sms.mockAttempts.EXPECT().Add(loginKey, window).Return(DefaultLoginProtection.MaxLoginAttempts+2, nil)
+		sms.mockAttempts.EXPECT().Lock(loginKey, 4*DefaultLoginProtection.BaseLockTime).Return(nil)
+		sms.mockAttempts.EXPECT().Add(ipKey, window).Return(uint64(7), nil)
+
+		t.NewStep("Check result")
+		_, err := sms.sessionManager.Login(login, login, client)
+		t.Require().ErrorIs(err, ErrorTooManyAttempts)
+		var lockout *LockoutError
+		t.Require().ErrorAs(err, &lockout)
+		t.Require().Equal(4*DefaultLoginProtection.BaseLockTime, lockout.RetryAfter)
+	})
+
+	t.WithNewStep("Lock time is limited execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectNoLock()
+		sms.mockUser.EXPECT().GetPasswordByLogin(login).
+			Return(&user.LoginUser{ID: userId, Password: encryptPassword, Status: user.StatusActive}, nil)
+		sms.mockAttempts.EXPECT().Add(loginKey, window).Return(uint64(1), nil)
+		sms.mockAttempts.EXPECT().Add(ipKey, window).Return(uint64(1000), nil)
+		sms.mockAttempts.EXPECT().Lock(ipKey, DefaultLoginProtection.MaxLockTime).Return(nil)
+
+		t.NewStep("Check result")
+		_, err := sms.sessionManager.Login(login, login, client)
+		var lockout *LockoutError
+		t.Require().ErrorAs(err, &lockout)
+		t.Require().Equal(DefaultLoginProtection.MaxLockTime, lockout.RetryAfter)
+	})
+
+	t.WithNewStep("Locked address execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockAttempts.EXPECT().GetLockTime(loginKey).Return(time.Second, nil)
+		sms.mockAttempts.EXPECT().GetLockTime(ipKey).Return(time.Minute, nil)
+
+		t.NewStep("Check result")
+		_, err := sms.sessionManager.Login(login, password, client)
+		var lockout *LockoutError
+		t.Require().ErrorAs(err, &lockout)
+		t.Require().Equal(time.Minute, lockout.RetryAfter)
+	})
+
+	t.WithNewStep("Without client address execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockAttempts.EXPECT().GetLockTime(loginKey).Return(time.Duration(0), nil)
+		sms.mockUser.EXPECT().GetPasswordByLogin(login).Return(nil, user.ErrorUserNotFound)
+		sms.mockAttempts.EXPECT().Add(loginKey, window).Return(uint64(1), nil)
+
+		t.NewStep("Check result")
+		_, err := sms.sessionManager.Login(login, password, ClientInfo{})
+		t.Require().ErrorIs(err, user.ErrorUserNotFound)
+	})
+
+	t.WithNewStep("Attempts repository error on get lock time", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockAttempts.EXPECT().GetLockTime(loginKey).Return(time.Duration(0), testError)
+
+		t.NewStep("Check result")
+		_, err := sms.sessionManager.Login(login, password, client)
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Attempts repository error on add attempt", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectNoLock()
+		sms.mockUser.EXPECT().GetPasswordByLogin(login).Return(nil, user.ErrorUserNotFound)
+		sms.mockAttempts.EXPECT().Add(loginKey, window).Return(uint64(0), testError)
+
+		t.NewStep("Check result")
+		_, err := sms.sessionManager.Login(login, password, client)
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Attempts repository error on lock", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectNoLock()
+		sms.mockUser.EXPECT().GetPasswordByLogin(login).Return(nil, user.ErrorUserNotFound)
+		sms.mockAttempts.EXPECT().Add(loginKey, window).Return(DefaultLoginProtection.MaxLoginAttempts, nil)
+		sms.mockAttempts.EXPECT().Lock(loginKey, DefaultLoginProtection.BaseLockTime).Return(testError)
+
+		t.NewStep("Check result")
+		_, err := sms.sessionManager.Login(login, password, client)
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Attempts repository error on reset", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectNoLock()
+		sms.mockUser.EXPECT().GetPasswordByLogin(login).
+			Return(&user.LoginUser{ID: userId, Password: encryptPassword, Status: user.StatusActive}, nil)
+		sms.mockAttempts.EXPECT().Reset(loginKey).Return(testError)
+
+		t.NewStep("Check result")
+		_, err := sms.sessionManager.Login(login, password, client)
+		t.Require().ErrorIs(err, testError)
+	})
+
 	t.WithNewStep("Bcrypt error execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
+		expectNoLock()
 		sms.mockUser.EXPECT().GetPasswordByLogin(login).
 			Return(&user.LoginUser{ID: userId, Password: password}, nil)
 
 		t.NewStep("Check result")
-		_, err := sms.sessionManager.Login(login, password)
+		_, err := sms.sessionManager.Login(login, password, client)
 		t.Require().Error(err)
 	})
 
 	t.WithNewStep("User repository error in execution", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
+		expectNoLock()
 		sms.mockUser.EXPECT().GetPasswordByLogin(login).
 			Return(&user.LoginUser{ID: userId, Password: password}, testError)
 
 		t.NewStep("Check result")
-		_, err := sms.sessionManager.Login(login, password)
+		_, err := sms.sessionManager.Login(login, password, client)
 		t.Require().ErrorIs(err, testError)
 	})
 
 	t.WithNewStep("Session repository error in execution", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
+		expectNoLock()
+		sms.mockUser.EXPECT().GetPasswordByLogin(login).
+			Return(&user.LoginUser{ID: userId, Password: encryptPassword, Status: user.StatusActive}, nil)
+		sms.mockAttempts.EXPECT().Reset(loginKey).Return(nil)
+		sms.mockSession.EXPECT().Set(gomock.Any(), info).Return(testError)
+
+		t.NewStep("Check result")
+		_, err := sms.sessionManager.Login(login, password, client)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (sms *SessionManagerSuite) TestLoginRehashFunction(t provider.T) {
+	t.Title("Rehash of outdated password on login of sessions manager")
+	t.NewStep("Init test data")
+	login := "login"
+	password := "password"
+	legacyPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	t.Require().NoError(err)
+	userId := types.Id(1)
+	client := ClientInfo{IP: "127.0.0.1", UserAgent: "curl/8.0"}
+	info := testSession(userId, client, DefaultSessionPolicy.Default)
+
+	expectLogin := func() {
+		sms.mockAttempts.EXPECT().GetLockTime(gomock.Any()).Return(time.Duration(0), nil).Times(2)
+		sms.mockUser.EXPECT().GetPasswordByLogin(login).
+			Return(&user.LoginUser{ID: userId, Password: string(legacyPassword), Status: user.StatusActive}, nil)
+		sms.mockAttempts.EXPECT().Reset("login:" + login).Return(nil)
+	}
+
+	t.WithNewStep("Bcrypt password execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectLogin()
+		sms.mockUser.EXPECT().UpdateUserPassword(gomock.Any()).Do(func(usr *user.User) {
+			t.Require().Equal(userId, usr.ID)
+			t.Require().True(strings.HasPrefix(usr.Password, argon2idPrefix))
+			outdated, err := NewTestPasswordHasher().Verify(usr.Password, password)
+			t.Require().NoError(err)
+			t.Require().False(outdated)
+		}).Return(nil)
+		sms.mockSession.EXPECT().Set(gomock.Any(), info).Return(nil)
+
+		t.NewStep("Check result")
+		_, err := sms.sessionManager.Login(login, password, client)
+		t.Require().NoError(err)
+	})
+
+	t.WithNewStep("Rehash error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectLogin()
+		sms.mockUser.EXPECT().UpdateUserPassword(gomock.Any()).Return(testError)
+		sms.mockSession.EXPECT().Set(gomock.Any(), info).Return(nil)
+
+		t.NewStep("Check result")
+		_, err := sms.sessionManager.Login(login, password, client)
+		t.Require().NoError(err)
+	})
+
+	t.WithNewStep("Outdated argon2id parameters execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		weak := NewArgon2Hasher(Argon2Params{Memory: 32, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+		hash, err := weak.Hash(password)
+		t.Require().NoError(err)
+		sms.mockAttempts.EXPECT().GetLockTime(gomock.Any()).Return(time.Duration(0), nil).Times(2)
+		sms.mockUser.EXPECT().GetPasswordByLogin(login).
+			Return(&user.LoginUser{ID: userId, Password: hash, Status: user.StatusActive}, nil)
+		sms.mockAttempts.EXPECT().Reset("login:" + login).Return(nil)
+		sms.mockUser.EXPECT().UpdateUserPassword(gomock.Any()).Return(nil)
+		sms.mockSession.EXPECT().Set(gomock.Any(), info).Return(nil)
+
+		t.NewStep("Check result")
+		_, err = sms.sessionManager.Login(login, password, client)
+		t.Require().NoError(err)
+	})
+
+	t.WithNewStep("Not approved user execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockAttempts.EXPECT().GetLockTime(gomock.Any()).Return(time.Duration(0), nil).Times(2)
 		sms.mockUser.EXPECT().GetPasswordByLogin(login).
-			Return(&user.LoginUser{ID: userId, Password: string(encryptPassword)}, nil)
-		sms.mockSession.EXPECT().Set(gomock.Any(), userId, ExpiredSessionTime).Return(testError)
+			Return(&user.LoginUser{ID: userId, Password: string(legacyPassword), Status: user.StatusPending}, nil)
+		sms.mockAttempts.EXPECT().Reset("login:" + login).Return(nil)
+
+		t.NewStep("Check result")
+		_, err := sms.sessionManager.Login(login, password, client)
+		t.Require().ErrorIs(err, ErrorAccountNotActive)
+	})
+}
+
+func (sms *SessionManagerSuite) TestLoginUserFunction(t provider.T) {
+	t.Title("LoginUser function of sessions manager")
+	t.NewStep("Init test data")
+	sessionId := "id"
+	userId := types.Id(1)
+	client := ClientInfo{IP: "127.0.0.1", UserAgent: "curl/8.0"}
+	info := testSession(userId, client, DefaultSessionPolicy.Default)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockSession.EXPECT().Set(gomock.Any(), info).
+			Do(
+				func(sesId string, _ *session.Session) {
+					sessionId = sesId
+				},
+			).Return(nil)
 
 		t.NewStep("Check result")
-		_, err := sms.sessionManager.Login(login, password)
+		credentials, err := sms.sessionManager.LoginUser(userId, client)
+		t.Require().NoError(err)
+		t.Require().Equal(sessionId, credentials.SessionId)
+		t.Require().Equal(DefaultSessionPolicy.Default.MaxLifetime, credentials.ExpiresIn)
+		t.Require().False(credentials.Persistent)
+	})
+
+	t.WithNewStep("Session repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockSession.EXPECT().Set(gomock.Any(), info).Return(testError)
+
+		t.NewStep("Check result")
+		_, err := sms.sessionManager.LoginUser(userId, client)
 		t.Require().ErrorIs(err, testError)
 	})
 }
 
+func (sms *SessionManagerSuite) TestSessionLifetime(t provider.T) {
+	t.Title("Session lifetime policies of sessions manager")
+	t.NewStep("Init test data")
+	userId := types.Id(1)
+	client := ClientInfo{IP: "127.0.0.1", UserAgent: "curl/8.0"}
+	remembered := client
+	remembered.RememberMe = true
+
+	manager := NewSessionManager(sms.mockUser, sms.mockSession, sms.mockAttempts, sms.mockToken, nil,
+		DefaultLoginProtection, DefaultTwoFactorPolicy, DefaultSessionPolicy, nil, NewTestPasswordHasher(), DefaultPasswordPolicy, nil, nil)
+	manager.now = func() time.Time { return testNow }
+
+	for _, lifetimeCase := range []struct {
+		name       string
+		role       types.Roles
+		client     ClientInfo
+		lifetime   SessionLifetime
+		persistent bool
+	}{
+		{"User session", types.USER, client, DefaultSessionPolicy.Default, false},
+		{"Remembered user session", types.USER, remembered, DefaultSessionPolicy.Remember, true},
+		{"Admin session", types.ADMIN, client, DefaultSessionPolicy.Admin, false},
+		{"Remembered admin session", types.ADMIN, remembered, DefaultSessionPolicy.Admin, false},
+	} {
+		t.WithNewStep(lifetimeCase.name+" execute", func(t provider.StepCtx) {
+			t.NewStep("Init mock")
+			sms.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId, Role: lifetimeCase.role}, nil)
+			sms.mockSession.EXPECT().Set(gomock.Any(), testSession(userId, lifetimeCase.client, lifetimeCase.lifetime)).
+				Return(nil)
+
+			t.NewStep("Check result")
+			credentials, err := manager.LoginUser(userId, lifetimeCase.client)
+			t.Require().NoError(err)
+			t.Require().Equal(lifetimeCase.lifetime.MaxLifetime, credentials.ExpiresIn)
+			t.Require().Equal(lifetimeCase.persistent, credentials.Persistent)
+		})
+	}
+
+	t.WithNewStep("Remember me without remember policy execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockSession.EXPECT().Set(gomock.Any(), testSession(userId, remembered, DefaultSessionPolicy.Default)).
+			Return(nil)
+
+		t.NewStep("Check result")
+		noRemember := NewSessionManager(sms.mockUser, sms.mockSession, sms.mockAttempts, sms.mockToken, nil,
+			DefaultLoginProtection, DefaultTwoFactorPolicy, SessionPolicy{Default: DefaultSessionPolicy.Default}, nil, NewTestPasswordHasher(), DefaultPasswordPolicy, nil, nil)
+		noRemember.now = func() time.Time { return testNow }
+
+		credentials, err := noRemember.LoginUser(userId, remembered)
+		t.Require().NoError(err)
+		t.Require().False(credentials.Persistent)
+	})
+
+	t.WithNewStep("User repository error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockUser.EXPECT().GetUserById(userId).Return(nil, testError)
+
+		t.NewStep("Check result")
+		_, err := manager.LoginUser(userId, client)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (sms *SessionManagerSuite) TestRefreshFunction(t provider.T) {
+	t.Title("Refresh function of sessions manager")
+
+	t.WithNewStep("Refresh is not supported execute", func(t provider.StepCtx) {
+		t.NewStep("Check result")
+		_, err := sms.sessionManager.Refresh("token", ClientInfo{})
+		t.Require().ErrorIs(err, ErrorRefreshNotSupported)
+	})
+}
+
+func (sms *SessionManagerSuite) TestUnlockUserFunction(t provider.T) {
+	t.Title("UnlockUser function of sessions manager")
+	t.NewStep("Init test data")
+	u := &user.User{ID: 1, Login: "login", Role: types.USER}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockUser.EXPECT().GetUserById(u.ID).Return(u, nil)
+		sms.mockAttempts.EXPECT().Reset("login:" + u.Login).Return(nil)
+
+		t.NewStep("Check result")
+		t.Require().NoError(sms.sessionManager.UnlockUser(u.ID))
+	})
+
+	t.WithNewStep("User repository user not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockUser.EXPECT().GetUserById(u.ID).Return(nil, user.ErrorUserNotFound)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(sms.sessionManager.UnlockUser(u.ID), user.ErrorUserNotFound)
+	})
+
+	t.WithNewStep("Attempts repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockUser.EXPECT().GetUserById(u.ID).Return(u, nil)
+		sms.mockAttempts.EXPECT().Reset("login:" + u.Login).Return(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(sms.sessionManager.UnlockUser(u.ID), testError)
+	})
+}
+
 func (sms *SessionManagerSuite) TestLogoutFunction(t provider.T) {
 	t.Title("Logout function of sessions manager")
 	t.NewStep("Init test data")
@@ -115,7 +548,7 @@ func (sms *SessionManagerSuite) TestLogoutFunction(t provider.T) {
 		sms.mockSession.EXPECT().Del(sessionId).Return(nil)
 
 		t.NewStep("Check result")
-		err := sms.sessionManager.Logout(sessionId)
+		err := sms.sessionManager.Logout(sessionId, ClientInfo{})
 		t.Require().NoError(err)
 	})
 
@@ -124,7 +557,7 @@ func (sms *SessionManagerSuite) TestLogoutFunction(t provider.T) {
 		sms.mockSession.EXPECT().Del(sessionId).Return(testError)
 
 		t.NewStep("Check result")
-		err := sms.sessionManager.Logout(sessionId)
+		err := sms.sessionManager.Logout(sessionId, ClientInfo{})
 		t.Require().ErrorIs(err, testError)
 	})
 }
@@ -141,7 +574,7 @@ func (sms *SessionManagerSuite) TestGetUserIdFunction(t provider.T) {
 
 	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		sms.mockSession.EXPECT().GetUserId(sessionId, ExpiredSessionTime).Return(u.ID, nil)
+		sms.mockSession.EXPECT().GetUserId(sessionId).Return(u.ID, nil)
 		sms.mockUser.EXPECT().GetUserById(u.ID).Return(u, nil)
 
 		t.NewStep("Check result")
@@ -152,7 +585,7 @@ func (sms *SessionManagerSuite) TestGetUserIdFunction(t provider.T) {
 
 	t.WithNewStep("Session repository error in execution", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		sms.mockSession.EXPECT().GetUserId(sessionId, ExpiredSessionTime).Return(u.ID, testError)
+		sms.mockSession.EXPECT().GetUserId(sessionId).Return(u.ID, testError)
 
 		t.NewStep("Check result")
 		_, err := sms.sessionManager.GetUserId(sessionId)
@@ -161,7 +594,7 @@ func (sms *SessionManagerSuite) TestGetUserIdFunction(t provider.T) {
 
 	t.WithNewStep("User repository error in execution", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		sms.mockSession.EXPECT().GetUserId(sessionId, ExpiredSessionTime).Return(u.ID, nil)
+		sms.mockSession.EXPECT().GetUserId(sessionId).Return(u.ID, nil)
 		sms.mockUser.EXPECT().GetUserById(u.ID).Return(u, testError)
 
 		t.NewStep("Check result")
@@ -171,7 +604,7 @@ func (sms *SessionManagerSuite) TestGetUserIdFunction(t provider.T) {
 
 	t.WithNewStep("User repository user not found in execution", func(t provider.StepCtx) {
 		t.NewStep("Init mock")
-		sms.mockSession.EXPECT().GetUserId(sessionId, ExpiredSessionTime).Return(u.ID, nil)
+		sms.mockSession.EXPECT().GetUserId(sessionId).Return(u.ID, nil)
 		sms.mockUser.EXPECT().GetUserById(u.ID).Return(u, user.ErrorUserNotFound)
 		sms.mockSession.EXPECT().Del(sessionId)
 
@@ -181,6 +614,463 @@ func (sms *SessionManagerSuite) TestGetUserIdFunction(t provider.T) {
 	})
 }
 
+func (sms *SessionManagerSuite) TestUserCache(t provider.T) {
+	t.Title("Cache of session users of sessions manager")
+	t.NewStep("Init test data")
+	u := &user.User{ID: 1, Login: "login", Role: types.USER}
+	sessionId := "id"
+	now := testNow
+	cache := NewUserCache(5 * time.Second)
+	cache.now = func() time.Time { return now }
+	manager := NewSessionManager(sms.mockUser, sms.mockSession, sms.mockAttempts, sms.mockToken, nil,
+		DefaultLoginProtection, DefaultTwoFactorPolicy, testSessionPolicy, cache, NewTestPasswordHasher(), DefaultPasswordPolicy, nil, nil)
+
+	expectLookup := func() {
+		sms.mockSession.EXPECT().GetUserId(sessionId).Return(u.ID, nil)
+		sms.mockUser.EXPECT().GetUserById(u.ID).Return(&user.User{ID: u.ID, Login: u.Login, Role: u.Role}, nil)
+	}
+
+	t.WithNewStep("Cached user execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectLookup()
+
+		t.NewStep("Check result")
+		for i := 0; i < 2; i++ {
+			usr, err := manager.GetUserId(sessionId)
+			t.Require().NoError(err)
+			t.Require().Equal(u, usr)
+			usr.Role = types.ADMIN
+		}
+		t.Require().Equal(CacheStats{Hits: 1, Misses: 1, HitRatio: 0.5}, cache.Stats())
+	})
+
+	t.WithNewStep("Outdated user execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		now = now.Add(5 * time.Second)
+		expectLookup()
+
+		t.NewStep("Check result")
+		_, err := manager.GetUserId(sessionId)
+		t.Require().NoError(err)
+	})
+
+	t.WithNewStep("Invalidated user execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		manager.InvalidateUser(u.ID)
+		expectLookup()
+
+		t.NewStep("Check result")
+		_, err := manager.GetUserId(sessionId)
+		t.Require().NoError(err)
+	})
+
+	t.WithNewStep("Invalidated during lookup user execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		manager.InvalidateUser(u.ID)
+		sms.mockSession.EXPECT().GetUserId(sessionId).Return(u.ID, nil)
+		sms.mockUser.EXPECT().GetUserById(u.ID).DoAndReturn(func(id types.Id) (*user.User, error) {
+			// Пользователь изменён, пока читались его устаревшие данные
+			manager.InvalidateUser(id)
+			return &user.User{ID: u.ID, Login: u.Login, Role: u.Role}, nil
+		})
+		expectLookup()
+
+		t.NewStep("Check result")
+		for i := 0; i < 2; i++ {
+			_, err := manager.GetUserId(sessionId)
+			t.Require().NoError(err)
+		}
+	})
+
+	t.WithNewStep("Copied permissions execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		manager.InvalidateUser(u.ID)
+		maxCertification := types.AGE12
+		sms.mockSession.EXPECT().GetUserId(sessionId).Return(u.ID, nil)
+		sms.mockUser.EXPECT().GetUserById(u.ID).Return(&user.User{ID: u.ID, Permissions: []types.Permission{types.FilmWrite},
+			MaxCertification: &maxCertification}, nil)
+
+		t.NewStep("Check result")
+		usr, err := manager.GetUserId(sessionId)
+		t.Require().NoError(err)
+		usr.Permissions[0] = types.RoleManage
+		*usr.MaxCertification = types.AGE18
+
+		usr, err = manager.GetUserId(sessionId)
+		t.Require().NoError(err)
+		t.Require().Equal([]types.Permission{types.FilmWrite}, usr.Permissions)
+		t.Require().Equal(types.AGE12, *usr.MaxCertification)
+		usr.Permissions[0] = types.RoleManage
+		*usr.MaxCertification = types.AGE18
+
+		usr, err = manager.GetUserId(sessionId)
+		t.Require().NoError(err)
+		t.Require().Equal([]types.Permission{types.FilmWrite}, usr.Permissions)
+		t.Require().Equal(types.AGE12, *usr.MaxCertification)
+	})
+
+	t.WithNewStep("Invalidated all users execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		manager.InvalidateAll()
+		expectLookup()
+
+		t.NewStep("Check result")
+		_, err := manager.GetUserId(sessionId)
+		t.Require().NoError(err)
+	})
+
+	t.WithNewStep("Logout execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockSession.EXPECT().Del(sessionId).Return(nil)
+		t.Require().NoError(manager.Logout(sessionId, ClientInfo{}))
+		sms.mockSession.EXPECT().GetUserId(sessionId).Return(u.ID, session.ErrorNoSession)
+
+		t.NewStep("Check result")
+		_, err := manager.GetUserId(sessionId)
+		t.Require().ErrorIs(err, session.ErrorNoSession)
+	})
+
+	t.WithNewStep("Revoked sessions with repository error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectLookup()
+		_, err := manager.GetUserId(sessionId)
+		t.Require().NoError(err)
+
+		sms.mockSession.EXPECT().DelUserSessions(u.ID, "").Return(testError)
+		t.Require().ErrorIs(manager.RevokeUserSessions(u.ID), testError)
+		expectLookup()
+
+		t.NewStep("Check result")
+		_, err = manager.GetUserId(sessionId)
+		t.Require().NoError(err)
+	})
+
+	t.WithNewStep("Disabled cache execute", func(t provider.StepCtx) {
+		t.Require().Nil(NewUserCache(0))
+		t.Require().Equal(CacheStats{}, NewUserCache(0).Stats())
+	})
+}
+
+func (sms *SessionManagerSuite) TestChangePasswordFunction(t provider.T) {
+	t.Title("ChangePassword function of sessions manager")
+	t.NewStep("Init test data")
+	password := "password"
+	newPassword := "new password"
+	encryptPassword, err := NewTestPasswordHasher().Hash(password)
+	t.Require().NoError(err)
+	sessionId := "id"
+	userId := types.Id(1)
+
+	checkPassword := func(usr *user.User) {
+		t.Require().Equal(userId, usr.ID)
+		_, err := NewTestPasswordHasher().Verify(usr.Password, newPassword)
+		t.Require().NoError(err)
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockUser.EXPECT().GetPasswordById(userId).
+			Return(&user.LoginUser{ID: userId, Password: encryptPassword, Status: user.StatusActive}, nil)
+		sms.mockUser.EXPECT().UpdateUserPassword(gomock.Any()).Do(checkPassword).Return(nil)
+		sms.mockSession.EXPECT().DelUserSessions(userId, sessionId).Return(nil)
+
+		t.NewStep("Check result")
+		t.Require().NoError(sms.sessionManager.ChangePassword(userId, sessionId, password, newPassword, ClientInfo{}))
+	})
+
+	t.WithNewStep("Incorrect password execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockUser.EXPECT().GetPasswordById(userId).
+			Return(&user.LoginUser{ID: userId, Password: encryptPassword, Status: user.StatusActive}, nil)
+
+		t.NewStep("Check result")
+		err := sms.sessionManager.ChangePassword(userId, sessionId, newPassword, newPassword, ClientInfo{})
+		t.Require().ErrorIs(err, ErrorIncorrectPassword)
+	})
+
+	t.WithNewStep("Weak new password execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockUser.EXPECT().GetPasswordById(userId).
+			Return(&user.LoginUser{ID: userId, Password: encryptPassword, Status: user.StatusActive}, nil)
+
+		t.NewStep("Check result")
+		err := sms.sessionManager.ChangePassword(userId, sessionId, password, "onlylowercase", ClientInfo{})
+		t.Require().ErrorIs(err, ErrorPasswordPolicy)
+	})
+
+	t.WithNewStep("User without password execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockUser.EXPECT().GetPasswordById(userId).Return(&user.LoginUser{ID: userId}, nil)
+
+		t.NewStep("Check result")
+		err := sms.sessionManager.ChangePassword(userId, sessionId, "", newPassword, ClientInfo{})
+		t.Require().ErrorIs(err, ErrorIncorrectPassword)
+	})
+
+	t.WithNewStep("User repository error on get password", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockUser.EXPECT().GetPasswordById(userId).Return(nil, testError)
+
+		t.NewStep("Check result")
+		err := sms.sessionManager.ChangePassword(userId, sessionId, password, newPassword, ClientInfo{})
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("User repository error on update password", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockUser.EXPECT().GetPasswordById(userId).
+			Return(&user.LoginUser{ID: userId, Password: encryptPassword, Status: user.StatusActive}, nil)
+		sms.mockUser.EXPECT().UpdateUserPassword(gomock.Any()).Return(testError)
+
+		t.NewStep("Check result")
+		err := sms.sessionManager.ChangePassword(userId, sessionId, password, newPassword, ClientInfo{})
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Session repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockUser.EXPECT().GetPasswordById(userId).
+			Return(&user.LoginUser{ID: userId, Password: encryptPassword, Status: user.StatusActive}, nil)
+		sms.mockUser.EXPECT().UpdateUserPassword(gomock.Any()).Return(nil)
+		sms.mockSession.EXPECT().DelUserSessions(userId, sessionId).Return(testError)
+
+		t.NewStep("Check result")
+		err := sms.sessionManager.ChangePassword(userId, sessionId, password, newPassword, ClientInfo{})
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (sms *SessionManagerSuite) TestResetPasswordFunction(t provider.T) {
+	t.Title("ResetPassword function of sessions manager")
+	t.NewStep("Init test data")
+	newPassword := "new password"
+	userId := types.Id(1)
+
+	checkPassword := func(usr *user.User) {
+		t.Require().Equal(userId, usr.ID)
+		_, err := NewTestPasswordHasher().Verify(usr.Password, newPassword)
+		t.Require().NoError(err)
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockUser.EXPECT().UpdateUserPassword(gomock.Any()).Do(checkPassword).Return(nil)
+		sms.mockSession.EXPECT().DelUserSessions(userId, "").Return(nil)
+
+		t.NewStep("Check result")
+		t.Require().NoError(sms.sessionManager.ResetPassword(userId, newPassword, ClientInfo{}))
+	})
+
+	t.WithNewStep("Weak new password execute", func(t provider.StepCtx) {
+		t.NewStep("Check result")
+		err := sms.sessionManager.ResetPassword(userId, "password", ClientInfo{})
+		t.Require().ErrorIs(err, ErrorPasswordPolicy)
+	})
+
+	t.WithNewStep("User repository user not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockUser.EXPECT().UpdateUserPassword(gomock.Any()).Return(user.ErrorUserNotFound)
+
+		t.NewStep("Check result")
+		err := sms.sessionManager.ResetPassword(userId, newPassword, ClientInfo{})
+		t.Require().ErrorIs(err, user.ErrorUserNotFound)
+	})
+
+	t.WithNewStep("Session repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockUser.EXPECT().UpdateUserPassword(gomock.Any()).Return(nil)
+		sms.mockSession.EXPECT().DelUserSessions(userId, "").Return(testError)
+
+		t.NewStep("Check result")
+		err := sms.sessionManager.ResetPassword(userId, newPassword, ClientInfo{})
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (sms *SessionManagerSuite) TestGetSessionsFunction(t provider.T) {
+	t.Title("GetSessions function of sessions manager")
+	t.NewStep("Init test data")
+	userId := types.Id(1)
+	currentSessionId := "current"
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockSession.EXPECT().GetUserSessions(userId).Return([]session.Session{
+			{ID: session.PublicId("other"), UserID: userId},
+			{ID: session.PublicId(currentSessionId), UserID: userId},
+		}, nil)
+
+		t.NewStep("Check result")
+		sessions, err := sms.sessionManager.GetSessions(userId, currentSessionId)
+		t.Require().NoError(err)
+		t.Require().Len(sessions, 2)
+		t.Require().False(sessions[0].Current)
+		t.Require().True(sessions[1].Current)
+	})
+
+	t.WithNewStep("Session repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockSession.EXPECT().GetUserSessions(userId).Return(nil, testError)
+
+		t.NewStep("Check result")
+		_, err := sms.sessionManager.GetSessions(userId, currentSessionId)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (sms *SessionManagerSuite) TestRevokeSessionFunction(t provider.T) {
+	t.Title("RevokeSession function of sessions manager")
+	t.NewStep("Init test data")
+	userId := types.Id(1)
+	publicId := session.PublicId("session")
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockSession.EXPECT().DelUserSession(userId, publicId).Return(nil)
+
+		t.NewStep("Check result")
+		t.Require().NoError(sms.sessionManager.RevokeSession(userId, publicId))
+	})
+
+	t.WithNewStep("Session not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockSession.EXPECT().DelUserSession(userId, publicId).Return(session.ErrorNoSession)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(sms.sessionManager.RevokeSession(userId, publicId), session.ErrorNoSession)
+	})
+}
+
+func (sms *SessionManagerSuite) TestRevokeUserSessionsFunction(t provider.T) {
+	t.Title("RevokeUserSessions function of sessions manager")
+	t.NewStep("Init test data")
+	userId := types.Id(1)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockSession.EXPECT().DelUserSessions(userId, "").Return(nil)
+
+		t.NewStep("Check result")
+		t.Require().NoError(sms.sessionManager.RevokeUserSessions(userId))
+	})
+
+	t.WithNewStep("Session repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockSession.EXPECT().DelUserSessions(userId, "").Return(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(sms.sessionManager.RevokeUserSessions(userId), testError)
+	})
+}
+
+func (sms *SessionManagerSuite) TestCreateTokenFunction(t provider.T) {
+	t.Title("CreateToken function of sessions manager")
+	t.NewStep("Init test data")
+	tkn := &token.Token{UserID: 1, Name: "ci", ReadOnly: true}
+	createdToken := &token.Token{ID: 2, UserID: 1, Name: "ci", ReadOnly: true}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		var hash string
+		sms.mockToken.EXPECT().CreateToken(tkn, gomock.Any()).
+			Do(func(_ *token.Token, h string) { hash = h }).Return(createdToken, nil)
+
+		t.NewStep("Check result")
+		secret, res, err := sms.sessionManager.CreateToken(tkn)
+		t.Require().NoError(err)
+		t.Require().Equal(createdToken, res)
+		t.Require().True(strings.HasPrefix(secret, TokenPrefix))
+		t.Require().Equal(hashToken(secret), hash)
+		t.Require().NotEqual(secret, hash)
+	})
+
+	t.WithNewStep("Token repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockToken.EXPECT().CreateToken(tkn, gomock.Any()).Return(nil, token.ErrorTokenNameAlreadyExists)
+
+		t.NewStep("Check result")
+		_, _, err := sms.sessionManager.CreateToken(tkn)
+		t.Require().ErrorIs(err, token.ErrorTokenNameAlreadyExists)
+	})
+}
+
+func (sms *SessionManagerSuite) TestRevokeTokenFunction(t provider.T) {
+	t.Title("RevokeToken function of sessions manager")
+	t.NewStep("Init test data")
+	userId, tokenId := types.Id(1), types.Id(2)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockToken.EXPECT().DeleteToken(userId, tokenId).Return(nil)
+
+		t.NewStep("Check result")
+		t.Require().NoError(sms.sessionManager.RevokeToken(userId, tokenId))
+	})
+
+	t.WithNewStep("Token not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockToken.EXPECT().DeleteToken(userId, tokenId).Return(token.ErrorTokenNotFound)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(sms.sessionManager.RevokeToken(userId, tokenId), token.ErrorTokenNotFound)
+	})
+}
+
+func (sms *SessionManagerSuite) TestGetUserByTokenFunction(t provider.T) {
+	t.Title("GetUserByToken function of sessions manager")
+	t.NewStep("Init test data")
+	secret := TokenPrefix + "secret"
+	tkn := &token.Token{ID: 2, UserID: 1, Name: "ci"}
+	usr := &user.User{ID: 1, Login: "login", Role: types.USER}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockToken.EXPECT().UseToken(hashToken(secret)).Return(tkn, nil)
+		sms.mockUser.EXPECT().GetUserById(tkn.UserID).Return(usr, nil)
+
+		t.NewStep("Check result")
+		resUsr, resToken, err := sms.sessionManager.GetUserByToken(secret)
+		t.Require().NoError(err)
+		t.Require().Equal(usr, resUsr)
+		t.Require().Equal(tkn, resToken)
+	})
+
+	t.WithNewStep("Token without prefix execute", func(t provider.StepCtx) {
+		t.NewStep("Check result")
+		_, _, err := sms.sessionManager.GetUserByToken("secret")
+		t.Require().ErrorIs(err, token.ErrorTokenNotFound)
+	})
+
+	t.WithNewStep("Token not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockToken.EXPECT().UseToken(hashToken(secret)).Return(nil, token.ErrorTokenNotFound)
+
+		t.NewStep("Check result")
+		_, _, err := sms.sessionManager.GetUserByToken(secret)
+		t.Require().ErrorIs(err, token.ErrorTokenNotFound)
+	})
+
+	t.WithNewStep("User not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockToken.EXPECT().UseToken(hashToken(secret)).Return(tkn, nil)
+		sms.mockUser.EXPECT().GetUserById(tkn.UserID).Return(nil, user.ErrorUserNotFound)
+
+		t.NewStep("Check result")
+		_, _, err := sms.sessionManager.GetUserByToken(secret)
+		t.Require().ErrorIs(err, token.ErrorTokenNotFound)
+	})
+
+	t.WithNewStep("User repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockToken.EXPECT().UseToken(hashToken(secret)).Return(tkn, nil)
+		sms.mockUser.EXPECT().GetUserById(tkn.UserID).Return(nil, testError)
+
+		t.NewStep("Check result")
+		_, _, err := sms.sessionManager.GetUserByToken(secret)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
 func TestRunSessionManagerSuite(t *testing.T) {
 	suite.RunSuite(t, new(SessionManagerSuite))
 }
@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/user"
+)
+
+// UserCache
+// Short-lived in-process cache of users of sessions, so authenticated requests
+// don't query the session store and Postgres every time. A cached session stays valid
+// up to the ttl after it expires or is revoked on another instance of the server.
+// Nil cache caches nothing. Safe for concurrent use.
+type UserCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu          sync.Mutex
+	entries     map[string]cachedUser
+	sessions    map[types.Id]map[string]struct{}
+	generations map[types.Id]uint64
+	epoch       uint64
+	sweptAt     time.Time
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+type cachedUser struct {
+	user      user.User
+	expiresAt time.Time
+}
+
+// cacheGeneration
+// Поколение данных пользователя в кеше. Меняется при каждом сбросе кеша пользователя,
+// чтобы пользователь, прочитанный до сброса, не попал в кеш после него
+type cacheGeneration struct {
+	epoch uint64
+	user  uint64
+}
+
+// CacheStats
+// Counters of the cache since the start of the server
+type CacheStats struct {
+	Hits     uint64  `json:"hits"`
+	Misses   uint64  `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+// NewUserCache
+// Returns nil if ttl is not positive
+func NewUserCache(ttl time.Duration) *UserCache {
+	if ttl <= 0 {
+		return nil
+	}
+
+	return &UserCache{
+		ttl:         ttl,
+		now:         time.Now,
+		entries:     make(map[string]cachedUser),
+		sessions:    make(map[types.Id]map[string]struct{}),
+		generations: make(map[types.Id]uint64),
+	}
+}
+
+// Stats
+// Returns the number of hits and misses and the share of hits among all lookups
+func (uc *UserCache) Stats() CacheStats {
+	if uc == nil {
+		return CacheStats{}
+	}
+
+	stats := CacheStats{Hits: uc.hits.Load(), Misses: uc.misses.Load()}
+	if total := stats.Hits + stats.Misses; total != 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
+
+// get
+// Возвращает копию пользователя сессии, если она есть в кеше и ещё не устарела
+func (uc *UserCache) get(sessionId string) (*user.User, bool) {
+	if uc == nil {
+		return nil, false
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	entry, found := uc.entries[sessionId]
+	if !found || !uc.now().Before(entry.expiresAt) {
+		uc.misses.Add(1)
+		return nil, false
+	}
+
+	uc.hits.Add(1)
+	usr := copyUser(&entry.user)
+	return &usr, true
+}
+
+// generation
+// Возвращает текущее поколение данных пользователя. Его нужно получить до чтения пользователя
+// из хранилища и передать в put
+func (uc *UserCache) generation(userId types.Id) cacheGeneration {
+	if uc == nil {
+		return cacheGeneration{}
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	return cacheGeneration{epoch: uc.epoch, user: uc.generations[userId]}
+}
+
+// put
+// Сохраняет пользователя сессии на время ttl, если с момента получения поколения
+// кеш пользователя не сбрасывался
+func (uc *UserCache) put(sessionId string, usr *user.User, gen cacheGeneration) {
+	if uc == nil {
+		return
+	}
+
+	now := uc.now()
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if gen != (cacheGeneration{epoch: uc.epoch, user: uc.generations[usr.ID]}) {
+		return
+	}
+
+	// Устаревшие записи удаляются не чаще раза за ttl, чтобы кеш не рос за счёт закрытых сессий
+	if now.Sub(uc.sweptAt) >= uc.ttl {
+		uc.sweep(now)
+	}
+
+	uc.entries[sessionId] = cachedUser{user: copyUser(usr), expiresAt: now.Add(uc.ttl)}
+	if uc.sessions[usr.ID] == nil {
+		uc.sessions[usr.ID] = make(map[string]struct{})
+	}
+	uc.sessions[usr.ID][sessionId] = struct{}{}
+}
+
+// forgetSession
+// Удаляет сессию из кеша
+func (uc *UserCache) forgetSession(sessionId string) {
+	if uc == nil {
+		return
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if entry, found := uc.entries[sessionId]; found {
+		uc.delete(entry.user.ID, sessionId)
+	}
+}
+
+// forgetUser
+// Удаляет из кеша все сессии пользователя
+func (uc *UserCache) forgetUser(userId types.Id) {
+	if uc == nil {
+		return
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.generations[userId]++
+	for sessionId := range uc.sessions[userId] {
+		uc.delete(userId, sessionId)
+	}
+}
+
+// forgetAll
+// Удаляет из кеша сессии всех пользователей
+func (uc *UserCache) forgetAll() {
+	if uc == nil {
+		return
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.epoch++
+	uc.entries = make(map[string]cachedUser)
+	uc.sessions = make(map[types.Id]map[string]struct{})
+}
+
+// sweep
+// Удаляет устаревшие записи. Вызывается под блокировкой
+func (uc *UserCache) sweep(now time.Time) {
+	for sessionId, entry := range uc.entries {
+		if !now.Before(entry.expiresAt) {
+			uc.delete(entry.user.ID, sessionId)
+		}
+	}
+	uc.sweptAt = now
+}
+
+// delete
+// Удаляет запись сессии пользователя. Вызывается под блокировкой
+func (uc *UserCache) delete(userId types.Id, sessionId string) {
+	delete(uc.entries, sessionId)
+	delete(uc.sessions[userId], sessionId)
+	if len(uc.sessions[userId]) == 0 {
+		delete(uc.sessions, userId)
+	}
+}
+
+// copyUser
+// Копирует пользователя вместе с правами и ограничением, чтобы изменения копии не затрагивали кеш
+func copyUser(usr *user.User) user.User {
+	cp := *usr
+	if usr.Permissions != nil {
+		cp.Permissions = append([]types.Permission(nil), usr.Permissions...)
+	}
+	if usr.MaxCertification != nil {
+		maxCertification := *usr.MaxCertification
+		cp.MaxCertification = &maxCertification
+	}
+	return cp
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"github.com/pkg/errors"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/event"
+)
+
+// recordEvent
+// Сохраняет событие безопасности пользователя, если журнал событий включён.
+// Ошибка сохранения не мешает самой операции, поэтому не возвращается, а записывается в лог
+func (sm *SessionManager) recordEvent(userId types.Id, eventType event.Type, client ClientInfo) {
+	if sm.events == nil {
+		return
+	}
+
+	err := sm.events.AddEvent(&event.Event{
+		UserID:    userId,
+		Type:      eventType,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+	})
+	if err != nil {
+		sm.l.Error(errors.Wrapf(err, "[Security] can't record event %s of user %d", eventType, userId))
+	}
+}
+
+// recordFailure
+// Сохраняет событие неудачного входа пользователя и событие блокировки,
+// если неудачная попытка привела к ней
+func (sm *SessionManager) recordFailure(userId types.Id, client ClientInfo, err error) {
+	sm.recordEvent(userId, event.LoginFailed, client)
+
+	var lockout *LockoutError
+	if errors.As(err, &lockout) {
+		sm.recordEvent(userId, event.Lockout, client)
+	}
+}
+
+func (sm *SessionManager) GetEvents(filter event.Filter) ([]event.Event, error) {
+	if sm.events == nil {
+		return []event.Event{}, nil
+	}
+
+	events, err := sm.events.GetEvents(filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "try get security events")
+	}
+
+	return events, nil
+}
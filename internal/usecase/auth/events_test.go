@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"go.uber.org/mock/gomock"
+	"time"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/event"
+	mre "vk_film/internal/repository/event/mocks"
+	"vk_film/internal/repository/session"
+	"vk_film/internal/repository/user"
+	"vk_film/pkg/logger"
+)
+
+// testLogger
+// Запоминает ошибки, записанные в лог
+type testLogger struct {
+	errors []any
+}
+
+func (*testLogger) Debug(_ any, _ ...any)                          {}
+func (*testLogger) Info(_ any, _ ...any)                           {}
+func (*testLogger) Warn(_ any, _ ...any)                           {}
+func (tl *testLogger) Error(message any, _ ...any)                 { tl.errors = append(tl.errors, message) }
+func (*testLogger) Panic(_ any, _ ...any)                          {}
+func (*testLogger) Fatal(_ any, _ ...any)                          {}
+func (tl *testLogger) With(_ logger.Field, _ any) logger.Interface { return tl }
+
+// testEvent
+// Ожидаемое событие безопасности пользователя от клиента
+func testEvent(userId types.Id, eventType event.Type, client ClientInfo) *event.Event {
+	return &event.Event{
+		UserID:    userId,
+		Type:      eventType,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+	}
+}
+
+func (sms *SessionManagerSuite) TestSecurityEventsFunction(t provider.T) {
+	t.Title("Security events of sessions manager")
+	t.NewStep("Init test data")
+	mockEvents := mre.NewEventRepository(sms.gmc)
+	l := &testLogger{}
+	manager := NewSessionManager(sms.mockUser, sms.mockSession, sms.mockAttempts, sms.mockToken, nil,
+		DefaultLoginProtection, DefaultTwoFactorPolicy, testSessionPolicy, nil, NewTestPasswordHasher(), DefaultPasswordPolicy,
+		mockEvents, l)
+	manager.now = func() time.Time { return testNow }
+
+	login := "login"
+	password := "password"
+	encryptPassword, err := NewTestPasswordHasher().Hash(password)
+	t.Require().NoError(err)
+	userId := types.Id(1)
+	sessionId := "id"
+	client := ClientInfo{IP: "127.0.0.1", UserAgent: "curl/8.0"}
+	loginKey, ipKey := "login:"+login, "ip:"+client.IP
+	window := DefaultLoginProtection.AttemptsWindow
+	loginUser := &user.LoginUser{ID: userId, Password: encryptPassword, Status: user.StatusActive}
+
+	expectNoLock := func() {
+		sms.mockAttempts.EXPECT().GetLockTime(loginKey).Return(time.Duration(0), nil)
+		sms.mockAttempts.EXPECT().GetLockTime(ipKey).Return(time.Duration(0), nil)
+	}
+
+	t.WithNewStep("Successful login execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectNoLock()
+		sms.mockUser.EXPECT().GetPasswordByLogin(login).Return(loginUser, nil)
+		sms.mockAttempts.EXPECT().Reset(loginKey).Return(nil)
+		sms.mockSession.EXPECT().Set(gomock.Any(), gomock.Any()).Return(nil)
+		mockEvents.EXPECT().AddEvent(testEvent(userId, event.LoginSucceeded, client)).Return(nil)
+
+		t.NewStep("Check result")
+		_, err := manager.Login(login, password, client)
+		t.Require().NoError(err)
+	})
+
+	t.WithNewStep("Failed login execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectNoLock()
+		sms.mockUser.EXPECT().GetPasswordByLogin(login).Return(loginUser, nil)
+		sms.mockAttempts.EXPECT().Add(loginKey, window).Return(uint64(1), nil)
+		sms.mockAttempts.EXPECT().Add(ipKey, window).Return(uint64(1), nil)
+		mockEvents.EXPECT().AddEvent(testEvent(userId, event.LoginFailed, client)).Return(nil)
+
+		t.NewStep("Check result")
+		_, err := manager.Login(login, login, client)
+		t.Require().ErrorIs(err, ErrorIncorrectPassword)
+	})
+
+	t.WithNewStep("Failed login with lockout execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectNoLock()
+		sms.mockUser.EXPECT().GetPasswordByLogin(login).Return(loginUser, nil)
+		sms.mockAttempts.EXPECT().Add(loginKey, window).Return(DefaultLoginProtection.MaxLoginAttempts, nil)
+		sms.mockAttempts.EXPECT().Lock(loginKey, DefaultLoginProtection.BaseLockTime).Return(nil)
+		sms.mockAttempts.EXPECT().Add(ipKey, window).Return(uint64(1), nil)
+		gomock.InOrder(
+			mockEvents.EXPECT().AddEvent(testEvent(userId, event.LoginFailed, client)).Return(nil),
+			mockEvents.EXPECT().AddEvent(testEvent(userId, event.Lockout, client)).Return(nil),
+		)
+
+		t.NewStep("Check result")
+		_, err := manager.Login(login, login, client)
+		t.Require().ErrorIs(err, ErrorTooManyAttempts)
+	})
+
+	t.WithNewStep("Unknown login execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectNoLock()
+		sms.mockUser.EXPECT().GetPasswordByLogin(login).Return(nil, user.ErrorUserNotFound)
+		sms.mockAttempts.EXPECT().Add(loginKey, window).Return(uint64(1), nil)
+		sms.mockAttempts.EXPECT().Add(ipKey, window).Return(uint64(1), nil)
+
+		t.NewStep("Check result")
+		_, err := manager.Login(login, password, client)
+		t.Require().ErrorIs(err, user.ErrorUserNotFound)
+	})
+
+	t.WithNewStep("Event repository error doesn't break login execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockSession.EXPECT().Set(gomock.Any(), gomock.Any()).Return(nil)
+		mockEvents.EXPECT().AddEvent(testEvent(userId, event.LoginSucceeded, client)).Return(testError)
+
+		t.NewStep("Check result")
+		_, err := manager.LoginUser(userId, client)
+		t.Require().NoError(err)
+	})
+
+	t.WithNewStep("Logout execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockSession.EXPECT().GetUserId(sessionId).Return(userId, nil)
+		sms.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId}, nil)
+		sms.mockSession.EXPECT().Del(sessionId).Return(nil)
+		mockEvents.EXPECT().AddEvent(testEvent(userId, event.Logout, client)).Return(nil)
+
+		t.NewStep("Check result")
+		t.Require().NoError(manager.Logout(sessionId, client))
+	})
+
+	t.WithNewStep("Logout of expired session execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockSession.EXPECT().GetUserId(sessionId).Return(types.Id(0), session.ErrorNoSession)
+		sms.mockSession.EXPECT().Del(sessionId).Return(nil)
+
+		t.NewStep("Check result")
+		t.Require().NoError(manager.Logout(sessionId, client))
+	})
+
+	t.WithNewStep("Change password execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockUser.EXPECT().GetPasswordById(userId).Return(loginUser, nil)
+		sms.mockUser.EXPECT().UpdateUserPassword(gomock.Any()).Return(nil)
+		sms.mockSession.EXPECT().DelUserSessions(userId, sessionId).Return(nil)
+		mockEvents.EXPECT().AddEvent(testEvent(userId, event.PasswordChanged, client)).Return(nil)
+
+		t.NewStep("Check result")
+		t.Require().NoError(manager.ChangePassword(userId, sessionId, password, "new password", client))
+	})
+
+	t.WithNewStep("Reset password execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockUser.EXPECT().UpdateUserPassword(gomock.Any()).Return(nil)
+		sms.mockSession.EXPECT().DelUserSessions(userId, "").Return(nil)
+		mockEvents.EXPECT().AddEvent(testEvent(userId, event.PasswordReset, client)).Return(nil)
+
+		t.NewStep("Check result")
+		t.Require().NoError(manager.ResetPassword(userId, "new password", client))
+	})
+
+	t.WithNewStep("Event repository error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sms.mockUser.EXPECT().UpdateUserPassword(gomock.Any()).Return(nil)
+		sms.mockSession.EXPECT().DelUserSessions(userId, "").Return(nil)
+		mockEvents.EXPECT().AddEvent(testEvent(userId, event.PasswordReset, client)).Return(testError)
+
+		t.NewStep("Check result")
+		logged := len(l.errors)
+		t.Require().NoError(manager.ResetPassword(userId, "new password", client))
+		t.Require().Len(l.errors, logged+1)
+		t.Require().ErrorIs(l.errors[logged].(error), testError)
+	})
+}
+
+func (sms *SessionManagerSuite) TestGetEventsFunction(t provider.T) {
+	t.Title("GetEvents function of sessions manager")
+	t.NewStep("Init test data")
+	mockEvents := mre.NewEventRepository(sms.gmc)
+	manager := NewSessionManager(sms.mockUser, sms.mockSession, sms.mockAttempts, sms.mockToken, nil,
+		DefaultLoginProtection, DefaultTwoFactorPolicy, testSessionPolicy, nil, NewTestPasswordHasher(), DefaultPasswordPolicy,
+		mockEvents, &testLogger{})
+	filter := event.Filter{UserID: 1, Types: []event.Type{event.LoginFailed}, Limit: 10}
+	expected := []event.Event{{ID: 1, UserID: 1, Type: event.LoginFailed, CreatedAt: testNow}}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		mockEvents.EXPECT().GetEvents(filter).Return(expected, nil)
+
+		t.NewStep("Check result")
+		events, err := manager.GetEvents(filter)
+		t.Require().NoError(err)
+		t.Require().Equal(expected, events)
+	})
+
+	t.WithNewStep("Disabled event log execute", func(t provider.StepCtx) {
+		t.NewStep("Check result")
+		events, err := sms.sessionManager.GetEvents(filter)
+		t.Require().NoError(err)
+		t.Require().Empty(events)
+	})
+
+	t.WithNewStep("Event repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		mockEvents.EXPECT().GetEvents(filter).Return(nil, testError)
+
+		t.NewStep("Check result")
+		_, err := manager.GetEvents(filter)
+		t.Require().ErrorIs(err, testError)
+	})
+}
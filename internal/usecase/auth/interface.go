@@ -1,18 +1,196 @@
 package auth
 
 import (
+	"fmt"
 	"github.com/pkg/errors"
+	"time"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/event"
+	"vk_film/internal/repository/session"
+	"vk_film/internal/repository/token"
 	"vk_film/internal/repository/user"
 )
 
 var (
-	ErrorIncorrectPassword = errors.New("incorrect password")
+	ErrorIncorrectPassword   = errors.New("incorrect password")
+	ErrorUnknownPasswordHash = errors.New("unknown format of password hash")
+	ErrorTooManyAttempts     = errors.New("too many login attempts")
+	ErrorAccountNotActive    = errors.New("account is not approved")
+	ErrorPasswordPolicy      = errors.New("password does not satisfy the policy")
+
+	ErrorRefreshNotSupported = errors.New("refresh tokens are supported only in jwt mode")
+	ErrorRefreshTokenReused  = errors.New("refresh token was already used")
+
+	ErrorTwoFactorDisabled = errors.New("two-factor authentication is not configured")
+	ErrorIncorrectCode     = errors.New("incorrect two-factor code")
+	ErrorTwoFactorRequired = errors.New("two-factor authentication is required for the role of user")
 )
 
+const (
+	SessionMode = "session"
+	JWTMode     = "jwt"
+)
+
+// Credentials
+// Result of a successful login or refresh. In session mode SessionId is the identifier
+// of the session and RefreshToken is empty, in jwt mode SessionId is the signed access token.
+// Pending credentials are returned by Login when the user has to pass the second factor:
+// SessionId is then the challenge token accepted only by VerifyLogin and EnrollLogin,
+// EnrollRequired means that the user has to enroll two-factor authentication first.
+// RecoveryCodes are returned once by VerifyLogin when the login enrolled two-factor authentication.
+// Persistent credentials are kept by the client until ExpiresIn, others only until the browser is closed.
+type Credentials struct {
+	SessionId      string
+	RefreshToken   string
+	ExpiresIn      time.Duration
+	Persistent     bool
+	Pending        bool
+	EnrollRequired bool
+	RecoveryCodes  []string
+}
+
+// Enrollment
+// Not confirmed totp secret of the user and its provisioning URI for authenticator applications
+type Enrollment struct {
+	Secret string
+	URI    string
+}
+
+// ClientInfo
+// Client metadata saved with the session. RememberMe asks for the longer lifetime
+// of remembered sessions, it is ignored in jwt mode.
+type ClientInfo struct {
+	IP         string
+	UserAgent  string
+	RememberMe bool
+}
+
+// LockoutError
+// Returned by Login when the login or the client address is temporarily locked.
+// Matches ErrorTooManyAttempts with errors.Is.
+type LockoutError struct {
+	RetryAfter time.Duration
+}
+
+func (le *LockoutError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrorTooManyAttempts, le.RetryAfter)
+}
+
+func (le *LockoutError) Unwrap() error {
+	return ErrorTooManyAttempts
+}
+
+// PasswordHasher
+// Hashes passwords of users and verifies them
+type PasswordHasher interface {
+	// Hash
+	// Returns the hash of the password with the current parameters
+	Hash(password string) (string, error)
+
+	// Verify
+	// Checks the password against the hash and reports whether the hash is outdated
+	// and must be replaced by Hash of the same password.
+	// Returns Error:
+	//   - ErrorIncorrectPassword
+	//   - ErrorUnknownPasswordHash
+	Verify(hash, password string) (bool, error)
+}
+
 //go:generate mockgen -destination=mocks/manager.go -package=mu -mock_names=Manager=SessionManager . Manager
 
 type Manager interface {
-	Login(login, password string) (string, error)
-	Logout(sessionId string) error
+	// Login
+	// Returns pending credentials if the user has to pass the second factor.
+	// Returns Error:
+	//   - ErrorIncorrectPassword
+	//   - ErrorAccountNotActive
+	//   - LockoutError
+	Login(login, password string, client ClientInfo) (*Credentials, error)
+	// VerifyLogin
+	// Completes the pending login with the totp or the recovery code.
+	// The login that enrolls two-factor authentication accepts only the totp code.
+	// Returns Error:
+	//   - session.ErrorNoSession
+	//   - ErrorIncorrectCode
+	//   - ErrorTooManyAttempts
+	//   - twofactor.ErrorSecretNotFound
+	VerifyLogin(challengeToken, code string, client ClientInfo) (*Credentials, error)
+	// EnrollLogin
+	// Creates the totp secret for the pending login that requires enrollment.
+	// Returns Error:
+	//   - session.ErrorNoSession
+	//   - twofactor.ErrorAlreadyEnabled
+	EnrollLogin(challengeToken string) (*Enrollment, error)
+	// EnrollTwoFactor
+	// Creates the totp secret of the user, it is enabled by ConfirmTwoFactor.
+	// Returns Error:
+	//   - ErrorTwoFactorDisabled
+	//   - twofactor.ErrorAlreadyEnabled
+	EnrollTwoFactor(userId types.Id) (*Enrollment, error)
+	// ConfirmTwoFactor
+	// Enables two-factor authentication with the totp code and returns new recovery codes.
+	// Returns Error:
+	//   - ErrorTwoFactorDisabled
+	//   - ErrorIncorrectCode
+	//   - LockoutError
+	//   - twofactor.ErrorSecretNotFound
+	//   - twofactor.ErrorAlreadyEnabled
+	ConfirmTwoFactor(userId types.Id, code string) ([]string, error)
+	// DisableTwoFactor
+	// Disables two-factor authentication with the totp or the recovery code.
+	// Returns Error:
+	//   - ErrorTwoFactorDisabled
+	//   - ErrorTwoFactorRequired
+	//   - ErrorIncorrectCode
+	//   - LockoutError
+	//   - twofactor.ErrorSecretNotFound
+	DisableTwoFactor(userId types.Id, code string) error
+	// LoginUser
+	// Issues credentials for the user without any checks, the caller is responsible for authentication
+	LoginUser(userId types.Id, client ClientInfo) (*Credentials, error)
+	// LoginExternal
+	// Issues credentials for the user already authenticated by an external identity provider.
+	// Returns pending credentials if the user has to pass the second factor.
+	LoginExternal(userId types.Id, client ClientInfo) (*Credentials, error)
+	Refresh(refreshToken string, client ClientInfo) (*Credentials, error)
+	Logout(sessionId string, client ClientInfo) error
 	GetUserId(sessionId string) (*user.User, error)
+	// ChangePassword
+	// Sets the new password after the check of the current one and revokes other sessions of the user.
+	// Returns Error:
+	//   - ErrorIncorrectPassword
+	//   - ErrorPasswordPolicy
+	ChangePassword(userId types.Id, sessionId, currentPassword, newPassword string, client ClientInfo) error
+	// VerifyPassword
+	// Checks the current password of the user before changes of the account
+	// that don't require a new password. Users without a password never pass the check.
+	// Returns Error:
+	//   - ErrorIncorrectPassword
+	VerifyPassword(userId types.Id, password string) error
+	// ResetPassword
+	// Sets the new password without the current one, client is the client
+	// that requested the reset and is saved in the security event.
+	// Returns Error:
+	//   - ErrorPasswordPolicy
+	ResetPassword(userId types.Id, newPassword string, client ClientInfo) error
+	UnlockUser(userId types.Id) error
+	GetSessions(userId types.Id, currentSessionId string) ([]session.Session, error)
+	RevokeSession(userId types.Id, publicId string) error
+	RevokeUserSessions(userId types.Id) error
+	// InvalidateUser
+	// Drops cached data of the user, must be called after changes of the user
+	// that don't revoke its sessions
+	InvalidateUser(userId types.Id)
+	// InvalidateAll
+	// Drops cached data of all users, must be called after changes of roles
+	// and their permissions
+	InvalidateAll()
+	CreateToken(tkn *token.Token) (string, *token.Token, error)
+	GetTokens(userId types.Id) ([]token.Token, error)
+	RevokeToken(userId, tokenId types.Id) error
+	GetUserByToken(secret string) (*user.User, *token.Token, error)
+	// GetEvents
+	// Returns security events matched by the filter ordered from newest to oldest.
+	// Returns no events if the security event log is disabled.
+	GetEvents(filter event.Filter) ([]event.Event, error)
 }
@@ -0,0 +1,337 @@
+package auth
+
+import (
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"strconv"
+	"time"
+	"vk_film/internal/pkg/jwt"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/attempts"
+	"vk_film/internal/repository/event"
+	"vk_film/internal/repository/refresh"
+	"vk_film/internal/repository/session"
+	"vk_film/internal/repository/token"
+	"vk_film/internal/repository/twofactor"
+	"vk_film/internal/repository/user"
+	"vk_film/pkg/logger"
+)
+
+const RefreshTokenPrefix = "vkr_"
+
+// JWTPolicy
+// Access tokens are stateless and can't be revoked before AccessTTL passes,
+// so AccessTTL should be short. Every refresh rotates the refresh token,
+// reuse of an already rotated token revokes the whole session.
+type JWTPolicy struct {
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+var DefaultJWTPolicy = JWTPolicy{
+	AccessTTL:  15 * time.Minute,
+	RefreshTTL: 30 * 24 * time.Hour,
+}
+
+// JWTManager
+// Manager that issues signed access tokens instead of sessions stored in Redis.
+// The session id passed to Logout, GetUserId and ChangePassword is the access token,
+// the public identifier of the session is the session id from its claims.
+type JWTManager struct {
+	*SessionManager
+	refresh refresh.Repository
+	keys    *jwt.KeySet
+	policy  JWTPolicy
+}
+
+func NewJWTManager(users user.Repository, attempts attempts.Repository, tokens token.Repository,
+	twoFactor twofactor.Repository, refresh refresh.Repository, keys *jwt.KeySet, policy JWTPolicy,
+	protection LoginProtection, twoFactorPolicy TwoFactorPolicy, passwords PasswordHasher,
+	passwordPolicy PasswordPolicy, events event.Repository, l logger.Interface) *JWTManager {
+	sessionManager := NewSessionManager(users, nil, attempts, tokens, twoFactor, protection, twoFactorPolicy,
+		SessionPolicy{}, nil, passwords, passwordPolicy, events, l)
+
+	return &JWTManager{
+		SessionManager: sessionManager,
+		refresh:        refresh,
+		keys:           keys,
+		policy:         policy,
+	}
+}
+
+func (jm *JWTManager) Login(login, password string, client ClientInfo) (*Credentials, error) {
+	usr, err := jm.authenticate(login, password, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// Токены выдаются только после проверки второго фактора
+	credentials, err := jm.challenge(usr.ID, client)
+	if err != nil || credentials != nil {
+		return credentials, err
+	}
+
+	credentials, err = jm.LoginUser(usr.ID, client)
+	if err != nil {
+		return nil, errors.Wrapf(err, "try login user %s", login)
+	}
+
+	return credentials, nil
+}
+
+func (jm *JWTManager) LoginExternal(userId types.Id, client ClientInfo) (*Credentials, error) {
+	credentials, err := jm.challenge(userId, client)
+	if err != nil || credentials != nil {
+		return credentials, err
+	}
+
+	return jm.LoginUser(userId, client)
+}
+
+func (jm *JWTManager) LoginUser(userId types.Id, client ClientInfo) (*Credentials, error) {
+	credentials, err := jm.issue(userId, uuid.New().String(), client)
+	if err != nil {
+		return nil, errors.Wrapf(err, "try issue tokens for user %d", userId)
+	}
+
+	jm.recordEvent(userId, event.LoginSucceeded, client)
+
+	return credentials, nil
+}
+
+func (jm *JWTManager) VerifyLogin(challengeToken, code string, client ClientInfo) (*Credentials, error) {
+	userId, recoveryCodes, err := jm.verifyChallenge(challengeToken, code)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := jm.LoginUser(userId, client)
+	if err != nil {
+		return nil, err
+	}
+	credentials.RecoveryCodes = recoveryCodes
+
+	return credentials, nil
+}
+
+func (jm *JWTManager) Refresh(refreshToken string, client ClientInfo) (*Credentials, error) {
+	tkn, err := jm.refresh.GetToken(hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, refresh.ErrorTokenNotFound) {
+			return nil, session.ErrorNoSession
+		}
+		return nil, errors.Wrap(err, "try get refresh token")
+	}
+
+	// Повторное использование токена означает его утечку, поэтому сессия завершается целиком
+	if tkn.UsedAt != nil {
+		return nil, jm.revokeReused(tkn)
+	}
+
+	if err := jm.refresh.UseToken(tkn.ID); err != nil {
+		if errors.Is(err, refresh.ErrorTokenAlreadyUsed) {
+			return nil, jm.revokeReused(tkn)
+		}
+		return nil, errors.Wrapf(err, "try use refresh token of session %s", tkn.SessionId)
+	}
+
+	if _, err := jm.users.GetUserById(tkn.UserID); err != nil {
+		if errors.Is(err, user.ErrorUserNotFound) {
+			return nil, session.ErrorNoSession
+		}
+		return nil, errors.Wrapf(err, "try get user by id %d in session %s", tkn.UserID, tkn.SessionId)
+	}
+
+	credentials, err := jm.issue(tkn.UserID, tkn.SessionId, client)
+	if err != nil {
+		return nil, errors.Wrapf(err, "try issue tokens for session %s", tkn.SessionId)
+	}
+
+	return credentials, nil
+}
+
+func (jm *JWTManager) Logout(accessToken string, client ClientInfo) error {
+	claims, err := jm.parse(accessToken)
+	if err != nil {
+		return err
+	}
+
+	if err := jm.refresh.DelSession(claims.SessionId); err != nil {
+		return errors.Wrapf(err, "try delete session %s", claims.SessionId)
+	}
+
+	if userId, err := strconv.ParseUint(claims.Subject, 10, 64); err == nil {
+		jm.recordEvent(types.Id(userId), event.Logout, client)
+	}
+
+	return nil
+}
+
+func (jm *JWTManager) GetUserId(accessToken string) (*user.User, error) {
+	claims, err := jm.parse(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	userId, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		return nil, errors.Wrapf(session.ErrorNoSession, "incorrect subject %s", claims.Subject)
+	}
+
+	usr, err := jm.users.GetUserById(types.Id(userId))
+	if err != nil {
+		if errors.Is(err, user.ErrorUserNotFound) {
+			return nil, session.ErrorNoSession
+		}
+		return nil, errors.Wrapf(err, "try get user by id %d in session %s", userId, claims.SessionId)
+	}
+
+	return usr, nil
+}
+
+func (jm *JWTManager) ChangePassword(userId types.Id, accessToken, currentPassword, newPassword string,
+	client ClientInfo) error {
+	claims, err := jm.parse(accessToken)
+	if err != nil {
+		return err
+	}
+
+	if err := jm.checkPassword(userId, currentPassword); err != nil {
+		return err
+	}
+
+	if err := jm.updatePassword(userId, newPassword); err != nil {
+		return err
+	}
+
+	// Текущая сессия остаётся, остальные сессии пользователя завершаются
+	if err := jm.refresh.DelUserSessions(userId, claims.SessionId); err != nil {
+		return errors.Wrapf(err, "try delete other sessions of user %d", userId)
+	}
+
+	jm.recordEvent(userId, event.PasswordChanged, client)
+
+	return nil
+}
+
+func (jm *JWTManager) GetSessions(userId types.Id, accessToken string) ([]session.Session, error) {
+	refreshSessions, err := jm.refresh.GetUserSessions(userId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "try get sessions of user %d", userId)
+	}
+
+	// Текущая сессия определяется по access токену запроса, если он передан
+	currentId := ""
+	if accessToken != "" {
+		if claims, err := jm.parse(accessToken); err == nil {
+			currentId = claims.SessionId
+		}
+	}
+
+	sessions := make([]session.Session, 0, len(refreshSessions))
+	for _, rs := range refreshSessions {
+		sessions = append(sessions, session.Session{
+			ID:         rs.SessionId,
+			UserID:     rs.UserID,
+			CreatedAt:  rs.CreatedAt,
+			LastSeenAt: rs.LastSeenAt,
+			IP:         rs.IP,
+			UserAgent:  rs.UserAgent,
+			Current:    currentId != "" && rs.SessionId == currentId,
+		})
+	}
+
+	return sessions, nil
+}
+
+func (jm *JWTManager) RevokeSession(userId types.Id, publicId string) error {
+	if err := jm.refresh.DelUserSession(userId, publicId); err != nil {
+		if errors.Is(err, refresh.ErrorSessionNotFound) {
+			return errors.Wrapf(session.ErrorNoSession, "with id %s of user %d", publicId, userId)
+		}
+		return errors.Wrapf(err, "try delete session %s of user %d", publicId, userId)
+	}
+	return nil
+}
+
+func (jm *JWTManager) RevokeUserSessions(userId types.Id) error {
+	if err := jm.refresh.DelUserSessions(userId, ""); err != nil {
+		return errors.Wrapf(err, "try delete sessions of user %d", userId)
+	}
+	return nil
+}
+
+func (jm *JWTManager) ResetPassword(userId types.Id, newPassword string, client ClientInfo) error {
+	if err := jm.updatePassword(userId, newPassword); err != nil {
+		return err
+	}
+
+	// После сброса пароля завершаются все сессии пользователя
+	if err := jm.refresh.DelUserSessions(userId, ""); err != nil {
+		return errors.Wrapf(err, "try delete sessions of user %d", userId)
+	}
+
+	jm.recordEvent(userId, event.PasswordReset, client)
+
+	return nil
+}
+
+// issue
+// Выпускает новую пару из access и refresh токенов для сессии
+func (jm *JWTManager) issue(userId types.Id, sessionId string, client ClientInfo) (*Credentials, error) {
+	now := jm.now()
+
+	accessToken, err := jm.keys.Sign(&jwt.Claims{
+		Subject:   strconv.FormatUint(uint64(userId), 10),
+		SessionId: sessionId,
+		ID:        uuid.New().String(),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(jm.policy.AccessTTL).Unix(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "try sign access token")
+	}
+
+	refreshToken, err := generateToken(RefreshTokenPrefix)
+	if err != nil {
+		return nil, errors.Wrap(err, "try generate refresh token")
+	}
+
+	if err := jm.refresh.CreateToken(&refresh.Token{
+		SessionId: sessionId,
+		UserID:    userId,
+		ExpiresAt: now.Add(jm.policy.RefreshTTL),
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+	}, hashToken(refreshToken)); err != nil {
+		return nil, errors.Wrap(err, "try save refresh token")
+	}
+
+	return &Credentials{
+		SessionId:    accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    jm.policy.AccessTTL,
+		Persistent:   true,
+	}, nil
+}
+
+// parse
+// Проверяет access токен. Любой недействительный токен считается отсутствующей сессией
+func (jm *JWTManager) parse(accessToken string) (*jwt.Claims, error) {
+	claims, err := jm.keys.Parse(accessToken, jm.now())
+	if err != nil {
+		return nil, errors.Wrap(session.ErrorNoSession, err.Error())
+	}
+
+	return claims, nil
+}
+
+// revokeReused
+// Завершает сессию, refresh токен которой был использован повторно
+func (jm *JWTManager) revokeReused(tkn *refresh.Token) error {
+	if err := jm.refresh.DelSession(tkn.SessionId); err != nil {
+		return errors.Wrapf(err, "try delete session %s after refresh token reuse", tkn.SessionId)
+	}
+
+	return errors.Wrapf(ErrorRefreshTokenReused, "in session %s of user %d", tkn.SessionId, tkn.UserID)
+}
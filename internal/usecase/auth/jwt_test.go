@@ -0,0 +1,536 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+	"vk_film/internal/pkg/jwt"
+	"vk_film/internal/pkg/types"
+	mra "vk_film/internal/repository/attempts/mocks"
+	"vk_film/internal/repository/refresh"
+	mrr "vk_film/internal/repository/refresh/mocks"
+	"vk_film/internal/repository/session"
+	mrt "vk_film/internal/repository/token/mocks"
+	"vk_film/internal/repository/user"
+	mru "vk_film/internal/repository/user/mocks"
+)
+
+type JWTManagerSuite struct {
+	suite.Suite
+	jwtManager   *JWTManager
+	keys         *jwt.KeySet
+	mockUser     *mru.UserRepository
+	mockAttempts *mra.AttemptsRepository
+	mockToken    *mrt.TokenRepository
+	mockRefresh  *mrr.RefreshRepository
+	now          time.Time
+	gmc          *gomock.Controller
+}
+
+func newTestKeySet(t provider.T) *jwt.KeySet {
+	hsKey, err := jwt.NewHS256Key("hs", []byte(strings.Repeat("s", 32)))
+	t.Require().NoError(err)
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	t.Require().NoError(err)
+	edKey, err := jwt.NewEd25519Key("ed", private)
+	t.Require().NoError(err)
+
+	keys, err := jwt.NewKeySet("hs", hsKey, edKey)
+	t.Require().NoError(err)
+
+	return keys
+}
+
+func (jms *JWTManagerSuite) BeforeEach(t provider.T) {
+	jms.gmc = gomock.NewController(t)
+	jms.mockUser = mru.NewUserRepository(jms.gmc)
+	jms.mockAttempts = mra.NewAttemptsRepository(jms.gmc)
+	jms.mockToken = mrt.NewTokenRepository(jms.gmc)
+	jms.mockRefresh = mrr.NewRefreshRepository(jms.gmc)
+	jms.keys = newTestKeySet(t)
+	jms.now = time.Now()
+	jms.jwtManager = NewJWTManager(jms.mockUser, nil, jms.mockToken, nil, jms.mockRefresh, jms.keys,
+		DefaultJWTPolicy, DefaultLoginProtection, DefaultTwoFactorPolicy, NewTestPasswordHasher(), DefaultPasswordPolicy, nil, nil)
+	jms.jwtManager.now = func() time.Time { return jms.now }
+}
+
+func (jms *JWTManagerSuite) AfterEach(t provider.T) {
+	jms.gmc.Finish()
+}
+
+// issueTestToken
+// Выпускает access токен для тестов через мок репозитория refresh токенов
+func (jms *JWTManagerSuite) issueTestToken(t provider.StepCtx, userId types.Id, sessionId string) *Credentials {
+	jms.mockRefresh.EXPECT().CreateToken(gomock.Any(), gomock.Any()).Return(nil)
+	credentials, err := jms.jwtManager.issue(userId, sessionId, ClientInfo{})
+	t.Require().NoError(err)
+	return credentials
+}
+
+func (jms *JWTManagerSuite) TestLoginFunction(t provider.T) {
+	t.Title("Login function of jwt manager")
+	t.NewStep("Init test data")
+	login := "login"
+	password := "password"
+	encryptPassword, err := NewTestPasswordHasher().Hash(password)
+	t.Require().NoError(err)
+	userId := types.Id(1)
+	client := ClientInfo{IP: "127.0.0.1", UserAgent: "curl/8.0"}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		var sessionId, hash string
+		jms.mockUser.EXPECT().GetPasswordByLogin(login).
+			Return(&user.LoginUser{ID: userId, Password: encryptPassword, Status: user.StatusActive}, nil)
+		jms.mockRefresh.EXPECT().CreateToken(gomock.Any(), gomock.Any()).
+			Do(func(tkn *refresh.Token, h string) {
+				t.Require().Equal(userId, tkn.UserID)
+				t.Require().Equal(jms.now.Add(DefaultJWTPolicy.RefreshTTL), tkn.ExpiresAt)
+				t.Require().Equal(client.IP, tkn.IP)
+				t.Require().Equal(client.UserAgent, tkn.UserAgent)
+				sessionId, hash = tkn.SessionId, h
+			}).Return(nil)
+
+		t.NewStep("Check result")
+		credentials, err := jms.jwtManager.Login(login, password, client)
+		t.Require().NoError(err)
+		t.Require().Equal(DefaultJWTPolicy.AccessTTL, credentials.ExpiresIn)
+		t.Require().True(strings.HasPrefix(credentials.RefreshToken, RefreshTokenPrefix))
+		t.Require().Equal(hashToken(credentials.RefreshToken), hash)
+
+		claims, err := jms.keys.Parse(credentials.SessionId, jms.now)
+		t.Require().NoError(err)
+		t.Require().Equal("1", claims.Subject)
+		t.Require().Equal(sessionId, claims.SessionId)
+		t.Require().Equal(jms.now.Add(DefaultJWTPolicy.AccessTTL).Unix(), claims.ExpiresAt)
+	})
+
+	t.WithNewStep("Incorrect password execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		jms.mockUser.EXPECT().GetPasswordByLogin(login).
+			Return(&user.LoginUser{ID: userId, Password: encryptPassword, Status: user.StatusActive}, nil)
+
+		t.NewStep("Check result")
+		_, err := jms.jwtManager.Login(login, login, client)
+		t.Require().ErrorIs(err, ErrorIncorrectPassword)
+	})
+
+	t.WithNewStep("Refresh repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		jms.mockUser.EXPECT().GetPasswordByLogin(login).
+			Return(&user.LoginUser{ID: userId, Password: encryptPassword, Status: user.StatusActive}, nil)
+		jms.mockRefresh.EXPECT().CreateToken(gomock.Any(), gomock.Any()).Return(testError)
+
+		t.NewStep("Check result")
+		_, err := jms.jwtManager.Login(login, password, client)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (jms *JWTManagerSuite) TestLoginUserFunction(t provider.T) {
+	t.Title("LoginUser function of jwt manager")
+	t.NewStep("Init test data")
+	userId := types.Id(1)
+	client := ClientInfo{IP: "127.0.0.1", UserAgent: "curl/8.0"}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		var sessionId string
+		jms.mockRefresh.EXPECT().CreateToken(gomock.Any(), gomock.Any()).
+			Do(func(tkn *refresh.Token, _ string) {
+				t.Require().Equal(userId, tkn.UserID)
+				sessionId = tkn.SessionId
+			}).Return(nil)
+
+		t.NewStep("Check result")
+		credentials, err := jms.jwtManager.LoginUser(userId, client)
+		t.Require().NoError(err)
+		t.Require().True(strings.HasPrefix(credentials.RefreshToken, RefreshTokenPrefix))
+
+		claims, err := jms.keys.Parse(credentials.SessionId, jms.now)
+		t.Require().NoError(err)
+		t.Require().Equal("1", claims.Subject)
+		t.Require().Equal(sessionId, claims.SessionId)
+	})
+
+	t.WithNewStep("Refresh repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		jms.mockRefresh.EXPECT().CreateToken(gomock.Any(), gomock.Any()).Return(testError)
+
+		t.NewStep("Check result")
+		_, err := jms.jwtManager.LoginUser(userId, client)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (jms *JWTManagerSuite) TestGetUserIdFunction(t provider.T) {
+	t.Title("GetUserId function of jwt manager")
+	t.NewStep("Init test data")
+	userId := types.Id(1)
+	usr := &user.User{ID: userId, Login: "login", Role: types.USER}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		credentials := jms.issueTestToken(t, userId, "session")
+		jms.mockUser.EXPECT().GetUserById(userId).Return(usr, nil)
+
+		t.NewStep("Check result")
+		res, err := jms.jwtManager.GetUserId(credentials.SessionId)
+		t.Require().NoError(err)
+		t.Require().Equal(usr, res)
+	})
+
+	t.WithNewStep("Token signed by other key execute", func(t provider.StepCtx) {
+		t.NewStep("Init data")
+		key, err := jwt.NewHS256Key("ed", []byte(strings.Repeat("o", 32)))
+		t.Require().NoError(err)
+		otherKeys, err := jwt.NewKeySet("ed", key)
+		t.Require().NoError(err)
+		accessToken, err := otherKeys.Sign(&jwt.Claims{Subject: "1", ExpiresAt: jms.now.Add(time.Hour).Unix()})
+		t.Require().NoError(err)
+
+		t.NewStep("Check result")
+		_, err = jms.jwtManager.GetUserId(accessToken)
+		t.Require().ErrorIs(err, session.ErrorNoSession)
+	})
+
+	t.WithNewStep("Expired token execute", func(t provider.StepCtx) {
+		t.NewStep("Init data")
+		credentials := jms.issueTestToken(t, userId, "session")
+		jms.jwtManager.now = func() time.Time { return jms.now.Add(DefaultJWTPolicy.AccessTTL) }
+		defer func() { jms.jwtManager.now = func() time.Time { return jms.now } }()
+
+		t.NewStep("Check result")
+		_, err := jms.jwtManager.GetUserId(credentials.SessionId)
+		t.Require().ErrorIs(err, session.ErrorNoSession)
+	})
+
+	t.WithNewStep("Malformed token execute", func(t provider.StepCtx) {
+		t.NewStep("Check result")
+		_, err := jms.jwtManager.GetUserId("token")
+		t.Require().ErrorIs(err, session.ErrorNoSession)
+	})
+
+	t.WithNewStep("User not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		credentials := jms.issueTestToken(t, userId, "session")
+		jms.mockUser.EXPECT().GetUserById(userId).Return(nil, user.ErrorUserNotFound)
+
+		t.NewStep("Check result")
+		_, err := jms.jwtManager.GetUserId(credentials.SessionId)
+		t.Require().ErrorIs(err, session.ErrorNoSession)
+	})
+
+	t.WithNewStep("User repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		credentials := jms.issueTestToken(t, userId, "session")
+		jms.mockUser.EXPECT().GetUserById(userId).Return(nil, testError)
+
+		t.NewStep("Check result")
+		_, err := jms.jwtManager.GetUserId(credentials.SessionId)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (jms *JWTManagerSuite) TestRefreshFunction(t provider.T) {
+	t.Title("Refresh function of jwt manager")
+	t.NewStep("Init test data")
+	refreshToken := RefreshTokenPrefix + "secret"
+	usedAt := time.Now()
+	tkn := &refresh.Token{ID: 1, SessionId: "session", UserID: 2}
+	usedToken := &refresh.Token{ID: 1, SessionId: "session", UserID: 2, UsedAt: &usedAt}
+	client := ClientInfo{IP: "10.0.0.1", UserAgent: "curl/8.0"}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		jms.mockRefresh.EXPECT().GetToken(hashToken(refreshToken)).Return(tkn, nil)
+		jms.mockRefresh.EXPECT().UseToken(tkn.ID).Return(nil)
+		jms.mockUser.EXPECT().GetUserById(tkn.UserID).Return(&user.User{ID: tkn.UserID}, nil)
+		jms.mockRefresh.EXPECT().CreateToken(gomock.Any(), gomock.Any()).
+			Do(func(newToken *refresh.Token, _ string) {
+				t.Require().Equal(tkn.SessionId, newToken.SessionId)
+				t.Require().Equal(tkn.UserID, newToken.UserID)
+				t.Require().Equal(client.IP, newToken.IP)
+			}).Return(nil)
+
+		t.NewStep("Check result")
+		credentials, err := jms.jwtManager.Refresh(refreshToken, client)
+		t.Require().NoError(err)
+		t.Require().NotEqual(refreshToken, credentials.RefreshToken)
+
+		claims, err := jms.keys.Parse(credentials.SessionId, jms.now)
+		t.Require().NoError(err)
+		t.Require().Equal(tkn.SessionId, claims.SessionId)
+	})
+
+	t.WithNewStep("Token not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		jms.mockRefresh.EXPECT().GetToken(hashToken(refreshToken)).Return(nil, refresh.ErrorTokenNotFound)
+
+		t.NewStep("Check result")
+		_, err := jms.jwtManager.Refresh(refreshToken, client)
+		t.Require().ErrorIs(err, session.ErrorNoSession)
+	})
+
+	t.WithNewStep("Reused token execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		jms.mockRefresh.EXPECT().GetToken(hashToken(refreshToken)).Return(usedToken, nil)
+		jms.mockRefresh.EXPECT().DelSession(usedToken.SessionId).Return(nil)
+
+		t.NewStep("Check result")
+		_, err := jms.jwtManager.Refresh(refreshToken, client)
+		t.Require().ErrorIs(err, ErrorRefreshTokenReused)
+	})
+
+	t.WithNewStep("Concurrently used token execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		jms.mockRefresh.EXPECT().GetToken(hashToken(refreshToken)).Return(tkn, nil)
+		jms.mockRefresh.EXPECT().UseToken(tkn.ID).Return(refresh.ErrorTokenAlreadyUsed)
+		jms.mockRefresh.EXPECT().DelSession(tkn.SessionId).Return(nil)
+
+		t.NewStep("Check result")
+		_, err := jms.jwtManager.Refresh(refreshToken, client)
+		t.Require().ErrorIs(err, ErrorRefreshTokenReused)
+	})
+
+	t.WithNewStep("User not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		jms.mockRefresh.EXPECT().GetToken(hashToken(refreshToken)).Return(tkn, nil)
+		jms.mockRefresh.EXPECT().UseToken(tkn.ID).Return(nil)
+		jms.mockUser.EXPECT().GetUserById(tkn.UserID).Return(nil, user.ErrorUserNotFound)
+
+		t.NewStep("Check result")
+		_, err := jms.jwtManager.Refresh(refreshToken, client)
+		t.Require().ErrorIs(err, session.ErrorNoSession)
+	})
+
+	t.WithNewStep("Refresh repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		jms.mockRefresh.EXPECT().GetToken(hashToken(refreshToken)).Return(nil, testError)
+
+		t.NewStep("Check result")
+		_, err := jms.jwtManager.Refresh(refreshToken, client)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (jms *JWTManagerSuite) TestLogoutFunction(t provider.T) {
+	t.Title("Logout function of jwt manager")
+	t.NewStep("Init test data")
+	userId := types.Id(1)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		credentials := jms.issueTestToken(t, userId, "session")
+		jms.mockRefresh.EXPECT().DelSession("session").Return(nil)
+
+		t.NewStep("Check result")
+		t.Require().NoError(jms.jwtManager.Logout(credentials.SessionId, ClientInfo{}))
+	})
+
+	t.WithNewStep("Invalid token execute", func(t provider.StepCtx) {
+		t.NewStep("Check result")
+		t.Require().ErrorIs(jms.jwtManager.Logout("token", ClientInfo{}), session.ErrorNoSession)
+	})
+
+	t.WithNewStep("Refresh repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		credentials := jms.issueTestToken(t, userId, "session")
+		jms.mockRefresh.EXPECT().DelSession("session").Return(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(jms.jwtManager.Logout(credentials.SessionId, ClientInfo{}), testError)
+	})
+}
+
+func (jms *JWTManagerSuite) TestChangePasswordFunction(t provider.T) {
+	t.Title("ChangePassword function of jwt manager")
+	t.NewStep("Init test data")
+	password := "password"
+	newPassword := "new password"
+	encryptPassword, err := NewTestPasswordHasher().Hash(password)
+	t.Require().NoError(err)
+	userId := types.Id(1)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		credentials := jms.issueTestToken(t, userId, "session")
+		jms.mockUser.EXPECT().GetPasswordById(userId).
+			Return(&user.LoginUser{ID: userId, Password: encryptPassword, Status: user.StatusActive}, nil)
+		jms.mockUser.EXPECT().UpdateUserPassword(gomock.Any()).Return(nil)
+		jms.mockRefresh.EXPECT().DelUserSessions(userId, "session").Return(nil)
+
+		t.NewStep("Check result")
+		t.Require().NoError(jms.jwtManager.ChangePassword(userId, credentials.SessionId, password, newPassword, ClientInfo{}))
+	})
+
+	t.WithNewStep("Incorrect password execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		credentials := jms.issueTestToken(t, userId, "session")
+		jms.mockUser.EXPECT().GetPasswordById(userId).
+			Return(&user.LoginUser{ID: userId, Password: encryptPassword, Status: user.StatusActive}, nil)
+
+		t.NewStep("Check result")
+		err := jms.jwtManager.ChangePassword(userId, credentials.SessionId, newPassword, newPassword, ClientInfo{})
+		t.Require().ErrorIs(err, ErrorIncorrectPassword)
+	})
+}
+
+func (jms *JWTManagerSuite) TestResetPasswordFunction(t provider.T) {
+	t.Title("ResetPassword function of jwt manager")
+	t.NewStep("Init test data")
+	userId := types.Id(1)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		jms.mockUser.EXPECT().UpdateUserPassword(gomock.Any()).Return(nil)
+		jms.mockRefresh.EXPECT().DelUserSessions(userId, "").Return(nil)
+
+		t.NewStep("Check result")
+		t.Require().NoError(jms.jwtManager.ResetPassword(userId, "new password", ClientInfo{}))
+	})
+
+	t.WithNewStep("Refresh repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		jms.mockUser.EXPECT().UpdateUserPassword(gomock.Any()).Return(nil)
+		jms.mockRefresh.EXPECT().DelUserSessions(userId, "").Return(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(jms.jwtManager.ResetPassword(userId, "new password", ClientInfo{}), testError)
+	})
+}
+
+func (jms *JWTManagerSuite) TestGetSessionsFunction(t provider.T) {
+	t.Title("GetSessions function of jwt manager")
+	t.NewStep("Init test data")
+	userId := types.Id(1)
+	refreshSessions := []refresh.Session{
+		{SessionId: "other", UserID: userId, IP: "10.0.0.1", UserAgent: "curl/8.0"},
+		{SessionId: "session", UserID: userId, IP: "127.0.0.1", UserAgent: "Mozilla/5.0"},
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		credentials := jms.issueTestToken(t, userId, "session")
+		jms.mockRefresh.EXPECT().GetUserSessions(userId).Return(refreshSessions, nil)
+
+		t.NewStep("Check result")
+		sessions, err := jms.jwtManager.GetSessions(userId, credentials.SessionId)
+		t.Require().NoError(err)
+		t.Require().Len(sessions, 2)
+		t.Require().Equal("other", sessions[0].ID)
+		t.Require().Equal(refreshSessions[0].IP, sessions[0].IP)
+		t.Require().False(sessions[0].Current)
+		t.Require().True(sessions[1].Current)
+	})
+
+	t.WithNewStep("Correct execute without access token", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		jms.mockRefresh.EXPECT().GetUserSessions(userId).Return(refreshSessions, nil)
+
+		t.NewStep("Check result")
+		sessions, err := jms.jwtManager.GetSessions(userId, "")
+		t.Require().NoError(err)
+		t.Require().False(sessions[0].Current)
+		t.Require().False(sessions[1].Current)
+	})
+
+	t.WithNewStep("Refresh repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		jms.mockRefresh.EXPECT().GetUserSessions(userId).Return(nil, testError)
+
+		t.NewStep("Check result")
+		_, err := jms.jwtManager.GetSessions(userId, "")
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (jms *JWTManagerSuite) TestRevokeSessionFunction(t provider.T) {
+	t.Title("RevokeSession function of jwt manager")
+	t.NewStep("Init test data")
+	userId := types.Id(1)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		jms.mockRefresh.EXPECT().DelUserSession(userId, "session").Return(nil)
+
+		t.NewStep("Check result")
+		t.Require().NoError(jms.jwtManager.RevokeSession(userId, "session"))
+	})
+
+	t.WithNewStep("Session not found in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		jms.mockRefresh.EXPECT().DelUserSession(userId, "session").Return(refresh.ErrorSessionNotFound)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(jms.jwtManager.RevokeSession(userId, "session"), session.ErrorNoSession)
+	})
+
+	t.WithNewStep("Refresh repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		jms.mockRefresh.EXPECT().DelUserSession(userId, "session").Return(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(jms.jwtManager.RevokeSession(userId, "session"), testError)
+	})
+}
+
+func (jms *JWTManagerSuite) TestRevokeUserSessionsFunction(t provider.T) {
+	t.Title("RevokeUserSessions function of jwt manager")
+	t.NewStep("Init test data")
+	userId := types.Id(1)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		jms.mockRefresh.EXPECT().DelUserSessions(userId, "").Return(nil)
+
+		t.NewStep("Check result")
+		t.Require().NoError(jms.jwtManager.RevokeUserSessions(userId))
+	})
+
+	t.WithNewStep("Refresh repository error in execution", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		jms.mockRefresh.EXPECT().DelUserSessions(userId, "").Return(testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(jms.jwtManager.RevokeUserSessions(userId), testError)
+	})
+}
+
+func (jms *JWTManagerSuite) TestKeyRotation(t provider.T) {
+	t.Title("Key rotation of jwt manager")
+	t.NewStep("Init test data")
+	userId := types.Id(1)
+
+	t.WithNewStep("Token of previous signing key is accepted", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		credentials := jms.issueTestToken(t, userId, "session")
+
+		t.NewStep("Rotate keys")
+		hsKey, err := jwt.NewHS256Key("hs", []byte(strings.Repeat("s", 32)))
+		t.Require().NoError(err)
+		newKey, err := jwt.NewHS256Key("new", []byte(strings.Repeat("n", 32)))
+		t.Require().NoError(err)
+		jms.jwtManager.keys, err = jwt.NewKeySet("new", hsKey, newKey)
+		t.Require().NoError(err)
+		jms.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId}, nil).Times(2)
+
+		t.NewStep("Check result")
+		_, err = jms.jwtManager.GetUserId(credentials.SessionId)
+		t.Require().NoError(err)
+
+		newCredentials := jms.issueTestToken(t, userId, "session")
+		_, err = jms.jwtManager.GetUserId(newCredentials.SessionId)
+		t.Require().NoError(err)
+	})
+}
+
+func TestRunJWTManagerSuite(t *testing.T) {
+	suite.RunSuite(t, new(JWTManagerSuite))
+}
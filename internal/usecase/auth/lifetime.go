@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"github.com/pkg/errors"
+	"time"
+	"vk_film/internal/pkg/types"
+)
+
+// SessionLifetime
+// The session ends after IdleTimeout without requests or after MaxLifetime since login,
+// whichever comes first. Requests extend the session only up to MaxLifetime.
+type SessionLifetime struct {
+	IdleTimeout time.Duration
+	MaxLifetime time.Duration
+}
+
+// enabled
+// Нулевое время жизни означает, что политика не задана
+func (sl SessionLifetime) enabled() bool {
+	return sl.IdleTimeout > 0 && sl.MaxLifetime > 0
+}
+
+// SessionPolicy
+// Lifetimes of sessions. Remember is used when the client asks to remember the login,
+// its cookie is kept by the browser after restart. Admin is used for users of the admin role
+// regardless of remember me. Disabled Remember or Admin lifetime falls back to Default.
+type SessionPolicy struct {
+	Default  SessionLifetime
+	Remember SessionLifetime
+	Admin    SessionLifetime
+}
+
+var DefaultSessionPolicy = SessionPolicy{
+	Default:  SessionLifetime{IdleTimeout: 48 * time.Hour, MaxLifetime: 7 * 24 * time.Hour},
+	Remember: SessionLifetime{IdleTimeout: 30 * 24 * time.Hour, MaxLifetime: 90 * 24 * time.Hour},
+	Admin:    SessionLifetime{IdleTimeout: time.Hour, MaxLifetime: 12 * time.Hour},
+}
+
+// lifetime
+// Выбирает время жизни новой сессии пользователя и сообщает, запомнен ли вход
+func (sm *SessionManager) lifetime(userId types.Id, rememberMe bool) (SessionLifetime, bool, error) {
+	// Роль проверяется, только если для администраторов задана отдельная политика
+	if sm.sessionPolicy.Admin.enabled() {
+		usr, err := sm.users.GetUserById(userId)
+		if err != nil {
+			return SessionLifetime{}, false, errors.Wrapf(err, "try get user by id %d", userId)
+		}
+
+		if usr.Role == types.ADMIN {
+			return sm.sessionPolicy.Admin, false, nil
+		}
+	}
+
+	if rememberMe && sm.sessionPolicy.Remember.enabled() {
+		return sm.sessionPolicy.Remember, true, nil
+	}
+
+	return sm.sessionPolicy.Default, false, nil
+}
@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"github.com/pkg/errors"
+	"time"
+)
+
+const (
+	loginAttemptsPrefix = "login:"
+	ipAttemptsPrefix    = "ip:"
+)
+
+// LoginProtection
+// Failed login attempts are counted separately for the login and for the client address.
+// When the number of failures reaches the maximum, the key is locked for BaseLockTime,
+// and every next failure doubles the lock time up to MaxLockTime.
+// Counters are removed after AttemptsWindow passes without new failures.
+// Zero maximum disables the counter.
+type LoginProtection struct {
+	MaxLoginAttempts uint64
+	MaxIPAttempts    uint64
+	BaseLockTime     time.Duration
+	MaxLockTime      time.Duration
+	AttemptsWindow   time.Duration
+}
+
+var DefaultLoginProtection = LoginProtection{
+	MaxLoginAttempts: 5,
+	MaxIPAttempts:    20,
+	BaseLockTime:     time.Minute,
+	MaxLockTime:      time.Hour,
+	AttemptsWindow:   24 * time.Hour,
+}
+
+type attemptKey struct {
+	key         string
+	maxAttempts uint64
+}
+
+func loginAttemptsKey(login string) string {
+	return loginAttemptsPrefix + login
+}
+
+// resetAttempts
+// Сбрасывает счётчик неудачных попыток входа для логина, если защита включена
+func (sm *SessionManager) resetAttempts(login string) error {
+	if sm.attempts == nil {
+		return nil
+	}
+
+	return sm.attempts.Reset(loginAttemptsKey(login))
+}
+
+func (sm *SessionManager) attemptKeys(login, clientIP string) []attemptKey {
+	keys := make([]attemptKey, 0, 2)
+
+	if sm.protection.MaxLoginAttempts != 0 {
+		keys = append(keys, attemptKey{key: loginAttemptsKey(login), maxAttempts: sm.protection.MaxLoginAttempts})
+	}
+
+	if sm.protection.MaxIPAttempts != 0 && clientIP != "" {
+		keys = append(keys, attemptKey{key: ipAttemptsPrefix + clientIP, maxAttempts: sm.protection.MaxIPAttempts})
+	}
+
+	return keys
+}
+
+// lockTime
+// Вычисляет время блокировки, удваивая базовое время за каждую неудачную попытку сверх максимума
+func (sm *SessionManager) lockTime(extraAttempts uint64) time.Duration {
+	lock := sm.protection.BaseLockTime
+	for i := uint64(0); i < extraAttempts && lock < sm.protection.MaxLockTime; i++ {
+		lock *= 2
+	}
+
+	return min(lock, sm.protection.MaxLockTime)
+}
+
+// checkLock
+// Возвращает LockoutError, если хотя бы один из ключей заблокирован
+func (sm *SessionManager) checkLock(keys []attemptKey) error {
+	var retryAfter time.Duration
+
+	for _, key := range keys {
+		lock, err := sm.attempts.GetLockTime(key.key)
+		if err != nil {
+			return errors.Wrapf(err, "try get lock time for %s", key.key)
+		}
+
+		retryAfter = max(retryAfter, lock)
+	}
+
+	if retryAfter > 0 {
+		return &LockoutError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// registerFailure
+// Учитывает неудачную попытку входа. Возвращает LockoutError, если ключ был заблокирован,
+// иначе исходную ошибку cause
+func (sm *SessionManager) registerFailure(keys []attemptKey, cause error) error {
+	var retryAfter time.Duration
+
+	for _, key := range keys {
+		count, err := sm.attempts.Add(key.key, sm.protection.AttemptsWindow)
+		if err != nil {
+			return errors.Wrapf(err, "try add failed login attempt for %s", key.key)
+		}
+
+		if count < key.maxAttempts {
+			continue
+		}
+
+		lock := sm.lockTime(count - key.maxAttempts)
+		if err := sm.attempts.Lock(key.key, lock); err != nil {
+			return errors.Wrapf(err, "try lock %s", key.key)
+		}
+
+		retryAfter = max(retryAfter, lock)
+	}
+
+	if retryAfter > 0 {
+		return &LockoutError{RetryAfter: retryAfter}
+	}
+
+	return cause
+}
@@ -11,7 +11,12 @@ package mu
 
 import (
 	reflect "reflect"
+	types "vk_film/internal/pkg/types"
+	event "vk_film/internal/repository/event"
+	session "vk_film/internal/repository/session"
+	token "vk_film/internal/repository/token"
 	user "vk_film/internal/repository/user"
+	auth "vk_film/internal/usecase/auth"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -39,6 +44,156 @@ func (m *SessionManager) EXPECT() *SessionManagerMockRecorder {
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *SessionManager) ChangePassword(arg0 types.Id, arg1, arg2, arg3 string, arg4 auth.ClientInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *SessionManagerMockRecorder) ChangePassword(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*SessionManager)(nil).ChangePassword), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ConfirmTwoFactor mocks base method.
+func (m *SessionManager) ConfirmTwoFactor(arg0 types.Id, arg1 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTwoFactor", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTwoFactor indicates an expected call of ConfirmTwoFactor.
+func (mr *SessionManagerMockRecorder) ConfirmTwoFactor(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTwoFactor", reflect.TypeOf((*SessionManager)(nil).ConfirmTwoFactor), arg0, arg1)
+}
+
+// CreateToken mocks base method.
+func (m *SessionManager) CreateToken(arg0 *token.Token) (string, *token.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateToken", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(*token.Token)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateToken indicates an expected call of CreateToken.
+func (mr *SessionManagerMockRecorder) CreateToken(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToken", reflect.TypeOf((*SessionManager)(nil).CreateToken), arg0)
+}
+
+// DisableTwoFactor mocks base method.
+func (m *SessionManager) DisableTwoFactor(arg0 types.Id, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTwoFactor", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTwoFactor indicates an expected call of DisableTwoFactor.
+func (mr *SessionManagerMockRecorder) DisableTwoFactor(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTwoFactor", reflect.TypeOf((*SessionManager)(nil).DisableTwoFactor), arg0, arg1)
+}
+
+// EnrollLogin mocks base method.
+func (m *SessionManager) EnrollLogin(arg0 string) (*auth.Enrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollLogin", arg0)
+	ret0, _ := ret[0].(*auth.Enrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollLogin indicates an expected call of EnrollLogin.
+func (mr *SessionManagerMockRecorder) EnrollLogin(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollLogin", reflect.TypeOf((*SessionManager)(nil).EnrollLogin), arg0)
+}
+
+// EnrollTwoFactor mocks base method.
+func (m *SessionManager) EnrollTwoFactor(arg0 types.Id) (*auth.Enrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTwoFactor", arg0)
+	ret0, _ := ret[0].(*auth.Enrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollTwoFactor indicates an expected call of EnrollTwoFactor.
+func (mr *SessionManagerMockRecorder) EnrollTwoFactor(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTwoFactor", reflect.TypeOf((*SessionManager)(nil).EnrollTwoFactor), arg0)
+}
+
+// GetEvents mocks base method.
+func (m *SessionManager) GetEvents(arg0 event.Filter) ([]event.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvents", arg0)
+	ret0, _ := ret[0].([]event.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvents indicates an expected call of GetEvents.
+func (mr *SessionManagerMockRecorder) GetEvents(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*SessionManager)(nil).GetEvents), arg0)
+}
+
+// GetSessions mocks base method.
+func (m *SessionManager) GetSessions(arg0 types.Id, arg1 string) ([]session.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessions", arg0, arg1)
+	ret0, _ := ret[0].([]session.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessions indicates an expected call of GetSessions.
+func (mr *SessionManagerMockRecorder) GetSessions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessions", reflect.TypeOf((*SessionManager)(nil).GetSessions), arg0, arg1)
+}
+
+// GetTokens mocks base method.
+func (m *SessionManager) GetTokens(arg0 types.Id) ([]token.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokens", arg0)
+	ret0, _ := ret[0].([]token.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokens indicates an expected call of GetTokens.
+func (mr *SessionManagerMockRecorder) GetTokens(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokens", reflect.TypeOf((*SessionManager)(nil).GetTokens), arg0)
+}
+
+// GetUserByToken mocks base method.
+func (m *SessionManager) GetUserByToken(arg0 string) (*user.User, *token.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByToken", arg0)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(*token.Token)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUserByToken indicates an expected call of GetUserByToken.
+func (mr *SessionManagerMockRecorder) GetUserByToken(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByToken", reflect.TypeOf((*SessionManager)(nil).GetUserByToken), arg0)
+}
+
 // GetUserId mocks base method.
 func (m *SessionManager) GetUserId(arg0 string) (*user.User, error) {
 	m.ctrl.T.Helper()
@@ -54,31 +209,199 @@ func (mr *SessionManagerMockRecorder) GetUserId(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserId", reflect.TypeOf((*SessionManager)(nil).GetUserId), arg0)
 }
 
+// InvalidateAll mocks base method.
+func (m *SessionManager) InvalidateAll() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "InvalidateAll")
+}
+
+// InvalidateAll indicates an expected call of InvalidateAll.
+func (mr *SessionManagerMockRecorder) InvalidateAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateAll", reflect.TypeOf((*SessionManager)(nil).InvalidateAll))
+}
+
+// InvalidateUser mocks base method.
+func (m *SessionManager) InvalidateUser(arg0 types.Id) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "InvalidateUser", arg0)
+}
+
+// InvalidateUser indicates an expected call of InvalidateUser.
+func (mr *SessionManagerMockRecorder) InvalidateUser(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateUser", reflect.TypeOf((*SessionManager)(nil).InvalidateUser), arg0)
+}
+
 // Login mocks base method.
-func (m *SessionManager) Login(arg0, arg1 string) (string, error) {
+func (m *SessionManager) Login(arg0, arg1 string, arg2 auth.ClientInfo) (*auth.Credentials, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", arg0, arg1)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "Login", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*auth.Credentials)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Login indicates an expected call of Login.
-func (mr *SessionManagerMockRecorder) Login(arg0, arg1 any) *gomock.Call {
+func (mr *SessionManagerMockRecorder) Login(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*SessionManager)(nil).Login), arg0, arg1, arg2)
+}
+
+// LoginExternal mocks base method.
+func (m *SessionManager) LoginExternal(arg0 types.Id, arg1 auth.ClientInfo) (*auth.Credentials, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginExternal", arg0, arg1)
+	ret0, _ := ret[0].(*auth.Credentials)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginExternal indicates an expected call of LoginExternal.
+func (mr *SessionManagerMockRecorder) LoginExternal(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*SessionManager)(nil).Login), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginExternal", reflect.TypeOf((*SessionManager)(nil).LoginExternal), arg0, arg1)
+}
+
+// LoginUser mocks base method.
+func (m *SessionManager) LoginUser(arg0 types.Id, arg1 auth.ClientInfo) (*auth.Credentials, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginUser", arg0, arg1)
+	ret0, _ := ret[0].(*auth.Credentials)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginUser indicates an expected call of LoginUser.
+func (mr *SessionManagerMockRecorder) LoginUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginUser", reflect.TypeOf((*SessionManager)(nil).LoginUser), arg0, arg1)
 }
 
 // Logout mocks base method.
-func (m *SessionManager) Logout(arg0 string) error {
+func (m *SessionManager) Logout(arg0 string, arg1 auth.ClientInfo) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Logout", arg0)
+	ret := m.ctrl.Call(m, "Logout", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Logout indicates an expected call of Logout.
-func (mr *SessionManagerMockRecorder) Logout(arg0 any) *gomock.Call {
+func (mr *SessionManagerMockRecorder) Logout(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*SessionManager)(nil).Logout), arg0, arg1)
+}
+
+// Refresh mocks base method.
+func (m *SessionManager) Refresh(arg0 string, arg1 auth.ClientInfo) (*auth.Credentials, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", arg0, arg1)
+	ret0, _ := ret[0].(*auth.Credentials)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *SessionManagerMockRecorder) Refresh(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*SessionManager)(nil).Refresh), arg0, arg1)
+}
+
+// ResetPassword mocks base method.
+func (m *SessionManager) ResetPassword(arg0 types.Id, arg1 string, arg2 auth.ClientInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *SessionManagerMockRecorder) ResetPassword(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*SessionManager)(nil).ResetPassword), arg0, arg1, arg2)
+}
+
+// RevokeSession mocks base method.
+func (m *SessionManager) RevokeSession(arg0 types.Id, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *SessionManagerMockRecorder) RevokeSession(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*SessionManager)(nil).RevokeSession), arg0, arg1)
+}
+
+// RevokeToken mocks base method.
+func (m *SessionManager) RevokeToken(arg0, arg1 types.Id) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *SessionManagerMockRecorder) RevokeToken(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*SessionManager)(nil).RevokeToken), arg0, arg1)
+}
+
+// RevokeUserSessions mocks base method.
+func (m *SessionManager) RevokeUserSessions(arg0 types.Id) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserSessions", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserSessions indicates an expected call of RevokeUserSessions.
+func (mr *SessionManagerMockRecorder) RevokeUserSessions(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserSessions", reflect.TypeOf((*SessionManager)(nil).RevokeUserSessions), arg0)
+}
+
+// UnlockUser mocks base method.
+func (m *SessionManager) UnlockUser(arg0 types.Id) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockUser", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockUser indicates an expected call of UnlockUser.
+func (mr *SessionManagerMockRecorder) UnlockUser(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockUser", reflect.TypeOf((*SessionManager)(nil).UnlockUser), arg0)
+}
+
+// VerifyLogin mocks base method.
+func (m *SessionManager) VerifyLogin(arg0, arg1 string, arg2 auth.ClientInfo) (*auth.Credentials, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyLogin", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*auth.Credentials)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyLogin indicates an expected call of VerifyLogin.
+func (mr *SessionManagerMockRecorder) VerifyLogin(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyLogin", reflect.TypeOf((*SessionManager)(nil).VerifyLogin), arg0, arg1, arg2)
+}
+
+// VerifyPassword mocks base method.
+func (m *SessionManager) VerifyPassword(arg0 types.Id, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyPassword", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyPassword indicates an expected call of VerifyPassword.
+func (mr *SessionManagerMockRecorder) VerifyPassword(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*SessionManager)(nil).Logout), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyPassword", reflect.TypeOf((*SessionManager)(nil).VerifyPassword), arg0, arg1)
 }
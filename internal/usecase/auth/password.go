@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+)
+
+const argon2idPrefix = "$argon2id$"
+
+var phcEncoding = base64.RawStdEncoding
+
+// Argon2Params
+// Parameters of new argon2id hashes. Memory is set in KiB.
+// Hashes with other parameters are still verified and are re-hashed on login.
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params
+// Second recommended option of RFC 9106 for memory-constrained environments
+var DefaultArgon2Params = Argon2Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 4,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// Argon2Hasher
+// Hashes passwords with argon2id in the PHC string format
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<hash>.
+// Legacy bcrypt hashes are verified and reported as outdated.
+type Argon2Hasher struct {
+	params Argon2Params
+}
+
+func NewArgon2Hasher(params Argon2Params) *Argon2Hasher {
+	return &Argon2Hasher{params: params}
+}
+
+var _ = PasswordHasher(&Argon2Hasher{})
+
+// NewTestPasswordHasher
+// Returns the hasher with the minimal cost, so tests don't spend time on argon2id.
+// It must not be used for passwords of real users
+func NewTestPasswordHasher() *Argon2Hasher {
+	return NewArgon2Hasher(Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+}
+
+func (ah *Argon2Hasher) Hash(password string) (string, error) {
+	salt := make([]byte, ah.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", errors.Wrap(err, "try generate salt")
+	}
+
+	key := argon2.IDKey([]byte(password), salt, ah.params.Iterations, ah.params.Memory,
+		ah.params.Parallelism, ah.params.KeyLength)
+
+	return formatArgon2(ah.params, salt, key), nil
+}
+
+func (ah *Argon2Hasher) Verify(hash, password string) (bool, error) {
+	if strings.HasPrefix(hash, argon2idPrefix) {
+		params, salt, key, err := parseArgon2(hash)
+		if err != nil {
+			return false, err
+		}
+
+		actual := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism,
+			params.KeyLength)
+		if subtle.ConstantTimeCompare(key, actual) != 1 {
+			return false, ErrorIncorrectPassword
+		}
+
+		// Соль другой длины не ослабляет хеш, поэтому пересчёт нужен только при смене стоимости
+		outdated := params.Memory != ah.params.Memory || params.Iterations != ah.params.Iterations ||
+			params.Parallelism != ah.params.Parallelism || params.KeyLength != ah.params.KeyLength
+		return outdated, nil
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, ErrorIncorrectPassword
+		}
+		return false, errors.Wrap(ErrorUnknownPasswordHash, err.Error())
+	}
+
+	// Хеши bcrypt остались от прежних версий и заменяются на argon2id
+	return true, nil
+}
+
+// formatArgon2
+// Записывает хеш в формате PHC
+func formatArgon2(params Argon2Params, salt, key []byte) string {
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version,
+		params.Memory, params.Iterations, params.Parallelism,
+		phcEncoding.EncodeToString(salt), phcEncoding.EncodeToString(key))
+}
+
+// parseArgon2
+// Разбирает хеш в формате PHC и возвращает его параметры, соль и ключ
+func parseArgon2(hash string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	parts := strings.Split(strings.TrimPrefix(hash, argon2idPrefix), "$")
+	if len(parts) != 4 {
+		return params, nil, nil, errors.Wrap(ErrorUnknownPasswordHash, "argon2id hash must have 4 parts")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[0], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, errors.Wrapf(ErrorUnknownPasswordHash, "unsupported argon2id version %s", parts[0])
+	}
+
+	if _, err := fmt.Sscanf(parts[1], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations,
+		&params.Parallelism); err != nil || params.Iterations == 0 || params.Parallelism == 0 {
+		return params, nil, nil, errors.Wrapf(ErrorUnknownPasswordHash, "incorrect argon2id parameters %s", parts[1])
+	}
+
+	salt, err := phcEncoding.DecodeString(parts[2])
+	if err != nil {
+		return params, nil, nil, errors.Wrap(ErrorUnknownPasswordHash, "incorrect argon2id salt")
+	}
+
+	key, err := phcEncoding.DecodeString(parts[3])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, errors.Wrap(ErrorUnknownPasswordHash, "incorrect argon2id key")
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"testing"
+)
+
+type PasswordHasherSuite struct {
+	suite.Suite
+}
+
+func (phs *PasswordHasherSuite) TestHashFunction(t provider.T) {
+	t.Title("Hash function of argon2id hasher")
+	t.NewStep("Init test data")
+	hasher := NewTestPasswordHasher()
+	password := "password"
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		hash, err := hasher.Hash(password)
+		t.Require().NoError(err)
+		t.Require().True(strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"))
+
+		t.NewStep("Check salt is random")
+		other, err := hasher.Hash(password)
+		t.Require().NoError(err)
+		t.Require().NotEqual(hash, other)
+	})
+}
+
+func (phs *PasswordHasherSuite) TestVerifyFunction(t provider.T) {
+	t.Title("Verify function of argon2id hasher")
+	t.NewStep("Init test data")
+	hasher := NewTestPasswordHasher()
+	password := "password"
+	hash, err := hasher.Hash(password)
+	t.Require().NoError(err)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		outdated, err := hasher.Verify(hash, password)
+		t.Require().NoError(err)
+		t.Require().False(outdated)
+	})
+
+	t.WithNewStep("Incorrect password execute", func(t provider.StepCtx) {
+		_, err := hasher.Verify(hash, "other")
+		t.Require().ErrorIs(err, ErrorIncorrectPassword)
+	})
+
+	t.WithNewStep("Outdated parameters execute", func(t provider.StepCtx) {
+		stronger := NewArgon2Hasher(Argon2Params{Memory: 128, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+		outdated, err := stronger.Verify(hash, password)
+		t.Require().NoError(err)
+		t.Require().True(outdated)
+	})
+
+	t.WithNewStep("Other salt length execute", func(t provider.StepCtx) {
+		longSalt := NewArgon2Hasher(Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 32, KeyLength: 32})
+		outdated, err := longSalt.Verify(hash, password)
+		t.Require().NoError(err)
+		t.Require().False(outdated)
+	})
+
+	t.WithNewStep("Bcrypt execute", func(t provider.StepCtx) {
+		legacy, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+		t.Require().NoError(err)
+
+		outdated, err := hasher.Verify(string(legacy), password)
+		t.Require().NoError(err)
+		t.Require().True(outdated)
+
+		_, err = hasher.Verify(string(legacy), "other")
+		t.Require().ErrorIs(err, ErrorIncorrectPassword)
+	})
+
+	t.WithNewStep("Malformed hash execute", func(t provider.StepCtx) {
+		for _, malformed := range []string{
+			"plain",
+			"$argon2id$v=19$m=64,t=1,p=1$salt",
+			"$argon2id$v=16$m=64,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+			"$argon2id$v=19$m=64,t=0,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+			"$argon2id$v=19$m=64,t=1,p=1$!!!$a2V5",
+			"$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$",
+		} {
+			_, err := hasher.Verify(malformed, password)
+			t.Require().ErrorIs(err, ErrorUnknownPasswordHash, malformed)
+		}
+	})
+}
+
+func TestRunPasswordHasherSuite(t *testing.T) {
+	suite.RunSuite(t, new(PasswordHasherSuite))
+}
@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"github.com/pkg/errors"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordPolicy
+// Rules of new passwords, applied on registration, password change and password reset.
+// MinClasses is the number of character classes (lowercase letters, uppercase letters,
+// digits and other characters) the password must contain. Zero MaxLength doesn't limit the length.
+type PasswordPolicy struct {
+	MinLength  int
+	MaxLength  int
+	MinClasses int
+}
+
+// DefaultPasswordPolicy
+// Password length is limited to bound the cost of hashing
+var DefaultPasswordPolicy = PasswordPolicy{
+	MinLength:  8,
+	MaxLength:  72,
+	MinClasses: 2,
+}
+
+// Check
+// Returns Error:
+//   - ErrorPasswordPolicy
+func (pp PasswordPolicy) Check(password string) error {
+	length := utf8.RuneCountInString(password)
+	if length < pp.MinLength {
+		return errors.Wrapf(ErrorPasswordPolicy, "must be at least %d characters", pp.MinLength)
+	}
+
+	// Ограничение длины задано в байтах, а не в символах
+	if pp.MaxLength > 0 && len(password) > pp.MaxLength {
+		return errors.Wrapf(ErrorPasswordPolicy, "must be at most %d bytes", pp.MaxLength)
+	}
+
+	if classes := characterClasses(password); classes < pp.MinClasses {
+		return errors.Wrapf(ErrorPasswordPolicy,
+			"must contain at least %d of lowercase letters, uppercase letters, digits and other characters",
+			pp.MinClasses)
+	}
+
+	return nil
+}
+
+// characterClasses
+// Считает классы символов в пароле: строчные и заглавные буквы, цифры и остальные символы
+func characterClasses(password string) int {
+	var lower, upper, digit, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	classes := 0
+	for _, present := range []bool{lower, upper, digit, other} {
+		if present {
+			classes++
+		}
+	}
+
+	return classes
+}
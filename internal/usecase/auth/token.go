@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/pkg/errors"
+	"strings"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/token"
+	"vk_film/internal/repository/user"
+)
+
+const (
+	TokenPrefix = "vkf_"
+	tokenLength = 32
+)
+
+// hashToken
+// Хеширует секрет токена. В отличие от паролей используется быстрый хеш, так как секрет случайный и длинный
+func hashToken(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// generateToken
+// Генерирует случайный секрет токена с префиксом
+func generateToken(prefix string) (string, error) {
+	secret := make([]byte, tokenLength)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return prefix + hex.EncodeToString(secret), nil
+}
+
+func (sm *SessionManager) CreateToken(tkn *token.Token) (string, *token.Token, error) {
+	secret, err := generateToken(TokenPrefix)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "try generate token for user %d", tkn.UserID)
+	}
+
+	createdToken, err := sm.tokens.CreateToken(tkn, hashToken(secret))
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "try save token for user %d", tkn.UserID)
+	}
+
+	return secret, createdToken, nil
+}
+
+func (sm *SessionManager) GetTokens(userId types.Id) ([]token.Token, error) {
+	tokens, err := sm.tokens.GetUserTokens(userId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "try get tokens of user %d", userId)
+	}
+
+	return tokens, nil
+}
+
+func (sm *SessionManager) RevokeToken(userId, tokenId types.Id) error {
+	if err := sm.tokens.DeleteToken(userId, tokenId); err != nil {
+		return errors.Wrapf(err, "try delete token %d of user %d", tokenId, userId)
+	}
+
+	return nil
+}
+
+func (sm *SessionManager) GetUserByToken(secret string) (*user.User, *token.Token, error) {
+	if !strings.HasPrefix(secret, TokenPrefix) {
+		return nil, nil, token.ErrorTokenNotFound
+	}
+
+	tkn, err := sm.tokens.UseToken(hashToken(secret))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "try use token")
+	}
+
+	usr, err := sm.users.GetUserById(tkn.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrorUserNotFound) {
+			return nil, nil, token.ErrorTokenNotFound
+		}
+		return nil, nil, errors.Wrapf(err, "try get user by id %d for token %d", tkn.UserID, tkn.ID)
+	}
+
+	return usr, tkn, nil
+}
@@ -0,0 +1,415 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/pkg/errors"
+	"strings"
+	"time"
+	"vk_film/internal/pkg/totp"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/session"
+	"vk_film/internal/repository/twofactor"
+)
+
+const (
+	ChallengeTokenPrefix = "vkc_"
+
+	codeAttemptsPrefix = "2fa:"
+	codeSkew           = 1
+	recoveryCodesCount = 10
+	recoveryCodeLength = 5
+)
+
+// TwoFactorPolicy
+// The login of the user with enabled two-factor authentication passes the password check
+// and waits for the second factor for ChallengeTTL. After MaxAttempts incorrect codes
+// the pending login is dropped. RequiredForAdmin makes users of the admin role enroll
+// two-factor authentication on the next login and forbids them to disable it.
+type TwoFactorPolicy struct {
+	Issuer           string
+	RequiredForAdmin bool
+	ChallengeTTL     time.Duration
+	MaxAttempts      uint64
+}
+
+var DefaultTwoFactorPolicy = TwoFactorPolicy{
+	Issuer:       "VK Film",
+	ChallengeTTL: 5 * time.Minute,
+	MaxAttempts:  5,
+}
+
+// challenge
+// Создаёт ожидающий вход, если пользователь должен пройти второй фактор. Иначе возвращает nil
+func (sm *SessionManager) challenge(userId types.Id, client ClientInfo) (*Credentials, error) {
+	if sm.twoFactor == nil {
+		return nil, nil
+	}
+
+	enabled, err := sm.twoFactorEnabled(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !enabled {
+		required, err := sm.twoFactorRequired(userId)
+		if err != nil || !required {
+			return nil, err
+		}
+	}
+
+	challengeToken, err := generateToken(ChallengeTokenPrefix)
+	if err != nil {
+		return nil, errors.Wrapf(err, "try generate challenge token for user %d", userId)
+	}
+
+	if err := sm.twoFactor.CreateChallenge(&twofactor.Challenge{
+		UserID:    userId,
+		ExpiresAt: sm.now().Add(sm.twoFactorPolicy.ChallengeTTL),
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+	}, hashToken(challengeToken)); err != nil {
+		return nil, errors.Wrapf(err, "try save login challenge for user %d", userId)
+	}
+
+	return &Credentials{
+		SessionId:      challengeToken,
+		ExpiresIn:      sm.twoFactorPolicy.ChallengeTTL,
+		Pending:        true,
+		EnrollRequired: !enabled,
+	}, nil
+}
+
+// verifyChallenge
+// Проверяет код ожидающего входа и возвращает пользователя. Если вход включает
+// двухфакторную аутентификацию, возвращает новые коды восстановления
+func (sm *SessionManager) verifyChallenge(challengeToken, code string) (types.Id, []string, error) {
+	if sm.twoFactor == nil {
+		return 0, nil, session.ErrorNoSession
+	}
+
+	hash := hashToken(challengeToken)
+	challenge, err := sm.getChallenge(hash)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	keys := sm.codeAttemptKeys(challenge.UserID)
+	if err := sm.checkLock(keys); err != nil {
+		return 0, nil, err
+	}
+
+	secret, err := sm.twoFactor.GetSecret(challenge.UserID)
+	if err != nil {
+		return 0, nil, errors.Wrapf(err, "try get totp secret of user %d", challenge.UserID)
+	}
+
+	var recoveryCodes []string
+	if secret.Enabled {
+		err = sm.checkCode(secret, code)
+	} else {
+		recoveryCodes, err = sm.enable(secret, code)
+	}
+
+	if errors.Is(err, ErrorIncorrectCode) {
+		err = sm.registerCodeFailure(hash, keys, err)
+		sm.recordFailure(challenge.UserID, ClientInfo{IP: challenge.IP, UserAgent: challenge.UserAgent}, err)
+		return 0, nil, err
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if err := sm.twoFactor.DelChallenge(hash); err != nil {
+		return 0, nil, errors.Wrapf(err, "try delete login challenge of user %d", challenge.UserID)
+	}
+
+	return challenge.UserID, recoveryCodes, nil
+}
+
+func (sm *SessionManager) VerifyLogin(challengeToken, code string, client ClientInfo) (*Credentials, error) {
+	userId, recoveryCodes, err := sm.verifyChallenge(challengeToken, code)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := sm.LoginUser(userId, client)
+	if err != nil {
+		return nil, errors.Wrapf(err, "try login user %d", userId)
+	}
+	credentials.RecoveryCodes = recoveryCodes
+
+	return credentials, nil
+}
+
+func (sm *SessionManager) EnrollLogin(challengeToken string) (*Enrollment, error) {
+	if sm.twoFactor == nil {
+		return nil, session.ErrorNoSession
+	}
+
+	challenge, err := sm.getChallenge(hashToken(challengeToken))
+	if err != nil {
+		return nil, err
+	}
+
+	return sm.EnrollTwoFactor(challenge.UserID)
+}
+
+func (sm *SessionManager) EnrollTwoFactor(userId types.Id) (*Enrollment, error) {
+	if sm.twoFactor == nil {
+		return nil, ErrorTwoFactorDisabled
+	}
+
+	usr, err := sm.users.GetUserById(userId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "try get user by id %d", userId)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.Wrapf(err, "try generate totp secret for user %d", userId)
+	}
+
+	// Включённый секрет не заменяется, поэтому чужой вход с паролем не может перенастроить второй фактор
+	if err := sm.twoFactor.SetSecret(userId, secret); err != nil {
+		return nil, errors.Wrapf(err, "try save totp secret of user %d", userId)
+	}
+
+	return &Enrollment{
+		Secret: secret,
+		URI:    totp.ProvisioningURI(sm.twoFactorPolicy.Issuer, usr.Login, secret),
+	}, nil
+}
+
+func (sm *SessionManager) ConfirmTwoFactor(userId types.Id, code string) ([]string, error) {
+	if sm.twoFactor == nil {
+		return nil, ErrorTwoFactorDisabled
+	}
+
+	keys := sm.codeAttemptKeys(userId)
+	if err := sm.checkLock(keys); err != nil {
+		return nil, err
+	}
+
+	secret, err := sm.twoFactor.GetSecret(userId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "try get totp secret of user %d", userId)
+	}
+
+	if secret.Enabled {
+		return nil, errors.Wrapf(twofactor.ErrorAlreadyEnabled, "for user %d", userId)
+	}
+
+	recoveryCodes, err := sm.enable(secret, code)
+	if errors.Is(err, ErrorIncorrectCode) {
+		return nil, sm.registerFailure(keys, err)
+	}
+
+	return recoveryCodes, err
+}
+
+func (sm *SessionManager) DisableTwoFactor(userId types.Id, code string) error {
+	if sm.twoFactor == nil {
+		return ErrorTwoFactorDisabled
+	}
+
+	required, err := sm.twoFactorRequired(userId)
+	if err != nil {
+		return err
+	}
+
+	if required {
+		return errors.Wrapf(ErrorTwoFactorRequired, "for user %d", userId)
+	}
+
+	keys := sm.codeAttemptKeys(userId)
+	if err := sm.checkLock(keys); err != nil {
+		return err
+	}
+
+	secret, err := sm.twoFactor.GetSecret(userId)
+	if err != nil {
+		return errors.Wrapf(err, "try get totp secret of user %d", userId)
+	}
+
+	if !secret.Enabled {
+		return errors.Wrapf(twofactor.ErrorSecretNotFound, "enabled for user %d", userId)
+	}
+
+	if err := sm.checkCode(secret, code); err != nil {
+		if errors.Is(err, ErrorIncorrectCode) {
+			return sm.registerFailure(keys, err)
+		}
+		return err
+	}
+
+	if err := sm.twoFactor.Delete(userId); err != nil {
+		return errors.Wrapf(err, "try delete totp secret of user %d", userId)
+	}
+
+	return nil
+}
+
+// getChallenge
+// Ищет ожидающий вход, отсутствующий или истёкший вход считается отсутствующей сессией
+func (sm *SessionManager) getChallenge(hash string) (*twofactor.Challenge, error) {
+	challenge, err := sm.twoFactor.GetChallenge(hash)
+	if err != nil {
+		if errors.Is(err, twofactor.ErrorChallengeNotFound) {
+			return nil, session.ErrorNoSession
+		}
+		return nil, errors.Wrap(err, "try get login challenge")
+	}
+
+	return challenge, nil
+}
+
+// twoFactorEnabled
+// Проверяет, включена ли у пользователя двухфакторная аутентификация
+func (sm *SessionManager) twoFactorEnabled(userId types.Id) (bool, error) {
+	secret, err := sm.twoFactor.GetSecret(userId)
+	if err != nil {
+		if errors.Is(err, twofactor.ErrorSecretNotFound) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "try get totp secret of user %d", userId)
+	}
+
+	return secret.Enabled, nil
+}
+
+// twoFactorRequired
+// Проверяет, обязательна ли двухфакторная аутентификация для роли пользователя
+func (sm *SessionManager) twoFactorRequired(userId types.Id) (bool, error) {
+	if !sm.twoFactorPolicy.RequiredForAdmin {
+		return false, nil
+	}
+
+	usr, err := sm.users.GetUserById(userId)
+	if err != nil {
+		return false, errors.Wrapf(err, "try get user by id %d", userId)
+	}
+
+	return usr.Role == types.ADMIN, nil
+}
+
+// enable
+// Проверяет код из приложения и включает секрет, возвращает новые коды восстановления
+func (sm *SessionManager) enable(secret *twofactor.Secret, code string) ([]string, error) {
+	if err := sm.checkTOTP(secret, code); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, errors.Wrapf(err, "try generate recovery codes for user %d", secret.UserID)
+	}
+
+	if err := sm.twoFactor.Enable(secret.UserID, hashes); err != nil {
+		return nil, errors.Wrapf(err, "try enable totp secret of user %d", secret.UserID)
+	}
+
+	return recoveryCodes, nil
+}
+
+// checkCode
+// Проверяет код из приложения или одноразовый код восстановления
+func (sm *SessionManager) checkCode(secret *twofactor.Secret, code string) error {
+	code = normalizeCode(code)
+	if len(code) == totp.Digits {
+		return sm.checkTOTP(secret, code)
+	}
+
+	if err := sm.twoFactor.UseRecoveryCode(secret.UserID, hashToken(code)); err != nil {
+		if errors.Is(err, twofactor.ErrorRecoveryCodeNotFound) {
+			return errors.Wrapf(ErrorIncorrectCode, "recovery code of user %d", secret.UserID)
+		}
+		return errors.Wrapf(err, "try use recovery code of user %d", secret.UserID)
+	}
+
+	return nil
+}
+
+// checkTOTP
+// Проверяет код из приложения. Код каждого шага времени принимается только один раз
+func (sm *SessionManager) checkTOTP(secret *twofactor.Secret, code string) error {
+	counter, ok, err := totp.Validate(secret.Secret, normalizeCode(code), sm.now(), codeSkew)
+	if err != nil {
+		return errors.Wrapf(err, "try validate totp code of user %d", secret.UserID)
+	}
+
+	if !ok {
+		return errors.Wrapf(ErrorIncorrectCode, "totp code of user %d", secret.UserID)
+	}
+
+	if err := sm.twoFactor.UseCounter(secret.UserID, counter); err != nil {
+		if errors.Is(err, twofactor.ErrorCodeAlreadyUsed) {
+			return errors.Wrapf(ErrorIncorrectCode, "totp code of user %d was already used", secret.UserID)
+		}
+		return errors.Wrapf(err, "try use totp code of user %d", secret.UserID)
+	}
+
+	return nil
+}
+
+// codeAttemptKeys
+// Неудачные попытки ввода кода учитываются для пользователя так же, как попытки входа для логина
+func (sm *SessionManager) codeAttemptKeys(userId types.Id) []attemptKey {
+	if sm.attempts == nil || sm.protection.MaxLoginAttempts == 0 {
+		return nil
+	}
+
+	return []attemptKey{{
+		key:         fmt.Sprintf("%s%d", codeAttemptsPrefix, userId),
+		maxAttempts: sm.protection.MaxLoginAttempts,
+	}}
+}
+
+// registerCodeFailure
+// Учитывает неверный код ожидающего входа. После MaxAttempts неверных кодов вход удаляется
+func (sm *SessionManager) registerCodeFailure(hash string, keys []attemptKey, cause error) error {
+	attempts, err := sm.twoFactor.AddChallengeAttempt(hash)
+	if err != nil && !errors.Is(err, twofactor.ErrorChallengeNotFound) {
+		return errors.Wrap(err, "try add attempt of login challenge")
+	}
+
+	if err != nil || attempts >= sm.twoFactorPolicy.MaxAttempts {
+		if err := sm.twoFactor.DelChallenge(hash); err != nil {
+			return errors.Wrap(err, "try delete login challenge")
+		}
+		cause = errors.Wrap(ErrorTooManyAttempts, cause.Error())
+	}
+
+	if len(keys) == 0 {
+		return cause
+	}
+
+	return sm.registerFailure(keys, cause)
+}
+
+// generateRecoveryCodes
+// Генерирует одноразовые коды восстановления и их хеши
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([]string, 0, recoveryCodesCount)
+
+	buf := make([]byte, recoveryCodeLength)
+	for i := 0; i < recoveryCodesCount; i++ {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+
+		code := hex.EncodeToString(buf)
+		codes = append(codes, code[:recoveryCodeLength]+"-"+code[recoveryCodeLength:])
+		hashes = append(hashes, hashToken(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// normalizeCode
+// Убирает из кода пробелы и дефисы, которые пользователи вводят для удобства
+func normalizeCode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(code))
+}
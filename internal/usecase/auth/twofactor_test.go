@@ -0,0 +1,519 @@
+package auth
+
+import (
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+	"vk_film/internal/pkg/totp"
+	"vk_film/internal/pkg/types"
+	mra "vk_film/internal/repository/attempts/mocks"
+	mrr "vk_film/internal/repository/refresh/mocks"
+	"vk_film/internal/repository/session"
+	mrs "vk_film/internal/repository/session/mocks"
+	mrt "vk_film/internal/repository/token/mocks"
+	"vk_film/internal/repository/twofactor"
+	mrf "vk_film/internal/repository/twofactor/mocks"
+	"vk_film/internal/repository/user"
+	mru "vk_film/internal/repository/user/mocks"
+)
+
+const testSecret = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+
+type TwoFactorSuite struct {
+	suite.Suite
+	sessionManager *SessionManager
+	mockUser       *mru.UserRepository
+	mockSession    *mrs.SessionRepository
+	mockAttempts   *mra.AttemptsRepository
+	mockTwoFactor  *mrf.TwoFactorRepository
+	now            time.Time
+	gmc            *gomock.Controller
+}
+
+func (tfs *TwoFactorSuite) BeforeEach(t provider.T) {
+	tfs.gmc = gomock.NewController(t)
+	tfs.mockUser = mru.NewUserRepository(tfs.gmc)
+	tfs.mockSession = mrs.NewSessionRepository(tfs.gmc)
+	tfs.mockAttempts = mra.NewAttemptsRepository(tfs.gmc)
+	tfs.mockTwoFactor = mrf.NewTwoFactorRepository(tfs.gmc)
+
+	policy := DefaultTwoFactorPolicy
+	policy.RequiredForAdmin = true
+	tfs.sessionManager = NewSessionManager(tfs.mockUser, tfs.mockSession, tfs.mockAttempts,
+		mrt.NewTokenRepository(tfs.gmc), tfs.mockTwoFactor, DefaultLoginProtection, policy, testSessionPolicy, nil, NewTestPasswordHasher(), DefaultPasswordPolicy, nil, nil)
+	tfs.now = time.Date(2024, 3, 1, 12, 0, 10, 0, time.UTC)
+	tfs.sessionManager.now = func() time.Time { return tfs.now }
+}
+
+func (tfs *TwoFactorSuite) AfterEach(t provider.T) {
+	tfs.gmc.Finish()
+}
+
+// currentCode
+// Возвращает код из приложения для текущего времени теста
+func (tfs *TwoFactorSuite) currentCode(t provider.StepCtx) string {
+	code, err := totp.Code(testSecret, totp.Counter(tfs.now))
+	t.Require().NoError(err)
+	return code
+}
+
+func (tfs *TwoFactorSuite) TestLoginFunction(t provider.T) {
+	t.Title("Login function of sessions manager with two-factor authentication")
+	t.NewStep("Init test data")
+	login := "login"
+	password := "password"
+	encryptPassword, err := NewTestPasswordHasher().Hash(password)
+	t.Require().NoError(err)
+	userId := types.Id(1)
+	client := ClientInfo{IP: "127.0.0.1", UserAgent: "curl/8.0"}
+
+	expectPassword := func() {
+		tfs.mockAttempts.EXPECT().GetLockTime(gomock.Any()).Return(time.Duration(0), nil).Times(2)
+		tfs.mockUser.EXPECT().GetPasswordByLogin(login).
+			Return(&user.LoginUser{ID: userId, Password: encryptPassword, Status: user.StatusActive}, nil)
+		tfs.mockAttempts.EXPECT().Reset("login:" + login).Return(nil)
+	}
+
+	t.WithNewStep("Pending login with enabled second factor execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectPassword()
+		var hash string
+		tfs.mockTwoFactor.EXPECT().GetSecret(userId).
+			Return(&twofactor.Secret{UserID: userId, Secret: testSecret, Enabled: true}, nil)
+		tfs.mockTwoFactor.EXPECT().CreateChallenge(gomock.Any(), gomock.Any()).
+			Do(func(challenge *twofactor.Challenge, h string) {
+				t.Require().Equal(userId, challenge.UserID)
+				t.Require().Equal(tfs.now.Add(DefaultTwoFactorPolicy.ChallengeTTL), challenge.ExpiresAt)
+				t.Require().Equal(client.IP, challenge.IP)
+				hash = h
+			}).Return(nil)
+
+		t.NewStep("Check result")
+		credentials, err := tfs.sessionManager.Login(login, password, client)
+		t.Require().NoError(err)
+		t.Require().True(credentials.Pending)
+		t.Require().False(credentials.EnrollRequired)
+		t.Require().True(strings.HasPrefix(credentials.SessionId, ChallengeTokenPrefix))
+		t.Require().Equal(hashToken(credentials.SessionId), hash)
+		t.Require().Equal(DefaultTwoFactorPolicy.ChallengeTTL, credentials.ExpiresIn)
+	})
+
+	t.WithNewStep("Pending login of admin without second factor execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectPassword()
+		tfs.mockTwoFactor.EXPECT().GetSecret(userId).Return(nil, twofactor.ErrorSecretNotFound)
+		tfs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId, Role: types.ADMIN}, nil)
+		tfs.mockTwoFactor.EXPECT().CreateChallenge(gomock.Any(), gomock.Any()).Return(nil)
+
+		t.NewStep("Check result")
+		credentials, err := tfs.sessionManager.Login(login, password, client)
+		t.Require().NoError(err)
+		t.Require().True(credentials.Pending)
+		t.Require().True(credentials.EnrollRequired)
+	})
+
+	t.WithNewStep("Login of user without second factor execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectPassword()
+		tfs.mockTwoFactor.EXPECT().GetSecret(userId).
+			Return(&twofactor.Secret{UserID: userId, Secret: testSecret}, nil)
+		tfs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId, Role: types.USER}, nil)
+		tfs.mockSession.EXPECT().Set(gomock.Any(), gomock.Any()).Return(nil)
+
+		t.NewStep("Check result")
+		credentials, err := tfs.sessionManager.Login(login, password, client)
+		t.Require().NoError(err)
+		t.Require().False(credentials.Pending)
+	})
+
+	t.WithNewStep("Error of getting secret execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectPassword()
+		tfs.mockTwoFactor.EXPECT().GetSecret(userId).Return(nil, testError)
+
+		t.NewStep("Check result")
+		_, err := tfs.sessionManager.Login(login, password, client)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (tfs *TwoFactorSuite) TestVerifyLoginFunction(t provider.T) {
+	t.Title("VerifyLogin function of sessions manager")
+	t.NewStep("Init test data")
+	challengeToken := ChallengeTokenPrefix + "token"
+	hash := hashToken(challengeToken)
+	userId := types.Id(1)
+	codeKey := "2fa:1"
+	client := ClientInfo{IP: "127.0.0.1", UserAgent: "curl/8.0"}
+	challenge := &twofactor.Challenge{UserID: userId, ExpiresAt: tfs.now.Add(time.Minute)}
+	secret := &twofactor.Secret{UserID: userId, Secret: testSecret, Enabled: true}
+	window := DefaultLoginProtection.AttemptsWindow
+
+	expectChallenge := func(secret *twofactor.Secret) {
+		tfs.mockTwoFactor.EXPECT().GetChallenge(hash).Return(challenge, nil)
+		tfs.mockAttempts.EXPECT().GetLockTime(codeKey).Return(time.Duration(0), nil)
+		tfs.mockTwoFactor.EXPECT().GetSecret(userId).Return(secret, nil)
+	}
+
+	t.WithNewStep("Correct execute with totp code", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectChallenge(secret)
+		tfs.mockTwoFactor.EXPECT().UseCounter(userId, totp.Counter(tfs.now)).Return(nil)
+		tfs.mockTwoFactor.EXPECT().DelChallenge(hash).Return(nil)
+		tfs.mockSession.EXPECT().Set(gomock.Any(), &session.Session{
+			UserID:      userId,
+			IP:          client.IP,
+			UserAgent:   client.UserAgent,
+			IdleTimeout: testSessionPolicy.Default.IdleTimeout,
+			ExpiresAt:   tfs.now.Add(testSessionPolicy.Default.MaxLifetime),
+		}).Return(nil)
+
+		t.NewStep("Check result")
+		credentials, err := tfs.sessionManager.VerifyLogin(challengeToken, tfs.currentCode(t), client)
+		t.Require().NoError(err)
+		t.Require().False(credentials.Pending)
+		t.Require().NotEmpty(credentials.SessionId)
+		t.Require().Empty(credentials.RecoveryCodes)
+	})
+
+	t.WithNewStep("Correct execute with recovery code", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectChallenge(secret)
+		tfs.mockTwoFactor.EXPECT().UseRecoveryCode(userId, hashToken("3f7a0c9d12")).Return(nil)
+		tfs.mockTwoFactor.EXPECT().DelChallenge(hash).Return(nil)
+		tfs.mockSession.EXPECT().Set(gomock.Any(), gomock.Any()).Return(nil)
+
+		t.NewStep("Check result")
+		_, err := tfs.sessionManager.VerifyLogin(challengeToken, "3F7A0-C9D12", client)
+		t.Require().NoError(err)
+	})
+
+	t.WithNewStep("Correct execute with enrollment", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectChallenge(&twofactor.Secret{UserID: userId, Secret: testSecret})
+		tfs.mockTwoFactor.EXPECT().UseCounter(userId, totp.Counter(tfs.now)).Return(nil)
+		tfs.mockTwoFactor.EXPECT().Enable(userId, gomock.Len(recoveryCodesCount)).Return(nil)
+		tfs.mockTwoFactor.EXPECT().DelChallenge(hash).Return(nil)
+		tfs.mockSession.EXPECT().Set(gomock.Any(), gomock.Any()).Return(nil)
+
+		t.NewStep("Check result")
+		credentials, err := tfs.sessionManager.VerifyLogin(challengeToken, tfs.currentCode(t), client)
+		t.Require().NoError(err)
+		t.Require().Len(credentials.RecoveryCodes, recoveryCodesCount)
+	})
+
+	t.WithNewStep("Recovery code on enrollment execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectChallenge(&twofactor.Secret{UserID: userId, Secret: testSecret})
+		tfs.mockTwoFactor.EXPECT().AddChallengeAttempt(hash).Return(uint64(1), nil)
+		tfs.mockAttempts.EXPECT().Add(codeKey, window).Return(uint64(1), nil)
+
+		t.NewStep("Check result")
+		_, err := tfs.sessionManager.VerifyLogin(challengeToken, "3f7a0-c9d12", client)
+		t.Require().ErrorIs(err, ErrorIncorrectCode)
+	})
+
+	t.WithNewStep("Incorrect code execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectChallenge(secret)
+		tfs.mockTwoFactor.EXPECT().AddChallengeAttempt(hash).Return(uint64(1), nil)
+		tfs.mockAttempts.EXPECT().Add(codeKey, window).Return(uint64(1), nil)
+
+		t.NewStep("Check result")
+		_, err := tfs.sessionManager.VerifyLogin(challengeToken, "000000", client)
+		t.Require().ErrorIs(err, ErrorIncorrectCode)
+	})
+
+	t.WithNewStep("Already used code execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectChallenge(secret)
+		tfs.mockTwoFactor.EXPECT().UseCounter(userId, totp.Counter(tfs.now)).Return(twofactor.ErrorCodeAlreadyUsed)
+		tfs.mockTwoFactor.EXPECT().AddChallengeAttempt(hash).Return(uint64(1), nil)
+		tfs.mockAttempts.EXPECT().Add(codeKey, window).Return(uint64(1), nil)
+
+		t.NewStep("Check result")
+		_, err := tfs.sessionManager.VerifyLogin(challengeToken, tfs.currentCode(t), client)
+		t.Require().ErrorIs(err, ErrorIncorrectCode)
+	})
+
+	t.WithNewStep("Last attempt of challenge execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectChallenge(secret)
+		tfs.mockTwoFactor.EXPECT().AddChallengeAttempt(hash).Return(DefaultTwoFactorPolicy.MaxAttempts, nil)
+		tfs.mockTwoFactor.EXPECT().DelChallenge(hash).Return(nil)
+		tfs.mockAttempts.EXPECT().Add(codeKey, window).Return(uint64(2), nil)
+
+		t.NewStep("Check result")
+		_, err := tfs.sessionManager.VerifyLogin(challengeToken, "000000", client)
+		t.Require().ErrorIs(err, ErrorTooManyAttempts)
+	})
+
+	t.WithNewStep("Locked user execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		tfs.mockTwoFactor.EXPECT().GetChallenge(hash).Return(challenge, nil)
+		tfs.mockAttempts.EXPECT().GetLockTime(codeKey).Return(time.Minute, nil)
+
+		t.NewStep("Check result")
+		_, err := tfs.sessionManager.VerifyLogin(challengeToken, tfs.currentCode(t), client)
+		var lockout *LockoutError
+		t.Require().ErrorAs(err, &lockout)
+	})
+
+	t.WithNewStep("Challenge not found execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		tfs.mockTwoFactor.EXPECT().GetChallenge(hash).Return(nil, twofactor.ErrorChallengeNotFound)
+
+		t.NewStep("Check result")
+		_, err := tfs.sessionManager.VerifyLogin(challengeToken, tfs.currentCode(t), client)
+		t.Require().ErrorIs(err, session.ErrorNoSession)
+	})
+
+	t.WithNewStep("Enrollment not started execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		tfs.mockTwoFactor.EXPECT().GetChallenge(hash).Return(challenge, nil)
+		tfs.mockAttempts.EXPECT().GetLockTime(codeKey).Return(time.Duration(0), nil)
+		tfs.mockTwoFactor.EXPECT().GetSecret(userId).Return(nil, twofactor.ErrorSecretNotFound)
+
+		t.NewStep("Check result")
+		_, err := tfs.sessionManager.VerifyLogin(challengeToken, tfs.currentCode(t), client)
+		t.Require().ErrorIs(err, twofactor.ErrorSecretNotFound)
+	})
+}
+
+func (tfs *TwoFactorSuite) TestEnrollLoginFunction(t provider.T) {
+	t.Title("EnrollLogin function of sessions manager")
+	t.NewStep("Init test data")
+	challengeToken := ChallengeTokenPrefix + "token"
+	userId := types.Id(1)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		tfs.mockTwoFactor.EXPECT().GetChallenge(hashToken(challengeToken)).
+			Return(&twofactor.Challenge{UserID: userId}, nil)
+		tfs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId, Login: "admin"}, nil)
+		tfs.mockTwoFactor.EXPECT().SetSecret(userId, gomock.Any()).Return(nil)
+
+		t.NewStep("Check result")
+		enrollment, err := tfs.sessionManager.EnrollLogin(challengeToken)
+		t.Require().NoError(err)
+		t.Require().NotEmpty(enrollment.Secret)
+	})
+
+	t.WithNewStep("Challenge not found execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		tfs.mockTwoFactor.EXPECT().GetChallenge(hashToken(challengeToken)).
+			Return(nil, twofactor.ErrorChallengeNotFound)
+
+		t.NewStep("Check result")
+		_, err := tfs.sessionManager.EnrollLogin(challengeToken)
+		t.Require().ErrorIs(err, session.ErrorNoSession)
+	})
+}
+
+func (tfs *TwoFactorSuite) TestEnrollTwoFactorFunction(t provider.T) {
+	t.Title("EnrollTwoFactor function of sessions manager")
+	t.NewStep("Init test data")
+	userId := types.Id(1)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		var secret string
+		tfs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId, Login: "admin"}, nil)
+		tfs.mockTwoFactor.EXPECT().SetSecret(userId, gomock.Any()).
+			Do(func(_ types.Id, s string) { secret = s }).Return(nil)
+
+		t.NewStep("Check result")
+		enrollment, err := tfs.sessionManager.EnrollTwoFactor(userId)
+		t.Require().NoError(err)
+		t.Require().Equal(secret, enrollment.Secret)
+		t.Require().Equal(totp.ProvisioningURI(DefaultTwoFactorPolicy.Issuer, "admin", secret), enrollment.URI)
+	})
+
+	t.WithNewStep("Already enabled execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		tfs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId, Login: "admin"}, nil)
+		tfs.mockTwoFactor.EXPECT().SetSecret(userId, gomock.Any()).Return(twofactor.ErrorAlreadyEnabled)
+
+		t.NewStep("Check result")
+		_, err := tfs.sessionManager.EnrollTwoFactor(userId)
+		t.Require().ErrorIs(err, twofactor.ErrorAlreadyEnabled)
+	})
+}
+
+func (tfs *TwoFactorSuite) TestConfirmTwoFactorFunction(t provider.T) {
+	t.Title("ConfirmTwoFactor function of sessions manager")
+	t.NewStep("Init test data")
+	userId := types.Id(1)
+	codeKey := "2fa:1"
+	secret := &twofactor.Secret{UserID: userId, Secret: testSecret}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		var hashes []string
+		tfs.mockAttempts.EXPECT().GetLockTime(codeKey).Return(time.Duration(0), nil)
+		tfs.mockTwoFactor.EXPECT().GetSecret(userId).Return(secret, nil)
+		tfs.mockTwoFactor.EXPECT().UseCounter(userId, totp.Counter(tfs.now)).Return(nil)
+		tfs.mockTwoFactor.EXPECT().Enable(userId, gomock.Any()).
+			Do(func(_ types.Id, h []string) { hashes = h }).Return(nil)
+
+		t.NewStep("Check result")
+		recoveryCodes, err := tfs.sessionManager.ConfirmTwoFactor(userId, tfs.currentCode(t))
+		t.Require().NoError(err)
+		t.Require().Len(recoveryCodes, recoveryCodesCount)
+		t.Require().Len(hashes, recoveryCodesCount)
+		for i, code := range recoveryCodes {
+			t.Require().Equal(hashToken(normalizeCode(code)), hashes[i])
+		}
+	})
+
+	t.WithNewStep("Already enabled execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		tfs.mockAttempts.EXPECT().GetLockTime(codeKey).Return(time.Duration(0), nil)
+		tfs.mockTwoFactor.EXPECT().GetSecret(userId).
+			Return(&twofactor.Secret{UserID: userId, Secret: testSecret, Enabled: true}, nil)
+
+		t.NewStep("Check result")
+		_, err := tfs.sessionManager.ConfirmTwoFactor(userId, tfs.currentCode(t))
+		t.Require().ErrorIs(err, twofactor.ErrorAlreadyEnabled)
+	})
+
+	t.WithNewStep("Incorrect code execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		tfs.mockAttempts.EXPECT().GetLockTime(codeKey).Return(time.Duration(0), nil)
+		tfs.mockTwoFactor.EXPECT().GetSecret(userId).Return(secret, nil)
+		tfs.mockAttempts.EXPECT().Add(codeKey, DefaultLoginProtection.AttemptsWindow).Return(uint64(1), nil)
+
+		t.NewStep("Check result")
+		_, err := tfs.sessionManager.ConfirmTwoFactor(userId, "000000")
+		t.Require().ErrorIs(err, ErrorIncorrectCode)
+	})
+}
+
+func (tfs *TwoFactorSuite) TestDisableTwoFactorFunction(t provider.T) {
+	t.Title("DisableTwoFactor function of sessions manager")
+	t.NewStep("Init test data")
+	userId := types.Id(1)
+	codeKey := "2fa:1"
+	secret := &twofactor.Secret{UserID: userId, Secret: testSecret, Enabled: true}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		tfs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId, Role: types.USER}, nil)
+		tfs.mockAttempts.EXPECT().GetLockTime(codeKey).Return(time.Duration(0), nil)
+		tfs.mockTwoFactor.EXPECT().GetSecret(userId).Return(secret, nil)
+		tfs.mockTwoFactor.EXPECT().UseCounter(userId, totp.Counter(tfs.now)).Return(nil)
+		tfs.mockTwoFactor.EXPECT().Delete(userId).Return(nil)
+
+		t.NewStep("Check result")
+		t.Require().NoError(tfs.sessionManager.DisableTwoFactor(userId, tfs.currentCode(t)))
+	})
+
+	t.WithNewStep("Required for admin execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		tfs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId, Role: types.ADMIN}, nil)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(tfs.sessionManager.DisableTwoFactor(userId, tfs.currentCode(t)), ErrorTwoFactorRequired)
+	})
+
+	t.WithNewStep("Not enabled execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		tfs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId, Role: types.USER}, nil)
+		tfs.mockAttempts.EXPECT().GetLockTime(codeKey).Return(time.Duration(0), nil)
+		tfs.mockTwoFactor.EXPECT().GetSecret(userId).Return(&twofactor.Secret{UserID: userId}, nil)
+
+		t.NewStep("Check result")
+		err := tfs.sessionManager.DisableTwoFactor(userId, tfs.currentCode(t))
+		t.Require().ErrorIs(err, twofactor.ErrorSecretNotFound)
+	})
+
+	t.WithNewStep("Incorrect recovery code execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		tfs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId, Role: types.USER}, nil)
+		tfs.mockAttempts.EXPECT().GetLockTime(codeKey).Return(time.Duration(0), nil)
+		tfs.mockTwoFactor.EXPECT().GetSecret(userId).Return(secret, nil)
+		tfs.mockTwoFactor.EXPECT().UseRecoveryCode(userId, gomock.Any()).Return(twofactor.ErrorRecoveryCodeNotFound)
+		tfs.mockAttempts.EXPECT().Add(codeKey, DefaultLoginProtection.AttemptsWindow).Return(uint64(1), nil)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(tfs.sessionManager.DisableTwoFactor(userId, "3f7a0-c9d12"), ErrorIncorrectCode)
+	})
+}
+
+func (tfs *TwoFactorSuite) TestLoginExternalFunction(t provider.T) {
+	t.Title("LoginExternal function of sessions manager with two-factor authentication")
+	t.NewStep("Init test data")
+	userId := types.Id(1)
+	client := ClientInfo{IP: "127.0.0.1", UserAgent: "curl/8.0"}
+
+	t.WithNewStep("Pending login with enabled second factor execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		tfs.mockTwoFactor.EXPECT().GetSecret(userId).
+			Return(&twofactor.Secret{UserID: userId, Secret: testSecret, Enabled: true}, nil)
+		tfs.mockTwoFactor.EXPECT().CreateChallenge(gomock.Any(), gomock.Any()).
+			Do(func(challenge *twofactor.Challenge, _ string) {
+				t.Require().Equal(userId, challenge.UserID)
+			}).Return(nil)
+
+		t.NewStep("Check result")
+		credentials, err := tfs.sessionManager.LoginExternal(userId, client)
+		t.Require().NoError(err)
+		t.Require().True(credentials.Pending)
+		t.Require().True(strings.HasPrefix(credentials.SessionId, ChallengeTokenPrefix))
+	})
+
+	t.WithNewStep("Login of user without second factor execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		tfs.mockTwoFactor.EXPECT().GetSecret(userId).Return(nil, twofactor.ErrorSecretNotFound)
+		tfs.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId, Role: types.USER}, nil)
+		tfs.mockSession.EXPECT().Set(gomock.Any(), gomock.Any()).Return(nil)
+
+		t.NewStep("Check result")
+		credentials, err := tfs.sessionManager.LoginExternal(userId, client)
+		t.Require().NoError(err)
+		t.Require().False(credentials.Pending)
+	})
+
+	t.WithNewStep("Error of getting secret execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		tfs.mockTwoFactor.EXPECT().GetSecret(userId).Return(nil, testError)
+
+		t.NewStep("Check result")
+		_, err := tfs.sessionManager.LoginExternal(userId, client)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func (tfs *TwoFactorSuite) TestJWTVerifyLoginFunction(t provider.T) {
+	t.Title("VerifyLogin function of jwt manager")
+	t.NewStep("Init test data")
+	mockRefresh := mrr.NewRefreshRepository(tfs.gmc)
+	jwtManager := NewJWTManager(tfs.mockUser, nil, mrt.NewTokenRepository(tfs.gmc), tfs.mockTwoFactor,
+		mockRefresh, newTestKeySet(t), DefaultJWTPolicy, DefaultLoginProtection, DefaultTwoFactorPolicy, NewTestPasswordHasher(), DefaultPasswordPolicy, nil, nil)
+	jwtManager.now = func() time.Time { return tfs.now }
+	challengeToken := ChallengeTokenPrefix + "token"
+	hash := hashToken(challengeToken)
+	userId := types.Id(1)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		tfs.mockTwoFactor.EXPECT().GetChallenge(hash).Return(&twofactor.Challenge{UserID: userId}, nil)
+		tfs.mockTwoFactor.EXPECT().GetSecret(userId).
+			Return(&twofactor.Secret{UserID: userId, Secret: testSecret, Enabled: true}, nil)
+		tfs.mockTwoFactor.EXPECT().UseCounter(userId, totp.Counter(tfs.now)).Return(nil)
+		tfs.mockTwoFactor.EXPECT().DelChallenge(hash).Return(nil)
+		mockRefresh.EXPECT().CreateToken(gomock.Any(), gomock.Any()).Return(nil)
+
+		t.NewStep("Check result")
+		credentials, err := jwtManager.VerifyLogin(challengeToken, tfs.currentCode(t), ClientInfo{})
+		t.Require().NoError(err)
+		t.Require().True(strings.HasPrefix(credentials.RefreshToken, RefreshTokenPrefix))
+	})
+}
+
+func TestRunTwoFactorSuite(t *testing.T) {
+	suite.RunSuite(t, new(TwoFactorSuite))
+}
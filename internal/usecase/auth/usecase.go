@@ -3,63 +3,233 @@ package auth
 import (
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
-	"golang.org/x/crypto/bcrypt"
+	"sync"
 	"time"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/attempts"
+	"vk_film/internal/repository/event"
 	"vk_film/internal/repository/session"
+	"vk_film/internal/repository/token"
+	"vk_film/internal/repository/twofactor"
 	"vk_film/internal/repository/user"
-)
-
-const (
-	ExpiredSessionTime = 48 * time.Hour
+	"vk_film/pkg/logger"
 )
 
 type SessionManager struct {
-	users    user.Repository
-	sessions session.Repository
+	users           user.Repository
+	sessions        session.Repository
+	attempts        attempts.Repository
+	tokens          token.Repository
+	twoFactor       twofactor.Repository
+	protection      LoginProtection
+	twoFactorPolicy TwoFactorPolicy
+	sessionPolicy   SessionPolicy
+	cache           *UserCache
+	passwords       PasswordHasher
+	passwordPolicy  PasswordPolicy
+	dummyOnce       sync.Once
+	dummyHash       string
+	events          event.Repository
+	l               logger.Interface
+	now             func() time.Time
 }
 
-func NewSessionManager(users user.Repository, sessions session.Repository) *SessionManager {
+// NewSessionManager
+// Nil attempts repository disables the login protection,
+// nil twoFactor repository disables two-factor authentication, nil cache disables caching of session users,
+// nil events repository disables the security event log. Failures of the security event log
+// don't break operations and are reported to l
+func NewSessionManager(users user.Repository, sessions session.Repository,
+	attempts attempts.Repository, tokens token.Repository, twoFactor twofactor.Repository,
+	protection LoginProtection, twoFactorPolicy TwoFactorPolicy, sessionPolicy SessionPolicy,
+	cache *UserCache, passwords PasswordHasher, passwordPolicy PasswordPolicy,
+	events event.Repository, l logger.Interface) *SessionManager {
+	if attempts == nil {
+		protection = LoginProtection{}
+	}
+
 	return &SessionManager{
-		users:    users,
-		sessions: sessions,
+		users:           users,
+		sessions:        sessions,
+		attempts:        attempts,
+		tokens:          tokens,
+		twoFactor:       twoFactor,
+		protection:      protection,
+		twoFactorPolicy: twoFactorPolicy,
+		sessionPolicy:   sessionPolicy,
+		cache:           cache,
+		passwords:       passwords,
+		passwordPolicy:  passwordPolicy,
+		events:          events,
+		l:               l,
+		now:             time.Now,
+	}
+}
+
+func (sm *SessionManager) Login(login, password string, client ClientInfo) (*Credentials, error) {
+	usr, err := sm.authenticate(login, password, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// Сессия выдаётся только после проверки второго фактора
+	credentials, err := sm.challenge(usr.ID, client)
+	if err != nil || credentials != nil {
+		return credentials, err
+	}
+
+	credentials, err = sm.LoginUser(usr.ID, client)
+	if err != nil {
+		return nil, errors.Wrapf(err, "try login user %s", login)
+	}
+
+	return credentials, nil
+}
+
+func (sm *SessionManager) LoginExternal(userId types.Id, client ClientInfo) (*Credentials, error) {
+	// Провайдер подтверждает только личность, второй фактор проверяется так же, как при входе по паролю
+	credentials, err := sm.challenge(userId, client)
+	if err != nil || credentials != nil {
+		return credentials, err
+	}
+
+	return sm.LoginUser(userId, client)
+}
+
+func (sm *SessionManager) LoginUser(userId types.Id, client ClientInfo) (*Credentials, error) {
+	lifetime, remembered, err := sm.lifetime(userId, client.RememberMe)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionId := uuid.New().String()
+
+	if err := sm.sessions.Set(sessionId, &session.Session{
+		UserID:      userId,
+		IP:          client.IP,
+		UserAgent:   client.UserAgent,
+		IdleTimeout: lifetime.IdleTimeout,
+		ExpiresAt:   sm.now().Add(lifetime.MaxLifetime),
+	}); err != nil {
+		return nil, errors.Wrapf(err, "try save session for user %d", userId)
 	}
+
+	sm.recordEvent(userId, event.LoginSucceeded, client)
+
+	return &Credentials{SessionId: sessionId, ExpiresIn: lifetime.MaxLifetime, Persistent: remembered}, nil
 }
 
-func (sm *SessionManager) Login(login, password string) (string, error) {
+// authenticate
+// Проверяет логин и пароль пользователя с учётом блокировки после неудачных попыток
+func (sm *SessionManager) authenticate(login, password string, client ClientInfo) (*user.LoginUser, error) {
+	keys := sm.attemptKeys(login, client.IP)
+
+	// Проверка блокировки логина и адреса клиента
+	if err := sm.checkLock(keys); err != nil {
+		return nil, err
+	}
+
 	usr, err := sm.users.GetPasswordByLogin(login)
 	if err != nil {
-		return "", err
+		if errors.Is(err, user.ErrorUserNotFound) {
+			sm.verifyDummy(password)
+			return nil, sm.registerFailure(keys, err)
+		}
+		return nil, err
+	}
+
+	// У пользователей внешнего провайдера нет пароля, вход по паролю для них невозможен
+	if usr.Password == "" {
+		sm.verifyDummy(password)
+		return nil, sm.failLogin(usr.ID, keys, client)
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(usr.Password), []byte(password)); err != nil {
-		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-			return "", ErrorIncorrectPassword
+	outdated, err := sm.passwords.Verify(usr.Password, password)
+	if err != nil {
+		if errors.Is(err, ErrorIncorrectPassword) {
+			return nil, sm.failLogin(usr.ID, keys, client)
 		}
-		return "", err
+		return nil, errors.Wrapf(err, "try verify password of user %s", login)
 	}
 
-	sessionId := uuid.New().String()
+	// Успешный вход сбрасывает счётчик неудачных попыток для логина
+	if err := sm.resetAttempts(login); err != nil {
+		return nil, errors.Wrapf(err, "try reset login attempts for user %s", login)
+	}
+
+	// Войти могут только одобренные пользователи, о статусе сообщается лишь после проверки пароля
+	if usr.Status != user.StatusActive {
+		return nil, errors.Wrapf(ErrorAccountNotActive, "user %s is %s", login, usr.Status)
+	}
 
-	if err := sm.sessions.Set(sessionId, usr.ID, ExpiredSessionTime); err != nil {
-		return "", errors.Wrapf(err, "try save session for user %s", login)
+	// Устаревший хеш заменяется, пока известен пароль. Ошибка не мешает входу,
+	// хеш будет заменён при следующем входе
+	if outdated {
+		_ = sm.updatePassword(usr.ID, password)
 	}
 
-	return sessionId, nil
+	return usr, nil
+}
+
+// verifyDummy
+// Проверяет пароль по постоянному хешу, чтобы ответ для неизвестного логина занимал столько же времени,
+// сколько проверка пароля существующего пользователя. Хеш вычисляется один раз с текущими параметрами
+func (sm *SessionManager) verifyDummy(password string) {
+	sm.dummyOnce.Do(func() {
+		sm.dummyHash, _ = sm.passwords.Hash(uuid.New().String())
+	})
+
+	_, _ = sm.passwords.Verify(sm.dummyHash, password)
 }
 
-func (sm *SessionManager) Logout(sessionId string) error {
+// failLogin
+// Учитывает неверный пароль известного пользователя и сохраняет события о нём
+func (sm *SessionManager) failLogin(userId types.Id, keys []attemptKey, client ClientInfo) error {
+	err := sm.registerFailure(keys, ErrorIncorrectPassword)
+	sm.recordFailure(userId, client, err)
+
+	return err
+}
+
+func (sm *SessionManager) Refresh(_ string, _ ClientInfo) (*Credentials, error) {
+	return nil, ErrorRefreshNotSupported
+}
+
+func (sm *SessionManager) Logout(sessionId string, client ClientInfo) error {
+	// Пользователь сессии нужен только для события выхода, обычно он уже есть в кеше
+	var usr *user.User
+	if sm.events != nil {
+		var err error
+		usr, err = sm.GetUserId(sessionId)
+		if err != nil && !errors.Is(err, session.ErrorNoSession) {
+			return err
+		}
+	}
+
+	sm.cache.forgetSession(sessionId)
 	if err := sm.sessions.Del(sessionId); err != nil {
 		return errors.Wrapf(err, "try delete session %s", sessionId)
 	}
+
+	if usr != nil {
+		sm.recordEvent(usr.ID, event.Logout, client)
+	}
+
 	return nil
 }
 
 func (sm *SessionManager) GetUserId(sessionId string) (*user.User, error) {
-	userId, err := sm.sessions.GetUserId(sessionId, ExpiredSessionTime)
+	if usr, found := sm.cache.get(sessionId); found {
+		return usr, nil
+	}
+
+	userId, err := sm.sessions.GetUserId(sessionId)
 	if err != nil {
 		return nil, errors.Wrapf(err, "try get session %s", sessionId)
 	}
 
+	// Поколение берётся до чтения пользователя, чтобы не закешировать данные, изменённые во время чтения
+	gen := sm.cache.generation(userId)
 	usr, err := sm.users.GetUserById(userId)
 	if err != nil {
 		if errors.Is(err, user.ErrorUserNotFound) {
@@ -69,5 +239,140 @@ func (sm *SessionManager) GetUserId(sessionId string) (*user.User, error) {
 		return nil, errors.Wrapf(err, "try get user by id %d in session %s", userId, sessionId)
 	}
 
+	sm.cache.put(sessionId, usr, gen)
 	return usr, nil
 }
+
+func (sm *SessionManager) GetSessions(userId types.Id, currentSessionId string) ([]session.Session, error) {
+	sessions, err := sm.sessions.GetUserSessions(userId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "try get sessions of user %d", userId)
+	}
+
+	currentId := session.PublicId(currentSessionId)
+	for i := range sessions {
+		sessions[i].Current = currentSessionId != "" && sessions[i].ID == currentId
+	}
+
+	return sessions, nil
+}
+
+func (sm *SessionManager) RevokeSession(userId types.Id, publicId string) error {
+	// Кеш не хранит публичные идентификаторы, поэтому забываются все сессии пользователя
+	sm.cache.forgetUser(userId)
+	if err := sm.sessions.DelUserSession(userId, publicId); err != nil {
+		return errors.Wrapf(err, "try delete session %s of user %d", publicId, userId)
+	}
+	return nil
+}
+
+func (sm *SessionManager) InvalidateUser(userId types.Id) {
+	sm.cache.forgetUser(userId)
+}
+
+func (sm *SessionManager) InvalidateAll() {
+	sm.cache.forgetAll()
+}
+
+func (sm *SessionManager) RevokeUserSessions(userId types.Id) error {
+	sm.cache.forgetUser(userId)
+	if err := sm.sessions.DelUserSessions(userId, ""); err != nil {
+		return errors.Wrapf(err, "try delete sessions of user %d", userId)
+	}
+	return nil
+}
+
+func (sm *SessionManager) ChangePassword(userId types.Id, sessionId, currentPassword, newPassword string,
+	client ClientInfo) error {
+	if err := sm.checkPassword(userId, currentPassword); err != nil {
+		return err
+	}
+
+	if err := sm.updatePassword(userId, newPassword); err != nil {
+		return err
+	}
+
+	// Текущая сессия остаётся, остальные сессии пользователя завершаются
+	sm.cache.forgetUser(userId)
+	if err := sm.sessions.DelUserSessions(userId, sessionId); err != nil {
+		return errors.Wrapf(err, "try delete other sessions of user %d", userId)
+	}
+
+	sm.recordEvent(userId, event.PasswordChanged, client)
+
+	return nil
+}
+
+func (sm *SessionManager) ResetPassword(userId types.Id, newPassword string, client ClientInfo) error {
+	if err := sm.updatePassword(userId, newPassword); err != nil {
+		return err
+	}
+
+	// После сброса пароля завершаются все сессии пользователя
+	sm.cache.forgetUser(userId)
+	if err := sm.sessions.DelUserSessions(userId, ""); err != nil {
+		return errors.Wrapf(err, "try delete sessions of user %d", userId)
+	}
+
+	sm.recordEvent(userId, event.PasswordReset, client)
+
+	return nil
+}
+
+func (sm *SessionManager) VerifyPassword(userId types.Id, password string) error {
+	return sm.checkPassword(userId, password)
+}
+
+// checkPassword
+// Проверяет текущий пароль пользователя
+func (sm *SessionManager) checkPassword(userId types.Id, password string) error {
+	usr, err := sm.users.GetPasswordById(userId)
+	if err != nil {
+		return errors.Wrapf(err, "try get password of user %d", userId)
+	}
+
+	if usr.Password == "" {
+		return ErrorIncorrectPassword
+	}
+
+	if _, err := sm.passwords.Verify(usr.Password, password); err != nil {
+		if errors.Is(err, ErrorIncorrectPassword) {
+			return ErrorIncorrectPassword
+		}
+		return errors.Wrapf(err, "try verify password of user %d", userId)
+	}
+
+	return nil
+}
+
+// updatePassword
+// Проверяет новый пароль по политике, хеширует и сохраняет его
+func (sm *SessionManager) updatePassword(userId types.Id, password string) error {
+	if err := sm.passwordPolicy.Check(password); err != nil {
+		return err
+	}
+
+	hash, err := sm.passwords.Hash(password)
+	if err != nil {
+		return errors.Wrapf(err, "try hash password for user %d", userId)
+	}
+
+	if err := sm.users.UpdateUserPassword(&user.User{ID: userId, Password: hash}); err != nil {
+		return errors.Wrapf(err, "try update password of user %d", userId)
+	}
+
+	return nil
+}
+
+func (sm *SessionManager) UnlockUser(userId types.Id) error {
+	usr, err := sm.users.GetUserById(userId)
+	if err != nil {
+		return errors.Wrapf(err, "try get user by id %d", userId)
+	}
+
+	if err := sm.resetAttempts(usr.Login); err != nil {
+		return errors.Wrapf(err, "try reset login attempts for user %d", userId)
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package bootstrap
+
+import (
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/user"
+	mru "vk_film/internal/repository/user/mocks"
+	"vk_film/internal/usecase/auth"
+)
+
+var testError = errors.New("test error")
+
+type AdminBootstrapSuite struct {
+	suite.Suite
+	usecase  *AdminBootstrap
+	mockUser *mru.UserRepository
+	gmc      *gomock.Controller
+}
+
+func (abs *AdminBootstrapSuite) BeforeEach(t provider.T) {
+	abs.gmc = gomock.NewController(t)
+	abs.mockUser = mru.NewUserRepository(abs.gmc)
+	abs.usecase = NewAdminBootstrap(abs.mockUser, auth.NewTestPasswordHasher())
+}
+
+func (abs *AdminBootstrapSuite) AfterEach(t provider.T) {
+	abs.gmc.Finish()
+}
+
+func (abs *AdminBootstrapSuite) TestEnsureAdminFunction(t provider.T) {
+	t.Title("EnsureAdmin function of Admin bootstrap usecase")
+	t.NewStep("Init test data")
+	admin := Admin{Login: "root", Password: "long admin password", Email: "root@example.com"}
+
+	checkUser := func(password string) func(usr *user.User) (*user.User, error) {
+		return func(usr *user.User) (*user.User, error) {
+			t.Require().Equal(admin.Login, usr.Login)
+			t.Require().Equal(types.ADMIN, usr.Role)
+			t.Require().Equal(admin.Email, usr.Email)
+			t.Require().Empty(usr.Status)
+			outdated, err := auth.NewTestPasswordHasher().Verify(usr.Password, password)
+			t.Require().NoError(err)
+			t.Require().False(outdated)
+			return &user.User{ID: 1, Login: usr.Login, Role: usr.Role}, nil
+		}
+	}
+
+	t.WithNewStep("Admin exists execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		abs.mockUser.EXPECT().CountActiveUsers(types.ADMIN).Return(1, nil)
+
+		t.NewStep("Check result")
+		result, err := abs.usecase.EnsureAdmin(admin)
+		t.Require().NoError(err)
+		t.Require().Nil(result)
+	})
+
+	t.WithNewStep("Configured password execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		abs.mockUser.EXPECT().CountActiveUsers(types.ADMIN).Return(0, nil)
+		abs.mockUser.EXPECT().CreateUser(gomock.Any()).DoAndReturn(checkUser(admin.Password))
+
+		t.NewStep("Check result")
+		result, err := abs.usecase.EnsureAdmin(admin)
+		t.Require().NoError(err)
+		t.Require().Equal(&Result{
+			User:     &user.User{ID: 1, Login: admin.Login, Role: types.ADMIN},
+			Password: admin.Password,
+		}, result)
+	})
+
+	t.WithNewStep("Generated password execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		var password string
+		abs.mockUser.EXPECT().CountActiveUsers(types.ADMIN).Return(0, nil)
+		abs.mockUser.EXPECT().CreateUser(gomock.Any()).DoAndReturn(func(usr *user.User) (*user.User, error) {
+			password = usr.Password
+			return &user.User{ID: 1, Login: usr.Login, Role: usr.Role}, nil
+		})
+
+		t.NewStep("Check result")
+		result, err := abs.usecase.EnsureAdmin(Admin{Login: admin.Login})
+		t.Require().NoError(err)
+		t.Require().True(result.Generated)
+		t.Require().Len(result.Password, 24)
+		_, err = auth.NewTestPasswordHasher().Verify(password, result.Password)
+		t.Require().NoError(err)
+	})
+
+	t.WithNewStep("Short password execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		abs.mockUser.EXPECT().CountActiveUsers(types.ADMIN).Return(0, nil)
+
+		t.NewStep("Check result")
+		_, err := abs.usecase.EnsureAdmin(Admin{Login: admin.Login, Password: "admin"})
+		t.Require().ErrorIs(err, ErrorAdminPassword)
+	})
+
+	t.WithNewStep("Login occupied execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		abs.mockUser.EXPECT().CountActiveUsers(types.ADMIN).Return(0, nil).Times(2)
+		abs.mockUser.EXPECT().CreateUser(gomock.Any()).Return(&user.User{ID: 2}, user.ErrorLoginAlreadyExists)
+
+		t.NewStep("Check result")
+		_, err := abs.usecase.EnsureAdmin(admin)
+		t.Require().ErrorIs(err, ErrorLoginOccupied)
+	})
+
+	t.WithNewStep("Created concurrently execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		gomock.InOrder(
+			abs.mockUser.EXPECT().CountActiveUsers(types.ADMIN).Return(0, nil),
+			abs.mockUser.EXPECT().CreateUser(gomock.Any()).Return(&user.User{ID: 2}, user.ErrorLoginAlreadyExists),
+			abs.mockUser.EXPECT().CountActiveUsers(types.ADMIN).Return(1, nil),
+		)
+
+		t.NewStep("Check result")
+		result, err := abs.usecase.EnsureAdmin(admin)
+		t.Require().NoError(err)
+		t.Require().Nil(result)
+	})
+
+	t.WithNewStep("Postgres error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		abs.mockUser.EXPECT().CountActiveUsers(types.ADMIN).Return(0, testError)
+
+		t.NewStep("Check result")
+		_, err := abs.usecase.EnsureAdmin(admin)
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Create error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		abs.mockUser.EXPECT().CountActiveUsers(types.ADMIN).Return(0, nil)
+		abs.mockUser.EXPECT().CreateUser(gomock.Any()).Return(nil, testError)
+
+		t.NewStep("Check result")
+		_, err := abs.usecase.EnsureAdmin(admin)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func TestRunAdminBootstrapSuite(t *testing.T) {
+	suite.RunSuite(t, new(AdminBootstrapSuite))
+}
@@ -0,0 +1,43 @@
+package bootstrap
+
+import (
+	"github.com/pkg/errors"
+	"vk_film/internal/repository/user"
+)
+
+var (
+	ErrorAdminPassword = errors.New("password of initial admin is too short")
+	ErrorLoginOccupied = errors.New("login of initial admin is occupied by another user")
+)
+
+// AdminPasswordMinLength
+// Minimal length of the configured password of the initial admin
+const AdminPasswordMinLength = 12
+
+// Admin
+// Account of the initial admin. Empty password is replaced by a generated one, empty email is not saved.
+type Admin struct {
+	Login    string
+	Password string
+	Email    string
+}
+
+// Result
+// Created admin and his password. Generated is true if the password was not configured
+// and must be shown to the operator, because it is not stored anywhere else.
+type Result struct {
+	User      *user.User
+	Password  string
+	Generated bool
+}
+
+type Usecase interface {
+	// EnsureAdmin
+	// Creates the initial admin only if there are no active admins.
+	// Returns nil result if an admin already exists.
+	// Returns Error:
+	//   - ErrorAdminPassword
+	//   - ErrorLoginOccupied
+	//   - user.ErrorEmailAlreadyExists
+	EnsureAdmin(admin Admin) (*Result, error)
+}
@@ -0,0 +1,93 @@
+package bootstrap
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"github.com/pkg/errors"
+	"unicode/utf8"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/user"
+	"vk_film/internal/usecase/auth"
+)
+
+// generatedPasswordBytes
+// Число случайных байт сгенерированного пароля, в base64 это 24 символа
+const generatedPasswordBytes = 18
+
+type AdminBootstrap struct {
+	users     user.Repository
+	passwords auth.PasswordHasher
+}
+
+func NewAdminBootstrap(users user.Repository, passwords auth.PasswordHasher) *AdminBootstrap {
+	return &AdminBootstrap{
+		users:     users,
+		passwords: passwords,
+	}
+}
+
+var _ = Usecase(&AdminBootstrap{})
+
+func (ab *AdminBootstrap) EnsureAdmin(admin Admin) (*Result, error) {
+	exists, err := ab.adminExists()
+	if err != nil || exists {
+		return nil, err
+	}
+
+	result := &Result{Password: admin.Password}
+	if result.Password == "" {
+		if result.Password, err = generatePassword(); err != nil {
+			return nil, err
+		}
+		result.Generated = true
+	} else if utf8.RuneCountInString(result.Password) < AdminPasswordMinLength {
+		return nil, errors.Wrapf(ErrorAdminPassword, "must be at least %d characters", AdminPasswordMinLength)
+	}
+
+	hash, err := ab.passwords.Hash(result.Password)
+	if err != nil {
+		return nil, errors.Wrapf(err, "try hash password of admin %s", admin.Login)
+	}
+
+	result.User, err = ab.users.CreateUser(&user.User{
+		Login:    admin.Login,
+		Password: hash,
+		Role:     types.ADMIN,
+		Email:    admin.Email,
+	})
+	if err != nil {
+		if !errors.Is(err, user.ErrorLoginAlreadyExists) {
+			return nil, errors.Wrapf(err, "try create admin %s", admin.Login)
+		}
+
+		// Другой экземпляр сервера мог создать администратора одновременно с нами
+		if exists, err := ab.adminExists(); err != nil || exists {
+			return nil, err
+		}
+		return nil, errors.Wrapf(ErrorLoginOccupied, "login %s", admin.Login)
+	}
+
+	return result, nil
+}
+
+// adminExists
+// Проверяет, есть ли активные администраторы
+func (ab *AdminBootstrap) adminExists() (bool, error) {
+	count, err := ab.users.CountActiveUsers(types.ADMIN)
+	if err != nil {
+		return false, errors.Wrap(err, "try count admins")
+	}
+
+	return count > 0, nil
+}
+
+// generatePassword
+// Создаёт случайный пароль для первого входа администратора
+func generatePassword() (string, error) {
+	buf := make([]byte, generatedPasswordBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.Wrap(err, "try generate admin password")
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,59 @@
+package recovery
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"time"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/usecase/auth"
+)
+
+var (
+	ErrorInvalidToken    = errors.New("password reset token is invalid, used or expired")
+	ErrorTooManyRequests = errors.New("too many password reset requests")
+)
+
+// Policy
+// Lifetime of reset tokens and the address of the reset page. The token is appended to ResetURL
+// as the token query parameter, empty ResetURL sends the bare token. The new password is checked
+// by Password before the token is used, so a weak password doesn't waste the token.
+// Reset requests are counted for the user and for the client address during RequestsWindow,
+// zero maximum disables the limit.
+type Policy struct {
+	TokenTTL        time.Duration
+	ResetURL        string
+	Password        auth.PasswordPolicy
+	MaxUserRequests uint64
+	MaxIPRequests   uint64
+	RequestsWindow  time.Duration
+}
+
+var DefaultPolicy = Policy{
+	TokenTTL:        time.Hour,
+	Password:        auth.DefaultPasswordPolicy,
+	MaxUserRequests: 3,
+	MaxIPRequests:   10,
+	RequestsWindow:  time.Hour,
+}
+
+//go:generate mockgen -destination=mocks/usecase.go -package=mrc -mock_names=Usecase=RecoveryUsecase . Usecase
+
+type Usecase interface {
+	// RequestReset
+	// Sends a single-use reset token to the email of the active user found by email or,
+	// if email is empty, by login. The user is found and the email is sent in the background,
+	// so neither the result nor the time of the call reveals which accounts exist. Unknown users,
+	// not active users, users without email and users over the request limit are silently ignored.
+	// Returns Error:
+	//   - ErrorTooManyRequests
+	//   - attempts repository error
+	RequestReset(ctx context.Context, login, email, clientIP string) error
+
+	// ConfirmReset
+	// Uses the token, sets the new password of its user and ends all his sessions.
+	// The client is saved in the security event of the reset.
+	// Returns Error:
+	//   - ErrorInvalidToken
+	//   - auth.ErrorPasswordPolicy
+	ConfirmReset(token, newPassword string, client auth.ClientInfo) (types.Id, error)
+}
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: vk_film/internal/usecase/recovery (interfaces: Usecase)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/usecase.go -package=mrc -mock_names=Usecase=RecoveryUsecase . Usecase
+//
+
+// Package mrc is a generated GoMock package.
+package mrc
+
+import (
+	context "context"
+	reflect "reflect"
+	types "vk_film/internal/pkg/types"
+	auth "vk_film/internal/usecase/auth"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// RecoveryUsecase is a mock of Usecase interface.
+type RecoveryUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *RecoveryUsecaseMockRecorder
+}
+
+// RecoveryUsecaseMockRecorder is the mock recorder for RecoveryUsecase.
+type RecoveryUsecaseMockRecorder struct {
+	mock *RecoveryUsecase
+}
+
+// NewRecoveryUsecase creates a new mock instance.
+func NewRecoveryUsecase(ctrl *gomock.Controller) *RecoveryUsecase {
+	mock := &RecoveryUsecase{ctrl: ctrl}
+	mock.recorder = &RecoveryUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *RecoveryUsecase) EXPECT() *RecoveryUsecaseMockRecorder {
+	return m.recorder
+}
+
+// ConfirmReset mocks base method.
+func (m *RecoveryUsecase) ConfirmReset(arg0, arg1 string, arg2 auth.ClientInfo) (types.Id, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmReset", arg0, arg1, arg2)
+	ret0, _ := ret[0].(types.Id)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmReset indicates an expected call of ConfirmReset.
+func (mr *RecoveryUsecaseMockRecorder) ConfirmReset(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmReset", reflect.TypeOf((*RecoveryUsecase)(nil).ConfirmReset), arg0, arg1, arg2)
+}
+
+// RequestReset mocks base method.
+func (m *RecoveryUsecase) RequestReset(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestReset", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestReset indicates an expected call of RequestReset.
+func (mr *RecoveryUsecaseMockRecorder) RequestReset(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestReset", reflect.TypeOf((*RecoveryUsecase)(nil).RequestReset), arg0, arg1, arg2, arg3)
+}
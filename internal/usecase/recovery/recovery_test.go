@@ -0,0 +1,322 @@
+package recovery
+
+import (
+	"context"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+	"vk_film/internal/pkg/notify"
+	"vk_film/internal/pkg/types"
+	mra "vk_film/internal/repository/attempts/mocks"
+	"vk_film/internal/repository/reset"
+	mrr "vk_film/internal/repository/reset/mocks"
+	"vk_film/internal/repository/user"
+	mru "vk_film/internal/repository/user/mocks"
+	"vk_film/internal/usecase/auth"
+	mu "vk_film/internal/usecase/auth/mocks"
+	"vk_film/pkg/logger"
+)
+
+var testError = errors.New("test error")
+
+// testNotifier
+// Запоминает отправленные сообщения
+type testNotifier struct {
+	messages []notify.Message
+	err      error
+}
+
+func (tn *testNotifier) Send(_ context.Context, msg notify.Message) error {
+	tn.messages = append(tn.messages, msg)
+	return tn.err
+}
+
+// blockingNotifier
+// Отправляет сообщение только после закрытия release и проверяет, что контекст не отменён
+type blockingNotifier struct {
+	release chan struct{}
+	sent    chan notify.Message
+}
+
+func (bn *blockingNotifier) Send(ctx context.Context, msg notify.Message) error {
+	<-bn.release
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	bn.sent <- msg
+	return nil
+}
+
+// testLogger
+// Запоминает ошибки, записанные в лог
+type testLogger struct {
+	errors []any
+}
+
+func (*testLogger) Debug(_ any, _ ...any)                          {}
+func (*testLogger) Info(_ any, _ ...any)                           {}
+func (*testLogger) Warn(_ any, _ ...any)                           {}
+func (tl *testLogger) Error(message any, _ ...any)                 { tl.errors = append(tl.errors, message) }
+func (*testLogger) Panic(_ any, _ ...any)                          {}
+func (*testLogger) Fatal(_ any, _ ...any)                          {}
+func (tl *testLogger) With(_ logger.Field, _ any) logger.Interface { return tl }
+
+type RecoveryUsecaseSuite struct {
+	suite.Suite
+	usecase      *RecoveryUsecase
+	mockUser     *mru.UserRepository
+	mockReset    *mrr.ResetRepository
+	mockManager  *mu.SessionManager
+	mockAttempts *mra.AttemptsRepository
+	notifier     *testNotifier
+	l            *testLogger
+	gmc          *gomock.Controller
+}
+
+func (rus *RecoveryUsecaseSuite) BeforeEach(t provider.T) {
+	rus.gmc = gomock.NewController(t)
+	rus.mockUser = mru.NewUserRepository(rus.gmc)
+	rus.mockReset = mrr.NewResetRepository(rus.gmc)
+	rus.mockManager = mu.NewSessionManager(rus.gmc)
+	rus.mockAttempts = mra.NewAttemptsRepository(rus.gmc)
+	rus.notifier = &testNotifier{}
+	rus.l = &testLogger{}
+
+	policy := DefaultPolicy
+	policy.ResetURL = "https://films.example.com/reset"
+	rus.usecase = NewRecoveryUsecase(rus.mockUser, rus.mockReset, rus.mockManager, rus.mockAttempts, rus.notifier,
+		policy, rus.l)
+	rus.usecase.async = func(f func()) { f() }
+}
+
+func (rus *RecoveryUsecaseSuite) AfterEach(t provider.T) {
+	rus.gmc.Finish()
+}
+
+func (rus *RecoveryUsecaseSuite) TestRequestResetFunction(t provider.T) {
+	t.Title("RequestReset function of Recovery usecase")
+	t.NewStep("Init test data")
+	ctx := context.Background()
+	clientIP := "127.0.0.1"
+	active := &user.User{ID: 1, Login: "user", Email: "user@example.com", Role: types.USER, Status: user.StatusActive}
+
+	expectIPRequest := func(count uint64) {
+		rus.mockAttempts.EXPECT().Add("reset-ip:"+clientIP, DefaultPolicy.RequestsWindow).Return(count, nil)
+	}
+	expectUserRequest := func(count uint64) {
+		rus.mockAttempts.EXPECT().Add("reset-user:1", DefaultPolicy.RequestsWindow).Return(count, nil)
+	}
+
+	t.WithNewStep("Correct execute by email", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		var savedHash string
+		expectIPRequest(1)
+		rus.mockUser.EXPECT().GetUserByEmail(active.Email).Return(active, nil)
+		expectUserRequest(1)
+		rus.mockReset.EXPECT().CreateToken(active.ID, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ types.Id, hash string, expiresAt time.Time) error {
+				savedHash = hash
+				t.Require().WithinDuration(time.Now().Add(DefaultPolicy.TokenTTL), expiresAt, time.Minute)
+				return nil
+			})
+
+		t.NewStep("Check result")
+		t.Require().NoError(rus.usecase.RequestReset(ctx, "", "User@Example.com", clientIP))
+		t.Require().Len(rus.notifier.messages, 1)
+
+		msg := rus.notifier.messages[0]
+		t.Require().Equal(active.Email, msg.To)
+		_, link, found := strings.Cut(msg.Body, "https://films.example.com/reset?token=")
+		t.Require().True(found)
+		token := strings.Fields(link)[0]
+		t.Require().True(strings.HasPrefix(token, TokenPrefix))
+		t.Require().Equal(hashToken(token), savedHash)
+		rus.notifier.messages = nil
+	})
+
+	t.WithNewStep("Correct execute by login", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectIPRequest(1)
+		rus.mockUser.EXPECT().GetUserByLogin(active.Login).Return(active, nil)
+		expectUserRequest(1)
+		rus.mockReset.EXPECT().CreateToken(active.ID, gomock.Any(), gomock.Any()).Return(nil)
+
+		t.NewStep("Check result")
+		t.Require().NoError(rus.usecase.RequestReset(ctx, active.Login, "", clientIP))
+		t.Require().Len(rus.notifier.messages, 1)
+		rus.notifier.messages = nil
+	})
+
+	for _, ignoredCase := range []struct {
+		name string
+		usr  *user.User
+		err  error
+	}{
+		{"Unknown user", nil, user.ErrorUserNotFound},
+		{"Pending user", &user.User{ID: 2, Email: "pending@example.com", Status: user.StatusPending}, nil},
+		{"User without email", &user.User{ID: 3, Status: user.StatusActive}, nil},
+	} {
+		t.WithNewStep(ignoredCase.name+" execute", func(t provider.StepCtx) {
+			t.NewStep("Init mock")
+			expectIPRequest(1)
+			rus.mockUser.EXPECT().GetUserByLogin("someone").Return(ignoredCase.usr, ignoredCase.err)
+
+			t.NewStep("Check result")
+			t.Require().NoError(rus.usecase.RequestReset(ctx, "someone", "", clientIP))
+			t.Require().Empty(rus.notifier.messages)
+			t.Require().Empty(rus.l.errors)
+		})
+	}
+
+	t.WithNewStep("Too many requests for user execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectIPRequest(1)
+		rus.mockUser.EXPECT().GetUserByLogin(active.Login).Return(active, nil)
+		expectUserRequest(DefaultPolicy.MaxUserRequests + 1)
+
+		t.NewStep("Check result")
+		t.Require().NoError(rus.usecase.RequestReset(ctx, active.Login, "", clientIP))
+		t.Require().Empty(rus.notifier.messages)
+	})
+
+	t.WithNewStep("Too many requests from client execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectIPRequest(DefaultPolicy.MaxIPRequests + 1)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rus.usecase.RequestReset(ctx, active.Login, "", clientIP), ErrorTooManyRequests)
+		t.Require().Empty(rus.notifier.messages)
+	})
+
+	t.WithNewStep("Without client address execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rus.mockUser.EXPECT().GetUserByLogin("someone").Return(nil, user.ErrorUserNotFound)
+
+		t.NewStep("Check result")
+		t.Require().NoError(rus.usecase.RequestReset(ctx, "someone", "", ""))
+	})
+
+	t.WithNewStep("Attempts repository error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rus.mockAttempts.EXPECT().Add("reset-ip:"+clientIP, DefaultPolicy.RequestsWindow).Return(uint64(0), testError)
+
+		t.NewStep("Check result")
+		t.Require().ErrorIs(rus.usecase.RequestReset(ctx, active.Login, "", clientIP), testError)
+	})
+
+	t.WithNewStep("Repository error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		expectIPRequest(1)
+		rus.mockUser.EXPECT().GetUserByLogin(active.Login).Return(active, nil)
+		expectUserRequest(1)
+		rus.mockReset.EXPECT().CreateToken(active.ID, gomock.Any(), gomock.Any()).Return(testError)
+
+		t.NewStep("Check result")
+		t.Require().NoError(rus.usecase.RequestReset(ctx, active.Login, "", clientIP))
+		t.Require().Empty(rus.notifier.messages)
+		t.Require().Len(rus.l.errors, 1)
+		t.Require().ErrorIs(rus.l.errors[0].(error), testError)
+		rus.l.errors = nil
+	})
+
+	t.WithNewStep("Notifier error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rus.notifier.err = testError
+		expectIPRequest(1)
+		rus.mockUser.EXPECT().GetUserByLogin(active.Login).Return(active, nil)
+		expectUserRequest(1)
+		rus.mockReset.EXPECT().CreateToken(active.ID, gomock.Any(), gomock.Any()).Return(nil)
+
+		t.NewStep("Check result")
+		t.Require().NoError(rus.usecase.RequestReset(ctx, active.Login, "", clientIP))
+		t.Require().Len(rus.l.errors, 1)
+		t.Require().ErrorIs(rus.l.errors[0].(error), testError)
+	})
+}
+
+func (rus *RecoveryUsecaseSuite) TestRequestResetInBackgroundFunction(t provider.T) {
+	t.Title("RequestReset function of Recovery usecase sends email in background")
+	t.NewStep("Init test data")
+	active := &user.User{ID: 1, Login: "user", Email: "user@example.com", Status: user.StatusActive}
+	notifier := &blockingNotifier{release: make(chan struct{}), sent: make(chan notify.Message, 1)}
+	usecase := NewRecoveryUsecase(rus.mockUser, rus.mockReset, rus.mockManager, nil, notifier, DefaultPolicy, rus.l)
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rus.mockUser.EXPECT().GetUserByLogin(active.Login).Return(active, nil)
+		rus.mockReset.EXPECT().CreateToken(active.ID, gomock.Any(), gomock.Any()).Return(nil)
+
+		t.NewStep("Check result")
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Require().NoError(usecase.RequestReset(ctx, active.Login, "", ""))
+
+		// Ответ не ждёт письма, а отмена запроса не отменяет отправку
+		cancel()
+		close(notifier.release)
+		select {
+		case msg := <-notifier.sent:
+			t.Require().Equal(active.Email, msg.To)
+		case <-time.After(5 * time.Second):
+			t.Breakf("reset email is not sent")
+		}
+	})
+}
+
+func (rus *RecoveryUsecaseSuite) TestConfirmResetFunction(t provider.T) {
+	t.Title("ConfirmReset function of Recovery usecase")
+	t.NewStep("Init test data")
+	var userId types.Id = 1
+	token := TokenPrefix + "secret"
+	password := "New-password"
+	client := auth.ClientInfo{IP: "127.0.0.1", UserAgent: "curl/8.0"}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rus.mockReset.EXPECT().UseToken(hashToken(token)).Return(userId, nil)
+		rus.mockManager.EXPECT().ResetPassword(userId, password, client).Return(nil)
+
+		t.NewStep("Check result")
+		id, err := rus.usecase.ConfirmReset(token, password, client)
+		t.Require().NoError(err)
+		t.Require().Equal(userId, id)
+	})
+
+	t.WithNewStep("Used or expired token execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rus.mockReset.EXPECT().UseToken(hashToken(token)).Return(types.Id(0), reset.ErrorTokenNotFound)
+
+		t.NewStep("Check result")
+		_, err := rus.usecase.ConfirmReset(token, password, client)
+		t.Require().ErrorIs(err, ErrorInvalidToken)
+	})
+
+	t.WithNewStep("Token without prefix execute", func(t provider.StepCtx) {
+		t.NewStep("Check result")
+		_, err := rus.usecase.ConfirmReset("secret", password, client)
+		t.Require().ErrorIs(err, ErrorInvalidToken)
+	})
+
+	t.WithNewStep("Weak password execute", func(t provider.StepCtx) {
+		t.NewStep("Check result")
+		_, err := rus.usecase.ConfirmReset(token, "password", client)
+		t.Require().ErrorIs(err, auth.ErrorPasswordPolicy)
+	})
+
+	t.WithNewStep("Reset password error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rus.mockReset.EXPECT().UseToken(hashToken(token)).Return(userId, nil)
+		rus.mockManager.EXPECT().ResetPassword(userId, password, client).Return(testError)
+
+		t.NewStep("Check result")
+		_, err := rus.usecase.ConfirmReset(token, password, client)
+		t.Require().ErrorIs(err, testError)
+	})
+}
+
+func TestRunRecoveryUsecaseSuite(t *testing.T) {
+	suite.RunSuite(t, new(RecoveryUsecaseSuite))
+}
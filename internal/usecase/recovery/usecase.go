@@ -0,0 +1,198 @@
+package recovery
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/pkg/errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"vk_film/internal/pkg/notify"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/attempts"
+	"vk_film/internal/repository/reset"
+	"vk_film/internal/repository/user"
+	"vk_film/internal/usecase/auth"
+	"vk_film/pkg/logger"
+)
+
+const (
+	TokenPrefix = "vkp_"
+	tokenLength = 32
+
+	userRequestsPrefix = "reset-user:"
+	ipRequestsPrefix   = "reset-ip:"
+)
+
+type RecoveryUsecase struct {
+	users    user.Repository
+	resets   reset.Repository
+	manager  auth.Manager
+	attempts attempts.Repository
+	notifier notify.Notifier
+	policy   Policy
+	l        logger.Interface
+	async    func(func())
+}
+
+// NewRecoveryUsecase
+// Nil attempts repository disables the limits of reset requests.
+// Reset emails are sent in the background, their failures are reported to l
+func NewRecoveryUsecase(users user.Repository, resets reset.Repository, manager auth.Manager,
+	attempts attempts.Repository, notifier notify.Notifier, policy Policy, l logger.Interface) *RecoveryUsecase {
+	return &RecoveryUsecase{
+		users:    users,
+		resets:   resets,
+		manager:  manager,
+		attempts: attempts,
+		notifier: notifier,
+		policy:   policy,
+		l:        l,
+		async:    func(f func()) { go f() },
+	}
+}
+
+var _ = Usecase(&RecoveryUsecase{})
+
+func (ru *RecoveryUsecase) RequestReset(ctx context.Context, login, email, clientIP string) error {
+	// Ограничение по адресу клиента не зависит от пользователя, поэтому проверяется до ответа
+	if clientIP != "" {
+		limited, err := ru.throttle(ipRequestsPrefix+clientIP, ru.policy.MaxIPRequests)
+		if err != nil {
+			return err
+		}
+		if limited {
+			return errors.Wrapf(ErrorTooManyRequests, "client %s", clientIP)
+		}
+	}
+
+	// Поиск пользователя и отправка письма выполняются после ответа, чтобы время ответа
+	// не зависело от существования и состояния учётной записи
+	ctx = context.WithoutCancel(ctx)
+	ru.async(func() {
+		if err := ru.sendReset(ctx, login, email); err != nil {
+			ru.l.Error(errors.Wrapf(err, "can't send password reset"))
+		}
+	})
+
+	return nil
+}
+
+// sendReset
+// Находит активного пользователя с почтой и отправляет ему код сброса
+func (ru *RecoveryUsecase) sendReset(ctx context.Context, login, email string) error {
+	var usr *user.User
+	var err error
+	if email != "" {
+		usr, err = ru.users.GetUserByEmail(strings.ToLower(email))
+	} else {
+		usr, err = ru.users.GetUserByLogin(login)
+	}
+	if err != nil {
+		if errors.Is(err, user.ErrorUserNotFound) {
+			return nil
+		}
+		return errors.Wrap(err, "try find user for password reset")
+	}
+
+	if usr.Status != user.StatusActive || usr.Email == "" {
+		return nil
+	}
+
+	limited, err := ru.throttle(userRequestsPrefix+strconv.FormatUint(uint64(usr.ID), 10), ru.policy.MaxUserRequests)
+	if err != nil {
+		return err
+	}
+	if limited {
+		ru.l.Warn("[Security] password reset of user %d is not sent, too many requests", usr.ID)
+		return nil
+	}
+
+	secret := make([]byte, tokenLength)
+	if _, err = rand.Read(secret); err != nil {
+		return errors.Wrapf(err, "try generate reset token for user %d", usr.ID)
+	}
+	token := TokenPrefix + hex.EncodeToString(secret)
+
+	if err = ru.resets.CreateToken(usr.ID, hashToken(token), time.Now().Add(ru.policy.TokenTTL)); err != nil {
+		return errors.Wrapf(err, "try save reset token for user %d", usr.ID)
+	}
+
+	if err = ru.notifier.Send(ctx, ru.message(usr, token)); err != nil {
+		return errors.Wrapf(err, "try send reset token to user %d", usr.ID)
+	}
+
+	return nil
+}
+
+func (ru *RecoveryUsecase) ConfirmReset(token, newPassword string, client auth.ClientInfo) (types.Id, error) {
+	if !strings.HasPrefix(token, TokenPrefix) {
+		return 0, ErrorInvalidToken
+	}
+
+	if err := ru.policy.Password.Check(newPassword); err != nil {
+		return 0, err
+	}
+
+	userId, err := ru.resets.UseToken(hashToken(token))
+	if err != nil {
+		if errors.Is(err, reset.ErrorTokenNotFound) {
+			return 0, ErrorInvalidToken
+		}
+		return 0, errors.Wrap(err, "try use reset token")
+	}
+
+	if err = ru.manager.ResetPassword(userId, newPassword, client); err != nil {
+		return 0, errors.Wrapf(err, "try reset password of user %d", userId)
+	}
+
+	return userId, nil
+}
+
+// throttle
+// Учитывает запрос сброса для ключа и сообщает, превышено ли их число за окно
+func (ru *RecoveryUsecase) throttle(key string, maxRequests uint64) (bool, error) {
+	if ru.attempts == nil || maxRequests == 0 {
+		return false, nil
+	}
+
+	count, err := ru.attempts.Add(key, ru.policy.RequestsWindow)
+	if err != nil {
+		return false, errors.Wrapf(err, "try count password reset requests for %s", key)
+	}
+
+	return count > maxRequests, nil
+}
+
+// message
+// Составляет письмо со ссылкой на страницу сброса пароля или с самим токеном
+func (ru *RecoveryUsecase) message(usr *user.User, token string) notify.Message {
+	instruction := fmt.Sprintf("Код для сброса пароля:\n%s", token)
+	if ru.policy.ResetURL != "" {
+		separator := "?"
+		if strings.Contains(ru.policy.ResetURL, "?") {
+			separator = "&"
+		}
+		instruction = fmt.Sprintf("Для сброса пароля перейдите по ссылке:\n%s%stoken=%s",
+			ru.policy.ResetURL, separator, url.QueryEscape(token))
+	}
+
+	return notify.Message{
+		To:      usr.Email,
+		Subject: "Сброс пароля",
+		Body: fmt.Sprintf("Здравствуйте, %s!\n\n%s\n\nСрок действия %d мин., воспользоваться можно только один раз. "+
+			"Если вы не запрашивали сброс пароля, просто проигнорируйте это письмо.",
+			usr.Login, instruction, int(ru.policy.TokenTTL.Minutes())),
+	}
+}
+
+// hashToken
+// Хеширует токен сброса, в базе данных хранится только хеш
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
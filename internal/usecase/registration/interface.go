@@ -0,0 +1,68 @@
+package registration
+
+import (
+	"github.com/pkg/errors"
+	"regexp"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/user"
+	"vk_film/internal/usecase/auth"
+)
+
+var (
+	ErrorRegistrationDisabled = errors.New("registration is disabled")
+	ErrorLoginPolicy          = errors.New("login does not satisfy the policy")
+	ErrorPasswordPolicy       = auth.ErrorPasswordPolicy
+)
+
+// Policy
+// Rules of self-registration. Disabled registration rejects new accounts, but already pending
+// accounts can still be approved or rejected. Nil LoginPattern allows any characters.
+type Policy struct {
+	Enabled        bool
+	DefaultRole    types.Roles
+	LoginMinLength int
+	LoginMaxLength int
+	LoginPattern   *regexp.Regexp
+	Password       auth.PasswordPolicy
+}
+
+var DefaultPolicy = Policy{
+	DefaultRole:    types.USER,
+	LoginMinLength: 3,
+	LoginMaxLength: 32,
+	LoginPattern:   regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`),
+	Password:       auth.DefaultPasswordPolicy,
+}
+
+//go:generate mockgen -destination=mocks/usecase.go -package=mrg -mock_names=Usecase=RegistrationUsecase . Usecase
+
+type Usecase interface {
+	// Register
+	// Creates the pending user with the default role, he can't log in until approval.
+	// Email is optional and is used for password reset.
+	// Returns Error:
+	//   - ErrorRegistrationDisabled
+	//   - ErrorLoginPolicy
+	//   - ErrorPasswordPolicy
+	//   - user.ErrorLoginAlreadyExists
+	//   - user.ErrorEmailAlreadyExists
+	Register(login, password, email string) (*user.User, error)
+
+	// GetPending
+	// Returns the queue of users waiting for approval in order of registration
+	GetPending() ([]user.User, error)
+
+	// Approve
+	// Activates the pending user.
+	// Returns Error:
+	//   - user.ErrorUserNotFound
+	//   - user.ErrorStatusMismatch
+	Approve(userId types.Id) (*user.User, error)
+
+	// Reject
+	// Rejects the pending user, his login stays occupied until the user is deleted.
+	// Returns Error:
+	//   - user.ErrorUserNotFound
+	//   - user.ErrorStatusMismatch
+	Reject(userId types.Id) (*user.User, error)
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: vk_film/internal/usecase/registration (interfaces: Usecase)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/usecase.go -package=mrg -mock_names=Usecase=RegistrationUsecase . Usecase
+//
+
+// Package mrg is a generated GoMock package.
+package mrg
+
+import (
+	reflect "reflect"
+	types "vk_film/internal/pkg/types"
+	user "vk_film/internal/repository/user"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// RegistrationUsecase is a mock of Usecase interface.
+type RegistrationUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *RegistrationUsecaseMockRecorder
+}
+
+// RegistrationUsecaseMockRecorder is the mock recorder for RegistrationUsecase.
+type RegistrationUsecaseMockRecorder struct {
+	mock *RegistrationUsecase
+}
+
+// NewRegistrationUsecase creates a new mock instance.
+func NewRegistrationUsecase(ctrl *gomock.Controller) *RegistrationUsecase {
+	mock := &RegistrationUsecase{ctrl: ctrl}
+	mock.recorder = &RegistrationUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *RegistrationUsecase) EXPECT() *RegistrationUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Approve mocks base method.
+func (m *RegistrationUsecase) Approve(arg0 types.Id) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Approve", arg0)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Approve indicates an expected call of Approve.
+func (mr *RegistrationUsecaseMockRecorder) Approve(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Approve", reflect.TypeOf((*RegistrationUsecase)(nil).Approve), arg0)
+}
+
+// GetPending mocks base method.
+func (m *RegistrationUsecase) GetPending() ([]user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPending")
+	ret0, _ := ret[0].([]user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPending indicates an expected call of GetPending.
+func (mr *RegistrationUsecaseMockRecorder) GetPending() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPending", reflect.TypeOf((*RegistrationUsecase)(nil).GetPending))
+}
+
+// Register mocks base method.
+func (m *RegistrationUsecase) Register(arg0, arg1, arg2 string) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Register indicates an expected call of Register.
+func (mr *RegistrationUsecaseMockRecorder) Register(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*RegistrationUsecase)(nil).Register), arg0, arg1, arg2)
+}
+
+// Reject mocks base method.
+func (m *RegistrationUsecase) Reject(arg0 types.Id) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reject", arg0)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reject indicates an expected call of Reject.
+func (mr *RegistrationUsecaseMockRecorder) Reject(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reject", reflect.TypeOf((*RegistrationUsecase)(nil).Reject), arg0)
+}
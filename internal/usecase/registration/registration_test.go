@@ -0,0 +1,166 @@
+package registration
+
+import (
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/user"
+	mru "vk_film/internal/repository/user/mocks"
+	"vk_film/internal/usecase/auth"
+)
+
+var testError = errors.New("test error")
+
+type RegistrationUsecaseSuite struct {
+	suite.Suite
+	usecase  *RegistrationUsecase
+	mockUser *mru.UserRepository
+	gmc      *gomock.Controller
+}
+
+func (rus *RegistrationUsecaseSuite) BeforeEach(t provider.T) {
+	rus.gmc = gomock.NewController(t)
+	rus.mockUser = mru.NewUserRepository(rus.gmc)
+
+	policy := DefaultPolicy
+	policy.Enabled = true
+	rus.usecase = NewRegistrationUsecase(rus.mockUser, auth.NewTestPasswordHasher(), policy)
+}
+
+func (rus *RegistrationUsecaseSuite) AfterEach(t provider.T) {
+	rus.gmc.Finish()
+}
+
+func (rus *RegistrationUsecaseSuite) TestRegisterFunction(t provider.T) {
+	t.Title("Register function of Registration usecase")
+	t.NewStep("Init test data")
+	login := "new.user"
+	password := "Secret-password"
+	email := "new.user@example.com"
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rus.mockUser.EXPECT().CreateUser(gomock.Any()).DoAndReturn(func(usr *user.User) (*user.User, error) {
+			t.Require().Equal(login, usr.Login)
+			t.Require().Equal(types.USER, usr.Role)
+			t.Require().Equal(user.StatusPending, usr.Status)
+			t.Require().Equal(email, usr.Email)
+			outdated, err := auth.NewTestPasswordHasher().Verify(usr.Password, password)
+			t.Require().NoError(err)
+			t.Require().False(outdated)
+			return &user.User{ID: 1, Login: usr.Login, Role: usr.Role}, nil
+		})
+
+		t.NewStep("Check result")
+		usr, err := rus.usecase.Register(login, password, email)
+		t.Require().NoError(err)
+		t.Require().Equal(&user.User{ID: 1, Login: login, Role: types.USER, Status: user.StatusPending}, usr)
+	})
+
+	t.WithNewStep("Login already exists execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rus.mockUser.EXPECT().CreateUser(gomock.Any()).Return(&user.User{ID: 1}, user.ErrorLoginAlreadyExists)
+
+		t.NewStep("Check result")
+		_, err := rus.usecase.Register(login, password, "")
+		t.Require().ErrorIs(err, user.ErrorLoginAlreadyExists)
+	})
+
+	t.WithNewStep("Registration disabled execute", func(t provider.StepCtx) {
+		t.NewStep("Check result")
+		_, err := NewRegistrationUsecase(rus.mockUser, auth.NewTestPasswordHasher(), DefaultPolicy).Register(login, password, "")
+		t.Require().ErrorIs(err, ErrorRegistrationDisabled)
+	})
+
+	t.WithNewStep("Login policy violations execute", func(t provider.StepCtx) {
+		for _, incorrect := range []string{"ab", strings.Repeat("a", 33), "user name", "логин"} {
+			_, err := rus.usecase.Register(incorrect, password, "")
+			t.Require().ErrorIs(err, ErrorLoginPolicy, incorrect)
+		}
+	})
+
+	t.WithNewStep("Password policy violations execute", func(t provider.StepCtx) {
+		for _, incorrect := range []string{"Short-1", "onlylowercase", strings.Repeat("Aa1", 25)} {
+			_, err := rus.usecase.Register(login, incorrect, "")
+			t.Require().ErrorIs(err, ErrorPasswordPolicy, incorrect)
+		}
+	})
+}
+
+func (rus *RegistrationUsecaseSuite) TestQueueFunctions(t provider.T) {
+	t.Title("Approval queue of Registration usecase")
+	t.NewStep("Init test data")
+	var userId types.Id = 1
+	pending := user.User{ID: userId, Login: "new.user", Role: types.USER, Status: user.StatusPending}
+
+	t.WithNewStep("Get pending users execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rus.mockUser.EXPECT().GetUsersByStatus(user.StatusPending).Return([]user.User{pending}, nil)
+
+		t.NewStep("Check result")
+		users, err := rus.usecase.GetPending()
+		t.Require().NoError(err)
+		t.Require().Equal([]user.User{pending}, users)
+	})
+
+	t.WithNewStep("Get pending users error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rus.mockUser.EXPECT().GetUsersByStatus(user.StatusPending).Return(nil, testError)
+
+		t.NewStep("Check result")
+		_, err := rus.usecase.GetPending()
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Approve execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		approved := pending
+		approved.Status = user.StatusActive
+		rus.mockUser.EXPECT().UpdateUserStatus(userId, user.StatusPending, user.StatusActive).Return(&approved, nil)
+
+		t.NewStep("Check result")
+		usr, err := rus.usecase.Approve(userId)
+		t.Require().NoError(err)
+		t.Require().Equal(&approved, usr)
+	})
+
+	t.WithNewStep("Approve not pending user execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rus.mockUser.EXPECT().UpdateUserStatus(userId, user.StatusPending, user.StatusActive).
+			Return(nil, user.ErrorStatusMismatch)
+
+		t.NewStep("Check result")
+		_, err := rus.usecase.Approve(userId)
+		t.Require().ErrorIs(err, user.ErrorStatusMismatch)
+	})
+
+	t.WithNewStep("Reject execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rejected := pending
+		rejected.Status = user.StatusRejected
+		rus.mockUser.EXPECT().UpdateUserStatus(userId, user.StatusPending, user.StatusRejected).Return(&rejected, nil)
+
+		t.NewStep("Check result")
+		usr, err := rus.usecase.Reject(userId)
+		t.Require().NoError(err)
+		t.Require().Equal(&rejected, usr)
+	})
+
+	t.WithNewStep("Reject unknown user execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		rus.mockUser.EXPECT().UpdateUserStatus(userId, user.StatusPending, user.StatusRejected).
+			Return(nil, user.ErrorUserNotFound)
+
+		t.NewStep("Check result")
+		_, err := rus.usecase.Reject(userId)
+		t.Require().ErrorIs(err, user.ErrorUserNotFound)
+	})
+}
+
+func TestRunRegistrationUsecaseSuite(t *testing.T) {
+	suite.RunSuite(t, new(RegistrationUsecaseSuite))
+}
@@ -0,0 +1,101 @@
+package registration
+
+import (
+	"github.com/pkg/errors"
+	"unicode/utf8"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/user"
+	"vk_film/internal/usecase/auth"
+)
+
+type RegistrationUsecase struct {
+	users     user.Repository
+	passwords auth.PasswordHasher
+	policy    Policy
+}
+
+func NewRegistrationUsecase(users user.Repository, passwords auth.PasswordHasher, policy Policy) *RegistrationUsecase {
+	return &RegistrationUsecase{
+		users:     users,
+		passwords: passwords,
+		policy:    policy,
+	}
+}
+
+var _ = Usecase(&RegistrationUsecase{})
+
+func (ru *RegistrationUsecase) Register(login, password, email string) (*user.User, error) {
+	if !ru.policy.Enabled {
+		return nil, ErrorRegistrationDisabled
+	}
+
+	if err := ru.checkLogin(login); err != nil {
+		return nil, err
+	}
+
+	if err := ru.policy.Password.Check(password); err != nil {
+		return nil, err
+	}
+
+	hash, err := ru.passwords.Hash(password)
+	if err != nil {
+		return nil, errors.Wrapf(err, "try hash password of user %s", login)
+	}
+
+	created, err := ru.users.CreateUser(&user.User{
+		Login:    login,
+		Password: hash,
+		Role:     ru.policy.DefaultRole,
+		Status:   user.StatusPending,
+		Email:    email,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "try create pending user %s", login)
+	}
+	created.Status = user.StatusPending
+
+	return created, nil
+}
+
+func (ru *RegistrationUsecase) GetPending() ([]user.User, error) {
+	users, err := ru.users.GetUsersByStatus(user.StatusPending)
+	if err != nil {
+		return nil, errors.Wrap(err, "try get pending users")
+	}
+
+	return users, nil
+}
+
+func (ru *RegistrationUsecase) Approve(userId types.Id) (*user.User, error) {
+	usr, err := ru.users.UpdateUserStatus(userId, user.StatusPending, user.StatusActive)
+	if err != nil {
+		return nil, errors.Wrapf(err, "try approve user %d", userId)
+	}
+
+	return usr, nil
+}
+
+func (ru *RegistrationUsecase) Reject(userId types.Id) (*user.User, error) {
+	usr, err := ru.users.UpdateUserStatus(userId, user.StatusPending, user.StatusRejected)
+	if err != nil {
+		return nil, errors.Wrapf(err, "try reject user %d", userId)
+	}
+
+	return usr, nil
+}
+
+// checkLogin
+// Проверяет длину и допустимые символы логина
+func (ru *RegistrationUsecase) checkLogin(login string) error {
+	length := utf8.RuneCountInString(login)
+	if length < ru.policy.LoginMinLength || length > ru.policy.LoginMaxLength {
+		return errors.Wrapf(ErrorLoginPolicy, "length must be from %d to %d characters",
+			ru.policy.LoginMinLength, ru.policy.LoginMaxLength)
+	}
+
+	if ru.policy.LoginPattern != nil && !ru.policy.LoginPattern.MatchString(login) {
+		return errors.Wrapf(ErrorLoginPolicy, "must match %s", ru.policy.LoginPattern)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package sso
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/usecase/auth"
+)
+
+var (
+	ErrorAuthorizationFailed = errors.New("authorization in identity provider failed")
+	ErrorNoRole              = errors.New("identity has no role in the application")
+	ErrorNoLogin             = errors.New("identity has no login claim")
+	ErrorLoginAlreadyExists  = errors.New("login of identity is already used by another user")
+)
+
+// Authorization
+// Parameters of the started authorization. State, Nonce and Verifier must be kept
+// by the client until the provider redirects it back with the authorization code.
+type Authorization struct {
+	URL      string
+	State    string
+	Nonce    string
+	Verifier string
+}
+
+// RoleMapping
+// Role granted to users whose role claim contains Value
+type RoleMapping struct {
+	Value string
+	Role  types.Roles
+}
+
+// Policy
+// Mapping of identity claims to users. Mappings are checked in order, the first matched
+// mapping grants its role. Without matched mapping DefaultRole is granted, empty DefaultRole
+// denies access.
+type Policy struct {
+	LoginClaim  string
+	RoleClaim   string
+	Roles       []RoleMapping
+	DefaultRole types.Roles
+}
+
+//go:generate mockgen -destination=mocks/usecase.go -package=ms -mock_names=Usecase=SSOUsecase . Usecase
+
+type Usecase interface {
+	// Begin
+	// Starts authorization with PKCE and returns the address of the provider authorization page.
+	// Returns Error:
+	//   - ErrorAuthorizationFailed
+	Begin(ctx context.Context) (*Authorization, error)
+
+	// Complete
+	// Exchanges the authorization code, verifies the id token, provisions the user on first login,
+	// updates his role by the role claim and issues credentials.
+	// Returns Error:
+	//   - ErrorAuthorizationFailed
+	//   - ErrorNoRole
+	//   - ErrorNoLogin
+	//   - ErrorLoginAlreadyExists
+	Complete(ctx context.Context, code string, authorization *Authorization,
+		client auth.ClientInfo) (*auth.Credentials, types.Id, error)
+}
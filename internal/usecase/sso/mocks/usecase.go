@@ -0,0 +1,74 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: vk_film/internal/usecase/sso (interfaces: Usecase)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/usecase.go -package=ms -mock_names=Usecase=SSOUsecase . Usecase
+//
+
+// Package ms is a generated GoMock package.
+package ms
+
+import (
+	context "context"
+	reflect "reflect"
+	types "vk_film/internal/pkg/types"
+	auth "vk_film/internal/usecase/auth"
+	sso "vk_film/internal/usecase/sso"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// SSOUsecase is a mock of Usecase interface.
+type SSOUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *SSOUsecaseMockRecorder
+}
+
+// SSOUsecaseMockRecorder is the mock recorder for SSOUsecase.
+type SSOUsecaseMockRecorder struct {
+	mock *SSOUsecase
+}
+
+// NewSSOUsecase creates a new mock instance.
+func NewSSOUsecase(ctrl *gomock.Controller) *SSOUsecase {
+	mock := &SSOUsecase{ctrl: ctrl}
+	mock.recorder = &SSOUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *SSOUsecase) EXPECT() *SSOUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *SSOUsecase) Begin(arg0 context.Context) (*sso.Authorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", arg0)
+	ret0, _ := ret[0].(*sso.Authorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *SSOUsecaseMockRecorder) Begin(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*SSOUsecase)(nil).Begin), arg0)
+}
+
+// Complete mocks base method.
+func (m *SSOUsecase) Complete(arg0 context.Context, arg1 string, arg2 *sso.Authorization, arg3 auth.ClientInfo) (*auth.Credentials, types.Id, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*auth.Credentials)
+	ret1, _ := ret[1].(types.Id)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *SSOUsecaseMockRecorder) Complete(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*SSOUsecase)(nil).Complete), arg0, arg1, arg2, arg3)
+}
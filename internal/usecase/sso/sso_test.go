@@ -0,0 +1,359 @@
+package sso
+
+import (
+	"context"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"vk_film/internal/pkg/oidc"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/identity"
+	mri "vk_film/internal/repository/identity/mocks"
+	"vk_film/internal/repository/user"
+	mru "vk_film/internal/repository/user/mocks"
+	"vk_film/internal/usecase/auth"
+	mu "vk_film/internal/usecase/auth/mocks"
+)
+
+var testError = errors.New("test error")
+
+var testPolicy = Policy{
+	LoginClaim: "preferred_username",
+	RoleClaim:  "groups",
+	Roles: []RoleMapping{
+		{Value: "film-admins", Role: types.ADMIN},
+		{Value: "film-editors", Role: "editor"},
+	},
+	DefaultRole: types.USER,
+}
+
+type SSOUsecaseSuite struct {
+	suite.Suite
+	stub         *stubProvider
+	usecase      *OIDCUsecase
+	mockUser     *mru.UserRepository
+	mockIdentity *mri.IdentityRepository
+	mockManager  *mu.SessionManager
+	gmc          *gomock.Controller
+}
+
+func (sus *SSOUsecaseSuite) BeforeEach(t provider.T) {
+	var err error
+	sus.stub, err = newStubProvider()
+	t.Require().NoError(err)
+
+	sus.gmc = gomock.NewController(t)
+	sus.mockUser = mru.NewUserRepository(sus.gmc)
+	sus.mockIdentity = mri.NewIdentityRepository(sus.gmc)
+	sus.mockManager = mu.NewSessionManager(sus.gmc)
+	sus.usecase = sus.newUsecase(testPolicy)
+}
+
+func (sus *SSOUsecaseSuite) AfterEach(t provider.T) {
+	sus.gmc.Finish()
+	sus.stub.Close()
+}
+
+func (sus *SSOUsecaseSuite) newUsecase(policy Policy) *OIDCUsecase {
+	return NewOIDCUsecase(oidc.NewProvider(sus.stub.Config(), &http.Client{Timeout: time.Second}),
+		sus.mockUser, sus.mockIdentity, sus.mockManager, policy)
+}
+
+// login
+// Начинает авторизацию и подтверждает её в тестовом провайдере с указанными заявками
+func (sus *SSOUsecaseSuite) login(t provider.StepCtx, usecase *OIDCUsecase,
+	claims map[string]any) (string, *Authorization) {
+	authorization, err := usecase.Begin(context.Background())
+	t.Require().NoError(err)
+
+	code, err := sus.stub.Authorize(authorization.URL, claims)
+	t.Require().NoError(err)
+
+	return code, authorization
+}
+
+func (sus *SSOUsecaseSuite) TestBeginFunction(t provider.T) {
+	t.Title("Begin function of sso usecase")
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		t.NewStep("Check result")
+		authorization, err := sus.usecase.Begin(context.Background())
+		t.Require().NoError(err)
+		t.Require().NotEmpty(authorization.State)
+		t.Require().NotEmpty(authorization.Nonce)
+		t.Require().NotEmpty(authorization.Verifier)
+
+		authURL, err := url.Parse(authorization.URL)
+		t.Require().NoError(err)
+		query := authURL.Query()
+		t.Require().Equal(sus.stub.Issuer()+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+		t.Require().Equal("code", query.Get("response_type"))
+		t.Require().Equal(stubClientID, query.Get("client_id"))
+		t.Require().Equal(stubRedirectURL, query.Get("redirect_uri"))
+		t.Require().Equal("openid profile email", query.Get("scope"))
+		t.Require().Equal(authorization.State, query.Get("state"))
+		t.Require().Equal(authorization.Nonce, query.Get("nonce"))
+		t.Require().Equal("S256", query.Get("code_challenge_method"))
+		t.Require().Equal(oidc.Challenge(authorization.Verifier), query.Get("code_challenge"))
+	})
+
+	t.WithNewStep("Provider is unavailable execute", func(t provider.StepCtx) {
+		t.NewStep("Init usecase")
+		usecase := NewOIDCUsecase(oidc.NewProvider(oidc.Config{Issuer: "http://127.0.0.1:1"}, http.DefaultClient),
+			sus.mockUser, sus.mockIdentity, sus.mockManager, testPolicy)
+
+		t.NewStep("Check result")
+		_, err := usecase.Begin(context.Background())
+		t.Require().ErrorIs(err, ErrorAuthorizationFailed)
+	})
+}
+
+func (sus *SSOUsecaseSuite) TestCompleteFunction(t provider.T) {
+	t.Title("Complete function of sso usecase")
+	t.NewStep("Init test data")
+	subject := "248289761001"
+	userId := types.Id(5)
+	client := auth.ClientInfo{IP: "127.0.0.1", UserAgent: "curl/8.0"}
+	credentials := &auth.Credentials{SessionId: "session", ExpiresIn: time.Hour}
+	claims := map[string]any{
+		"sub":                subject,
+		"preferred_username": "jane",
+		"groups":             []string{"staff", "film-editors"},
+	}
+	issuerIdentity := &identity.Identity{Issuer: sus.stub.Issuer(), Subject: subject}
+
+	t.WithNewStep("First login execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sus.mockIdentity.EXPECT().GetUserId(sus.stub.Issuer(), subject).Return(types.Id(0), identity.ErrorIdentityNotFound)
+		sus.mockIdentity.EXPECT().CreateUser(&user.User{Login: "jane", Role: "editor"}, issuerIdentity).
+			Return(&user.User{ID: userId, Login: "jane", Role: "editor"}, nil)
+		sus.mockManager.EXPECT().LoginExternal(userId, client).Return(credentials, nil)
+
+		t.NewStep("Check result")
+		code, authorization := sus.login(t, sus.usecase, claims)
+		res, id, err := sus.usecase.Complete(context.Background(), code, authorization, client)
+		t.Require().NoError(err)
+		t.Require().Equal(credentials, res)
+		t.Require().Equal(userId, id)
+	})
+
+	t.WithNewStep("Existing user with changed role execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sus.mockIdentity.EXPECT().GetUserId(sus.stub.Issuer(), subject).Return(userId, nil)
+		sus.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId, Role: types.USER}, nil)
+		sus.mockUser.EXPECT().UpdateUserRole(&user.User{ID: userId, Role: types.ADMIN}).
+			Return(&user.User{ID: userId, Role: types.ADMIN}, nil)
+		sus.mockManager.EXPECT().InvalidateUser(userId)
+		sus.mockManager.EXPECT().LoginExternal(userId, client).Return(credentials, nil)
+
+		t.NewStep("Check result")
+		code, authorization := sus.login(t, sus.usecase, map[string]any{
+			"sub":    subject,
+			"groups": "film-admins",
+		})
+		_, id, err := sus.usecase.Complete(context.Background(), code, authorization, client)
+		t.Require().NoError(err)
+		t.Require().Equal(userId, id)
+	})
+
+	t.WithNewStep("Existing user with default role execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sus.mockIdentity.EXPECT().GetUserId(sus.stub.Issuer(), subject).Return(userId, nil)
+		sus.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId, Role: types.USER}, nil)
+		sus.mockManager.EXPECT().LoginExternal(userId, client).Return(credentials, nil)
+
+		t.NewStep("Check result")
+		code, authorization := sus.login(t, sus.usecase, map[string]any{"sub": subject})
+		_, _, err := sus.usecase.Complete(context.Background(), code, authorization, client)
+		t.Require().NoError(err)
+	})
+
+	t.WithNewStep("User with enabled second factor execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		pending := &auth.Credentials{SessionId: auth.ChallengeTokenPrefix + "token", ExpiresIn: 5 * time.Minute, Pending: true}
+		sus.mockIdentity.EXPECT().GetUserId(sus.stub.Issuer(), subject).Return(userId, nil)
+		sus.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId, Role: types.USER}, nil)
+		sus.mockManager.EXPECT().LoginExternal(userId, client).Return(pending, nil)
+
+		t.NewStep("Check result")
+		code, authorization := sus.login(t, sus.usecase, map[string]any{"sub": subject})
+		res, id, err := sus.usecase.Complete(context.Background(), code, authorization, client)
+		t.Require().NoError(err)
+		t.Require().Equal(pending, res)
+		t.Require().Equal(userId, id)
+	})
+
+	t.WithNewStep("Concurrent first login execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sus.mockIdentity.EXPECT().GetUserId(sus.stub.Issuer(), subject).Return(types.Id(0), identity.ErrorIdentityNotFound)
+		sus.mockIdentity.EXPECT().CreateUser(gomock.Any(), issuerIdentity).Return(nil, identity.ErrorIdentityAlreadyExists)
+		sus.mockIdentity.EXPECT().GetUserId(sus.stub.Issuer(), subject).Return(userId, nil)
+		sus.mockManager.EXPECT().LoginExternal(userId, client).Return(credentials, nil)
+
+		t.NewStep("Check result")
+		code, authorization := sus.login(t, sus.usecase, claims)
+		_, id, err := sus.usecase.Complete(context.Background(), code, authorization, client)
+		t.Require().NoError(err)
+		t.Require().Equal(userId, id)
+	})
+
+	t.WithNewStep("No role execute", func(t provider.StepCtx) {
+		t.NewStep("Init usecase")
+		policy := testPolicy
+		policy.DefaultRole = ""
+		usecase := sus.newUsecase(policy)
+
+		t.NewStep("Check result")
+		code, authorization := sus.login(t, usecase, map[string]any{"sub": subject, "groups": []string{"staff"}})
+		_, _, err := usecase.Complete(context.Background(), code, authorization, client)
+		t.Require().ErrorIs(err, ErrorNoRole)
+	})
+
+	t.WithNewStep("No login claim execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sus.mockIdentity.EXPECT().GetUserId(sus.stub.Issuer(), subject).Return(types.Id(0), identity.ErrorIdentityNotFound)
+
+		t.NewStep("Check result")
+		code, authorization := sus.login(t, sus.usecase, map[string]any{"sub": subject})
+		_, _, err := sus.usecase.Complete(context.Background(), code, authorization, client)
+		t.Require().ErrorIs(err, ErrorNoLogin)
+	})
+
+	t.WithNewStep("Login already exists execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sus.mockIdentity.EXPECT().GetUserId(sus.stub.Issuer(), subject).Return(types.Id(0), identity.ErrorIdentityNotFound)
+		sus.mockIdentity.EXPECT().CreateUser(gomock.Any(), issuerIdentity).Return(nil, identity.ErrorLoginAlreadyExists)
+
+		t.NewStep("Check result")
+		code, authorization := sus.login(t, sus.usecase, claims)
+		_, _, err := sus.usecase.Complete(context.Background(), code, authorization, client)
+		t.Require().ErrorIs(err, ErrorLoginAlreadyExists)
+	})
+
+	t.WithNewStep("Identity repository error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sus.mockIdentity.EXPECT().GetUserId(sus.stub.Issuer(), subject).Return(types.Id(0), testError)
+
+		t.NewStep("Check result")
+		code, authorization := sus.login(t, sus.usecase, claims)
+		_, _, err := sus.usecase.Complete(context.Background(), code, authorization, client)
+		t.Require().ErrorIs(err, testError)
+	})
+
+	t.WithNewStep("Session manager error execute", func(t provider.StepCtx) {
+		t.NewStep("Init mock")
+		sus.mockIdentity.EXPECT().GetUserId(sus.stub.Issuer(), subject).Return(userId, nil)
+		sus.mockUser.EXPECT().GetUserById(userId).Return(&user.User{ID: userId, Role: "editor"}, nil)
+		sus.mockManager.EXPECT().LoginExternal(userId, client).Return(nil, testError)
+
+		t.NewStep("Check result")
+		code, authorization := sus.login(t, sus.usecase, claims)
+		_, _, err := sus.usecase.Complete(context.Background(), code, authorization, client)
+		t.Require().ErrorIs(err, testError)
+	})
+
+	failedCases := []struct {
+		name   string
+		claims map[string]any
+		modify func(code string, authorization *Authorization) string
+	}{
+		{
+			name:   "Unknown code",
+			claims: claims,
+			modify: func(code string, _ *Authorization) string { return code + "x" },
+		},
+		{
+			name:   "Wrong code verifier",
+			claims: claims,
+			modify: func(code string, authorization *Authorization) string {
+				authorization.Verifier += "x"
+				return code
+			},
+		},
+		{
+			name:   "Wrong nonce",
+			claims: claims,
+			modify: func(code string, authorization *Authorization) string {
+				authorization.Nonce += "x"
+				return code
+			},
+		},
+		{
+			name:   "Expired id token",
+			claims: map[string]any{"sub": subject, "exp": time.Now().Add(-time.Hour).Unix()},
+		},
+		{
+			name:   "Other audience",
+			claims: map[string]any{"sub": subject, "aud": []string{"other", stubClientID}, "azp": "other"},
+		},
+		{
+			name:   "Other issuer",
+			claims: map[string]any{"sub": subject, "iss": "https://evil.example.com"},
+		},
+		{
+			name:   "Without subject",
+			claims: map[string]any{"preferred_username": "jane"},
+		},
+	}
+
+	for _, cs := range failedCases {
+		t.WithNewStep(cs.name+" execute", func(t provider.StepCtx) {
+			t.NewStep("Check result")
+			code, authorization := sus.login(t, sus.usecase, cs.claims)
+			if cs.modify != nil {
+				code = cs.modify(code, authorization)
+			}
+			_, _, err := sus.usecase.Complete(context.Background(), code, authorization, client)
+			t.Require().ErrorIs(err, ErrorAuthorizationFailed)
+		})
+	}
+}
+
+func (sus *SSOUsecaseSuite) TestVerifySignature(t provider.T) {
+	t.Title("Verification of id token signature")
+	t.NewStep("Init test data")
+	prv := oidc.NewProvider(sus.stub.Config(), http.DefaultClient)
+	claims := map[string]any{
+		"iss":   sus.stub.Issuer(),
+		"aud":   stubClientID,
+		"sub":   "1",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"nonce": "nonce",
+	}
+
+	t.WithNewStep("Correct execute", func(t provider.StepCtx) {
+		idToken, err := sus.stub.Sign(claims)
+		t.Require().NoError(err)
+
+		res, err := prv.Verify(context.Background(), idToken, "nonce")
+		t.Require().NoError(err)
+		t.Require().Equal("1", res.Subject())
+	})
+
+	t.WithNewStep("Tampered payload execute", func(t provider.StepCtx) {
+		idToken, err := sus.stub.Sign(claims)
+		t.Require().NoError(err)
+		other, err := sus.stub.Sign(map[string]any{"iss": sus.stub.Issuer(), "aud": stubClientID, "sub": "2"})
+		t.Require().NoError(err)
+
+		parts, otherParts := strings.Split(idToken, "."), strings.Split(other, ".")
+		tampered := parts[0] + "." + otherParts[1] + "." + parts[2]
+		_, err = prv.Verify(context.Background(), tampered, "nonce")
+		t.Require().ErrorIs(err, oidc.ErrorInvalidSignature)
+	})
+
+	t.WithNewStep("Malformed token execute", func(t provider.StepCtx) {
+		_, err := prv.Verify(context.Background(), "abc.def", "nonce")
+		t.Require().ErrorIs(err, oidc.ErrorMalformedToken)
+	})
+}
+
+func TestRunSSOUsecaseSuite(t *testing.T) {
+	suite.RunSuite(t, new(SSOUsecaseSuite))
+}
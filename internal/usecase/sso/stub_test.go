@@ -0,0 +1,195 @@
+package sso
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+	"vk_film/internal/pkg/oidc"
+)
+
+const (
+	stubClientID     = "vk-film"
+	stubClientSecret = "secret"
+	stubRedirectURL  = "http://localhost:8080/api/v1/oidc/callback"
+	stubKeyId        = "stub-key"
+)
+
+var stubEncoding = base64.RawURLEncoding
+
+// stubAuthorization
+// Авторизация, подтверждённая пользователем в тестовом провайдере
+type stubAuthorization struct {
+	challenge string
+	nonce     string
+	claims    map[string]any
+}
+
+// stubProvider
+// Локальный OpenID Connect провайдер для тестов: отдаёт конфигурацию, JWKS и выдаёт
+// подписанные RS256 id токены по коду авторизации с проверкой PKCE
+type stubProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]stubAuthorization
+}
+
+func newStubProvider() (*stubProvider, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	sp := &stubProvider{key: key, codes: map[string]stubAuthorization{}}
+
+	router := http.NewServeMux()
+	router.HandleFunc("GET "+oidc.DiscoveryPath, sp.discovery)
+	router.HandleFunc("GET /jwks", sp.jwks)
+	router.HandleFunc("POST /token", sp.token)
+	sp.server = httptest.NewServer(router)
+
+	return sp, nil
+}
+
+func (sp *stubProvider) Close() {
+	sp.server.Close()
+}
+
+func (sp *stubProvider) Issuer() string {
+	return sp.server.URL
+}
+
+func (sp *stubProvider) Config() oidc.Config {
+	return oidc.Config{
+		Issuer:       sp.Issuer(),
+		ClientID:     stubClientID,
+		ClientSecret: stubClientSecret,
+		RedirectURL:  stubRedirectURL,
+	}
+}
+
+// Authorize
+// Эмулирует вход пользователя на странице провайдера и возвращает код авторизации
+func (sp *stubProvider) Authorize(authURL string, claims map[string]any) (string, error) {
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		return "", err
+	}
+	query := parsed.Query()
+
+	code, err := oidc.NewRandom()
+	if err != nil {
+		return "", err
+	}
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.codes[code] = stubAuthorization{
+		challenge: query.Get("code_challenge"),
+		nonce:     query.Get("nonce"),
+		claims:    claims,
+	}
+
+	return code, nil
+}
+
+// Sign
+// Подписывает заявки ключом провайдера
+func (sp *stubProvider) Sign(claims map[string]any) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": oidc.RS256, "kid": stubKeyId, "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := stubEncoding.EncodeToString(header) + "." + stubEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, sp.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return unsigned + "." + stubEncoding.EncodeToString(signature), nil
+}
+
+func (sp *stubProvider) discovery(w http.ResponseWriter, _ *http.Request) {
+	_ = json.NewEncoder(w).Encode(oidc.Metadata{
+		Issuer:                sp.Issuer(),
+		AuthorizationEndpoint: sp.Issuer() + "/authorize",
+		TokenEndpoint:         sp.Issuer() + "/token",
+		JWKSURI:               sp.Issuer() + "/jwks",
+	})
+}
+
+func (sp *stubProvider) jwks(w http.ResponseWriter, _ *http.Request) {
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": stubKeyId,
+			"use": "sig",
+			"n":   stubEncoding.EncodeToString(sp.key.N.Bytes()),
+			"e":   stubEncoding.EncodeToString(big.NewInt(int64(sp.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (sp *stubProvider) token(w http.ResponseWriter, r *http.Request) {
+	clientId, secret, ok := r.BasicAuth()
+	if !ok || clientId != stubClientID || secret != stubClientSecret {
+		sp.sendError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("redirect_uri") != stubRedirectURL {
+		sp.sendError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	sp.mu.Lock()
+	authorization, ok := sp.codes[r.PostForm.Get("code")]
+	delete(sp.codes, r.PostForm.Get("code"))
+	sp.mu.Unlock()
+
+	if !ok || oidc.Challenge(r.PostForm.Get("code_verifier")) != authorization.challenge {
+		sp.sendError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	claims := map[string]any{
+		"iss":   sp.Issuer(),
+		"aud":   stubClientID,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"nonce": authorization.nonce,
+	}
+	for k, v := range authorization.claims {
+		claims[k] = v
+	}
+
+	idToken, err := sp.Sign(claims)
+	if err != nil {
+		sp.sendError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]string{"id_token": idToken, "token_type": "Bearer"})
+}
+
+func (sp *stubProvider) sendError(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
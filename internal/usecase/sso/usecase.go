@@ -0,0 +1,162 @@
+package sso
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"slices"
+	"vk_film/internal/pkg/oidc"
+	"vk_film/internal/pkg/types"
+	"vk_film/internal/repository/identity"
+	"vk_film/internal/repository/user"
+	"vk_film/internal/usecase/auth"
+)
+
+// Provider
+// OpenID Connect provider, implemented by oidc.Provider
+type Provider interface {
+	Issuer() string
+	AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	Exchange(ctx context.Context, code, verifier string) (string, error)
+	Verify(ctx context.Context, idToken, nonce string) (oidc.Claims, error)
+}
+
+type OIDCUsecase struct {
+	provider   Provider
+	users      user.Repository
+	identities identity.Repository
+	manager    auth.Manager
+	policy     Policy
+}
+
+func NewOIDCUsecase(provider Provider, users user.Repository, identities identity.Repository,
+	manager auth.Manager, policy Policy) *OIDCUsecase {
+	return &OIDCUsecase{
+		provider:   provider,
+		users:      users,
+		identities: identities,
+		manager:    manager,
+		policy:     policy,
+	}
+}
+
+var _ = Usecase(&OIDCUsecase{})
+
+func (ou *OIDCUsecase) Begin(ctx context.Context) (*Authorization, error) {
+	authorization := &Authorization{}
+
+	var err error
+	for _, value := range []*string{&authorization.State, &authorization.Nonce, &authorization.Verifier} {
+		if *value, err = oidc.NewRandom(); err != nil {
+			return nil, err
+		}
+	}
+
+	authorization.URL, err = ou.provider.AuthCodeURL(ctx, authorization.State, authorization.Nonce,
+		authorization.Verifier)
+	if err != nil {
+		return nil, errors.Wrap(ErrorAuthorizationFailed, err.Error())
+	}
+
+	return authorization, nil
+}
+
+func (ou *OIDCUsecase) Complete(ctx context.Context, code string, authorization *Authorization,
+	client auth.ClientInfo) (*auth.Credentials, types.Id, error) {
+	idToken, err := ou.provider.Exchange(ctx, code, authorization.Verifier)
+	if err != nil {
+		return nil, 0, errors.Wrap(ErrorAuthorizationFailed, err.Error())
+	}
+
+	claims, err := ou.provider.Verify(ctx, idToken, authorization.Nonce)
+	if err != nil {
+		return nil, 0, errors.Wrap(ErrorAuthorizationFailed, err.Error())
+	}
+
+	role := ou.role(claims)
+	if role == "" {
+		return nil, 0, errors.Wrapf(ErrorNoRole, "subject %s, %s %v", claims.Subject(),
+			ou.policy.RoleClaim, claims.Strings(ou.policy.RoleClaim))
+	}
+
+	userId, err := ou.provisionUser(claims, role)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	credentials, err := ou.manager.LoginExternal(userId, client)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "try login user %d", userId)
+	}
+
+	return credentials, userId, nil
+}
+
+// role
+// Определяет роль пользователя по значению заявки с ролями
+func (ou *OIDCUsecase) role(claims oidc.Claims) types.Roles {
+	values := claims.Strings(ou.policy.RoleClaim)
+	for _, mapping := range ou.policy.Roles {
+		if slices.Contains(values, mapping.Value) {
+			return mapping.Role
+		}
+	}
+
+	return ou.policy.DefaultRole
+}
+
+// provisionUser
+// Находит пользователя, связанного с учётной записью провайдера, и обновляет его роль.
+// При первом входе пользователь создаётся.
+func (ou *OIDCUsecase) provisionUser(claims oidc.Claims, role types.Roles) (types.Id, error) {
+	subject := claims.Subject()
+
+	userId, err := ou.identities.GetUserId(ou.provider.Issuer(), subject)
+	if err != nil {
+		if errors.Is(err, identity.ErrorIdentityNotFound) {
+			return ou.createUser(claims, role)
+		}
+		return 0, errors.Wrapf(err, "try get user of subject %s", subject)
+	}
+
+	usr, err := ou.users.GetUserById(userId)
+	if err != nil {
+		return 0, errors.Wrapf(err, "try get user %d of subject %s", userId, subject)
+	}
+
+	// Роль определяется провайдером, поэтому при каждом входе она синхронизируется
+	if usr.Role != role {
+		if _, err := ou.users.UpdateUserRole(&user.User{ID: userId, Role: role}); err != nil {
+			return 0, errors.Wrapf(err, "try update role of user %d to %s", userId, role)
+		}
+		ou.manager.InvalidateUser(userId)
+	}
+
+	return userId, nil
+}
+
+// createUser
+// Создаёт пользователя без пароля и связывает его с учётной записью провайдера.
+// Существующий пользователь с тем же логином не связывается автоматически.
+func (ou *OIDCUsecase) createUser(claims oidc.Claims, role types.Roles) (types.Id, error) {
+	login := claims.String(ou.policy.LoginClaim)
+	if login == "" {
+		return 0, errors.Wrapf(ErrorNoLogin, "claim %s of subject %s", ou.policy.LoginClaim, claims.Subject())
+	}
+
+	usr, err := ou.identities.CreateUser(&user.User{Login: login, Role: role}, &identity.Identity{
+		Issuer:  ou.provider.Issuer(),
+		Subject: claims.Subject(),
+	})
+	if err != nil {
+		if errors.Is(err, identity.ErrorLoginAlreadyExists) {
+			return 0, errors.Wrapf(ErrorLoginAlreadyExists, "login %s", login)
+		}
+		// Пользователь уже создан параллельным входом с той же учётной записью
+		if errors.Is(err, identity.ErrorIdentityAlreadyExists) {
+			return ou.identities.GetUserId(ou.provider.Issuer(), claims.Subject())
+		}
+		return 0, errors.Wrapf(err, "try create user %s for subject %s", login, claims.Subject())
+	}
+
+	return usr.ID, nil
+}
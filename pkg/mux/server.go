@@ -25,6 +25,14 @@ func (p *Params) GetUint64(name string) (uint64, error) {
 	return val, nil
 }
 
+func (p *Params) GetString(name string) (string, error) {
+	val := p.req.PathValue(name)
+	if val == "" {
+		return "", errors.Errorf("param \"%s\" is empty in URL", name)
+	}
+	return val, nil
+}
+
 type ExtendedHandleFunc func(w http.ResponseWriter, r *http.Request, params Params)
 type MiddlewareFunc func(ExtendedHandleFunc) ExtendedHandleFunc
 
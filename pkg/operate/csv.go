@@ -0,0 +1,21 @@
+package operate
+
+import (
+	"encoding/csv"
+	"net/http"
+	"vk_film/pkg/logger"
+)
+
+const csvContentType = "text/csv; charset=utf-8"
+
+func SendCSV(w http.ResponseWriter, code int, records [][]string, l logger.Interface) {
+	w.Header().Set("Content-Type", csvContentType)
+	w.WriteHeader(code)
+
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(records); err != nil {
+		l.Error("got csv error: %s, when sending response", err)
+	}
+
+	l.Info("was sent csv response with status code %d", code)
+}
@@ -1,32 +1,169 @@
 CREATE EXTENSION IF NOT EXISTS citext;
 
-CREATE TYPE roles as ENUM ('user', 'admin');
+CREATE TABLE IF NOT EXISTS roles
+(
+    name        text   not null primary key check (name ~ '^[a-z][a-z0-9_-]{0,49}$'),
+    description text   not null default '' check (char_length(description) <= 200),
+    permissions text[] not null default '{}'
+);
+
+INSERT INTO roles (name, description, permissions)
+VALUES ('admin', 'Администратор', '{film:write,film:delete,actor:write,actor:delete,user:manage,user:moderate,role:manage}'),
+       ('user', 'Пользователь, только просмотр', '{}'),
+       ('editor', 'Редактор каталога фильмов и актёров', '{film:write,film:delete,actor:write,actor:delete}'),
+       ('moderator', 'Модератор, снимает блокировки и завершает сессии пользователей', '{user:moderate}');
+
+CREATE TYPE certifications as ENUM ('0+', '6+', '12+', '16+', '18+', 'G', 'PG', 'PG-13', 'R', 'NC-17');
+
+CREATE TYPE user_statuses as ENUM ('active', 'pending', 'rejected');
 
 CREATE TABLE IF NOT EXISTS users
 (
-    id       bigserial   not null primary key,
-    login    text unique not null,
-    password text        not null,
-    role     roles       not null default 'user'
+    id                bigserial     not null primary key,
+    login             text unique   not null,
+    email             text unique,
+    password          text          not null,
+    role              text          not null default 'user' references roles (name) on update cascade,
+    status            user_statuses not null default 'active',
+    max_certification certifications
+);
+
+CREATE INDEX IF NOT EXISTS users_pending_idx ON users (id) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS user_identities
+(
+    issuer     text        not null,
+    subject    text        not null,
+    user_id    bigint      not null references users (id) on delete cascade,
+    created_at timestamptz not null default now(),
+    primary key (issuer, subject)
+);
+
+CREATE TABLE IF NOT EXISTS user_totp
+(
+    user_id      bigint      not null primary key references users (id) on delete cascade,
+    secret       text        not null,
+    enabled      bool        not null default false,
+    last_counter bigint      not null default 0,
+    created_at   timestamptz not null default now()
+);
+
+CREATE TABLE IF NOT EXISTS totp_recovery_codes
+(
+    id        bigserial   not null primary key,
+    user_id   bigint      not null references users (id) on delete cascade,
+    code_hash text        not null,
+    used_at   timestamptz,
+    unique (user_id, code_hash)
+);
+
+CREATE TABLE IF NOT EXISTS login_challenges
+(
+    challenge_hash text        not null primary key,
+    user_id        bigint      not null references users (id) on delete cascade,
+    expires_at     timestamptz not null,
+    ip             text        not null default '',
+    user_agent     text        not null default '',
+    attempts       int         not null default 0
+);
+
+CREATE TABLE IF NOT EXISTS password_resets
+(
+    token_hash text        not null primary key,
+    user_id    bigint      not null references users (id) on delete cascade,
+    expires_at timestamptz not null,
+    used_at    timestamptz,
+    created_at timestamptz not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS password_resets_user_idx ON password_resets (user_id);
+
+CREATE TABLE IF NOT EXISTS api_tokens
+(
+    id           bigserial   not null primary key,
+    user_id      bigint      not null references users (id) on delete cascade,
+    name         text        not null check (char_length(name) >= 1 and char_length(name) <= 100),
+    token_hash   text unique not null,
+    read_only    bool        not null default false,
+    created_at   timestamptz not null default now(),
+    expires_at   timestamptz,
+    last_used_at timestamptz,
+    unique (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens
+(
+    id         bigserial   not null primary key,
+    session_id text        not null,
+    user_id    bigint      not null references users (id) on delete cascade,
+    token_hash text unique not null,
+    expires_at timestamptz not null,
+    used_at    timestamptz,
+    ip         text        not null default '',
+    user_agent text        not null default '',
+    created_at timestamptz not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_session_idx ON refresh_tokens (session_id);
+CREATE INDEX IF NOT EXISTS refresh_tokens_user_idx ON refresh_tokens (user_id);
+
+CREATE TABLE IF NOT EXISTS sessions
+(
+    session_hash text        not null primary key,
+    user_id      bigint      not null references users (id) on delete cascade,
+    idle_timeout bigint      not null check (idle_timeout >= 0),
+    expires_at   timestamptz not null,
+    active_until timestamptz not null,
+    ip           text        not null default '',
+    user_agent   text        not null default '',
+    created_at   timestamptz not null default now(),
+    last_seen_at timestamptz not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS sessions_user_idx ON sessions (user_id);
+CREATE INDEX IF NOT EXISTS sessions_active_until_idx ON sessions (active_until);
+
+CREATE TABLE IF NOT EXISTS security_events
+(
+    id         bigserial   not null primary key,
+    user_id    bigint      references users (id) on delete set null,
+    login      text        not null default '',
+    type       text        not null,
+    ip         text        not null default '',
+    user_agent text        not null default '',
+    created_at timestamptz not null default now()
 );
 
+CREATE INDEX IF NOT EXISTS security_events_user_idx ON security_events (user_id, created_at);
+CREATE INDEX IF NOT EXISTS security_events_created_at_idx ON security_events (created_at);
+
 CREATE TYPE sexes as ENUM ('male', 'female');
 
 CREATE TABLE IF NOT EXISTS actors
 (
-    id       bigserial not null primary key,
-    name     citext    not null,
-    sex      sexes     not null,
-    birthday date      not null
+    id          bigserial not null primary key,
+    name        citext    not null,
+    sex         sexes     not null,
+    birthday    date      not null,
+    death_date  date,
+    birth_place text check (char_length(birth_place) <= 200),
+    country     text check (char_length(country) <= 100),
+    biography   text check (char_length(biography) <= 5000),
+    aliases     citext[]  not null default '{}',
+    constraint actors_death_date_check check (death_date > birthday)
 );
 
 CREATE TABLE IF NOT EXISTS films
 (
-    id           bigserial not null primary key,
-    name         citext    not null check (char_length(name) >= 1 and char_length(name) <= 150),
-    description  text      not null check (char_length(description) <= 1000),
-    publish_date date      not null,
-    rating       int8      not null check (rating >= 0 and rating <= 10)
+    id                bigserial      not null primary key,
+    name              citext         not null check (char_length(name) >= 1 and char_length(name) <= 150),
+    description       text           not null check (char_length(description) <= 1000),
+    publish_date      date           not null,
+    rating            int8           not null check (rating >= 0 and rating <= 10),
+    runtime           int4 check (runtime >= 1 and runtime <= 1000),
+    countries         citext[]       not null default '{}',
+    original_language citext check (char_length(original_language) = 2),
+    age_certification certifications
 );
 
 CREATE TABLE IF NOT EXISTS film_actor
@@ -35,8 +172,3 @@ CREATE TABLE IF NOT EXISTS film_actor
     film_id  bigint    not null references films (id) on delete cascade,
     actor_id bigint    not null references actors (id) on delete cascade
 );
-
-
-INSERT INTO users (login, password, role)
-VALUES ('admin', '$2a$10$tmOxaYBG7wIw5eWrghzwueVVtJTaKQFHGxO6Ndri7JERsaiG/V.f.', 'admin'),
-       ('user', '$2a$10$h32WBtNWkQU3Nhm9jC.tv.xEVYVuqGisHIqWflLQkIr.j43fbH2XW', 'user')